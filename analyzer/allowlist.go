@@ -0,0 +1,99 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/types"
+	"slices"
+	"strings"
+)
+
+// defaultAllowed lists sentinel errors that are documented to be returned
+// unwrapped, so comparing against them with == is safe.
+var defaultAllowed = []string{
+	"database/sql.ErrNoRows",
+	"io.EOF",
+	"io.ErrUnexpectedEOF",
+	"io/fs.SkipAll",
+	"io/fs.SkipDir",
+	"net/http.ErrServerClosed",
+	"path/filepath.SkipAll",
+	"path/filepath.SkipDir",
+}
+
+// allowlist is a set of qualified sentinel names such as "io.EOF". It
+// implements flag.Value.
+type allowlist map[string]bool
+
+func newAllowlist(names ...string) allowlist {
+	l := make(allowlist, len(names))
+	for _, name := range names {
+		l[name] = true
+	}
+	return l
+}
+
+func (l allowlist) String() string {
+	names := make([]string, 0, len(l))
+	for name := range l {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return strings.Join(names, ",")
+}
+
+// Set adds the comma-separated sentinel names in value to the list.
+func (l allowlist) Set(value string) error {
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		slash := strings.LastIndexByte(name, '/')
+		if dot := strings.LastIndexByte(name, '.'); dot <= slash+1 || dot == len(name)-1 {
+			return fmt.Errorf("invalid sentinel %q: want pkg/path.Name", name)
+		}
+		l[name] = true
+	}
+	return nil
+}
+
+// defaultPassthrough lists the functions and packages whose errors the
+// wrapcheck check allows returning as they are: they create or wrap the
+// error themselves.
+var defaultPassthrough = []string{
+	"errors",
+	"fmt.Errorf",
+}
+
+// passthrough is a set of package paths, such as "io", and qualified
+// function names, such as "io.ReadAll" or "os.File.Close". It implements
+// flag.Value.
+type passthrough map[string]bool
+
+func newPassthrough(names ...string) passthrough {
+	p := make(passthrough, len(names))
+	for _, name := range names {
+		p[name] = true
+	}
+	return p
+}
+
+func (p passthrough) String() string {
+	return allowlist(p).String()
+}
+
+// Set adds the comma-separated package paths and function names in value
+// to the list.
+func (p passthrough) Set(value string) error {
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			p[name] = true
+		}
+	}
+	return nil
+}
+
+// allows reports whether errors returned by fn may pass through unwrapped.
+func (p passthrough) allows(fn *types.Func) bool {
+	return p[fn.Pkg().Path()] || p[fn.Pkg().Path()+"."+memberName(fn)]
+}
@@ -0,0 +1,352 @@
+// Package analyzer implements errlint, a go/analysis pass that reports error
+// handling patterns which stop working once errors are wrapped.
+package analyzer
+
+import (
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const doc = `report error handling anti-patterns
+
+errlint reports comparisons of errors against sentinel values using == and
+!=. Such comparisons only match the exact value and fail as soon as the error
+is wrapped with fmt.Errorf("...: %w", err); use errors.Is instead.
+
+It also reports type assertions on error values, such as err.(*MyError) or
+err.(net.Error), which fail for the same reason; use errors.As instead, with
+an interface target where the assertion was to an interface. Switch
+statements over error values and error types are reported likewise.
+
+Finally, it reports errors passed to fmt.Errorf under a %v or %s verb, which
+flattens the error into text; use %w so callers can still unwrap it. The
+same check reports %w verbs without an argument or with an argument that is
+not an error, error arguments without a verb, and multiple %w verbs in files
+built with a Go version older than 1.20. If a call with an error passes more
+or fewer arguments than its format takes, it reports the mismatch, which
+shifts every argument after it onto the wrong verb.
+
+It reports the os.IsNotExist, os.IsExist, os.IsPermission and os.IsTimeout
+helpers, which predate wrapping and do not unwrap errors; use errors.Is with
+fs.ErrNotExist, fs.ErrExist or fs.ErrPermission instead.
+
+It reports error messages matched as text, err.Error() passed to
+strings.Contains, HasPrefix, HasSuffix or EqualFold or compared with == to
+a string constant, which breaks when the message changes; match the error
+with errors.Is or errors.As instead.
+
+It reports fmt.Errorf calls without verbs or arguments, which create the
+same error as errors.New; the suggested fix rewrites them and updates the
+imports.
+
+It reports calls of errors.Is with a nil target or error, and calls of
+errors.As whose target makes them panic, such as a nil pointer variable
+passed without &, or match anything, such as a *error target or
+errors.As(err, &err).
+
+It reports deferred functions that wrap an error which the function does
+not return, such as an err that shadows the named error result, and
+deferred fmt.Errorf calls that wrap the named result without checking that
+it is not nil, turning success into an error.
+
+It reports the message of an error, err.Error(), formatted with %s or %v
+by fmt.Errorf or concatenated into its format, which keeps the text but
+drops the error from the chain; the suggested fix wraps the error itself
+with %w.
+
+It reports error variables declared with := in a block that shadow an
+error variable of an enclosing block, which is returned or checked after
+the block without the error assigned in it, unless the inner variable is
+handled in the block, and panic(fmt.Errorf(...)) calls that wrap an error
+in functions that could return it instead.
+
+It reports error variables assigned from a call in each iteration of a
+loop but only returned or checked after it, which sees the error of the
+last iteration alone, unless the loop reads them or can stop after the
+assignment, as retries do.
+
+It reports errors.New(fmt.Sprintf(...)), which fmt.Errorf does in one call,
+and fmt.Sprintf calls that format an error, or its message, into the
+format or an argument of fmt.Errorf, which keeps the text of the error but
+drops it from the chain; the suggested fixes call fmt.Errorf and wrap the
+error with %w.
+
+It reports assignments to sentinel errors, package-level error variables
+named ErrX or errX and allowlisted sentinels such as io.EOF, outside their
+declaration, which leave the errors created before wrapping a value that
+errors.Is no longer matches.
+
+It reports comparisons with == or != and calls of errors.Is whose operand
+is an error created by errors.New, or fmt.Errorf without %w, in the same
+function, directly or through a local variable that the function does not
+pass on. Every call creates a new error, so they never match, not even an
+error with the same message; compare with a package-level sentinel.
+
+The nilerror check reports err.Error() calls on an error variable that is
+nil where they run, which panic, and %s, %q or %w verbs formatting it,
+which print %!s(<nil>): in the branch of if err == nil, or after an
+if err != nil block that returns. It also reports err.Error() called on the
+result of a call before the nil check that follows it.
+
+The internaltype check reports exported functions that return errors of a
+type declared in an internal package, whose callers outside its tree
+cannot import the type to match the error with errors.As; translate them
+into exported errors of the package. The astarget check reports errors.As
+calls whose result is ignored while their target, declared outside the
+loop or set by an earlier call, may still hold an earlier match.
+
+The boxed check reports nil checks that are true for a nil pointer: of
+local variables and maps of interface types that only store errors of a
+concrete type that may be nil, such as the *ParseError a parse function
+returns, and of such errors converted to an interface. An interface that
+holds a nil pointer is not nil. It also reports errors compared with ==
+to values of other interface types, such as any, which the comparison
+check does not see.
+
+The wrongerr check reports branches that check one error variable, such
+as err2 != nil, but return another, or wrap it with fmt.Errorf, without
+using the one they check, when an earlier check found the returned
+variable nil. A bare return returns the named error result, so it is
+reported too. The caller sees success; the fix returns the checked
+variable.
+
+The ignoredok check reports errors.As calls whose result is ignored and
+type assertions whose ok result is assigned to _, when a later statement
+dereferences the variable they set without comparing it with nil. The
+variable is nil, or a zero struct, whenever the error does not match; the
+fix moves the statements that use it into an if statement on the result.
+
+Two style checks enforce the naming conventions of errors: sentinelname
+reports exported sentinel errors whose name does not start with Err, and
+typename exported error types whose name does not end in Error.
+
+A //errlint:ignore comment suppresses findings on its line, or on the next
+line if it stands alone. It names the checks it suppresses, by ID or name,
+followed by the reason:
+
+	//errlint:ignore ERRLINT001 the driver returns io.EOF unwrapped
+
+Directives that suppress no finding, name unknown checks or give no reason
+are reported themselves, so they do not outlive the code they were written
+for.
+
+The -checks flag selects which of these checks run: comparison, assertion,
+switch, errorf, oserror, ignore, message, errorsnew, isas, sentinelname,
+typename, deferwrap, errortext, shadow, panic, overwrite, sprintf,
+reassign, newcompare, nilerror, internaltype, astarget, boxed, wrongerr
+and ignoredok. All of them run by default. Every finding ends with the
+stable ID of its check, such as ERRLINT001 for comparison; errlint explain
+lists the IDs, and errlint explain ERRLINT001 describes a check in detail.
+
+The opt-in dynamic check, ERRLINT007, reports errors created with
+errors.New, or fmt.Errorf without %w, inside functions and returned or
+compared directly, which callers cannot match with errors.Is; declare
+package-level sentinels instead. The opt-in wrapcheck check, ERRLINT010,
+reports errors from functions of other packages returned as they are,
+without saying what the program was doing; the -passthrough flag lists
+packages and functions whose errors may pass through. The opt-in swallow
+check, ERRLINT016, reports errors that are logged and then dropped by
+returning nil; the -loggers flag lists logging functions in addition to
+those of packages log and log/slog. The opt-in wrapmsg check, ERRLINT024,
+reports wrap messages of fmt.Errorf that do not read "context: %w", with a
+context in lower case, without trailing punctuation and not repeating the
+message of the sentinel it wraps; the -wrap-prefix flag sets a regular
+expression the context must match. The -enable flag runs opt-in checks in
+addition to the checks selected by -checks.
+
+The -custom flag adds checks of a project's own, as a JSON array of
+CustomCheck values, each reporting the calls of one function or the
+comparisons with one sentinel with a message template:
+
+	-custom='[{"name":"legacywrap","call":"example.com/app/errs.LegacyWrap","message":"{{.Expr}} is deprecated; wrap with fmt.Errorf and %w"}]'
+
+They always run, and their findings end with their name rather than an ID.
+
+The comparison check follows sentinels across packages: facts record the
+sentinels of each package and whether its functions return them as they are
+or wrapped, so comparisons against a sentinel its package wraps, or with an
+error known to hold it wrapped, are reported with where the wrapping
+happens. The facts also record the functions an error comes through as it
+is on its way from the function that wraps it, so the message traces the
+wrapping through helpers: err only holds store.ErrNotFound wrapped by
+store.Load, 2 calls down: api.Get returns the error of store.Load as it
+is. Within a function, it follows the assignments that reach a
+comparison, to local variables and to the fields and map values they hold,
+so an error wrapped with fmt.Errorf and %w, or stored in a new &T{...}
+value, and then compared with == is reported as never matching, even
+against an allowlisted sentinel.
+
+Code inside Is(error) bool methods is exempt from the comparison, assertion
+and switch checks: those methods implement custom matching for errors.Is and
+compare their target directly by design.
+
+Sentinels documented to be returned unwrapped, such as io.EOF and
+sql.ErrNoRows, may be compared directly. Use the -allow flag to add
+project-specific sentinels to that list, or document them in their own
+package with an //errlint:unwrapped directive, in the comment of the
+sentinel or in the package comment for all of its sentinels:
+
+	// ErrNil is returned when a key does not exist.
+	//
+	//errlint:unwrapped
+	var ErrNil = errors.New("nil")
+
+The directive is recorded as a fact, so comparisons in every package that
+imports the sentinel are allowed. A directive on a sentinel that a function
+of its package returns wrapped is reported.`
+
+// Analyzer reports error handling anti-patterns. It runs every check and is
+// configured through its flags.
+var Analyzer = newAnalyzer(newLinter())
+
+// Config configures an analyzer created by New.
+type Config struct {
+	// Checks lists the checks to run. If empty, all checks that are not
+	// opt-in run.
+	Checks []string
+	// Enable lists checks to run in addition to Checks, such as the opt-in
+	// dynamic and wrapcheck checks.
+	Enable []string
+	// Allow lists sentinel errors, as "pkg/path.Name", that are documented
+	// to be returned unwrapped and may be compared with ==. They are added
+	// to the default allowlist.
+	Allow []string
+	// Passthrough lists packages, as "pkg/path", and functions, as
+	// "pkg/path.Name" or "pkg/path.Type.Method", whose errors the
+	// wrapcheck check allows returning unwrapped. They are added to the
+	// defaults.
+	Passthrough []string
+	// Loggers lists packages, as "pkg/path", and functions, as
+	// "pkg/path.Name" or "pkg/path.Type.Method", whose calls the swallow
+	// check treats as logging an error. They are added to the defaults.
+	Loggers []string
+	// WrapPrefix is a regular expression the context of wrap messages, in
+	// front of ": %w", must match for the wrapmsg check. If empty, any
+	// context that follows the convention is fine.
+	WrapPrefix string
+	// Custom lists checks defined by patterns rather than implemented by
+	// errlint. They always run.
+	Custom []CustomCheck
+}
+
+// New returns an errlint analyzer configured by cfg.
+func New(cfg Config) (*analysis.Analyzer, error) {
+	l := newLinter()
+	if len(cfg.Checks) > 0 {
+		if err := l.checks.Set(strings.Join(cfg.Checks, ",")); err != nil {
+			return nil, err
+		}
+	}
+	if err := l.enabled.Set(strings.Join(cfg.Enable, ",")); err != nil {
+		return nil, err
+	}
+	if err := l.allowed.Set(strings.Join(cfg.Allow, ",")); err != nil {
+		return nil, err
+	}
+	if err := l.passthrough.Set(strings.Join(cfg.Passthrough, ",")); err != nil {
+		return nil, err
+	}
+	if err := l.loggers.Set(strings.Join(cfg.Loggers, ",")); err != nil {
+		return nil, err
+	}
+	if err := l.wrapPrefix.Set(cfg.WrapPrefix); err != nil {
+		return nil, err
+	}
+	if err := l.custom.set(cfg.Custom); err != nil {
+		return nil, err
+	}
+	return newAnalyzer(l), nil
+}
+
+func newAnalyzer(l *linter) *analysis.Analyzer {
+	a := &analysis.Analyzer{
+		Name:      "errlint",
+		Doc:       doc,
+		Requires:  []*analysis.Analyzer{inspect.Analyzer},
+		Run:       l.run,
+		FactTypes: []analysis.Fact{new(sentinelFact), new(returnsFact)},
+	}
+	a.Flags.Var(l.checks, "checks", "comma-separated `list` of checks to run: "+l.checks.String())
+	a.Flags.Var(l.enabled, "enable", "comma-separated `list` of checks to run in addition to -checks, such as the opt-in checks: "+optInChecks())
+	a.Flags.Var(l.allowed, "allow", "comma-separated `list` of additional sentinel errors, as pkg/path.Name, documented to be returned unwrapped")
+	a.Flags.Var(l.passthrough, "passthrough", "comma-separated `list` of additional packages and functions, as pkg/path or pkg/path.Name, whose errors the wrapcheck check allows returning unwrapped")
+	a.Flags.Var(l.loggers, "loggers", "comma-separated `list` of additional packages and functions, as pkg/path or pkg/path.Name, whose calls the swallow check treats as logging an error")
+	a.Flags.Var(l.wrapPrefix, "wrap-prefix", "regular `expression` the context of wrap messages, in front of \": %w\", must match for the wrapmsg check")
+	a.Flags.Var(l.custom, "custom", "JSON `array` of custom checks, each with a name, a call or compare pattern and a message template")
+	return a
+}
+
+// linter holds the configuration of one analyzer.
+type linter struct {
+	checks      checkSet
+	enabled     checkSet
+	allowed     allowlist
+	passthrough passthrough
+	loggers     passthrough
+	wrapPrefix  *wrapPattern
+	custom      *customChecks
+
+	// ignores are the suppression comments of the pass being run, facts
+	// the origins of its error values, and newErrors its local variables
+	// holding errors that no other error can be. run sets them on a copy
+	// of the linter for each pass.
+	ignores   *ignoreSet
+	facts     *factFinder
+	newErrors map[*types.Var]string
+}
+
+func newLinter() *linter {
+	return &linter{
+		checks:      newCheckSet(),
+		enabled:     make(checkSet),
+		allowed:     newAllowlist(defaultAllowed...),
+		passthrough: newPassthrough(defaultPassthrough...),
+		loggers:     newPassthrough(defaultLoggers...),
+		wrapPrefix:  new(wrapPattern),
+		custom:      new(customChecks),
+	}
+}
+
+func (l *linter) run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	pl := *l
+	pl.ignores = parseIgnores(pass, l.custom)
+	pl.facts = exportFacts(pass)
+	pl.newErrors = findNewErrors(pass, insp)
+
+	order := runOrder
+	if len(l.custom.checks) > 0 {
+		// Custom checks run before ignore too.
+		order = slices.Insert(slices.Clone(runOrder), len(runOrder)-1, l.custom.checks...)
+	}
+	for _, c := range order {
+		if !l.runs(c.name) {
+			continue
+		}
+		// Tag every diagnostic with the check that reported it, and drop
+		// the suppressed ones.
+		p := *pass
+		p.Report = func(d analysis.Diagnostic) {
+			if pl.ignores.suppress(pass.Fset, d.Pos, c.name) {
+				return
+			}
+			d.Category = c.name
+			d.Message += " [" + c.id + "]"
+			pass.Report(d)
+		}
+		c.run(&pl, &p, insp)
+	}
+
+	return nil, nil
+}
+
+// runs reports whether the named check runs.
+func (l *linter) runs(name string) bool {
+	_, custom := l.custom.find(name)
+	return l.checks[name] || l.enabled[name] || custom
+}
@@ -0,0 +1,87 @@
+package analyzer_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer"
+)
+
+// fixtures lists the fixture packages and the configuration they are
+// analyzed with. Most live in the GOPATH tree testdata/src; fixtures that
+// need a go.mod, for example to set the Go version, are modules in their
+// own directory under testdata.
+var fixtures = []struct {
+	module string
+	pkg    string
+	config analyzer.Config
+}{
+	{pkg: "comparison"},
+	{pkg: "assertion"},
+	{pkg: "switches"},
+	{pkg: "errorf"},
+	{pkg: "oserror"},
+	{pkg: "ignore"},
+	{pkg: "message"},
+	{pkg: "errorsnew"},
+	{pkg: "isas"},
+	{pkg: "naming"},
+	{pkg: "deferwrap"},
+	{pkg: "shadow"},
+	{pkg: "errortext"},
+	{pkg: "panics"},
+	{pkg: "overwrite"},
+	{pkg: "sprintf"},
+	{pkg: "reassign"},
+	{pkg: "newcompare"},
+	{pkg: "readfull"},
+	{pkg: "nilerror"},
+	{pkg: "astarget"},
+	{pkg: "boxed"},
+	{pkg: "wrongerr"},
+	{pkg: "ignoredok"},
+	{pkg: "facts"},
+	{pkg: "facts/store"},
+	{pkg: "facts/kv"},
+	{pkg: "facts/client"},
+	{pkg: "dynamic", config: analyzer.Config{Checks: []string{"dynamic"}}},
+	{pkg: "wrapcheck", config: analyzer.Config{Checks: []string{"wrapcheck"}, Passthrough: []string{"strconv", "io.ReadAll"}}},
+	{pkg: "swallow", config: analyzer.Config{Checks: []string{"swallow"}, Loggers: []string{"swallow.audit"}}},
+	{pkg: "wrapmsg", config: analyzer.Config{Checks: []string{"wrapmsg"}}},
+	{pkg: "wrapmsg/prefix", config: analyzer.Config{Checks: []string{"wrapmsg"}, WrapPrefix: `^[a-z]+ing\b`}},
+	{pkg: "internaltype", config: analyzer.Config{Checks: []string{"internaltype"}}},
+	{pkg: "internaltype/internal/cache", config: analyzer.Config{Checks: []string{"internaltype"}}},
+	{pkg: "custom", config: analyzer.Config{Checks: []string{"ignore"}, Custom: []analyzer.CustomCheck{
+		{Name: "legacywrap", Call: "custom/errs.LegacyWrap", Message: "{{.Expr}} is deprecated; wrap with fmt.Errorf and %w"},
+		{Name: "wrapper", Call: "custom/errs.Wrapper.Wrap"},
+		{Name: "errfoo", Compare: "custom/errs.ErrFoo", Message: "{{.Expr}}: {{.Target}} is going away"},
+	}}},
+	{pkg: "allow", config: analyzer.Config{Allow: []string{"allow.ErrMiss"}}},
+	{module: "go119", pkg: "go119/multiwrap"},
+	{module: "../../examples", pkg: "example.com/shop/..."},
+}
+
+// TestAnalyzer checks the diagnostics and suggested fixes of the analyzer
+// against the fixtures. Each fixture package marks the diagnostics it
+// expects with "// want" comments, and the expected result of applying
+// every suggested fix to a file x.go is x.go.golden; see the analysistest
+// package for the format.
+func TestAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	for _, f := range fixtures {
+		t.Run(f.pkg, func(t *testing.T) {
+			if f.module != "" {
+				// The go command rejects -mod=mod in workspace mode, such
+				// as that of the shop example.
+				t.Setenv("GOFLAGS", "")
+			}
+			a, err := analyzer.New(f.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			analysistest.RunWithSuggestedFixes(t, filepath.Join(testdata, f.module), a, f.pkg)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// checkAssertions reports type assertions such as err.(*NotFoundError) whose
+// operand is an error interface value.
+func (l *linter) checkAssertions(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.WithStack([]ast.Node{(*ast.TypeAssertExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		expr := n.(*ast.TypeAssertExpr)
+		if expr.Type == nil {
+			// x.(type) in a type switch.
+			return true
+		}
+		if !isErrorInterface(pass, expr.X) || isUnwrapInterface(pass.TypesInfo.TypeOf(expr.Type)) {
+			return true
+		}
+		if inIsMethod(pass, expr.Pos()) {
+			return true
+		}
+
+		msg := "type assertion on error fails on wrapped errors; use errors.As"
+		if t := pass.TypesInfo.TypeOf(expr.Type); types.IsInterface(t) {
+			// Interface targets such as net.Error are matched by the
+			// first error in the chain that implements them.
+			name := types.TypeString(t, types.RelativeTo(pass.Pkg))
+			msg = fmt.Sprintf("type assertion to %s fails on wrapped errors; use errors.As with a %s target", name, name)
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:            expr.Pos(),
+			End:            expr.End(),
+			Message:        msg,
+			SuggestedFixes: assertionFix(pass, expr, stack),
+		})
+		return true
+	})
+}
+
+// assertionFix rewrites the two-value assignment
+//
+//	x, ok := err.(T)
+//
+// into
+//
+//	var x T
+//	ok := errors.As(err, &x)
+//
+// The assignment may stand on its own or be the init statement of an if.
+// Single-value assertions panic on failure and are left alone, as are
+// assignments whose ok result is discarded.
+func assertionFix(pass *analysis.Pass, expr *ast.TypeAssertExpr, stack []ast.Node) []analysis.SuggestedFix {
+	if len(stack) < 3 {
+		return nil
+	}
+	assign, ok := stack[len(stack)-2].(*ast.AssignStmt)
+	if !ok || len(assign.Lhs) != 2 || len(assign.Rhs) != 1 {
+		return nil
+	}
+	x, okX := assign.Lhs[0].(*ast.Ident)
+	okIdent, okOK := assign.Lhs[1].(*ast.Ident)
+	if !okX || !okOK || okIdent.Name == "_" {
+		return nil
+	}
+
+	// stmt is the statement the variable declaration is inserted before.
+	var stmt ast.Stmt = assign
+	parent := stack[len(stack)-3]
+	if ifStmt, ok := parent.(*ast.IfStmt); ok && ifStmt.Init == assign {
+		stmt = ifStmt
+		if len(stack) < 4 {
+			return nil
+		}
+		parent = stack[len(stack)-4]
+	}
+	switch parent.(type) {
+	case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause:
+	default:
+		return nil
+	}
+
+	file := fileOf(pass, expr.Pos())
+	if file == nil {
+		return nil
+	}
+	name, edits := importErrors(pass, file)
+
+	typ := pass.TypesInfo.TypeOf(expr.Type)
+	target := fmt.Sprintf("new(%s)", render(pass, expr.Type))
+	if x.Name != "_" {
+		target = "&" + x.Name
+		switch {
+		case pass.TypesInfo.Defs[x] != nil:
+			// x is declared by the assignment. When it moves out of an if
+			// statement's scope, it must not collide with another x.
+			if stmt != ast.Stmt(assign) && pass.TypesInfo.Scopes[stmt].Parent().Lookup(x.Name) != nil {
+				return nil
+			}
+			edits = append(edits, analysis.TextEdit{
+				Pos:     stmt.Pos(),
+				End:     stmt.Pos(),
+				NewText: fmt.Appendf(nil, "var %s %s\n%s", x.Name, render(pass, expr.Type), indentation(pass, stmt.Pos())),
+			})
+		case !types.Identical(pass.TypesInfo.TypeOf(x), typ):
+			// errors.As would target the type of the existing variable.
+			return nil
+		}
+	}
+
+	call := fmt.Sprintf("%s.As(%s, %s)", name, render(pass, expr.X), target)
+	if ifStmt, ok := stmt.(*ast.IfStmt); ok && onlyCondUses(pass, ifStmt, okIdent) {
+		// if ok := errors.As(err, &x); ok { ... } reads better without ok.
+		edits = append(edits, analysis.TextEdit{
+			Pos:     ifStmt.Init.Pos(),
+			End:     ifStmt.Cond.End(),
+			NewText: []byte(call),
+		})
+	} else {
+		tok := token.ASSIGN
+		if assign.Tok == token.DEFINE && pass.TypesInfo.Defs[okIdent] != nil {
+			tok = token.DEFINE
+		}
+		edits = append(edits, analysis.TextEdit{
+			Pos:     assign.Pos(),
+			End:     assign.End(),
+			NewText: fmt.Appendf(nil, "%s %s %s", okIdent.Name, tok, call),
+		})
+	}
+
+	return []analysis.SuggestedFix{{
+		Message:   "Use errors.As",
+		TextEdits: edits,
+	}}
+}
+
+// onlyCondUses reports whether the variable declared by ok in the init
+// statement of ifStmt is used only as the entire condition of ifStmt.
+func onlyCondUses(pass *analysis.Pass, ifStmt *ast.IfStmt, ok *ast.Ident) bool {
+	obj := pass.TypesInfo.Defs[ok]
+	cond, isIdent := ifStmt.Cond.(*ast.Ident)
+	if obj == nil || !isIdent || pass.TypesInfo.Uses[cond] != obj {
+		return false
+	}
+
+	used := false
+	ast.Inspect(ifStmt, func(n ast.Node) bool {
+		if id, isIdent := n.(*ast.Ident); isIdent && id != cond && pass.TypesInfo.Uses[id] == obj {
+			used = true
+		}
+		return !used
+	})
+	return !used
+}
@@ -0,0 +1,159 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// checkAsTargets reports errors.As calls whose result is ignored, while
+// their target may still hold the error an earlier call matched, as in
+//
+//	var nf *NotFoundError
+//	for _, err := range errs {
+//		errors.As(err, &nf)
+//		if nf != nil {
+//			missing = append(missing, nf.Key)
+//		}
+//	}
+//
+// errors.As leaves its target alone when it does not match, so reading the
+// target instead of the result of the call sees the match of an earlier
+// call, or of an earlier iteration of a loop. Targets that are assigned
+// between the calls, such as reset to nil, and targets that are not read
+// after the call, are not reported.
+func (l *linter) checkAsTargets(pass *analysis.Pass, insp *inspector.Inspector) {
+	type asCall struct {
+		call    *ast.CallExpr
+		body    *ast.BlockStmt
+		loop    ast.Stmt
+		ignored bool
+	}
+	var vars []*types.Var
+	calls := make(map[*types.Var][]asCall)
+	insp.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		call := n.(*ast.CallExpr)
+		if !push || !isFunc(pass, call, "errors", "As") || len(call.Args) != 2 {
+			return true
+		}
+		v := addressedLocal(pass, call.Args[1])
+		body := enclosingBody(stack)
+		if v == nil || body == nil {
+			return true
+		}
+		ignored := false
+		switch parent := stack[len(stack)-2].(type) {
+		case *ast.ExprStmt:
+			ignored = true
+		case *ast.AssignStmt:
+			id, ok := parent.Lhs[0].(*ast.Ident)
+			ignored = len(parent.Lhs) == 1 && ok && id.Name == "_"
+		}
+		if calls[v] == nil {
+			vars = append(vars, v)
+		}
+		calls[v] = append(calls[v], asCall{call: call, body: body, loop: enclosingLoop(stack), ignored: ignored})
+		return true
+	})
+
+	for _, v := range vars {
+		for i, c := range calls[v] {
+			if !c.ignored || !readAfter(pass, c.body, v, c.call.End(), c.loop) {
+				continue
+			}
+			var prev *ast.CallExpr
+			for _, p := range calls[v][:i] {
+				if p.body == c.body && !assignedBetween(pass, c.body, v, p.call.End(), c.call.Pos()) {
+					prev = p.call
+				}
+			}
+			switch {
+			case prev != nil:
+				pass.Reportf(c.call.Pos(), "the result of errors.As is ignored, but %s may still hold the error matched by errors.As on line %d; check the result, or set %s to nil before the call", v.Name(), pass.Fset.Position(prev.Pos()).Line, v.Name())
+			case c.loop != nil && (v.Pos() < c.loop.Pos() || v.Pos() >= c.loop.End()) && !assignedBetween(pass, c.body, v, c.loop.Pos(), c.call.Pos()):
+				pass.Reportf(c.call.Pos(), "the result of errors.As is ignored, but %s may still hold the error matched in an earlier iteration of the loop; check the result, or declare %s in the loop", v.Name(), v.Name())
+			}
+		}
+	}
+}
+
+// addressedLocal returns the local variable of the package that expr takes
+// the address of, as in &target, or nil.
+func addressedLocal(pass *analysis.Pass, expr ast.Expr) *types.Var {
+	addr, ok := ast.Unparen(expr).(*ast.UnaryExpr)
+	if !ok || addr.Op != token.AND {
+		return nil
+	}
+	id, ok := ast.Unparen(addr.X).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	v, ok := pass.TypesInfo.Uses[id].(*types.Var)
+	if !ok || v.Pkg() != pass.Pkg || v.Parent() == pass.Pkg.Scope() {
+		return nil
+	}
+	return v
+}
+
+// enclosingBody returns the body of the innermost function declaration or
+// literal of stack, or nil.
+func enclosingBody(stack []ast.Node) *ast.BlockStmt {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.FuncLit:
+			return n.Body
+		case *ast.FuncDecl:
+			return n.Body
+		}
+	}
+	return nil
+}
+
+// readAfter reports whether body reads v after pos, or anywhere in loop
+// if it is not nil, other than by taking its address.
+func readAfter(pass *analysis.Pass, body *ast.BlockStmt, v *types.Var, pos token.Pos, loop ast.Stmt) bool {
+	skip := make(map[*ast.Ident]bool)
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.UnaryExpr:
+			if id, ok := ast.Unparen(n.X).(*ast.Ident); ok && n.Op == token.AND {
+				skip[id] = true
+			}
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok {
+					skip[id] = true
+				}
+			}
+		case *ast.Ident:
+			inLoop := loop != nil && n.Pos() >= loop.Pos() && n.Pos() < loop.End()
+			found = found || pass.TypesInfo.Uses[n] == v && !skip[n] && (n.Pos() > pos || inLoop)
+		}
+		return !found
+	})
+	return found
+}
+
+// assignedBetween reports whether body assigns to v between the positions
+// start and end.
+func assignedBetween(pass *analysis.Pass, body *ast.BlockStmt, v *types.Var, start, end token.Pos) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil || n.End() <= start || n.Pos() >= end {
+			return !found
+		}
+		if assign, ok := n.(*ast.AssignStmt); ok {
+			for _, lhs := range assign.Lhs {
+				if id, ok := ast.Unparen(lhs).(*ast.Ident); ok && pass.TypesInfo.Uses[id] == v {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
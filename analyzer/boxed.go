@@ -0,0 +1,354 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// checkBoxedErrors reports comparisons with == and != that do not compare
+// what they seem to, because of the value an interface holds. An interface
+// that holds a nil pointer is not nil, so nil checks of a local variable or
+// the value of a local map that only store errors of a concrete type, such
+// as
+//
+//	var err error = parse(line) // parse returns a *ParseError
+//	if err != nil {
+//
+// are true even if the function returned a nil pointer, and so is the nil
+// check of an error converted to an interface, as in error(pe) != nil.
+// Comparing an error with a value of an interface type other than error,
+// such as any, compares the values themselves, like ==, so wrapped errors
+// do not match, and the comparison check does not see it.
+func (l *linter) checkBoxedErrors(pass *analysis.Pass, insp *inspector.Inspector) {
+	boxes := findBoxes(pass, insp)
+	insp.Preorder([]ast.Node{(*ast.BinaryExpr)(nil)}, func(n ast.Node) {
+		expr := n.(*ast.BinaryExpr)
+		if expr.Op != token.EQL && expr.Op != token.NEQ || inIsMethod(pass, expr.Pos()) {
+			return
+		}
+		x, y := ast.Unparen(expr.X), ast.Unparen(expr.Y)
+		if isNil(pass, x) {
+			x, y = y, x
+		}
+		if isNil(pass, y) {
+			outcome := "true"
+			if expr.Op == token.EQL {
+				outcome = "false"
+			}
+			switch x := x.(type) {
+			case *ast.Ident:
+				if box, ok := boxes.of(localVar(pass, x)); ok {
+					pass.Reportf(expr.Pos(), "%s is %s even if %s holds a nil %s, as stored at line %d: an interface that holds a nil pointer is not nil; check the %s for nil before storing it in %s", render(pass, expr), outcome, x.Name, typeString(pass, box.typ), pass.Fset.Position(box.pos).Line, typeString(pass, box.typ), x.Name)
+				}
+			case *ast.IndexExpr:
+				if box, ok := boxes.of(localVar(pass, x.X)); ok {
+					pass.Reportf(expr.Pos(), "%s is %s even if the value is a nil %s, as stored at line %d: an interface that holds a nil pointer is not nil; check the %s for nil before storing it in %s", render(pass, expr), outcome, typeString(pass, box.typ), pass.Fset.Position(box.pos).Line, typeString(pass, box.typ), render(pass, x.X))
+				}
+			case *ast.CallExpr:
+				if t, arg := boxingConversion(pass, x); t != nil {
+					pass.Reportf(expr.Pos(), "%s is always %s: converting %s to an interface gives a value that is not nil, even if %s is a nil %s; compare %s with nil before the conversion", render(pass, expr), outcome, render(pass, arg), render(pass, arg), typeString(pass, t), render(pass, arg))
+				}
+			}
+			return
+		}
+
+		if otherInterface(pass, y) {
+			x, y = y, x
+		}
+		if !otherInterface(pass, x) || !isError(pass, y) || l.isAllowedSentinel(pass, y) {
+			return
+		}
+		pass.Reportf(expr.Pos(), "%s has type %s, so %s compares the value it holds with the error %s and misses it once it is wrapped; assert %s to error and use errors.Is", render(pass, x), typeString(pass, pass.TypesInfo.TypeOf(x)), expr.Op, render(pass, y), render(pass, x))
+	})
+}
+
+// otherInterface reports whether expr has an interface type that does not
+// implement error, such as any.
+func otherInterface(pass *analysis.Pass, expr ast.Expr) bool {
+	t := pass.TypesInfo.TypeOf(expr)
+	return t != nil && types.IsInterface(t) && !types.Implements(t, errorIface) && !isNil(pass, expr)
+}
+
+// boxingConversion returns the type of the argument of call and the
+// argument itself if call converts an error of a concrete type that can
+// be nil to an interface, as in error(pe) or any(pe).
+func boxingConversion(pass *analysis.Pass, call *ast.CallExpr) (types.Type, ast.Expr) {
+	tv, ok := pass.TypesInfo.Types[call.Fun]
+	if !ok || !tv.IsType() || !types.IsInterface(tv.Type) || len(call.Args) != 1 {
+		return nil, nil
+	}
+	arg := ast.Unparen(call.Args[0])
+	t := pass.TypesInfo.TypeOf(arg)
+	if !nilableError(t) || !maybeNil(pass, arg) {
+		return nil, nil
+	}
+	return t, arg
+}
+
+// nilableError reports whether t is a concrete type that implements error
+// and has a nil value, such as a pointer type.
+func nilableError(t types.Type) bool {
+	if t == nil || types.IsInterface(t) || !types.Implements(t, errorIface) {
+		return false
+	}
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Map, *types.Slice, *types.Signature, *types.Chan:
+		return true
+	}
+	return false
+}
+
+// maybeNil reports whether expr may be nil, which the address of a
+// composite literal, a composite literal and the result of new never are.
+func maybeNil(pass *analysis.Pass, expr ast.Expr) bool {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.UnaryExpr:
+		_, lit := ast.Unparen(e.X).(*ast.CompositeLit)
+		return !(e.Op == token.AND && lit)
+	case *ast.CompositeLit, *ast.FuncLit:
+		return false
+	case *ast.CallExpr:
+		return !isBuiltin(pass, e, "new") && !isBuiltin(pass, e, "make")
+	}
+	return true
+}
+
+// typeString returns t qualified by package names.
+func typeString(pass *analysis.Pass, t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		if pkg == pass.Pkg {
+			return ""
+		}
+		return pkg.Name()
+	})
+}
+
+// box is the first error of a concrete type that may be nil that a local
+// variable or map stores.
+type box struct {
+	typ types.Type
+	pos token.Pos
+}
+
+// boxSet holds the local variables of interface types, and the local maps
+// with values of interface types, that store errors of concrete types that
+// may be nil.
+type boxSet struct {
+	boxes map[*types.Var]box
+	// mixed holds the variables and maps that store other values, such as
+	// errors of interface types, or whose address is taken, so they may
+	// hold anything.
+	mixed map[*types.Var]bool
+}
+
+// of returns the first error v stores, if v only stores errors of concrete
+// types that may be nil, besides nil itself and values that are never nil.
+func (s *boxSet) of(v *types.Var) (box, bool) {
+	b, ok := s.boxes[v]
+	return b, ok && !s.mixed[v]
+}
+
+// findBoxes returns the boxes of the package.
+func findBoxes(pass *analysis.Pass, insp *inspector.Inspector) *boxSet {
+	s := &boxSet{
+		boxes: make(map[*types.Var]box),
+		mixed: make(map[*types.Var]bool),
+	}
+	// Variables can store the values of maps, so maps go first.
+	for _, maps := range []bool{true, false} {
+		insp.WithStack([]ast.Node{(*ast.Ident)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+			if !push {
+				return true
+			}
+			id := n.(*ast.Ident)
+			v, ok := pass.TypesInfo.ObjectOf(id).(*types.Var)
+			if !ok || v.IsField() || v.Parent() == nil || v.Parent() == pass.Pkg.Scope() {
+				return true
+			}
+			m, ok := v.Type().Underlying().(*types.Map)
+			switch {
+			case maps && ok && types.IsInterface(m.Elem()):
+				s.mapUse(pass, v, id, stack)
+			case !maps && types.IsInterface(v.Type()):
+				s.varUse(pass, v, id, stack)
+			}
+			return true
+		})
+	}
+	return s
+}
+
+// store records that v stores rhs, of type t.
+func (s *boxSet) store(pass *analysis.Pass, v *types.Var, rhs ast.Expr, t types.Type) {
+	switch {
+	case rhs == nil || isNil(pass, rhs):
+	case nilableError(t):
+		if _, ok := s.boxes[v]; !ok && maybeNil(pass, rhs) {
+			s.boxes[v] = box{typ: t, pos: rhs.Pos()}
+		}
+	case types.IsInterface(t):
+		if index, ok := ast.Unparen(rhs).(*ast.IndexExpr); ok {
+			if m := localVar(pass, index.X); m != nil {
+				s.flow(v, m)
+				return
+			}
+		}
+		s.mixed[v] = true
+	}
+}
+
+// flow records that v stores the values of the map m.
+func (s *boxSet) flow(v, m *types.Var) {
+	b, ok := s.of(m)
+	if !ok {
+		s.mixed[v] = true
+		return
+	}
+	if _, ok := s.boxes[v]; !ok {
+		s.boxes[v] = b
+	}
+}
+
+// mapUse records the use of the local map m by id, the last node of stack.
+// Indexing it, ranging over it, passing it to len, delete or clear, and
+// declaring it empty or with a literal keep it local; any other use, such
+// as passing it to a function, may store other values in it.
+func (s *boxSet) mapUse(pass *analysis.Pass, m *types.Var, id *ast.Ident, stack []ast.Node) {
+	switch p := stack[len(stack)-2].(type) {
+	case *ast.IndexExpr:
+		if p.X != id {
+			break
+		}
+		if assign, ok := stack[len(stack)-3].(*ast.AssignStmt); ok && len(assign.Lhs) == len(assign.Rhs) {
+			for i, lhs := range assign.Lhs {
+				if lhs == p {
+					s.store(pass, m, assign.Rhs[i], pass.TypesInfo.TypeOf(assign.Rhs[i]))
+				}
+			}
+		}
+		return
+	case *ast.RangeStmt:
+		if p.X == id {
+			return
+		}
+	case *ast.CallExpr:
+		if isBuiltin(pass, p, "len") || isBuiltin(pass, p, "delete") || isBuiltin(pass, p, "clear") {
+			return
+		}
+	case *ast.ValueSpec, *ast.AssignStmt:
+		rhs, ok := definedValue(p, id)
+		if !ok {
+			break
+		}
+		switch rhs := ast.Unparen(rhs).(type) {
+		case nil:
+			return
+		case *ast.CompositeLit:
+			for _, elt := range rhs.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					s.store(pass, m, kv.Value, pass.TypesInfo.TypeOf(kv.Value))
+				}
+			}
+			return
+		case *ast.CallExpr:
+			if isBuiltin(pass, rhs, "make") {
+				return
+			}
+		}
+	}
+	s.mixed[m] = true
+}
+
+// varUse records the use of the local variable v by id, the last node of
+// stack. Assignments and declarations store values in it, and taking its
+// address, ranging into it or declaring it as a parameter may store
+// anything.
+func (s *boxSet) varUse(pass *analysis.Pass, v *types.Var, id *ast.Ident, stack []ast.Node) {
+	switch p := stack[len(stack)-2].(type) {
+	case *ast.AssignStmt:
+		for i, lhs := range p.Lhs {
+			if lhs != id {
+				continue
+			}
+			switch {
+			case len(p.Lhs) == len(p.Rhs):
+				s.store(pass, v, p.Rhs[i], pass.TypesInfo.TypeOf(p.Rhs[i]))
+			case len(p.Rhs) == 1:
+				tuple, ok := pass.TypesInfo.TypeOf(p.Rhs[0]).(*types.Tuple)
+				if !ok {
+					s.mixed[v] = true
+					return
+				}
+				if index, ok := ast.Unparen(p.Rhs[0]).(*ast.IndexExpr); ok && i == 0 {
+					s.store(pass, v, index, tuple.At(0).Type())
+					return
+				}
+				s.store(pass, v, p.Rhs[0], tuple.At(i).Type())
+			}
+		}
+	case *ast.ValueSpec:
+		for i, name := range p.Names {
+			switch {
+			case name != id:
+			case len(p.Values) == len(p.Names):
+				s.store(pass, v, p.Values[i], pass.TypesInfo.TypeOf(p.Values[i]))
+			case len(p.Values) == 1:
+				tuple, ok := pass.TypesInfo.TypeOf(p.Values[0]).(*types.Tuple)
+				if !ok {
+					s.mixed[v] = true
+					return
+				}
+				s.store(pass, v, p.Values[0], tuple.At(i).Type())
+			}
+		}
+	case *ast.RangeStmt:
+		if p.Value != id {
+			if p.Key == id {
+				s.mixed[v] = true
+			}
+			return
+		}
+		if m := localVar(pass, p.X); m != nil && isMap(m) {
+			s.flow(v, m)
+			return
+		}
+		s.mixed[v] = true
+	case *ast.UnaryExpr:
+		if p.Op == token.AND {
+			s.mixed[v] = true
+		}
+	case *ast.Field:
+		s.mixed[v] = true
+	}
+}
+
+// definedValue returns the value parent, a declaration or an assignment,
+// gives id.
+func definedValue(parent ast.Node, id *ast.Ident) (ast.Expr, bool) {
+	switch p := parent.(type) {
+	case *ast.ValueSpec:
+		for i, name := range p.Names {
+			if name == id && len(p.Values) == len(p.Names) {
+				return p.Values[i], true
+			}
+		}
+		return nil, len(p.Values) == 0
+	case *ast.AssignStmt:
+		for i, lhs := range p.Lhs {
+			if lhs == id && len(p.Lhs) == len(p.Rhs) {
+				return p.Rhs[i], true
+			}
+		}
+	}
+	return nil, false
+}
+
+// isMap reports whether v is a map.
+func isMap(v *types.Var) bool {
+	_, ok := v.Type().Underlying().(*types.Map)
+	return ok
+}
@@ -0,0 +1,801 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Check describes one of the rules errlint enforces. Diagnostics carry the
+// name of the check that reported them in their Category, and end with its
+// ID in brackets, such as "[ERRLINT001]".
+type Check struct {
+	// ID identifies the check stably, for example in SARIF logs and in
+	// errlint explain.
+	ID string
+	// Name identifies the check in the -checks flag and in diagnostics.
+	Name string
+	// Doc is a one-sentence summary of what the check reports.
+	Doc string
+	// Rationale explains why the reported code is wrong.
+	Rationale string
+	// Bad and Good are examples of code the check reports and of its fix.
+	Bad, Good string
+	// Links point to the Go documentation of the correct pattern.
+	Links []string
+	// OptIn is set for checks that only run if they are enabled
+	// explicitly.
+	OptIn bool
+}
+
+// Checks returns the checks errlint implements, in the order of their IDs.
+func Checks() []Check {
+	out := make([]Check, len(checks))
+	for i, c := range checks {
+		out[i] = Check{
+			ID:        c.id,
+			Name:      c.name,
+			Doc:       c.doc,
+			Rationale: c.rationale,
+			Bad:       c.bad,
+			Good:      c.good,
+			Links:     c.links,
+			OptIn:     c.optIn,
+		}
+	}
+	return out
+}
+
+// check is the implementation of a Check.
+type check struct {
+	id        string
+	name      string
+	doc       string
+	rationale string
+	bad, good string
+	links     []string
+	optIn     bool
+	run       func(l *linter, pass *analysis.Pass, insp *inspector.Inspector)
+}
+
+var checks = []check{
+	{
+		id:   "ERRLINT001",
+		name: "comparison",
+		doc:  "Reports errors compared against sentinel values with == or !=.",
+		rationale: `== only matches the error value itself. As soon as a function wraps the
+sentinel, with fmt.Errorf("...: %w", err) or errors.Join, the comparison
+fails and the error goes unhandled. errors.Is unwraps the chain and also
+calls the Is methods of custom error types.
+
+Sentinels documented to be returned unwrapped, such as io.EOF, may be
+compared directly; see the -allow flag. Packages can document that
+guarantee for their own sentinels with an //errlint:unwrapped directive in
+the comment of the sentinel or of the package, which errlint records as a
+fact. Comparing them with an error that was just wrapped, for example by
+fmt.Errorf with %w earlier in the same function, is still reported: it
+never matches. So is a directive on a sentinel a function of its own
+package returns wrapped.`,
+		bad:  "if err == ErrNotFound {",
+		good: "if errors.Is(err, ErrNotFound) {",
+		links: []string{
+			"https://pkg.go.dev/errors#Is",
+			"https://go.dev/blog/go1.13-errors",
+		},
+		run: (*linter).checkComparisons,
+	},
+	{
+		id:   "ERRLINT002",
+		name: "assertion",
+		doc:  "Reports type assertions on error values.",
+		rationale: `A type assertion only looks at the outermost error, so it fails once the
+error is wrapped. errors.As walks the chain and sets its target to the
+first error of the target type, which may also be an interface such as
+net.Error.`,
+		bad:  "if pe, ok := err.(*fs.PathError); ok {",
+		good: "var pe *fs.PathError\nif errors.As(err, &pe) {",
+		links: []string{
+			"https://pkg.go.dev/errors#As",
+			"https://go.dev/blog/go1.13-errors",
+		},
+		run: (*linter).checkAssertions,
+	},
+	{
+		id:   "ERRLINT003",
+		name: "switch",
+		doc:  "Reports switch statements over error values or error types.",
+		rationale: `A switch on an error value compares its cases with ==, and a type switch
+asserts the type of the outermost error, so both miss wrapped errors. A
+tagless switch with errors.Is and errors.As cases keeps the shape and
+matches through wrapping.`,
+		bad:  "switch err {\ncase ErrNotFound:\n\t...\n}",
+		good: "switch {\ncase errors.Is(err, ErrNotFound):\n\t...\n}",
+		links: []string{
+			"https://pkg.go.dev/errors#Is",
+			"https://pkg.go.dev/errors#As",
+		},
+		run: (*linter).checkSwitches,
+	},
+	{
+		id:   "ERRLINT004",
+		name: "errorf",
+		doc:  "Reports errors formatted with %v or %s instead of %w in fmt.Errorf.",
+		rationale: `%v and %s flatten the error into the message of the new error, so
+errors.Is and errors.As can no longer find it. %w keeps it in the chain.
+The check also reports %w verbs without an argument, %w verbs whose
+argument is not an error, which fmt formats as %!w(...) without wrapping
+anything, error arguments without a verb, and several %w verbs in one
+call in files built with a Go version older than 1.20, which only added
+support for them. When a call with an error passes more or fewer arguments
+than its format takes, as in fmt.Errorf("reading %s: %w", err), the
+arguments land on the wrong verbs: an extra error is appended to the
+message as %!(EXTRA ...) and a %w verb without an argument wraps nothing.
+The check then reports the mismatch rather than the verbs.`,
+		bad:  `return fmt.Errorf("reading config: %v", err)`,
+		good: `return fmt.Errorf("reading config: %w", err)`,
+		links: []string{
+			"https://pkg.go.dev/fmt#Errorf",
+			"https://go.dev/doc/go1.20#errors",
+		},
+		run: (*linter).checkErrorf,
+	},
+	{
+		id:   "ERRLINT005",
+		name: "oserror",
+		doc:  "Reports os.IsNotExist, os.IsExist, os.IsPermission and os.IsTimeout, which do not unwrap errors.",
+		rationale: `The os.Is helpers predate error wrapping and only look through a few error
+types of package os, so they report false for an fs.ErrNotExist wrapped
+with fmt.Errorf. errors.Is with the sentinels of package io/fs works on
+any chain; a timeout is matched with errors.As and a Timeout method.`,
+		bad:  "if os.IsNotExist(err) {",
+		good: "if errors.Is(err, fs.ErrNotExist) {",
+		links: []string{
+			"https://pkg.go.dev/os#IsNotExist",
+			"https://pkg.go.dev/io/fs#pkg-variables",
+		},
+		run: (*linter).checkOSErrors,
+	},
+	{
+		id:   "ERRLINT006",
+		name: "ignore",
+		doc:  "Reports errlint:ignore directives that suppress no finding, name unknown checks or give no reason.",
+		rationale: `A //errlint:ignore comment suppresses the findings of the checks it names
+on its line, or on the next line if it stands alone:
+
+    //errlint:ignore ERRLINT001 the driver returns io.EOF unwrapped
+    if err == io.EOF {
+
+Once the code changes, a directive may suppress nothing, and it then
+hides the next finding on that line without anyone noticing. Remove
+directives that suppress nothing, and say why the others are needed.`,
+		bad:  "//errlint:ignore ERRLINT001\nif errors.Is(err, ErrNotFound) {",
+		good: "if errors.Is(err, ErrNotFound) {",
+		links: []string{
+			"https://go.dev/doc/comment#syntax",
+		},
+		run: (*linter).checkIgnores,
+	},
+	{
+		id:    "ERRLINT007",
+		name:  "dynamic",
+		doc:   "Reports errors created with errors.New or fmt.Errorf inside functions and returned or compared directly.",
+		optIn: true,
+		rationale: `An error created with errors.New, or with fmt.Errorf without %w, inside a
+function is a new value on every call. Callers cannot match it with
+errors.Is and have to compare messages instead, and comparing with it
+never matches. Declare the error once as a package-level sentinel, return
+it wrapped with the details of the call, and compare with the sentinel.
+
+Not every codebase wants every error to be a sentinel, so this check only
+runs if it is enabled, with -enable dynamic or enable: [dynamic] in the
+configuration file.`,
+		bad:  `return fmt.Errorf("user %q not found", name)`,
+		good: "var ErrNotFound = errors.New(\"not found\")\n\nreturn fmt.Errorf(\"user %q: %w\", name, ErrNotFound)",
+		links: []string{
+			"https://pkg.go.dev/errors#New",
+			"https://go.dev/blog/go1.13-errors",
+		},
+		run: (*linter).checkDynamic,
+	},
+	{
+		id:   "ERRLINT008",
+		name: "message",
+		doc:  "Reports error messages, err.Error(), matched with strings.Contains and similar functions or compared with == to a string.",
+		rationale: `Error messages are written for people. They change between versions, and
+they grow as callers wrap the error with context, so matching their text
+breaks without any compiler error. Match the error itself: errors.Is with
+a sentinel, or errors.As with the error type or an interface such as
+net.Error. If a package offers nothing to match, ask for a sentinel.`,
+		bad:  `if strings.Contains(err.Error(), "permission denied") {`,
+		good: "if errors.Is(err, fs.ErrPermission) {",
+		links: []string{
+			"https://pkg.go.dev/errors#Is",
+			"https://pkg.go.dev/errors#As",
+		},
+		run: (*linter).checkMessages,
+	},
+	{
+		id:   "ERRLINT009",
+		name: "errorsnew",
+		doc:  "Reports fmt.Errorf calls without verbs or arguments, which errors.New does with less work.",
+		rationale: `fmt.Errorf with a constant message and no arguments creates the same error
+as errors.New, after parsing the message as a format for nothing. It also
+tells the reader that something is formatted or wrapped when nothing is.
+The fix rewrites the call to errors.New and updates the imports.`,
+		bad:  `return fmt.Errorf("connection closed")`,
+		good: `return errors.New("connection closed")`,
+		links: []string{
+			"https://pkg.go.dev/errors#New",
+		},
+		run: (*linter).checkErrorsNew,
+	},
+	{
+		id:    "ERRLINT010",
+		name:  "wrapcheck",
+		doc:   "Reports errors from functions of other packages returned without wrapping.",
+		optIn: true,
+		rationale: `An error returned as it is from another package only says what failed
+deep down, such as "EOF" or "connection refused", not what the program
+was doing. Wrap it with fmt.Errorf and %w where it crosses into your
+code, so the message tells the whole story and errors.Is still matches.
+
+Errors of package errors and of fmt.Errorf, and of calls that take an
+error, which are assumed to wrap it, may be returned as they are, and so
+may methods delegating to a method of the same name, such as a Read
+method returning the io.EOF of the reader it wraps, which callers compare
+with ==. The -passthrough flag, or passthrough in the configuration file,
+adds packages, such as example.com/store, and functions, such as
+example.com/store.DB.Get, whose errors already say enough.
+
+This check only runs if it is enabled, with -enable wrapcheck or
+enable: [wrapcheck] in the configuration file.`,
+		bad:  "data, err := os.ReadFile(name)\nif err != nil {\n\treturn nil, err\n}",
+		good: "data, err := os.ReadFile(name)\nif err != nil {\n\treturn nil, fmt.Errorf(\"loading settings: %w\", err)\n}",
+		links: []string{
+			"https://go.dev/blog/go1.13-errors#whether-to-wrap",
+		},
+		run: (*linter).checkWrapcheck,
+	},
+	{
+		id:   "ERRLINT011",
+		name: "isas",
+		doc:  "Reports calls of errors.Is and errors.As that cannot match or that panic.",
+		rationale: `errors.Is(err, nil) only reports whether err is nil, and errors.Is(nil,
+target) never matches a sentinel: the arguments are usually swapped or
+the wrong variable is passed. errors.As panics unless its target is a
+non-nil pointer to a type that implements error, or to an interface, so
+passing target instead of &target, or &target where only *T implements
+error, fails at run time. go vet catches some of these; this check also
+reports a nil pointer variable passed as the target.
+
+An error target, as in errors.As(err, &other) with other of type error,
+matches any error and just copies it, and errors.As(err, &err) sets err to
+itself. Use a concrete error type, or an interface such as net.Error.`,
+		bad:  "var target *NotFoundError\nif errors.As(err, target) {",
+		good: "var target *NotFoundError\nif errors.As(err, &target) {",
+		links: []string{
+			"https://pkg.go.dev/errors#As",
+			"https://pkg.go.dev/golang.org/x/tools/go/analysis/passes/errorsas",
+		},
+		run: (*linter).checkIsAs,
+	},
+	{
+		id:   "ERRLINT012",
+		name: "sentinelname",
+		doc:  "Reports exported sentinel errors whose name does not start with Err.",
+		rationale: `By convention, the name of an exported sentinel error starts with Err, as
+in io.ErrUnexpectedEOF and fs.ErrNotExist. Readers then recognize a
+sentinel at the call site, in errors.Is(err, store.ErrNotFound), and
+tools such as this linter find it. Unexported sentinels follow the same
+convention with err, which this check does not enforce.`,
+		bad:  `var NotFound = errors.New("not found")`,
+		good: `var ErrNotFound = errors.New("not found")`,
+		links: []string{
+			"https://go.dev/wiki/Errors#naming",
+		},
+		run: (*linter).checkSentinelNames,
+	},
+	{
+		id:   "ERRLINT013",
+		name: "typename",
+		doc:  "Reports exported error types whose name does not end in Error.",
+		rationale: `By convention, the name of an exported error type ends in Error, as in
+fs.PathError and json.SyntaxError, so that errors.As targets such as
+var notFound *store.NotFoundError read as what they are. Interfaces that
+embed error describe behavior instead, like net.Error, and are exempt.`,
+		bad:  "type NotFound struct{ Item string }\n\nfunc (e *NotFound) Error() string",
+		good: "type NotFoundError struct{ Item string }\n\nfunc (e *NotFoundError) Error() string",
+		links: []string{
+			"https://go.dev/wiki/Errors#naming",
+		},
+		run: (*linter).checkTypeNames,
+	},
+	{
+		id:   "ERRLINT014",
+		name: "deferwrap",
+		doc:  "Reports deferred functions that wrap an error the function does not return, or wrap a nil error.",
+		rationale: `A deferred function can add context to every error a function returns by
+assigning to its named error result:
+
+    func Save(name string) (err error) {
+        defer func() {
+            if err != nil {
+                err = fmt.Errorf("saving %s: %w", name, err)
+            }
+        }()
+
+This only works if it assigns to the result itself. An err declared in an
+inner block, or in the deferred function as in if err := f.Close(); ...,
+shadows the result, and a local err of a function whose results have no
+names is copied into the result before deferred functions run, so the
+wrapped error is thrown away. Without the nil check, fmt.Errorf turns a
+nil error into "saving x: %!w(<nil>)" and the function fails when it
+succeeds.`,
+		bad:  "defer func() {\n\terr = fmt.Errorf(\"saving %s: %w\", name, err)\n}()",
+		good: "defer func() {\n\tif err != nil {\n\t\terr = fmt.Errorf(\"saving %s: %w\", name, err)\n\t}\n}()",
+		links: []string{
+			"https://go.dev/ref/spec#Defer_statements",
+			"https://go.dev/blog/defer-panic-and-recover",
+		},
+		run: (*linter).checkDeferWraps,
+	},
+	{
+		id:   "ERRLINT015",
+		name: "errortext",
+		doc:  "Reports err.Error() formatted with %s or %v, or concatenated into the format, in fmt.Errorf.",
+		rationale: `Passing the message of an error to fmt.Errorf keeps its text but not the
+error, so the new error wraps nothing and errors.Is and errors.As no
+longer find the original error. Concatenating the message into the format
+also lets a % in it be read as a verb. Pass the error itself under %w
+instead, which formats the same message and keeps the error in the chain.`,
+		bad:  "return fmt.Errorf(\"reading config: %s\", err.Error())\nreturn fmt.Errorf(\"reading config: \" + err.Error())",
+		good: `return fmt.Errorf("reading config: %w", err)`,
+		links: []string{
+			"https://pkg.go.dev/fmt#Errorf",
+			"https://go.dev/blog/go1.13-errors",
+		},
+		run: (*linter).checkErrorText,
+	},
+	{
+		id:    "ERRLINT016",
+		name:  "swallow",
+		doc:   "Reports errors that are logged and then dropped by returning nil.",
+		optIn: true,
+		rationale: `An error that is logged and followed by return nil is handled twice over
+as far as the log goes, and not at all as far as the caller goes: the
+call appears to succeed, so the caller carries on with a missing result,
+and errors.Is and errors.As have nothing to match. Either handle the
+error where it happens, or return it, wrapped with what the function was
+doing, and let the caller decide whether to log it.
+
+The check reports if err != nil blocks that pass err to a logging
+function and end by returning nil for every error result. Calls of
+packages log and log/slog are logging calls; the -loggers flag, or
+loggers in the configuration file, adds packages, such as
+go.uber.org/zap, and functions, such as example.com/app.Logger.Error.
+
+This check only runs if it is enabled, with -enable swallow or
+enable: [swallow] in the configuration file.`,
+		bad:  "if err != nil {\n\tlog.Printf(\"saving %s: %v\", name, err)\n\treturn nil\n}",
+		good: "if err != nil {\n\treturn fmt.Errorf(\"saving %s: %w\", name, err)\n}",
+		links: []string{
+			"https://go.dev/wiki/CodeReviewComments#handle-errors",
+			"https://dave.cheney.net/2015/11/05/lets-talk-about-logging",
+		},
+		run: (*linter).checkSwallow,
+	},
+	{
+		id:   "ERRLINT017",
+		name: "shadow",
+		doc:  "Reports error variables declared with := that shadow an error returned or checked after the block.",
+		rationale: `An err declared with := inside a block is a new variable, which hides the
+err of the enclosing block until the block ends. The error assigned to it
+never reaches the outer err, so when the function returns the outer err or
+checks it after the block, it sees whatever was there before, typically
+nil, and the failure goes unnoticed.
+
+The check reports such declarations unless the inner err is handled in the
+block: returned, passed on, or checked by an if statement that leaves the
+block on error. Assign to the outer err with = instead; the suggested fix
+does so when the other variables on the left are already declared.`,
+		bad:  "var err error\nif compress {\n\tdata, err := gzip(data)\n\tif err == nil {\n\t\tsave(data)\n\t}\n}\nreturn err",
+		good: "var err error\nif compress {\n\tvar data []byte\n\tdata, err = gzip(data)\n\tif err == nil {\n\t\tsave(data)\n\t}\n}\nreturn err",
+		links: []string{
+			"https://go.dev/ref/spec#Short_variable_declarations",
+			"https://pkg.go.dev/golang.org/x/tools/go/analysis/passes/shadow",
+		},
+		run: (*linter).checkShadows,
+	},
+	{
+		id:   "ERRLINT018",
+		name: "panic",
+		doc:  "Reports panic(fmt.Errorf(...)) with an error argument in functions that return an error.",
+		rationale: `A panic unwinds the stack up to a deferred recover, or crashes the
+program. It is meant for bugs and impossible states, not for errors the
+caller could handle: a function that already returns an error should
+return this one too, so its caller can check it, wrap it and match it
+with errors.Is. Panicking instead turns an ordinary failure, such as bad
+input, into a crash, or into control flow that every caller has to
+recover from.
+
+The check reports panic(fmt.Errorf(...)) calls that pass an error to
+fmt.Errorf; errors created from text alone typically describe a bug, such
+as misuse of an API, and are left alone. The suggested fix returns the
+error, with zero values for the other results.`,
+		bad:  "if err != nil {\n\tpanic(fmt.Errorf(\"parsing port %q: %w\", s, err))\n}",
+		good: "if err != nil {\n\treturn 0, fmt.Errorf(\"parsing port %q: %w\", s, err)\n}",
+		links: []string{
+			"https://go.dev/doc/effective_go#panic",
+			"https://go.dev/wiki/CodeReviewComments#dont-panic",
+		},
+		run: (*linter).checkPanics,
+	},
+	{
+		id:   "ERRLINT019",
+		name: "overwrite",
+		doc:  "Reports error variables assigned in each iteration of a loop but only returned or checked after it.",
+		rationale: `An err declared before a loop and assigned in its body with = holds the
+error of the last iteration only: each iteration overwrites the error of
+the one before. When the function then checks or returns err after the
+loop, a failure in any earlier iteration, such as a page of a paginated
+fetch, goes unnoticed, and the results of that iteration are used as if
+it succeeded.
+
+The check reports errors assigned from calls, unless err is also read in
+the loop, for example checked after the assignment or collected with
+errors.Join, or the loop can stop after the assignment with break, return
+or panic, as retries that stop once a call succeeds do. Check the error in
+the loop, or collect the errors of all iterations.`,
+		bad:  "var err error\nfor _, page := range pages {\n\titems, err = fetch(page)\n\tall = append(all, items...)\n}\nreturn all, err",
+		good: "for _, page := range pages {\n\titems, err := fetch(page)\n\tif err != nil {\n\t\treturn nil, fmt.Errorf(\"fetching page %d: %w\", page, err)\n\t}\n\tall = append(all, items...)\n}\nreturn all, nil",
+		links: []string{
+			"https://go.dev/blog/errors-are-values",
+			"https://pkg.go.dev/errors#Join",
+		},
+		run: (*linter).checkOverwrites,
+	},
+	{
+		id:   "ERRLINT020",
+		name: "sprintf",
+		doc:  "Reports errors.New(fmt.Sprintf(...)), and fmt.Sprintf calls that format an error into the format or an argument of fmt.Errorf.",
+		rationale: `errors.New(fmt.Sprintf(...)) formats the message into a string and then
+creates an error from it, which fmt.Errorf does in one call. When the
+arguments include an error, or its message, err.Error(), the new error
+only keeps its text, and errors.Is and errors.As no longer find it. The
+same happens when fmt.Sprintf formats an error into the format or an
+argument of fmt.Errorf: fmt.Errorf sees a string and has nothing to wrap.
+
+The fixes call fmt.Errorf with the format and arguments of fmt.Sprintf,
+pass the errors formatted with a plain %s or %v under %w instead, and
+remove the errors import once it is no longer used.`,
+		bad:  "return errors.New(fmt.Sprintf(\"user %d: %v\", id, err))\nreturn fmt.Errorf(\"loading: %s\", fmt.Sprintf(\"user %d: %v\", id, err))",
+		good: `return fmt.Errorf("user %d: %w", id, err)`,
+		links: []string{
+			"https://pkg.go.dev/fmt#Errorf",
+			"https://go.dev/blog/go1.13-errors",
+		},
+		run: (*linter).checkSprintf,
+	},
+	{
+		id:   "ERRLINT021",
+		name: "reassign",
+		doc:  "Reports sentinel errors assigned to outside their declaration.",
+		rationale: `A sentinel error is a value that callers match with errors.Is. Assigning a
+new value to it, for example to change its message, does not change the
+errors created before: they wrap the old value, so errors.Is(err,
+ErrNotFound) no longer matches them, in this package or in any package
+that imports it, and only errors created afterwards match. Which of the
+two an error is depends on timing, and an assignment while other
+goroutines read the sentinel is a data race.
+
+The check reports assignments to package-level error variables named
+ErrX or errX, and to the sentinels of the -allow list, such as io.EOF,
+whether they belong to the package or to a package it imports. Variables
+declared without a value, such as an errLast holding the last error, are
+state rather than sentinels and are left alone. Declare a new sentinel
+instead, and change messages where errors are shown, not where they are
+matched.`,
+		bad:  "func init() {\n\tErrNotFound = errors.New(\"introuvable\")\n}",
+		good: "var ErrNotFound = errors.New(\"not found\")\n\nif errors.Is(err, ErrNotFound) {\n\tfmt.Println(\"introuvable\")\n}",
+		links: []string{
+			"https://go.dev/blog/go1.13-errors",
+			"https://dave.cheney.net/2016/04/07/constant-errors",
+		},
+		run: (*linter).checkReassigns,
+	},
+	{
+		id:   "ERRLINT022",
+		name: "newcompare",
+		doc:  "Reports errors compared with an error created by errors.New or fmt.Errorf in the same function.",
+		rationale: `errors.New returns a new value on every call, and so does fmt.Errorf
+without %w. Two errors created from the same text are different values, so
+comparing an error with one created on the spot, err == errors.New("not
+found"), never matches, and neither does errors.Is: it compares the errors
+of the chain with == too, unless they have an Is method. The same holds
+for a local variable that holds such an error, as long as the function
+does not pass it on, so no other error can be it.
+
+Declare the error once as a package-level sentinel, return it, wrapped or
+not, where the failure happens, and compare with the sentinel using
+errors.Is. The check takes these comparisons over from the comparison
+check, whose errors.Is fix would not help, and from the opt-in dynamic
+check.`,
+		bad:  "notFound := errors.New(\"not found\")\nif err == notFound {",
+		good: "var ErrNotFound = errors.New(\"not found\")\n\nif errors.Is(err, ErrNotFound) {",
+		links: []string{
+			"https://pkg.go.dev/errors#New",
+			"https://go.dev/blog/go1.13-errors",
+		},
+		run: (*linter).checkNewCompares,
+	},
+	{
+		id:   "ERRLINT023",
+		name: "nilerror",
+		doc:  "Reports err.Error() calls and %s, %q or %w verbs on an error variable that is nil on that path.",
+		rationale: `err.Error() calls the Error method through the interface, and on a nil
+error that panics with a nil pointer dereference. It happens on paths
+where the error is known to be nil: in the branch of if err == nil, or
+after an if err != nil block that returns, where the code was moved or the
+condition inverted by mistake. Formatting the error there with %s, %q or
+%w does not panic, but fmt prints %!s(<nil>) in place of a message.
+
+Calling err.Error() right after the call that returned err, before the nil
+check that follows, panics whenever the call succeeds. Check the error
+first, and only use its message where it is not nil.`,
+		bad:  "data, err := load()\nlog.Print(err.Error())\nif err != nil {\n\treturn err\n}",
+		good: "data, err := load()\nif err != nil {\n\tlog.Print(err.Error())\n\treturn err\n}",
+		links: []string{
+			"https://go.dev/doc/faq#nil_error",
+			"https://pkg.go.dev/fmt#hdr-Format_errors",
+		},
+		run: (*linter).checkNilErrors,
+	},
+	{
+		id:    "ERRLINT024",
+		name:  "wrapmsg",
+		doc:   "Reports wrap messages of fmt.Errorf that do not follow the convention \"context: %w\".",
+		optIn: true,
+		rationale: `Each layer that wraps an error adds its context in front of the message of
+the error it wraps, so a chain that follows one convention reads as a path
+from the outermost operation to the cause:
+
+    handling order 42: loading customer: reading row: connection reset
+
+The convention is that the context comes first and ends in ": %w", starts
+in lower case, since it ends up in the middle of the messages of its
+callers, does not end in punctuation, and does not repeat the message of
+the sentinel it wraps, as in fmt.Errorf("not found: %w", ErrNotFound). The
+-wrap-prefix flag, or wrap-prefix in the configuration file, sets a regular
+expression the context must match as well, such as ^[a-z]+ing\b to require
+an operation such as "reading config". Calls with several %w verbs join
+errors and are left alone.
+
+This check only runs if it is enabled, with -enable wrapmsg or
+enable: [wrapmsg] in the configuration file.`,
+		bad:  `return fmt.Errorf("%w (while reading config).", err)`,
+		good: `return fmt.Errorf("reading config: %w", err)`,
+		links: []string{
+			"https://go.dev/wiki/CodeReviewComments#error-strings",
+			"https://go.dev/blog/go1.13-errors#adding-information",
+		},
+		run: (*linter).checkWrapMessages,
+	},
+	{
+		id:   "ERRLINT025",
+		name: "internaltype",
+		doc:  "Reports exported functions that return errors of a type declared in an internal package.",
+		rationale: `Go only lets the packages of the tree that holds an internal directory
+import the packages under it. An exported function that returns an error
+of a type declared there, such as the *storage.NotFoundError of a
+database driver in internal/storage, hands its callers a value they
+cannot name: errors.As needs a target of that type, so they can neither
+match the error nor read its fields, only parse its message.
+
+Translate internal errors where they cross the API: match them with
+errors.As or errors.Is inside the package, and return its own exported
+sentinels or error types, wrapped with the context of the call. The
+check reports return statements whose error has the internal type as its
+static type, and results declared with that type. Exported functions of
+packages internal to the same tree are left alone, since only that tree
+can call them.`,
+		bad:  "var nf *storage.NotFoundError\nif errors.As(err, &nf) {\n\treturn User{}, nf\n}",
+		good: "var nf *storage.NotFoundError\nif errors.As(err, &nf) {\n\treturn User{}, fmt.Errorf(\"getting user %s: %w\", id, ErrNotFound)\n}",
+		links: []string{
+			"https://go.dev/doc/go1.4#internalpackages",
+			"https://go.dev/blog/go1.13-errors#whether-to-wrap",
+		},
+		run: (*linter).checkInternalTypes,
+	},
+	{
+		id:   "ERRLINT026",
+		name: "astarget",
+		doc:  "Reports errors.As calls whose result is ignored while their target may still hold an earlier match.",
+		rationale: `errors.As only sets its target when it matches; otherwise the target keeps
+its value. Code that ignores the result and reads the target instead, as
+in errors.As(err, &nf) followed by if nf != nil, works for the first
+error it sees, but once a target is reused, for a second error or in the
+next iteration of a loop, a failed match leaves the error of the earlier
+match in place and the code acts on it.
+
+Check the result of errors.As, which is only true if this call matched,
+or declare the target where it is used, such as in the if statement or
+inside the loop, so each call starts from nil. Targets set to nil between
+the calls are not reported.`,
+		bad:  "var nf *NotFoundError\nfor _, err := range errs {\n\terrors.As(err, &nf)\n\tif nf != nil {",
+		good: "for _, err := range errs {\n\tvar nf *NotFoundError\n\tif errors.As(err, &nf) {",
+		links: []string{
+			"https://pkg.go.dev/errors#As",
+		},
+		run: (*linter).checkAsTargets,
+	},
+	{
+		id:   "ERRLINT027",
+		name: "boxed",
+		doc:  "Reports nil checks and comparisons of errors held in interfaces that do not compare what they seem to.",
+		rationale: `An interface value is nil only if it holds no value at all. Storing a
+nil *ParseError in a variable of type error, or in a map of errors, gives
+an interface that holds a nil pointer, and that interface is not nil: the
+nil check err != nil is true, and the caller goes on to handle an error
+that does not exist. The same happens to a pointer converted to an
+interface on the spot, as in error(pe) != nil, which is always true.
+
+The check reports nil checks of local variables and local maps of
+interface types that only store errors of a concrete type that may be nil,
+besides nil itself, and nil checks of such conversions. Check the pointer
+for nil before storing it, or have the function that creates the error
+return error, so a nil result stays nil.
+
+It also reports errors compared with a value of an interface type other
+than error, such as any received from a map[string]any or a channel of
+values: == compares the values themselves, so a wrapped error does not
+match, and the comparison check, which only sees comparisons of two
+errors, misses it. Assert the value to error and use errors.Is.`,
+		bad:  "var err error = parse(line) // returns *ParseError\nif err != nil {",
+		good: "if pe := parse(line); pe != nil {\n\treturn pe\n}",
+		links: []string{
+			"https://go.dev/doc/faq#nil_error",
+			"https://pkg.go.dev/errors#Is",
+		},
+		run: (*linter).checkBoxedErrors,
+	},
+	{
+		id:   "ERRLINT028",
+		name: "wrongerr",
+		doc:  "Reports branches that check one error variable but return or wrap another.",
+		rationale: `Functions that call several fallible operations often hold several error
+variables, such as err and err2, or err and cerr for a Close call. A branch
+that checks err2 != nil but returns err, or wraps err with fmt.Errorf,
+drops the error it was written for. Worse, err has usually been checked
+and found nil a few lines earlier, so the function returns nil, or a
+wrapped nil that prints as %!w(<nil>), and the caller carries on as if the
+call succeeded. A bare return in a function with a named error result
+returns that result, which is the same mistake when the branch checks
+another variable.
+
+The check reports returns in the branch of an if statement that checks a
+single local error variable with != nil, when the branch never uses that
+variable and returns, or passes to a call, another error variable it
+neither assigns nor checks, and an earlier check found nil. The fix
+returns the checked variable instead. Returning another error that may be
+set, such as one the function was given, is often deliberate and is not
+reported.`,
+		bad:  "if err2 := validate(data); err2 != nil {\n\treturn fmt.Errorf(\"validating: %w\", err)\n}",
+		good: "if err2 := validate(data); err2 != nil {\n\treturn fmt.Errorf(\"validating: %w\", err2)\n}",
+		links: []string{
+			"https://go.dev/blog/error-handling-and-go",
+			"https://pkg.go.dev/errors#Join",
+		},
+		run: (*linter).checkWrongErrors,
+	},
+	{
+		id:   "ERRLINT029",
+		name: "ignoredok",
+		doc:  "Reports errors.As calls and type assertions whose result is ignored before the variable they set is dereferenced.",
+		rationale: `errors.As reports whether it found a match, and only sets its target if it
+did. Ignoring the result and using the target anyway works while the error
+matches, and dereferences a nil pointer the first time it does not, often
+on an error path that tests never take. A two-value type assertion whose
+ok result is assigned to _ is the same mistake: the value is nil, or the
+zero value of a struct type, whenever the operand holds another type, and
+the assertion no longer panics to say so.
+
+The check reports errors.As calls whose result is dropped or assigned to
+_, with a target declared without a value, and two-value type assertions
+whose ok result is _, when a later statement of the same block selects a
+field or method of the variable, or dereferences it, and nothing compares
+it with nil. The fix moves the statements that use the variable into an if
+statement on the result.`,
+		bad:  "var pathErr *fs.PathError\nerrors.As(err, &pathErr)\nlog.Print(pathErr.Path)",
+		good: "var pathErr *fs.PathError\nif errors.As(err, &pathErr) {\n\tlog.Print(pathErr.Path)\n}",
+		links: []string{
+			"https://pkg.go.dev/errors#As",
+			"https://go.dev/ref/spec#Type_assertions",
+		},
+		run: (*linter).checkIgnoredOK,
+	},
+}
+
+// runOrder lists the checks in the order they run: ignore runs last, once
+// the findings of the other checks are known.
+var runOrder = func() []check {
+	order := slices.Clone(checks)
+	i := slices.IndexFunc(order, func(c check) bool { return c.name == "ignore" })
+	return append(append(order[:i:i], order[i+1:]...), order[i])
+}()
+
+// checkSet is a set of check names. It implements flag.Value.
+type checkSet map[string]bool
+
+// newCheckSet returns a set containing every check that is not opt-in.
+func newCheckSet() checkSet {
+	s := make(checkSet, len(checks))
+	for _, c := range checks {
+		if !c.optIn {
+			s[c.name] = true
+		}
+	}
+	return s
+}
+
+// optInChecks returns the names of the opt-in checks.
+func optInChecks() string {
+	var names []string
+	for _, c := range checks {
+		if c.optIn {
+			names = append(names, c.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+func (s checkSet) String() string {
+	var names []string
+	for _, c := range checks {
+		if s[c.name] {
+			names = append(names, c.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// Set replaces the set with the comma-separated check names in value.
+func (s checkSet) Set(value string) error {
+	names := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !isCheck(name) {
+			return fmt.Errorf("unknown check %q", name)
+		}
+		names[name] = true
+	}
+	clear(s)
+	for name := range names {
+		s[name] = true
+	}
+	return nil
+}
+
+func isCheck(name string) bool {
+	for _, c := range checks {
+		if c.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// findCheck returns the check with the given ID or name.
+func findCheck(idOrName string) (check, bool) {
+	for _, c := range checks {
+		if c.id == idOrName || c.name == idOrName {
+			return c, true
+		}
+	}
+	return check{}, false
+}
@@ -0,0 +1,378 @@
+package analyzer
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// oftenWrapped maps sentinels that are commonly compared with == although
+// standard library packages return them wrapped to where the wrapping
+// happens. Comparisons against them get a more specific message.
+var oftenWrapped = map[string]string{
+	"context.Canceled":         "net/http and database/sql wrap it",
+	"context.DeadlineExceeded": "net/http client timeouts and database drivers wrap it",
+	"os.ErrDeadlineExceeded":   "I/O deadlines wrap it in *os.PathError or *net.OpError",
+	"os.ErrNotExist":           "file operations wrap it in *fs.PathError",
+	"os.ErrPermission":         "file operations wrap it in *fs.PathError",
+	"io/fs.ErrNotExist":        "file operations wrap it in *fs.PathError",
+	"io/fs.ErrPermission":      "file operations wrap it in *fs.PathError",
+	"net.ErrClosed":            "network operations wrap it in *net.OpError",
+}
+
+// checkComparisons reports err == sentinel and err != sentinel expressions,
+// and unwrappedDirective comments on sentinels that their own package
+// returns wrapped.
+func (l *linter) checkComparisons(pass *analysis.Pass, insp *inspector.Inspector) {
+	vars := slices.SortedFunc(maps.Keys(l.facts.unwrapped), func(a, b *types.Var) int {
+		return cmp.Compare(a.Pos(), b.Pos())
+	})
+	for _, v := range vars {
+		var fact sentinelFact
+		if pass.ImportObjectFact(v, &fact) && len(fact.WrappedBy) > 0 {
+			pass.Reportf(l.facts.unwrapped[v].Pos(), "%s is documented to be returned unwrapped, but %s; comparisons with == are still reported", v.Name(), wrapsIt(fact.WrappedBy))
+		}
+	}
+
+	insp.WithStack([]ast.Node{(*ast.BinaryExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		expr := n.(*ast.BinaryExpr)
+		msg, ok := l.comparisonMessage(pass, expr)
+		if !ok {
+			return true
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:            expr.Pos(),
+			End:            expr.End(),
+			Message:        msg,
+			SuggestedFixes: l.comparisonFix(pass, expr, stack),
+		})
+		return true
+	})
+}
+
+// comparisonMessage returns the message for expr if it compares errors with
+// == or != in a way the check reports.
+func (l *linter) comparisonMessage(pass *analysis.Pass, expr *ast.BinaryExpr) (string, bool) {
+	if expr.Op != token.EQL && expr.Op != token.NEQ {
+		return "", false
+	}
+	if !isError(pass, expr.X) || !isError(pass, expr.Y) {
+		return "", false
+	}
+	if isNil(pass, expr.X) || isNil(pass, expr.Y) {
+		return "", false
+	}
+	if inIsMethod(pass, expr.Pos()) {
+		return "", false
+	}
+	// errors.Is does not match new errors either; the newcompare check
+	// reports them.
+	if _, _, ok := l.newError(pass, expr.X); ok {
+		return "", false
+	}
+	if _, _, ok := l.newError(pass, expr.Y); ok {
+		return "", false
+	}
+	// Allowlisted sentinels are returned as they are, but a comparison
+	// with an error known to be wrapped still never matches.
+	wrapped := l.wrappedComparison(pass, expr)
+	if wrapped == "" && (l.isAllowedSentinel(pass, expr.X) || l.isAllowedSentinel(pass, expr.Y) || l.facts.unwrappedSentinel(expr.X) || l.facts.unwrappedSentinel(expr.Y)) {
+		return "", false
+	}
+	if wrapped != "" {
+		return wrapped, true
+	}
+
+	msg := fmt.Sprintf("comparing errors with %s fails on wrapped errors; use errors.Is", expr.Op)
+	if isJoin(pass, expr.X) || isJoin(pass, expr.Y) {
+		msg = fmt.Sprintf("comparing a joined error with %s never matches; use errors.Is", expr.Op)
+	}
+	for _, operand := range []ast.Expr{expr.X, expr.Y} {
+		name, ok := sentinelName(pass, operand)
+		if !ok {
+			continue
+		}
+		reason := oftenWrapped[name]
+		if by := l.facts.wrappedBy(operand); reason == "" && len(by) > 0 {
+			reason = wrapsIt(by)
+		}
+		if reason != "" {
+			msg = fmt.Sprintf("comparing with %s fails for %s, which is usually returned wrapped (%s); use errors.Is", expr.Op, render(pass, operand), reason)
+		}
+	}
+	return msg, true
+}
+
+// wrappedComparison returns the message for comparing an error with a
+// sentinel if the error is known to hold the sentinel, or another error,
+// wrapped where it is compared, for example because it was just wrapped
+// with fmt.Errorf and %w or comes from a function that wraps the sentinel,
+// in this or another package. It returns "" otherwise.
+func (l *linter) wrappedComparison(pass *analysis.Pass, expr *ast.BinaryExpr) string {
+	if isJoin(pass, expr.X) || isJoin(pass, expr.Y) {
+		return ""
+	}
+	for _, pair := range [][2]ast.Expr{{expr.X, expr.Y}, {expr.Y, expr.X}} {
+		operand, other := pair[0], pair[1]
+		name, ok := sentinelName(pass, operand)
+		if !ok {
+			continue
+		}
+		if msg := wrappedMessage(pass, expr, operand, other, name, l.facts.exprAt(other)); msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+// wrappedMessage returns the message for comparing other, of origin o, with
+// the sentinel operand called name, or "".
+func wrappedMessage(pass *analysis.Pass, expr *ast.BinaryExpr, operand, other ast.Expr, name string, o *origin) string {
+	switch {
+	case o.returns[name] || o.opaque:
+		if o.wraps[name] {
+			return fmt.Sprintf("comparing with %s fails: %s can hold %s wrapped%s; use errors.Is", expr.Op, render(pass, other), render(pass, operand), trace(o.via[name]))
+		}
+	case o.wraps[name]:
+		return fmt.Sprintf("comparing with %s never matches: %s only holds %s wrapped%s; use errors.Is", expr.Op, render(pass, other), render(pass, operand), trace(o.via[name]))
+	case len(o.returns) == 0 && (len(o.wraps) > 0 || o.wrapsOther):
+		return fmt.Sprintf("comparing with %s never matches: %s holds a wrapped error, never %s itself; use errors.Is", expr.Op, render(pass, other), render(pass, operand))
+	}
+	return ""
+}
+
+// trace says where a sentinel that comes wrapped out of the calls via,
+// from the one called to the one that wraps it, is wrapped, such as
+// " by store.Load, 2 calls down: api.Get returns the error of store.Load as
+// it is". It returns "" if via is empty.
+func trace(via []string) string {
+	switch len(via) {
+	case 0:
+		return ""
+	case 1:
+		return " by " + via[0]
+	}
+	steps := via[0] + " returns the error of " + via[1] + " as it is"
+	if len(via) > 2 {
+		steps = via[0] + " returns the error of " + via[1]
+		for i := 1; i < len(via)-1; i++ {
+			steps += ", and " + via[i] + " that of " + via[i+1]
+		}
+		steps += ", as they are"
+	}
+	return fmt.Sprintf(" by %s, %d calls down: %s", via[len(via)-1], len(via), steps)
+}
+
+// wrapsIt says that the functions by wrap a sentinel.
+func wrapsIt(by []string) string {
+	if len(by) == 1 {
+		return by[0] + " wraps it"
+	}
+	return strings.Join(by, " and ") + " wrap it"
+}
+
+// comparisonFix rewrites err == target to errors.Is(err, target), and
+// err != target to !errors.Is(err, target). If the comparison is part of a
+// condition negated as a whole, such as !(err == ErrA || err == ErrB), the
+// fix rewrites the negated condition instead, moving the negation inwards
+// by De Morgan's laws: !errors.Is(err, ErrA) && !errors.Is(err, ErrB). The
+// other comparisons of the condition that the check reports are rewritten
+// alike, so their fixes are the same, and nil checks are inverted.
+func (l *linter) comparisonFix(pass *analysis.Pass, expr *ast.BinaryExpr, stack []ast.Node) []analysis.SuggestedFix {
+	file := fileOf(pass, expr.Pos())
+	if file == nil {
+		return nil
+	}
+	name, edits := importErrors(pass, file)
+
+	var old ast.Expr = expr
+	var text string
+	if not, parent := negatedCondition(stack); not != nil {
+		c := conditionRewriter{l: l, pass: pass, errors: name}
+		var prec int
+		text, prec = c.negate(not.X)
+		if needsParens(not, parent, prec) {
+			text = "(" + text + ")"
+		}
+		old = not
+	} else {
+		text = isCall(pass, name, expr)
+	}
+
+	return []analysis.SuggestedFix{{
+		Message: "Use errors.Is",
+		TextEdits: append(edits, analysis.TextEdit{
+			Pos:     old.Pos(),
+			End:     old.End(),
+			NewText: []byte(text),
+		}),
+	}}
+}
+
+// isCall returns the call of errors.Is, known to file as name, that expr, a
+// comparison of errors with == or !=, becomes.
+func isCall(pass *analysis.Pass, name string, expr *ast.BinaryExpr) string {
+	err, target := expr.X, expr.Y
+	if _, ok := sentinelName(pass, err); ok {
+		if _, ok := sentinelName(pass, target); !ok {
+			err, target = target, err
+		}
+	}
+	call := fmt.Sprintf("%s.Is(%s, %s)", name, render(pass, err), render(pass, target))
+	if expr.Op == token.NEQ {
+		call = "!" + call
+	}
+	return call
+}
+
+// negatedCondition returns the outermost !x expression of the condition that
+// the last node of stack, a comparison, is part of, and the parent of that
+// expression. A condition is made of parentheses, !, && and ||. It returns
+// nil if the comparison is not negated as part of a condition.
+func negatedCondition(stack []ast.Node) (not *ast.UnaryExpr, parent ast.Node) {
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.ParenExpr:
+		case *ast.BinaryExpr:
+			if n.Op != token.LAND && n.Op != token.LOR {
+				return not, parent
+			}
+		case *ast.UnaryExpr:
+			if n.Op != token.NOT {
+				return not, parent
+			}
+			not, parent = n, stack[i-1]
+		default:
+			return not, parent
+		}
+	}
+	return not, parent
+}
+
+// needsParens reports whether an expression of precedence prec replacing
+// the operand x of parent must be parenthesized.
+func needsParens(x ast.Expr, parent ast.Node, prec int) bool {
+	switch p := parent.(type) {
+	case *ast.BinaryExpr:
+		// Binary operators are left-associative, so a right operand of the
+		// same precedence needs parentheses as well.
+		if p.Y == x {
+			return prec <= p.Op.Precedence()
+		}
+		return prec < p.Op.Precedence()
+	case *ast.UnaryExpr, *ast.StarExpr, *ast.SelectorExpr, *ast.IndexExpr, *ast.CallExpr, *ast.TypeAssertExpr:
+		return prec < token.UnaryPrec
+	}
+	return false
+}
+
+// conditionRewriter renders conditions with the error comparisons the check
+// reports rewritten to calls of errors.Is, known to the file as errors.
+type conditionRewriter struct {
+	l      *linter
+	pass   *analysis.Pass
+	errors string
+}
+
+// keep returns the source of expr, and the precedence of its operator.
+func (c conditionRewriter) keep(expr ast.Expr) (string, int) {
+	if b, ok := expr.(*ast.BinaryExpr); ok {
+		return render(c.pass, b), b.Op.Precedence()
+	}
+	return render(c.pass, expr), token.UnaryPrec
+}
+
+// rewrite returns expr with the comparisons the check reports rewritten,
+// and the precedence of its operator.
+func (c conditionRewriter) rewrite(expr ast.Expr) (string, int) {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.UnaryExpr:
+		if e.Op == token.NOT {
+			return c.negate(e.X)
+		}
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.LAND, token.LOR:
+			return c.join(c.rewrite, e.X, e.Op, e.Y), e.Op.Precedence()
+		case token.EQL, token.NEQ:
+			if _, ok := c.l.comparisonMessage(c.pass, e); ok {
+				return isCall(c.pass, c.errors, e), token.UnaryPrec
+			}
+		}
+	}
+	return c.keep(ast.Unparen(expr))
+}
+
+// negate returns the negation of expr, with the negation moved into &&
+// and || by De Morgan's laws and the comparisons the check reports
+// rewritten, and the precedence of its operator.
+func (c conditionRewriter) negate(expr ast.Expr) (string, int) {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.UnaryExpr:
+		if e.Op == token.NOT {
+			return c.rewrite(e.X)
+		}
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.LAND:
+			return c.join(c.negate, e.X, token.LOR, e.Y), token.LOR.Precedence()
+		case token.LOR:
+			return c.join(c.negate, e.X, token.LAND, e.Y), token.LAND.Precedence()
+		case token.EQL, token.NEQ:
+			// x != y is !(x == y) for values of every type, so == and !=
+			// are inverted, and comparisons the check reports rewritten.
+			inverted := *e
+			inverted.Op = token.NEQ
+			if e.Op == token.NEQ {
+				inverted.Op = token.EQL
+			}
+			if _, ok := c.l.comparisonMessage(c.pass, e); ok {
+				return isCall(c.pass, c.errors, &inverted), token.UnaryPrec
+			}
+			return render(c.pass, &inverted), token.EQL.Precedence()
+		}
+	}
+	text, prec := c.keep(ast.Unparen(expr))
+	if prec < token.UnaryPrec {
+		text = "(" + text + ")"
+	}
+	return "!" + text, token.UnaryPrec
+}
+
+// join returns x op y, with each operand rendered by f and parenthesized if
+// its operator binds less tightly than op.
+func (c conditionRewriter) join(f func(ast.Expr) (string, int), x ast.Expr, op token.Token, y ast.Expr) string {
+	operands := make([]string, 2)
+	for i, operand := range []ast.Expr{x, y} {
+		text, prec := f(operand)
+		if prec < op.Precedence() {
+			text = "(" + text + ")"
+		}
+		operands[i] = text
+	}
+	return operands[0] + " " + op.String() + " " + operands[1]
+}
+
+// isJoin reports whether expr is a call to errors.Join, whose result is a
+// new value that is never equal to any other error.
+func isJoin(pass *analysis.Pass, expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	return ok && isFunc(pass, call, "errors", "Join")
+}
+
+// isAllowedSentinel reports whether expr refers to an allowlisted sentinel.
+func (l *linter) isAllowedSentinel(pass *analysis.Pass, expr ast.Expr) bool {
+	name, ok := sentinelName(pass, expr)
+	return ok && l.allowed[name]
+}
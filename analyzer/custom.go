@@ -0,0 +1,259 @@
+package analyzer
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io"
+	"slices"
+	"strings"
+	"text/template"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// CustomCheck is a check defined in the configuration rather than in
+// errlint. It reports the calls of a function, or the comparisons with a
+// sentinel error, with a message of its own:
+//
+//	custom:
+//	  - name: legacywrap
+//	    call: example.com/app/errs.LegacyWrap
+//	    message: "{{.Expr}} is deprecated; wrap with fmt.Errorf and %w"
+//	  - name: errfoo
+//	    compare: example.com/app/store.ErrFoo
+//
+// Its findings carry its name as their category and end with it in
+// brackets, and suppression comments and severities refer to it by name.
+// Custom checks always run.
+type CustomCheck struct {
+	// Name identifies the check. It must not be the name or ID of a check
+	// of errlint.
+	Name string `json:"name" yaml:"name"`
+	// Call is the function, as "pkg/path.Name", or the method, as
+	// "pkg/path.Type.Method", whose calls the check reports.
+	Call string `json:"call,omitempty" yaml:"call,omitempty"`
+	// Compare is the sentinel error, as "pkg/path.Name", whose comparisons
+	// with == and !=, in switch cases and with errors.Is the check
+	// reports.
+	Compare string `json:"compare,omitempty" yaml:"compare,omitempty"`
+	// Message is the text/template of the message of the findings, in
+	// which {{.Expr}} is the reported call or comparison, such as
+	// "err == store.ErrFoo", and {{.Target}} is Call or Compare. If empty,
+	// the message says that the call or comparison is not allowed.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// Check describes c as the checks of errlint are described.
+func (c CustomCheck) Check() Check {
+	doc := "Custom: calls of " + c.Call
+	if c.Compare != "" {
+		doc = "Custom: comparisons with " + c.Compare
+	}
+	return Check{ID: c.Name, Name: c.Name, Doc: doc}
+}
+
+// ValidateCustom reports custom checks without a valid name, with neither
+// or both of a call and a comparison, with a malformed pattern or with a
+// message that is not a valid template.
+func ValidateCustom(defs []CustomCheck) error {
+	_, err := compileCustom(defs)
+	return err
+}
+
+// customData is the data the message template of a custom check is
+// executed with.
+type customData struct {
+	Expr   string
+	Target string
+}
+
+// customCheck is a compiled CustomCheck.
+type customCheck struct {
+	def     CustomCheck
+	message *template.Template
+}
+
+// compileCustom turns custom check definitions into checks that run after
+// those of errlint.
+func compileCustom(defs []CustomCheck) ([]check, error) {
+	var errs []error
+	var out []check
+	names := make(map[string]bool)
+	for _, def := range defs {
+		c, err := def.compile()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if names[def.Name] {
+			errs = append(errs, fmt.Errorf("custom check %s is defined twice", def.Name))
+			continue
+		}
+		names[def.Name] = true
+		out = append(out, check{
+			id:   def.Name,
+			name: def.Name,
+			doc:  def.Check().Doc,
+			run: func(_ *linter, pass *analysis.Pass, insp *inspector.Inspector) {
+				c.run(pass, insp)
+			},
+		})
+	}
+	return out, errors.Join(errs...)
+}
+
+func (def CustomCheck) compile() (*customCheck, error) {
+	switch _, builtin := findCheck(def.Name); {
+	case def.Name == "":
+		return nil, errors.New("custom check needs a name")
+	case strings.ContainsAny(def.Name, ", \t\n"):
+		return nil, fmt.Errorf("custom check name %q contains a comma or a space", def.Name)
+	case builtin:
+		return nil, fmt.Errorf("custom check name %q is already the name or ID of a check of errlint", def.Name)
+	case def.Call == "" && def.Compare == "":
+		return nil, fmt.Errorf("custom check %s needs a call or a compare pattern", def.Name)
+	case def.Call != "" && def.Compare != "":
+		return nil, fmt.Errorf("custom check %s has both a call and a compare pattern; define a check for each", def.Name)
+	}
+	if def.Call != "" && !qualifiedName(def.Call) {
+		return nil, fmt.Errorf("custom check %s: invalid call %q: want pkg/path.Name or pkg/path.Type.Method", def.Name, def.Call)
+	}
+	if def.Compare != "" && !qualifiedName(def.Compare) {
+		return nil, fmt.Errorf("custom check %s: invalid compare %q: want pkg/path.Name", def.Name, def.Compare)
+	}
+
+	text := def.Message
+	if text == "" {
+		text = "call of {{.Target}} is not allowed"
+		if def.Compare != "" {
+			text = "comparison with {{.Target}} is not allowed"
+		}
+	}
+	message, err := template.New(def.Name).Parse(text)
+	if err == nil {
+		err = message.Execute(io.Discard, customData{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("custom check %s: invalid message: %w", def.Name, err)
+	}
+	return &customCheck{def: def, message: message}, nil
+}
+
+// qualifiedName reports whether name has the form pkg/path.Name, with a
+// dot after the last slash that neither starts nor ends what follows it.
+func qualifiedName(name string) bool {
+	slash := strings.LastIndexByte(name, '/')
+	dot := strings.IndexByte(name[slash+1:], '.')
+	return dot > 0 && !strings.HasSuffix(name, ".")
+}
+
+func (c *customCheck) run(pass *analysis.Pass, insp *inspector.Inspector) {
+	if c.def.Call != "" {
+		insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+			call := n.(*ast.CallExpr)
+			fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+			if ok && fn.Pkg() != nil && fn.Pkg().Path()+"."+memberName(fn) == c.def.Call {
+				c.report(pass, call, render(pass, call))
+			}
+		})
+		return
+	}
+
+	target := func(expr ast.Expr) bool {
+		name, ok := sentinelName(pass, expr)
+		return ok && name == c.def.Compare
+	}
+	insp.Preorder([]ast.Node{(*ast.BinaryExpr)(nil), (*ast.CallExpr)(nil), (*ast.SwitchStmt)(nil)}, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.BinaryExpr:
+			if (n.Op == token.EQL || n.Op == token.NEQ) && (target(n.X) || target(n.Y)) {
+				c.report(pass, n, render(pass, n))
+			}
+		case *ast.CallExpr:
+			if len(n.Args) == 2 && isFunc(pass, n, "errors", "Is") && target(n.Args[1]) {
+				c.report(pass, n, render(pass, n))
+			}
+		case *ast.SwitchStmt:
+			if n.Tag == nil {
+				return
+			}
+			for _, stmt := range n.Body.List {
+				for _, expr := range stmt.(*ast.CaseClause).List {
+					if target(expr) {
+						// A case reads as the comparison it makes.
+						c.report(pass, expr, render(pass, n.Tag)+" == "+render(pass, expr))
+					}
+				}
+			}
+		}
+	})
+}
+
+// report reports node with the message of the check, for the call or
+// comparison expr.
+func (c *customCheck) report(pass *analysis.Pass, node ast.Node, expr string) {
+	var message strings.Builder
+	if err := c.message.Execute(&message, customData{Expr: expr, Target: cmp.Or(c.def.Call, c.def.Compare)}); err != nil {
+		message.Reset()
+		fmt.Fprintf(&message, "%s: %v", expr, err)
+	}
+	pass.Report(analysis.Diagnostic{Pos: node.Pos(), End: node.End(), Message: message.String()})
+}
+
+// customChecks holds the custom checks of a linter. It implements
+// flag.Getter, with the definitions of the checks as a JSON array.
+type customChecks struct {
+	defs   []CustomCheck
+	checks []check
+}
+
+func (c *customChecks) String() string {
+	if c == nil || len(c.defs) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(c.defs)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// Set replaces the checks with those defined by the JSON array value.
+func (c *customChecks) Set(value string) error {
+	var defs []CustomCheck
+	if value != "" {
+		if err := json.Unmarshal([]byte(value), &defs); err != nil {
+			return fmt.Errorf("invalid custom checks: %w", err)
+		}
+	}
+	return c.set(defs)
+}
+
+func (c *customChecks) set(defs []CustomCheck) error {
+	checks, err := compileCustom(defs)
+	if err != nil {
+		return err
+	}
+	c.defs, c.checks = slices.Clone(defs), checks
+	return nil
+}
+
+// Get returns the definitions of the checks, as a []CustomCheck.
+func (c *customChecks) Get() any {
+	return slices.Clone(c.defs)
+}
+
+// find returns the custom check with the given name.
+func (c *customChecks) find(name string) (check, bool) {
+	if i := slices.IndexFunc(c.checks, func(c check) bool { return c.name == name }); i >= 0 {
+		return c.checks[i], true
+	}
+	return check{}, false
+}
@@ -0,0 +1,278 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// checkDeferWraps reports deferred functions that wrap an error, as in
+//
+//	defer func() {
+//		if err != nil {
+//			err = fmt.Errorf("saving %s: %w", name, err)
+//		}
+//	}()
+//
+// in ways that do not change what the function returns: wrapping a
+// variable other than its error result, such as an err that shadows the
+// named result or an err of a function whose results have no names, and
+// wrapping the named result with fmt.Errorf without checking that it is
+// not nil, which makes the function fail when it succeeds.
+func (l *linter) checkDeferWraps(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.WithStack([]ast.Node{(*ast.DeferStmt)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		lit, ok := ast.Unparen(n.(*ast.DeferStmt).Call.Fun).(*ast.FuncLit)
+		if !ok {
+			return true
+		}
+		fn, results := enclosingFunc(pass, stack[:len(stack)-1])
+		if fn == nil {
+			return true
+		}
+		var errResults []*types.Var
+		for v := range results.Variables() {
+			if types.IsInterface(v.Type()) && types.Implements(v.Type(), errorIface) {
+				errResults = append(errResults, v)
+			}
+		}
+		if len(errResults) == 0 {
+			return true
+		}
+
+		var path []ast.Node
+		ast.Inspect(lit.Body, func(n ast.Node) bool {
+			if n == nil {
+				path = path[:len(path)-1]
+				return true
+			}
+			if _, ok := n.(*ast.FuncLit); ok {
+				// Nested functions do not run when the deferred one does.
+				return false
+			}
+			path = append(path, n)
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok {
+				return true
+			}
+			v, call := wrapAssignment(pass, assign)
+			if v == nil {
+				return true
+			}
+			switch {
+			case isResult(v, errResults):
+				if isFunc(pass, call, "fmt", "Errorf") && !joinsErrors(pass, call, v) && !nilChecked(pass, v, path) {
+					pass.Report(analysis.Diagnostic{
+						Pos:            assign.Pos(),
+						End:            assign.End(),
+						Message:        fmt.Sprintf("deferred fmt.Errorf wraps %s even when it is nil, so the function fails when it succeeds; check %s != nil first", v.Name(), v.Name()),
+						SuggestedFixes: nilCheckFix(pass, assign, v.Name()),
+					})
+				}
+			case v.Parent() == pass.Pkg.Scope() || v.Pos() < fn.Pos() || v.Pos() >= fn.End():
+				// A package-level or outer variable outlives the function,
+				// so wrapping it may be intended.
+			case v.Pos() >= lit.Pos() && v.Pos() < lit.End() && usedAfter(pass, v, assign, lit.Body):
+				// A variable of the deferred function itself that is used
+				// again after it is wrapped.
+			default:
+				pass.Reportf(assign.Lhs[0].Pos(), "deferred function %s; the returned error is not wrapped", lostWrap(pass, v, errResults))
+			}
+			return true
+		})
+		return true
+	})
+}
+
+// enclosingFunc returns the innermost function declaration or literal in
+// stack and its results.
+func enclosingFunc(pass *analysis.Pass, stack []ast.Node) (ast.Node, *types.Tuple) {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.FuncLit:
+			sig, ok := pass.TypesInfo.TypeOf(n).(*types.Signature)
+			if !ok {
+				return nil, nil
+			}
+			return n, sig.Results()
+		case *ast.FuncDecl:
+			fn, ok := pass.TypesInfo.Defs[n.Name].(*types.Func)
+			if !ok {
+				return nil, nil
+			}
+			return n, fn.Signature().Results()
+		}
+	}
+	return nil, nil
+}
+
+// wrapAssignment returns the error variable of an assignment v = f(..., v,
+// ...), which wraps the error v holds, and the call.
+func wrapAssignment(pass *analysis.Pass, assign *ast.AssignStmt) (*types.Var, *ast.CallExpr) {
+	if assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return nil, nil
+	}
+	id, ok := ast.Unparen(assign.Lhs[0]).(*ast.Ident)
+	if !ok {
+		return nil, nil
+	}
+	v, ok := pass.TypesInfo.Uses[id].(*types.Var)
+	if !ok || !types.IsInterface(v.Type()) || !types.Implements(v.Type(), errorIface) {
+		return nil, nil
+	}
+	call, ok := ast.Unparen(assign.Rhs[0]).(*ast.CallExpr)
+	if !ok {
+		return nil, nil
+	}
+	for _, arg := range call.Args {
+		if id, ok := ast.Unparen(arg).(*ast.Ident); ok && pass.TypesInfo.Uses[id] == v {
+			return v, call
+		}
+	}
+	return nil, nil
+}
+
+func isResult(v *types.Var, results []*types.Var) bool {
+	for _, r := range results {
+		if r == v {
+			return true
+		}
+	}
+	return false
+}
+
+// joinsErrors reports whether call has an error argument other than v, such
+// as a second error that makes the function fail on its own.
+func joinsErrors(pass *analysis.Pass, call *ast.CallExpr, v *types.Var) bool {
+	for _, arg := range call.Args {
+		if id, ok := ast.Unparen(arg).(*ast.Ident); ok && pass.TypesInfo.Uses[id] == v {
+			continue
+		}
+		if t := pass.TypesInfo.TypeOf(arg); t != nil && types.Implements(t, errorIface) {
+			return true
+		}
+	}
+	return false
+}
+
+// nilChecked reports whether the last node of path only runs if v is not
+// nil: it is inside an if statement whose condition checks v != nil or that
+// matches v with errors.Is or errors.As, which never match nil, or follows
+// one that returns if v == nil.
+func nilChecked(pass *analysis.Pass, v *types.Var, path []ast.Node) bool {
+	for i := len(path) - 2; i >= 0; i-- {
+		switch n := path[i].(type) {
+		case *ast.IfStmt:
+			if path[i+1] == n.Body && (comparesNil(pass, n.Cond, v, token.NEQ) || matches(pass, n.Cond, v) || n.Init != nil && matches(pass, n.Init, v)) {
+				return true
+			}
+		case *ast.BlockStmt:
+			for _, stmt := range n.List {
+				if stmt == path[i+1] {
+					break
+				}
+				if ifStmt, ok := stmt.(*ast.IfStmt); ok && comparesNil(pass, ifStmt.Cond, v, token.EQL) && len(ifStmt.Body.List) > 0 {
+					if _, ok := ifStmt.Body.List[len(ifStmt.Body.List)-1].(*ast.ReturnStmt); ok {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// comparesNil reports whether cond contains the comparison v op nil.
+func comparesNil(pass *analysis.Pass, cond ast.Expr, v *types.Var, op token.Token) bool {
+	found := false
+	ast.Inspect(cond, func(n ast.Node) bool {
+		bin, ok := n.(*ast.BinaryExpr)
+		if !ok || bin.Op != op {
+			return !found
+		}
+		for _, pair := range [][2]ast.Expr{{bin.X, bin.Y}, {bin.Y, bin.X}} {
+			if id, ok := ast.Unparen(pair[0]).(*ast.Ident); ok && pass.TypesInfo.Uses[id] == v && isNil(pass, pair[1]) {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// matches reports whether n contains a call of errors.Is, errors.As or
+// errors.AsType with v as the error.
+func matches(pass *analysis.Pass, n ast.Node, v *types.Var) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if ok && len(call.Args) > 0 && (isFunc(pass, call, "errors", "Is") || isFunc(pass, call, "errors", "As") || isFunc(pass, call, "errors", "AsType")) {
+			if id, ok := ast.Unparen(call.Args[0]).(*ast.Ident); ok && pass.TypesInfo.Uses[id] == v {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// usedAfter reports whether v is used in body after stmt.
+func usedAfter(pass *analysis.Pass, v *types.Var, stmt ast.Stmt, body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && id.Pos() >= stmt.End() && pass.TypesInfo.Uses[id] == v {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// lostWrap describes the variable v that a deferred function wraps instead
+// of the error results of the function.
+func lostWrap(pass *analysis.Pass, v *types.Var, results []*types.Var) string {
+	for _, r := range results {
+		if r.Name() == v.Name() {
+			return fmt.Sprintf("wraps %s declared at line %d, which shadows the named result %s", v.Name(), pass.Fset.Position(v.Pos()).Line, r.Name())
+		}
+	}
+	for _, r := range results {
+		if r.Name() != "" && r.Name() != "_" {
+			return fmt.Sprintf("wraps %s, not the error result %s", v.Name(), r.Name())
+		}
+	}
+	return fmt.Sprintf("wraps %s, which the function does not return; name the error result, as in (%s error), to change it", v.Name(), v.Name())
+}
+
+// nilCheckFix puts the assignment, which wraps name, in an if statement
+// checking that name is not nil.
+func nilCheckFix(pass *analysis.Pass, assign *ast.AssignStmt, name string) []analysis.SuggestedFix {
+	text, ok := source(pass, assign.Pos(), assign.End())
+	if !ok {
+		return nil
+	}
+	indent := indentation(pass, assign.Pos())
+	tf := pass.Fset.File(assign.Pos())
+	before, _ := source(pass, tf.LineStart(tf.Line(assign.Pos())), assign.Pos())
+	var fixed string
+	if strings.TrimSpace(before) == "" {
+		fixed = fmt.Sprintf("if %s != nil {\n%s\t%s\n%s}", name, indent, text, indent)
+	} else {
+		fixed = fmt.Sprintf("if %s != nil { %s }", name, text)
+	}
+	return []analysis.SuggestedFix{{
+		Message: fmt.Sprintf("Only wrap %s if it is not nil", name),
+		TextEdits: []analysis.TextEdit{{
+			Pos:     assign.Pos(),
+			End:     assign.End(),
+			NewText: []byte(fixed),
+		}},
+	}}
+}
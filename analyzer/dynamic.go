@@ -0,0 +1,77 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer/internal/verbs"
+)
+
+// checkDynamic reports errors created with errors.New, or with fmt.Errorf
+// without %w, that are returned or compared directly. Every call creates a
+// new error, so callers cannot match it with errors.Is, and a comparison
+// with it never matches.
+func (l *linter) checkDynamic(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		call := n.(*ast.CallExpr)
+		name, ok := "errors.New", isFunc(pass, call, "errors", "New")
+		if !ok && isFunc(pass, call, "fmt", "Errorf") && !wraps(pass, call) {
+			name, ok = "fmt.Errorf without %w", true
+		}
+		if !ok {
+			return true
+		}
+
+		// Find the node the result is used by, looking through parentheses.
+		i := len(stack) - 2
+		for i >= 0 {
+			if _, ok := stack[i].(*ast.ParenExpr); !ok {
+				break
+			}
+			i--
+		}
+		if i < 0 {
+			return true
+		}
+		// Comparisons with new errors are reported by the newcompare check
+		// if it runs.
+		compared := !l.runs("newcompare")
+		switch parent := stack[i].(type) {
+		case *ast.ReturnStmt:
+			msg := "%s creates a new error on every call, which callers cannot match with errors.Is; return a package-level sentinel"
+			if name != "errors.New" {
+				msg = "%s creates a new error on every call, which callers cannot match with errors.Is; wrap a package-level sentinel with %%w"
+			}
+			pass.Reportf(call.Pos(), msg, name)
+		case *ast.BinaryExpr:
+			if compared && (parent.Op == token.EQL || parent.Op == token.NEQ) {
+				pass.Reportf(call.Pos(), "comparing with a new error from %s never matches; compare with a package-level sentinel", name)
+			}
+		case *ast.CallExpr:
+			if compared && len(parent.Args) == 2 && parent.Args[1] == stack[i+1] && isFunc(pass, parent, "errors", "Is") {
+				pass.Reportf(call.Pos(), "errors.Is with a new error from %s never matches; compare with a package-level sentinel", name)
+			}
+		}
+		return true
+	})
+}
+
+// wraps reports whether the fmt.Errorf call has a %w verb. Calls whose
+// format or operands are only known at run time are assumed to wrap.
+func wraps(pass *analysis.Pass, call *ast.CallExpr) bool {
+	if len(call.Args) == 0 || call.Ellipsis.IsValid() {
+		return true
+	}
+	format, ok := constantString(pass, call.Args[0])
+	if !ok {
+		return true
+	}
+	return slices.ContainsFunc(verbs.Parse(format, len(call.Args)-1), func(v verbs.Verb) bool { return v.Verb == 'w' })
+}
@@ -0,0 +1,239 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"go/version"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer/internal/verbs"
+)
+
+// checkErrorf reports error arguments of fmt.Errorf that are formatted with
+// %v or %s instead of %w, which discards the wrapped error. It also verifies
+// that every %w verb has an error argument, that no error argument is left
+// without a verb, and that multiple %w verbs are only used from Go 1.20 on.
+// If the format takes more or fewer arguments than the call passes, the
+// verbs and the arguments no longer line up, so it reports the mismatch
+// instead of the verbs of the arguments that shifted.
+func (l *linter) checkErrorf(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		// With args... the operands, and so the verbs they match, are
+		// only known at run time.
+		if !isFunc(pass, call, "fmt", "Errorf") || len(call.Args) == 0 || call.Ellipsis.IsValid() {
+			return
+		}
+		format, ok := constantString(pass, call.Args[0])
+		if !ok {
+			return
+		}
+
+		args := call.Args[1:]
+		vs := verbs.Parse(format, len(args))
+		// A mismatch without errors is left to the printf check of go vet.
+		mismatch := ""
+		if slices.ContainsFunc(vs, func(v verbs.Verb) bool { return v.Verb == 'w' }) || slices.ContainsFunc(args, func(arg ast.Expr) bool {
+			return isError(pass, arg) && !isNil(pass, arg)
+		}) {
+			mismatch = countMismatch(format, len(args))
+		}
+		used := make([]bool, len(args))
+		wraps := 0
+		var unwrapped, nonErrors []verbs.Verb
+		for _, v := range vs {
+			if v.Arg < 0 || v.Arg >= len(args) {
+				switch {
+				case v.Verb == 'w':
+					pass.Reportf(call.Args[0].Pos(), "%%w verb in fmt.Errorf has no matching argument%s", mismatch)
+				case mismatch != "":
+					pass.Reportf(call.Args[0].Pos(), "%%%c verb in fmt.Errorf has no matching argument%s", v.Verb, mismatch)
+				}
+				continue
+			}
+			used[v.Arg] = true
+
+			arg := args[v.Arg]
+			switch {
+			case v.Verb == 'w' && !isError(pass, arg) && !isNil(pass, arg) && !types.IsInterface(pass.TypesInfo.TypeOf(arg)):
+				// fmt formats it as %!w(...) and the result unwraps to nil.
+				// An interface value, such as an any, may still hold an error.
+				nonErrors = append(nonErrors, v)
+			case v.Verb == 'w':
+				wraps++
+			case v.Verb != 'v' && v.Verb != 's':
+			case isError(pass, arg) && !isNil(pass, arg):
+				unwrapped = append(unwrapped, v)
+			}
+		}
+		if mismatch != "" {
+			// The arguments the verbs format are not the ones they were
+			// meant for, so neither the reports nor the fixes of their
+			// verbs would help.
+			unwrapped, nonErrors = nil, nil
+		}
+
+		multiWrap := supportsMultiWrap(pass, call)
+		if wraps > 1 && !multiWrap {
+			pass.Reportf(call.Pos(), "fmt.Errorf with multiple %%w verbs requires go1.20 or later; this file is built with %s", goVersion(pass, call))
+		}
+
+		// The verbs the fixes rewrite, which a fix rewriting the whole
+		// literal must all agree on.
+		fixUnwrapped := multiWrap || wraps == 0 && len(unwrapped) == 1
+		rewrites := nonErrors
+		if fixUnwrapped {
+			rewrites = append(rewrites, unwrapped...)
+		}
+
+		for _, v := range unwrapped {
+			var fixes []analysis.SuggestedFix
+			if fixUnwrapped {
+				fixes = errorfFix(call.Args[0], format, v, rewrites)
+			}
+			arg := args[v.Arg]
+			pass.Report(analysis.Diagnostic{
+				Pos:            arg.Pos(),
+				End:            arg.End(),
+				Message:        fmt.Sprintf("error formatted with %%%c in fmt.Errorf is not wrapped; use %%w", v.Verb),
+				SuggestedFixes: fixes,
+			})
+		}
+
+		for _, v := range nonErrors {
+			arg := args[v.Arg]
+			pass.Report(analysis.Diagnostic{
+				Pos:            arg.Pos(),
+				End:            arg.End(),
+				Message:        fmt.Sprintf("%%w verb in fmt.Errorf has an argument of type %s, which is not an error; use %%v", types.TypeString(types.Default(pass.TypesInfo.TypeOf(arg)), types.RelativeTo(pass.Pkg))),
+				SuggestedFixes: errorfFix(call.Args[0], format, v, rewrites),
+			})
+		}
+
+		for i, arg := range args {
+			if !used[i] && isError(pass, arg) && !isNil(pass, arg) {
+				extra := ""
+				if mismatch != "" {
+					extra = mismatch + ", so fmt appends it to the message as %!(EXTRA ...)"
+				}
+				pass.Reportf(arg.Pos(), "error argument of fmt.Errorf has no verb and is not wrapped%s", extra)
+			}
+		}
+	})
+}
+
+// countMismatch describes how the number of arguments the format takes
+// differs from nargs, the number the call passes, as a clause such as
+// ": the format takes 2 arguments but the call passes 1", or returns "" if
+// they are the same. A '*' width or precision takes an argument too. Formats
+// with explicit argument indexes, such as %[2]v, may skip arguments on
+// purpose, and fmt does not report the extra ones, so it returns "" for
+// them; their bad indexes are reported as missing arguments.
+func countMismatch(format string, nargs int) string {
+	takes := 0
+	for _, v := range verbs.Parse(format, math.MaxInt) {
+		if strings.Contains(format[v.Start:v.End], "[") {
+			return ""
+		}
+		takes = max(takes, v.Arg+1)
+	}
+	if takes == nargs {
+		return ""
+	}
+	switch takes {
+	case 0:
+		return fmt.Sprintf(": the format takes no arguments but the call passes %d", nargs)
+	case 1:
+		return fmt.Sprintf(": the format takes 1 argument but the call passes %d", nargs)
+	}
+	return fmt.Sprintf(": the format takes %d arguments but the call passes %d", takes, nargs)
+}
+
+// supportsMultiWrap reports whether the file containing node is built with
+// a Go version that allows several %w verbs in one fmt.Errorf call.
+func supportsMultiWrap(pass *analysis.Pass, node ast.Node) bool {
+	v := goVersion(pass, node)
+	return v == "" || version.Compare(v, "go1.20") >= 0
+}
+
+// goVersion returns the Go version, such as "go1.21", that the file
+// containing node is built with, or "" if it is unknown.
+func goVersion(pass *analysis.Pass, node ast.Node) string {
+	if file := fileOf(pass, node.Pos()); file != nil {
+		if v := pass.TypesInfo.FileVersions[file]; v != "" {
+			return v
+		}
+	}
+	return pass.Pkg.GoVersion()
+}
+
+// errorfFix replaces the verb v in the format literal with %w, or a %w verb
+// with %v. If the literal contains escape sequences, its source offsets do
+// not match the format string, so the whole literal is rewritten with every
+// verb in rewrites replaced; the fixes of all diagnostics for the call then
+// agree.
+func errorfFix(expr ast.Expr, format string, v verbs.Verb, rewrites []verbs.Verb) []analysis.SuggestedFix {
+	lit, ok := ast.Unparen(expr).(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return nil
+	}
+
+	edit := analysis.TextEdit{
+		Pos:     lit.Pos() + token.Pos(v.End),
+		End:     lit.Pos() + token.Pos(v.End) + 1,
+		NewText: []byte{rewritten(v)},
+	}
+	if lit.Value[0] == '"' && strings.ContainsRune(lit.Value, '\\') {
+		b := []byte(format)
+		for _, u := range rewrites {
+			b[u.End-1] = rewritten(u)
+		}
+		edit = analysis.TextEdit{
+			Pos:     lit.Pos(),
+			End:     lit.End(),
+			NewText: []byte(strconv.Quote(string(b))),
+		}
+	}
+
+	msg := "Use %w to wrap the error"
+	if v.Verb == 'w' {
+		msg = "Use %v to format the argument"
+	}
+	return []analysis.SuggestedFix{{
+		Message:   msg,
+		TextEdits: []analysis.TextEdit{edit},
+	}}
+}
+
+// rewritten returns the verb the fix of the errorf check replaces v with.
+func rewritten(v verbs.Verb) byte {
+	if v.Verb == 'w' {
+		return 'v'
+	}
+	return 'w'
+}
+
+// isFunc reports whether call invokes the package-level function pkg.name.
+func isFunc(pass *analysis.Pass, call *ast.CallExpr, pkg, name string) bool {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == pkg && fn.Name() == name
+}
+
+// constantString returns the value of expr if it is a constant string.
+func constantString(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
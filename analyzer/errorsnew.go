@@ -0,0 +1,146 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer/internal/verbs"
+)
+
+// checkErrorsNew reports fmt.Errorf calls with a constant format without
+// verbs and no other arguments, which create the same error as errors.New
+// with more work and suggest formatting that does not happen.
+func (l *linter) checkErrorsNew(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		msg, ok := plainErrorf(pass, call)
+		if !ok {
+			return
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:            call.Pos(),
+			End:            call.End(),
+			Message:        "fmt.Errorf without verbs or arguments; use errors.New",
+			SuggestedFixes: l.errorsNewFix(pass, call, msg),
+		})
+	})
+}
+
+// plainErrorf reports whether call is fmt.Errorf with only a constant
+// format without verbs, and returns the message of the error it creates.
+func plainErrorf(pass *analysis.Pass, call *ast.CallExpr) (string, bool) {
+	if !isFunc(pass, call, "fmt", "Errorf") || len(call.Args) != 1 || call.Ellipsis.IsValid() {
+		return "", false
+	}
+	format, ok := constantString(pass, call.Args[0])
+	if !ok || len(verbs.Parse(format, 0)) > 0 {
+		return "", false
+	}
+	// A lone %, as in "50%", is formatted as %!(NOVERB), which errors.New
+	// would not reproduce.
+	if strings.Contains(strings.ReplaceAll(format, "%%", ""), "%") {
+		return "", false
+	}
+	return strings.ReplaceAll(format, "%%", "%"), true
+}
+
+// errorsNewFix rewrites fmt.Errorf(format) to errors.New(msg). It imports
+// errors if needed, and removes the fmt import if the file has no other use
+// for it once every such call is rewritten.
+func (l *linter) errorsNewFix(pass *analysis.Pass, call *ast.CallExpr, msg string) []analysis.SuggestedFix {
+	file := fileOf(pass, call.Pos())
+	fmtName := l.rewritable(pass, call)
+	if file == nil || fmtName == nil {
+		return nil
+	}
+
+	var edits []analysis.TextEdit
+	name := "errors"
+	if decl, spec := importSpec(pass, file, fmtName); spec != nil && !l.usesPackage(pass, file, fmtName) {
+		// Replace the fmt import with errors, or drop it if errors is
+		// already imported.
+		var imports []analysis.TextEdit
+		name, imports = importErrors(pass, file)
+		text := ""
+		if len(imports) > 0 {
+			name, text = "errors", `"errors"`
+		}
+		edits = append(edits, replaceImport(pass, decl, spec, text))
+	} else {
+		var imports []analysis.TextEdit
+		name, imports = importErrors(pass, file)
+		edits = append(edits, imports...)
+	}
+
+	edits = append(edits, analysis.TextEdit{Pos: call.Fun.Pos(), End: call.Fun.End(), NewText: []byte(name + ".New")})
+	if lit, ok := ast.Unparen(call.Args[0]).(*ast.BasicLit); ok && strings.Contains(lit.Value, "%%") {
+		edits = append(edits, analysis.TextEdit{Pos: lit.Pos(), End: lit.End(), NewText: []byte(strconv.Quote(msg))})
+	}
+
+	return []analysis.SuggestedFix{{
+		Message:   "Use errors.New",
+		TextEdits: edits,
+	}}
+}
+
+// rewritable returns the package name the fmt.Errorf call refers to fmt
+// by if the fix of the errorsnew check rewrites it, or nil.
+func (l *linter) rewritable(pass *analysis.Pass, call *ast.CallExpr) *types.PkgName {
+	msg, ok := plainErrorf(pass, call)
+	if !ok || l.ignores.find(pass.Fset, call.Pos(), "errorsnew") != nil {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	id, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	// A named constant with %% cannot be passed to errors.New as is.
+	if _, ok := ast.Unparen(call.Args[0]).(*ast.BasicLit); !ok && strings.Contains(msg, "%") {
+		return nil
+	}
+	pkg, _ := pass.TypesInfo.Uses[id].(*types.PkgName)
+	return pkg
+}
+
+// importSpec returns the import of file that declares pkg, and the
+// declaration it is part of.
+func importSpec(pass *analysis.Pass, file *ast.File, pkg *types.PkgName) (*ast.GenDecl, *ast.ImportSpec) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		for _, s := range gen.Specs {
+			spec := s.(*ast.ImportSpec)
+			obj := pass.TypesInfo.Implicits[spec]
+			if spec.Name != nil {
+				obj = pass.TypesInfo.Defs[spec.Name]
+			}
+			if obj == pkg {
+				return gen, spec
+			}
+		}
+	}
+	return nil, nil
+}
+
+// usesPackage reports whether file refers to pkg other than in the calls
+// the errorsnew check rewrites.
+func (l *linter) usesPackage(pass *analysis.Pass, file *ast.File, pkg *types.PkgName) bool {
+	return refersTo(pass, file, pkg, func(call *ast.CallExpr) bool { return l.rewritable(pass, call) == pkg })
+}
+
+func isUse(pass *analysis.Pass, n ast.Node, pkg *types.PkgName) bool {
+	id, ok := n.(*ast.Ident)
+	return ok && pass.TypesInfo.Uses[id] == pkg
+}
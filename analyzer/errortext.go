@@ -0,0 +1,157 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer/internal/verbs"
+)
+
+// checkErrorText reports the message of an error, err.Error(), passed to
+// fmt.Errorf under a %s or %v verb or concatenated into its format, which
+// keeps the text of the error but drops the error from the chain. The
+// fixes pass the error itself under %w instead.
+func (l *linter) checkErrorText(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		if !isFunc(pass, call, "fmt", "Errorf") || len(call.Args) == 0 || call.Ellipsis.IsValid() {
+			return
+		}
+		if format, ok := constantString(pass, call.Args[0]); ok {
+			checkFormattedText(pass, call, format)
+			return
+		}
+		checkConcatenatedText(pass, call)
+	})
+}
+
+// checkFormattedText reports the messages of errors formatted with %s or
+// %v by the fmt.Errorf call with the constant format.
+func checkFormattedText(pass *analysis.Pass, call *ast.CallExpr, format string) {
+	args := call.Args[1:]
+	// wraps counts the verbs that wrap an error, or that the fix of the
+	// errorf check makes wrap one.
+	wraps := 0
+	var texts []verbs.Verb
+	for _, v := range verbs.Parse(format, len(args)) {
+		if v.Arg < 0 || v.Arg >= len(args) {
+			continue
+		}
+		arg := args[v.Arg]
+		switch {
+		case v.Verb == 'w':
+			wraps++
+		case v.Verb != 'v' && v.Verb != 's':
+		case isError(pass, arg) && !isNil(pass, arg):
+			wraps++
+		default:
+			if _, ok := errorMessage(pass, arg); ok {
+				texts = append(texts, v)
+			}
+		}
+	}
+
+	fixable := supportsMultiWrap(pass, call) || wraps == 0 && len(texts) == 1
+	for _, v := range texts {
+		arg := args[v.Arg]
+		err, _ := errorMessage(pass, arg)
+		var fixes []analysis.SuggestedFix
+		if fixable {
+			fixes = errorfFix(call.Args[0], format, v, texts)
+		}
+		if len(fixes) > 0 {
+			fixes[0].Message = "Wrap the error with %w"
+			fixes[0].TextEdits = append(fixes[0].TextEdits, analysis.TextEdit{
+				Pos:     arg.Pos(),
+				End:     arg.End(),
+				NewText: []byte(render(pass, err)),
+			})
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:            arg.Pos(),
+			End:            arg.End(),
+			Message:        fmt.Sprintf("%s formatted with %%%c in fmt.Errorf drops the error from the chain; wrap %s with %%w", render(pass, arg), v.Verb, render(pass, err)),
+			SuggestedFixes: fixes,
+		})
+	}
+}
+
+// checkConcatenatedText reports the messages of errors concatenated into
+// the format of the fmt.Errorf call.
+func checkConcatenatedText(pass *analysis.Pass, call *ast.CallExpr) {
+	operands := concatenation(call.Args[0])
+	var texts []ast.Expr
+	for _, op := range operands {
+		if _, ok := errorMessage(pass, op); ok {
+			texts = append(texts, op)
+		}
+	}
+	if len(texts) == 0 {
+		return
+	}
+
+	var fixes []analysis.SuggestedFix
+	// Further arguments belong to verbs in the constant parts, which the
+	// fix would have to renumber.
+	if len(call.Args) == 1 && (len(texts) == 1 || supportsMultiWrap(pass, call)) {
+		fixes = concatenationFix(pass, call.Args[0], operands)
+	}
+	for _, op := range texts {
+		err, _ := errorMessage(pass, op)
+		pass.Report(analysis.Diagnostic{
+			Pos:            op.Pos(),
+			End:            op.End(),
+			Message:        fmt.Sprintf("%s concatenated into the format of fmt.Errorf drops the error from the chain; wrap %s with %%w", render(pass, op), render(pass, err)),
+			SuggestedFixes: fixes,
+		})
+	}
+}
+
+// concatenation returns the operands of the string concatenation expr, or
+// expr itself if it is not one.
+func concatenation(expr ast.Expr) []ast.Expr {
+	bin, ok := ast.Unparen(expr).(*ast.BinaryExpr)
+	if !ok || bin.Op != token.ADD {
+		return []ast.Expr{expr}
+	}
+	return append(concatenation(bin.X), concatenation(bin.Y)...)
+}
+
+// concatenationFix rewrites the format concatenated from operands into a
+// constant format, with %w for the messages of errors, which are passed as
+// the errors themselves, and %s for the other strings. Percent signs in the
+// constant parts are escaped, since they were not meant as verbs.
+func concatenationFix(pass *analysis.Pass, format ast.Expr, operands []ast.Expr) []analysis.SuggestedFix {
+	var (
+		b    strings.Builder
+		args []string
+	)
+	for _, op := range operands {
+		if tv, ok := pass.TypesInfo.Types[op]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+			b.WriteString(strings.ReplaceAll(constant.StringVal(tv.Value), "%", "%%"))
+			continue
+		}
+		if err, ok := errorMessage(pass, op); ok {
+			b.WriteString("%w")
+			args = append(args, render(pass, err))
+			continue
+		}
+		b.WriteString("%s")
+		args = append(args, render(pass, op))
+	}
+	return []analysis.SuggestedFix{{
+		Message: "Wrap the error with %w",
+		TextEdits: []analysis.TextEdit{{
+			Pos:     format.Pos(),
+			End:     format.End(),
+			NewText: []byte(strings.Join(append([]string{strconv.Quote(b.String())}, args...), ", ")),
+		}},
+	}}
+}
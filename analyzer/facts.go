@@ -0,0 +1,1059 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer/internal/verbs"
+)
+
+// unwrappedDirective documents that the sentinels of a package are
+// returned as they are, so callers may compare them with ==, as io.EOF
+// is. It applies to the variables whose declaration it documents, or to
+// every sentinel of the package in the package comment:
+//
+//	// ErrNil is returned when a key does not exist.
+//	//
+//	//errlint:unwrapped
+//	var ErrNil = errors.New("nil")
+const unwrappedDirective = "//errlint:unwrapped"
+
+// sentinelFact marks a package-level error variable as a sentinel, so that
+// packages comparing against it know how its own package returns it.
+type sentinelFact struct {
+	// WrappedBy lists the functions of the sentinel's package that return
+	// it wrapped, such as "store.Load" or "store.DB.Get".
+	WrappedBy []string
+	// Unwrapped is set if the sentinel is documented to be returned as it
+	// is with an unwrappedDirective.
+	Unwrapped bool
+}
+
+func (*sentinelFact) AFact() {}
+
+func (f *sentinelFact) String() string {
+	switch {
+	case f.Unwrapped && len(f.WrappedBy) > 0:
+		return "sentinel documented unwrapped, wrapped by " + strings.Join(f.WrappedBy, ", ")
+	case f.Unwrapped:
+		return "sentinel returned unwrapped"
+	case len(f.WrappedBy) > 0:
+		return "sentinel wrapped by " + strings.Join(f.WrappedBy, ", ")
+	}
+	return "sentinel"
+}
+
+// returnsFact records the sentinels a function returns, as "pkg/path.Name".
+// It is only exported for functions that return at least one sentinel.
+type returnsFact struct {
+	// Returns lists the sentinels the function returns as they are, and
+	// Wraps those it returns wrapped.
+	Returns, Wraps []string
+	// Via holds, for each sentinel of Wraps that the function does not
+	// wrap itself but passes on from a call that returns it wrapped, the
+	// functions the error comes through, as "pkg.Name", from the one the
+	// function calls to the one that wraps the sentinel. It is nil if the
+	// function wraps all of them itself, and empty for those it wraps.
+	Via [][]string
+	// Opaque is set if the function also returns errors of unknown origin,
+	// as they are or wrapped, which may hold any sentinel.
+	Opaque bool
+}
+
+func (*returnsFact) AFact() {}
+
+func (f *returnsFact) String() string {
+	var parts []string
+	if len(f.Returns) > 0 {
+		parts = append(parts, "returns "+strings.Join(f.Returns, ", "))
+	}
+	if len(f.Wraps) > 0 {
+		wraps := make([]string, len(f.Wraps))
+		for i, name := range f.Wraps {
+			wraps[i] = name
+			if i < len(f.Via) && len(f.Via[i]) > 0 {
+				wraps[i] += " (via " + strings.Join(f.Via[i], ", ") + ")"
+			}
+		}
+		parts = append(parts, "wraps "+strings.Join(wraps, ", "))
+	}
+	if f.Opaque {
+		parts = append(parts, "returns other errors")
+	}
+	return strings.Join(parts, "; ")
+}
+
+// origin describes the sentinels an error value may hold.
+type origin struct {
+	returns, wraps map[string]bool
+	// via maps the wrapped sentinels that come wrapped out of a call to the
+	// functions the error comes through, from the one called to the one
+	// that wraps the sentinel. Sentinels wrapped where the value is
+	// created have no entry; of several paths, the shortest is kept.
+	via map[string][]string
+	// opaque is set if the value may be an error of unknown origin as it
+	// is, which may be any sentinel, and wrapsOther if it may wrap one.
+	opaque, wrapsOther bool
+}
+
+var opaque = &origin{opaque: true}
+
+func (o *origin) add(other *origin) {
+	if o.returns == nil {
+		o.returns, o.wraps, o.via = make(map[string]bool), make(map[string]bool), make(map[string][]string)
+	}
+	for name := range other.wraps {
+		via, ok := other.via[name]
+		switch {
+		case !o.wraps[name]:
+			if ok {
+				o.via[name] = via
+			}
+		case !ok:
+			delete(o.via, name)
+		case o.via[name] != nil && len(via) < len(o.via[name]):
+			o.via[name] = via
+		}
+	}
+	maps.Copy(o.returns, other.returns)
+	maps.Copy(o.wraps, other.wraps)
+	o.opaque = o.opaque || other.opaque
+	o.wrapsOther = o.wrapsOther || other.wrapsOther
+}
+
+// wrap adds the sentinels of other to o as wrapped where o is created.
+func (o *origin) wrap(other *origin) {
+	wraps := make(map[string]bool, len(other.wraps)+len(other.returns))
+	maps.Copy(wraps, other.wraps)
+	maps.Copy(wraps, other.returns)
+	o.add(&origin{wraps: wraps, wrapsOther: other.opaque || other.wrapsOther})
+}
+
+// through returns the origin of the error results of a call of the
+// function called name whose results have origin o.
+func (o *origin) through(name string) *origin {
+	if len(o.wraps) == 0 {
+		return o
+	}
+	out := &origin{}
+	out.add(o)
+	for wrapped := range out.wraps {
+		out.via[wrapped] = append([]string{name}, o.via[wrapped]...)
+	}
+	return out
+}
+
+func (o *origin) equal(other *origin) bool {
+	return o.opaque == other.opaque && o.wrapsOther == other.wrapsOther && maps.Equal(o.returns, other.returns) && maps.Equal(o.wraps, other.wraps) && maps.EqualFunc(o.via, other.via, slices.Equal)
+}
+
+func (o *origin) fact() *returnsFact {
+	f := &returnsFact{
+		Returns: slices.Sorted(maps.Keys(o.returns)),
+		Wraps:   slices.Sorted(maps.Keys(o.wraps)),
+		Opaque:  o.opaque || o.wrapsOther,
+	}
+	if len(o.via) > 0 {
+		f.Via = make([][]string, len(f.Wraps))
+		for i, name := range f.Wraps {
+			f.Via[i] = o.via[name]
+		}
+	}
+	return f
+}
+
+// factFinder computes the origins of the error values of one pass, using
+// the facts of the functions of dependencies.
+type factFinder struct {
+	pass  *analysis.Pass
+	decls map[*types.Func]*ast.FuncDecl
+	// funcs holds the origins of the results of the functions of the
+	// package; a nil origin marks a function being computed.
+	funcs map[*types.Func]*origin
+	// locals holds the approximate origins of the local variables being
+	// computed, and stores those of the locations.
+	locals map[localAt]*origin
+	stores map[locationAt]*origin
+	// at, if valid, is the position the expression being traced is
+	// evaluated at; only the assignments that reach it count.
+	at token.Pos
+	// unwrapped maps the sentinels of the package documented to be
+	// returned unwrapped to the directive documenting them.
+	unwrapped map[*types.Var]*ast.Comment
+}
+
+// localAt is a local variable at a position in its function.
+type localAt struct {
+	v  *types.Var
+	at token.Pos
+}
+
+// locationAt is a location, as location returns it, at a position in the
+// function of its variable.
+type locationAt struct {
+	root *types.Var
+	path string
+	at   token.Pos
+}
+
+// exportFacts computes the facts of the functions and sentinels of the
+// package of pass and exports them.
+func exportFacts(pass *analysis.Pass) *factFinder {
+	ff := &factFinder{
+		pass:   pass,
+		decls:  make(map[*types.Func]*ast.FuncDecl),
+		funcs:  make(map[*types.Func]*origin),
+		locals: make(map[localAt]*origin),
+		stores: make(map[locationAt]*origin),
+	}
+	var fns []*types.Func
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			if fd, ok := decl.(*ast.FuncDecl); ok {
+				if fn, ok := pass.TypesInfo.Defs[fd.Name].(*types.Func); ok {
+					ff.decls[fn] = fd
+					fns = append(fns, fn)
+				}
+			}
+		}
+	}
+
+	for _, fn := range fns {
+		if o := ff.function(fn); len(o.returns) > 0 || len(o.wraps) > 0 {
+			pass.ExportObjectFact(fn, o.fact())
+		}
+	}
+
+	ff.unwrapped = unwrappedSentinels(pass)
+	scope := pass.Pkg.Scope()
+	for _, name := range scope.Names() {
+		v, ok := scope.Lookup(name).(*types.Var)
+		if !ok || !types.Implements(v.Type(), errorIface) {
+			continue
+		}
+		fact := &sentinelFact{Unwrapped: ff.unwrapped[v] != nil}
+		for _, fn := range fns {
+			if ff.function(fn).wraps[v.Pkg().Path()+"."+v.Name()] {
+				fact.WrappedBy = append(fact.WrappedBy, funcName(fn))
+			}
+		}
+		pass.ExportObjectFact(v, fact)
+	}
+	return ff
+}
+
+// unwrappedSentinels returns the package-level error variables of pass
+// that an unwrappedDirective documents, in the comment of their
+// declaration or in the package comment, and the directive.
+func unwrappedSentinels(pass *analysis.Pass) map[*types.Var]*ast.Comment {
+	vars := make(map[*types.Var]*ast.Comment)
+	add := func(id *ast.Ident, c *ast.Comment) {
+		if v, ok := pass.TypesInfo.Defs[id].(*types.Var); ok && types.Implements(v.Type(), errorIface) && vars[v] == nil {
+			vars[v] = c
+		}
+	}
+	var pkgDirective *ast.Comment
+	for _, file := range pass.Files {
+		if c := directive(file.Doc, unwrappedDirective); c != nil && pkgDirective == nil {
+			pkgDirective = c
+		}
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.VAR {
+				continue
+			}
+			declDirective := directive(gd.Doc, unwrappedDirective)
+			for _, spec := range gd.Specs {
+				vs := spec.(*ast.ValueSpec)
+				c := directive(vs.Doc, unwrappedDirective)
+				if c == nil {
+					c = declDirective
+				}
+				if c == nil {
+					continue
+				}
+				for _, id := range vs.Names {
+					add(id, c)
+				}
+			}
+		}
+	}
+	if pkgDirective != nil {
+		scope := pass.Pkg.Scope()
+		for _, name := range scope.Names() {
+			if v, ok := scope.Lookup(name).(*types.Var); ok && types.Implements(v.Type(), errorIface) && vars[v] == nil {
+				vars[v] = pkgDirective
+			}
+		}
+	}
+	return vars
+}
+
+// directive returns the comment of group that is the directive name,
+// possibly followed by an explanation, or nil.
+func directive(group *ast.CommentGroup, name string) *ast.Comment {
+	if group == nil {
+		return nil
+	}
+	for _, c := range group.List {
+		if rest, ok := strings.CutPrefix(c.Text, name); ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+			return c
+		}
+	}
+	return nil
+}
+
+// wrappedBy returns the functions of its own package that return the
+// sentinel expr refers to wrapped.
+func (ff *factFinder) wrappedBy(expr ast.Expr) []string {
+	return ff.sentinel(expr).WrappedBy
+}
+
+// unwrappedSentinel reports whether expr refers to a sentinel documented
+// to be returned unwrapped, which no function of its package wraps.
+func (ff *factFinder) unwrappedSentinel(expr ast.Expr) bool {
+	fact := ff.sentinel(expr)
+	return fact.Unwrapped && len(fact.WrappedBy) == 0
+}
+
+// sentinel returns the fact of the sentinel expr refers to, or an empty
+// fact.
+func (ff *factFinder) sentinel(expr ast.Expr) sentinelFact {
+	var id *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		id = e.Sel
+	}
+	var fact sentinelFact
+	if v, ok := ff.pass.TypesInfo.Uses[id].(*types.Var); ok {
+		ff.pass.ImportObjectFact(v, &fact)
+	}
+	return fact
+}
+
+// expr returns the origin of the error value of expr.
+func (ff *factFinder) expr(expr ast.Expr) *origin {
+	expr = ast.Unparen(expr)
+	if isNil(ff.pass, expr) {
+		return &origin{}
+	}
+	if name, ok := sentinelName(ff.pass, expr); ok {
+		return &origin{returns: map[string]bool{name: true}}
+	}
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if v, ok := ff.pass.TypesInfo.Uses[e].(*types.Var); ok {
+			return ff.local(v)
+		}
+	case *ast.CallExpr:
+		return ff.call(e)
+	case *ast.SelectorExpr, *ast.IndexExpr:
+		if root, path, ok := ff.location(e); ok && path != "" {
+			return ff.stored(root, path)
+		}
+	case *ast.UnaryExpr:
+		// &T{...} is a new value, which is no sentinel, but may wrap any.
+		if _, ok := ast.Unparen(e.X).(*ast.CompositeLit); ok && e.Op == token.AND {
+			return &origin{wrapsOther: true}
+		}
+	}
+	return opaque
+}
+
+// exprAt returns the origin of the error value of expr where it is used,
+// from the assignments that reach it.
+func (ff *factFinder) exprAt(expr ast.Expr) *origin {
+	defer ff.setAt(expr.Pos())()
+	return ff.expr(expr)
+}
+
+// setAt sets the position expressions are evaluated at, and returns a
+// function that restores the previous one.
+func (ff *factFinder) setAt(pos token.Pos) func() {
+	at := ff.at
+	ff.at = pos
+	return func() { ff.at = at }
+}
+
+// call returns the origin of the error results of call.
+func (ff *factFinder) call(call *ast.CallExpr) *origin {
+	info := ff.pass.TypesInfo
+	if tv, ok := info.Types[call.Fun]; ok && tv.IsType() && len(call.Args) == 1 {
+		return ff.expr(call.Args[0])
+	}
+	switch {
+	case isFunc(ff.pass, call, "errors", "New"):
+		return &origin{}
+	case isFunc(ff.pass, call, "errors", "Join"):
+		if call.Ellipsis.IsValid() {
+			return opaque
+		}
+		o := &origin{}
+		for _, arg := range call.Args {
+			o.wrap(ff.expr(arg))
+		}
+		return o
+	case isFunc(ff.pass, call, "fmt", "Errorf"):
+		if len(call.Args) == 0 || call.Ellipsis.IsValid() {
+			return opaque
+		}
+		format, ok := constantString(ff.pass, call.Args[0])
+		if !ok {
+			return opaque
+		}
+		args := call.Args[1:]
+		o := &origin{}
+		for _, v := range verbs.Parse(format, len(args)) {
+			if v.Verb == 'w' && 0 <= v.Arg && v.Arg < len(args) {
+				o.wrap(ff.expr(args[v.Arg]))
+			}
+		}
+		return o
+	}
+	if fn := typeutil.StaticCallee(info, call); fn != nil {
+		return ff.function(fn.Origin()).through(funcName(fn))
+	}
+	return opaque
+}
+
+// function returns the origin of the error results of fn.
+func (ff *factFinder) function(fn *types.Func) *origin {
+	if fn.Pkg() != ff.pass.Pkg {
+		var fact returnsFact
+		if !ff.pass.ImportObjectFact(fn, &fact) {
+			return opaque
+		}
+		o := &origin{returns: make(map[string]bool), wraps: make(map[string]bool), via: make(map[string][]string), opaque: fact.Opaque}
+		for _, name := range fact.Returns {
+			o.returns[name] = true
+		}
+		for i, name := range fact.Wraps {
+			o.wraps[name] = true
+			if i < len(fact.Via) && len(fact.Via[i]) > 0 {
+				o.via[name] = fact.Via[i]
+			}
+		}
+		return o
+	}
+
+	if o, ok := ff.funcs[fn]; ok {
+		if o == nil {
+			// Recursive functions are not followed.
+			return opaque
+		}
+		return o
+	}
+	decl := ff.decls[fn]
+	if decl == nil || decl.Body == nil {
+		return opaque
+	}
+	ff.funcs[fn] = nil
+
+	results := fn.Signature().Results()
+	var errs []int
+	for i := range results.Len() {
+		if t := results.At(i).Type(); types.IsInterface(t) && types.Implements(t, errorIface) {
+			errs = append(errs, i)
+		}
+	}
+	o := &origin{}
+	if len(errs) > 0 {
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.ReturnStmt:
+				defer ff.setAt(n.Pos())()
+				switch {
+				case len(n.Results) == 0:
+					for _, i := range errs {
+						o.add(ff.local(results.At(i)))
+					}
+				case len(n.Results) == 1 && results.Len() > 1:
+					if call, ok := ast.Unparen(n.Results[0]).(*ast.CallExpr); ok {
+						o.add(ff.call(call))
+					} else {
+						o.add(opaque)
+					}
+				default:
+					for _, i := range errs {
+						o.add(ff.expr(n.Results[i]))
+					}
+				}
+			}
+			return true
+		})
+	}
+	ff.funcs[fn] = o
+	return o
+}
+
+// local returns the origin of the local variable v at the current
+// position, the union of the origins of the values assigned to it that
+// reach the position. Parameters, variables whose address is taken and
+// variables declared by a range or type switch statement are opaque.
+func (ff *factFinder) local(v *types.Var) *origin {
+	decl := ff.enclosingFunc(v.Pos())
+	if decl == nil {
+		return opaque
+	}
+	key := localAt{v, ff.at}
+	if !ff.at.IsValid() || ff.at < decl.Pos() || ff.at >= decl.End() {
+		key.at = token.NoPos
+	}
+	if o, ok := ff.locals[key]; ok {
+		return o
+	}
+
+	// Assignments in closures, which may run at any time, can refer to v
+	// itself, so iterate until its origin no longer grows.
+	ff.locals[key] = &origin{}
+	defer delete(ff.locals, key)
+	for {
+		o := ff.assigned(v, decl, key.at)
+		if o.equal(ff.locals[key]) {
+			return o
+		}
+		ff.locals[key] = o
+	}
+}
+
+// assigned returns the union of the origins of the values assigned to v in
+// decl. If at is valid, only the assignments that reach it count: those
+// before it and not followed by another assignment that always runs on
+// the way to it, and those in closures, which may run at any time.
+func (ff *factFinder) assigned(v *types.Var, decl *ast.FuncDecl, at token.Pos) *origin {
+	info := ff.pass.TypesInfo
+	is := func(expr ast.Expr) bool {
+		id, ok := ast.Unparen(expr).(*ast.Ident)
+		return ok && (info.Defs[id] == v || info.Uses[id] == v)
+	}
+	since := token.NoPos
+	var closures []*ast.FuncLit
+	if at.IsValid() {
+		if stmt := ff.reaching(v, at); stmt != nil {
+			since = stmt.Pos()
+		}
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.FuncLit); ok && (at < lit.Pos() || at >= lit.End()) {
+				closures = append(closures, lit)
+				return false
+			}
+			return true
+		})
+	}
+	reaches := func(n ast.Node) bool {
+		if !at.IsValid() || slices.ContainsFunc(closures, func(lit *ast.FuncLit) bool { return lit.Pos() <= n.Pos() && n.End() <= lit.End() }) {
+			return true
+		}
+		return since <= n.Pos() && n.Pos() < at
+	}
+
+	o := &origin{}
+	declares := func(fields *ast.FieldList) bool {
+		return fields != nil && slices.ContainsFunc(fields.List, func(field *ast.Field) bool {
+			return slices.ContainsFunc(field.Names, func(id *ast.Ident) bool { return info.Defs[id] == v })
+		})
+	}
+	declared := declares(decl.Type.Results)
+	if declares(decl.Recv) || declares(decl.Type.Params) {
+		// The value passed in is opaque, unless an assignment hides it.
+		declared = true
+		if !since.IsValid() {
+			o.add(opaque)
+		}
+	}
+	values := func(pos token.Pos, rhs []ast.Expr, n, i int) {
+		o.add(ff.value(pos, rhs, n, i, ""))
+	}
+	ast.Inspect(decl, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range n.Lhs {
+				if !is(lhs) {
+					continue
+				}
+				declared = declared || info.Defs[lhs.(*ast.Ident)] == v
+				switch {
+				case !reaches(n):
+				case n.Tok != token.ASSIGN && n.Tok != token.DEFINE:
+					o.add(opaque)
+				default:
+					values(n.Pos(), n.Rhs, len(n.Lhs), i)
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range n.Names {
+				if info.Defs[name] == v {
+					declared = true
+					if reaches(n) {
+						values(n.Pos(), n.Values, len(n.Names), i)
+					}
+				}
+			}
+		case *ast.UnaryExpr:
+			if n.Op == token.AND && is(n.X) {
+				o.add(opaque)
+			}
+		}
+		return true
+	})
+	if !declared {
+		return opaque
+	}
+	return o
+}
+
+// value returns the origin of the part at path rest, as location returns
+// paths, of the i-th of the values assigned to n variables by the
+// statement at pos.
+func (ff *factFinder) value(pos token.Pos, rhs []ast.Expr, n, i int, rest string) *origin {
+	defer ff.setAt(pos)()
+	switch {
+	case len(rhs) == n:
+		return ff.part(rhs[i], rest)
+	case len(rhs) != 1:
+		return &origin{}
+	}
+	if call, ok := ast.Unparen(rhs[0]).(*ast.CallExpr); ok && rest == "" {
+		return ff.call(call)
+	}
+	return opaque
+}
+
+// part returns the origin of the error value at path rest in the value
+// of expr: the field of a composite literal, or the values of a map
+// literal, in turn, or the value of expr itself for an empty path.
+func (ff *factFinder) part(expr ast.Expr, rest string) *origin {
+	if rest == "" {
+		return ff.expr(expr)
+	}
+	expr = ast.Unparen(expr)
+	if u, ok := expr.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		expr = ast.Unparen(u.X)
+		rest, _ = strings.CutPrefix(rest, "*")
+	}
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		switch t := ff.pass.TypesInfo.TypeOf(e).Underlying().(type) {
+		case *types.Struct:
+			index, rest, ok := cutField(rest)
+			if !ok || index >= t.NumFields() {
+				return opaque
+			}
+			for j, elt := range e.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if id, ok := kv.Key.(*ast.Ident); ok && id.Name == t.Field(index).Name() {
+						return ff.part(kv.Value, rest)
+					}
+				} else if j == index {
+					return ff.part(elt, rest)
+				}
+			}
+			// The field holds its zero value.
+			return &origin{}
+		case *types.Map:
+			rest, ok := strings.CutPrefix(rest, "[]")
+			if !ok {
+				return opaque
+			}
+			o := &origin{}
+			for _, elt := range e.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					o.add(ff.part(kv.Value, rest))
+				}
+			}
+			return o
+		}
+	case *ast.CallExpr:
+		if isBuiltin(ff.pass, e, "new") || isBuiltin(ff.pass, e, "make") {
+			return &origin{}
+		}
+	}
+	return opaque
+}
+
+// cutField splits the index of the first field off path.
+func cutField(path string) (int, string, bool) {
+	path, ok := strings.CutPrefix(path, ".")
+	if !ok {
+		return 0, "", false
+	}
+	end := strings.IndexAny(path, ".*[")
+	if end < 0 {
+		end = len(path)
+	}
+	index, err := strconv.Atoi(path[:end])
+	return index, path[end:], err == nil
+}
+
+// location returns the location expr refers to: a local variable, a field
+// of it, a value of a map it holds, or a field or map value of one of
+// those in turn, such as r.err, m[key] or r.errs[key]. The location is the
+// variable and the path from it to the value, made of the indexes of the
+// fields, such as ".0", "[]" for the values of a map, whose keys are not
+// told apart, and "*" for the indirections through pointers.
+func (ff *factFinder) location(expr ast.Expr) (*types.Var, string, bool) {
+	info := ff.pass.TypesInfo
+	var path []string
+	for {
+		switch e := ast.Unparen(expr).(type) {
+		case *ast.Ident:
+			v, ok := info.ObjectOf(e).(*types.Var)
+			if !ok || v.IsField() || v.Parent() == nil || v.Parent() == ff.pass.Pkg.Scope() {
+				return nil, "", false
+			}
+			slices.Reverse(path)
+			return v, strings.Join(path, ""), true
+		case *ast.SelectorExpr:
+			sel := info.Selections[e]
+			if sel == nil || sel.Kind() != types.FieldVal {
+				return nil, "", false
+			}
+			var fields []string
+			t := sel.Recv()
+			for _, i := range sel.Index() {
+				if p, ok := t.Underlying().(*types.Pointer); ok {
+					fields = append(fields, "*")
+					t = p.Elem()
+				}
+				s, ok := t.Underlying().(*types.Struct)
+				if !ok {
+					return nil, "", false
+				}
+				fields = append(fields, "."+strconv.Itoa(i))
+				t = s.Field(i).Type()
+			}
+			slices.Reverse(fields)
+			path = append(path, fields...)
+			expr = e.X
+		case *ast.IndexExpr:
+			if _, ok := info.TypeOf(e.X).Underlying().(*types.Map); !ok {
+				return nil, "", false
+			}
+			path = append(path, "[]")
+			expr = e.X
+		case *ast.StarExpr:
+			path = append(path, "*")
+			expr = e.X
+		default:
+			return nil, "", false
+		}
+	}
+}
+
+// stored returns the origin of the error value at the location of root
+// and path where it is read, the union of the origins of the values
+// stored there, or in the values around it, before the position or in
+// closures, which may run at any time. Unlike for local variables, later
+// stores do not hide earlier ones. A location whose address is taken, or
+// that can be changed through a copy of a pointer or map on the path to
+// it, is opaque, and so is one in a parameter.
+func (ff *factFinder) stored(root *types.Var, path string) *origin {
+	decl := ff.enclosingFunc(root.Pos())
+	if decl == nil {
+		return opaque
+	}
+	key := locationAt{root, path, ff.at}
+	if !ff.at.IsValid() || ff.at < decl.Pos() || ff.at >= decl.End() {
+		key.at = token.NoPos
+	}
+	if o, ok := ff.stores[key]; ok {
+		return o
+	}
+
+	// Stores in closures can refer to the location itself, so iterate
+	// until its origin no longer grows, as for local variables.
+	ff.stores[key] = &origin{}
+	defer delete(ff.stores, key)
+	for {
+		o := ff.storedIn(decl, key)
+		if o.equal(ff.stores[key]) {
+			return o
+		}
+		ff.stores[key] = o
+	}
+}
+
+// storedIn returns the union of the origins of the values stored at the
+// location of key in decl, as stored describes it.
+func (ff *factFinder) storedIn(decl *ast.FuncDecl, key locationAt) *origin {
+	info := ff.pass.TypesInfo
+	since := token.NoPos
+	var closures []*ast.FuncLit
+	if key.at.IsValid() {
+		if stmt := ff.reachingBy(key.at, func(stmt ast.Stmt) bool { return ff.overwrites(stmt, key) }); stmt != nil {
+			since = stmt.Pos()
+		}
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.FuncLit); ok && (key.at < lit.Pos() || key.at >= lit.End()) {
+				closures = append(closures, lit)
+				return false
+			}
+			return true
+		})
+	}
+	reaches := func(n ast.Node) bool {
+		if !key.at.IsValid() || slices.ContainsFunc(closures, func(lit *ast.FuncLit) bool { return lit.Pos() <= n.Pos() && n.End() <= lit.End() }) {
+			return true
+		}
+		return since <= n.Pos() && n.Pos() < key.at
+	}
+
+	o := &origin{}
+	declared := false
+	var stack []ast.Node
+	ast.Inspect(decl, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+		id, ok := n.(*ast.Ident)
+		if !ok || info.ObjectOf(id) != key.root {
+			return true
+		}
+		// Find the outermost expression of the location id starts.
+		i := len(stack) - 1
+		for i > 1 && extendsLocation(info, stack[i-1], stack[i]) {
+			i--
+		}
+		top := stack[i].(ast.Expr)
+		_, path, ok := ff.location(top)
+		if !ok {
+			return true
+		}
+		rest, ok := strings.CutPrefix(key.path, path)
+		if !ok {
+			// An unrelated location.
+			return true
+		}
+		switch parent := stack[i-1].(type) {
+		case *ast.AssignStmt:
+			if j := slices.Index(parent.Lhs, top); j >= 0 {
+				declared = declared || info.Defs[id] == key.root
+				switch {
+				case !reaches(parent):
+				case parent.Tok != token.ASSIGN && parent.Tok != token.DEFINE:
+					o.add(opaque)
+				default:
+					o.add(ff.value(parent.Pos(), parent.Rhs, len(parent.Lhs), j, rest))
+				}
+				return true
+			}
+		case *ast.ValueSpec:
+			if j := slices.Index(parent.Names, id); j >= 0 {
+				declared = true
+				if reaches(parent) {
+					o.add(ff.value(parent.Pos(), parent.Values, len(parent.Names), j, rest))
+				}
+				return true
+			}
+		case *ast.Field:
+			// A parameter holds whatever the caller passed, and a result
+			// starts with its zero value.
+			declared = true
+			if fields, ok := stack[i-2].(*ast.FieldList); ok && !since.IsValid() {
+				if ft, ok := stack[i-3].(*ast.FuncType); !ok || ft.Results != fields {
+					o.add(opaque)
+				}
+			}
+			return true
+		case *ast.RangeStmt:
+			if parent.Key == top || parent.Value == top {
+				declared = declared || info.Defs[id] == key.root
+				if reaches(parent) {
+					o.add(opaque)
+				}
+				return true
+			}
+			// Ranging over a map copies its values.
+			return true
+		case *ast.UnaryExpr:
+			if parent.Op == token.AND {
+				o.add(opaque)
+				return true
+			}
+		case *ast.SelectorExpr:
+			// A method with a pointer receiver can change the value.
+			if sel := info.Selections[parent]; sel != nil && sel.Kind() == types.MethodVal {
+				if _, ok := sel.Obj().Type().(*types.Signature).Recv().Type().(*types.Pointer); ok {
+					o.add(opaque)
+					return true
+				}
+			}
+		case *ast.CallExpr:
+			// Builtins such as len and delete keep no copy.
+			if id, ok := ast.Unparen(parent.Fun).(*ast.Ident); ok {
+				if _, ok := info.Uses[id].(*types.Builtin); ok {
+					return true
+				}
+			}
+		}
+		// Any other use reads the value at the location; a copy of a
+		// pointer or map on the way to the error can change it later.
+		if strings.ContainsAny(rest, "*[") {
+			o.add(opaque)
+		}
+		return true
+	})
+	if !declared {
+		return opaque
+	}
+	return o
+}
+
+// overwrites reports whether stmt stores a value at the location of key,
+// or at one around it, that replaces the value there: a store at a map
+// value only replaces the value of one key.
+func (ff *factFinder) overwrites(stmt ast.Stmt, key locationAt) bool {
+	switch s := stmt.(type) {
+	case *ast.LabeledStmt:
+		return ff.overwrites(s.Stmt, key)
+	case *ast.AssignStmt:
+		if s.Tok != token.ASSIGN && s.Tok != token.DEFINE {
+			return false
+		}
+		return slices.ContainsFunc(s.Lhs, func(lhs ast.Expr) bool {
+			root, path, ok := ff.location(lhs)
+			return ok && root == key.root && strings.HasPrefix(key.path, path) && !strings.Contains(path, "[]")
+		})
+	}
+	return ff.assigns(stmt, key.root)
+}
+
+// extendsLocation reports whether parent extends the location child
+// refers to, as location follows it.
+func extendsLocation(info *types.Info, parent, child ast.Node) bool {
+	switch p := parent.(type) {
+	case *ast.ParenExpr, *ast.StarExpr:
+		return true
+	case *ast.SelectorExpr:
+		sel := info.Selections[p]
+		return p.X == child && sel != nil && sel.Kind() == types.FieldVal
+	case *ast.IndexExpr:
+		_, ok := info.TypeOf(p.X).Underlying().(*types.Map)
+		return p.X == child && ok
+	}
+	return false
+}
+
+// reaching returns the last statement before pos that assigns v and
+// always runs on the way to pos, so that it hides the assignments before
+// it, or nil.
+func (ff *factFinder) reaching(v *types.Var, pos token.Pos) ast.Stmt {
+	return ff.reachingBy(pos, func(stmt ast.Stmt) bool { return ff.assigns(stmt, v) })
+}
+
+// reachingBy returns the last statement before pos for which assigns
+// reports true and that always runs on the way to pos, or nil. Only the
+// statements of the blocks around pos, up to the closure or function
+// containing it, are considered.
+func (ff *factFinder) reachingBy(pos token.Pos, assigns func(ast.Stmt) bool) ast.Stmt {
+	file := fileOf(ff.pass, pos)
+	if file == nil {
+		return nil
+	}
+	path, _ := astutil.PathEnclosingInterval(file, pos, pos)
+	var last ast.Stmt
+	for _, n := range path {
+		var stmts []ast.Stmt
+		switch n := n.(type) {
+		case *ast.FuncLit, *ast.FuncDecl:
+			return last
+		case *ast.BlockStmt:
+			stmts = n.List
+		case *ast.CaseClause:
+			stmts = n.Body
+		case *ast.CommClause:
+			stmts = n.Body
+		case *ast.IfStmt:
+			stmts = []ast.Stmt{n.Init}
+		case *ast.SwitchStmt:
+			stmts = []ast.Stmt{n.Init}
+		case *ast.TypeSwitchStmt:
+			stmts = []ast.Stmt{n.Init}
+		}
+		for _, stmt := range stmts {
+			if stmt != nil && stmt.End() <= pos && (last == nil || stmt.Pos() > last.Pos()) && assigns(stmt) {
+				last = stmt
+			}
+		}
+	}
+	return last
+}
+
+// assigns reports whether stmt assigns a value to v, or declares it.
+func (ff *factFinder) assigns(stmt ast.Stmt, v *types.Var) bool {
+	info := ff.pass.TypesInfo
+	switch s := stmt.(type) {
+	case *ast.LabeledStmt:
+		return ff.assigns(s.Stmt, v)
+	case *ast.AssignStmt:
+		if s.Tok != token.ASSIGN && s.Tok != token.DEFINE {
+			return false
+		}
+		return slices.ContainsFunc(s.Lhs, func(lhs ast.Expr) bool {
+			id, ok := lhs.(*ast.Ident)
+			return ok && (info.Defs[id] == v || info.Uses[id] == v)
+		})
+	case *ast.DeclStmt:
+		gen, ok := s.Decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			return false
+		}
+		for _, spec := range gen.Specs {
+			if slices.ContainsFunc(spec.(*ast.ValueSpec).Names, func(id *ast.Ident) bool { return info.Defs[id] == v }) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// enclosingFunc returns the function declaration that contains pos.
+func (ff *factFinder) enclosingFunc(pos token.Pos) *ast.FuncDecl {
+	file := fileOf(ff.pass, pos)
+	if file == nil {
+		return nil
+	}
+	for _, decl := range file.Decls {
+		if fd, ok := decl.(*ast.FuncDecl); ok && fd.Pos() <= pos && pos < fd.End() {
+			return fd
+		}
+	}
+	return nil
+}
+
+// funcName returns the name of fn qualified by its package name, such as
+// "store.Load" or, for a method, "store.DB.Get".
+func funcName(fn *types.Func) string {
+	return fn.Pkg().Name() + "." + memberName(fn)
+}
+
+// memberName returns the name of fn within its package, such as "Load" or,
+// for a method, "DB.Get".
+func memberName(fn *types.Func) string {
+	name := fn.Name()
+	if recv := fn.Signature().Recv(); recv != nil {
+		t := recv.Type()
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		if named, ok := t.(*types.Named); ok {
+			name = named.Obj().Name() + "." + name
+		}
+	}
+	return name
+}
@@ -0,0 +1,155 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// fileOf returns the file of the package that contains pos.
+func fileOf(pass *analysis.Pass, pos token.Pos) *ast.File {
+	for _, f := range pass.Files {
+		if f.FileStart <= pos && pos < f.FileEnd {
+			return f
+		}
+	}
+	return nil
+}
+
+// render returns the Go source for node.
+func render(pass *analysis.Pass, node ast.Node) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, pass.Fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// source returns the original source text between start and end.
+func source(pass *analysis.Pass, start, end token.Pos) (string, bool) {
+	tf := pass.Fset.File(start)
+	if tf == nil {
+		return "", false
+	}
+	content, err := pass.ReadFile(tf.Name())
+	if err != nil {
+		return "", false
+	}
+	startOff, endOff := tf.Offset(start), tf.Offset(end)
+	if startOff > endOff || endOff > len(content) {
+		return "", false
+	}
+	return string(content[startOff:endOff]), true
+}
+
+// indentation returns the leading whitespace of the line containing pos.
+func indentation(pass *analysis.Pass, pos token.Pos) string {
+	tf := pass.Fset.File(pos)
+	if tf == nil {
+		return ""
+	}
+	line, ok := source(pass, tf.LineStart(tf.Line(pos)), pos)
+	if !ok {
+		return ""
+	}
+	for i, r := range line {
+		if r != ' ' && r != '\t' {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// importErrors returns the name under which file refers to package errors,
+// and the edits that add the import if the file does not have it yet.
+func importErrors(pass *analysis.Pass, file *ast.File) (string, []analysis.TextEdit) {
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || path != "errors" {
+			continue
+		}
+		if spec.Name == nil {
+			return "errors", nil
+		}
+		if spec.Name.Name != "_" && spec.Name.Name != "." {
+			return spec.Name.Name, nil
+		}
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		if gen.Lparen.IsValid() {
+			return "errors", []analysis.TextEdit{{
+				Pos:     gen.Lparen + 1,
+				End:     gen.Lparen + 1,
+				NewText: []byte("\n\t\"errors\""),
+			}}
+		}
+		spec, ok := source(pass, gen.Specs[0].Pos(), gen.Specs[0].End())
+		if !ok {
+			break
+		}
+		return "errors", []analysis.TextEdit{{
+			Pos:     gen.Pos(),
+			End:     gen.End(),
+			NewText: fmt.Appendf(nil, "import (\n\t\"errors\"\n\t%s\n)", spec),
+		}}
+	}
+
+	return "errors", []analysis.TextEdit{{
+		Pos:     file.Name.End(),
+		End:     file.Name.End(),
+		NewText: []byte("\n\nimport \"errors\""),
+	}}
+}
+
+// replaceImport returns the edit that replaces spec, an import of decl,
+// with text, such as `"errors"`, or removes it if text is empty.
+func replaceImport(pass *analysis.Pass, decl *ast.GenDecl, spec *ast.ImportSpec, text string) analysis.TextEdit {
+	edit := analysis.TextEdit{Pos: spec.Pos(), End: spec.End(), NewText: []byte(text)}
+	switch tf := pass.Fset.File(spec.Pos()); {
+	case !decl.Lparen.IsValid():
+		edit.Pos, edit.End = decl.Pos(), decl.End()
+		if text != "" {
+			edit.NewText = []byte("import " + text)
+		}
+	case text == "" && tf.Line(spec.End()) < tf.Line(decl.Rparen):
+		// Remove the line of the import; gofmt fixes the indentation of
+		// the next one.
+		edit.End = tf.LineStart(tf.Line(spec.End()) + 1)
+	}
+	return edit
+}
+
+// refersTo reports whether file refers to pkg other than in the functions
+// of the calls that rewritten reports, whose fixes no longer call pkg: only
+// their arguments are searched.
+func refersTo(pass *analysis.Pass, file *ast.File, pkg *types.PkgName, rewritten func(*ast.CallExpr) bool) bool {
+	used := false
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			if rewritten(n) {
+				for _, arg := range n.Args {
+					ast.Inspect(arg, visit)
+				}
+				return false
+			}
+		case *ast.Ident:
+			used = used || isUse(pass, n, pkg)
+		}
+		return !used
+	}
+	ast.Inspect(file, visit)
+	return used
+}
@@ -0,0 +1,167 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// ignorePrefix starts a suppression comment such as
+//
+//	//errlint:ignore ERRLINT001,ERRLINT004 compared before wrapping
+//
+// It names the checks whose findings it suppresses, by ID or name, and
+// the reason. A directive at the end of a line applies to that line, and a
+// directive on a line of its own to the next one.
+const ignorePrefix = "//errlint:ignore"
+
+// ignoreDirective is a parsed suppression comment.
+type ignoreDirective struct {
+	comment *ast.Comment
+	// lineStart and lineEnd delimit the line the comment is alone on; they
+	// are invalid if the comment follows code.
+	lineStart, lineEnd token.Pos
+	// checks are the checks the directive suppresses, and unknown the IDs
+	// or names that match no check.
+	checks  []check
+	unknown []string
+	reason  string
+	// used records the checks whose findings the directive suppressed.
+	used map[string]bool
+}
+
+// ignoreKey is a line a directive applies to.
+type ignoreKey struct {
+	filename string
+	line     int
+}
+
+// ignoreSet holds the directives of the files of one pass.
+type ignoreSet struct {
+	directives []*ignoreDirective
+	byLine     map[ignoreKey][]*ignoreDirective
+}
+
+// parseIgnores collects the suppression comments of the files of pass,
+// which may name the custom checks as well as those of errlint.
+func parseIgnores(pass *analysis.Pass, custom *customChecks) *ignoreSet {
+	s := &ignoreSet{byLine: make(map[ignoreKey][]*ignoreDirective)}
+	for _, file := range pass.Files {
+		tf := pass.Fset.File(file.FileStart)
+		if tf == nil {
+			continue
+		}
+		src, _ := pass.ReadFile(tf.Name())
+		for _, group := range file.Comments {
+			for _, c := range group.List {
+				rest, ok := strings.CutPrefix(c.Text, ignorePrefix)
+				if !ok || rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+					continue
+				}
+				d := &ignoreDirective{comment: c, used: make(map[string]bool)}
+				list, reason, _ := strings.Cut(strings.TrimSpace(rest), " ")
+				d.reason = strings.TrimSpace(reason)
+				for idOrName := range strings.SplitSeq(list, ",") {
+					c, ok := findCheck(idOrName)
+					if !ok {
+						c, ok = custom.find(idOrName)
+					}
+					if ok {
+						d.checks = append(d.checks, c)
+					} else if idOrName != "" {
+						d.unknown = append(d.unknown, idOrName)
+					}
+				}
+
+				line := tf.Line(c.Pos())
+				if start := tf.LineStart(line); src != nil && len(bytes.TrimSpace(src[tf.Offset(start):tf.Offset(c.Pos())])) == 0 {
+					d.lineStart = start
+					d.lineEnd = file.FileEnd
+					if line < tf.LineCount() {
+						d.lineEnd = tf.LineStart(line + 1)
+					}
+					line++
+				}
+				key := ignoreKey{filename: tf.Name(), line: line}
+				s.byLine[key] = append(s.byLine[key], d)
+				s.directives = append(s.directives, d)
+			}
+		}
+	}
+	return s
+}
+
+// suppress reports whether a directive suppresses the findings of the
+// named check at pos, and records that it did.
+func (s *ignoreSet) suppress(fset *token.FileSet, pos token.Pos, name string) bool {
+	if d := s.find(fset, pos, name); d != nil {
+		d.used[name] = true
+		return true
+	}
+	return false
+}
+
+// find returns the directive that suppresses the findings of the named
+// check at pos, or nil.
+func (s *ignoreSet) find(fset *token.FileSet, pos token.Pos, name string) *ignoreDirective {
+	p := fset.Position(pos)
+	for _, d := range s.byLine[ignoreKey{filename: p.Filename, line: p.Line}] {
+		if slices.ContainsFunc(d.checks, func(c check) bool { return c.name == name }) {
+			return d
+		}
+	}
+	return nil
+}
+
+// checkIgnores reports suppression comments that name unknown checks, give
+// no reason, or suppress no finding, so that they do not outlive the code
+// they were written for. It runs after the other checks, and only judges
+// directives for the checks that ran.
+func (l *linter) checkIgnores(pass *analysis.Pass, _ *inspector.Inspector) {
+	for _, d := range l.ignores.directives {
+		for _, name := range d.unknown {
+			pass.Reportf(d.comment.Pos(), "errlint:ignore directive names unknown check %q", name)
+		}
+		if len(d.checks) == 0 && len(d.unknown) == 0 {
+			pass.Reportf(d.comment.Pos(), "errlint:ignore directive names no check; list the IDs of the checks it suppresses")
+			continue
+		}
+		if d.reason == "" {
+			pass.Reportf(d.comment.Pos(), "errlint:ignore directive needs a reason after the checks it suppresses")
+		}
+
+		var unused []string
+		for _, c := range d.checks {
+			if l.runs(c.name) && c.name != "ignore" && !d.used[c.name] {
+				unused = append(unused, c.id)
+			}
+		}
+		if len(unused) == 0 {
+			continue
+		}
+		ids := strings.Join(unused, " or ")
+		diag := analysis.Diagnostic{
+			Pos:     d.comment.Pos(),
+			End:     d.comment.End(),
+			Message: fmt.Sprintf("errlint:ignore directive suppresses no %s finding; remove %s from it", ids, strings.Join(unused, " and ")),
+		}
+		if len(unused) == len(d.checks) && len(d.unknown) == 0 {
+			diag.Message = fmt.Sprintf("errlint:ignore directive suppresses no %s finding; remove it", ids)
+			edit := analysis.TextEdit{Pos: d.comment.Pos(), End: d.comment.End()}
+			if d.lineStart.IsValid() {
+				edit = analysis.TextEdit{Pos: d.lineStart, End: d.lineEnd}
+			}
+			diag.SuggestedFixes = []analysis.SuggestedFix{{
+				Message:   "Remove the errlint:ignore directive",
+				TextEdits: []analysis.TextEdit{edit},
+			}}
+		}
+		pass.Report(diag)
+	}
+}
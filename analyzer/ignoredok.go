@@ -0,0 +1,331 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// checkIgnoredOK reports errors.As calls whose result is ignored and type
+// assertions whose ok result is assigned to _, when a later statement of
+// the same block dereferences the variable they set, as in
+//
+//	var pathErr *fs.PathError
+//	errors.As(err, &pathErr)
+//	log.Print(pathErr.Path)
+//
+//	conn, _ := c.(*net.TCPConn)
+//	conn.SetKeepAlive(true)
+//
+// If the error does not match, or the value is of another type, the
+// variable is nil and the dereference panics; a variable of a struct type
+// holds its zero value instead, and reading it silently uses the wrong
+// data. Variables that are compared with nil after the statement, and
+// errors.As targets that are not declared without a value right before, are
+// not reported. The suggested fix guards the statements that use the
+// variable with the result.
+func (l *linter) checkIgnoredOK(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.WithStack([]ast.Node{(*ast.ExprStmt)(nil), (*ast.AssignStmt)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push || len(stack) < 2 {
+			return true
+		}
+		stmt := n.(ast.Stmt)
+		stmts := blockStmts(stack[len(stack)-2])
+		i := slices.Index(stmts, stmt)
+		if i < 0 {
+			return true
+		}
+		ig := ignoredResult(pass, stmt, enclosingBody(stack))
+		if ig == nil {
+			return true
+		}
+		deref := firstDeref(pass, stmts[i+1:], ig.v)
+		if deref == nil {
+			return true
+		}
+
+		t := ig.v.Type()
+		_, isPointer := t.Underlying().(*types.Pointer)
+		nilable := isPointer || types.IsInterface(t)
+		name, operand, line := ig.v.Name(), render(pass, ig.operand), pass.Fset.Position(deref.Pos()).Line
+		d := analysis.Diagnostic{Pos: ig.expr.Pos(), End: ig.expr.End()}
+		switch {
+		case ig.assert == nil && nilable:
+			d.Message = fmt.Sprintf("the result of errors.As is ignored, but %s stays nil if %s does not match, and %s on line %d dereferences it; check the result", name, operand, render(pass, deref), line)
+		case ig.assert == nil:
+			d.Message = fmt.Sprintf("the result of errors.As is ignored, but %s keeps its zero value if %s does not match, and %s on line %d reads it; check the result", name, operand, render(pass, deref), line)
+		case nilable:
+			d.Message = fmt.Sprintf("the ok result of the type assertion is discarded, but %s is nil if %s is not a %s, and %s on line %d dereferences it; check ok", name, operand, typeString(pass, t), render(pass, deref), line)
+		default:
+			d.Message = fmt.Sprintf("the ok result of the type assertion is discarded, but %s is the zero %s if %s is not one, and %s on line %d reads it; check ok", name, typeString(pass, t), operand, render(pass, deref), line)
+		}
+		_, results := enclosingFunc(pass, stack)
+		d.SuggestedFixes = guardFix(pass, stmts, i, ig, results)
+		pass.Report(d)
+		return true
+	})
+}
+
+// ignoredOK is a statement that ignores the result of errors.As or the ok
+// result of a type assertion.
+type ignoredOK struct {
+	// v is the target of errors.As, or the variable the assertion sets.
+	v *types.Var
+	// expr is the errors.As call or the type assertion, and operand the
+	// error or value they look at.
+	expr, operand ast.Expr
+	// assert is the type assertion, and blank the _ its ok result is
+	// assigned to, or nil for errors.As.
+	assert *ast.TypeAssertExpr
+	blank  *ast.Ident
+	define bool
+}
+
+// ignoredResult returns what stmt ignores, if it is an errors.As call
+// whose result is dropped or assigned to _, with a target declared in body
+// without a value and not assigned since, or a two-value type assertion
+// whose ok result is assigned to _. It returns nil otherwise.
+func ignoredResult(pass *analysis.Pass, stmt ast.Stmt, body *ast.BlockStmt) *ignoredOK {
+	var call ast.Expr
+	switch stmt := stmt.(type) {
+	case *ast.ExprStmt:
+		call = stmt.X
+	case *ast.AssignStmt:
+		if len(stmt.Lhs) == 1 && len(stmt.Rhs) == 1 && isBlank(stmt.Lhs[0]) {
+			call = stmt.Rhs[0]
+			break
+		}
+		if len(stmt.Lhs) != 2 || len(stmt.Rhs) != 1 || !isBlank(stmt.Lhs[1]) {
+			return nil
+		}
+		assert, ok := ast.Unparen(stmt.Rhs[0]).(*ast.TypeAssertExpr)
+		id, okID := stmt.Lhs[0].(*ast.Ident)
+		if !ok || assert.Type == nil || !okID {
+			return nil
+		}
+		v, ok := pass.TypesInfo.ObjectOf(id).(*types.Var)
+		if !ok {
+			return nil
+		}
+		return &ignoredOK{v: v, expr: assert, operand: assert.X, assert: assert, blank: stmt.Lhs[1].(*ast.Ident), define: stmt.Tok == token.DEFINE}
+	}
+	c, ok := ast.Unparen(call).(*ast.CallExpr)
+	if !ok || !isFunc(pass, c, "errors", "As") || len(c.Args) != 2 || body == nil {
+		return nil
+	}
+	v := addressedLocal(pass, c.Args[1])
+	if v == nil || !declaredZero(pass, body, v) || assignedBetween(pass, body, v, v.Pos(), c.Pos()) {
+		return nil
+	}
+	return &ignoredOK{v: v, expr: c, operand: c.Args[0]}
+}
+
+// isBlank reports whether expr is the blank identifier.
+func isBlank(expr ast.Expr) bool {
+	id, ok := expr.(*ast.Ident)
+	return ok && id.Name == "_"
+}
+
+// blockStmts returns the statements of the block, case clause or select
+// clause n, or nil.
+func blockStmts(n ast.Node) []ast.Stmt {
+	switch n := n.(type) {
+	case *ast.BlockStmt:
+		return n.List
+	case *ast.CaseClause:
+		return n.Body
+	case *ast.CommClause:
+		return n.Body
+	}
+	return nil
+}
+
+// declaredZero reports whether body declares v with a var declaration
+// without a value.
+func declaredZero(pass *analysis.Pass, body *ast.BlockStmt, v *types.Var) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.ValueSpec); ok && len(spec.Values) == 0 {
+			found = found || slices.ContainsFunc(spec.Names, func(id *ast.Ident) bool { return pass.TypesInfo.Defs[id] == v })
+		}
+		return !found
+	})
+	return found
+}
+
+// firstDeref returns the first selector or indirection of v in stmts,
+// before v is assigned again, or nil if there is none or stmts compare v
+// with nil.
+func firstDeref(pass *analysis.Pass, stmts []ast.Stmt, v *types.Var) ast.Expr {
+	is := func(expr ast.Expr) bool {
+		id, ok := ast.Unparen(expr).(*ast.Ident)
+		return ok && pass.TypesInfo.Uses[id] == v
+	}
+	for _, stmt := range stmts {
+		guarded := false
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			if cmp, ok := n.(*ast.BinaryExpr); ok {
+				guarded = guarded || comparesNil(pass, cmp, v, token.EQL) || comparesNil(pass, cmp, v, token.NEQ)
+			}
+			return !guarded
+		})
+		if guarded {
+			return nil
+		}
+	}
+	var deref ast.Expr
+	assigned := false
+	for _, stmt := range stmts {
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			if deref != nil || assigned {
+				return false
+			}
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				assigned = slices.ContainsFunc(n.Lhs, is)
+			case *ast.SelectorExpr:
+				if is(n.X) {
+					deref = n
+				}
+			case *ast.StarExpr:
+				if is(n.X) {
+					deref = n
+				}
+			}
+			return true
+		})
+		if deref != nil || assigned {
+			break
+		}
+	}
+	return deref
+}
+
+// guardFix returns the fix that moves the statements of stmts after
+// stmts[i], up to the last one that uses the variable of ig, into an if
+// statement on the result ig ignores:
+//
+//	if errors.As(err, &pathErr) {
+//		log.Print(pathErr.Path)
+//	}
+//
+//	conn, ok := c.(*net.TCPConn)
+//	if ok {
+//		conn.SetKeepAlive(true)
+//	}
+//
+// It returns nil if the moved statements declare names used after them,
+// end a function with results, or hold multi-line raw strings, which
+// cannot be indented, or if ok is taken.
+func guardFix(pass *analysis.Pass, stmts []ast.Stmt, i int, ig *ignoredOK, results *types.Tuple) []analysis.SuggestedFix {
+	last := i
+	for j := i + 1; j < len(stmts); j++ {
+		if usesVar(pass, stmts[j], ig.v) {
+			last = j
+		}
+	}
+	moved, after := stmts[i+1:last+1], stmts[last+1:]
+	if len(moved) == 0 || len(after) == 0 && results != nil && results.Len() > 0 {
+		return nil
+	}
+	for _, stmt := range moved {
+		if declaresUsed(pass, stmt, after) || hasRawNewline(stmt) {
+			return nil
+		}
+	}
+
+	head := stmts[i]
+	tf := pass.Fset.File(head.Pos())
+	line := tf.Line(head.End())
+	if line >= tf.LineCount() || tf.LineStart(line+1) > moved[0].Pos() {
+		return nil
+	}
+	eol := tf.LineStart(line+1) - 1
+	body, ok := source(pass, eol, moved[len(moved)-1].End())
+	if !ok {
+		return nil
+	}
+	indent := indentation(pass, head.Pos())
+	lines := strings.Split(body, "\n")
+	for k := 1; k < len(lines); k++ {
+		if lines[k] != "" {
+			lines[k] = "\t" + lines[k]
+		}
+	}
+	body = strings.Join(lines, "\n") + "\n" + indent + "}"
+
+	var edits []analysis.TextEdit
+	if ig.assert == nil {
+		edits = []analysis.TextEdit{
+			{Pos: head.Pos(), End: head.End(), NewText: []byte("if " + render(pass, ig.expr) + " {")},
+			{Pos: eol, End: moved[len(moved)-1].End(), NewText: []byte(body)},
+		}
+	} else {
+		if !ig.define {
+			return nil
+		}
+		if scope := pass.Pkg.Scope().Innermost(head.Pos()); scope == nil {
+			return nil
+		} else if _, obj := scope.LookupParent("ok", head.Pos()); obj != nil || declaresName(pass, stmts[i+1:], "ok") {
+			return nil
+		}
+		edits = []analysis.TextEdit{
+			{Pos: ig.blank.Pos(), End: ig.blank.End(), NewText: []byte("ok")},
+			{Pos: eol, End: moved[len(moved)-1].End(), NewText: []byte("\n" + indent + "if ok {" + body)},
+		}
+	}
+	return []analysis.SuggestedFix{{
+		Message:   "Check the result before using " + ig.v.Name(),
+		TextEdits: edits,
+	}}
+}
+
+// declaresUsed reports whether stmt declares a name that stmts use.
+func declaresUsed(pass *analysis.Pass, stmt ast.Stmt, stmts []ast.Stmt) bool {
+	found := false
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			if v, ok := pass.TypesInfo.Defs[id].(*types.Var); ok {
+				found = slices.ContainsFunc(stmts, func(s ast.Stmt) bool { return usesVar(pass, s, v) })
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// declaresName reports whether stmts declare a variable called name.
+func declaresName(pass *analysis.Pass, stmts []ast.Stmt, name string) bool {
+	for _, stmt := range stmts {
+		found := false
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok && id.Name == name && pass.TypesInfo.Defs[id] != nil {
+				found = true
+			}
+			return !found
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// hasRawNewline reports whether n holds a raw string literal that spans
+// several lines.
+func hasRawNewline(n ast.Node) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		if lit, ok := n.(*ast.BasicLit); ok && lit.Kind == token.STRING && strings.HasPrefix(lit.Value, "`") && strings.Contains(lit.Value, "\n") {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
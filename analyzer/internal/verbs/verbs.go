@@ -0,0 +1,169 @@
+// Package verbs parses the directives of printf-style format strings the
+// way package fmt does.
+package verbs
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Verb is a single formatting directive in a printf-style format string.
+type Verb struct {
+	// Start and End are the byte offsets of the directive, including the
+	// leading '%', within the format string.
+	Start, End int
+	// Verb is the verb character, such as 'v' or 'w'.
+	Verb rune
+	// Arg is the index of the operand the verb formats, relative to the
+	// first argument after the format string, or -1 if the directive has a
+	// bad argument index. An Arg of nargs or more means the operand is
+	// missing.
+	Arg int
+}
+
+// Parse returns the directives of format that consume an operand, when
+// format is followed by nargs operands. It follows the argument numbering
+// rules of package fmt: each '*' width or precision consumes an operand,
+// an explicit index such as %[2]v resets the position for the directives
+// that follow, and a directive with a malformed or out of range index,
+// which fmt formats as %!v(BADINDEX), consumes none.
+func Parse(format string, nargs int) []Verb {
+	var verbs []Verb
+	argNum := 0
+	end := len(format)
+	for i := 0; i < end; {
+		if format[i] != '%' {
+			i++
+			continue
+		}
+		start := i
+		i++
+
+		for i < end && strings.IndexByte("+-# 0", format[i]) >= 0 {
+			i++
+		}
+
+		good := true
+		var afterIndex, ok bool
+		argNum, i, afterIndex, ok = argNumber(format, i, argNum, nargs)
+		good = good && ok
+
+		// Width.
+		if i < end && format[i] == '*' {
+			i++
+			if argNum < nargs {
+				argNum++
+			}
+			afterIndex = false
+		} else {
+			var present bool
+			present, i = parseNum(format, i, end)
+			if afterIndex && present { // "%[3]2d"
+				good = false
+			}
+		}
+
+		// Precision. A '.' ending the format is the verb.
+		if i+1 < end && format[i] == '.' {
+			i++
+			if afterIndex { // "%[3].2d"
+				good = false
+			}
+			argNum, i, afterIndex, ok = argNumber(format, i, argNum, nargs)
+			good = good && ok
+			if i < end && format[i] == '*' {
+				i++
+				if argNum < nargs {
+					argNum++
+				}
+				afterIndex = false
+			} else {
+				_, i = parseNum(format, i, end)
+			}
+		}
+
+		if !afterIndex {
+			argNum, i, _, ok = argNumber(format, i, argNum, nargs)
+			good = good && ok
+		}
+
+		if i >= end {
+			// Missing verb, formatted as %!(NOVERB).
+			break
+		}
+		r, size := utf8.DecodeRuneInString(format[i:])
+		i += size
+
+		switch {
+		case r == '%':
+		case !good:
+			verbs = append(verbs, Verb{Start: start, End: i, Verb: r, Arg: -1})
+		default:
+			// A missing operand, formatted as %!v(MISSING), does not
+			// advance the position either.
+			verbs = append(verbs, Verb{Start: start, End: i, Verb: r, Arg: argNum})
+			if argNum < nargs {
+				argNum++
+			}
+		}
+	}
+	return verbs
+}
+
+// argNumber parses an explicit argument index such as "[2]" at format[i:].
+// It returns the new zero-based argument number, the position after the
+// index, whether a well-formed index was present, and whether the index,
+// if any, refers to one of the nargs operands.
+func argNumber(format string, i, argNum, nargs int) (int, int, bool, bool) {
+	if i >= len(format) || format[i] != '[' {
+		return argNum, i, false, true
+	}
+	index, width, ok := parseArgNumber(format[i:])
+	if ok && 0 <= index && index < nargs {
+		return index, i + width, true, true
+	}
+	return argNum, i + width, ok, false
+}
+
+// parseArgNumber parses the index at the start of format, which begins
+// with '['. It returns the zero-based index, the length of the index
+// including its brackets, and whether it is well formed.
+func parseArgNumber(format string) (int, int, bool) {
+	if len(format) < 3 {
+		return 0, 1, false
+	}
+	end := strings.IndexByte(format, ']')
+	if end < 0 {
+		return 0, 1, false
+	}
+	n := 0
+	for i := 1; i < end; i++ {
+		if format[i] < '0' || '9' < format[i] || tooLarge(n) {
+			return 0, end + 1, false
+		}
+		n = n*10 + int(format[i]-'0')
+	}
+	if end == 1 {
+		return 0, end + 1, false
+	}
+	return n - 1, end + 1, true
+}
+
+// parseNum skips the number at format[i:end], such as a width. It reports
+// whether there was one and returns the position after it; like fmt, it
+// gives up at end on absurdly long numbers.
+func parseNum(format string, i, end int) (bool, int) {
+	n, present := 0, false
+	for ; i < end && '0' <= format[i] && format[i] <= '9'; i++ {
+		if tooLarge(n) {
+			return false, end
+		}
+		n = n*10 + int(format[i]-'0')
+		present = true
+	}
+	return present, i
+}
+
+func tooLarge(n int) bool {
+	return n > 1e6
+}
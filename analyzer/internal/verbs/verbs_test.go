@@ -0,0 +1,162 @@
+package verbs
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// corpus lists format strings that exercise the corners of the argument
+// numbering rules of package fmt.
+var corpus = []string{
+	"",
+	"%",
+	"%%",
+	"%v",
+	"%w %v",
+	"%[2]v %[1]v",
+	"%[3]*.[2]v",
+	"%[2]*[1]d",
+	"%.*v",
+	"%*.*v",
+	"%[1]*v %v",
+	"%-+# 05.3v",
+	"%[0]v %v",
+	"%[-1]v",
+	"%[-1]v %v",
+	"%[x]v %v",
+	"%[1v",
+	"%[1]",
+	"%[1].v",
+	"%[2]",
+	"%.[2]v",
+	"%[1]*.[1]*v",
+	"%[99]v",
+	"%5%",
+	"%!",
+	"%é",
+	"%[1]v %[1]w %[1]s",
+	"%v %[1]v %v",
+	"%[1]2v %v",
+	"%[2].3v %v",
+	"%.",
+	"%[]v %v",
+	"%[1]%v",
+	"%[5]v %v",
+	"%v %v %v %v %v",
+	"%99999999v %v",
+	"%[99999999]v %v",
+}
+
+// maxOperands is the largest number of operands a format string is
+// formatted with; the indexes in corpus go beyond it.
+const maxOperands = 3
+
+// use is an operand formatted by a directive.
+type use struct {
+	verb rune
+	arg  int
+}
+
+// probe is an operand that records which verbs format it. It is an error
+// so that fmt.Errorf accepts it for %w, which it formats as %v.
+type probe struct {
+	arg  int
+	uses *[]use
+}
+
+func (p probe) Error() string {
+	return fmt.Sprintf("<p%d>", p.arg)
+}
+
+func (p probe) Format(s fmt.State, verb rune) {
+	*p.uses = append(*p.uses, use{verb: verb, arg: p.arg})
+	fmt.Fprintf(s, "<p%d>", p.arg)
+}
+
+// result is what formatting does with the operands of a format string.
+type result struct {
+	uses []use
+	// missing and bad count the directives formatted as %!v(MISSING)
+	// and %!v(BADINDEX).
+	missing, bad int
+}
+
+func (r result) String() string {
+	parts := make([]string, len(r.uses))
+	for i, u := range r.uses {
+		parts[i] = fmt.Sprintf("%%%c(%d)", u.verb, u.arg)
+	}
+	return fmt.Sprintf("[%s], %d missing, %d bad index", strings.Join(parts, " "), r.missing, r.bad)
+}
+
+// formatted returns what fmt.Errorf does with format and nargs operands.
+func formatted(format string, nargs int) result {
+	var r result
+	args := make([]any, nargs)
+	for i := range args {
+		args[i] = probe{arg: i, uses: &r.uses}
+	}
+	out := fmt.Errorf(format, args...).Error()
+
+	// fmt formats unused operands with %v after "%!(EXTRA"; they are not
+	// consumed by directives.
+	if i := strings.LastIndex(out, "%!(EXTRA "); i >= 0 {
+		r.uses = r.uses[:len(r.uses)-strings.Count(out[i:], "<p")]
+	}
+	r.missing = strings.Count(out, "(MISSING)")
+	r.bad = strings.Count(out, "(BADINDEX)")
+	return r
+}
+
+// parsed returns what Parse says fmt.Errorf does with format and nargs
+// operands.
+func parsed(format string, nargs int) result {
+	var r result
+	for _, v := range Parse(format, nargs) {
+		switch {
+		case v.Arg < 0:
+			r.bad++
+		case v.Arg >= nargs:
+			r.missing++
+		default:
+			verb := v.Verb
+			if verb == 'w' {
+				verb = 'v'
+			}
+			r.uses = append(r.uses, use{verb: verb, arg: v.Arg})
+		}
+	}
+	return r
+}
+
+// FuzzVerbs checks that Parse assigns operands to directives exactly like
+// fmt.Errorf, on format strings derived from corpus, with up to
+// maxOperands operands. It formats operands that record which verb
+// formatted them, and compares that with the directives and operand
+// indexes Parse returns; it also compares the directives fmt formats as
+// missing or with a bad index. Run it with
+//
+//	go test ./analyzer/internal/verbs -fuzz FuzzVerbs
+//
+// Without -fuzz, go test checks the corpus.
+func FuzzVerbs(f *testing.F) {
+	for _, format := range corpus {
+		for nargs := range maxOperands + 1 {
+			f.Add(format, uint8(nargs))
+		}
+	}
+	f.Fuzz(func(t *testing.T, format string, n uint8) {
+		nargs := int(n) % (maxOperands + 1)
+		// fmt formats %T and %p without calling the Format method the
+		// operands record their use with.
+		if slices.ContainsFunc(Parse(format, nargs), func(v Verb) bool { return v.Verb == 'T' || v.Verb == 'p' }) {
+			t.Skip("format string has a T or p verb")
+		}
+		want, got := formatted(format, nargs), parsed(format, nargs)
+		if !slices.Equal(want.uses, got.uses) || want.missing != got.missing || want.bad != got.bad {
+			t.Errorf("%q, %d operands:\n\tfmt formats   %v\n\tParse returns %v", format, nargs, want, got)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// checkInternalTypes reports exported functions that return errors of a
+// type declared in an internal package, as a result of that type or as an
+// error. Callers outside the tree of the internal package cannot import
+// it, so they can neither match the error with errors.As nor read its
+// fields, only its message. Packages that are internal to the same tree
+// share their callers with it, and are left alone.
+func (l *linter) checkInternalTypes(pass *analysis.Pass, insp *inspector.Inspector) {
+	if pass.Pkg.Name() == "main" {
+		return
+	}
+	insp.WithStack([]ast.Node{(*ast.FuncDecl)(nil), (*ast.ReturnStmt)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		if decl, ok := n.(*ast.FuncDecl); ok {
+			fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func)
+			if !ok || !exportedFunc(fn) || decl.Type.Results == nil {
+				return true
+			}
+			for _, field := range decl.Type.Results.List {
+				if named := internalErrorType(pass, pass.TypesInfo.TypeOf(field.Type)); named != nil {
+					pass.Reportf(field.Type.Pos(), "%s returns the error type %s of internal package %s, which its callers cannot import; declare the result as error and return an exported error or sentinel of package %s", funcName(fn), typeName(pass, field.Type), named.Obj().Pkg().Path(), pass.Pkg.Name())
+				}
+			}
+			return true
+		}
+
+		ret := n.(*ast.ReturnStmt)
+		fn, results := enclosingSignature(pass, stack)
+		if fn == nil || !exportedFunc(fn) || len(ret.Results) != results.Len() {
+			return true
+		}
+		for i, expr := range ret.Results {
+			if t := results.At(i).Type(); !types.IsInterface(t) || !types.Implements(t, errorIface) {
+				continue
+			}
+			if named := internalErrorType(pass, pass.TypesInfo.TypeOf(expr)); named != nil {
+				pass.Reportf(expr.Pos(), "%s returns an error of type %s from internal package %s, which its callers cannot import to match it with errors.As; translate it into an exported error or sentinel of package %s", funcName(fn), typeName(pass, expr), named.Obj().Pkg().Path(), pass.Pkg.Name())
+			}
+		}
+		return true
+	})
+}
+
+// exportedFunc reports whether fn can be called from other packages: it
+// is exported and, for a method, so is the type of its receiver.
+func exportedFunc(fn *types.Func) bool {
+	if !fn.Exported() {
+		return false
+	}
+	recv := fn.Signature().Recv()
+	if recv == nil {
+		return true
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Exported()
+}
+
+// internalErrorType returns the named type of t, or of what t points to,
+// if it is a concrete error type of an internal package that some
+// importers of the package of pass cannot import, or nil.
+func internalErrorType(pass *analysis.Pass, t types.Type) *types.Named {
+	if t == nil || types.IsInterface(t) || !types.Implements(t, errorIface) {
+		return nil
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg() == pass.Pkg {
+		return nil
+	}
+	root, ok := internalRoot(named.Obj().Pkg().Path())
+	if !ok {
+		return nil
+	}
+	// A package internal to the same tree is only imported from within it.
+	if own, ok := internalRoot(pass.Pkg.Path()); ok && (own == root || strings.HasPrefix(own, root+"/")) {
+		return nil
+	}
+	return named
+}
+
+// internalRoot returns the path of the tree whose packages may import the
+// package path, the parent of its last internal element, and reports
+// whether it has one.
+func internalRoot(path string) (string, bool) {
+	if path == "internal" || strings.HasPrefix(path, "internal/") {
+		path = "/" + path
+	}
+	i := strings.LastIndex(path+"/", "/internal/")
+	if i < 0 {
+		return "", false
+	}
+	return path[:i], true
+}
+
+// typeName returns the type of expr qualified by package names, as in
+// *storage.NotFoundError.
+func typeName(pass *analysis.Pass, expr ast.Expr) string {
+	return types.TypeString(pass.TypesInfo.TypeOf(expr), func(pkg *types.Package) string {
+		if pkg == pass.Pkg {
+			return ""
+		}
+		return pkg.Name()
+	})
+}
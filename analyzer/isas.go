@@ -0,0 +1,178 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// checkIsAs reports calls of errors.Is and errors.As whose arguments make
+// them useless or make them panic: a nil target or error for errors.Is, and
+// for errors.As a target that is not a non-nil pointer to an error type, a
+// *error target, which matches any error, or a target pointing to the
+// error itself.
+func (l *linter) checkIsAs(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		call := n.(*ast.CallExpr)
+		if len(call.Args) != 2 || call.Ellipsis.IsValid() {
+			return true
+		}
+		switch {
+		case isFunc(pass, call, "errors", "Is"):
+			checkIs(pass, call, stack)
+		case isFunc(pass, call, "errors", "As"):
+			checkAs(pass, call, stack)
+		}
+		return true
+	})
+}
+
+func checkIs(pass *analysis.Pass, call *ast.CallExpr, stack []ast.Node) {
+	err, target := call.Args[0], call.Args[1]
+	switch {
+	case isNil(pass, target) && !isNil(pass, err):
+		// !errors.Is(err, nil) becomes err != nil rather than !err == nil.
+		var node ast.Node = call
+		op := token.EQL
+		if i := len(stack) - 2; i >= 0 {
+			if not, ok := stack[i].(*ast.UnaryExpr); ok && not.Op == token.NOT {
+				node, op = not, token.NEQ
+			}
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:     call.Pos(),
+			End:     call.End(),
+			Message: fmt.Sprintf("errors.Is with a nil target only reports whether %s is nil; use %s == nil", render(pass, err), render(pass, err)),
+			SuggestedFixes: []analysis.SuggestedFix{{
+				Message: "Compare with nil",
+				TextEdits: []analysis.TextEdit{{
+					Pos:     node.Pos(),
+					End:     node.End(),
+					NewText: fmt.Appendf(nil, "%s %s nil", render(pass, err), op),
+				}},
+			}},
+		})
+	case isNil(pass, err) && !isNil(pass, target):
+		pass.Reportf(call.Pos(), "errors.Is with a nil error never matches %s; the error to inspect comes first", render(pass, target))
+	}
+}
+
+func checkAs(pass *analysis.Pass, call *ast.CallExpr, stack []ast.Node) {
+	err, target := call.Args[0], ast.Unparen(call.Args[1])
+	t := pass.TypesInfo.TypeOf(target)
+	if t == nil || isNil(pass, target) {
+		return
+	}
+
+	// errors.As(err, &x) with x of type error.
+	if addr, ok := target.(*ast.UnaryExpr); ok && addr.Op == token.AND {
+		if xt := pass.TypesInfo.TypeOf(addr.X); xt != nil && types.Identical(xt, errorType) {
+			if sameVar(pass, addr.X, err) {
+				pass.Reportf(target.Pos(), "errors.As(%s, &%s) always matches and sets %s to itself; declare a target of the error type to look for", render(pass, err), render(pass, addr.X), render(pass, addr.X))
+				return
+			}
+			pass.Reportf(target.Pos(), "errors.As with a *error target matches any error and sets it to %s; use a concrete error type, or an interface such as net.Error", render(pass, err))
+			return
+		}
+	}
+
+	ptr, ok := t.Underlying().(*types.Pointer)
+	if !ok {
+		if !types.IsInterface(t) {
+			pass.Reportf(target.Pos(), "errors.As panics unless its target is a non-nil pointer; pass a pointer to a variable of the error type, such as &target")
+		}
+		return
+	}
+	if id, ok := target.(*ast.Ident); ok && neverAssigned(pass, id, stack) {
+		pass.Report(analysis.Diagnostic{
+			Pos:     target.Pos(),
+			End:     target.End(),
+			Message: fmt.Sprintf("errors.As panics: %s is a nil %s; pass &%s", id.Name, types.TypeString(t, types.RelativeTo(pass.Pkg)), id.Name),
+			SuggestedFixes: []analysis.SuggestedFix{{
+				Message:   "Pass a pointer to " + id.Name,
+				TextEdits: []analysis.TextEdit{{Pos: id.Pos(), End: id.Pos(), NewText: []byte("&")}},
+			}},
+		})
+		return
+	}
+	if elem := ptr.Elem(); !types.IsInterface(elem) && !types.Implements(elem, errorIface) {
+		msg := fmt.Sprintf("errors.As panics: its target points to %s, which does not implement error", types.TypeString(elem, types.RelativeTo(pass.Pkg)))
+		if types.Implements(types.NewPointer(elem), errorIface) {
+			msg += fmt.Sprintf("; declare the target as %s and pass its address", types.TypeString(types.NewPointer(elem), types.RelativeTo(pass.Pkg)))
+		}
+		pass.Reportf(target.Pos(), "%s", msg)
+	}
+}
+
+// sameVar reports whether x and y refer to the same variable.
+func sameVar(pass *analysis.Pass, x, y ast.Expr) bool {
+	xid, ok := ast.Unparen(x).(*ast.Ident)
+	yid, ok2 := ast.Unparen(y).(*ast.Ident)
+	return ok && ok2 && pass.TypesInfo.ObjectOf(xid) != nil && pass.TypesInfo.ObjectOf(xid) == pass.TypesInfo.ObjectOf(yid)
+}
+
+// neverAssigned reports whether id refers to a local variable declared
+// without a value, such as var target *T, that the function declaration of
+// stack does not assign or take the address of before id, or in a loop
+// around it, so that it is still nil.
+func neverAssigned(pass *analysis.Pass, id *ast.Ident, stack []ast.Node) bool {
+	v, ok := pass.TypesInfo.Uses[id].(*types.Var)
+	if !ok {
+		return false
+	}
+	var decl *ast.FuncDecl
+	for _, n := range stack {
+		if fd, ok := n.(*ast.FuncDecl); ok {
+			decl = fd
+			break
+		}
+	}
+	if decl == nil || decl.Body == nil || v.Pos() < decl.Body.Pos() || v.Pos() >= decl.Body.End() {
+		return false
+	}
+	var loops []ast.Node
+	for _, n := range stack {
+		switch n.(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			loops = append(loops, n)
+		}
+	}
+	is := func(expr ast.Expr) bool {
+		x, ok := ast.Unparen(expr).(*ast.Ident)
+		if !ok || pass.TypesInfo.ObjectOf(x) != v {
+			return false
+		}
+		return x.Pos() < id.Pos() || slices.ContainsFunc(loops, func(loop ast.Node) bool { return loop.Pos() <= x.Pos() && x.Pos() < loop.End() })
+	}
+	zero, assigned := false, false
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			for _, name := range n.Names {
+				if pass.TypesInfo.Defs[name] == v {
+					zero = len(n.Values) == 0
+				}
+			}
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				assigned = assigned || is(lhs)
+			}
+		case *ast.IncDecStmt:
+			assigned = assigned || is(n.X)
+		case *ast.UnaryExpr:
+			assigned = assigned || n.Op == token.AND && is(n.X)
+		case *ast.RangeStmt:
+			assigned = assigned || n.Key != nil && is(n.Key) || n.Value != nil && is(n.Value)
+		}
+		return !assigned
+	})
+	return zero && !assigned
+}
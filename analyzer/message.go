@@ -0,0 +1,73 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// stringMatchers are the functions of package strings that match the
+// message of an error against text.
+var stringMatchers = []string{"Contains", "HasPrefix", "HasSuffix", "EqualFold"}
+
+// checkMessages reports the message of an error, err.Error(), matched
+// against text with strings.Contains and similar functions or compared
+// with == to a string constant. Messages change and grow as errors are
+// wrapped; errors.Is and errors.As match the errors themselves.
+func (l *linter) checkMessages(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil), (*ast.BinaryExpr)(nil)}, func(n ast.Node) {
+		if inIsMethod(pass, n.Pos()) {
+			return
+		}
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			for _, name := range stringMatchers {
+				if !isFunc(pass, n, "strings", name) {
+					continue
+				}
+				for _, arg := range n.Args {
+					if err, ok := errorMessage(pass, arg); ok {
+						pass.Reportf(arg.Pos(), "matching the message of %s with strings.%s breaks when the message changes; use errors.Is or errors.As", render(pass, err), name)
+					}
+				}
+			}
+		case *ast.BinaryExpr:
+			if n.Op != token.EQL && n.Op != token.NEQ {
+				return
+			}
+			for _, pair := range [][2]ast.Expr{{n.X, n.Y}, {n.Y, n.X}} {
+				err, ok := errorMessage(pass, pair[0])
+				if tv, isConst := pass.TypesInfo.Types[pair[1]]; ok && isConst && tv.Value != nil {
+					pass.Report(analysis.Diagnostic{
+						Pos:     n.Pos(),
+						End:     n.End(),
+						Message: fmt.Sprintf("comparing the message of %s with %s breaks when the message changes or the error is wrapped; use errors.Is or errors.As", render(pass, err), n.Op),
+					})
+					return
+				}
+			}
+		}
+	})
+}
+
+// errorMessage reports whether expr is a call of the Error method of an
+// error value, and returns the error.
+func errorMessage(pass *analysis.Pass, expr ast.Expr) (ast.Expr, bool) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 {
+		return nil, false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Error" || !isError(pass, sel.X) {
+		return nil, false
+	}
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Signature().Recv() == nil {
+		return nil, false
+	}
+	return sel.X, true
+}
@@ -0,0 +1,53 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// checkSentinelNames reports exported package-level error variables whose
+// name does not start with Err, such as NotFound instead of ErrNotFound.
+func (l *linter) checkSentinelNames(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.Preorder([]ast.Node{(*ast.GenDecl)(nil)}, func(n ast.Node) {
+		decl := n.(*ast.GenDecl)
+		if decl.Tok != token.VAR {
+			return
+		}
+		for _, spec := range decl.Specs {
+			for _, name := range spec.(*ast.ValueSpec).Names {
+				v, ok := pass.TypesInfo.Defs[name].(*types.Var)
+				if !ok || v.Parent() != pass.Pkg.Scope() || !v.Exported() || !types.Implements(v.Type(), errorIface) {
+					continue
+				}
+				if !strings.HasPrefix(v.Name(), "Err") {
+					base := strings.TrimSuffix(strings.TrimSuffix(v.Name(), "Error"), "Err")
+					pass.Reportf(name.Pos(), "sentinel error %s should be named Err%s, so readers recognize it at the call site", v.Name(), base)
+				}
+			}
+		}
+	})
+}
+
+// checkTypeNames reports exported error types whose name does not end in
+// Error, such as NotFound instead of NotFoundError.
+func (l *linter) checkTypeNames(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.Preorder([]ast.Node{(*ast.TypeSpec)(nil)}, func(n ast.Node) {
+		spec := n.(*ast.TypeSpec)
+		tn, ok := pass.TypesInfo.Defs[spec.Name].(*types.TypeName)
+		if !ok || tn.Parent() != pass.Pkg.Scope() || !tn.Exported() || tn.IsAlias() {
+			return
+		}
+		t := tn.Type()
+		if types.IsInterface(t) || !types.Implements(t, errorIface) && !types.Implements(types.NewPointer(t), errorIface) {
+			return
+		}
+		if !strings.HasSuffix(tn.Name(), "Error") {
+			pass.Reportf(spec.Name.Pos(), "error type %s should be named %sError, so readers recognize it at the call site", tn.Name(), strings.TrimSuffix(tn.Name(), "Err"))
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// checkNewCompares reports comparisons with == or != and calls of errors.Is
+// whose operand is an error created by errors.New, or by fmt.Errorf without
+// %w, in the function: either the call itself or a local variable holding
+// its result. Every call creates a new value, so the comparison never
+// matches an error created elsewhere, even one with the same message.
+func (l *linter) checkNewCompares(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.Preorder([]ast.Node{(*ast.BinaryExpr)(nil), (*ast.CallExpr)(nil)}, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.BinaryExpr:
+			if n.Op != token.EQL && n.Op != token.NEQ || isNil(pass, n.X) || isNil(pass, n.Y) {
+				return
+			}
+			if x, y := localVar(pass, n.X), localVar(pass, n.Y); x != nil && x == y {
+				return
+			}
+			for _, operand := range []ast.Expr{n.X, n.Y} {
+				if v, name, ok := l.newError(pass, operand); ok {
+					if v != nil {
+						pass.Reportf(n.Pos(), "%s is a new error from %s, so comparing with %s never matches an error created elsewhere, not even one with the same message; compare with a package-level sentinel", v.Name(), name, n.Op)
+					} else {
+						pass.Reportf(n.Pos(), "comparing with a new error from %s never matches, not even an error with the same message; compare with a package-level sentinel", name)
+					}
+					return
+				}
+			}
+		case *ast.CallExpr:
+			if len(n.Args) != 2 || !isFunc(pass, n, "errors", "Is") {
+				return
+			}
+			if v, name, ok := l.newError(pass, n.Args[1]); ok {
+				if v != nil {
+					pass.Reportf(n.Pos(), "%s is a new error from %s, so errors.Is with it never matches an error created elsewhere, not even one with the same message; compare with a package-level sentinel", v.Name(), name)
+				} else {
+					pass.Reportf(n.Pos(), "errors.Is with a new error from %s never matches, not even an error with the same message; compare with a package-level sentinel", name)
+				}
+			}
+		}
+	})
+}
+
+// newError reports whether expr is a new error: a call of errors.New or of
+// fmt.Errorf without %w, or a local variable that only ever holds the
+// result of such a call and is only compared. It returns the variable, if
+// expr is one, and the name of the function that creates the error.
+func (l *linter) newError(pass *analysis.Pass, expr ast.Expr) (*types.Var, string, bool) {
+	if name, ok := newErrorCall(pass, expr); ok {
+		return nil, name, true
+	}
+	if v := localVar(pass, expr); v != nil {
+		if name, ok := l.newErrors[v]; ok {
+			return v, name, true
+		}
+	}
+	return nil, "", false
+}
+
+// newErrorCall returns the name of the function, errors.New or fmt.Errorf
+// without %w, that expr calls to create a new error.
+func newErrorCall(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	switch {
+	case !ok:
+		return "", false
+	case isFunc(pass, call, "errors", "New"):
+		return "errors.New", true
+	case isFunc(pass, call, "fmt", "Errorf") && !wraps(pass, call):
+		return "fmt.Errorf without %w", true
+	}
+	return "", false
+}
+
+// localVar returns the local variable that expr refers to, or nil.
+func localVar(pass *analysis.Pass, expr ast.Expr) *types.Var {
+	id, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	v, ok := pass.TypesInfo.Uses[id].(*types.Var)
+	if !ok || v.Parent() == nil || v.Parent() == pass.Pkg.Scope() {
+		return nil
+	}
+	return v
+}
+
+// findNewErrors returns the local error variables of the package that are
+// only assigned new errors, from errors.New or fmt.Errorf without %w, and
+// are only compared with == or !=, passed to errors.Is or asked for their
+// message, mapped to the name of the function that creates their errors.
+// No other error can hold the value of such a variable, so comparisons with
+// it never match. Variables used in any other way, for example passed to a
+// function that may return them, are left out.
+func findNewErrors(pass *analysis.Pass, insp *inspector.Inspector) map[*types.Var]string {
+	names := make(map[*types.Var]string)
+	escaped := make(map[*types.Var]bool)
+	insp.WithStack([]ast.Node{(*ast.Ident)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		id := n.(*ast.Ident)
+		v, ok := pass.TypesInfo.ObjectOf(id).(*types.Var)
+		if !ok || v.IsField() || v.Parent() == nil || v.Parent() == pass.Pkg.Scope() || !isErrorVar(v) {
+			return true
+		}
+		name, ok := newErrorUse(pass, id, stack)
+		switch {
+		case !ok:
+			escaped[v] = true
+		case name != "" && names[v] == "":
+			names[v] = name
+		}
+		return true
+	})
+	for v := range names {
+		if escaped[v] {
+			delete(names, v)
+		}
+	}
+	return names
+}
+
+// newErrorUse classifies id, the last node of stack, which refers to a
+// local error variable. It returns the name of the function that creates
+// the new error assigned to the variable, or "" for a use that keeps its
+// value local, and false for any other use.
+func newErrorUse(pass *analysis.Pass, id *ast.Ident, stack []ast.Node) (string, bool) {
+	i := len(stack) - 2
+	for i > 0 {
+		if _, ok := stack[i].(*ast.ParenExpr); !ok {
+			break
+		}
+		i--
+	}
+	switch parent := stack[i].(type) {
+	case *ast.AssignStmt:
+		if parent.Tok != token.DEFINE && parent.Tok != token.ASSIGN || len(parent.Lhs) != len(parent.Rhs) {
+			return "", false
+		}
+		for j, lhs := range parent.Lhs {
+			if lhs == id {
+				return newErrorCall(pass, parent.Rhs[j])
+			}
+		}
+	case *ast.ValueSpec:
+		for j, name := range parent.Names {
+			if name == id && len(parent.Values) == len(parent.Names) {
+				return newErrorCall(pass, parent.Values[j])
+			}
+		}
+	case *ast.BinaryExpr:
+		if parent.Op == token.EQL || parent.Op == token.NEQ {
+			return "", true
+		}
+	case *ast.CallExpr:
+		if isFunc(pass, parent, "errors", "Is") {
+			return "", true
+		}
+	case *ast.SelectorExpr:
+		// err.Error() gives away the message, not the value.
+		if call, ok := stack[i-1].(*ast.CallExpr); ok && call.Fun == parent && parent.Sel.Name == "Error" {
+			return "", true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,278 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer/internal/verbs"
+)
+
+// checkNilErrors reports calls of the Error method of a local error
+// variable that is nil where it is called, which panic, and the variable
+// formatted with %s, %q or %w, which fmt formats as %!s(<nil>). The
+// variable is known to be nil in the branch of an if statement or a case of
+// a tagless switch whose condition says so, and after an if statement that
+// leaves the block if it is not nil, the common early return shape:
+//
+//	if err != nil {
+//		return err
+//	}
+//	log.Print(err.Error())
+//
+// as long as it is not assigned in between. The check also reports calls
+// of the Error method of an error just returned by a call and only checked
+// for nil after it:
+//
+//	data, err := load()
+//	log.Print(err.Error())
+//	if err != nil {
+//
+// Variables whose address is taken, or that a closure assigns, may change
+// at any time and are left alone.
+func (l *linter) checkNilErrors(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		call := n.(*ast.CallExpr)
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Error" && len(call.Args) == 0 {
+			v := localVar(pass, sel.X)
+			if v == nil || !isErrorVar(v) || escapes(pass, stack, v) {
+				return true
+			}
+			if check := knownNil(pass, v, stack); check != nil {
+				pass.Reportf(call.Pos(), "%s.Error() panics: %s is always nil here, after the nil check at line %d", v.Name(), v.Name(), pass.Fset.Position(check.Pos()).Line)
+			} else if check := checkedAfter(pass, v, stack); check != nil {
+				pass.Reportf(call.Pos(), "%s.Error() is called before %s is checked for nil at line %d, and panics if %s is nil; check %s first", v.Name(), v.Name(), pass.Fset.Position(check.Pos()).Line, v.Name(), v.Name())
+			}
+			return true
+		}
+
+		format, ok := formatIndex(pass, call)
+		if !ok || len(call.Args) <= format || call.Ellipsis.IsValid() {
+			return true
+		}
+		text, ok := constantString(pass, call.Args[format])
+		if !ok {
+			return true
+		}
+		args := call.Args[format+1:]
+		for _, verb := range verbs.Parse(text, len(args)) {
+			if verb.Verb != 's' && verb.Verb != 'q' && verb.Verb != 'w' || verb.Arg < 0 || verb.Arg >= len(args) {
+				continue
+			}
+			v := localVar(pass, args[verb.Arg])
+			if v == nil || !isErrorVar(v) || escapes(pass, stack, v) {
+				continue
+			}
+			if check := knownNil(pass, v, stack); check != nil {
+				pass.Reportf(args[verb.Arg].Pos(), "%s is always nil here, after the nil check at line %d, so %%%c formats it as %%!%c(<nil>)", v.Name(), pass.Fset.Position(check.Pos()).Line, verb.Verb, verb.Verb)
+			}
+		}
+		return true
+	})
+}
+
+// formatIndex returns the index of the format argument of call if it calls
+// one of the printf-like functions of packages fmt and log.
+func formatIndex(pass *analysis.Pass, call *ast.CallExpr) (int, bool) {
+	for _, name := range []string{"Errorf", "Sprintf", "Printf"} {
+		if isFunc(pass, call, "fmt", name) {
+			return 0, true
+		}
+	}
+	if isFunc(pass, call, "fmt", "Fprintf") {
+		return 1, true
+	}
+	for _, name := range []string{"Printf", "Fatalf", "Panicf"} {
+		if isFunc(pass, call, "log", name) {
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+// knownNil returns the if statement or case clause that makes v nil where
+// the node that ends stack is evaluated, or nil if v may hold an error
+// there.
+func knownNil(pass *analysis.Pass, v *types.Var, stack []ast.Node) ast.Node {
+	for i := len(stack) - 2; i >= 0; i-- {
+		child := stack[i+1]
+		var stmts []ast.Stmt
+		switch n := stack[i].(type) {
+		case *ast.FuncLit, *ast.FuncDecl:
+			return nil
+		case *ast.ForStmt, *ast.RangeStmt:
+			// The assignments in the loop reach the next iteration.
+			if assignsVar(pass, n, v) {
+				return nil
+			}
+		case *ast.IfStmt:
+			if child == n.Body && impliesNil(pass, n.Cond, v, true) || child == n.Else && impliesNil(pass, n.Cond, v, false) {
+				return n
+			}
+			// The init statement runs after the earlier checks.
+			if n.Init != nil && child != n.Init && assignsVar(pass, n.Init, v) {
+				return nil
+			}
+		case *ast.SwitchStmt:
+			if n.Init != nil && child != n.Init && assignsVar(pass, n.Init, v) {
+				return nil
+			}
+		case *ast.TypeSwitchStmt:
+			if n.Init != nil && child != n.Init && assignsVar(pass, n.Init, v) {
+				return nil
+			}
+		case *ast.CaseClause:
+			if i >= 2 {
+				if sw, ok := stack[i-2].(*ast.SwitchStmt); ok && sw.Tag == nil && len(n.List) == 1 && impliesNil(pass, n.List[0], v, true) && child != n.List[0] {
+					return n
+				}
+			}
+			stmts = n.Body
+		case *ast.CommClause:
+			stmts = n.Body
+		case *ast.BlockStmt:
+			stmts = n.List
+		}
+		for j := slices.IndexFunc(stmts, func(s ast.Stmt) bool { return s == child }) - 1; j >= 0; j-- {
+			if ifStmt, ok := stmts[j].(*ast.IfStmt); ok && ifStmt.Else == nil && leavesBlock(pass, ifStmt.Body) {
+				if impliesNil(pass, ifStmt.Cond, v, false) {
+					return ifStmt
+				}
+			}
+			if assignsVar(pass, stmts[j], v) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// checkedAfter returns the if statement that checks v for nil after the
+// statement holding the node that ends stack, if v was last assigned the
+// result of a call before that statement, in the same block, and not
+// checked in between. It returns nil otherwise.
+func checkedAfter(pass *analysis.Pass, v *types.Var, stack []ast.Node) *ast.IfStmt {
+	var stmts []ast.Stmt
+	var stmt ast.Node
+	for i := len(stack) - 2; i >= 0 && stmts == nil; i-- {
+		switch n := stack[i].(type) {
+		case *ast.FuncLit, *ast.FuncDecl:
+			return nil
+		case *ast.BlockStmt:
+			stmts = n.List
+		case *ast.CaseClause:
+			stmts = n.Body
+		case *ast.CommClause:
+			stmts = n.Body
+		}
+		stmt = stack[i+1]
+	}
+	at := slices.IndexFunc(stmts, func(s ast.Stmt) bool { return s == stmt })
+	if at < 0 {
+		return nil
+	}
+	assigned := false
+	for j := at - 1; j >= 0; j-- {
+		if ifStmt, ok := stmts[j].(*ast.IfStmt); ok && (comparesNil(pass, ifStmt.Cond, v, token.EQL) || comparesNil(pass, ifStmt.Cond, v, token.NEQ)) {
+			return nil
+		}
+		if assignsVar(pass, stmts[j], v) {
+			assign, ok := stmts[j].(*ast.AssignStmt)
+			if ok && len(assign.Rhs) == 1 {
+				_, call := ast.Unparen(assign.Rhs[0]).(*ast.CallExpr)
+				assigned = call && (assign.Tok == token.DEFINE || assign.Tok == token.ASSIGN)
+			}
+			break
+		}
+	}
+	if !assigned {
+		return nil
+	}
+	for _, s := range stmts[at+1:] {
+		if ifStmt, ok := s.(*ast.IfStmt); ok && (comparesNil(pass, ifStmt.Cond, v, token.EQL) || comparesNil(pass, ifStmt.Cond, v, token.NEQ)) {
+			return ifStmt
+		}
+		if assignsVar(pass, s, v) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// impliesNil reports whether cond evaluating to outcome implies that v is
+// nil.
+func impliesNil(pass *analysis.Pass, cond ast.Expr, v *types.Var, outcome bool) bool {
+	switch e := ast.Unparen(cond).(type) {
+	case *ast.UnaryExpr:
+		return e.Op == token.NOT && impliesNil(pass, e.X, v, !outcome)
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.EQL, token.NEQ:
+			for _, pair := range [][2]ast.Expr{{e.X, e.Y}, {e.Y, e.X}} {
+				if id, ok := ast.Unparen(pair[0]).(*ast.Ident); ok && pass.TypesInfo.Uses[id] == v && isNil(pass, pair[1]) {
+					return (e.Op == token.EQL) == outcome
+				}
+			}
+		case token.LAND:
+			return outcome && (impliesNil(pass, e.X, v, true) || impliesNil(pass, e.Y, v, true))
+		case token.LOR:
+			return !outcome && (impliesNil(pass, e.X, v, false) || impliesNil(pass, e.Y, v, false))
+		}
+	}
+	return false
+}
+
+// assignsVar reports whether n assigns a value to v anywhere.
+func assignsVar(pass *analysis.Pass, n ast.Node, v *types.Var) bool {
+	is := func(expr ast.Expr) bool {
+		id, ok := ast.Unparen(expr).(*ast.Ident)
+		return ok && pass.TypesInfo.ObjectOf(id) == v
+	}
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			found = found || slices.ContainsFunc(n.Lhs, is)
+		case *ast.RangeStmt:
+			found = found || n.Key != nil && is(n.Key) || n.Value != nil && is(n.Value)
+		case *ast.ValueSpec:
+			found = found || slices.ContainsFunc(n.Names, func(id *ast.Ident) bool { return is(id) })
+		}
+		return !found
+	})
+	return found
+}
+
+// escapes reports whether v may change at any time in the function
+// holding the node that ends stack: its address is taken, or a closure
+// assigns it.
+func escapes(pass *analysis.Pass, stack []ast.Node, v *types.Var) bool {
+	var fn ast.Node
+	for i := len(stack) - 1; i >= 0 && fn == nil; i-- {
+		if decl, ok := stack[i].(*ast.FuncDecl); ok {
+			fn = decl
+		}
+	}
+	if fn == nil {
+		return true
+	}
+	found := false
+	ast.Inspect(fn, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.UnaryExpr:
+			id, ok := ast.Unparen(n.X).(*ast.Ident)
+			found = found || n.Op == token.AND && ok && pass.TypesInfo.Uses[id] == v
+		case *ast.FuncLit:
+			found = found || assignsVar(pass, n.Body, v)
+		}
+		return !found
+	})
+	return found
+}
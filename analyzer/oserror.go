@@ -0,0 +1,76 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// osPredicates maps the os.IsXxx helpers, which predate error wrapping and
+// only look through a few wrapper types, to the sentinel that errors.Is
+// should check instead. os.IsTimeout has no sentinel equivalent.
+var osPredicates = map[string]string{
+	"IsExist":      "ErrExist",
+	"IsNotExist":   "ErrNotExist",
+	"IsPermission": "ErrPermission",
+	"IsTimeout":    "",
+}
+
+// checkOSErrors reports calls to os.IsExist, os.IsNotExist, os.IsPermission
+// and os.IsTimeout, which do not unwrap errors wrapped with %w.
+func (l *linter) checkOSErrors(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "os" || len(call.Args) != 1 {
+			return
+		}
+		sentinel, ok := osPredicates[fn.Name()]
+		if !ok {
+			return
+		}
+
+		if sentinel == "" {
+			pass.Reportf(call.Pos(), "os.%s does not unwrap errors; use errors.As with an interface{ Timeout() bool } target, or errors.Is(err, os.ErrDeadlineExceeded)", fn.Name())
+			return
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:            call.Pos(),
+			End:            call.End(),
+			Message:        fmt.Sprintf("os.%s does not unwrap errors; use errors.Is(err, fs.%s)", fn.Name(), sentinel),
+			SuggestedFixes: osErrorFix(pass, call, sentinel),
+		})
+	})
+}
+
+// osErrorFix rewrites os.IsNotExist(err) to errors.Is(err, os.ErrNotExist).
+// The os sentinels are the fs ones, and referring to them through os avoids
+// adding an import.
+func osErrorFix(pass *analysis.Pass, call *ast.CallExpr, sentinel string) []analysis.SuggestedFix {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	file := fileOf(pass, call.Pos())
+	if file == nil {
+		return nil
+	}
+
+	name, edits := importErrors(pass, file)
+	return []analysis.SuggestedFix{{
+		Message: "Use errors.Is",
+		TextEdits: append(edits, analysis.TextEdit{
+			Pos:     call.Pos(),
+			End:     call.End(),
+			NewText: fmt.Appendf(nil, "%s.Is(%s, %s.%s)", name, render(pass, call.Args[0]), pkg.Name, sentinel),
+		}),
+	}}
+}
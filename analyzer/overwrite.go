@@ -0,0 +1,201 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// checkOverwrites reports error variables assigned in each iteration of a
+// loop and only returned or checked after it, as in
+//
+//	var err error
+//	for _, page := range pages {
+//		items, err = fetch(page)
+//		all = append(all, items...)
+//	}
+//	if err != nil {
+//		return nil, err
+//	}
+//
+// Each iteration overwrites the error of the one before, so only the error
+// of the last iteration is seen. Only errors returned by calls are
+// reported: those of errors.New, fmt.Errorf and sentinels are the same in
+// each iteration. Variables that are read in the loop, for example checked
+// after the assignment or passed to errors.Join, are not reported, and
+// neither are loops that can stop after the assignment, with break, return
+// or panic, such as retries that stop once a call succeeds, which keep the
+// last error on purpose.
+func (l *linter) checkOverwrites(pass *analysis.Pass, insp *inspector.Inspector) {
+	type overwrite struct {
+		id   *ast.Ident
+		loop ast.Stmt
+		v    *types.Var
+	}
+	var overwrites []overwrite
+	seen := make(map[ast.Stmt]map[*types.Var]bool)
+	vars := make(map[*types.Var]bool)
+	insp.WithStack([]ast.Node{(*ast.AssignStmt)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		assign := n.(*ast.AssignStmt)
+		if !push || assign.Tok != token.ASSIGN {
+			return true
+		}
+		loop := enclosingLoop(stack)
+		if loop == nil || stopsEarly(pass, loop, assign.End()) {
+			return true
+		}
+		for i, lhs := range assign.Lhs {
+			id, ok := lhs.(*ast.Ident)
+			if !ok || !errorFromCall(pass, assign, i) {
+				continue
+			}
+			v, ok := pass.TypesInfo.Uses[id].(*types.Var)
+			if !ok || !isErrorVar(v) || v.Pkg() != pass.Pkg || v.Parent() == pass.Pkg.Scope() {
+				continue
+			}
+			// Variables declared in the loop start over in each iteration.
+			if v.Pos() >= loop.Pos() && v.Pos() < loop.End() || seen[loop][v] {
+				continue
+			}
+			if seen[loop] == nil {
+				seen[loop] = make(map[*types.Var]bool)
+			}
+			seen[loop][v] = true
+			overwrites = append(overwrites, overwrite{id, loop, v})
+			vars[v] = true
+		}
+		return true
+	})
+	if len(overwrites) == 0 {
+		return
+	}
+
+	uses := make(map[*types.Var][]errorUse)
+	insp.WithStack([]ast.Node{(*ast.Ident)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		if v, ok := pass.TypesInfo.Uses[n.(*ast.Ident)].(*types.Var); ok && vars[v] {
+			uses[v] = append(uses[v], classifyUse(pass, stack))
+		}
+		return true
+	})
+
+	for _, o := range overwrites {
+		readInLoop := false
+		var after *errorUse
+		for i, u := range uses[o.v] {
+			if u.pos >= o.loop.Pos() && u.pos < o.loop.End() && u.kind != useAssigned {
+				readInLoop = true
+				break
+			}
+			if u.pos >= o.loop.End() {
+				after = &uses[o.v][i]
+				break
+			}
+		}
+		if readInLoop || after == nil || after.kind != useReturned && after.kind != useChecked {
+			continue
+		}
+		how := "returned"
+		if after.kind == useChecked {
+			how = "checked"
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:     o.id.Pos(),
+			End:     o.id.End(),
+			Message: fmt.Sprintf("%s is assigned in each iteration of the loop but only %s after it, at line %d, so the errors of all but the last iteration are lost; check %s in the loop", o.id.Name, how, pass.Fset.Position(after.pos).Line, o.id.Name),
+		})
+	}
+}
+
+// enclosingLoop returns the innermost for or range statement of the
+// function that holds the node that ends stack, or nil.
+func enclosingLoop(stack []ast.Node) ast.Stmt {
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.FuncLit, *ast.FuncDecl:
+			return nil
+		case *ast.ForStmt:
+			// The init statement runs once, before the loop.
+			if n.Init != nil && n.Init == stack[i+1] {
+				return nil
+			}
+			return n
+		case *ast.RangeStmt:
+			return n
+		}
+	}
+	return nil
+}
+
+// stopsEarly reports whether loop holds a statement after pos that can
+// leave it before its last iteration: a return or goto statement, a break
+// out of loop, or a call of panic.
+func stopsEarly(pass *analysis.Pass, loop ast.Stmt, pos token.Pos) bool {
+	var body *ast.BlockStmt
+	switch loop := loop.(type) {
+	case *ast.ForStmt:
+		body = loop.Body
+	case *ast.RangeStmt:
+		body = loop.Body
+	}
+	stops := false
+	// depth counts the switch, select and loop statements of loop that
+	// hold the node, which an unlabeled break leaves instead of loop.
+	var visit func(n ast.Node, depth int)
+	visit = func(n ast.Node, depth int) {
+		ast.Inspect(n, func(n ast.Node) bool {
+			if stops || n == nil {
+				return false
+			}
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.SwitchStmt:
+				visit(n.Body, depth+1)
+				return false
+			case *ast.TypeSwitchStmt:
+				visit(n.Body, depth+1)
+				return false
+			case *ast.SelectStmt:
+				visit(n.Body, depth+1)
+				return false
+			case *ast.ForStmt:
+				visit(n.Body, depth+1)
+				return false
+			case *ast.RangeStmt:
+				visit(n.Body, depth+1)
+				return false
+			case *ast.ReturnStmt:
+				stops = n.Pos() > pos
+			case *ast.BranchStmt:
+				stops = n.Pos() > pos && (n.Tok == token.GOTO || n.Tok == token.BREAK && (n.Label != nil || depth == 0))
+			case *ast.CallExpr:
+				stops = n.Pos() > pos && isBuiltin(pass, n, "panic")
+			}
+			return true
+		})
+	}
+	visit(body, 0)
+	return stops
+}
+
+// errorFromCall reports whether the value assigned to the i-th variable on
+// the left of assign comes from a call, other than one that creates an
+// error from text, so each iteration may assign a different error.
+func errorFromCall(pass *analysis.Pass, assign *ast.AssignStmt, i int) bool {
+	rhs := assign.Rhs[0]
+	if len(assign.Rhs) == len(assign.Lhs) {
+		rhs = assign.Rhs[i]
+	}
+	call, ok := ast.Unparen(rhs).(*ast.CallExpr)
+	if !ok || pass.TypesInfo.Types[call.Fun].IsType() {
+		return false
+	}
+	return !isFunc(pass, call, "errors", "New") && !isFunc(pass, call, "fmt", "Errorf")
+}
@@ -0,0 +1,133 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// checkPanics reports errors created with fmt.Errorf from another error
+// and passed to panic in a function that returns an error, which could
+// return the error to its caller instead of unwinding the stack. Errors
+// created from text alone are left alone: they typically describe a bug,
+// such as misuse of an API, which panics are for. The fix returns the
+// error, with zero values for the other results.
+func (l *linter) checkPanics(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		call := n.(*ast.CallExpr)
+		if !isBuiltin(pass, call, "panic") || len(call.Args) != 1 {
+			return true
+		}
+		created, ok := ast.Unparen(call.Args[0]).(*ast.CallExpr)
+		if !ok || !isFunc(pass, created, "fmt", "Errorf") {
+			return true
+		}
+		// Errors without an error argument describe bugs, such as misuse
+		// of an API, which panics are for.
+		// The message names the error that failed rather than a sentinel
+		// it is tagged with, if there are both.
+		var cause ast.Expr
+		for _, arg := range created.Args[1:] {
+			if !isError(pass, arg) || isNil(pass, arg) {
+				continue
+			}
+			_, sentinel := sentinelName(pass, arg)
+			if cause == nil || !sentinel {
+				cause = arg
+			}
+			if !sentinel {
+				break
+			}
+		}
+		if cause == nil {
+			return true
+		}
+		fn, results := enclosingFunc(pass, stack[:len(stack)-1])
+		if fn == nil || !returnsError(results) {
+			return true
+		}
+		var fixes []analysis.SuggestedFix
+		if _, ok := stack[len(stack)-2].(*ast.ExprStmt); ok {
+			fixes = panicFix(pass, call, fn, results)
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:            call.Pos(),
+			End:            call.End(),
+			Message:        fmt.Sprintf("fmt.Errorf with %s is passed to panic in a function that returns an error; return the error instead", render(pass, cause)),
+			SuggestedFixes: fixes,
+		})
+		return true
+	})
+}
+
+// returnsError reports whether the last of results is an error.
+func returnsError(results *types.Tuple) bool {
+	if results.Len() == 0 {
+		return false
+	}
+	t := results.At(results.Len() - 1).Type()
+	return types.IsInterface(t) && types.Implements(t, errorIface)
+}
+
+// panicFix replaces the panic call with a return statement that returns its
+// argument as the last result of fn and zero values for the others.
+func panicFix(pass *analysis.Pass, call *ast.CallExpr, fn ast.Node, results *types.Tuple) []analysis.SuggestedFix {
+	var typ *ast.FuncType
+	switch fn := fn.(type) {
+	case *ast.FuncLit:
+		typ = fn.Type
+	case *ast.FuncDecl:
+		typ = fn.Type
+	}
+	var values []string
+	for _, field := range typ.Results.List {
+		names := max(len(field.Names), 1)
+		for range names {
+			values = append(values, zeroLiteral(pass, field.Type))
+		}
+	}
+	if len(values) != results.Len() {
+		return nil
+	}
+	values[len(values)-1] = render(pass, call.Args[0])
+	return []analysis.SuggestedFix{{
+		Message: "Return the error instead",
+		TextEdits: []analysis.TextEdit{{
+			Pos:     call.Pos(),
+			End:     call.End(),
+			NewText: []byte("return " + strings.Join(values, ", ")),
+		}},
+	}}
+}
+
+// zeroLiteral returns the zero value of the type expr denotes as it is
+// written in a return statement, such as 0, "", nil or T{}.
+func zeroLiteral(pass *analysis.Pass, expr ast.Expr) string {
+	t := pass.TypesInfo.TypeOf(expr)
+	if _, ok := t.(*types.TypeParam); ok {
+		return "*new(" + render(pass, expr) + ")"
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return "false"
+		case u.Info()&types.IsString != 0:
+			return `""`
+		case u.Info()&types.IsNumeric != 0:
+			return "0"
+		}
+	case *types.Pointer, *types.Interface, *types.Map, *types.Slice, *types.Chan, *types.Signature:
+		return "nil"
+	case *types.Struct, *types.Array:
+		return render(pass, expr) + "{}"
+	}
+	return "*new(" + render(pass, expr) + ")"
+}
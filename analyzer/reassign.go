@@ -0,0 +1,90 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// checkReassigns reports assignments to sentinel errors, package-level
+// error variables named ErrX or errX and the allowlisted sentinels such as
+// io.EOF, of the package or of the packages it imports, outside their
+// declaration. Errors created before the assignment
+// wrap the old value, so errors.Is no longer matches them against the
+// sentinel, in this package or in any other. Variables of the package
+// declared without a value hold state, such as the last error, rather than
+// a sentinel, and are not reported.
+func (l *linter) checkReassigns(pass *analysis.Pass, insp *inspector.Inspector) {
+	uninitialized := make(map[*types.Var]bool)
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.ValueSpec)
+				for _, name := range spec.Names {
+					if v, ok := pass.TypesInfo.Defs[name].(*types.Var); ok && len(spec.Values) == 0 {
+						uninitialized[v] = true
+					}
+				}
+			}
+		}
+	}
+
+	insp.Preorder([]ast.Node{(*ast.AssignStmt)(nil)}, func(n ast.Node) {
+		assign := n.(*ast.AssignStmt)
+		if assign.Tok != token.ASSIGN {
+			return
+		}
+		for _, lhs := range assign.Lhs {
+			v := packageErrorVar(pass, lhs)
+			if v == nil || uninitialized[v] || !sentinelNamed(v.Name()) && !l.allowed[v.Pkg().Path()+"."+v.Name()] {
+				continue
+			}
+			pass.Reportf(lhs.Pos(), "sentinel error %s is reassigned, so errors.Is no longer matches the errors that wrap its previous value; declare a new sentinel instead of assigning to it", render(pass, lhs))
+		}
+	})
+}
+
+// packageErrorVar returns the package-level variable of an error type
+// that expr refers to, or nil.
+func packageErrorVar(pass *analysis.Pass, expr ast.Expr) *types.Var {
+	var id *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		id = e.Sel
+	default:
+		return nil
+	}
+	// The objects of imported packages have no parent scope, so the
+	// package scope is looked up instead.
+	v, ok := pass.TypesInfo.Uses[id].(*types.Var)
+	if !ok || v.Pkg() == nil || v.Pkg().Scope().Lookup(v.Name()) != v || !types.Implements(v.Type(), errorIface) {
+		return nil
+	}
+	return v
+}
+
+// sentinelNamed reports whether name follows the convention of sentinel
+// errors: Err or err followed by an upper-case letter, or Err alone.
+func sentinelNamed(name string) bool {
+	if name == "Err" {
+		return true
+	}
+	rest, ok := strings.CutPrefix(name, "Err")
+	if !ok {
+		rest, ok = strings.CutPrefix(name, "err")
+	}
+	r, _ := utf8.DecodeRuneInString(rest)
+	return ok && unicode.IsUpper(r)
+}
@@ -0,0 +1,251 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// checkShadows reports error variables declared with := in a block that
+// shadow an error variable of an enclosing block, which is then returned or
+// checked after the block, as in
+//
+//	var err error
+//	if compress {
+//		data, err := gzip(data)
+//		...
+//	}
+//	return err
+//
+// The error assigned in the block never reaches the outer variable, so the
+// function returns or checks the wrong value. Inner variables that are
+// returned or passed on, or checked by an if statement that leaves the
+// block, are handled where they are and not reported, and so are variables
+// declared in the init statement of an if, switch or for statement.
+func (l *linter) checkShadows(pass *analysis.Pass, insp *inspector.Inspector) {
+	type shadow struct {
+		id           *ast.Ident
+		assign       *ast.AssignStmt
+		inner, outer *types.Var
+	}
+	var shadows []shadow
+	vars := make(map[*types.Var]bool)
+	insp.WithStack([]ast.Node{(*ast.AssignStmt)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		assign := n.(*ast.AssignStmt)
+		// An err declared in the init statement of an if, switch or for
+		// statement is scoped to it on purpose, typically to probe for an
+		// error that is then ignored.
+		if _, ok := stack[len(stack)-2].(*ast.BlockStmt); !push || !ok || assign.Tok != token.DEFINE {
+			return true
+		}
+		for _, lhs := range assign.Lhs {
+			id, ok := lhs.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			inner, ok := pass.TypesInfo.Defs[id].(*types.Var)
+			if !ok || !isErrorVar(inner) {
+				continue
+			}
+			if outer := shadowedError(pass, inner); outer != nil {
+				shadows = append(shadows, shadow{id, assign, inner, outer})
+				vars[inner], vars[outer] = true, true
+			}
+		}
+		return true
+	})
+	if len(shadows) == 0 {
+		return
+	}
+
+	uses := make(map[*types.Var][]errorUse)
+	insp.WithStack([]ast.Node{(*ast.Ident)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		if v, ok := pass.TypesInfo.Uses[n.(*ast.Ident)].(*types.Var); ok && vars[v] {
+			uses[v] = append(uses[v], classifyUse(pass, stack))
+		}
+		return true
+	})
+
+	for _, s := range shadows {
+		if handled(uses[s.inner]) {
+			continue
+		}
+		// The statement of the outer block that holds the inner one ends
+		// with the else branches, which may assign to the outer variable
+		// themselves.
+		scope := s.inner.Parent()
+		for scope.Parent() != s.outer.Parent() {
+			scope = scope.Parent()
+		}
+		var after *errorUse
+		for i, u := range uses[s.outer] {
+			if u.pos >= scope.End() {
+				after = &uses[s.outer][i]
+				break
+			}
+		}
+		if after == nil || after.kind != useReturned && after.kind != useChecked {
+			continue
+		}
+		how := "returned"
+		if after.kind == useChecked {
+			how = "checked"
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:            s.id.Pos(),
+			End:            s.id.End(),
+			Message:        fmt.Sprintf("%s declared here shadows the %s declared at line %d, which is %s at line %d without this error; assign to the outer %s with = instead", s.id.Name, s.outer.Name(), pass.Fset.Position(s.outer.Pos()).Line, how, pass.Fset.Position(after.pos).Line, s.outer.Name()),
+			SuggestedFixes: shadowFix(pass, s.assign, s.id, s.inner, s.outer),
+		})
+	}
+}
+
+// isErrorVar reports whether v has an interface type that implements error.
+func isErrorVar(v *types.Var) bool {
+	return types.IsInterface(v.Type()) && types.Implements(v.Type(), errorIface)
+}
+
+// shadowedError returns the error variable of an enclosing block of the
+// same function that inner shadows, or nil.
+func shadowedError(pass *analysis.Pass, inner *types.Var) *types.Var {
+	scope := inner.Parent()
+	if scope == nil || scope.Parent() == nil {
+		return nil
+	}
+	_, obj := scope.Parent().LookupParent(inner.Name(), inner.Pos())
+	outer, ok := obj.(*types.Var)
+	if !ok || outer.Pkg() != pass.Pkg || outer.Parent() == pass.Pkg.Scope() || outer.Parent() == nil || !isErrorVar(outer) {
+		return nil
+	}
+	return outer
+}
+
+// useKind says how an error variable is used.
+type useKind int
+
+const (
+	useOther useKind = iota
+	useAssigned
+	useReturned
+	useChecked
+)
+
+// errorUse is a use of an error variable.
+type errorUse struct {
+	pos  token.Pos
+	kind useKind
+	// leaves reports whether the use is the condition of an if statement
+	// whose branch for the error leaves the block.
+	leaves bool
+}
+
+// classifyUse returns the use of the error variable that ends stack.
+func classifyUse(pass *analysis.Pass, stack []ast.Node) errorUse {
+	id := stack[len(stack)-1].(*ast.Ident)
+	use := errorUse{pos: id.Pos()}
+	i := len(stack) - 2
+	for i > 0 {
+		if _, ok := stack[i].(*ast.ParenExpr); !ok {
+			break
+		}
+		i--
+	}
+	switch parent := stack[i].(type) {
+	case *ast.AssignStmt:
+		for _, lhs := range parent.Lhs {
+			if lhs == id {
+				use.kind = useAssigned
+			}
+		}
+	case *ast.ReturnStmt:
+		use.kind = useReturned
+	case *ast.BinaryExpr:
+		if parent.Op != token.EQL && parent.Op != token.NEQ {
+			break
+		}
+		use.kind = useChecked
+		if ifStmt, ok := stack[i-1].(*ast.IfStmt); ok && ifStmt.Cond == parent {
+			// The error is in the else branch of err == nil.
+			onError := ast.Stmt(ifStmt.Body)
+			if parent.Op == token.EQL && (isNil(pass, parent.X) || isNil(pass, parent.Y)) {
+				onError = ifStmt.Else
+			}
+			use.leaves = leavesBlock(pass, onError)
+		}
+	case *ast.CallExpr:
+		if len(parent.Args) > 0 && ast.Unparen(parent.Args[0]) == id && (isFunc(pass, parent, "errors", "Is") || isFunc(pass, parent, "errors", "As")) {
+			use.kind = useChecked
+			if ifStmt, ok := stack[i-1].(*ast.IfStmt); ok && ifStmt.Cond == parent {
+				use.leaves = leavesBlock(pass, ifStmt.Body)
+			}
+		}
+	}
+	return use
+}
+
+// handled reports whether the uses of an error variable handle its error:
+// it is returned, passed on, or checked by an if statement that leaves the
+// block if there is an error.
+func handled(uses []errorUse) bool {
+	for _, u := range uses {
+		switch {
+		case u.kind == useReturned, u.kind == useOther:
+			return true
+		case u.kind == useChecked && u.leaves:
+			return true
+		}
+	}
+	return false
+}
+
+// leavesBlock reports whether stmt is a block that ends by leaving the
+// enclosing block: with a return, break, continue or goto statement, or a
+// call of panic.
+func leavesBlock(pass *analysis.Pass, stmt ast.Stmt) bool {
+	block, ok := stmt.(*ast.BlockStmt)
+	if !ok || len(block.List) == 0 {
+		return false
+	}
+	switch last := block.List[len(block.List)-1].(type) {
+	case *ast.ReturnStmt, *ast.BranchStmt:
+		return true
+	case *ast.ExprStmt:
+		call, ok := ast.Unparen(last.X).(*ast.CallExpr)
+		return ok && isBuiltin(pass, call, "panic")
+	}
+	return false
+}
+
+// shadowFix replaces := with = in assign, which assigns to the outer
+// variable instead of declaring the inner one, id, if the inner one is
+// assignable to it and each of the other variables on the left is blank or
+// already declared.
+func shadowFix(pass *analysis.Pass, assign *ast.AssignStmt, id *ast.Ident, inner, outer *types.Var) []analysis.SuggestedFix {
+	if !types.AssignableTo(inner.Type(), outer.Type()) {
+		return nil
+	}
+	for _, lhs := range assign.Lhs {
+		other, ok := lhs.(*ast.Ident)
+		if !ok || other == id || other.Name == "_" {
+			continue
+		}
+		if pass.TypesInfo.Defs[other] != nil {
+			return nil
+		}
+	}
+	return []analysis.SuggestedFix{{
+		Message: fmt.Sprintf("Assign to the outer %s", id.Name),
+		TextEdits: []analysis.TextEdit{{
+			Pos:     assign.TokPos,
+			End:     assign.TokPos + token.Pos(len(token.DEFINE.String())),
+			NewText: []byte("="),
+		}},
+	}}
+}
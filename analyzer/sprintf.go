@@ -0,0 +1,313 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer/internal/verbs"
+)
+
+// checkSprintf reports errors.New(fmt.Sprintf(...)), which formats the
+// message in two steps where fmt.Errorf takes one, and fmt.Sprintf calls
+// that format an error, or its message, into the format or an argument of
+// fmt.Errorf, which keeps the text of the error but drops the error from
+// the chain. The fixes call fmt.Errorf with the format and arguments of
+// fmt.Sprintf, and wrap the errors it formats with %w.
+func (l *linter) checkSprintf(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		switch {
+		case isFunc(pass, call, "errors", "New"):
+			l.checkNewSprintf(pass, call)
+		case isFunc(pass, call, "fmt", "Errorf"):
+			checkErrorfSprintf(pass, call)
+		}
+	})
+}
+
+// checkNewSprintf reports the errors.New call if its message is formatted
+// by fmt.Sprintf.
+func (l *linter) checkNewSprintf(pass *analysis.Pass, call *ast.CallExpr) {
+	if len(call.Args) != 1 {
+		return
+	}
+	sprintf, ok := sprintfCall(pass, call.Args[0])
+	if !ok {
+		return
+	}
+	msg := "errors.New(fmt.Sprintf(...)) formats the message in two steps; use fmt.Errorf"
+	if errs := formattedErrors(pass, sprintf); len(errs) > 0 {
+		msg = fmt.Sprintf("errors.New(fmt.Sprintf(...)) keeps the text of %s but drops it from the chain; use fmt.Errorf and wrap %s with %%w", errs, errs)
+	}
+	pass.Report(analysis.Diagnostic{
+		Pos:            call.Pos(),
+		End:            call.End(),
+		Message:        msg,
+		SuggestedFixes: l.newSprintfFix(pass, call, sprintf),
+	})
+}
+
+// checkErrorfSprintf reports the fmt.Sprintf calls that format an error
+// into the format or an argument of the fmt.Errorf call.
+func checkErrorfSprintf(pass *analysis.Pass, call *ast.CallExpr) {
+	if call.Ellipsis.IsValid() {
+		return
+	}
+	var found []int
+	for i, arg := range call.Args {
+		if sprintf, ok := sprintfCall(pass, arg); ok && len(formattedErrors(pass, sprintf)) > 0 {
+			found = append(found, i)
+		}
+	}
+	for _, i := range found {
+		sprintf, _ := sprintfCall(pass, call.Args[i])
+		errs := formattedErrors(pass, sprintf)
+		where := "an argument"
+		if i == 0 {
+			where = "the format"
+		}
+		var fixes []analysis.SuggestedFix
+		// Each fix rewrites all the arguments of fmt.Errorf, so the fixes of
+		// several fmt.Sprintf calls would conflict.
+		if len(found) == 1 {
+			fixes = inlineSprintfFix(pass, call, i, sprintf)
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:            sprintf.Pos(),
+			End:            sprintf.End(),
+			Message:        fmt.Sprintf("fmt.Sprintf formats the text of %s into %s of fmt.Errorf, which drops it from the chain; wrap %s with %%w", errs, where, errs),
+			SuggestedFixes: fixes,
+		})
+	}
+}
+
+// sprintfCall reports whether expr is a call of fmt.Sprintf with arguments
+// after the format, and returns it.
+func sprintfCall(pass *analysis.Pass, expr ast.Expr) (*ast.CallExpr, bool) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || !isFunc(pass, call, "fmt", "Sprintf") || len(call.Args) < 2 {
+		return nil, false
+	}
+	return call, true
+}
+
+// errorList is the list of errors a fmt.Sprintf call formats, as they
+// appear in messages.
+type errorList []string
+
+func (l errorList) String() string {
+	return strings.Join(l, " and ")
+}
+
+// formattedErrors returns the errors the fmt.Sprintf call formats: its
+// error arguments, and the errors whose message, err.Error(), it formats.
+func formattedErrors(pass *analysis.Pass, sprintf *ast.CallExpr) errorList {
+	var errs errorList
+	for _, arg := range sprintf.Args[1:] {
+		if isError(pass, arg) && !isNil(pass, arg) {
+			errs = append(errs, render(pass, arg))
+		} else if err, ok := errorMessage(pass, arg); ok {
+			errs = append(errs, render(pass, err))
+		}
+	}
+	return errs
+}
+
+// wrappedFormat returns the format and the source of the arguments of the
+// fmt.Sprintf call, with the errors it formats under a plain %s or %v, or
+// whose messages it formats so, passed under %w instead, and the number of
+// errors it wraps. It reports false unless the format is constant and each
+// of its verbs formats the next argument.
+func wrappedFormat(pass *analysis.Pass, sprintf *ast.CallExpr) (string, []string, int, bool) {
+	if sprintf.Ellipsis.IsValid() {
+		return "", nil, 0, false
+	}
+	format, ok := constantString(pass, sprintf.Args[0])
+	if !ok {
+		return "", nil, 0, false
+	}
+	args := sprintf.Args[1:]
+	vs := verbs.Parse(format, len(args))
+	if !inOrder(vs, len(args)) {
+		return "", nil, 0, false
+	}
+
+	b := []byte(format)
+	texts := make([]string, len(args))
+	wraps := 0
+	for i, arg := range args {
+		texts[i] = render(pass, arg)
+		// Flags, as in %+v, and widths change the text fmt formats, which
+		// %w would not keep.
+		if v := vs[i]; v.End-v.Start != 2 || v.Verb != 's' && v.Verb != 'v' && v.Verb != 'w' {
+			continue
+		}
+		if isError(pass, arg) && !isNil(pass, arg) {
+			b[vs[i].End-1] = 'w'
+			wraps++
+		} else if err, ok := errorMessage(pass, arg); ok {
+			b[vs[i].End-1] = 'w'
+			texts[i] = render(pass, err)
+			wraps++
+		}
+	}
+	return string(b), texts, wraps, true
+}
+
+// inOrder reports whether each of the nargs operands of a format is
+// formatted by one verb of vs, in order, without explicit indexes or '*'
+// widths and precisions.
+func inOrder(vs []verbs.Verb, nargs int) bool {
+	if len(vs) != nargs {
+		return false
+	}
+	for i, v := range vs {
+		if v.Arg != i {
+			return false
+		}
+	}
+	return true
+}
+
+// newSprintfFix rewrites errors.New(fmt.Sprintf(format, args...)) to
+// fmt.Errorf(format, args...), wrapping the errors it formats with %w. It
+// removes the errors import if the file has no other use for it once every
+// such call is rewritten.
+func (l *linter) newSprintfFix(pass *analysis.Pass, call, sprintf *ast.CallExpr) []analysis.SuggestedFix {
+	file := fileOf(pass, call.Pos())
+	errorsName := l.rewritesNew(pass, call)
+	sel, ok := sprintf.Fun.(*ast.SelectorExpr)
+	if file == nil || errorsName == nil || !ok {
+		return nil
+	}
+	errorf := render(pass, sel.X) + ".Errorf"
+
+	var edits []analysis.TextEdit
+	format, args, wraps, ok := wrappedFormat(pass, sprintf)
+	if ok && wraps > 0 && (wraps == 1 || supportsMultiWrap(pass, call)) {
+		edits = append(edits, analysis.TextEdit{
+			Pos:     call.Pos(),
+			End:     call.End(),
+			NewText: []byte(errorf + "(" + strings.Join(append([]string{strconv.Quote(format)}, args...), ", ") + ")"),
+		})
+	} else {
+		// Keep the arguments of fmt.Sprintf as they are written.
+		edits = append(edits,
+			analysis.TextEdit{Pos: call.Pos(), End: sprintf.Lparen, NewText: []byte(errorf)},
+			analysis.TextEdit{Pos: sprintf.End(), End: call.End()},
+		)
+	}
+	if decl, spec := importSpec(pass, file, errorsName); spec != nil && !l.keepsErrors(pass, file, errorsName) {
+		edits = append(edits, replaceImport(pass, decl, spec, ""))
+	}
+
+	msg := "Use fmt.Errorf"
+	if wraps > 0 {
+		msg = "Use fmt.Errorf and wrap the error with %w"
+	}
+	return []analysis.SuggestedFix{{
+		Message:   msg,
+		TextEdits: edits,
+	}}
+}
+
+// rewritesNew returns the package name the errors.New call refers to
+// errors by if the fix of the sprintf check rewrites it, or nil.
+func (l *linter) rewritesNew(pass *analysis.Pass, call *ast.CallExpr) *types.PkgName {
+	if !isFunc(pass, call, "errors", "New") || len(call.Args) != 1 || l.ignores.find(pass.Fset, call.Pos(), "sprintf") != nil {
+		return nil
+	}
+	if _, ok := sprintfCall(pass, call.Args[0]); !ok {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	id, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	pkg, _ := pass.TypesInfo.Uses[id].(*types.PkgName)
+	return pkg
+}
+
+// keepsErrors reports whether file needs its import of package errors, as
+// pkg, once the fixes of the sprintf check have rewritten the errors.New
+// calls: because it refers to pkg elsewhere, or because the fix of the
+// errorsnew check calls errors.New.
+func (l *linter) keepsErrors(pass *analysis.Pass, file *ast.File, pkg *types.PkgName) bool {
+	keeps := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && l.rewritable(pass, call) != nil {
+			keeps = true
+		}
+		return !keeps
+	})
+	return keeps || refersTo(pass, file, pkg, func(call *ast.CallExpr) bool { return l.rewritesNew(pass, call) == pkg })
+}
+
+// inlineSprintfFix moves the format and arguments of the fmt.Sprintf call,
+// the i-th argument of the fmt.Errorf call, into fmt.Errorf, in place of
+// the plain %s or %v verb that formats it, and wraps the errors it formats
+// with %w.
+func inlineSprintfFix(pass *analysis.Pass, call *ast.CallExpr, i int, sprintf *ast.CallExpr) []analysis.SuggestedFix {
+	inner, innerArgs, wraps, ok := wrappedFormat(pass, sprintf)
+	if !ok || wraps == 0 {
+		return nil
+	}
+
+	var (
+		format string
+		args   []string
+	)
+	if i == 0 {
+		// Further arguments belong to verbs of the formatted format.
+		if len(call.Args) != 1 {
+			return nil
+		}
+		format, args = inner, innerArgs
+	} else {
+		outer, ok := constantString(pass, call.Args[0])
+		if !ok {
+			return nil
+		}
+		rest := call.Args[1:]
+		vs := verbs.Parse(outer, len(rest))
+		if !inOrder(vs, len(rest)) {
+			return nil
+		}
+		if v := vs[i-1]; v.End-v.Start != 2 || v.Verb != 's' && v.Verb != 'v' {
+			return nil
+		}
+		format = outer[:vs[i-1].Start] + inner + outer[vs[i-1].End:]
+		for j, arg := range rest {
+			if j == i-1 {
+				args = append(args, innerArgs...)
+				continue
+			}
+			if vs[j].Verb == 'w' {
+				wraps++
+			}
+			args = append(args, render(pass, arg))
+		}
+	}
+	if wraps > 1 && !supportsMultiWrap(pass, call) {
+		return nil
+	}
+
+	return []analysis.SuggestedFix{{
+		Message: "Format with fmt.Errorf and wrap the error with %w",
+		TextEdits: []analysis.TextEdit{{
+			Pos:     call.Args[0].Pos(),
+			End:     call.Args[len(call.Args)-1].End(),
+			NewText: []byte(strings.Join(append([]string{strconv.Quote(format)}, args...), ", ")),
+		}},
+	}}
+}
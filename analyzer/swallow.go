@@ -0,0 +1,121 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// defaultLoggers lists the packages and functions whose calls the swallow
+// check treats as logging an error.
+var defaultLoggers = []string{
+	"log",
+	"log/slog",
+}
+
+// checkSwallow reports errors that are logged and then dropped: an
+// if err != nil block that passes err to a logging function and returns
+// nil for every error result of the function. The caller sees success, and
+// errors.Is and errors.As have nothing to match.
+func (l *linter) checkSwallow(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.WithStack([]ast.Node{(*ast.IfStmt)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		stmt := n.(*ast.IfStmt)
+		v := checkedError(pass, stmt.Cond)
+		if v == nil || len(stmt.Body.List) < 2 {
+			return true
+		}
+		ret, ok := stmt.Body.List[len(stmt.Body.List)-1].(*ast.ReturnStmt)
+		if !ok {
+			return true
+		}
+		_, results := enclosingSignature(pass, stack)
+		if results == nil || !returnsNilErrors(pass, ret, results) {
+			return true
+		}
+		for _, s := range stmt.Body.List[:len(stmt.Body.List)-1] {
+			if logger := l.logs(pass, s, v); logger != nil {
+				pass.Reportf(ret.Pos(), "error %s is logged with %s and then dropped by returning nil; callers see success, so return it instead", v.Name(), funcName(logger))
+				break
+			}
+		}
+		return true
+	})
+}
+
+// checkedError returns the error variable that cond tests with != nil.
+func checkedError(pass *analysis.Pass, cond ast.Expr) *types.Var {
+	bin, ok := ast.Unparen(cond).(*ast.BinaryExpr)
+	if !ok || bin.Op != token.NEQ {
+		return nil
+	}
+	x := bin.X
+	if isNil(pass, x) {
+		x = bin.Y
+	} else if !isNil(pass, bin.Y) {
+		return nil
+	}
+	id, ok := ast.Unparen(x).(*ast.Ident)
+	if !ok || !isError(pass, id) {
+		return nil
+	}
+	v, _ := pass.TypesInfo.Uses[id].(*types.Var)
+	return v
+}
+
+// returnsNilErrors reports whether ret returns the literal nil for every
+// error of the results, of which there is at least one. A bare return
+// with named results is not counted: the results hold whatever was last
+// assigned to them.
+func returnsNilErrors(pass *analysis.Pass, ret *ast.ReturnStmt, results *types.Tuple) bool {
+	if len(ret.Results) != results.Len() {
+		return false
+	}
+	errs := 0
+	for i, expr := range ret.Results {
+		if t := results.At(i).Type(); !types.IsInterface(t) || !types.Implements(t, errorIface) {
+			continue
+		}
+		if !isNil(pass, expr) {
+			return false
+		}
+		errs++
+	}
+	return errs > 0
+}
+
+// logs returns the logging function the statement s calls with v among
+// its arguments, or nil.
+func (l *linter) logs(pass *analysis.Pass, s ast.Stmt, v *types.Var) *types.Func {
+	expr, ok := s.(*ast.ExprStmt)
+	if !ok {
+		return nil
+	}
+	call, ok := ast.Unparen(expr.X).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || !l.loggers.allows(fn.Origin()) {
+		return nil
+	}
+	for _, arg := range call.Args {
+		found := false
+		ast.Inspect(arg, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok && pass.TypesInfo.Uses[id] == v {
+				found = true
+			}
+			return !found
+		})
+		if found {
+			return fn
+		}
+	}
+	return nil
+}
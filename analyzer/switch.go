@@ -0,0 +1,196 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// checkSwitches reports switch statements over error values and error types.
+func (l *linter) checkSwitches(pass *analysis.Pass, insp *inspector.Inspector) {
+	nodes := []ast.Node{(*ast.SwitchStmt)(nil), (*ast.TypeSwitchStmt)(nil)}
+	insp.WithStack(nodes, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push || inIsMethod(pass, n.Pos()) {
+			return true
+		}
+		switch stmt := n.(type) {
+		case *ast.SwitchStmt:
+			if stmt.Tag == nil || !isError(pass, stmt.Tag) || !l.hasSentinelCase(pass, stmt) {
+				return true
+			}
+			var fixes []analysis.SuggestedFix
+			if _, labeled := stack[len(stack)-2].(*ast.LabeledStmt); !labeled {
+				fixes = switchFix(pass, stmt)
+			}
+			pass.Report(analysis.Diagnostic{
+				Pos:            stmt.Pos(),
+				End:            stmt.Body.Lbrace,
+				Message:        "switch on error value fails on wrapped errors; use errors.Is",
+				SuggestedFixes: fixes,
+			})
+		case *ast.TypeSwitchStmt:
+			x := typeSwitchOperand(stmt)
+			if x == nil || !isErrorInterface(pass, x) || onlyUnwrapCases(pass, stmt) {
+				return true
+			}
+			var fixes []analysis.SuggestedFix
+			if _, labeled := stack[len(stack)-2].(*ast.LabeledStmt); !labeled {
+				fixes = typeSwitchFix(pass, stmt)
+			}
+			pass.Report(analysis.Diagnostic{
+				Pos:            stmt.Pos(),
+				Message:        "type switch on error fails on wrapped errors; use errors.As",
+				SuggestedFixes: fixes,
+			})
+		}
+		return true
+	})
+}
+
+// hasSentinelCase reports whether any case of stmt compares the tag against a
+// value other than nil or an allowlisted sentinel.
+func (l *linter) hasSentinelCase(pass *analysis.Pass, stmt *ast.SwitchStmt) bool {
+	for _, clause := range stmt.Body.List {
+		for _, expr := range clause.(*ast.CaseClause).List {
+			if !isNil(pass, expr) && !l.isAllowedSentinel(pass, expr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// onlyUnwrapCases reports whether every case of a type switch is nil or an
+// Unwrap interface, see isUnwrapInterface.
+func onlyUnwrapCases(pass *analysis.Pass, stmt *ast.TypeSwitchStmt) bool {
+	for _, clause := range stmt.Body.List {
+		for _, expr := range clause.(*ast.CaseClause).List {
+			if !isNil(pass, expr) && !isUnwrapInterface(pass.TypesInfo.TypeOf(expr)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// switchFix rewrites a switch over an error value into an if/else chain of
+// errors.Is calls. Switches with an init statement, a tag with possible side
+// effects, or case bodies that break or fall through are left alone.
+func switchFix(pass *analysis.Pass, stmt *ast.SwitchStmt) []analysis.SuggestedFix {
+	if stmt.Init != nil || !isPure(stmt.Tag) {
+		return nil
+	}
+	file := fileOf(pass, stmt.Pos())
+	if file == nil {
+		return nil
+	}
+	name, edits := importErrors(pass, file)
+	tag := render(pass, stmt.Tag)
+	indent := indentation(pass, stmt.Pos())
+
+	var (
+		buf         strings.Builder
+		defaultBody string
+	)
+	for i, c := range stmt.Body.List {
+		clause := c.(*ast.CaseClause)
+		if branchesOut(clause.Body) {
+			return nil
+		}
+		end := stmt.Body.Rbrace
+		if i+1 < len(stmt.Body.List) {
+			end = stmt.Body.List[i+1].Pos()
+		}
+		body, ok := source(pass, clause.Colon+1, end)
+		if !ok {
+			return nil
+		}
+		body = strings.TrimRight(body, " \t\n")
+
+		if clause.List == nil {
+			defaultBody = body
+			continue
+		}
+		conds := make([]string, 0, len(clause.List))
+		for _, expr := range clause.List {
+			if isNil(pass, expr) {
+				conds = append(conds, tag+" == nil")
+				continue
+			}
+			conds = append(conds, fmt.Sprintf("%s.Is(%s, %s)", name, tag, render(pass, expr)))
+		}
+		if buf.Len() > 0 {
+			buf.WriteString(" else ")
+		}
+		fmt.Fprintf(&buf, "if %s {%s\n%s}", strings.Join(conds, " || "), body, indent)
+	}
+	if defaultBody != "" {
+		fmt.Fprintf(&buf, " else {%s\n%s}", defaultBody, indent)
+	}
+
+	return []analysis.SuggestedFix{{
+		Message: "Rewrite as errors.Is chain",
+		TextEdits: append(edits, analysis.TextEdit{
+			Pos:     stmt.Pos(),
+			End:     stmt.End(),
+			NewText: []byte(buf.String()),
+		}),
+	}}
+}
+
+// isPure reports whether evaluating expr more than once is safe.
+func isPure(expr ast.Expr) bool {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		return true
+	case *ast.SelectorExpr:
+		return isPure(e.X)
+	}
+	return false
+}
+
+// branchesOut reports whether stmts contain a fallthrough or an unlabeled
+// break that would bind to a different statement once the enclosing switch
+// is rewritten.
+func branchesOut(stmts []ast.Stmt) bool {
+	found := false
+	for _, stmt := range stmts {
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit, *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+				return false
+			case *ast.BranchStmt:
+				if n.Tok == token.FALLTHROUGH || (n.Tok == token.BREAK && n.Label == nil) {
+					found = true
+				}
+			}
+			return !found
+		})
+	}
+	return found
+}
+
+// typeSwitchOperand returns x from a "switch x.(type)" or
+// "switch v := x.(type)" statement.
+func typeSwitchOperand(stmt *ast.TypeSwitchStmt) ast.Expr {
+	var expr ast.Expr
+	switch assign := stmt.Assign.(type) {
+	case *ast.ExprStmt:
+		expr = assign.X
+	case *ast.AssignStmt:
+		if len(assign.Rhs) != 1 {
+			return nil
+		}
+		expr = assign.Rhs[0]
+	}
+
+	ta, ok := ast.Unparen(expr).(*ast.TypeAssertExpr)
+	if !ok {
+		return nil
+	}
+	return ta.X
+}
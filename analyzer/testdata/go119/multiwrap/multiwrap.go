@@ -0,0 +1,25 @@
+package multiwrap
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrPrimaryDown = errors.New("primary database down") // want ErrPrimaryDown:"sentinel"
+	ErrReplicaDown = errors.New("replica database down") // want ErrReplicaDown:"sentinel"
+)
+
+func query(err error) []error {
+	return []error{
+		fmt.Errorf("query failed: %w, then %w", ErrPrimaryDown, ErrReplicaDown), // want `fmt.Errorf with multiple %w verbs requires go1.20 or later; this file is built with go1.19`
+
+		// Before go1.20 only one verb can become %w, so there is no fix.
+		fmt.Errorf("query failed: %v, then %v", ErrPrimaryDown, err), // want `error formatted with %v in fmt.Errorf is not wrapped; use %w` `error formatted with %v in fmt.Errorf is not wrapped; use %w`
+
+		// Nor for the messages of errors passed next to a %w verb, or
+		// concatenated with another one.
+		fmt.Errorf("query failed: %w, then %s", ErrPrimaryDown, err.Error()),            // want `err.Error\(\) formatted with %s in fmt.Errorf drops the error from the chain`
+		fmt.Errorf("query failed: " + ErrPrimaryDown.Error() + ", then " + err.Error()), // want `ErrPrimaryDown.Error\(\) concatenated` `err.Error\(\) concatenated`
+	}
+}
@@ -0,0 +1,24 @@
+package allow
+
+import "errors"
+
+var (
+	ErrMiss  = errors.New("cache miss")  // want ErrMiss:"sentinel"
+	ErrStale = errors.New("stale entry") // want ErrStale:"sentinel"
+)
+
+func get() error { // want get:"returns allow.ErrMiss"
+	return ErrMiss
+}
+
+func lookup() {
+	err := get()
+
+	// ErrMiss is allowed by the configuration of this fixture.
+	if err == ErrMiss {
+		println("miss")
+	}
+	if err == ErrStale { // want `comparing errors with == fails on wrapped errors; use errors.Is`
+		println("stale")
+	}
+}
@@ -0,0 +1,56 @@
+package assertion
+
+import (
+	"fmt"
+	"net"
+)
+
+type NotFoundError struct {
+	Item string
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Item + " not found"
+}
+
+func find() error {
+	return &NotFoundError{Item: "document"}
+}
+
+func assert() {
+	err := find()
+
+	notFound, ok := err.(*NotFoundError) // want `type assertion on error fails on wrapped errors; use errors.As`
+	if ok {
+		fmt.Println(notFound.Item)
+	}
+
+	if e, ok := err.(*NotFoundError); ok { // want `type assertion on error fails on wrapped errors; use errors.As`
+		fmt.Println(e.Item)
+	}
+
+	if _, ok := err.(*NotFoundError); ok { // want `type assertion on error fails on wrapped errors; use errors.As`
+		fmt.Println("not found")
+	}
+
+	if ne, ok := err.(net.Error); ok && ne.Timeout() { // want `type assertion to net.Error fails on wrapped errors; use errors.As with a net.Error target`
+		fmt.Println("timeout")
+	}
+
+	// Single-value assertions panic on failure, so they get no fix.
+	fmt.Println(err.(*NotFoundError).Item) // want `type assertion on error fails on wrapped errors; use errors.As`
+
+	// Inspecting how an error is wrapped is fine.
+	if u, ok := err.(interface{ Unwrap() []error }); ok {
+		fmt.Println(len(u.Unwrap()))
+	}
+}
+
+type matchError struct{}
+
+func (matchError) Error() string { return "match" }
+
+func (matchError) Is(target error) bool {
+	_, ok := target.(*NotFoundError)
+	return ok
+}
@@ -0,0 +1,95 @@
+package astarget
+
+import (
+	"errors"
+	"fmt"
+)
+
+type NotFoundError struct {
+	Key string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s not found", e.Key)
+}
+
+func missing(errs []error) []string {
+	var keys []string
+	var nf *NotFoundError
+	for _, err := range errs {
+		errors.As(err, &nf) // want `the result of errors.As is ignored, but nf may still hold the error matched in an earlier iteration of the loop; check the result, or declare nf in the loop`
+		if nf != nil {
+			keys = append(keys, nf.Key)
+		}
+	}
+	return keys
+}
+
+func both(first, second error) (string, string) {
+	var nf *NotFoundError
+	var a, b string
+	if errors.As(first, &nf) {
+		a = nf.Key
+	}
+	_ = errors.As(second, &nf) // want `the result of errors.As is ignored, but nf may still hold the error matched by errors.As on line 31; check the result, or set nf to nil before the call`
+	if nf != nil {
+		b = nf.Key
+	}
+	return a, b
+}
+
+// Checking the result, resetting the target and declaring it in the loop
+// are fine, and so is a single call.
+func checked(errs []error) []string {
+	var keys []string
+	var nf *NotFoundError
+	for _, err := range errs {
+		if errors.As(err, &nf) {
+			keys = append(keys, nf.Key)
+		}
+	}
+	return keys
+}
+
+func reset(errs []error) []string {
+	var keys []string
+	var nf *NotFoundError
+	for _, err := range errs {
+		nf = nil
+		errors.As(err, &nf)
+		if nf != nil {
+			keys = append(keys, nf.Key)
+		}
+	}
+	return keys
+}
+
+func declared(errs []error) []string {
+	var keys []string
+	for _, err := range errs {
+		var nf *NotFoundError
+		errors.As(err, &nf)
+		if nf != nil {
+			keys = append(keys, nf.Key)
+		}
+	}
+	return keys
+}
+
+func single(err error) string {
+	var nf *NotFoundError
+	errors.As(err, &nf)
+	if nf != nil {
+		return nf.Key
+	}
+	return ""
+}
+
+func resetBetween(first, second error) bool {
+	var nf *NotFoundError
+	errors.As(first, &nf)
+	found := nf != nil
+	nf = nil
+	errors.As(second, &nf)
+	return found && nf != nil
+}
@@ -0,0 +1,131 @@
+package boxed
+
+import (
+	"errors"
+	"io"
+	"strconv"
+)
+
+var ErrNotFound = errors.New("not found") // want ErrNotFound:"^sentinel$"
+
+type ParseError struct {
+	Line int
+}
+
+func (e *ParseError) Error() string {
+	return "parse error on line " + strconv.Itoa(e.Line)
+}
+
+func parse(line string) *ParseError {
+	if line == "" {
+		return &ParseError{}
+	}
+	return nil
+}
+
+func check(line string) bool {
+	var err error = parse(line)
+	return err != nil // want `err != nil is true even if err holds a nil \*ParseError, as stored at line 27: an interface that holds a nil pointer is not nil; check the \*ParseError for nil before storing it in err`
+}
+
+func checkLater(line string) bool {
+	var err error
+	err = parse(line)
+	return err == nil // want `err == nil is false even if err holds a nil \*ParseError, as stored at line 33: an interface that holds a nil pointer is not nil; check the \*ParseError for nil before storing it in err`
+}
+
+func failed(lines []string) []string {
+	results := make(map[string]error)
+	for _, line := range lines {
+		results[line] = parse(line)
+	}
+	var bad []string
+	for line := range results {
+		if results[line] != nil { // want `results\[line\] != nil is true even if the value is a nil \*ParseError, as stored at line 40: an interface that holds a nil pointer is not nil; check the \*ParseError for nil before storing it in results`
+			bad = append(bad, line)
+		}
+	}
+	for _, err := range results {
+		if err != nil { // want `err != nil is true even if err holds a nil \*ParseError, as stored at line 40: an interface that holds a nil pointer is not nil; check the \*ParseError for nil before storing it in err`
+			bad = append(bad, err.Error())
+		}
+	}
+	if err, ok := results["header"]; ok && err != nil { // want `err != nil is true even if err holds a nil \*ParseError, as stored at line 40: an interface that holds a nil pointer is not nil; check the \*ParseError for nil before storing it in err`
+		bad = append(bad, "header")
+	}
+	return bad
+}
+
+func converted(line string) bool {
+	pe := parse(line)
+	return error(pe) != nil // want `error\(pe\) != nil is always true: converting pe to an interface gives a value that is not nil, even if pe is a nil \*ParseError; compare pe with nil before the conversion`
+}
+
+func fromAny(v any) bool {
+	return v == ErrNotFound // want `v has type any, so == compares the value it holds with the error ErrNotFound and misses it once it is wrapped; assert v to error and use errors.Is`
+}
+
+func fromAnyMap(values map[string]any) bool {
+	return ErrNotFound != values["err"] // want `values\["err"\] has type any, so != compares the value it holds with the error ErrNotFound and misses it once it is wrapped; assert values\["err"\] to error and use errors.Is`
+}
+
+// Checking the pointer, storing interface errors, values that are never
+// nil and allowlisted sentinels are fine.
+func checkPointer(line string) error {
+	if pe := parse(line); pe != nil {
+		return pe
+	}
+	return nil
+}
+
+func mixed(line string) bool {
+	var err error = parse(line)
+	if line == "-" {
+		err = ErrNotFound
+	}
+	return err != nil
+}
+
+func neverNil(line string) bool {
+	var err error
+	if line == "" {
+		err = &ParseError{}
+	}
+	return err != nil
+}
+
+func passedOn(lines []string) int {
+	results := make(map[string]error)
+	for _, line := range lines {
+		results[line] = parse(line)
+	}
+	record(results)
+	n := 0
+	for _, err := range results {
+		if err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+func record(map[string]error) {}
+
+func addressed(line string) bool {
+	var err error = parse(line)
+	reset(&err)
+	return err != nil
+}
+
+func reset(err *error) {
+	*err = nil
+}
+
+func anyEOF(v any) bool {
+	return v == io.EOF
+}
+
+func asserted(v any) bool {
+	err, ok := v.(error)
+	return ok && errors.Is(err, ErrNotFound)
+}
@@ -0,0 +1,72 @@
+package comparison
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+)
+
+var ErrInvalidInput = errors.New("invalid input") // want ErrInvalidInput:"sentinel"
+
+func fetch() error { // want fetch:"returns comparison.ErrInvalidInput"
+	return ErrInvalidInput
+}
+
+func compare() {
+	err := fetch()
+
+	if err == ErrInvalidInput { // want `comparing errors with == fails on wrapped errors; use errors.Is`
+		println("invalid input")
+	}
+	if err != ErrInvalidInput { // want `comparing errors with != fails on wrapped errors; use errors.Is`
+		println("other error")
+	}
+	if ErrInvalidInput == err { // want `comparing errors with == fails on wrapped errors; use errors.Is`
+		println("invalid input")
+	}
+
+	// Nil checks and documented unwrapped sentinels are fine.
+	if err == nil || err == io.EOF || err == sql.ErrNoRows {
+		println("ok")
+	}
+
+	if err == context.Canceled { // want `comparing with == fails for context.Canceled, which is usually returned wrapped \(net/http and database/sql wrap it\); use errors.Is`
+		println("canceled")
+	}
+
+	if errors.Join(err, io.EOF) == ErrInvalidInput { // want `comparing a joined error with == never matches; use errors.Is`
+		println("never")
+	}
+}
+
+type matchError struct{}
+
+func (matchError) Error() string { return "match" }
+
+// Is implements the matching errors.Is relies on, so == is correct here.
+func (matchError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
+
+var ErrNotFound = errors.New("not found") // want ErrNotFound:"sentinel"
+
+// negated compares errors in conditions negated as a whole, whose fixes
+// move the negation inwards.
+func negated(ok bool) bool {
+	err := fetch()
+
+	if !(err != ErrInvalidInput) { // want `comparing errors with != fails on wrapped errors; use errors.Is`
+		println("invalid input")
+	}
+	if err != nil && !(err == ErrInvalidInput) { // want `comparing errors with == fails on wrapped errors; use errors.Is`
+		println("other error")
+	}
+	if !(err == ErrInvalidInput || err == ErrNotFound) { // want `comparing errors with == fails on wrapped errors; use errors.Is` `comparing errors with == fails on wrapped errors; use errors.Is`
+		println("other error")
+	}
+	if !(err == nil || err == io.EOF || err == ErrNotFound) && ok { // want `comparing errors with == fails on wrapped errors; use errors.Is`
+		println("other error")
+	}
+	return ok == !(err != nil && err != ErrNotFound) // want `comparing errors with != fails on wrapped errors; use errors.Is`
+}
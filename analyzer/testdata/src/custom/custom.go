@@ -0,0 +1,46 @@
+package custom
+
+import (
+	"errors"
+
+	"custom/errs"
+)
+
+func load() error { return nil }
+
+func wrapped() error {
+	if err := load(); err != nil {
+		return errs.LegacyWrap(err, "loading") // want `errs.LegacyWrap\(err, "loading"\) is deprecated; wrap with fmt.Errorf and %w \[legacywrap\]`
+	}
+	var w errs.Wrapper
+	if err := load(); err != nil {
+		return w.Wrap(err, "loading") // want `call of custom/errs.Wrapper.Wrap is not allowed \[wrapper\]`
+	}
+	wrap := errs.LegacyWrap // references that are not calls are fine
+	_ = wrap
+	return nil
+}
+
+func compared(err error) string {
+	if err == errs.ErrFoo { // want `err == errs.ErrFoo: custom/errs.ErrFoo is going away \[errfoo\]`
+		return "foo"
+	}
+	if errors.Is(err, errs.ErrFoo) { // want `errors.Is\(err, errs.ErrFoo\): custom/errs.ErrFoo is going away \[errfoo\]`
+		return "foo"
+	}
+	switch err {
+	case errs.ErrNotFound, errs.ErrFoo: // want `err == errs.ErrFoo: custom/errs.ErrFoo is going away \[errfoo\]`
+		return "missing"
+	}
+	//errlint:ignore errfoo kept until the store stops returning it
+	if errs.ErrFoo != err {
+		return "other"
+	}
+	// want +1 `errlint:ignore directive suppresses no legacywrap finding; remove it`
+	//errlint:ignore legacywrap nothing is reported here
+	return "foo"
+}
+
+func other(err error) bool {
+	return errors.Is(err, errs.ErrNotFound)
+}
@@ -0,0 +1,21 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrFoo      = errors.New("foo")
+	ErrNotFound = errors.New("not found")
+)
+
+func LegacyWrap(err error, msg string) error {
+	return fmt.Errorf("%s: %v", msg, err)
+}
+
+type Wrapper struct{}
+
+func (*Wrapper) Wrap(err error, msg string) error {
+	return LegacyWrap(err, msg)
+}
@@ -0,0 +1,177 @@
+package deferwrap
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+)
+
+var errGlobal error // want errGlobal:"sentinel"
+
+func open(name string) (io.ReadCloser, error) { return nil, errors.New(name) }
+
+func remove(name string) error { return errors.New(name) }
+
+// The named result is wrapped only if it is not nil.
+func good(name string) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("good %s: %w", name, err)
+		}
+	}()
+	_, err = open(name)
+	return err
+}
+
+// So is an early return.
+func earlyReturn(name string) (err error) {
+	defer func() {
+		if err == nil {
+			return
+		}
+		err = fmt.Errorf("early %s: %w", name, err)
+	}()
+	_, err = open(name)
+	return err
+}
+
+// So is matching the error, which never matches nil.
+func matched(name string) (err error) {
+	defer func() {
+		if _, ok := errors.AsType[*fs.PathError](err); ok {
+			err = fmt.Errorf("matched: %w", err)
+		}
+	}()
+	_, err = open(name)
+	return err
+}
+
+// Adding a second error makes the function fail on purpose.
+func cleanup(name string) (err error) {
+	defer func() {
+		if cerr := remove(name); cerr != nil {
+			err = fmt.Errorf("%w; cleaning up: %w", err, cerr)
+		}
+	}()
+	_, err = open(name)
+	return err
+}
+
+func unchecked(name string) (err error) {
+	defer func() {
+		err = fmt.Errorf("unchecked %s: %w", name, err) // want `deferred fmt.Errorf wraps err even when it is nil, so the function fails when it succeeds; check err != nil first`
+	}()
+	_, err = open(name)
+	return err
+}
+
+func oneLine(name string) (err error) {
+	defer func() { err = fmt.Errorf("one line %s: %w", name, err) }() // want `deferred fmt.Errorf wraps err even when it is nil`
+	_, err = open(name)
+	return err
+}
+
+// Other wrappers may return nil for nil.
+func joined(name string) (err error) {
+	f, err := open(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, f.Close())
+	}()
+	return nil
+}
+
+func shadowed(name string, verbose bool) (err error) {
+	if verbose {
+		_, err := open(name)
+		defer func() {
+			if err != nil {
+				err = fmt.Errorf("shadowed %s: %w", name, err) // want `deferred function wraps err declared at line 90, which shadows the named result err; the returned error is not wrapped`
+			}
+		}()
+		log.Print(err)
+	}
+	return nil
+}
+
+func closeErr(name string) (err error) {
+	f, err := open(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			err = fmt.Errorf("closing %s: %w", name, err) // want `deferred function wraps err declared at line 107, which shadows the named result err`
+		}
+	}()
+	return nil
+}
+
+func otherName(name string) (failure error) {
+	_, err := open(name)
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("other %s: %w", name, err) // want `deferred function wraps err, not the error result failure; the returned error is not wrapped`
+		}
+	}()
+	return err
+}
+
+func unnamed(name string) error {
+	_, err := open(name)
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("unnamed %s: %w", name, err) // want `deferred function wraps err, which the function does not return; name the error result, as in \(err error\), to change it`
+		}
+	}()
+	return err
+}
+
+// A variable of the deferred function that is used after it is wrapped.
+func logged(name string) (err error) {
+	f, err := open(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			err = fmt.Errorf("closing %s: %w", name, err)
+			log.Print(err)
+		}
+	}()
+	return nil
+}
+
+// Package-level variables outlive the function.
+func global(name string) (err error) {
+	defer func() {
+		if errGlobal != nil {
+			errGlobal = fmt.Errorf("global %s: %w", name, errGlobal)
+		}
+	}()
+	return nil
+}
+
+// Functions without an error result are not reported.
+func noError(name string) {
+	_, err := open(name)
+	defer func() {
+		err = fmt.Errorf("no error %s: %w", name, err)
+		log.Print(err)
+	}()
+}
+
+// Nested functions run at other times.
+func nested(name string) (err error) {
+	defer func() {
+		retry := func() {
+			err = fmt.Errorf("nested %s: %w", name, err)
+		}
+		_ = retry
+	}()
+	return nil
+}
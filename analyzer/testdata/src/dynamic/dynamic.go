@@ -0,0 +1,42 @@
+package dynamic
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Package-level sentinels are what the check asks for.
+var ErrNotFound = errors.New("not found") // want ErrNotFound:"sentinel"
+
+func find(name string) error { // want find:"wraps dynamic.ErrNotFound"
+	switch name {
+	case "":
+		return errors.New("empty name") // want `errors.New creates a new error on every call, which callers cannot match with errors.Is; return a package-level sentinel`
+	case "root":
+		return fmt.Errorf("user %q is reserved", name) // want `fmt.Errorf without %w creates a new error on every call, which callers cannot match with errors.Is; wrap a package-level sentinel with %w`
+	case "admin":
+		return (errors.New("admin")) // want `errors.New creates a new error on every call`
+	}
+	return fmt.Errorf("user %q: %w", name, ErrNotFound)
+}
+
+func compare(err error) bool {
+	if err == errors.New("empty name") { // want `comparing with a new error from errors.New never matches; compare with a package-level sentinel`
+		return true
+	}
+	if errors.Is(err, fmt.Errorf("user %q is reserved", "root")) { // want `errors.Is with a new error from fmt.Errorf without %w never matches; compare with a package-level sentinel`
+		return true
+	}
+	return errors.Is(err, ErrNotFound)
+}
+
+func notReported(format string, args ...any) error {
+	// Errors built for other uses, and calls whose format is only known
+	// at run time, are fine.
+	err := errors.New("logged")
+	println(err.Error())
+	if format != "" {
+		return fmt.Errorf(format, args...)
+	}
+	return err
+}
@@ -0,0 +1,54 @@
+package errorf
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errInput = errors.New("input validation failed") // want errInput:"sentinel"
+
+type request struct{ id int }
+
+func wrap(req request, v any, args ...any) []error {
+	other := errors.New("other")
+	return []error{
+		fmt.Errorf("operation failed: %v", errInput),              // want `error formatted with %v in fmt.Errorf is not wrapped; use %w`
+		fmt.Errorf("operation failed: %s", errInput),              // want `error formatted with %s in fmt.Errorf is not wrapped; use %w`
+		fmt.Errorf("%d: %[1]d %[3]v", 1, 2, errInput),             // want `error formatted with %v in fmt.Errorf is not wrapped; use %w`
+		fmt.Errorf("multiple errors: %v and %v", errInput, other), // want `error formatted with %v in fmt.Errorf is not wrapped; use %w` `error formatted with %v in fmt.Errorf is not wrapped; use %w`
+		fmt.Errorf("escaped\t%v", errInput),                       // want `error formatted with %v in fmt.Errorf is not wrapped; use %w`
+		fmt.Errorf("missing %w"),                                  // want `%w verb in fmt.Errorf has no matching argument`
+		fmt.Errorf("bad index %[2]w", errInput),                   // want `%w verb in fmt.Errorf has no matching argument` `error argument of fmt.Errorf has no verb and is not wrapped`
+		fmt.Errorf("no verb", errInput),                           // want `error argument of fmt.Errorf has no verb and is not wrapped`
+		fmt.Errorf("reading %w", "config"),                        // want `%w verb in fmt.Errorf has an argument of type string, which is not an error; use %v`
+		fmt.Errorf("request %w: %w", req, errInput),               // want `%w verb in fmt.Errorf has an argument of type request, which is not an error; use %v`
+		fmt.Errorf("escaped\t%w: %v", 42, errInput),               // want `%w verb in fmt.Errorf has an argument of type int, which is not an error; use %v` `error formatted with %v in fmt.Errorf is not wrapped; use %w`
+
+		// The format takes more or fewer arguments than the call passes.
+		fmt.Errorf("reading %s: %w", errInput),                       // want `%w verb in fmt.Errorf has no matching argument: the format takes 2 arguments but the call passes 1`
+		fmt.Errorf("request %d: %s", errInput),                       // want `%s verb in fmt.Errorf has no matching argument: the format takes 2 arguments but the call passes 1`
+		fmt.Errorf("reading %s: %w", "config", "app.yaml", errInput), // want `error argument of fmt.Errorf has no verb and is not wrapped: the format takes 2 arguments but the call passes 3, so fmt appends it to the message as %!\(EXTRA ...\)`
+		fmt.Errorf("disk 100%%w full", errInput),                     // want `error argument of fmt.Errorf has no verb and is not wrapped: the format takes no arguments but the call passes 1,`
+		fmt.Errorf("disk %d%%: %w", 90),                              // want `%w verb in fmt.Errorf has no matching argument: the format takes 2 arguments but the call passes 1`
+		fmt.Errorf("%*d: %w", 4, errInput),                           // want `%w verb in fmt.Errorf has no matching argument: the format takes 3 arguments but the call passes 2`
+		fmt.Errorf("%[1]s", "config", errInput),                      // want `error argument of fmt.Errorf has no verb and is not wrapped \[ERRLINT004\]`
+
+		// Wrapped errors and non-error arguments are fine.
+		fmt.Errorf("operation failed: %w", errInput),
+		fmt.Errorf("both: %w and %w", errInput, other),
+		fmt.Errorf("item %v", "document"),
+		fmt.Errorf("disk %d%% full: %w", 90, errInput),
+		fmt.Errorf("%*d: %w", 4, 2, errInput),
+		fmt.Errorf("%[2]s: %[1]w", errInput, "config"),
+		fmt.Errorf("%[1]s %[1]q: %w", "config", errInput),
+
+		// Without errors, a mismatch is left to go vet.
+		fmt.Errorf("item %v %v", "document"),
+
+		// An interface value may hold an error at run time.
+		fmt.Errorf("value: %w", v),
+
+		// The verbs of spread arguments are only known at run time.
+		fmt.Errorf("spread %v %w", args...),
+	}
+}
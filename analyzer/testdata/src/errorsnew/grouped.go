@@ -0,0 +1,14 @@
+package errorsnew
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+func grouped(r io.Reader) error { // want grouped:"returns errors.ErrUnsupported"
+	if r == nil {
+		return fmt.Errorf("no reader") // want `fmt.Errorf without verbs or arguments; use errors.New`
+	}
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,12 @@
+package errorsnew
+
+import "fmt"
+
+func ignored() error {
+	//errlint:ignore errorsnew kept as fmt.Errorf to match the generated code
+	return fmt.Errorf("ignored")
+}
+
+func fixed() error {
+	return fmt.Errorf("fixed") // want `fmt.Errorf without verbs or arguments; use errors.New`
+}
@@ -0,0 +1,8 @@
+package errorsnew
+
+import "fmt"
+
+var (
+	ErrClosed  = fmt.Errorf("closed")  // want ErrClosed:"sentinel" `fmt.Errorf without verbs or arguments; use errors.New`
+	ErrTimeout = fmt.Errorf(`timeout`) // want ErrTimeout:"sentinel" `fmt.Errorf without verbs or arguments; use errors.New`
+)
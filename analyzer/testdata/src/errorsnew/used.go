@@ -0,0 +1,26 @@
+package errorsnew
+
+import (
+	"fmt"
+	"os"
+)
+
+const percent = "100%% done"
+
+func used(name string) error {
+	if name == "" {
+		return fmt.Errorf("empty name") // want `fmt.Errorf without verbs or arguments; use errors.New`
+	}
+	if name == "full" {
+		return fmt.Errorf("disk is 100%% full") // want `fmt.Errorf without verbs or arguments; use errors.New`
+	}
+	if name == "done" {
+		return fmt.Errorf(percent) // want `fmt.Errorf without verbs or arguments; use errors.New`
+	}
+	if name == "half" {
+		// A lone % is formatted as %!(NOVERB).
+		return fmt.Errorf("50%")
+	}
+	fmt.Fprintln(os.Stderr, name)
+	return fmt.Errorf("user %s", name)
+}
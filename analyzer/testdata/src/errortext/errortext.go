@@ -0,0 +1,61 @@
+package errortext
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type parseError struct {
+	line int
+}
+
+func (e *parseError) Error() string {
+	return "parse error on line " + strconv.Itoa(e.line)
+}
+
+func formatted(err error) error {
+	return fmt.Errorf("loading config: %s", err.Error()) // want `err.Error\(\) formatted with %s in fmt.Errorf drops the error from the chain; wrap err with %w`
+}
+
+func verb(err error) error {
+	return fmt.Errorf("loading config: %v", (err.Error())) // want `\(err.Error\(\)\) formatted with %v in fmt.Errorf drops the error from the chain; wrap err with %w`
+}
+
+func concrete(line int) error {
+	err := &parseError{line: line}
+	return fmt.Errorf("loading config: %s", err.Error()) // want `err.Error\(\) formatted with %s in fmt.Errorf drops the error from the chain; wrap err with %w`
+}
+
+func escaped(err error) error {
+	return fmt.Errorf("loading config:\t%s", err.Error()) // want `err.Error\(\) formatted with %s in fmt.Errorf drops the error from the chain; wrap err with %w`
+}
+
+func both(read, closing error) error {
+	return fmt.Errorf("reading: %s, closing: %s", read.Error(), closing.Error()) // want `read.Error\(\) formatted with %s` `closing.Error\(\) formatted with %s`
+}
+
+func quoted(err error) error {
+	// %q quotes the message, which %w would not.
+	return fmt.Errorf("loading config: %q", err.Error())
+}
+
+func concatenated(err error) error {
+	return fmt.Errorf("loading config: " + err.Error()) // want `err.Error\(\) concatenated into the format of fmt.Errorf drops the error from the chain; wrap err with %w`
+}
+
+func named(name string, err error) error {
+	return fmt.Errorf("loading " + name + " at 100%: " + err.Error()) // want `err.Error\(\) concatenated into the format of fmt.Errorf`
+}
+
+func arguments(id int, err error) error {
+	// The fix would have to renumber the verbs of the constant parts.
+	return fmt.Errorf("user %d: "+err.Error(), id) // want `err.Error\(\) concatenated into the format of fmt.Errorf`
+}
+
+func wrapped(err error) error {
+	return fmt.Errorf("loading config: %w", err)
+}
+
+func message(name string) error {
+	return fmt.Errorf("loading " + name)
+}
@@ -0,0 +1,39 @@
+// Package client is a dependency of the facts fixture that documents some
+// of its sentinels to be returned unwrapped and wraps the others.
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotModified is returned as it is when nothing changed.
+	//
+	//errlint:unwrapped
+	ErrNotModified = errors.New("not modified") // want ErrNotModified:"^sentinel returned unwrapped$"
+
+	// ErrNotFound is returned wrapped with the path.
+	ErrNotFound = errors.New("not found") // want ErrNotFound:"^sentinel wrapped by client.Get$"
+)
+
+// ErrRetry is documented unwrapped, but Do wraps it.
+//
+//errlint:unwrapped // want `ErrRetry is documented to be returned unwrapped, but client.Do wraps it; comparisons with == are still reported`
+var ErrRetry = errors.New("retry") // want ErrRetry:"^sentinel documented unwrapped, wrapped by client.Do$"
+
+// Variables of other types are not sentinels.
+//
+//errlint:unwrapped
+var timeout = 3
+
+func Get(path string, cached bool) error { // want Get:"^returns facts/client.ErrNotModified; wraps facts/client.ErrNotFound$"
+	if cached {
+		return ErrNotModified
+	}
+	return fmt.Errorf("get %s: %w", path, ErrNotFound)
+}
+
+func Do() error { // want Do:"^wraps facts/client.ErrRetry$"
+	return fmt.Errorf("do: %w", ErrRetry)
+}
@@ -0,0 +1,76 @@
+package facts
+
+import (
+	"fmt"
+
+	"facts/client"
+	"facts/kv"
+	"facts/store"
+)
+
+func open(db *store.DB) error { // want open:"^wraps facts/store.ErrClosed$"
+	return fmt.Errorf("open: %w", db.Close())
+}
+
+func lookup(db *store.DB, key string) {
+	// The sentinel is returned as is here, but store.Load wraps it.
+	if err := store.Get(key); err == store.ErrNotFound { // want `comparing with == fails for store.ErrNotFound, which is usually returned wrapped \(store.Load wraps it\); use errors.Is`
+		println("not found")
+	}
+
+	if err := store.Load(key); err == store.ErrNotFound { // want `comparing with == never matches: err only holds store.ErrNotFound wrapped by store.Load; use errors.Is`
+		println("never")
+	}
+
+	if err := open(db); err != store.ErrClosed { // want `comparing with != never matches: err only holds store.ErrClosed wrapped by facts.open; use errors.Is`
+		println("always")
+	}
+
+	// Without facts about q, the error may hold anything.
+	if err := db.Query(func() error { return nil }); err == store.ErrClosed { // want `comparing errors with == fails on wrapped errors; use errors.Is`
+		println("closed")
+	}
+
+	// err holds the results of both calls.
+	err := db.Close()
+	if err == nil {
+		err = open(db)
+	}
+	if err == store.ErrClosed { // want `comparing with == fails: err can hold store.ErrClosed wrapped by facts.open; use errors.Is`
+		println("closed")
+	}
+}
+
+func retry(key string) {
+	// Only the assignments before the comparison are taken into account.
+	err := store.Get(key)
+	if err == store.ErrNotFound { // want `comparing with == fails for store.ErrNotFound, which is usually returned wrapped \(store.Load wraps it\); use errors.Is`
+		err = store.Load(key)
+	}
+	println(err)
+}
+
+func documented(key string, cached bool) {
+	// Sentinels documented to be returned unwrapped may be compared.
+	if _, err := kv.Get(key); err == kv.ErrNil {
+		println("nil")
+	}
+	if err := client.Get(key, cached); err == client.ErrNotModified {
+		println("not modified")
+	}
+
+	// Unless the error is known to hold them wrapped.
+	if err := fmt.Errorf("get: %w", kv.ErrNil); err == kv.ErrNil { // want `comparing with == never matches: err only holds kv.ErrNil wrapped; use errors.Is`
+		println("never")
+	}
+
+	// The others are still reported.
+	if err := client.Get(key, cached); err == client.ErrNotFound { // want `comparing with == never matches: err only holds client.ErrNotFound wrapped by client.Get; use errors.Is`
+		println("never")
+	}
+}
+
+// A directive does not allow comparing a sentinel its package wraps.
+func retryable(err error) bool {
+	return err == client.ErrRetry // want `comparing with == fails for client.ErrRetry, which is usually returned wrapped \(client.Do wraps it\); use errors.Is`
+}
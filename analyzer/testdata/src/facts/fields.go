@@ -0,0 +1,108 @@
+package facts
+
+import (
+	"fmt"
+	"io"
+)
+
+type result struct {
+	n   int
+	err error
+}
+
+type batch struct {
+	last   result
+	errs   map[string]error
+	failed *result
+}
+
+func fields(r io.Reader) {
+	// A field holds what was stored in it last, like a local variable.
+	var res result
+	res.n, res.err = r.Read(nil)
+	res.err = fmt.Errorf("read: %w", res.err)
+	if res.err == io.EOF { // want `comparing with == never matches: res.err holds a wrapped error, never io.EOF itself; use errors.Is`
+		println("done")
+	}
+
+	// Composite literals store their fields too.
+	b := batch{last: result{err: fmt.Errorf("read: %w", io.EOF)}}
+	if b.last.err == io.EOF { // want `comparing with == never matches: b.last.err only holds io.EOF wrapped; use errors.Is`
+		println("done")
+	}
+
+	// Through a pointer, as long as it is not copied.
+	p := &result{}
+	if busy() {
+		p.err = fmt.Errorf("retry: %w", errBusy)
+	}
+	if p.err == errBusy { // want `comparing with == never matches: p.err only holds errBusy wrapped; use errors.Is`
+		println("busy")
+	}
+
+	// A store at the field of the copy of a pointer changes the field too.
+	b.failed = &result{err: fmt.Errorf("retry: %w", errBusy)}
+	failed := b.failed
+	failed.err = errBusy
+	if b.failed.err == errBusy { // want `comparing with == fails: b.failed.err can hold errBusy wrapped; use errors.Is`
+		println("busy")
+	}
+}
+
+func maps(keys []string) {
+	// Map values hold any of the values stored in the map.
+	errs := map[string]error{"a": fmt.Errorf("a: %w", io.EOF)}
+	for _, k := range keys {
+		errs[k] = fmt.Errorf("%s: %w", k, io.EOF)
+	}
+	for _, k := range keys {
+		if errs[k] == io.EOF { // want `comparing with == never matches: errs\[k\] only holds io.EOF wrapped; use errors.Is`
+			println("done")
+		}
+	}
+
+	// A store at one key does not replace the values at the others.
+	errs["b"] = io.EOF
+	if errs["a"] == io.EOF { // want `comparing with == fails: errs\["a"\] can hold io.EOF wrapped; use errors.Is`
+		println("done")
+	}
+
+	b := batch{errs: make(map[string]error)}
+	b.errs["c"] = fmt.Errorf("c: %w", errBusy)
+	if b.errs["c"] != errBusy { // want `comparing with != never matches: b.errs\["c"\] only holds errBusy wrapped; use errors.Is`
+		println("always")
+	}
+}
+
+func escaped(r result) {
+	// A field whose address is taken can change anywhere, but it may
+	// still hold the wrapped error.
+	var res result
+	res.err = fmt.Errorf("read: %w", io.EOF)
+	fill(&res)
+	if res.err == io.EOF { // want `comparing with == fails: res.err can hold io.EOF wrapped; use errors.Is`
+		println("done")
+	}
+
+	// So can the values of a map passed to a function.
+	errs := map[string]error{"a": fmt.Errorf("a: %w", io.EOF)}
+	reset(errs)
+	if errs["a"] == io.EOF { // want `comparing with == fails: errs\["a"\] can hold io.EOF wrapped; use errors.Is`
+		println("done")
+	}
+
+	// Fields of parameters hold whatever the caller stored.
+	if r.err == io.EOF {
+		println("done")
+	}
+}
+
+func fill(res *result) { res.err = io.EOF }
+
+func reset(errs map[string]error) {
+	for k := range errs {
+		errs[k] = io.EOF
+	}
+}
+
+func busy() bool { return false }
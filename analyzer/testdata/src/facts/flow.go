@@ -0,0 +1,64 @@
+package facts
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+var errBusy = errors.New("busy") // want errBusy:"sentinel"
+
+type opError struct{ err error }
+
+func (e *opError) Error() string { return "op: " + e.err.Error() }
+func (e *opError) Unwrap() error { return e.err }
+
+func wrapped(r io.Reader, busy bool) {
+	// Wrapping replaces the error err held before.
+	err := errBusy
+	err = fmt.Errorf("retry: %w", err)
+	if err == errBusy { // want `comparing with == never matches: err only holds errBusy wrapped; use errors.Is`
+		println("busy")
+	}
+
+	// io.EOF is returned as is, but not once it is wrapped.
+	_, err = r.Read(nil)
+	err = &opError{err}
+	if err == io.EOF { // want `comparing with == never matches: err holds a wrapped error, never io.EOF itself; use errors.Is`
+		println("done")
+	}
+
+	// The wrapping only happens on one branch.
+	err = errBusy
+	if busy {
+		err = fmt.Errorf("retry: %w", err)
+	}
+	if err == errBusy { // want `comparing with == fails: err can hold errBusy wrapped; use errors.Is`
+		println("busy")
+	}
+
+	// A later assignment hides the wrapped error again.
+	err = fmt.Errorf("retry: %w", err)
+	err = errBusy
+	if err == errBusy { // want `comparing errors with == fails on wrapped errors; use errors.Is`
+		println("busy")
+	}
+}
+
+func handle(err error) {
+	err = fmt.Errorf("handle: %w", err)
+	if err == io.EOF { // want `comparing with == never matches: err holds a wrapped error, never io.EOF itself; use errors.Is`
+		println("done")
+	}
+}
+
+func closure() {
+	err := errBusy
+	reset := func() { err = errBusy }
+	err = fmt.Errorf("retry: %w", err)
+	// The closure may run at any time and reset err.
+	reset()
+	if err == errBusy { // want `comparing with == fails: err can hold errBusy wrapped; use errors.Is`
+		println("busy")
+	}
+}
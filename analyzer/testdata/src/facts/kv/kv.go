@@ -0,0 +1,17 @@
+// Package kv is a dependency of the facts fixture that documents its
+// sentinels to be returned unwrapped, as io.EOF is.
+//
+//errlint:unwrapped
+package kv
+
+import "errors"
+
+// ErrNil is returned when a key does not exist.
+var ErrNil = errors.New("kv: nil") // want ErrNil:"^sentinel returned unwrapped$"
+
+func Get(key string) (string, error) { // want Get:"^returns facts/kv.ErrNil$"
+	if key == "" {
+		return "", ErrNil
+	}
+	return key, nil
+}
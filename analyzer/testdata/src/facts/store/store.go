@@ -0,0 +1,46 @@
+// Package store is a dependency of the facts fixture. Its sentinels and
+// the functions returning them are described by facts.
+package store
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotFound = errors.New("not found") // want ErrNotFound:"sentinel wrapped by store.Load"
+	ErrClosed   = errors.New("closed")    // want ErrClosed:"^sentinel$"
+)
+
+func Get(key string) error { // want Get:"^returns facts/store.ErrNotFound$"
+	if key == "" {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func Load(key string) error { // want Load:"^wraps facts/store.ErrNotFound$"
+	if err := Get(key); err != nil {
+		return fmt.Errorf("load %s: %w", key, err)
+	}
+	return nil
+}
+
+type DB struct {
+	closed bool
+}
+
+func (db *DB) Close() error { // want Close:"^returns facts/store.ErrClosed$"
+	if db.closed {
+		return ErrClosed
+	}
+	db.closed = true
+	return nil
+}
+
+func (db *DB) Query(q func() error) error { // want Query:"returns facts/store.ErrClosed; returns other errors"
+	if db.closed {
+		return ErrClosed
+	}
+	return q()
+}
@@ -0,0 +1,41 @@
+package facts
+
+import (
+	"fmt"
+
+	"facts/store"
+)
+
+// fetch passes on the errors of store.Load as they are, and find those of
+// fetch, so the sentinel is wrapped further and further down.
+func fetch(key string) error { // want fetch:"^wraps facts/store.ErrNotFound \\(via store.Load\\)$"
+	return store.Load(key)
+}
+
+func find(key string) error { // want find:"^wraps facts/store.ErrNotFound \\(via facts.fetch, store.Load\\)$"
+	if err := fetch(key); err != nil {
+		return err
+	}
+	return nil
+}
+
+// reload wraps the error itself, so its callers only need to look at it.
+func reload(key string) error { // want reload:"^wraps facts/store.ErrNotFound$"
+	if err := find(key); err != nil {
+		return fmt.Errorf("reload: %w", err)
+	}
+	return nil
+}
+
+func traced(key string) {
+	if err := fetch(key); err == store.ErrNotFound { // want `comparing with == never matches: err only holds store.ErrNotFound wrapped by store.Load, 2 calls down: facts.fetch returns the error of store.Load as it is; use errors.Is`
+		println("never")
+	}
+	err := find(key)
+	if err == store.ErrNotFound { // want `comparing with == never matches: err only holds store.ErrNotFound wrapped by store.Load, 3 calls down: facts.find returns the error of facts.fetch, and facts.fetch that of store.Load, as they are; use errors.Is`
+		println("never")
+	}
+	if err := reload(key); err == store.ErrNotFound { // want `comparing with == never matches: err only holds store.ErrNotFound wrapped by facts.reload; use errors.Is`
+		println("never")
+	}
+}
@@ -0,0 +1,45 @@
+package ignore
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errMiss = errors.New("miss") // want errMiss:"sentinel"
+
+func suppressed(err error) bool {
+	if err == errMiss { //errlint:ignore ERRLINT001 compared before anything wraps it
+		return true
+	}
+	//errlint:ignore comparison checks are named by ID or name
+	if err == errMiss {
+		return true
+	}
+	//errlint:ignore ERRLINT001,ERRLINT004 one directive may name several checks
+	return err == errMiss || fmt.Errorf("get: %v", err) != nil
+}
+
+func unused(err error) bool {
+	// want +1 `errlint:ignore directive suppresses no ERRLINT001 finding; remove it`
+	//errlint:ignore ERRLINT001 the comparison below was fixed long ago
+	if errors.Is(err, errMiss) {
+		return true
+	}
+	// want +1 `errlint:ignore directive suppresses no ERRLINT004 finding; remove ERRLINT004 from it`
+	//errlint:ignore ERRLINT001,ERRLINT004 only the comparison is left
+	return err == errMiss
+}
+
+func malformed(err error) bool {
+	// want +1 `errlint:ignore directive needs a reason after the checks it suppresses`
+	//errlint:ignore ERRLINT001
+	if err == errMiss {
+		return true
+	}
+	// want +1 `errlint:ignore directive names unknown check "ERRLINT999"`
+	//errlint:ignore ERRLINT999 no such check
+	// want +1 `errlint:ignore directive names no check`
+	//errlint:ignore
+	//errlint:ignored is not a directive
+	return false
+}
@@ -0,0 +1,87 @@
+package ignoredok
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+)
+
+type Point struct{ X, Y int }
+
+func path(err error) {
+	var pathErr *fs.PathError
+	errors.As(err, &pathErr) // want `the result of errors.As is ignored, but pathErr stays nil if err does not match, and pathErr.Path on line 15 dereferences it; check the result`
+	log.Print(pathErr.Path)
+	log.Print("done")
+}
+
+func blankAs(err error) {
+	var pathErr *fs.PathError
+	_ = errors.As(err, &pathErr) // want `the result of errors.As is ignored, but pathErr stays nil if err does not match, and pathErr.Op on line 22 dereferences it; check the result`
+	op := pathErr.Op
+
+	log.Print(op, *pathErr)
+}
+
+type CodeError struct{ Code int }
+
+func (e CodeError) Error() string { return fmt.Sprint(e.Code) }
+
+func code(err error) int {
+	var ce CodeError
+	errors.As(err, &ce) // want `the result of errors.As is ignored, but ce keeps its zero value if err does not match, and ce.Code on line 34 reads it; check the result`
+	return ce.Code
+}
+
+func stringer(v any) {
+	s, _ := v.(fmt.Stringer) // want `the ok result of the type assertion is discarded, but s is nil if v is not a fmt.Stringer, and s.String on line 39 dereferences it; check ok`
+	log.Print(s.String())
+}
+
+func point(v any) int {
+	p, _ := v.(Point) // want `the ok result of the type assertion is discarded, but p is the zero Point if v is not one, and p.X on line 44 reads it; check ok`
+	log.Print(p.X)
+	return p.Y
+}
+
+func assertError(err error) {
+	pe, _ := err.(*fs.PathError) // want `type assertion on error fails on wrapped errors; use errors.As` `the ok result of the type assertion is discarded, but pe is nil if err is not a \*fs.PathError, and pe.Path on line 50 dereferences it; check ok`
+	log.Print(pe.Path)
+}
+
+// Results that are checked, variables compared with nil, targets that hold
+// a value before the call and values that are not dereferenced are fine.
+func checked(err error) {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		log.Print(pathErr.Path)
+	}
+}
+
+func guarded(err error) {
+	var pathErr *fs.PathError
+	errors.As(err, &pathErr)
+	if pathErr != nil {
+		log.Print(pathErr.Path)
+	}
+}
+
+func preset(err error) {
+	pathErr := &fs.PathError{Op: "unknown"}
+	errors.As(err, &pathErr)
+	log.Print(pathErr.Op)
+}
+
+func zero(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+func reassigned(v any) {
+	s, _ := v.(fmt.Stringer)
+	s = stringerOf(v)
+	log.Print(s.String())
+}
+
+func stringerOf(v any) fmt.Stringer { return nil }
@@ -0,0 +1,22 @@
+package cache
+
+import (
+	"errors"
+
+	"internaltype/internal/store"
+)
+
+// Packages of the same tree share their callers, so they may pass the
+// errors of the store on.
+func Get(key string) (string, error) {
+	v, err := store.Get(key)
+	var nf *store.NotFoundError
+	if errors.As(err, &nf) {
+		return "", nf
+	}
+	return v, err
+}
+
+func Lookup(key string) *store.NotFoundError {
+	return &store.NotFoundError{Key: key}
+}
@@ -0,0 +1,21 @@
+package store
+
+import "fmt"
+
+type NotFoundError struct {
+	Key string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("key %s not found", e.Key)
+}
+
+type Timeout struct{}
+
+func (Timeout) Error() string { return "timeout" }
+
+type Row struct{}
+
+func Get(key string) (string, error) {
+	return "", &NotFoundError{Key: key}
+}
@@ -0,0 +1,61 @@
+package internaltype
+
+import (
+	"errors"
+	"fmt"
+
+	"internaltype/internal/store"
+)
+
+var ErrNotFound = errors.New("not found") // want ErrNotFound:"sentinel wrapped by internaltype.Client.Lookup"
+
+type Client struct{}
+
+func (c *Client) Get(key string) (string, error) {
+	v, err := store.Get(key)
+	var nf *store.NotFoundError
+	if errors.As(err, &nf) {
+		return "", nf // want `internaltype.Client.Get returns an error of type \*store.NotFoundError from internal package internaltype/internal/store, which its callers cannot import to match it with errors.As; translate it into an exported error or sentinel of package internaltype`
+	}
+	if err != nil {
+		return "", fmt.Errorf("getting %s: %w", key, err)
+	}
+	return v, nil
+}
+
+func Wait() error {
+	return store.Timeout{} // want `internaltype.Wait returns an error of type store.Timeout from internal package internaltype/internal/store`
+}
+
+func Find(key string) (*store.NotFoundError, bool) { // want `internaltype.Find returns the error type \*store.NotFoundError of internal package internaltype/internal/store, which its callers cannot import; declare the result as error and return an exported error or sentinel of package internaltype`
+	return &store.NotFoundError{Key: key}, false
+}
+
+// Translating the error into a sentinel of the package is fine, and so are
+// errors of other types and values that are not errors.
+func (c *Client) Lookup(key string) (store.Row, error) { // want Lookup:"wraps internaltype.ErrNotFound; returns other errors"
+	_, err := store.Get(key)
+	var nf *store.NotFoundError
+	if errors.As(err, &nf) {
+		return store.Row{}, fmt.Errorf("looking up %s: %w", key, ErrNotFound)
+	}
+	return store.Row{}, err
+}
+
+// Unexported functions, methods of unexported types and function literals
+// only have callers in the package.
+func get(key string) error {
+	return &store.NotFoundError{Key: key}
+}
+
+type client struct{}
+
+func (client) Get(key string) error {
+	return &store.NotFoundError{Key: key}
+}
+
+func Each(keys []string) func() error {
+	return func() error {
+		return &store.NotFoundError{Key: keys[0]}
+	}
+}
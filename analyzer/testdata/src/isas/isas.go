@@ -0,0 +1,75 @@
+package isas
+
+import (
+	"errors"
+	"net"
+)
+
+var ErrMissing = errors.New("missing") // want ErrMissing:"sentinel"
+
+type PathError struct{ Path string }
+
+func (e *PathError) Error() string { return "bad path " + e.Path }
+
+type CodeError struct{ Code int }
+
+func (e CodeError) Error() string { return "code" }
+
+func is(err error) {
+	if errors.Is(err, nil) { // want `errors.Is with a nil target only reports whether err is nil; use err == nil`
+		println("nil")
+	}
+	if !errors.Is(err, nil) && err != ErrMissing { // want `errors.Is with a nil target only reports whether err is nil; use err == nil` `comparing errors with != fails on wrapped errors; use errors.Is`
+		println("not nil")
+	}
+	if errors.Is(nil, ErrMissing) { // want `errors.Is with a nil error never matches ErrMissing; the error to inspect comes first`
+		println("never")
+	}
+
+	// Matching a sentinel is fine.
+	if errors.Is(err, ErrMissing) {
+		println("missing")
+	}
+}
+
+func as(err error) {
+	var target *PathError
+	if errors.As(err, target) { // want `errors.As panics: target is a nil \*PathError; pass &target`
+		println(target.Path)
+	}
+
+	var path PathError
+	if errors.As(err, &path) { // want `errors.As panics: its target points to PathError, which does not implement error; declare the target as \*PathError and pass its address`
+		println(path.Path)
+	}
+
+	var code CodeError
+	if errors.As(err, code) { // want `errors.As panics unless its target is a non-nil pointer; pass a pointer to a variable of the error type, such as &target`
+		println(code.Code)
+	}
+
+	var other error
+	if errors.As(err, &other) { // want `errors.As with a \*error target matches any error and sets it to err; use a concrete error type, or an interface such as net.Error`
+		println(other)
+	}
+	if errors.As(err, &err) { // want `errors.As\(err, &err\) always matches and sets err to itself; declare a target of the error type to look for`
+		println(err)
+	}
+
+	// Pointers to error types and to interfaces are fine, and so are
+	// pointer variables that point somewhere.
+	if errors.As(err, &target) {
+		println(target.Path)
+	}
+	if errors.As(err, &code) {
+		println(code.Code)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		println(netErr.Timeout())
+	}
+	found := new(*PathError)
+	if errors.As(err, found) {
+		println((*found).Path)
+	}
+}
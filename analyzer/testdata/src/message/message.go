@@ -0,0 +1,53 @@
+package message
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+)
+
+const notFound = "not found"
+
+type quotaError struct{}
+
+func (*quotaError) Error() string { return "quota exceeded" }
+
+func match(err error, qe *quotaError) {
+	if strings.Contains(err.Error(), "permission denied") { // want `matching the message of err with strings.Contains breaks when the message changes; use errors.Is or errors.As`
+		println("denied")
+	}
+	if strings.HasPrefix(err.Error(), "open ") || strings.HasSuffix((err.Error()), notFound) { // want `matching the message of err with strings.HasPrefix` `matching the message of err with strings.HasSuffix`
+		println("open")
+	}
+	if strings.EqualFold("EOF", err.Error()) { // want `matching the message of err with strings.EqualFold`
+		println("eof")
+	}
+	if err.Error() == "file does not exist" { // want `comparing the message of err with == breaks when the message changes or the error is wrapped; use errors.Is or errors.As`
+		println("missing")
+	}
+	if notFound != err.Error() { // want `comparing the message of err with != breaks`
+		println("found")
+	}
+	if qe.Error() == "quota exceeded" { // want `comparing the message of qe with ==`
+		println("quota")
+	}
+
+	// Matching the errors themselves, and using messages for output, is
+	// fine.
+	if errors.Is(err, fs.ErrPermission) || strings.Contains("text", "t") {
+		println("denied")
+	}
+	msg := strings.ToUpper(err.Error())
+	if err.Error() == msg {
+		println(msg)
+	}
+}
+
+type codeError struct{ code string }
+
+func (e codeError) Error() string { return e.code }
+
+// Is matches errors by their message by design.
+func (e codeError) Is(target error) bool {
+	return target.Error() == "code "+e.code || strings.HasPrefix(target.Error(), "code")
+}
@@ -0,0 +1,40 @@
+package naming
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrClosed   = errors.New("closed")         // want ErrClosed:"sentinel"
+	NotFound    = errors.New("not found")      // want `sentinel error NotFound should be named ErrNotFound, so readers recognize it at the call site` NotFound:"sentinel"
+	TimeoutErr  = fmt.Errorf("timeout %d", 5)  // want `sentinel error TimeoutErr should be named ErrTimeout, so readers recognize it at the call site` TimeoutErr:"sentinel"
+	ParseError  = &SyntaxError{Line: 1}        // want `sentinel error ParseError should be named ErrParse, so readers recognize it at the call site` ParseError:"sentinel"
+	errInternal = errors.New("internal error") // want errInternal:"sentinel"
+)
+
+// Variables that do not hold errors are not sentinels.
+var NotFoundMessage = "not found"
+
+type SyntaxError struct{ Line int }
+
+func (e *SyntaxError) Error() string { return fmt.Sprintf("syntax error on line %d", e.Line) }
+
+type Missing struct{ Key string } // want `error type Missing should be named MissingError, so readers recognize it at the call site`
+
+func (e Missing) Error() string { return "missing " + e.Key }
+
+type QuotaErr struct{} // want `error type QuotaErr should be named QuotaError, so readers recognize it at the call site`
+
+func (*QuotaErr) Error() string { return "quota exceeded" }
+
+// Interfaces describe behavior, and unexported types are not part of the
+// API.
+type Temporary interface {
+	error
+	Temporary() bool
+}
+
+type internal struct{}
+
+func (internal) Error() string { return "internal" }
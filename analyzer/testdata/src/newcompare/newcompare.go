@@ -0,0 +1,62 @@
+package newcompare
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrNotFound = errors.New("not found") // want ErrNotFound:"sentinel"
+
+func find(name string) error { // want find:"wraps newcompare.ErrNotFound"
+	return fmt.Errorf("user %q: %w", name, ErrNotFound)
+}
+
+func compare(name string) bool {
+	err := find(name)
+
+	if err == errors.New("not found") { // want `comparing with a new error from errors.New never matches, not even an error with the same message; compare with a package-level sentinel`
+		return true
+	}
+	if (fmt.Errorf("user %q", name)) != err { // want `comparing with a new error from fmt.Errorf without %w never matches`
+		return true
+	}
+	if errors.Is(err, errors.New("not found")) { // want `errors.Is with a new error from errors.New never matches, not even an error with the same message; compare with a package-level sentinel`
+		return true
+	}
+
+	notFound := errors.New("not found")
+	if err == notFound { // want `notFound is a new error from errors.New, so comparing with == never matches an error created elsewhere, not even one with the same message; compare with a package-level sentinel`
+		return true
+	}
+	var reserved = fmt.Errorf("user %q is reserved", name)
+	if errors.Is(err, reserved) { // want `reserved is a new error from fmt.Errorf without %w, so errors.Is with it never matches an error created elsewhere`
+		println(reserved.Error())
+		return true
+	}
+	return errors.Is(err, ErrNotFound)
+}
+
+func notReported(name string) bool {
+	// Variables passed on can come back as the error compared with them.
+	want := errors.New("boom")
+	if err := call(func() error { return want }); errors.Is(err, want) {
+		return true
+	}
+	sent := errors.New("sent")
+	ch := make(chan error, 1)
+	ch <- sent
+	if errors.Is(<-ch, sent) {
+		return true
+	}
+	// Variables that may hold other errors, and wrapping fmt.Errorf
+	// calls, may match.
+	err := errors.New("empty")
+	if name != "" {
+		err = find(name)
+	}
+	return errors.Is(err, ErrNotFound) || errors.Is(err, fmt.Errorf("%w", ErrNotFound))
+}
+
+func call(f func() error) error {
+	return f()
+}
@@ -0,0 +1,154 @@
+package nilerror
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+func load(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func inverted(name string) string {
+	_, err := load(name)
+	if err == nil {
+		return err.Error() // want `err.Error\(\) panics: err is always nil here, after the nil check at line 15 \[ERRLINT023\]`
+	}
+	return ""
+}
+
+func elseBranch(name string) error {
+	_, err := load(name)
+	if err != nil {
+		return err
+	} else {
+		log.Printf("loading %s: %s", name, err) // want `err is always nil here, after the nil check at line 23, so %s formats it as %!s\(<nil>\)`
+	}
+	return nil
+}
+
+func earlyReturn(name string) error {
+	data, err := load(name)
+	if err != nil {
+		return fmt.Errorf("loading %s: %w", name, err)
+	}
+	log.Print(err.Error()) // want `err.Error\(\) panics: err is always nil here, after the nil check at line 33`
+	if len(data) == 0 {
+		return fmt.Errorf("loading %s: %w", name, err) // want `err is always nil here, after the nil check at line 33, so %w formats it as %!w\(<nil>\)`
+	}
+	return nil
+}
+
+func conditions(name string, strict bool) string {
+	_, err := load(name)
+	if strict && err == nil {
+		return fmt.Sprintf("%q", err) // want `err is always nil here, after the nil check at line 45, so %q formats it as %!q\(<nil>\)`
+	}
+	if !(err != nil) {
+		return err.Error() // want `err.Error\(\) panics: err is always nil here, after the nil check at line 48`
+	}
+	switch {
+	case err == nil:
+		return err.Error() // want `err.Error\(\) panics: err is always nil here, after the nil check at line 52`
+	}
+	return ""
+}
+
+func loop(names []string) {
+	for _, name := range names {
+		_, err := load(name)
+		if err != nil || name == "" {
+			continue
+		}
+		log.Printf("%s: %s", name, err.Error()) // want `err.Error\(\) panics: err is always nil here, after the nil check at line 61`
+	}
+}
+
+func beforeCheck(name string) ([]byte, error) {
+	data, err := load(name)
+	log.Print("loading: " + err.Error()) // want `err.Error\(\) is called before err is checked for nil at line 71, and panics if err is nil; check err first`
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func notReported(name string, names []string) error {
+	// The error is not nil in these branches.
+	_, err := load(name)
+	if err != nil {
+		log.Print(err.Error())
+	}
+	if err == nil {
+		return nil
+	}
+	log.Printf("loading: %s", err)
+	if err == nil || name == "" {
+		return nil
+	}
+
+	// Assigned again after the check.
+	_, err = load(name)
+	if err != nil {
+		return err
+	}
+	_, err = load(name + ".bak")
+	log.Printf("backup: %v", err)
+
+	// Assigned again in the init statement of an if or switch.
+	_, err = load(name)
+	if err != nil {
+		return err
+	}
+	if _, err = load(name + ".bak"); err != nil {
+		log.Print(err.Error())
+	}
+	_, err = load(name)
+	if err != nil {
+		return err
+	}
+	switch _, err = load(name + ".old"); {
+	case err != nil:
+		log.Print(err.Error())
+	}
+
+	// Conditions that leave a nil error possible but not certain.
+	_, err = load(name)
+	if err != nil && name == "" {
+		return err
+	}
+	if err == nil {
+		log.Print("loaded")
+	} else if name != "" {
+		log.Print(err.Error())
+	}
+
+	// A loop assigns the error for the next iteration.
+	var last error
+	for _, n := range names {
+		if last == nil {
+			_, last = load(n)
+			continue
+		}
+		log.Print(last.Error())
+	}
+
+	// A closure may assign the error at any time.
+	var async error
+	done := func() { async = os.ErrClosed }
+	if async == nil {
+		done()
+		log.Print(async.Error())
+	}
+
+	// Checked before the call of Error.
+	data, err := load(name)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		log.Printf("%d bytes", len(data))
+	}
+	return nil
+}
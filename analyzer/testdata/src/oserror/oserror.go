@@ -0,0 +1,18 @@
+package oserror
+
+import "os"
+
+func open(name string) string {
+	_, err := os.Open(name)
+	switch {
+	case os.IsNotExist(err): // want `os.IsNotExist does not unwrap errors; use errors.Is\(err, fs.ErrNotExist\)`
+		return "missing"
+	case os.IsExist(err): // want `os.IsExist does not unwrap errors; use errors.Is\(err, fs.ErrExist\)`
+		return "exists"
+	case os.IsPermission(err): // want `os.IsPermission does not unwrap errors; use errors.Is\(err, fs.ErrPermission\)`
+		return "denied"
+	case os.IsTimeout(err): // want `os.IsTimeout does not unwrap errors; use errors.As with an interface\{ Timeout\(\) bool \} target, or errors.Is\(err, os.ErrDeadlineExceeded\)`
+		return "timeout"
+	}
+	return "ok"
+}
@@ -0,0 +1,133 @@
+package overwrite
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrEmpty = errors.New("empty page") // want ErrEmpty:"sentinel wrapped by overwrite.joined"
+
+func fetch(page int) ([]string, error) { // want fetch:"returns overwrite.ErrEmpty"
+	if page == 0 {
+		return nil, ErrEmpty
+	}
+	return []string{fmt.Sprint(page)}, nil
+}
+
+func fetchAll(pages []int) ([]string, error) { // want fetchAll:"returns overwrite.ErrEmpty"
+	var all []string
+	var err error
+	for _, page := range pages {
+		var items []string
+		items, err = fetch(page) // want `err is assigned in each iteration of the loop but only checked after it, at line 25, so the errors of all but the last iteration are lost; check err in the loop`
+		all = append(all, items...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+func fetchN(n int) (all []string, err error) { // want fetchN:"returns overwrite.ErrEmpty"
+	for page := 0; page < n; page++ {
+		var items []string
+		items, err = fetch(page) // want `err is assigned in each iteration of the loop but only returned after it, at line 37, so the errors of all but the last iteration are lost; check err in the loop`
+		all = append(all, items...)
+	}
+	return all, err
+}
+
+func receive(pages <-chan int, n int) error { // want receive:"returns overwrite.ErrEmpty"
+	var err error
+	for range n {
+		select {
+		case page := <-pages:
+			_, err = fetch(page) // want `err is assigned in each iteration of the loop but only returned after it, at line 50, so the errors of all but the last iteration are lost; check err in the loop`
+			break
+		default:
+		}
+	}
+	return err
+}
+
+func checked(pages []int) error { // want checked:"returns overwrite.ErrEmpty"
+	var err error
+	for _, page := range pages {
+		_, err = fetch(page)
+		if err != nil {
+			continue
+		}
+	}
+	return err
+}
+
+func joined(pages []int) error { // want joined:"wraps overwrite.ErrEmpty"
+	var err error
+	for _, page := range pages {
+		_, e := fetch(page)
+		err = errors.Join(err, e)
+	}
+	return err
+}
+
+func retry(page int) error { // want retry:"returns overwrite.ErrEmpty"
+	var err error
+	for range 3 {
+		if _, err = fetch(page); err == nil {
+			break
+		}
+	}
+	return err
+}
+
+func untilFound(pages []int) ([]string, error) { // want untilFound:"returns overwrite.ErrEmpty"
+	var items []string
+	var err error
+	for _, page := range pages {
+		items, err = fetch(page)
+		if len(items) > 0 {
+			return items, nil
+		}
+	}
+	return nil, err
+}
+
+func sentinel(pages []int) error { // want sentinel:"returns overwrite.ErrEmpty"
+	var err error
+	for _, page := range pages {
+		if page == 0 {
+			err = ErrEmpty
+		}
+	}
+	return err
+}
+
+func created(pages []int) error {
+	var err error
+	for _, page := range pages {
+		if page < 0 {
+			err = fmt.Errorf("page %d is negative", page)
+		}
+	}
+	return err
+}
+
+func closure(pages []int) error { // want closure:"returns overwrite.ErrEmpty"
+	var err error
+	for _, page := range pages {
+		func() {
+			_, err = fetch(page)
+		}()
+	}
+	return err
+}
+
+func inLoop(pages []int) {
+	for _, page := range pages {
+		var err error
+		_, err = fetch(page)
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+}
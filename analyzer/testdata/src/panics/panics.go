@@ -0,0 +1,101 @@
+package panics
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+type config struct {
+	port int
+	name string
+}
+
+func parsePort(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		panic(fmt.Errorf("parsing port %q: %w", s, err)) // want `fmt.Errorf with err is passed to panic in a function that returns an error; return the error instead`
+	}
+	return n, nil
+}
+
+func load(name string) (cfg config, ok bool, err error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		panic(fmt.Errorf("loading %s: %w", name, err)) // want `fmt.Errorf with err is passed to panic in a function that returns an error; return the error instead`
+	}
+	return config{name: string(data)}, true, nil
+}
+
+func remove(names ...string) error {
+	for _, name := range names {
+		if err := os.Remove(name); err != nil {
+			panic(fmt.Errorf("removing %s: %w", name, err)) // want `fmt.Errorf with err is passed to panic in a function that returns an error; return the error instead`
+		}
+	}
+	return nil
+}
+
+func first[T any](items []T, open func(T) error) (T, *os.File, error) {
+	for _, item := range items {
+		if err := open(item); err != nil {
+			panic(fmt.Errorf("opening: %w", err)) // want `fmt.Errorf with err is passed to panic in a function that returns an error; return the error instead`
+		}
+	}
+	return *new(T), nil, nil
+}
+
+func handler() func(string) ([]byte, error) {
+	return func(name string) ([]byte, error) {
+		f, err := os.Open(name)
+		if err != nil {
+			panic(fmt.Errorf("opening %s: %w", name, err)) // want `fmt.Errorf with err is passed to panic in a function that returns an error; return the error instead`
+		}
+		defer f.Close()
+		return nil, nil
+	}
+}
+
+// Errors created from text alone describe bugs.
+func lookup(m map[string]int, key string) (int, error) {
+	if m == nil {
+		panic(fmt.Errorf("lookup: nil map for key %q", key))
+	}
+	if key == "" {
+		panic(errors.New("lookup: empty key"))
+	}
+	return m[key], nil
+}
+
+// Functions without an error result have no other way to fail.
+func mustParse(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		panic(fmt.Errorf("parsing %q: %w", s, err))
+	}
+	return n
+}
+
+// A deferred function cannot return the error of the function.
+func deferred(name string) error {
+	defer func() {
+		if err := os.Remove(name); err != nil {
+			panic(fmt.Errorf("cleaning up: %w", err))
+		}
+	}()
+	return nil
+}
+
+var ErrBadPort = errors.New("bad port") // want ErrBadPort:"sentinel"
+
+func tagged(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		panic(fmt.Errorf("%w: %w", ErrBadPort, err)) // want `fmt.Errorf with err is passed to panic in a function that returns an error; return the error instead`
+	}
+	if n < 0 {
+		panic(fmt.Errorf("%d: %w", n, ErrBadPort)) // want `fmt.Errorf with ErrBadPort is passed to panic in a function that returns an error; return the error instead`
+	}
+	return n, nil
+}
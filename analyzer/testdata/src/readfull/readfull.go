@@ -0,0 +1,66 @@
+// Package readfull is a fixture of the sentinels io.ReadFull returns,
+// io.EOF and io.ErrUnexpectedEOF, which are allowed in comparisons until
+// they are wrapped.
+package readfull
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+func direct(r io.Reader, buf []byte) {
+	// io.ReadFull returns both sentinels unwrapped.
+	if _, err := io.ReadFull(r, buf); err == io.EOF {
+		println("done")
+	}
+	_, err := io.ReadFull(r, buf)
+	if err == io.ErrUnexpectedEOF || err == io.EOF {
+		println("short")
+	}
+	switch _, err := io.ReadAtLeast(r, buf, 1); err {
+	case nil, io.EOF, io.ErrUnexpectedEOF:
+	}
+
+	// So do Read methods.
+	if _, err := bufio.NewReader(r).Read(buf); err != io.EOF {
+		println("more")
+	}
+}
+
+// header returns the errors of io.ReadFull unwrapped.
+func header(r io.Reader) ([]byte, error) { // want header:"^returns io.ErrShortBuffer, io.ErrUnexpectedEOF; returns other errors$"
+	buf := make([]byte, 2)
+	_, err := io.ReadFull(r, buf)
+	return buf, err
+}
+
+// body wraps them.
+func body(r io.Reader, n int) ([]byte, error) { // want body:"^wraps io.ErrShortBuffer, io.ErrUnexpectedEOF; returns other errors$"
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, fmt.Errorf("reading %d bytes: %w", n, err)
+	}
+	return buf, nil
+}
+
+func layers(r io.Reader) {
+	if _, err := header(r); err == io.EOF {
+		println("done")
+	}
+
+	_, err := body(r, 8)
+	if err == io.ErrUnexpectedEOF { // want `comparing with == never matches: err only holds io.ErrUnexpectedEOF wrapped by readfull.body; use errors.Is`
+		println("short")
+	}
+
+	// A conversion in place still counts as wrapping.
+	_, err = io.ReadFull(r, make([]byte, 8))
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	err = fmt.Errorf("reading body: %w", err)
+	if err == io.ErrUnexpectedEOF { // want `comparing with == never matches: err only holds io.ErrUnexpectedEOF wrapped; use errors.Is`
+		println("short")
+	}
+}
@@ -0,0 +1,43 @@
+package reassign
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+var (
+	ErrNotFound = errors.New("not found") // want ErrNotFound:"sentinel"
+	errClosed   = errors.New("closed")    // want errClosed:"sentinel"
+	Err         = errors.New("failed")    // want Err:"sentinel"
+
+	// None of these is named like a sentinel, or declared with a value.
+	lastErr error                  // want lastErr:"sentinel"
+	Errata  = errors.New("errata") // want Errata:"sentinel"
+	err     error                  // want err:"sentinel"
+	errLast error                  // want errLast:"sentinel"
+)
+
+func init() {
+	ErrNotFound = errors.New("introuvable") // want `sentinel error ErrNotFound is reassigned, so errors.Is no longer matches the errors that wrap its previous value; declare a new sentinel instead of assigning to it`
+}
+
+func closeAll() {
+	errClosed = nil                  // want `sentinel error errClosed is reassigned`
+	Err, lastErr = os.ErrClosed, Err // want `sentinel error Err is reassigned`
+}
+
+func imported() {
+	io.EOF = errors.New("end of file") // want `sentinel error io.EOF is reassigned`
+	(os.ErrNotExist) = nil             // want `sentinel error \(os.ErrNotExist\) is reassigned`
+}
+
+func unrelated() {
+	lastErr = ErrNotFound
+	Errata = errors.New("more errata")
+	err = io.EOF
+	errLast = io.ErrUnexpectedEOF
+	ErrNotFound := errors.New("shadowed")
+	ErrNotFound = errors.New("local")
+	_ = ErrNotFound
+}
@@ -0,0 +1,121 @@
+package shadow
+
+import (
+	"errors"
+	"os"
+	"strconv"
+)
+
+var ErrEmpty = errors.New("empty") // want ErrEmpty:"sentinel"
+
+func compress(data []byte) ([]byte, error) { // want compress:"returns shadow.ErrEmpty"
+	if len(data) == 0 {
+		return nil, ErrEmpty
+	}
+	return data, nil
+}
+
+func save(name string, data []byte, gzip bool) error { // want save:"returns io.ErrShortWrite, os.ErrInvalid; returns other errors"
+	var err error
+	if gzip {
+		data, err := compress(data) // want `err declared here shadows the err declared at line 19, which is returned at line 28 without this error; assign to the outer err with = instead`
+		if err == nil {
+			_ = os.WriteFile(name, data, 0o644)
+		}
+	} else {
+		err = os.WriteFile(name, data, 0o644)
+	}
+	return err
+}
+
+func retry(name string) error {
+	var err error
+	for range 3 {
+		err := os.Remove(name) // want `err declared here shadows the err declared at line 32, which is checked at line 39 without this error; assign to the outer err with = instead`
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func parse(s string) (n int, err error) {
+	if s != "" {
+		n, err := strconv.Atoi(s) // want `err declared here shadows the err declared at line 45, which is returned at line 50 without this error; assign to the outer err with = instead`
+		_, _ = n, err == nil
+	}
+	return n, err
+}
+
+func matched(name string) bool {
+	err := os.ErrInvalid
+	{
+		_, err := os.Stat(name) // want `err declared here shadows the err declared at line 54, which is checked at line 59 without this error; assign to the outer err with = instead`
+		_ = err != nil
+	}
+	return errors.Is(err, os.ErrNotExist)
+}
+
+// Inner errors that are returned, passed on or checked by an if statement
+// that leaves the block are handled where they are.
+func handled(names []string) error { // want handled:"wraps shadow.ErrEmpty; returns other errors"
+	var err error
+	for _, name := range names {
+		if err := os.Remove(name); err != nil {
+			return err
+		}
+		if err := os.Remove(name); err != nil {
+			continue
+		}
+		if err := os.Remove(name); err == nil {
+			continue
+		} else {
+			panic(err)
+		}
+		if _, err := os.Stat(name); errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if f, err := os.Open(name); err == nil {
+			f.Close()
+		} else {
+			return errors.Join(ErrEmpty, err)
+		}
+	}
+	return err
+}
+
+// The outer variable is assigned again before it is returned.
+func reassigned(name string) error {
+	var err error
+	if name != "" {
+		_, err := os.Stat(name)
+		_ = err == nil
+	}
+	err = os.Remove(name)
+	return err
+}
+
+// An err scoped to an if statement probes for an error on purpose.
+func probe(name string) error { // want probe:"returns shadow.ErrEmpty; returns other errors"
+	_, err := os.Stat(name)
+	if err != nil {
+		if _, err := os.Lstat(name); err == nil {
+			return ErrEmpty
+		}
+	}
+	return err
+}
+
+// Variables of the same function only: a closure's err that hides a
+// package-level one, or a parameter of another function, is not reported.
+var err error // want err:"sentinel"
+
+func global() {
+	if _, err := os.Stat("x"); err == nil {
+		return
+	}
+	_ = err == nil
+}
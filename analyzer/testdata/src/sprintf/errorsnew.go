@@ -0,0 +1,15 @@
+package sprintf
+
+import (
+	"errors"
+	"fmt"
+)
+
+func closing(name string) error {
+	return errors.New(fmt.Sprintf("closing %s", name)) // want `formats the message in two steps`
+}
+
+func closed() error {
+	// The fix of the errorsnew check calls errors.New, so the import stays.
+	return fmt.Errorf("closed") // want `fmt.Errorf without verbs or arguments; use errors.New`
+}
@@ -0,0 +1,67 @@
+package sprintf
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+func formatted(id int) error {
+	return errors.New(fmt.Sprintf("user %d not found", id)) // want `errors.New\(fmt.Sprintf\(...\)\) formats the message in two steps; use fmt.Errorf`
+}
+
+func wrapped(id int, err error) error {
+	return errors.New(fmt.Sprintf("loading user %d: %v", id, err)) // want `errors.New\(fmt.Sprintf\(...\)\) keeps the text of err but drops it from the chain; use fmt.Errorf and wrap err with %w`
+}
+
+func message(name string, err error) error {
+	return errors.New(fmt.Sprintf("opening %s: %s", name, err.Error())) // want `keeps the text of err but drops it from the chain`
+}
+
+func both(read, closing error) error {
+	return errors.New(fmt.Sprintf("reading: %v, closing: %v", read, closing)) // want `keeps the text of read and closing but drops it from the chain; use fmt.Errorf and wrap read and closing with %w`
+}
+
+func quoted(name string, err error) error {
+	// %q quotes the message, which %w would not, so the fix keeps it.
+	return errors.New(fmt.Sprintf("opening %s: %q", name, err)) // want `keeps the text of err`
+}
+
+func spread(format string, args ...any) error {
+	return errors.New(fmt.Sprintf(format, args...)) // want `formats the message in two steps`
+}
+
+func argument(id int, err error) error {
+	return fmt.Errorf("loading: %s", fmt.Sprintf("user %d: %v", id, err)) // want `fmt.Sprintf formats the text of err into an argument of fmt.Errorf, which drops it from the chain; wrap err with %w`
+}
+
+func format(s string, err error) error {
+	return fmt.Errorf(fmt.Sprintf("parsing %q: %v", s, err)) // want `fmt.Sprintf formats the text of err into the format of fmt.Errorf, which drops it from the chain; wrap err with %w`
+}
+
+func around(name string, line int, err error) error {
+	return fmt.Errorf("%s:%d: %v", name, line, fmt.Sprintf("retry %s", err.Error())) // want `fmt.Sprintf formats the text of err into an argument of fmt.Errorf`
+}
+
+func padded(id int, err error) error {
+	// The width applies to the whole text, which the fix cannot keep.
+	return fmt.Errorf("loading: %40s", fmt.Sprintf("user %d: %v", id, err)) // want `fmt.Sprintf formats the text of err into an argument of fmt.Errorf`
+}
+
+func twice(read, closing error) error {
+	return fmt.Errorf("%s, %s", fmt.Sprintf("reading: %v", read), fmt.Sprintf("closing: %v", closing)) // want `formats the text of read into an argument` `formats the text of closing into an argument`
+}
+
+func number(s string) error {
+	_, err := strconv.Atoi(s)
+	return fmt.Errorf("parsing %s: %w", fmt.Sprintf("%q", s), err)
+}
+
+func text(id int) string {
+	return fmt.Sprintf("user %d", id)
+}
+
+func ignored(id int) error {
+	//errlint:ignore sprintf matches the message of the upstream client
+	return errors.New(fmt.Sprintf("user %d not found", id))
+}
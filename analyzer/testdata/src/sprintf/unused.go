@@ -0,0 +1,14 @@
+package sprintf
+
+import (
+	"errors"
+	"fmt"
+)
+
+func reading(name string, err error) error {
+	return errors.New(fmt.Sprintf("reading %s: %v", name, err)) // want `keeps the text of err`
+}
+
+func writing(name string) error {
+	return errors.New(fmt.Sprintf("writing %s", name)) // want `formats the message in two steps`
+}
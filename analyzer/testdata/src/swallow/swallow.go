@@ -0,0 +1,118 @@
+package swallow
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strconv"
+)
+
+func save(name string, data []byte) error {
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		log.Printf("saving %s: %v", name, err)
+		return nil // want `error err is logged with log.Printf and then dropped by returning nil; callers see success, so return it instead`
+	}
+	return nil
+}
+
+func parse(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		log.Println("parsing:", err)
+		return 0, nil // want `error err is logged with log.Println and then dropped by returning nil; callers see success, so return it instead`
+	}
+	return n, nil
+}
+
+func remove(name string) error {
+	err := os.Remove(name)
+	if nil != err {
+		slog.Error("removing file", "name", name, "err", err)
+		return nil // want `error err is logged with slog.Error and then dropped by returning nil; callers see success, so return it instead`
+	}
+	return nil
+}
+
+func open(logger *log.Logger, name string) (*os.File, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		logger.Printf("opening %s: %v", name, err.Error())
+		return nil, nil // want `error err is logged with log.Logger.Printf and then dropped by returning nil; callers see success, so return it instead`
+	}
+	return f, nil
+}
+
+func audit(args ...any) {}
+
+func mkdir(name string) error {
+	if err := os.Mkdir(name, 0o755); err != nil {
+		audit("mkdir", name, err)
+		return nil // want `error err is logged with swallow.audit and then dropped by returning nil; callers see success, so return it instead`
+	}
+	return nil
+}
+
+func handler() func(string) error {
+	return func(name string) error {
+		if err := os.Remove(name); err != nil {
+			slog.Default().Warn("cleanup", "err", err)
+			return nil // want `error err is logged with slog.Logger.Warn and then dropped by returning nil; callers see success, so return it instead`
+		}
+		return nil
+	}
+}
+
+// The error is returned after it is logged.
+func logged(name string) error {
+	if err := os.Remove(name); err != nil {
+		log.Printf("removing %s: %v", name, err)
+		return fmt.Errorf("removing %s: %w", name, err)
+	}
+	return nil
+}
+
+// Logging and carrying on with the next item is a decision, not an accident.
+func removeAll(names []string) {
+	for _, name := range names {
+		if err := os.Remove(name); err != nil {
+			log.Printf("removing %s: %v", name, err)
+			continue
+		}
+	}
+}
+
+// A bare return returns whatever the named result holds.
+func named(name string) (err error) {
+	if err = os.Remove(name); err != nil {
+		log.Printf("removing %s: %v", name, err)
+		return
+	}
+	return
+}
+
+// Functions without an error result have nothing to return it in.
+func background(name string) {
+	if err := os.Remove(name); err != nil {
+		log.Printf("removing %s: %v", name, err)
+		return
+	}
+}
+
+// Other errors are logged.
+func fallback(name string) error {
+	if err := os.Remove(name); err != nil {
+		log.Printf("removing %s", name)
+		return nil
+	}
+	return nil
+}
+
+// Printing to standard output is not logging.
+func printed(name string) error {
+	if err := os.Remove(name); err != nil {
+		fmt.Println(err)
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package switches
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidInput = errors.New("invalid input")       // want ErrInvalidInput:"sentinel"
+	ErrTimeout      = errors.New("operation timed out") // want ErrTimeout:"sentinel"
+)
+
+type NotFoundError struct {
+	Item string
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Item + " not found"
+}
+
+type URLError struct {
+	URL string
+}
+
+func (e URLError) Error() string {
+	return "bad URL " + e.URL
+}
+
+type temporary interface {
+	Temporary() bool
+}
+
+func fetch() error { // want fetch:"returns switches.ErrTimeout"
+	return ErrTimeout
+}
+
+func valueSwitch() {
+	err := fetch()
+
+	switch err { // want `switch on error value fails on wrapped errors; use errors.Is`
+	case ErrInvalidInput:
+		fmt.Println("invalid input")
+	case ErrTimeout:
+		fmt.Println("timeout")
+	default:
+		fmt.Println("unknown")
+	}
+
+	// Cases that break out of the switch get no fix.
+	switch err { // want `switch on error value fails on wrapped errors; use errors.Is`
+	case ErrTimeout:
+		if err != nil {
+			break
+		}
+		fmt.Println("timeout")
+	}
+
+	// Switches on nil only are fine.
+	switch err {
+	case nil:
+		fmt.Println("ok")
+	}
+}
+
+func typeSwitch() {
+	err := fetch()
+
+	switch e := err.(type) { // want `type switch on error fails on wrapped errors; use errors.As`
+	case *NotFoundError:
+		fmt.Println(e.Item)
+	default:
+		fmt.Println("other")
+	}
+
+	// The nil case, cases with several types and the default case redeclare
+	// the variable, which has the type of err there.
+	switch e := err.(type) { // want `type switch on error fails on wrapped errors; use errors.As`
+	case nil:
+		fmt.Println("ok", e == nil)
+	case URLError:
+		fmt.Println(e.URL)
+	case temporary:
+		fmt.Println(e.Temporary())
+	case *NotFoundError, *URLError:
+		fmt.Println("lookup", e)
+	default:
+		fmt.Println(e)
+	}
+
+	// A variable named err would hide err from the conditions that follow, so
+	// the single-type cases are named after their type.
+	switch err := err.(type) { // want `type switch on error fails on wrapped errors; use errors.As`
+	case *NotFoundError:
+		fmt.Println(err.Item)
+	case URLError:
+		fmt.Println(err.URL)
+	default:
+		fmt.Println(err)
+	}
+
+	// Cases that do not use the variable only test for the type.
+	switch err.(type) { // want `type switch on error fails on wrapped errors; use errors.As`
+	case *NotFoundError:
+		fmt.Println("not found")
+	case URLError, *URLError:
+		fmt.Println("bad URL")
+	}
+
+	// Cases that break out of the switch get no fix, and neither do cases of
+	// a type that every error implements.
+	switch e := err.(type) { // want `type switch on error fails on wrapped errors; use errors.As`
+	case *NotFoundError:
+		if e.Item == "" {
+			break
+		}
+		fmt.Println(e.Item)
+	}
+	switch e := err.(type) { // want `type switch on error fails on wrapped errors; use errors.As`
+	case *NotFoundError:
+		fmt.Println(e.Item)
+	case error:
+		fmt.Println(e)
+	}
+
+	// Visiting joined errors is fine.
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		fmt.Println(len(u.Unwrap()))
+	}
+}
@@ -0,0 +1,95 @@
+package wrapcheck
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+func open(name string) (*os.File, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err // want `error from os.Open is returned without context; wrap it with fmt.Errorf and %w`
+	}
+	return f, nil
+}
+
+func remove(name string) error {
+	return os.Remove(name) // want `error from os.Remove is returned without context; wrap it with fmt.Errorf and %w`
+}
+
+func closeFile(f *os.File) error { // want closeFile:"returns os.ErrInvalid; returns other errors"
+	if err := f.Close(); err != nil {
+		return err // want `error from os.File.Close is returned without context; wrap it with fmt.Errorf and %w`
+	}
+	return nil
+}
+
+func mkdir(name string) error {
+	var err = os.Mkdir(name, 0o755)
+	return err // want `error from os.Mkdir is returned without context; wrap it with fmt.Errorf and %w`
+}
+
+func cleanup(names []string) func() error {
+	return func() error {
+		for _, name := range names {
+			if err := os.Remove(name); err != nil {
+				return err // want `error from os.Remove is returned without context; wrap it with fmt.Errorf and %w`
+			}
+		}
+		return nil
+	}
+}
+
+// Wrapped errors and errors of this package are fine.
+func load(name string) ([]byte, error) { // want load:"returns os.ErrInvalid; returns other errors"
+	f, err := open(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	return data, closeFile(f)
+}
+
+// Calls that take the error wrap it.
+func chdir(dir string) error {
+	if err := os.Chdir(dir); err != nil {
+		return os.NewSyscallError("chdir", err)
+	}
+	return nil
+}
+
+// strconv and io.ReadAll are passed through by the configuration.
+func parse(r io.Reader) (int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(data))
+}
+
+// err may have been wrapped on the way.
+func rename(from, to string, verbose bool) error {
+	err := os.Rename(from, to)
+	if err != nil && verbose {
+		err = fmt.Errorf("renaming %s: %w", from, err)
+	}
+	return err
+}
+
+// A Read method passes the io.EOF of the reader it wraps through, which
+// callers compare with ==.
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
@@ -0,0 +1,21 @@
+package prefix
+
+import (
+	"fmt"
+	"os"
+)
+
+func load(name string) ([]byte, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("config %s: %w", name, err) // want `the context "config %s" of the wrap message does not match the -wrap-prefix pattern \^\[a-z\]\+ing\\b`
+	}
+	return data, nil
+}
+
+func save(name string, write func(string) error) error {
+	if err := write(name); err != nil {
+		return fmt.Errorf("writing config %s: %w", name, err)
+	}
+	return nil
+}
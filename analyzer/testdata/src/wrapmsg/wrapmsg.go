@@ -0,0 +1,37 @@
+package wrapmsg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrNotFound = errors.New("not found") // want ErrNotFound:"sentinel"
+
+func read(name string) error {
+	_, err := os.ReadFile(name)
+	return err
+}
+
+func wrap(name string) []error {
+	err := read(name)
+	return []error{
+		fmt.Errorf("%w", err),                    // want `wrap message "%w" adds no context; say what the code was doing, as in "reading config: %w"`
+		fmt.Errorf("%w: reading %s", err, name),  // want `wrap message "%w: reading %s" does not end in ": %w"; put the context first`
+		fmt.Errorf("reading %s - %w", name, err), // want `wrap message "reading %s - %w" does not end in ": %w"`
+		fmt.Errorf(": %w", err),                  // want `wrap message ": %w" does not end in ": %w"`
+		fmt.Errorf("Reading %s: %w", name, err),  // want `wrap message "Reading %s: %w" starts with a capital letter, but it ends up in the middle of the messages of its callers; start it in lower case`
+		fmt.Errorf("reading %s.: %w", name, err), // want `wrap message "reading %s.: %w" ends its context with "."; end it with a word`
+		fmt.Errorf("reading failed! : %w", err),  // want `wrap message "reading failed! : %w" ends its context with " "`
+		fmt.Errorf("not found: %w", ErrNotFound), // want `wrap message "not found: %w" repeats the message of ErrNotFound, so the error reads "not found: not found"; say what the code was doing instead`
+
+		// Wrap messages that follow the convention.
+		fmt.Errorf("reading %s: %w", name, err),
+		fmt.Errorf("HTTP request: %w", err),
+		fmt.Errorf("loading user %q: %w", name, ErrNotFound),
+
+		// Errors joined with several %w verbs, and messages without %w.
+		fmt.Errorf("%w and %w", err, ErrNotFound),
+		fmt.Errorf("Reading %s failed: %v", name, err),
+	}
+}
@@ -0,0 +1,123 @@
+package wrongerr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+func read(name string) ([]byte, error) { return os.ReadFile(name) }
+
+func validate(data []byte) error { return nil }
+
+func load(name string) ([]byte, error) {
+	data, err := read(name)
+	if err != nil {
+		return nil, err
+	}
+	if err2 := validate(data); err2 != nil {
+		return nil, err // want `the branch for err2 != nil returns err, which is nil here after the check at line 16, so the error is dropped and the caller sees success; use err2`
+	}
+	return data, nil
+}
+
+func loadWrapped(name string) ([]byte, error) {
+	data, err := read(name)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	verr := validate(data)
+	if verr != nil && len(data) > 0 {
+		return nil, fmt.Errorf("validating %s: %w", name, err) // want `err is always nil here, after the nil check at line 27, so %w formats it as %!w\(<nil>\)` `the branch for verr != nil wraps err, which is nil here after the check at line 27, so the error is dropped and the caller sees success; use verr`
+	}
+	return data, nil
+}
+
+func copyFile(dst io.WriteCloser, name string) (err error) {
+	data, err := read(name)
+	if err != nil {
+		return err
+	}
+	if _, werr := dst.Write(data); werr != nil {
+		return // want `the branch for werr != nil returns the named result err with a bare return, which is nil here after the check at line 39, so the error is dropped and the caller sees success; use werr`
+	}
+	if cerr := dst.Close(); cerr != nil {
+		if len(data) == 0 {
+			return fmt.Errorf("closing: %w", err) // want `err is always nil here, after the nil check at line 39, so %w formats it as %!w\(<nil>\)` `the branch for cerr != nil wraps err, which is nil here after the check at line 39, so the error is dropped and the caller sees success; use cerr`
+		}
+	}
+	return nil
+}
+
+func save(create func(string) (io.WriteCloser, error), name string, data []byte) (n int, err error) {
+	f, err := create(name)
+	if err != nil {
+		return 0, err
+	}
+	n, werr := f.Write(data)
+	if werr != nil {
+		return // want `the branch for werr != nil returns the named result err with a bare return, which is nil here after the check at line 55, so the error is dropped and the caller sees success; use werr`
+	}
+	return n, f.Close()
+}
+
+func cleanup() error { return nil }
+
+// Branches that use the checked error, or set or check the returned one,
+// and branches that return an error that may be set, are fine.
+func process(in error) error {
+	if cerr := cleanup(); cerr != nil {
+		return in
+	}
+	return in
+}
+
+func logged(name string) error {
+	data, err := read(name)
+	if err != nil {
+		return err
+	}
+	if err2 := validate(data); err2 != nil {
+		fmt.Println("invalid:", err2)
+		return err
+	}
+	return nil
+}
+
+func joined(in error) error {
+	if cerr := cleanup(); cerr != nil {
+		return errors.Join(in, cerr)
+	}
+	return in
+}
+
+func assigned(dst io.WriteCloser) (err error) {
+	if cerr := dst.Close(); cerr != nil && err == nil {
+		err = cerr
+		return
+	}
+	return nil
+}
+
+func checked(name string) error {
+	data, err := read(name)
+	if verr := validate(data); verr != nil {
+		if err != nil {
+			return err
+		}
+		return verr
+	}
+	return nil
+}
+
+func closure(name string) func() error {
+	data, err := read(name)
+	if err != nil {
+		return func() error { return err }
+	}
+	if verr := validate(data); verr != nil {
+		return func() error { return err }
+	}
+	return nil
+}
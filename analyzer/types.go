@@ -0,0 +1,106 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+var (
+	errorType  = types.Universe.Lookup("error").Type()
+	errorIface = errorType.Underlying().(*types.Interface)
+)
+
+// isError reports whether expr has a type that implements error.
+func isError(pass *analysis.Pass, expr ast.Expr) bool {
+	t := pass.TypesInfo.TypeOf(expr)
+	return t != nil && types.Implements(t, errorIface)
+}
+
+// isErrorInterface reports whether expr has an interface type that
+// implements error, such as error itself or net.Error.
+func isErrorInterface(pass *analysis.Pass, expr ast.Expr) bool {
+	t := pass.TypesInfo.TypeOf(expr)
+	return t != nil && types.IsInterface(t) && types.Implements(t, errorIface)
+}
+
+// isUnwrapInterface reports whether t is an interface whose only method is
+// Unwrap() error or Unwrap() []error. Asserting an error to such an interface
+// inspects the wrap structure of the error itself, which errors.As cannot.
+func isUnwrapInterface(t types.Type) bool {
+	iface, ok := t.Underlying().(*types.Interface)
+	if !ok || iface.NumMethods() != 1 || iface.Method(0).Name() != "Unwrap" {
+		return false
+	}
+	sig := iface.Method(0).Signature()
+	if sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return false
+	}
+	res := sig.Results().At(0).Type()
+	if slice, ok := res.(*types.Slice); ok {
+		res = slice.Elem()
+	}
+	return types.Identical(res, errorType)
+}
+
+// isNil reports whether expr is the untyped nil value.
+func isNil(pass *analysis.Pass, expr ast.Expr) bool {
+	tv, ok := pass.TypesInfo.Types[expr]
+	return ok && tv.IsNil()
+}
+
+// isBuiltin reports whether call calls the built-in function name.
+func isBuiltin(pass *analysis.Pass, call *ast.CallExpr, name string) bool {
+	id, ok := ast.Unparen(call.Fun).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	b, ok := pass.TypesInfo.Uses[id].(*types.Builtin)
+	return ok && b.Name() == name
+}
+
+// sentinelName returns the qualified name ("pkg/path.Name") of the
+// package-level variable expr refers to.
+func sentinelName(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	var id *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		id = e.Sel
+	default:
+		return "", false
+	}
+
+	v, ok := pass.TypesInfo.Uses[id].(*types.Var)
+	if !ok || v.Pkg() == nil || v.Parent() != v.Pkg().Scope() {
+		return "", false
+	}
+	return v.Pkg().Path() + "." + v.Name(), true
+}
+
+// inIsMethod reports whether pos is inside an Is(error) bool method. Such
+// methods implement custom matching for errors.Is and compare their target
+// with == or type assertions by design.
+func inIsMethod(pass *analysis.Pass, pos token.Pos) bool {
+	file := fileOf(pass, pos)
+	if file == nil {
+		return false
+	}
+	for _, decl := range file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Recv == nil || fd.Name.Name != "Is" || pos < fd.Pos() || pos >= fd.End() {
+			continue
+		}
+		fn, ok := pass.TypesInfo.Defs[fd.Name].(*types.Func)
+		if !ok {
+			return false
+		}
+		sig := fn.Signature()
+		return sig.Params().Len() == 1 && types.Identical(sig.Params().At(0).Type(), errorType) &&
+			sig.Results().Len() == 1 && types.Identical(sig.Results().At(0).Type(), types.Typ[types.Bool])
+	}
+	return false
+}
@@ -0,0 +1,268 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// typeSwitchFix rewrites a type switch over an error into an if/else chain
+// of errors.As calls. A case with a single type declares its variable in
+// the if statement, so that
+//
+//	switch e := err.(type) {
+//	case *fs.PathError:
+//		return e.Path
+//	default:
+//		return e.Error()
+//	}
+//
+// becomes
+//
+//	if e := (*fs.PathError)(nil); errors.As(err, &e) {
+//		return e.Path
+//	} else {
+//		e := err
+//		return e.Error()
+//	}
+//
+// The nil case, cases with several types and the default case, whose
+// variable has the type of err, redeclare it when they use it: it would
+// otherwise refer to the variable of an earlier condition. If the variable
+// has the name of an identifier of err, as in switch err := err.(type), it
+// would hide it from the conditions that follow, so the single-type cases
+// name it after their type instead.
+//
+// Switches with an init statement, an operand with possible side effects,
+// case bodies that break, or a case type that every error implements, such
+// as error itself, are left alone.
+func typeSwitchFix(pass *analysis.Pass, stmt *ast.TypeSwitchStmt) []analysis.SuggestedFix {
+	x := typeSwitchOperand(stmt)
+	if stmt.Init != nil || x == nil || !isPure(x) {
+		return nil
+	}
+	xt := pass.TypesInfo.TypeOf(x)
+	file := fileOf(pass, stmt.Pos())
+	if xt == nil || file == nil {
+		return nil
+	}
+	var binding string
+	if assign, ok := stmt.Assign.(*ast.AssignStmt); ok {
+		binding = assign.Lhs[0].(*ast.Ident).Name
+	}
+	rename := binding != "" && mentions(x, binding)
+
+	name, edits := importErrors(pass, file)
+	operand := render(pass, x)
+	indent := indentation(pass, stmt.Pos())
+
+	var (
+		buf         strings.Builder
+		defaultBody string
+	)
+	for i, c := range stmt.Body.List {
+		clause := c.(*ast.CaseClause)
+		if branchesOut(clause.Body) {
+			return nil
+		}
+		for _, expr := range clause.List {
+			if t := pass.TypesInfo.TypeOf(expr); !isNil(pass, expr) && (t == nil || types.IsInterface(t) && types.AssignableTo(xt, t)) {
+				return nil
+			}
+		}
+		end := stmt.Body.Rbrace
+		if i+1 < len(stmt.Body.List) {
+			end = stmt.Body.List[i+1].Pos()
+		}
+		v, _ := pass.TypesInfo.Implicits[clause].(*types.Var)
+		uses := usesOf(pass, clause.Body, v)
+
+		var cond string
+		target := binding
+		if len(clause.List) == 1 && !isNil(pass, clause.List[0]) && len(uses) > 0 {
+			expr := clause.List[0]
+			if rename {
+				target = freshName(stmt, pass.TypesInfo.TypeOf(expr))
+			}
+			cond = fmt.Sprintf("%s := %s; %s.As(%s, &%s)", target, zeroValue(pass, expr), name, operand, target)
+		} else {
+			conds := make([]string, 0, len(clause.List))
+			for _, expr := range clause.List {
+				if isNil(pass, expr) {
+					conds = append(conds, operand+" == nil")
+					continue
+				}
+				conds = append(conds, fmt.Sprintf("%s.As(%s, new(%s))", name, operand, render(pass, expr)))
+			}
+			cond = strings.Join(conds, " || ")
+		}
+
+		body, ok := renamed(pass, clause.Colon+1, end, uses, target)
+		if !ok {
+			return nil
+		}
+		body = strings.TrimRight(body, " \t\n")
+		if len(uses) > 0 && (len(clause.List) != 1 || isNil(pass, clause.List[0])) {
+			if !rename || operand != binding || assignsTo(pass, clause.Body, v) {
+				body = fmt.Sprintf("\n%s\t%s := %s%s", indent, binding, operand, body)
+			}
+		}
+
+		if clause.List == nil {
+			defaultBody = body
+			continue
+		}
+		if buf.Len() > 0 {
+			buf.WriteString(" else ")
+		}
+		fmt.Fprintf(&buf, "if %s {%s\n%s}", cond, body, indent)
+	}
+	if buf.Len() == 0 {
+		return nil
+	}
+	if defaultBody != "" {
+		fmt.Fprintf(&buf, " else {%s\n%s}", defaultBody, indent)
+	}
+
+	return []analysis.SuggestedFix{{
+		Message: "Rewrite as errors.As chain",
+		TextEdits: append(edits, analysis.TextEdit{
+			Pos:     stmt.Pos(),
+			End:     stmt.End(),
+			NewText: []byte(buf.String()),
+		}),
+	}}
+}
+
+// mentions reports whether expr refers to an identifier called name, not
+// counting the selectors of fields and methods.
+func mentions(expr ast.Expr, name string) bool {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		return e.Name == name
+	case *ast.SelectorExpr:
+		return mentions(e.X, name)
+	}
+	return false
+}
+
+// usesOf returns the identifiers in stmts that refer to v.
+func usesOf(pass *analysis.Pass, stmts []ast.Stmt, v *types.Var) []*ast.Ident {
+	if v == nil {
+		return nil
+	}
+	var uses []*ast.Ident
+	for _, stmt := range stmts {
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok && pass.TypesInfo.Uses[id] == v {
+				uses = append(uses, id)
+			}
+			return true
+		})
+	}
+	return uses
+}
+
+// assignsTo reports whether stmts assign to v or take its address.
+func assignsTo(pass *analysis.Pass, stmts []ast.Stmt, v *types.Var) bool {
+	is := func(expr ast.Expr) bool {
+		id, ok := ast.Unparen(expr).(*ast.Ident)
+		return ok && pass.TypesInfo.Uses[id] == v
+	}
+	found := false
+	for _, stmt := range stmts {
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				for _, lhs := range n.Lhs {
+					found = found || is(lhs)
+				}
+			case *ast.IncDecStmt:
+				found = found || is(n.X)
+			case *ast.UnaryExpr:
+				found = found || n.Op == token.AND && is(n.X)
+			}
+			return !found
+		})
+	}
+	return found
+}
+
+// renamed returns the source text between start and end with the
+// identifiers in uses, which lie in between, replaced by name.
+func renamed(pass *analysis.Pass, start, end token.Pos, uses []*ast.Ident, name string) (string, bool) {
+	text, ok := source(pass, start, end)
+	if !ok {
+		return "", false
+	}
+	var b strings.Builder
+	last := 0
+	for _, id := range uses {
+		off := int(id.Pos() - start)
+		b.WriteString(text[last:off])
+		b.WriteString(name)
+		last = off + len(id.Name)
+	}
+	b.WriteString(text[last:])
+	return b.String(), true
+}
+
+// zeroValue returns an expression for the zero value of the type expr
+// denotes, such as (*T)(nil) or (T{}), which is parenthesized to be valid
+// in the header of an if statement.
+func zeroValue(pass *analysis.Pass, expr ast.Expr) string {
+	typ := render(pass, expr)
+	switch pass.TypesInfo.TypeOf(expr).Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Map, *types.Slice, *types.Chan, *types.Signature:
+		switch expr.(type) {
+		case *ast.StarExpr, *ast.FuncType, *ast.ChanType:
+			typ = "(" + typ + ")"
+		}
+		return typ + "(nil)"
+	case *types.Struct, *types.Array:
+		return "(" + typ + "{})"
+	}
+	return "*new(" + typ + ")"
+}
+
+// freshName returns a name for a variable of type t, such as pathErr for
+// *fs.PathError, that no identifier in stmt uses.
+func freshName(stmt ast.Stmt, t types.Type) string {
+	base := "target"
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		obj := named.Obj()
+		if n := strings.TrimSuffix(obj.Name(), "Error"); n != "" {
+			base = n + "Err"
+		} else if obj.Pkg() != nil {
+			base = obj.Pkg().Name() + "Err"
+		}
+		// PathErr becomes pathErr, and URLErr urlErr.
+		upper := strings.IndexFunc(base, unicode.IsLower) - 1
+		if upper < 1 {
+			upper = 1
+		}
+		base = strings.ToLower(base[:upper]) + base[upper:]
+	}
+
+	taken := make(map[string]bool)
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			taken[id.Name] = true
+		}
+		return true
+	})
+	name := base
+	for i := 2; taken[name] || token.IsKeyword(name) || types.Universe.Lookup(name) != nil; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	return name
+}
@@ -0,0 +1,202 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// checkWrapcheck reports errors from functions of other packages that are
+// returned as they are. The caller then only gets the message of the
+// failed call, such as "EOF", without saying what was being done.
+func (l *linter) checkWrapcheck(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.WithStack([]ast.Node{(*ast.ReturnStmt)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		ret := n.(*ast.ReturnStmt)
+		fn, results := enclosingSignature(pass, stack)
+		if results == nil {
+			return true
+		}
+		var exprs []ast.Expr
+		switch {
+		case len(ret.Results) == 1 && results.Len() > 1:
+			// return f() passes all the results of f through.
+			exprs = ret.Results
+		case len(ret.Results) == results.Len():
+			for i, expr := range ret.Results {
+				if t := results.At(i).Type(); types.IsInterface(t) && types.Implements(t, errorIface) {
+					exprs = append(exprs, expr)
+				}
+			}
+		}
+		for _, expr := range exprs {
+			call := l.returnedCall(pass, expr, ret.Pos(), stack)
+			if call == nil {
+				continue
+			}
+			callee := l.passedThrough(pass, call, fn)
+			if callee == nil {
+				continue
+			}
+			pass.Reportf(expr.Pos(), "error from %s is returned without context; wrap it with fmt.Errorf and %%w", funcName(callee))
+		}
+		return true
+	})
+}
+
+// enclosingSignature returns the function declaration or literal at the
+// top of stack, or nil for a literal, and its results.
+func enclosingSignature(pass *analysis.Pass, stack []ast.Node) (*types.Func, *types.Tuple) {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.FuncLit:
+			sig, ok := pass.TypesInfo.TypeOf(n).(*types.Signature)
+			if !ok {
+				return nil, nil
+			}
+			return nil, sig.Results()
+		case *ast.FuncDecl:
+			fn, ok := pass.TypesInfo.Defs[n.Name].(*types.Func)
+			if !ok {
+				return nil, nil
+			}
+			return fn, fn.Signature().Results()
+		}
+	}
+	return nil, nil
+}
+
+// returnedCall returns the call whose error expr, returned at pos, holds:
+// the call itself, or the call the local variable expr was assigned from
+// by the only assignment that reaches pos.
+func (l *linter) returnedCall(pass *analysis.Pass, expr ast.Expr, pos token.Pos, stack []ast.Node) *ast.CallExpr {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.CallExpr:
+		return e
+	case *ast.Ident:
+		v, ok := pass.TypesInfo.Uses[e].(*types.Var)
+		if !ok || v.Parent() == v.Pkg().Scope() {
+			return nil
+		}
+		stmt := l.facts.reaching(v, pos)
+		if stmt == nil || reassigned(pass, v, stmt, pos, stack) {
+			return nil
+		}
+		for {
+			labeled, ok := stmt.(*ast.LabeledStmt)
+			if !ok {
+				break
+			}
+			stmt = labeled.Stmt
+		}
+		switch stmt := stmt.(type) {
+		case *ast.AssignStmt:
+			return assignedCall(pass, v, stmt.Lhs, stmt.Rhs)
+		case *ast.DeclStmt:
+			for _, spec := range stmt.Decl.(*ast.GenDecl).Specs {
+				spec := spec.(*ast.ValueSpec)
+				lhs := make([]ast.Expr, len(spec.Names))
+				for i, name := range spec.Names {
+					lhs[i] = name
+				}
+				if call := assignedCall(pass, v, lhs, spec.Values); call != nil {
+					return call
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// assignedCall returns the call the assignment of rhs to lhs assigns v
+// from, or nil.
+func assignedCall(pass *analysis.Pass, v *types.Var, lhs, rhs []ast.Expr) *ast.CallExpr {
+	for i, x := range lhs {
+		if id, ok := x.(*ast.Ident); !ok || pass.TypesInfo.ObjectOf(id) != v {
+			continue
+		}
+		var call *ast.CallExpr
+		switch {
+		case len(rhs) == len(lhs):
+			call, _ = ast.Unparen(rhs[i]).(*ast.CallExpr)
+		case len(rhs) == 1:
+			call, _ = ast.Unparen(rhs[0]).(*ast.CallExpr)
+		}
+		return call
+	}
+	return nil
+}
+
+// reassigned reports whether v may be assigned after stmt and before pos,
+// in a closure that may run at any time, or through a pointer, anywhere in
+// the function declaration of stack.
+func reassigned(pass *analysis.Pass, v *types.Var, stmt ast.Stmt, pos token.Pos, stack []ast.Node) bool {
+	var decl *ast.FuncDecl
+	for _, n := range stack {
+		if fd, ok := n.(*ast.FuncDecl); ok {
+			decl = fd
+			break
+		}
+	}
+	if decl == nil {
+		return true
+	}
+	is := func(expr ast.Expr) bool {
+		id, ok := ast.Unparen(expr).(*ast.Ident)
+		return ok && pass.TypesInfo.ObjectOf(id) == v
+	}
+	found := false
+	var visit func(n ast.Node, anytime bool)
+	visit = func(n ast.Node, anytime bool) {
+		ast.Inspect(n, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				if !anytime && (pos < n.Pos() || pos >= n.End()) {
+					visit(n.Body, true)
+					return false
+				}
+			case *ast.AssignStmt:
+				if n != stmt && (anytime || stmt.Pos() < n.Pos() && n.Pos() < pos) && slices.ContainsFunc(n.Lhs, is) {
+					found = true
+				}
+			case *ast.UnaryExpr:
+				found = found || n.Op == token.AND && is(n.X)
+			}
+			return !found
+		})
+	}
+	visit(decl.Body, false)
+	return found
+}
+
+// passedThrough returns the function of another package call invokes if
+// its error would be returned as it is by fn, or nil. Calls that are
+// allowlisted, or that take an error, which they are assumed to wrap, are
+// exempt, and so are methods delegating to a method of the same name, such
+// as a Read method returning the io.EOF of the reader it wraps.
+func (l *linter) passedThrough(pass *analysis.Pass, call *ast.CallExpr, fn *types.Func) *types.Func {
+	callee, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || callee.Pkg() == nil || callee.Pkg() == pass.Pkg {
+		return nil
+	}
+	callee = callee.Origin()
+	if l.passthrough.allows(callee) {
+		return nil
+	}
+	for _, arg := range call.Args {
+		if isError(pass, arg) && !isNil(pass, arg) {
+			return nil
+		}
+	}
+	if fn != nil && fn.Signature().Recv() != nil && callee.Signature().Recv() != nil && fn.Name() == callee.Name() {
+		return nil
+	}
+	return callee
+}
@@ -0,0 +1,172 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer/internal/verbs"
+)
+
+// checkWrapMessages reports wrap messages of fmt.Errorf that break the
+// convention that keeps chains readable: the context comes first and ends
+// in ": %w", starts in lower case, does not end in punctuation and does not
+// repeat the message of the error it wraps. The context must also match
+// the -wrap-prefix pattern, if one is set. Calls with several %w verbs
+// join errors rather than add context to one, and are left alone.
+func (l *linter) checkWrapMessages(pass *analysis.Pass, insp *inspector.Inspector) {
+	messages := sentinelMessages(pass)
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		if !isFunc(pass, call, "fmt", "Errorf") || len(call.Args) < 2 || call.Ellipsis.IsValid() {
+			return
+		}
+		format, ok := constantString(pass, call.Args[0])
+		if !ok {
+			return
+		}
+		var wrap *verbs.Verb
+		for _, v := range verbs.Parse(format, len(call.Args)-1) {
+			if v.Verb != 'w' {
+				continue
+			}
+			if wrap != nil {
+				return
+			}
+			wrap = &v
+		}
+		if wrap == nil || wrap.Arg < 0 || wrap.Arg >= len(call.Args)-1 {
+			return
+		}
+
+		context, ok := strings.CutSuffix(format[:wrap.Start], ": ")
+		switch {
+		case format == "%w":
+			pass.Reportf(call.Args[0].Pos(), `wrap message "%%w" adds no context; say what the code was doing, as in "reading config: %%w"`)
+		case wrap.End != len(format) || !ok || context == "":
+			pass.Reportf(call.Args[0].Pos(), `wrap message %q does not end in ": %%w"; put the context first, as in "reading config: %%w", so the chain reads from the outermost operation to the cause`, format)
+		case capitalized(context):
+			pass.Report(analysis.Diagnostic{
+				Pos:            call.Args[0].Pos(),
+				End:            call.Args[0].End(),
+				Message:        fmt.Sprintf("wrap message %q starts with a capital letter, but it ends up in the middle of the messages of its callers; start it in lower case", format),
+				SuggestedFixes: lowerFix(call.Args[0]),
+			})
+		case strings.ContainsAny(context[len(context)-1:], ".!?:;, \t\n"):
+			pass.Reportf(call.Args[0].Pos(), "wrap message %q ends its context with %q; end it with a word", format, context[len(context)-1:])
+		case messages[wrappedVar(pass, call.Args[1+wrap.Arg])] == context:
+			pass.Reportf(call.Args[0].Pos(), "wrap message %q repeats the message of %s, so the error reads %q; say what the code was doing instead", format, render(pass, call.Args[1+wrap.Arg]), context+": "+context)
+		case l.wrapPrefix.re != nil && !l.wrapPrefix.re.MatchString(context):
+			pass.Reportf(call.Args[0].Pos(), "the context %q of the wrap message does not match the -wrap-prefix pattern %s", context, l.wrapPrefix.re)
+		}
+	})
+}
+
+// capitalized reports whether s starts with a capital letter that does not
+// start an acronym, such as the H of "HTTP request".
+func capitalized(s string) bool {
+	r, size := utf8.DecodeRuneInString(s)
+	if !unicode.IsUpper(r) {
+		return false
+	}
+	next, _ := utf8.DecodeRuneInString(s[size:])
+	return !unicode.IsUpper(next) && !unicode.IsDigit(next)
+}
+
+// lowerFix lowers the first letter of the format literal expr, if it is
+// a literal starting with an ASCII letter.
+func lowerFix(expr ast.Expr) []analysis.SuggestedFix {
+	lit, ok := ast.Unparen(expr).(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING || len(lit.Value) < 2 || lit.Value[1] < 'A' || lit.Value[1] > 'Z' {
+		return nil
+	}
+	return []analysis.SuggestedFix{{
+		Message: "Start the wrap message in lower case",
+		TextEdits: []analysis.TextEdit{{
+			Pos:     lit.Pos() + 1,
+			End:     lit.Pos() + 2,
+			NewText: []byte{lit.Value[1] + 'a' - 'A'},
+		}},
+	}}
+}
+
+// wrappedVar returns the package-level variable expr refers to, or nil.
+func wrappedVar(pass *analysis.Pass, expr ast.Expr) *types.Var {
+	id, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	v, ok := pass.TypesInfo.Uses[id].(*types.Var)
+	if !ok || v.Parent() != pass.Pkg.Scope() {
+		return nil
+	}
+	return v
+}
+
+// sentinelMessages maps the package-level variables of the package that
+// are declared with errors.New and a constant message to the message.
+func sentinelMessages(pass *analysis.Pass) map[*types.Var]string {
+	messages := make(map[*types.Var]string)
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.ValueSpec)
+				if len(spec.Values) != len(spec.Names) {
+					continue
+				}
+				for i, name := range spec.Names {
+					call, ok := ast.Unparen(spec.Values[i]).(*ast.CallExpr)
+					if !ok || !isFunc(pass, call, "errors", "New") || len(call.Args) != 1 {
+						continue
+					}
+					tv := pass.TypesInfo.Types[call.Args[0]]
+					if v, ok := pass.TypesInfo.Defs[name].(*types.Var); ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+						messages[v] = constant.StringVal(tv.Value)
+					}
+				}
+			}
+		}
+	}
+	return messages
+}
+
+// wrapPattern is the pattern the context of wrap messages must match, if
+// it is set. It implements flag.Value.
+type wrapPattern struct {
+	re *regexp.Regexp
+}
+
+func (p *wrapPattern) String() string {
+	if p == nil || p.re == nil {
+		return ""
+	}
+	return p.re.String()
+}
+
+// Set sets the pattern to the regular expression value, or unsets it if
+// value is empty.
+func (p *wrapPattern) Set(value string) error {
+	if value == "" {
+		p.re = nil
+		return nil
+	}
+	re, err := regexp.Compile(value)
+	if err != nil {
+		return fmt.Errorf("invalid wrap prefix pattern: %w", err)
+	}
+	p.re = re
+	return nil
+}
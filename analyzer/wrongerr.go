@@ -0,0 +1,216 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// checkWrongErrors reports branches that check one error variable and
+// return another, as in
+//
+//	data, err := read(name)
+//	if err != nil {
+//		return err
+//	}
+//	if err2 := validate(data); err2 != nil {
+//		return fmt.Errorf("validating %s: %w", name, err)
+//	}
+//
+// The error of the check is dropped, and when the error returned instead
+// was found nil by an earlier check, the caller sees success. A bare
+// return in a function with a named error result returns that result,
+// which is the wrong variable too if the branch checks another one. Only
+// returned variables that are known to be nil are reported: returning
+// another error that may be set, such as an error the function was
+// given, is often deliberate. Branches that use the checked error
+// anywhere, such as to log it, join it or assign it to the returned
+// variable, and branches that assign or check the returned variable
+// themselves are not reported either.
+func (l *linter) checkWrongErrors(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.WithStack([]ast.Node{(*ast.ReturnStmt)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		ret := n.(*ast.ReturnStmt)
+		ifStmt, checked := checkingBranch(pass, stack)
+		if ifStmt == nil {
+			return true
+		}
+		fn, results := enclosingFunc(pass, stack)
+		if fn == nil {
+			return true
+		}
+		returned, at, wrapped := returnedError(pass, ret, results)
+		if returned == nil || returned == checked || usesVar(pass, ifStmt.Body, checked) || assignsVar(pass, ifStmt.Body, returned) || checksVar(pass, ifStmt.Body, returned) {
+			return true
+		}
+
+		check := knownNil(pass, returned, stack)
+		if check == nil {
+			return true
+		}
+		var what string
+		switch {
+		case at == nil:
+			what = "returns the named result " + returned.Name() + " with a bare return"
+		case wrapped:
+			what = "wraps " + returned.Name()
+		default:
+			what = "returns " + returned.Name()
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:            ret.Pos(),
+			End:            ret.End(),
+			Message:        fmt.Sprintf("the branch for %s != nil %s, which is nil here after the check at line %d, so the error is dropped and the caller sees success; use %s", checked.Name(), what, pass.Fset.Position(check.Pos()).Line, checked.Name()),
+			SuggestedFixes: wrongErrorFix(pass, ret, at, results, returned, checked),
+		})
+		return true
+	})
+}
+
+// checkingBranch returns the innermost if statement of the function
+// holding the node that ends stack whose body holds the node, if its
+// condition checks a single local error variable with != nil, possibly
+// among other conditions joined with &&, and the variable.
+func checkingBranch(pass *analysis.Pass, stack []ast.Node) (*ast.IfStmt, *types.Var) {
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.FuncLit, *ast.FuncDecl:
+			return nil, nil
+		case *ast.IfStmt:
+			if stack[i+1] != n.Body {
+				continue
+			}
+			var vars []*types.Var
+			for _, cond := range conjuncts(n.Cond) {
+				bin, ok := ast.Unparen(cond).(*ast.BinaryExpr)
+				if !ok || bin.Op != token.NEQ {
+					continue
+				}
+				for _, pair := range [][2]ast.Expr{{bin.X, bin.Y}, {bin.Y, bin.X}} {
+					if v := localErrorVar(pass, pair[0]); v != nil && isNil(pass, pair[1]) {
+						vars = append(vars, v)
+					}
+				}
+			}
+			if len(vars) == 1 {
+				return n, vars[0]
+			}
+		}
+	}
+	return nil, nil
+}
+
+// conjuncts returns the conditions cond joins with &&.
+func conjuncts(cond ast.Expr) []ast.Expr {
+	if bin, ok := ast.Unparen(cond).(*ast.BinaryExpr); ok && bin.Op == token.LAND {
+		return append(conjuncts(bin.X), conjuncts(bin.Y)...)
+	}
+	return []ast.Expr{cond}
+}
+
+// localErrorVar returns the local error variable expr names, or nil.
+func localErrorVar(pass *analysis.Pass, expr ast.Expr) *types.Var {
+	id, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	v, ok := pass.TypesInfo.Uses[id].(*types.Var)
+	if !ok || v.Pkg() != pass.Pkg || v.Parent() == pass.Pkg.Scope() || v.IsField() || !isErrorVar(v) {
+		return nil
+	}
+	return v
+}
+
+// returnedError returns the local error variable ret returns, the
+// identifier that names it, and whether ret passes it to a call, such as
+// fmt.Errorf, rather than returning it as it is. A bare return returns the
+// last named error result of a function, and no identifier.
+func returnedError(pass *analysis.Pass, ret *ast.ReturnStmt, results *types.Tuple) (*types.Var, *ast.Ident, bool) {
+	if len(ret.Results) == 0 {
+		for i := results.Len() - 1; i >= 0; i-- {
+			if r := results.At(i); r.Name() != "" && isErrorVar(r) {
+				return r, nil, false
+			}
+		}
+		return nil, nil, false
+	}
+	for _, result := range ret.Results {
+		if v := localErrorVar(pass, result); v != nil {
+			return v, ast.Unparen(result).(*ast.Ident), false
+		}
+		call, ok := ast.Unparen(result).(*ast.CallExpr)
+		if !ok || !isError(pass, call) {
+			continue
+		}
+		for _, arg := range call.Args {
+			if v := localErrorVar(pass, arg); v != nil {
+				return v, ast.Unparen(arg).(*ast.Ident), true
+			}
+		}
+	}
+	return nil, nil, false
+}
+
+// usesVar reports whether n refers to v.
+func usesVar(pass *analysis.Pass, n ast.Node, v *types.Var) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && pass.TypesInfo.Uses[id] == v {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// checksVar reports whether an if statement in n compares v with nil.
+func checksVar(pass *analysis.Pass, n ast.Node, v *types.Var) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		if ifStmt, ok := n.(*ast.IfStmt); ok && (comparesNil(pass, ifStmt.Cond, v, token.EQL) || comparesNil(pass, ifStmt.Cond, v, token.NEQ)) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// wrongErrorFix returns the fix that uses checked where ret uses
+// returned: in place of the identifier at, or as the error result of a
+// bare return, which then names every result. It returns nil if checked
+// is not in scope as itself, or a result has no name to return it by.
+func wrongErrorFix(pass *analysis.Pass, ret *ast.ReturnStmt, at *ast.Ident, results *types.Tuple, returned, checked *types.Var) []analysis.SuggestedFix {
+	if scope := pass.Pkg.Scope().Innermost(ret.Pos()); scope == nil {
+		return nil
+	} else if _, obj := scope.LookupParent(checked.Name(), ret.Pos()); obj != checked {
+		return nil
+	}
+	edit := analysis.TextEdit{Pos: ret.Pos(), End: ret.End()}
+	if at != nil {
+		edit = analysis.TextEdit{Pos: at.Pos(), End: at.End(), NewText: []byte(checked.Name())}
+	} else {
+		names := make([]string, results.Len())
+		for i := range names {
+			r := results.At(i)
+			if r.Name() == "" || r.Name() == "_" {
+				return nil
+			}
+			names[i] = r.Name()
+			if r == returned {
+				names[i] = checked.Name()
+			}
+		}
+		edit.NewText = []byte("return " + strings.Join(names, ", "))
+	}
+	return []analysis.SuggestedFix{{
+		Message:   "Use " + checked.Name(),
+		TextEdits: []analysis.TextEdit{edit},
+	}}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kakkoyun/demo-error-lint/demos"
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errexit"
+)
+
+const usage = `usage: demo-error-lint <command> [arguments]
+
+Commands:
+  list                 list the demos
+  run <demo>...        run the named demos, or all of them with "run all"
+  tutorial [<demo>...] pick the fix of each demo, or of the named ones
+  help                 print this help
+`
+
+func main() {
+	errexit.Run(func() error {
+		return run(os.Args[1:], os.Stdin, os.Stdout)
+	})
+}
+
+// run runs the command in args. Invalid command lines fail with the
+// errcode.InvalidInput code, so the program exits with status 2.
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return usageError("missing command")
+	}
+	switch args[0] {
+	case "list":
+		tw := tabwriter.NewWriter(stdout, 0, 8, 1, ' ', 0)
+		for _, d := range demos.All {
+			fmt.Fprintf(tw, "%s\t%s\n", d.Name, d.Title)
+		}
+		return tw.Flush()
+	case "run":
+		selected, err := selectDemos(args[1:])
+		if err != nil {
+			return err
+		}
+		for i, d := range selected {
+			if i > 0 {
+				fmt.Fprintln(stdout)
+			}
+			printDemo(stdout, d)
+		}
+		return nil
+	case "tutorial":
+		selected := demos.All
+		if len(args) > 1 {
+			var err error
+			if selected, err = selectDemos(args[1:]); err != nil {
+				return err
+			}
+		}
+		tutor(selected, stdin, stdout)
+		return nil
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return nil
+	}
+	return usageError(fmt.Sprintf("unknown command %q", args[0]))
+}
+
+// usageError returns an invalid input error with msg followed by the
+// usage.
+func usageError(msg string) error {
+	return errcode.WithCode(fmt.Errorf("%s\n\n%s", msg, strings.TrimSuffix(usage, "\n")), errcode.InvalidInput)
+}
+
+// selectDemos returns the demos with the given names, or all of them for
+// "all".
+func selectDemos(names []string) ([]demo.Demo, error) {
+	if len(names) == 0 {
+		return nil, errcode.WithCode(errors.New("run needs a demo name; see demo-error-lint list"), errcode.InvalidInput)
+	}
+	if len(names) == 1 && names[0] == "all" {
+		return demos.All, nil
+	}
+	var selected []demo.Demo
+	for _, name := range names {
+		d, ok := demos.Lookup(name)
+		if !ok {
+			return nil, errcode.WithCode(fmt.Errorf("unknown demo %q; see demo-error-lint list", name), errcode.InvalidInput)
+		}
+		selected = append(selected, d)
+	}
+	return selected, nil
+}
+
+// printDemo prints the code of d and the output of running it.
+func printDemo(w io.Writer, d demo.Demo) {
+	var out bytes.Buffer
+	d.Run(&out)
+
+	fmt.Fprintf(w, "== %s: %s\n", d.Name, d.Title)
+	fmt.Fprintf(w, "\nBuggy code:\n%s", indent(d.Buggy))
+	fmt.Fprintf(w, "\nCorrected code:\n%s", indent(d.Correct))
+	fmt.Fprintf(w, "\nOutput:\n%s", indent(out.String()))
+}
+
+func indent(s string) string {
+	var b strings.Builder
+	for line := range strings.Lines(s) {
+		if line != "\n" {
+			b.WriteString("    ")
+		}
+		b.WriteString(line)
+	}
+	if !strings.HasSuffix(s, "\n") {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
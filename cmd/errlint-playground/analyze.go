@@ -0,0 +1,185 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer"
+	"github.com/kakkoyun/demo-error-lint/errcode"
+)
+
+// fileName is the name the analyzed code is given.
+const fileName = "main.go"
+
+// workspace is the scratch module code is analyzed in. Only its go.mod is
+// on disk; the code itself is passed to go/packages as an overlay, so
+// requests do not interfere with each other.
+type workspace struct {
+	dir string
+}
+
+func newWorkspace() (*workspace, error) {
+	dir, err := os.MkdirTemp("", "errlint-playground")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module playground\n\ngo 1.25\n"), 0o644); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	return &workspace{dir: dir}, nil
+}
+
+func (ws *workspace) close() {
+	os.RemoveAll(ws.dir)
+}
+
+// diagnostic is a finding of the analyzer in the analyzed code.
+type diagnostic struct {
+	Check     string `json:"check"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"endLine"`
+	EndColumn int    `json:"endColumn"`
+	Message   string `json:"message"`
+	// Fixed is set if the suggested fix of the finding is applied in
+	// result.Fixed.
+	Fixed bool `json:"fixed"`
+}
+
+// result is the outcome of analyzing code.
+type result struct {
+	Diagnostics []diagnostic `json:"diagnostics"`
+	// Fixed is the code with the first suggested fix of each diagnostic
+	// applied, formatted with gofmt.
+	Fixed string `json:"fixed"`
+}
+
+// edit replaces the bytes [start, end) of the code with text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// analyze runs the analyzer on src. Code that does not compile is an
+// errcode.InvalidInput error listing the compiler errors.
+func (ws *workspace) analyze(ctx context.Context, src []byte) (*result, error) {
+	file := filepath.Join(ws.dir, fileName)
+	cfg := &packages.Config{
+		Context: ctx,
+		Mode:    packages.LoadAllSyntax,
+		Dir:     ws.dir,
+		Env:     append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOWORK=off"),
+		Overlay: map[string][]byte{file: src},
+	}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return nil, fmt.Errorf("loading code: %w", err)
+	}
+	var errs []error
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, err := range pkg.Errors {
+			// Report positions relative to the scratch module.
+			err.Pos = strings.TrimPrefix(err.Pos, ws.dir+string(filepath.Separator))
+			errs = append(errs, err)
+		}
+	})
+	if len(errs) > 0 {
+		return nil, errcode.WithCode(errors.Join(errs...), errcode.InvalidInput)
+	}
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{analyzer.Analyzer}, pkgs, nil)
+	if err != nil {
+		return nil, fmt.Errorf("analyzing code: %w", err)
+	}
+
+	res := &result{Diagnostics: []diagnostic{}}
+	var edits []edit
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return nil, fmt.Errorf("analyzing code: %w", act.Err)
+		}
+		fset := act.Package.Fset
+		for _, d := range act.Diagnostics {
+			pos := fset.Position(d.Pos)
+			end := pos
+			if d.End.IsValid() {
+				end = fset.Position(d.End)
+			}
+			diag := diagnostic{
+				Check:     d.Category,
+				Line:      pos.Line,
+				Column:    pos.Column,
+				EndLine:   end.Line,
+				EndColumn: end.Column,
+				Message:   d.Message,
+			}
+			if len(d.SuggestedFixes) > 0 {
+				var fix []edit
+				for _, te := range d.SuggestedFixes[0].TextEdits {
+					start, end := fset.Position(te.Pos), te.End
+					if !end.IsValid() {
+						end = te.Pos
+					}
+					fix = append(fix, edit{start: start.Offset, end: fset.Position(end).Offset, text: string(te.NewText)})
+				}
+				edits, diag.Fixed = merge(edits, fix)
+			}
+			res.Diagnostics = append(res.Diagnostics, diag)
+		}
+	}
+	slices.SortFunc(res.Diagnostics, func(a, b diagnostic) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column), cmp.Compare(a.Message, b.Message))
+	})
+
+	res.Fixed = string(apply(src, edits))
+	return res, nil
+}
+
+// merge adds the edits of one fix to the sorted list base, like errlint
+// -fix does: edits identical to one in base are dropped, and the whole fix
+// is skipped, returning base and false, if any other edit overlaps or
+// touches one in base.
+func merge(base, fix []edit) ([]edit, bool) {
+	out := slices.Clone(base)
+	for _, e := range fix {
+		if slices.Contains(base, e) {
+			continue
+		}
+		for _, o := range base {
+			if e.start < o.end && o.start < e.end || e.start == o.start {
+				return base, false
+			}
+		}
+		out = append(out, e)
+	}
+	slices.SortStableFunc(out, func(a, b edit) int { return cmp.Compare(a.start, b.start) })
+	return out, true
+}
+
+// apply applies the sorted edits to src and formats the result.
+func apply(src []byte, edits []edit) []byte {
+	var out []byte
+	last := 0
+	for _, e := range edits {
+		out = append(out, src[last:e.start]...)
+		out = append(out, e.text...)
+		last = e.end
+	}
+	out = append(out, src[last:]...)
+	if formatted, err := format.Source(out); err == nil {
+		out = formatted
+	}
+	return out
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+)
+
+// unwrapped is code with an error formatted with %v and compared with ==,
+// and fixedUnwrapped the code with both fixes applied.
+const (
+	unwrapped = `package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrNotFound = errors.New("not found")
+
+func find() error { return fmt.Errorf("finding: %v", ErrNotFound) }
+
+func main() {
+	if find() == ErrNotFound {
+		fmt.Println("not found")
+	}
+}
+`
+	fixedUnwrapped = `package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrNotFound = errors.New("not found")
+
+func find() error { return fmt.Errorf("finding: %w", ErrNotFound) }
+
+func main() {
+	if errors.Is(find(), ErrNotFound) {
+		fmt.Println("not found")
+	}
+}
+`
+)
+
+// testWorkspace returns a workspace that is removed at the end of the test.
+func testWorkspace(t *testing.T) *workspace {
+	t.Helper()
+	ws, err := newWorkspace()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(ws.close)
+	return ws
+}
+
+// TestAnalyze checks the findings and the fixed code of code with
+// findings, and the error of code that does not compile.
+func TestAnalyze(t *testing.T) {
+	ws := testWorkspace(t)
+
+	res, err := ws.analyze(context.Background(), []byte(unwrapped))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range res.Diagnostics {
+		got = append(got, fmt.Sprintf("%d:%d-%d:%d %s fixed=%t", d.Line, d.Column, d.EndLine, d.EndColumn, d.Check, d.Fixed))
+	}
+	want := []string{"10:54-10:65 errorf fixed=true", "13:5-13:26 comparison fixed=true"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("diagnostics = %q, want %q", got, want)
+	}
+	if res.Fixed != fixedUnwrapped {
+		t.Errorf("fixed code:\n%s\nwant:\n%s", res.Fixed, fixedUnwrapped)
+	}
+
+	res, err = ws.analyze(context.Background(), []byte(fixedUnwrapped))
+	if err != nil || len(res.Diagnostics) != 0 || res.Fixed != fixedUnwrapped {
+		t.Errorf("analyze(fixed code) = %+v, %v; want no diagnostics and the same code", res, err)
+	}
+
+	_, err = ws.analyze(context.Background(), []byte("package main\n\nfunc main() { x }\n"))
+	if code := errcode.CodeOf(err); code != errcode.InvalidInput || fmt.Sprint(err) != "main.go:3:15: undefined: x" {
+		t.Errorf("analyze(code that does not compile) = %v with code %v, want the compiler error with InvalidInput", err, code)
+	}
+}
+
+// TestMerge checks that merge keeps the edits sorted, drops edits that are
+// already there, and skips fixes that overlap or touch the edits before.
+func TestMerge(t *testing.T) {
+	base := []edit{{start: 10, end: 20, text: "a"}, {start: 30, end: 30, text: "b"}}
+	tests := []struct {
+		name string
+		fix  []edit
+		want []edit
+		ok   bool
+	}{
+		{"before", []edit{{start: 0, end: 5, text: "c"}}, []edit{{0, 5, "c"}, {10, 20, "a"}, {30, 30, "b"}}, true},
+		{"between", []edit{{start: 20, end: 25, text: "c"}}, []edit{{10, 20, "a"}, {20, 25, "c"}, {30, 30, "b"}}, true},
+		{"same edit", []edit{{start: 10, end: 20, text: "a"}, {start: 40, end: 41, text: "c"}}, []edit{{10, 20, "a"}, {30, 30, "b"}, {40, 41, "c"}}, true},
+		{"overlapping", []edit{{start: 0, end: 5, text: "c"}, {start: 15, end: 25, text: "d"}}, base, false},
+		{"same start", []edit{{start: 10, end: 12, text: "c"}}, base, false},
+		{"same insertion point", []edit{{start: 30, end: 30, text: "c"}}, base, false},
+		{"inside", []edit{{start: 12, end: 14, text: "c"}}, base, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := merge(base, tt.fix)
+			if ok != tt.ok || fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("merge = %v, %t, want %v, %t", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+	if len(base) != 2 {
+		t.Errorf("merge changed its base to %v", base)
+	}
+}
+
+// TestApply checks that apply applies the edits in order and formats the
+// code, and leaves code it cannot format as it is.
+func TestApply(t *testing.T) {
+	src := []byte("package main\nvar  x = 1\nvar y = 2\n")
+	got := apply(src, []edit{{start: 22, end: 23, text: "10"}, {start: 24, end: 34, text: ""}})
+	if want := "package main\n\nvar x = 10\n"; string(got) != want {
+		t.Errorf("apply = %q, want %q", got, want)
+	}
+	got = apply(src, []edit{{start: 0, end: 7, text: "}"}})
+	if want := "} main\nvar  x = 1\nvar y = 2\n"; string(got) != want {
+		t.Errorf("apply of an edit that breaks the code = %q, want %q", got, want)
+	}
+}
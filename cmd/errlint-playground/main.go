@@ -0,0 +1,59 @@
+// Command errlint-playground serves a web page where Go code pasted into a
+// form is checked by the errlint analyzer, for demos and workshops.
+//
+// Usage:
+//
+//	errlint-playground [-addr host:port]
+//
+// The page shows the findings and the code with their suggested fixes
+// applied. POST /analyze takes the code as the form value "code" and
+// answers with JSON instead of HTML if the request accepts
+// application/json:
+//
+//	curl -H 'Accept: application/json' --data-urlencode code@main.go localhost:8080/analyze
+//
+// The code is analyzed in memory, as the only file of a scratch module
+// without dependencies, so it may only import the standard library.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "`address` to listen on")
+	flag.Parse()
+
+	ws, err := newWorkspace()
+	if err != nil {
+		log.Fatalf("errlint-playground: %v", err)
+	}
+	defer ws.close()
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           newServer(ws),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		srv.Shutdown(context.Background())
+	}()
+
+	fmt.Printf("errlint-playground: listening on http://%s\n", *addr)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("errlint-playground: %v", err)
+		ws.close()
+		os.Exit(1)
+	}
+}
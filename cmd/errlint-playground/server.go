@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"html/template"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errhttp"
+)
+
+const (
+	// maxCodeSize is the largest code accepted, in bytes.
+	maxCodeSize = 64 << 10
+	// timeout bounds the time spent loading and analyzing code.
+	timeout = 30 * time.Second
+)
+
+// example is the code the page starts with.
+const example = `package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrNotFound = errors.New("not found")
+
+type PathError struct{ Path string }
+
+func (e *PathError) Error() string { return e.Path + ": invalid" }
+
+func find(name string) error {
+	return fmt.Errorf("finding %s: %v", name, ErrNotFound)
+}
+
+func main() {
+	err := find("config")
+	if err == ErrNotFound {
+		fmt.Println("not found")
+	}
+	if pe, ok := err.(*PathError); ok {
+		fmt.Println(pe.Path)
+	}
+	if _, err := os.Open("missing"); os.IsNotExist(err) {
+		fmt.Println("missing")
+	}
+}
+`
+
+var page = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>errlint playground</title>
+<style>
+body { font-family: sans-serif; max-width: 60em; margin: 2em auto; padding: 0 1em; }
+textarea, pre { font-family: monospace; font-size: 0.9em; width: 100%; box-sizing: border-box; }
+pre { background: #f4f4f4; padding: 1em; overflow-x: auto; }
+.error { color: #b00020; white-space: pre-wrap; }
+td { padding: 0.2em 0.6em; vertical-align: top; }
+</style>
+</head>
+<body>
+<h1>errlint playground</h1>
+<p>Paste a Go program that only imports the standard library, and errlint reports its error handling anti-patterns.</p>
+<form method="post" action="/analyze">
+<textarea name="code" rows="24" spellcheck="false">{{.Code}}</textarea>
+<p><button type="submit">Analyze</button></p>
+</form>
+{{with .Error}}<p class="error">{{.}}</p>{{end}}
+{{with .Result}}
+<h2>Findings</h2>
+{{if .Diagnostics}}
+<table>
+{{range .Diagnostics}}<tr><td>{{.Line}}:{{.Column}}</td><td>{{.Check}}</td><td>{{.Message}}{{if .Fixed}} (fixed){{end}}</td></tr>
+{{end}}</table>
+<h2>Fixed code</h2>
+<pre>{{.Fixed}}</pre>
+{{else}}
+<p>No findings.</p>
+{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+// pageData is what page renders.
+type pageData struct {
+	Code   string
+	Result *result
+	Error  string
+}
+
+// newServer returns the handler of the playground.
+func newServer(ws *workspace) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		render(w, http.StatusOK, pageData{Code: example})
+	})
+	mux.Handle("POST /analyze", errhttp.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		r.Body = http.MaxBytesReader(w, r.Body, maxCodeSize)
+		if err := r.ParseForm(); err != nil {
+			return errcode.WithCode(err, errcode.InvalidInput)
+		}
+		code := r.PostForm.Get("code")
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		res, err := ws.analyze(ctx, []byte(code))
+
+		if acceptsJSON(r) {
+			if err != nil {
+				return err
+			}
+			w.Header().Set("Content-Type", "application/json")
+			return json.NewEncoder(w).Encode(res)
+		}
+		if err != nil {
+			// Only client errors, such as compiler errors, are shown
+			// in detail; ProblemOf leaves the detail of others empty.
+			p := errhttp.ProblemOf(err)
+			msg := p.Title
+			if p.Detail != "" {
+				msg += ": " + p.Detail
+			}
+			render(w, p.Status, pageData{Code: code, Error: msg})
+			return nil
+		}
+		render(w, http.StatusOK, pageData{Code: code, Result: res})
+		return nil
+	}))
+	return mux
+}
+
+// acceptsJSON reports whether r asks for a JSON response.
+func acceptsJSON(r *http.Request) bool {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		if t, _, err := mime.ParseMediaType(accept); err == nil && t == "application/json" {
+			return true
+		}
+	}
+	return false
+}
+
+func render(w http.ResponseWriter, status int, data pageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := page.Execute(w, data); err != nil {
+		log.Printf("errlint-playground: rendering page: %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/kakkoyun/demo-error-lint/errhttp"
+)
+
+// post posts code to the /analyze endpoint of srv, asking for JSON if
+// asJSON is set, and returns the response with its body read.
+func post(t *testing.T, srv *httptest.Server, code string, asJSON bool) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/analyze", strings.NewReader(url.Values{"code": {code}}.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if asJSON {
+		req.Header.Set("Accept", "text/html;q=0.5, application/json")
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(body)
+}
+
+// TestServer checks the responses of the playground: the page, the JSON
+// and HTML results of /analyze, and its errors for code that does not
+// compile and code that is too large.
+func TestServer(t *testing.T) {
+	srv := httptest.NewServer(newServer(testWorkspace(t)))
+	defer srv.Close()
+
+	t.Run("page", func(t *testing.T) {
+		resp, err := srv.Client().Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "var ErrNotFound = errors.New(&#34;not found&#34;)") {
+			t.Errorf("GET / = %s with body:\n%s\nwant the example code", resp.Status, body)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		resp, body := post(t, srv, unwrapped, true)
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+			t.Fatalf("POST /analyze = %s %s, want 200 application/json", resp.Status, resp.Header.Get("Content-Type"))
+		}
+		var res result
+		if err := json.Unmarshal([]byte(body), &res); err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Diagnostics) != 2 || res.Diagnostics[0].Check != "errorf" || res.Diagnostics[1].Check != "comparison" || res.Fixed != fixedUnwrapped {
+			t.Errorf("POST /analyze = %s, want the errorf and comparison findings and the fixed code", body)
+		}
+	})
+
+	t.Run("json without findings", func(t *testing.T) {
+		_, body := post(t, srv, fixedUnwrapped, true)
+		if !strings.HasPrefix(body, `{"diagnostics":[],`) {
+			t.Errorf("POST /analyze = %s, want an empty list of diagnostics", body)
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		resp, body := post(t, srv, unwrapped, false)
+		if resp.StatusCode != http.StatusOK || !strings.Contains(body, "use errors.Is [ERRLINT001] (fixed)") || !strings.Contains(body, "if errors.Is(find(), ErrNotFound) {") {
+			t.Errorf("POST /analyze = %s with body:\n%s\nwant the findings and the fixed code", resp.Status, body)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		code   string
+		status int
+		detail string
+	}{
+		{"compile error", "package main\n\nfunc main() { x }\n", http.StatusBadRequest, "main.go:3:15: undefined: x"},
+		{"too large", strings.Repeat("a", maxCodeSize), http.StatusBadRequest, "http: request body too large"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := post(t, srv, tt.code, true)
+			var p errhttp.Problem
+			if err := json.Unmarshal([]byte(body), &p); err != nil {
+				t.Fatalf("POST /analyze = %s, want problem details: %v", body, err)
+			}
+			if resp.StatusCode != tt.status || p.Status != tt.status || p.Detail != tt.detail || p.Code != "InvalidInput" {
+				t.Errorf("POST /analyze = %s %s, want %d with the detail %q and the code InvalidInput", resp.Status, body, tt.status, tt.detail)
+			}
+		})
+	}
+
+	t.Run("html compile error", func(t *testing.T) {
+		resp, body := post(t, srv, "package main\n\nfunc main() { x }\n", false)
+		if resp.StatusCode != http.StatusBadRequest || !strings.Contains(body, `<p class="error">Bad Request: main.go:3:15: undefined: x</p>`) {
+			t.Errorf("POST /analyze = %s with body:\n%s\nwant 400 and the compiler error on the page", resp.Status, body)
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer"
+	"github.com/kakkoyun/demo-error-lint/internal/lint"
+)
+
+// finding is a diagnostic reported by the analyzer, resolved to a position.
+type finding struct {
+	analysis.Diagnostic
+
+	// Position and End are where the finding starts and ends. Their
+	// filenames are relative to the working directory when possible.
+	Position, End token.Position
+	// Fset is the file set the diagnostic's positions refer to.
+	Fset *token.FileSet
+	// Severity is error, warning or info. Findings of checks that are off
+	// are dropped before they are reported.
+	Severity string
+	// Generated is set if the finding is in a generated file.
+	Generated bool
+	// Package is the path of the package the finding was reported in, or
+	// of the package under test for test packages.
+	Package string
+	// Owners are the owners of the file of the finding in the CODEOWNERS
+	// file. They are only looked up for the formats that show them.
+	Owners []string
+}
+
+// analyze loads the packages matching patterns and returns the analyzer's
+// findings, sorted by position. Findings reported for several variants of
+// a package, such as the package and its test variant, appear once.
+// overlay maps file names to contents that replace the files on disk.
+func analyze(patterns []string, tests bool, overlay map[string][]byte) ([]finding, error) {
+	pkgs, err := load(patterns, tests, overlay)
+	if err != nil {
+		return nil, err
+	}
+	return analyzePackages(pkgs)
+}
+
+// load loads the packages matching patterns, with their test variants if
+// tests is set and with the overlay in place of the files on disk, and
+// returns an error if any of them or their dependencies failed to load or
+// type-check.
+func load(patterns []string, tests bool, overlay map[string][]byte) ([]*packages.Package, error) {
+	return lint.Load(&packages.Config{Tests: tests, Overlay: overlay}, patterns...)
+}
+
+// analyzePackages runs the analyzer on the loaded packages pkgs, see
+// analyze.
+func analyzePackages(pkgs []*packages.Package) ([]finding, error) {
+	diags, err := lint.Analyze(analyzer.Analyzer, pkgs)
+	if err != nil {
+		return nil, err
+	}
+	wd := workDir()
+	findings := make([]finding, len(diags))
+	for i, d := range diags {
+		findings[i] = newFinding(d.Diagnostic, d.Fset, d.Generated, d.Package, wd)
+	}
+	sortFindings(findings)
+	return findings, nil
+}
+
+// setConcurrency limits the number of packages loaded and analyzed at once
+// to n, by limiting the number of threads running Go code; the checker
+// analyzes each package as soon as its dependencies are done. n = 0 keeps
+// GOMAXPROCS, by default the number of CPUs.
+func setConcurrency(n int) error {
+	if n < 0 {
+		return fmt.Errorf("invalid -concurrency %d", n)
+	}
+	if n > 0 {
+		runtime.GOMAXPROCS(n)
+	}
+	return nil
+}
+
+// newFinding resolves the positions of the diagnostic d in fset, with file
+// names relative to the working directory wd.
+func newFinding(d analysis.Diagnostic, fset *token.FileSet, generated bool, pkg, wd string) finding {
+	pos := fset.Position(d.Pos)
+	pos.Filename = relative(wd, pos.Filename)
+	end := pos
+	if d.End.IsValid() {
+		end = fset.Position(d.End)
+		end.Filename = pos.Filename
+	}
+	return finding{
+		Diagnostic: d,
+		Position:   pos,
+		End:        end,
+		Fset:       fset,
+		Generated:  generated,
+		Package:    pkg,
+	}
+}
+
+// sortFindings sorts findings by position, then message.
+func sortFindings(findings []finding) {
+	slices.SortFunc(findings, func(a, b finding) int {
+		return cmp.Or(
+			cmp.Compare(a.Position.Filename, b.Position.Filename),
+			cmp.Compare(a.Position.Line, b.Position.Line),
+			cmp.Compare(a.Position.Column, b.Position.Column),
+			cmp.Compare(a.Message, b.Message),
+		)
+	})
+}
+
+// workDir returns the working directory, or "" if it is unknown.
+func workDir() string {
+	wd, _ := os.Getwd()
+	return wd
+}
+
+// relative returns filename relative to dir if it is inside dir.
+func relative(dir, filename string) string {
+	if dir == "" {
+		return filename
+	}
+	rel, err := filepath.Rel(dir, filename)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filename
+	}
+	return rel
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// annotatedName returns the name of the annotated copy of the Go file
+// name: file.annotated.go for file.go.
+func annotatedName(name string) string {
+	return strings.TrimSuffix(name, ".go") + ".annotated.go"
+}
+
+// readAnnotated returns the absolute name and the source of the file
+// errlint annotate annotates.
+func readAnnotated(name string) (string, []byte, error) {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return "", nil, err
+	}
+	src, err := os.ReadFile(abs)
+	if err != nil {
+		return "", nil, err
+	}
+	return abs, src, nil
+}
+
+// annotate returns src, the source of a file, with its findings as
+// comments beneath the lines they start on, indented like those lines:
+//
+//	if err == ErrMiss {
+//	// ^ warning: comparing errors with == fails on wrapped errors; use errors.Is [ERRLINT001]
+//
+// If buildIgnore is set, the result also starts with a //go:build ignore
+// constraint, which replaces that of the file, so that an annotated copy
+// next to the file is not built with its package.
+func annotate(src []byte, findings []finding, buildIgnore bool) []byte {
+	notes := make(map[int][]string)
+	for _, f := range findings {
+		notes[f.Position.Line] = append(notes[f.Position.Line], fmt.Sprintf("^ %s: %s", f.Severity, f.Message))
+	}
+
+	var out bytes.Buffer
+	constrained := false
+	lines := bytes.SplitAfter(src, []byte("\n"))
+	for i, line := range lines {
+		if buildIgnore && !constrained && bytes.HasPrefix(line, []byte("//go:build ")) {
+			out.WriteString("//go:build ignore\n")
+			constrained = true
+			continue
+		}
+		out.Write(line)
+		if len(notes[i+1]) == 0 {
+			continue
+		}
+		if !bytes.HasSuffix(line, []byte("\n")) {
+			out.WriteByte('\n')
+		}
+		indent := line[:len(line)-len(bytes.TrimLeft(line, " \t"))]
+		for _, note := range notes[i+1] {
+			fmt.Fprintf(&out, "%s// %s\n", indent, note)
+		}
+	}
+	if buildIgnore && !constrained {
+		return append([]byte("//go:build ignore\n\n"), out.Bytes()...)
+	}
+	return out.Bytes()
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kakkoyun/demo-error-lint/config"
+)
+
+// baselineVersion is the version of the baseline file format.
+const baselineVersion = 1
+
+// baseline lists known findings that are not reported, so that errlint can
+// be adopted on a codebase without fixing every existing finding first.
+type baseline struct {
+	Version  int             `json:"version"`
+	Findings []baselineEntry `json:"findings"`
+}
+
+// baselineEntry is a finding in a baseline. Only the fingerprint is
+// matched; the other fields are there for reviewers.
+type baselineEntry struct {
+	Fingerprint string `json:"fingerprint"`
+	Rule        string `json:"rule"`
+	File        string `json:"file"`
+	Message     string `json:"message"`
+}
+
+// baselinePath returns the baseline file to use: the -baseline flag if set,
+// then the baseline of the configuration file, then config.BaselineName
+// next to it. Outside of baseline generate, the last one is only used if it
+// exists, and "" means no baseline.
+func baselinePath(cfg *config.Config, flagValue string, generate bool) string {
+	switch {
+	case flagValue != "":
+		return flagValue
+	case cfg.Baseline != "":
+		return filepath.Join(cfg.Dir, cfg.Baseline)
+	}
+	name := filepath.Join(cfg.Dir, config.BaselineName)
+	if !generate {
+		if _, err := os.Stat(name); err != nil {
+			return ""
+		}
+	}
+	return name
+}
+
+// writeBaseline writes a baseline of findings to the file name.
+func writeBaseline(name string, findings []finding) error {
+	fp, err := newFingerprinter(name)
+	if err != nil {
+		return err
+	}
+	b := &baseline{Version: baselineVersion, Findings: []baselineEntry{}}
+	for _, f := range findings {
+		e, err := fp.entry(f)
+		if err != nil {
+			return err
+		}
+		b.Findings = append(b.Findings, e)
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name, append(data, '\n'), 0o644)
+}
+
+// readBaseline reads the baseline file name.
+func readBaseline(name string) (*baseline, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var b baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if b.Version != baselineVersion {
+		return nil, fmt.Errorf("%s: unsupported baseline version %d; run errlint baseline generate again", name, b.Version)
+	}
+	return &b, nil
+}
+
+// filterBaseline drops the findings that are in the baseline file name.
+// Every entry of the baseline matches one finding, so a finding that
+// appears once more than in the baseline is still reported. The source
+// lines of the files in overlay are taken from it rather than the disk.
+func filterBaseline(name string, findings []finding, overlay map[string][]byte) ([]finding, error) {
+	b, err := readBaseline(name)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]int)
+	for _, e := range b.Findings {
+		known[e.Fingerprint]++
+	}
+
+	fp, err := newFingerprinter(name)
+	if err != nil {
+		return nil, err
+	}
+	for abs, content := range overlay {
+		fp.lines[abs] = bytes.Split(content, []byte("\n"))
+	}
+	var out []finding
+	for _, f := range findings {
+		e, err := fp.entry(f)
+		if err != nil {
+			return nil, err
+		}
+		if known[e.Fingerprint] > 0 {
+			known[e.Fingerprint]--
+			continue
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+// fingerprinter computes the baseline entries of findings. A fingerprint
+// covers the check, the file relative to the baseline, the message and the
+// source line of a finding, but not its position, so that it survives code
+// being added or removed around the finding.
+type fingerprinter struct {
+	dir   string
+	ids   map[string]string
+	lines map[string][][]byte
+}
+
+func newFingerprinter(baselineName string) (*fingerprinter, error) {
+	dir, err := filepath.Abs(filepath.Dir(baselineName))
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]string)
+	for _, c := range allChecks() {
+		ids[c.Name] = c.ID
+	}
+	return &fingerprinter{dir: dir, ids: ids, lines: make(map[string][][]byte)}, nil
+}
+
+func (fp *fingerprinter) entry(f finding) (baselineEntry, error) {
+	abs, err := filepath.Abs(f.Position.Filename)
+	if err != nil {
+		return baselineEntry{}, err
+	}
+	file := abs
+	if rel, err := filepath.Rel(fp.dir, abs); err == nil && !strings.HasPrefix(rel, "..") {
+		file = filepath.ToSlash(rel)
+	}
+
+	lines, ok := fp.lines[abs]
+	if !ok {
+		content, err := os.ReadFile(abs)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return baselineEntry{}, err
+		}
+		lines = bytes.Split(content, []byte("\n"))
+		fp.lines[abs] = lines
+	}
+	var line []byte
+	if n := f.Position.Line; 0 < n && n <= len(lines) {
+		line = bytes.TrimSpace(lines[n-1])
+	}
+
+	e := baselineEntry{Rule: fp.ids[f.Category], File: file, Message: f.Message}
+	h := sha256.New()
+	for _, part := range [][]byte{[]byte(e.Rule), []byte(e.File), []byte(e.Message), line} {
+		h.Write(part)
+		h.Write([]byte{0})
+	}
+	e.Fingerprint = hex.EncodeToString(h.Sum(nil)[:8])
+	return e, nil
+}
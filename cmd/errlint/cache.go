@@ -0,0 +1,438 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer"
+	"github.com/kakkoyun/demo-error-lint/internal/lint"
+)
+
+// cacheVersion is the version of the format of cache entries. Changing it
+// invalidates every entry.
+const cacheVersion = 1
+
+// cacheEntryName matches the names of the files of cache entries.
+var cacheEntryName = regexp.MustCompile(`^[0-9a-f]{64}\.json$`)
+
+// cache stores the findings of packages on disk, keyed by a hash of
+// everything their analysis depends on, so that repeated runs only analyze
+// the packages that changed or whose dependencies changed.
+//
+// The key of a package covers the contents of its files, or for packages
+// of the module cache and the standard library, which do not change, the
+// module version or Go toolchain they come from, and the keys of the
+// packages it imports, whose facts it may use. Every key is salted with the
+// errlint executable, the analyzer flags and the environment of the go
+// command. The package and its test variants share an entry.
+type cache struct {
+	dir    string
+	salt   []byte
+	goroot string
+}
+
+// defaultCacheDir returns the errlint directory in the user cache
+// directory, or "" if there is none.
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "errlint")
+}
+
+// cacheFor returns the cache the -cache-dir flag value dir selects: none
+// if dir is "off", and if dir is empty the one in defaultCacheDir, or none
+// if that cannot be used.
+func cacheFor(dir string) (*cache, error) {
+	switch dir {
+	case "off":
+		return nil, nil
+	case "":
+		dir = defaultCacheDir()
+		if dir == "" {
+			return nil, nil
+		}
+		c, err := openCache(dir)
+		if err != nil {
+			return nil, nil
+		}
+		return c, nil
+	}
+	return openCache(dir)
+}
+
+// openCache opens the cache in dir, creating the directory if needed.
+func openCache(dir string) (*cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "errlint cache %d\n", cacheVersion)
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(exe)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	analyzer.Analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		fmt.Fprintf(h, "-%s=%s\n", f.Name, f.Value)
+	})
+	env, err := exec.Command("go", "env", "GOVERSION", "GOOS", "GOARCH", "GOFLAGS", "CGO_ENABLED", "GOROOT").Output()
+	if err != nil {
+		return nil, fmt.Errorf("go env: %w", err)
+	}
+	h.Write(env)
+	lines := strings.Split(strings.TrimSpace(string(env)), "\n")
+	return &cache{dir: dir, salt: h.Sum(nil), goroot: lines[len(lines)-1]}, nil
+}
+
+// analyze is like the analyze function, but takes the findings of the
+// packages that have an entry in the cache from it, and only loads and
+// analyzes the others, whose findings it adds to the cache.
+func (c *cache) analyze(patterns []string, tests bool) ([]finding, error) {
+	cfg := &packages.Config{
+		Mode:  packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedModule | packages.NeedForTest,
+		Tests: tests,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil || lint.PackageErrors(pkgs) != nil {
+		// Report the errors of a full load.
+		return analyze(patterns, tests, nil)
+	}
+
+	keys := make(map[string][]byte)
+	variants := make(map[string][][]byte)
+	for _, pkg := range pkgs {
+		if strings.HasSuffix(pkg.ID, ".test") {
+			// The generated main package of a test binary.
+			continue
+		}
+		key, err := c.key(pkg, keys)
+		if err != nil {
+			return nil, err
+		}
+		path := cmp.Or(pkg.ForTest, pkg.PkgPath)
+		variants[path] = append(variants[path], key)
+	}
+
+	wd := workDir()
+	fset := token.NewFileSet()
+	files := make(map[string]*token.File)
+	entries := make(map[string]string)
+	var (
+		findings []finding
+		missing  []string
+	)
+	for _, path := range slices.Sorted(maps.Keys(variants)) {
+		h := sha256.New()
+		slices.SortFunc(variants[path], bytes.Compare)
+		for _, key := range variants[path] {
+			h.Write(key)
+		}
+		entry := hex.EncodeToString(h.Sum(nil))
+		entries[path] = entry
+		cached, err := c.read(entry, fset, files, path, wd)
+		if err != nil {
+			missing = append(missing, path)
+			continue
+		}
+		findings = append(findings, cached...)
+	}
+
+	if len(missing) > 0 {
+		fresh, err := analyze(missing, tests, nil)
+		if err != nil {
+			return nil, err
+		}
+		byPath := make(map[string][]finding)
+		for _, f := range fresh {
+			byPath[f.Package] = append(byPath[f.Package], f)
+		}
+		for _, path := range missing {
+			if err := c.write(entries[path], byPath[path]); err != nil {
+				return nil, err
+			}
+		}
+		findings = append(findings, fresh...)
+	}
+	sortFindings(findings)
+	return findings, nil
+}
+
+// key returns the cache key of pkg, computing the keys of its imports
+// first. keys holds the keys computed so far by package ID.
+func (c *cache) key(pkg *packages.Package, keys map[string][]byte) ([]byte, error) {
+	if key, ok := keys[pkg.ID]; ok {
+		return key, nil
+	}
+	h := sha256.New()
+	h.Write(c.salt)
+	fmt.Fprintf(h, "package %s\n", pkg.ID)
+	immutable := pkg.Module == nil && c.goroot != "" && inDir(pkg.GoFiles, c.goroot)
+	if m := pkg.Module; m != nil && m.Version != "" && m.Replace == nil {
+		fmt.Fprintf(h, "module %s@%s\n", m.Path, m.Version)
+		immutable = true
+	}
+	for _, name := range pkg.GoFiles {
+		fmt.Fprintf(h, "file %s", name)
+		if !immutable {
+			content, err := os.ReadFile(name)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(h, " %x", sha256.Sum256(content))
+		}
+		fmt.Fprintln(h)
+	}
+	for _, path := range slices.Sorted(maps.Keys(pkg.Imports)) {
+		key, err := c.key(pkg.Imports[path], keys)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(h, "import %s %x\n", path, key)
+	}
+	keys[pkg.ID] = h.Sum(nil)
+	return keys[pkg.ID], nil
+}
+
+// inDir reports whether every file in names is inside dir.
+func inDir(names []string, dir string) bool {
+	for _, name := range names {
+		if rel, err := filepath.Rel(dir, name); err != nil || strings.HasPrefix(rel, "..") {
+			return false
+		}
+	}
+	return len(names) > 0
+}
+
+// cacheEntry holds the findings of a package and its test variants, with
+// positions as byte offsets.
+type cacheEntry struct {
+	Findings []cachedFinding `json:"findings"`
+}
+
+type cachedFinding struct {
+	cachedRange
+	Category  string          `json:"category"`
+	Message   string          `json:"message"`
+	URL       string          `json:"url,omitempty"`
+	Generated bool            `json:"generated,omitempty"`
+	Fixes     []cachedFix     `json:"fixes,omitempty"`
+	Related   []cachedRelated `json:"related,omitempty"`
+}
+
+type cachedFix struct {
+	Message string       `json:"message"`
+	Edits   []cachedEdit `json:"edits"`
+}
+
+type cachedEdit struct {
+	cachedRange
+	NewText string `json:"new_text"`
+}
+
+type cachedRelated struct {
+	cachedRange
+	Message string `json:"message"`
+}
+
+// cachedRange is a range of a file as byte offsets. End is -1 if the range
+// has no end.
+type cachedRange struct {
+	File  string `json:"file"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// read returns the findings of the cache entry for the package path. It
+// positions them in fset, adding the files they refer to, which it records
+// in files by name, and resolves their file names relative to wd.
+func (c *cache) read(entry string, fset *token.FileSet, files map[string]*token.File, path, wd string) ([]finding, error) {
+	data, err := os.ReadFile(filepath.Join(c.dir, entry+".json"))
+	if err != nil {
+		return nil, err
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+
+	pos := func(r cachedRange) (token.Pos, token.Pos, error) {
+		tf, ok := files[r.File]
+		if !ok {
+			content, err := os.ReadFile(r.File)
+			if err != nil {
+				return token.NoPos, token.NoPos, err
+			}
+			tf = fset.AddFile(r.File, -1, len(content))
+			tf.SetLinesForContent(content)
+			files[r.File] = tf
+		}
+		if r.Start > tf.Size() || r.End > tf.Size() {
+			return token.NoPos, token.NoPos, errors.New("offset out of range")
+		}
+		start, end := tf.Pos(r.Start), token.NoPos
+		if r.End >= 0 {
+			end = tf.Pos(r.End)
+		}
+		return start, end, nil
+	}
+
+	var findings []finding
+	for _, cf := range e.Findings {
+		d := analysis.Diagnostic{Category: cf.Category, Message: cf.Message, URL: cf.URL}
+		if d.Pos, d.End, err = pos(cf.cachedRange); err != nil {
+			return nil, err
+		}
+		for _, cfix := range cf.Fixes {
+			fix := analysis.SuggestedFix{Message: cfix.Message}
+			for _, ce := range cfix.Edits {
+				te := analysis.TextEdit{NewText: []byte(ce.NewText)}
+				if te.Pos, te.End, err = pos(ce.cachedRange); err != nil {
+					return nil, err
+				}
+				fix.TextEdits = append(fix.TextEdits, te)
+			}
+			d.SuggestedFixes = append(d.SuggestedFixes, fix)
+		}
+		for _, cr := range cf.Related {
+			ri := analysis.RelatedInformation{Message: cr.Message}
+			if ri.Pos, ri.End, err = pos(cr.cachedRange); err != nil {
+				return nil, err
+			}
+			d.Related = append(d.Related, ri)
+		}
+		findings = append(findings, newFinding(d, fset, cf.Generated, path, wd))
+	}
+	return findings, nil
+}
+
+// write stores findings in the cache entry, replacing it atomically.
+func (c *cache) write(entry string, findings []finding) error {
+	e := cacheEntry{Findings: []cachedFinding{}}
+	for _, f := range findings {
+		rng := func(start, end token.Pos) cachedRange {
+			tf := f.Fset.File(start)
+			r := cachedRange{File: tf.Name(), Start: tf.Offset(start), End: -1}
+			if end.IsValid() {
+				r.End = tf.Offset(end)
+			}
+			return r
+		}
+		cf := cachedFinding{
+			cachedRange: rng(f.Pos, f.Diagnostic.End),
+			Category:    f.Category,
+			Message:     f.Message,
+			URL:         f.URL,
+			Generated:   f.Generated,
+		}
+		for _, fix := range f.SuggestedFixes {
+			cfix := cachedFix{Message: fix.Message}
+			for _, te := range fix.TextEdits {
+				cfix.Edits = append(cfix.Edits, cachedEdit{cachedRange: rng(te.Pos, te.End), NewText: string(te.NewText)})
+			}
+			cf.Fixes = append(cf.Fixes, cfix)
+		}
+		for _, ri := range f.Related {
+			cf.Related = append(cf.Related, cachedRelated{cachedRange: rng(ri.Pos, ri.End), Message: ri.Message})
+		}
+		e.Findings = append(e.Findings, cf)
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.dir, entry+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(c.dir, entry+".json"))
+}
+
+// cleanCacheCmd removes the entries of the cache and returns the exit code.
+func cleanCacheCmd(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("errlint clean-cache", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: errlint clean-cache [-cache-dir dir]\n\nRemoves the cached findings.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	dir := flags.String("cache-dir", defaultCacheDir(), "cache `dir`ectory")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 || *dir == "" || *dir == "off" {
+		flags.Usage()
+		return 2
+	}
+	n, err := cleanCache(*dir)
+	if err != nil {
+		fmt.Fprintf(stderr, "errlint: %v\n", err)
+		return 2
+	}
+	fmt.Fprintf(stdout, "removed %d cache entries from %s\n", n, *dir)
+	return 0
+}
+
+// cleanCache removes the entries of the cache in dir, and dir itself if
+// nothing else is left in it, and returns the number of entries removed.
+// Other files are left alone, in case dir was given by mistake.
+func cleanCache(dir string) (int, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, de := range dirEntries {
+		if de.Type().IsRegular() && cacheEntryName.MatchString(de.Name()) {
+			if err := os.Remove(filepath.Join(dir, de.Name())); err != nil {
+				return removed, err
+			}
+			removed++
+		}
+	}
+	if removed == len(dirEntries) {
+		if err := os.Remove(dir); err != nil {
+			return removed, err
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/xml"
+	"io"
+	"path/filepath"
+)
+
+// The types below model the checkstyle XML format, which tools such as
+// reviewdog and the Jenkins warnings plugin read.
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// writeCheckstyle writes findings as a checkstyle XML report with an entry
+// per file. The source of each error is errlint. followed by its check,
+// such as errlint.comparison.
+func writeCheckstyle(w io.Writer, findings []finding) error {
+	report := checkstyleReport{Version: "4.3"}
+	files := make(map[string]int)
+	for _, f := range findings {
+		name := filepath.ToSlash(f.Position.Filename)
+		i, ok := files[name]
+		if !ok {
+			i = len(report.Files)
+			files[name] = i
+			report.Files = append(report.Files, checkstyleFile{Name: name})
+		}
+		report.Files[i].Errors = append(report.Files[i].Errors, checkstyleError{
+			Line:     f.Position.Line,
+			Column:   f.Position.Column,
+			Severity: f.Severity,
+			Message:  f.Message,
+			Source:   "errlint." + f.Category,
+		})
+	}
+	return writeXML(w, report)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"maps"
+	"strings"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer"
+	"github.com/kakkoyun/demo-error-lint/config"
+	"github.com/kakkoyun/demo-error-lint/internal/lint"
+)
+
+// loadConfig loads the configuration file at name, or the one found from the
+// working directory if name is empty. Without a file it returns an empty
+// configuration rooted at the working directory.
+func loadConfig(name string) (*config.Config, error) {
+	if name == "" {
+		found, err := config.Find(".")
+		if errors.Is(err, fs.ErrNotExist) {
+			return &config.Config{Dir: workDir()}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		name = found
+	}
+	return config.Load(name)
+}
+
+// applyConfig sets the analyzer flags that were not given on the command
+// line from cfg, so that flags override the configuration file.
+func applyConfig(cfg *config.Config, flags *flag.FlagSet) error {
+	set := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	values := map[string][]string{
+		"checks":      cfg.Checks,
+		"enable":      cfg.Enable,
+		"allow":       cfg.Allow,
+		"passthrough": cfg.Passthrough,
+		"loggers":     cfg.Loggers,
+	}
+	for name, value := range values {
+		if set[name] || len(value) == 0 {
+			continue
+		}
+		if err := analyzer.Analyzer.Flags.Set(name, strings.Join(value, ",")); err != nil {
+			return err
+		}
+	}
+	if !set["wrap-prefix"] && cfg.WrapPrefix != "" {
+		if err := analyzer.Analyzer.Flags.Set("wrap-prefix", cfg.WrapPrefix); err != nil {
+			return err
+		}
+	}
+	if !set["custom"] && len(cfg.Custom) > 0 {
+		data, err := json.Marshal(cfg.Custom)
+		if err != nil {
+			return err
+		}
+		if err := analyzer.Analyzer.Flags.Set("custom", string(data)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// allChecks returns the checks of errlint followed by the custom checks
+// of the analyzer.
+func allChecks() []analyzer.Check {
+	checks := analyzer.Checks()
+	for _, c := range analyzer.Analyzer.Flags.Lookup("custom").Value.(flag.Getter).Get().([]analyzer.CustomCheck) {
+		checks = append(checks, c.Check())
+	}
+	return checks
+}
+
+// applySeverities overrides the severities and the fail-on level of cfg with
+// the values of the -severity and -fail-on flags, if set.
+func applySeverities(cfg *config.Config, severities, failOn string) error {
+	if severities != "" {
+		cfg.Severity = maps.Clone(cfg.Severity)
+		if cfg.Severity == nil {
+			cfg.Severity = make(map[string]string)
+		}
+		for pair := range strings.SplitSeq(severities, ",") {
+			check, severity, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				return fmt.Errorf("-severity: %q is not check=severity", pair)
+			}
+			cfg.Severity[check] = severity
+		}
+	}
+	if failOn != "" {
+		cfg.FailOn = failOn
+	}
+	return cfg.Validate()
+}
+
+// applySkips overrides the generated setting and the skip-dirs globs of cfg
+// with the values of the -skip-generated and -skip-dirs flags, if set.
+func applySkips(cfg *config.Config, flags *flag.FlagSet, skipGenerated bool, skipDirs string) error {
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "skip-generated":
+			cfg.Generated = !skipGenerated
+		case "skip-dirs":
+			cfg.SkipDirs = nil
+			for glob := range strings.SplitSeq(skipDirs, ",") {
+				if glob = strings.TrimSpace(glob); glob != "" {
+					cfg.SkipDirs = append(cfg.SkipDirs, glob)
+				}
+			}
+		}
+	})
+	return cfg.Validate()
+}
+
+// filterFindings drops the findings cfg excludes or turns off, and sets
+// the severity of the others.
+func filterFindings(cfg *config.Config, findings []finding) []finding {
+	var out []finding
+	for _, f := range findings {
+		severity, ok := lint.Severity(cfg, f.Category, f.Position.Filename, f.Generated)
+		if !ok {
+			continue
+		}
+		f.Severity = severity
+		out = append(out, f)
+	}
+	return out
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/csv"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kakkoyun/demo-error-lint/config"
+)
+
+// csvHeader names the columns of the CSV format.
+var csvHeader = []string{"file", "line", "column", "severity", "id", "check", "message", "owners"}
+
+// writeCSV writes findings as CSV, with a header row and a row per
+// finding, for spreadsheets and scripts that split the cleanup of many
+// findings between teams. The owners column lists the owners of the file
+// of each finding, separated by spaces, from the CODEOWNERS file.
+func writeCSV(w io.Writer, findings []finding) error {
+	checks := checksByName()
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, f := range findings {
+		record := []string{
+			filepath.ToSlash(f.Position.Filename),
+			strconv.Itoa(f.Position.Line),
+			strconv.Itoa(f.Position.Column),
+			f.Severity,
+			checks[f.Category].ID,
+			f.Category,
+			f.Message,
+			strings.Join(f.Owners, " "),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// setOwners sets the owners of findings from the CODEOWNERS file: the
+// -owners flag if set, then the one of ownersPath. Without a file, the
+// findings have no owners.
+func setOwners(cfg *config.Config, flagValue string, findings []finding) error {
+	name := flagValue
+	if name == "" {
+		name = ownersPath(cfg)
+	}
+	if name == "" {
+		return nil
+	}
+	owners, err := config.LoadOwners(name)
+	if err != nil {
+		return err
+	}
+	for i := range findings {
+		findings[i].Owners = owners.Of(findings[i].Position.Filename)
+	}
+	return nil
+}
+
+// ownersPath returns the CODEOWNERS file of cfg: its owners file if set,
+// then the first of config.OwnersNames that exists next to it, or "".
+func ownersPath(cfg *config.Config) string {
+	if cfg.Owners != "" {
+		return filepath.Join(cfg.Dir, cfg.Owners)
+	}
+	return config.FindOwners(cfg.Dir)
+}
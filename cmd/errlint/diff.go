@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// changedLines maps file names to the lines a change adds or modifies in
+// the new version of the file.
+type changedLines map[string]map[int]bool
+
+// readDiff returns the lines changed by the diff of rev: the unified diff
+// read from stdin if rev is "-", or else the output of git diff against the
+// revision rev, which includes uncommitted changes, and every line of the
+// untracked files git does not ignore, which git diff leaves out. File
+// names in the diff are relative to the working directory.
+func readDiff(rev string, stdin io.Reader) (changedLines, error) {
+	dir := workDir()
+	if rev == "-" {
+		return parseDiff(stdin, dir)
+	}
+	// Set the prefixes parseDiff removes, whatever the diff.noprefix and
+	// diff.mnemonicPrefix settings of the user.
+	out, err := exec.Command("git", "diff", "--no-color", "--no-ext-diff", "--relative", "--src-prefix=a/", "--dst-prefix=b/", "-U0", rev, "--").Output()
+	if err != nil {
+		var exit *exec.ExitError
+		if errors.As(err, &exit) && len(exit.Stderr) > 0 {
+			return nil, fmt.Errorf("git diff %s: %s", rev, bytes.TrimSpace(exit.Stderr))
+		}
+		return nil, fmt.Errorf("git diff %s: %w", rev, err)
+	}
+	changed, err := parseDiff(bytes.NewReader(out), dir)
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := gitFiles("ls-files", "-z", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range untracked {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		lines := make(map[int]bool)
+		for line := range bytes.Count(data, []byte("\n")) + 1 {
+			lines[line+1] = true
+		}
+		changed[name] = lines
+	}
+	return changed, nil
+}
+
+// parseDiff returns the lines added by the unified diff read from r, with
+// the file names of the diff resolved relative to dir. The a/ and b/
+// prefixes of git diffs are removed.
+func parseDiff(r io.Reader, dir string) (changedLines, error) {
+	changed := make(changedLines)
+	var (
+		lines             map[int]bool // of the current file, nil if deleted
+		line              int          // the next line in the new file
+		oldLeft, newLeft  int          // lines of the current hunk left
+		lineNum, hunkLine int          // of the diff, for errors
+	)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		text := scanner.Text()
+		lineNum++
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(text, "+"):
+				if lines != nil {
+					lines[line] = true
+				}
+				line++
+				newLeft--
+			case strings.HasPrefix(text, "-"):
+				oldLeft--
+			case strings.HasPrefix(text, " "), text == "":
+				line++
+				oldLeft--
+				newLeft--
+			case strings.HasPrefix(text, `\`):
+				// \ No newline at end of file
+			default:
+				return nil, fmt.Errorf("diff line %d: hunk starting at line %d ends early", lineNum, hunkLine)
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "+++ "):
+			name, ok := diffName(strings.TrimPrefix(text, "+++ "))
+			if !ok {
+				lines = nil
+				continue
+			}
+			name = filepath.Join(dir, filepath.FromSlash(name))
+			if changed[name] == nil {
+				changed[name] = make(map[int]bool)
+			}
+			lines = changed[name]
+		case strings.HasPrefix(text, "@@ "):
+			var err error
+			line, oldLeft, newLeft, err = parseHunk(text)
+			if err != nil {
+				return nil, fmt.Errorf("diff line %d: %w", lineNum, err)
+			}
+			hunkLine = lineNum
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if oldLeft > 0 || newLeft > 0 {
+		return nil, fmt.Errorf("diff ends in the hunk starting at line %d", hunkLine)
+	}
+	return changed, nil
+}
+
+// diffName returns the file name of a "+++" line of a diff, or false for
+// /dev/null, which marks a deleted file.
+func diffName(s string) (string, bool) {
+	if strings.HasPrefix(s, `"`) {
+		if name, err := strconv.Unquote(s); err == nil {
+			s = name
+		}
+	} else if name, _, ok := strings.Cut(s, "\t"); ok {
+		// Diffs other than git's follow the name with a timestamp.
+		s = name
+	}
+	if s == "/dev/null" {
+		return "", false
+	}
+	return strings.TrimPrefix(s, "b/"), true
+}
+
+// parseHunk parses a hunk header such as "@@ -12,3 +12,4 @@ func f() {" and
+// returns the first line of the hunk in the new file and the number of
+// lines it covers in the old and new file.
+func parseHunk(s string) (line, oldCount, newCount int, err error) {
+	fields := strings.Fields(s)
+	if len(fields) < 4 || fields[3] != "@@" || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, 0, fmt.Errorf("malformed hunk header %q", s)
+	}
+	_, oldCount, err = parseRange(fields[1][1:])
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("malformed hunk header %q", s)
+	}
+	line, newCount, err = parseRange(fields[2][1:])
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("malformed hunk header %q", s)
+	}
+	return line, oldCount, newCount, nil
+}
+
+// parseRange parses the "start,count" range of a hunk header, where the
+// count defaults to 1.
+func parseRange(s string) (start, count int, err error) {
+	first, n, ok := strings.Cut(s, ",")
+	if start, err = strconv.Atoi(first); err != nil {
+		return 0, 0, err
+	}
+	count = 1
+	if ok {
+		if count, err = strconv.Atoi(n); err != nil {
+			return 0, 0, err
+		}
+	}
+	return start, count, nil
+}
+
+// filterDiff returns the findings on lines in changed. A finding spanning
+// several lines is kept if any of them changed.
+func filterDiff(changed changedLines, findings []finding) []finding {
+	var out []finding
+	for _, f := range findings {
+		lines := changed[f.Fset.File(f.Pos).Name()]
+		for line := f.Position.Line; line <= max(f.Position.Line, f.End.Line); line++ {
+			if lines[line] {
+				out = append(out, f)
+				break
+			}
+		}
+	}
+	return out
+}
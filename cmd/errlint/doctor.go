@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"go/token"
+	"go/types"
+	"io"
+	"io/fs"
+	"maps"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"gopkg.in/yaml.v3"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer"
+	"github.com/kakkoyun/demo-error-lint/config"
+)
+
+// effectiveConfig is the configuration errlint runs with, as errlint
+// doctor prints it: the checks that run, whether the configuration lists
+// them or not, and the lists of the checks with their defaults. It is a
+// valid configuration file.
+type effectiveConfig struct {
+	Checks      []string               `yaml:"checks,flow"`
+	Allow       []string               `yaml:"allow"`
+	Passthrough []string               `yaml:"passthrough"`
+	Loggers     []string               `yaml:"loggers"`
+	WrapPrefix  string                 `yaml:"wrap-prefix,omitempty"`
+	Custom      []analyzer.CustomCheck `yaml:"custom,omitempty"`
+	Exclude     []string               `yaml:"exclude,omitempty"`
+	SkipDirs    []string               `yaml:"skip-dirs,omitempty"`
+	Severity    map[string]string      `yaml:"severity"`
+	FailOn      string                 `yaml:"fail-on"`
+	Generated   bool                   `yaml:"generated"`
+	Explain     bool                   `yaml:"explain,omitempty"`
+	Baseline    string                 `yaml:"baseline,omitempty"`
+	Owners      string                 `yaml:"owners,omitempty"`
+}
+
+// doctor checks the configuration file and the files it refers to, prints
+// the problems it finds and the effective configuration, and returns the
+// exit code: 1 if there are errors, and 0 if there are only warnings.
+func doctor(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("errlint doctor", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: errlint doctor [-config file]\n\nChecks the configuration file, the sentinels it allows and the files it\nrefers to, warns about conflicting settings, and prints the effective\nconfiguration.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	configFile := flags.String("config", "", "configuration `file` (default: "+config.FileName+" in the working directory or its parents)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return 2
+	}
+
+	wd := workDir()
+	name := *configFile
+	if name == "" {
+		found, err := config.Find(".")
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(stderr, "errlint: %v\n", err)
+			return 2
+		}
+		name = found
+	}
+	cfg := &config.Config{Dir: wd}
+	if name == "" {
+		fmt.Fprintln(stdout, "config: none, using the defaults")
+	} else {
+		fmt.Fprintf(stdout, "config: %s\n", relative(wd, name))
+		loaded, err := config.Load(name)
+		if err != nil {
+			fmt.Fprintf(stdout, "error: %v\n", err)
+			fmt.Fprintln(stdout, "1 error")
+			return 1
+		}
+		cfg = loaded
+	}
+
+	var d diagnosis
+	// The flags of the analyzer hold the defaults until the configuration
+	// is applied to them.
+	defaultAllowed := strings.Split(analyzer.Analyzer.Flags.Lookup("allow").Value.String(), ",")
+	d.checkAllowed(cfg, defaultAllowed)
+	if _, err := regexp.Compile(cfg.WrapPrefix); err != nil {
+		d.errorf("wrap-prefix: %v", err)
+	}
+	if name := baselinePath(cfg, "", false); name != "" {
+		if _, err := readBaseline(name); err != nil {
+			d.errorf("baseline: %v", err)
+		}
+	}
+	if name := ownersPath(cfg); name != "" {
+		if _, err := config.LoadOwners(name); err != nil {
+			d.errorf("owners: %v", err)
+		}
+	}
+	var eff *effectiveConfig
+	if d.errors == 0 {
+		if err := applyConfig(cfg, flags); err != nil {
+			d.errorf("%v", err)
+		} else {
+			eff = effective(cfg)
+			d.checkConflicts(cfg, eff)
+		}
+	}
+
+	for _, p := range d.problems {
+		fmt.Fprintln(stdout, p)
+	}
+	switch {
+	case d.errors == 0 && len(d.problems) == 0:
+		fmt.Fprintln(stdout, "no problems found")
+	case d.errors == 0:
+		fmt.Fprintln(stdout, plural(len(d.problems), "warning"))
+	default:
+		fmt.Fprintf(stdout, "%s, %s\n", plural(d.errors, "error"), plural(len(d.problems)-d.errors, "warning"))
+	}
+	if d.errors > 0 {
+		return 1
+	}
+
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(eff); err != nil {
+		fmt.Fprintf(stderr, "errlint: %v\n", err)
+		return 2
+	}
+	fmt.Fprintf(stdout, "\n# Effective configuration\n%s", b.Bytes())
+	return 0
+}
+
+// diagnosis collects the problems errlint doctor finds.
+type diagnosis struct {
+	problems []string
+	errors   int
+}
+
+func (d *diagnosis) errorf(format string, args ...any) {
+	d.problems = append(d.problems, "error: "+fmt.Sprintf(format, args...))
+	d.errors++
+}
+
+func (d *diagnosis) warnf(format string, args ...any) {
+	d.problems = append(d.problems, "warning: "+fmt.Sprintf(format, args...))
+}
+
+// checkAllowed reports the allowed sentinels of cfg that are malformed,
+// already allowed by default, or do not name an exported error variable
+// of a package the module of cfg can load.
+func (d *diagnosis) checkAllowed(cfg *config.Config, defaults []string) {
+	sentinels := make(map[string][]string)
+	var paths []string
+	for _, name := range cfg.Allow {
+		slash := strings.LastIndexByte(name, '/')
+		dot := strings.LastIndexByte(name, '.')
+		if dot <= slash+1 || dot == len(name)-1 {
+			d.errorf("allow: invalid sentinel %q: want pkg/path.Name", name)
+			continue
+		}
+		if slices.Contains(defaults, name) {
+			d.warnf("allow: %s is allowed by default", name)
+			continue
+		}
+		path := name[:dot]
+		if _, ok := sentinels[path]; !ok {
+			paths = append(paths, path)
+		}
+		sentinels[path] = append(sentinels[path], name[dot+1:])
+	}
+	if len(paths) == 0 {
+		return
+	}
+
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName | packages.NeedTypes, Dir: cfg.Dir}, paths...)
+	if err != nil {
+		d.errorf("allow: %v", err)
+		return
+	}
+	loaded := make(map[string]*packages.Package)
+	for _, pkg := range pkgs {
+		loaded[pkg.PkgPath] = pkg
+	}
+	errorType := types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+	for _, path := range paths {
+		pkg := loaded[path]
+		for _, name := range sentinels[path] {
+			switch {
+			case pkg == nil || pkg.Types == nil:
+				d.errorf("allow: %s.%s: package %s cannot be loaded", path, name, path)
+			case len(pkg.Errors) > 0 && pkg.Types.Scope().Len() == 0:
+				d.errorf("allow: %s.%s: %s", path, name, pkg.Errors[0].Msg)
+			default:
+				d.checkSentinel(pkg.Types, name, errorType)
+			}
+		}
+	}
+}
+
+// checkSentinel reports name if it is not an exported error variable of
+// pkg.
+func (d *diagnosis) checkSentinel(pkg *types.Package, name string, errorType *types.Interface) {
+	// The export data of pkg only holds its exported objects.
+	if !token.IsExported(name) {
+		d.errorf("allow: %s.%s is not exported, so other packages cannot compare with it", pkg.Path(), name)
+		return
+	}
+	obj := pkg.Scope().Lookup(name)
+	v, ok := obj.(*types.Var)
+	switch {
+	case obj == nil:
+		d.errorf("allow: %s.%s: package %s has no %s", pkg.Path(), name, pkg.Path(), name)
+	case !ok:
+		d.errorf("allow: %s.%s is not a variable", pkg.Path(), name)
+	case !types.Implements(v.Type(), errorType):
+		d.errorf("allow: %s.%s has type %s, which is not an error", pkg.Path(), name, types.TypeString(v.Type(), types.RelativeTo(pkg)))
+	}
+}
+
+// checkConflicts warns about settings of cfg that have no effect, or
+// undo each other, once eff runs.
+func (d *diagnosis) checkConflicts(cfg *config.Config, eff *effectiveConfig) {
+	listed := make(map[string]bool)
+	optIn := make(map[string]bool)
+	for _, c := range analyzer.Checks() {
+		optIn[c.Name] = c.OptIn
+	}
+	for _, name := range cfg.Checks {
+		listed[name] = true
+	}
+	for _, name := range cfg.Enable {
+		if listed[name] || len(cfg.Checks) == 0 && !optIn[name] {
+			d.warnf("enable: %s already runs without it", name)
+		}
+		listed[name] = true
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg.Severity)) {
+		switch {
+		case !slices.Contains(eff.Checks, name):
+			d.warnf("severity: %s has a severity, but the check does not run", name)
+		case listed[name] && cfg.Severity[name] == "off":
+			d.warnf("severity: %s is off, so the check runs but its findings are dropped", name)
+		}
+	}
+	for _, s := range []struct {
+		set   bool
+		key   string
+		check string
+	}{
+		{len(cfg.Passthrough) > 0, "passthrough", "wrapcheck"},
+		{len(cfg.Loggers) > 0, "loggers", "swallow"},
+		{cfg.WrapPrefix != "", "wrap-prefix", "wrapmsg"},
+	} {
+		if s.set && !slices.Contains(eff.Checks, s.check) {
+			d.warnf("%s: the %s check that uses it does not run; add it to enable", s.key, s.check)
+		}
+	}
+	fails := false
+	for _, name := range eff.Checks {
+		fails = fails || cfg.Fails(eff.Severity[name])
+	}
+	if !fails {
+		d.warnf("fail-on: no check that runs has a severity of %s or more, so errlint never fails", eff.FailOn)
+	}
+}
+
+// effective returns the configuration errlint runs with, from cfg applied
+// to the flags of the analyzer.
+func effective(cfg *config.Config) *effectiveConfig {
+	lookup := func(name string) []string {
+		value := analyzer.Analyzer.Flags.Lookup(name).Value.String()
+		if value == "" {
+			return nil
+		}
+		return strings.Split(value, ",")
+	}
+	running := append(lookup("checks"), lookup("enable")...)
+	eff := &effectiveConfig{
+		Allow:       lookup("allow"),
+		Passthrough: lookup("passthrough"),
+		Loggers:     lookup("loggers"),
+		WrapPrefix:  cfg.WrapPrefix,
+		Custom:      cfg.Custom,
+		Exclude:     cfg.Exclude,
+		SkipDirs:    cfg.SkipDirs,
+		Severity:    make(map[string]string),
+		FailOn:      cmp.Or(cfg.FailOn, config.DefaultFailOn),
+		Generated:   cfg.Generated,
+		Explain:     cfg.Explain,
+		Baseline:    filepath.ToSlash(relative(cfg.Dir, baselinePath(cfg, "", false))),
+		Owners:      filepath.ToSlash(relative(cfg.Dir, ownersPath(cfg))),
+	}
+	for _, c := range cfg.Custom {
+		running = append(running, c.Name)
+	}
+	for _, c := range allChecks() {
+		if slices.Contains(running, c.Name) {
+			eff.Checks = append(eff.Checks, c.Name)
+			eff.Severity[c.Name] = cfg.SeverityOf(c.Name)
+		}
+	}
+	return eff
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer"
+)
+
+// explain prints the documentation of the checks named in args by ID or
+// name, or lists every check if args is empty, and returns the exit code.
+func explain(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		tw := tabwriter.NewWriter(stdout, 0, 8, 1, ' ', 0)
+		for _, c := range analyzer.Checks() {
+			doc := c.Doc
+			if c.OptIn {
+				doc += " (opt-in)"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", c.ID, c.Name, doc)
+		}
+		tw.Flush()
+		return 0
+	}
+	for i, arg := range args {
+		c, ok := findCheck(arg)
+		if !ok {
+			fmt.Fprintf(stderr, "errlint: unknown check %q; run errlint explain for the list\n", arg)
+			return 2
+		}
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		fmt.Fprintf(stdout, "%s %s: %s\n\n%s\n", c.ID, c.Name, c.Doc, c.Rationale)
+		fmt.Fprintf(stdout, "\nBad:\n%s\nGood:\n%s", indent(c.Bad), indent(c.Good))
+		fmt.Fprintf(stdout, "\nSee:\n%s", indent(strings.Join(c.Links, "\n")))
+	}
+	return 0
+}
+
+// findCheck returns the check with the given ID, in any case, or name.
+func findCheck(idOrName string) (analyzer.Check, bool) {
+	for _, c := range analyzer.Checks() {
+		if strings.EqualFold(c.ID, idOrName) || c.Name == idOrName {
+			return c, true
+		}
+	}
+	return analyzer.Check{}, false
+}
+
+func indent(s string) string {
+	var b strings.Builder
+	for line := range strings.Lines(s) {
+		if line != "\n" {
+			b.WriteString("    ")
+		}
+		b.WriteString(line)
+	}
+	if !strings.HasSuffix(s, "\n") {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"go/format"
+	"os"
+	"slices"
+)
+
+// edit replaces the bytes [start, end) of a file with text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// applyFixes applies the first suggested fix of each finding and returns
+// the findings that were left unfixed and the number of fixes applied to
+// each file. A fix is skipped as a whole if any of its edits overlaps an
+// edit of a fix applied before it; running errlint -fix again picks it up.
+// Fixed files are reformatted with gofmt.
+func applyFixes(findings []finding) ([]finding, map[string]int, error) {
+	accepted := make(map[string][]edit)
+	fixed := make(map[string]int)
+	var unfixed []finding
+	for _, f := range findings {
+		if len(f.SuggestedFixes) == 0 {
+			unfixed = append(unfixed, f)
+			continue
+		}
+
+		pending := make(map[string][]edit)
+		for _, te := range f.SuggestedFixes[0].TextEdits {
+			file := f.Fset.File(te.Pos)
+			end := te.End
+			if !end.IsValid() {
+				end = te.Pos
+			}
+			name := file.Name()
+			pending[name] = append(pending[name], edit{
+				start: file.Offset(te.Pos),
+				end:   file.Offset(end),
+				text:  string(te.NewText),
+			})
+		}
+
+		merged := make(map[string][]edit, len(pending))
+		ok := true
+		for name, edits := range pending {
+			merged[name], ok = merge(accepted[name], edits)
+			if !ok {
+				break
+			}
+		}
+		if !ok {
+			unfixed = append(unfixed, f)
+			continue
+		}
+		for name, edits := range merged {
+			accepted[name] = edits
+		}
+		fixed[f.Fset.File(f.Pos).Name()]++
+	}
+
+	for name, edits := range accepted {
+		if err := rewrite(name, edits); err != nil {
+			return nil, nil, err
+		}
+	}
+	return unfixed, fixed, nil
+}
+
+// merge adds the edits of one fix to the sorted list base. Edits identical
+// to one already in base are dropped. It reports false if any other edit
+// overlaps or touches an edit in base. Edits of the same fix starting at the
+// same offset keep their relative order.
+func merge(base, edits []edit) ([]edit, bool) {
+	out := slices.Clone(base)
+	for _, e := range edits {
+		if slices.Contains(base, e) {
+			continue
+		}
+		for _, o := range base {
+			if e.start < o.end && o.start < e.end || e.start == o.start {
+				return nil, false
+			}
+		}
+		out = append(out, e)
+	}
+	slices.SortStableFunc(out, func(a, b edit) int { return cmp.Compare(a.start, b.start) })
+	return out, true
+}
+
+// rewrite applies the sorted edits to the named file and formats it.
+func rewrite(name string, edits []edit) error {
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+
+	var out []byte
+	last := 0
+	for _, e := range edits {
+		if e.end > len(content) {
+			return fmt.Errorf("%s: edit out of range; file changed during analysis", name)
+		}
+		out = append(out, content[last:e.start]...)
+		out = append(out, e.text...)
+		last = e.end
+	}
+	out = append(out, content[last:]...)
+
+	if formatted, err := format.Source(out); err == nil {
+		out = formatted
+	}
+	return os.WriteFile(name, out, 0o644)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// genFixtures writes the fixtures generated from the patterns to the
+// directory in args, and returns the exit code.
+func genFixtures(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("errlint gen-fixtures", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: errlint gen-fixtures [-seed seed] dir\n\nFlags:")
+		flags.PrintDefaults()
+	}
+	seed := flags.Uint64("seed", 1, "`seed` of the names in the fixtures")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	dir := flags.Arg(0)
+
+	files, err := fixtures(*seed)
+	if err != nil {
+		fmt.Fprintf(stderr, "errlint: %v\n", err)
+		return 2
+	}
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			fmt.Fprintf(stderr, "errlint: %v\n", err)
+			return 2
+		}
+		if err := os.WriteFile(file, files[name], 0o644); err != nil {
+			fmt.Fprintf(stderr, "errlint: %v\n", err)
+			return 2
+		}
+	}
+	fmt.Fprintf(stdout, "%d fixtures of %d patterns written to %s\n", len(files)-1, len(patterns), dir)
+	return 0
+}
+
+// fixtures generates a module of fixtures with the given seed, a package
+// for each pattern, and returns its files by their slash-separated names.
+// The package of an anti-pattern has bad.go, with the buggy form and // want
+// comments on the lines the check reports, and good.go with its fix. The
+// package of a special case the checks allow has allowed.go alone.
+func fixtures(seed uint64) (map[string][]byte, error) {
+	files := map[string][]byte{"go.mod": []byte("module fixtures\n\ngo 1.25\n")}
+	r := rand.New(rand.NewPCG(seed, 0))
+	for _, p := range patterns {
+		c, _ := findCheck(p.check)
+		picked := r.Perm(len(nouns))
+		if p.bad == "" {
+			doc := fmt.Sprintf("Package %s is a fixture of code that the %s check, %s, allows, generated by errlint gen-fixtures -seed=%d. errlint reports nothing in it.", p.name, c.Name, c.ID, seed)
+			src, err := generate(doc, p.name, []part{{p, nouns[picked[0]], false}}, true)
+			if err != nil {
+				return nil, err
+			}
+			files[p.name+"/allowed.go"] = src
+			continue
+		}
+
+		doc := fmt.Sprintf("Package %s is a fixture of the %s check, %s, generated by errlint gen-fixtures -seed=%d. The check reports the lines of bad.go that end in a want comment, or follow one, and nothing in good.go, which fixes them.", p.name, c.Name, c.ID, seed)
+		if c.OptIn {
+			doc += fmt.Sprintf(" The check is opt-in, so run errlint -enable=%s.", c.Name)
+		}
+		bad, err := generate(doc, p.name, []part{{p, nouns[picked[0]], true}}, true)
+		if err != nil {
+			return nil, err
+		}
+		good, err := generate("", p.name, []part{{p, nouns[picked[1]], false}}, true)
+		if err != nil {
+			return nil, err
+		}
+		files[p.name+"/bad.go"], files[p.name+"/good.go"] = bad, good
+	}
+	return files, nil
+}
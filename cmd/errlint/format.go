@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+)
+
+// formats maps the names accepted by -format to the functions that write
+// findings in that format.
+var formats = map[string]func(w io.Writer, findings []finding) error{
+	"text":       writeText,
+	"json":       writeJSON,
+	"sarif":      writeSARIF,
+	"junit":      writeJUnit,
+	"checkstyle": writeCheckstyle,
+	"csv":        writeCSV,
+}
+
+func formatNames() []string {
+	var names []string
+	for name := range formats {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// writeText prints one finding per line as file:line:col: severity: message.
+func writeText(w io.Writer, findings []finding) error {
+	for _, f := range findings {
+		if _, err := fmt.Fprintf(w, "%s: %s: %s\n", f.Position, f.Severity, f.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeExplained prints the findings like writeText, with why the check
+// reports the code and an example of its fix under the first finding of
+// each check.
+func writeExplained(w io.Writer, findings []finding) error {
+	checks := checksByName()
+	explained := make(map[string]bool)
+	for _, f := range findings {
+		if _, err := fmt.Fprintf(w, "%s: %s: %s\n", f.Position, f.Severity, f.Message); err != nil {
+			return err
+		}
+		c := checks[f.Category]
+		if explained[f.Category] || c.Rationale == "" {
+			continue
+		}
+		explained[f.Category] = true
+		if _, err := fmt.Fprintf(w, "%s\n    Instead:\n%s\n", indent(c.Rationale), indent(indent(c.Good))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
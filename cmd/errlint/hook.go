@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// hookMarker is the line that identifies the pre-commit hooks written by
+// errlint install-hook, which it replaces without -force.
+const hookMarker = "# Written by errlint install-hook."
+
+// installHook writes the git pre-commit hook of the repository in the
+// working directory, which runs errlint -staged with the flags in args
+// after --, and returns the exit code.
+func installHook(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("errlint install-hook", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: errlint install-hook [-force] [-- flags]\n\nWrites a git pre-commit hook that runs errlint -staged with the flags after --.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	force := flags.Bool("force", false, "replace a pre-commit hook not written by errlint")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	// Parse stops at -- or at the first argument that is not a flag.
+	if rest := flags.Args(); len(rest) > 0 && (len(args) == len(rest) || args[len(args)-len(rest)-1] != "--") {
+		fmt.Fprintf(stderr, "errlint: install-hook: unexpected argument %q; the hook analyzes the staged files, pass its flags after --\n", rest[0])
+		return 2
+	}
+
+	out, err := git("rev-parse", "--git-path", "hooks/pre-commit")
+	if err != nil {
+		fmt.Fprintf(stderr, "errlint: install-hook: %v\n", err)
+		return 2
+	}
+	name := string(bytes.TrimSpace(out))
+	existing, err := os.ReadFile(name)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		fmt.Fprintf(stderr, "errlint: install-hook: %v\n", err)
+		return 2
+	case !bytes.Contains(existing, []byte(hookMarker)) && !*force:
+		fmt.Fprintf(stderr, "errlint: install-hook: %s already exists; use -force to replace it\n", name)
+		return 2
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		fmt.Fprintf(stderr, "errlint: install-hook: %v\n", err)
+		return 2
+	}
+	if err := os.WriteFile(name, hookScript(flags.Args()), 0o755); err != nil {
+		fmt.Fprintf(stderr, "errlint: install-hook: %v\n", err)
+		return 2
+	}
+	// WriteFile keeps the mode of an existing file.
+	if err := os.Chmod(name, 0o755); err != nil {
+		fmt.Fprintf(stderr, "errlint: install-hook: %v\n", err)
+		return 2
+	}
+	fmt.Fprintf(stdout, "errlint: installed the pre-commit hook in %s\n", name)
+	return 0
+}
+
+// hookScript returns the pre-commit hook that runs errlint -staged with
+// the flags.
+func hookScript(flags []string) []byte {
+	var b bytes.Buffer
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString(hookMarker + "\n")
+	b.WriteString("# It fails the commit if the staged Go files have findings, as they are\n")
+	b.WriteString("# staged. Skip it with git commit --no-verify.\n")
+	b.WriteString("exec errlint -staged")
+	for _, f := range flags {
+		b.WriteString(" " + shellQuote(f))
+	}
+	b.WriteString("\n")
+	return b.Bytes()
+}
+
+// shellSafe matches the words the shell does not need quoted.
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_./=,:+@%-]+$`)
+
+// shellQuote quotes s for the shell, if needed.
+func shellQuote(s string) string {
+	if shellSafe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
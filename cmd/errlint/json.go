@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"go/token"
+	"io"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer"
+)
+
+// jsonFinding is the JSON representation of a finding.
+type jsonFinding struct {
+	RuleID          string    `json:"ruleId"`
+	Rule            string    `json:"rule"`
+	RuleDescription string    `json:"ruleDescription"`
+	Severity        string    `json:"severity"`
+	File            string    `json:"file"`
+	Range           jsonRange `json:"range"`
+	Message         string    `json:"message"`
+	Fixes           []jsonFix `json:"fixes,omitempty"`
+}
+
+type jsonRange struct {
+	Start jsonPosition `json:"start"`
+	End   jsonPosition `json:"end"`
+}
+
+type jsonPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Offset int `json:"offset"`
+}
+
+type jsonFix struct {
+	Message string     `json:"message"`
+	Edits   []jsonEdit `json:"edits"`
+}
+
+type jsonEdit struct {
+	File    string    `json:"file"`
+	Range   jsonRange `json:"range"`
+	NewText string    `json:"newText"`
+}
+
+// writeJSON writes one JSON object per line for each finding.
+func writeJSON(w io.Writer, findings []finding) error {
+	checks := checksByName()
+	enc := json.NewEncoder(w)
+	for _, f := range findings {
+		if err := enc.Encode(newJSONFinding(checks, f)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checksByName returns the checks by name.
+func checksByName() map[string]analyzer.Check {
+	checks := make(map[string]analyzer.Check)
+	for _, c := range allChecks() {
+		checks[c.Name] = c
+	}
+	return checks
+}
+
+// newJSONFinding returns the JSON representation of f, whose check is in
+// checks.
+func newJSONFinding(checks map[string]analyzer.Check, f finding) jsonFinding {
+	jf := jsonFinding{
+		RuleID:          checks[f.Category].ID,
+		Rule:            f.Category,
+		RuleDescription: checks[f.Category].Doc,
+		Severity:        f.Severity,
+		File:            f.Position.Filename,
+		Range:           newJSONRange(f.Position, f.End),
+		Message:         f.Message,
+	}
+	for _, fix := range f.SuggestedFixes {
+		jfix := jsonFix{Message: fix.Message}
+		for _, te := range fix.TextEdits {
+			start := f.Fset.Position(te.Pos)
+			end := start
+			if te.End.IsValid() {
+				end = f.Fset.Position(te.End)
+			}
+			jfix.Edits = append(jfix.Edits, jsonEdit{
+				File:    relative(workDir(), start.Filename),
+				Range:   newJSONRange(start, end),
+				NewText: string(te.NewText),
+			})
+		}
+		jf.Fixes = append(jf.Fixes, jfix)
+	}
+	return jf
+}
+
+func newJSONRange(start, end token.Position) jsonRange {
+	return jsonRange{
+		Start: jsonPosition{Line: start.Line, Column: start.Column, Offset: start.Offset},
+		End:   jsonPosition{Line: end.Line, Column: end.Column, Offset: end.Offset},
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// The types below model the JUnit XML report format as Jenkins and GitLab
+// read it. See https://github.com/testmoapp/junitxml.
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string       `xml:"name,attr"`
+	Classname string       `xml:"classname,attr"`
+	File      string       `xml:"file,attr"`
+	Line      int          `xml:"line,attr"`
+	Failure   junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+// writeJUnit writes findings as a JUnit XML report with a test suite per
+// package and a failed test case per finding, named after its check and
+// position, so that CI systems list them with the results of the tests.
+// Findings of every severity are failures, since JUnit has no warnings.
+func writeJUnit(w io.Writer, findings []finding) error {
+	ids := make(map[string]string)
+	for _, c := range allChecks() {
+		ids[c.Name] = c.ID
+	}
+
+	report := junitTestSuites{Name: "errlint", Tests: len(findings), Failures: len(findings)}
+	suites := make(map[string]int)
+	for _, f := range findings {
+		i, ok := suites[f.Package]
+		if !ok {
+			i = len(report.Suites)
+			suites[f.Package] = i
+			report.Suites = append(report.Suites, junitTestSuite{Name: f.Package})
+		}
+		s := &report.Suites[i]
+		s.Tests++
+		s.Failures++
+		s.Cases = append(s.Cases, junitTestCase{
+			Name:      fmt.Sprintf("%s %s", ids[f.Category], f.Position),
+			Classname: f.Package,
+			File:      f.Position.Filename,
+			Line:      f.Position.Line,
+			Failure: junitFailure{
+				Message: f.Message,
+				Type:    f.Severity,
+				Text:    fmt.Sprintf("%s: %s: %s\n\nRun errlint explain %s for details.", f.Position, f.Severity, f.Message, ids[f.Category]),
+			},
+		})
+	}
+	return writeXML(w, report)
+}
+
+// writeXML writes v as an indented XML document.
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,588 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer"
+	"github.com/kakkoyun/demo-error-lint/config"
+)
+
+// lspDebounce is how long errlint lsp waits after a document changes for
+// further changes, such as the next keystrokes, before it analyzes the
+// open documents again.
+const lspDebounce = 500 * time.Millisecond
+
+// JSON-RPC error codes of the responses of errlint lsp.
+const (
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+)
+
+// lsp runs a language server on stdin and stdout that publishes the
+// findings of the open documents as diagnostics and their suggested fixes
+// as code actions, until the client sends exit, and returns the exit code.
+func lsp(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("errlint lsp", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: errlint lsp [flags]\n\nRuns a language server on stdin and stdout that reports the findings of open documents.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	configFile := flags.String("config", "", "configuration `file` (default: "+config.FileName+" in the working directory or its parents)")
+	tests := flags.Bool("tests", true, "also analyze test files")
+	analyzer.Analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		flags.Var(f.Value, f.Name, f.Usage)
+	})
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintln(stderr, "errlint: lsp analyzes the packages of the open documents; do not list packages")
+		return 2
+	}
+
+	cfg, err := loadConfig(*configFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "errlint: %v\n", err)
+		return 2
+	}
+	if err := applyConfig(cfg, flags); err != nil {
+		fmt.Fprintf(stderr, "errlint: %s: %v\n", config.FileName, err)
+		return 2
+	}
+
+	s := &lspServer{
+		tests:  *tests,
+		cfg:    cfg,
+		stdout: stdout,
+		stderr: stderr,
+		docs:   make(map[string]*lspDocument),
+	}
+	return s.serve(stdin)
+}
+
+// lspServer is the state of errlint lsp.
+type lspServer struct {
+	tests          bool
+	cfg            *config.Config
+	stdout, stderr io.Writer
+
+	// docs holds the open documents by URI.
+	docs map[string]*lspDocument
+	// timer fires when the documents changed and the changes settled.
+	timer *time.Timer
+	// shutdown is set once the client requested shutdown.
+	shutdown bool
+}
+
+// lspDocument is a document open in the client.
+type lspDocument struct {
+	uri  string
+	path string
+	text []byte
+	// dirty is set while text has changed since it was analyzed, or the
+	// analysis failed; its code actions are not offered then, since their
+	// edits would apply to another text.
+	dirty bool
+	// diagnostics are the findings of the last analysis of the document.
+	diagnostics []lspDiagnostic
+	// actions are the suggested fixes of the diagnostics.
+	actions []lspCodeAction
+}
+
+// serve reads the messages of the client from r and handles them one by
+// one. Analyses run between messages, so the diagnostics published after a
+// change always reflect the messages before it.
+func (s *lspServer) serve(r io.Reader) int {
+	type read struct {
+		msg *rpcMessage
+		err error
+	}
+	msgs := make(chan read)
+	go func() {
+		br := bufio.NewReader(r)
+		for {
+			msg, err := readMessage(br)
+			msgs <- read{msg, err}
+			if err != nil && !errors.As(err, new(*json.SyntaxError)) {
+				return
+			}
+		}
+	}()
+
+	s.timer = time.NewTimer(lspDebounce)
+	s.timer.Stop()
+	for {
+		select {
+		case <-s.timer.C:
+			s.analyze()
+		case m := <-msgs:
+			switch {
+			case errors.Is(m.err, io.EOF):
+				return s.exitCode()
+			case errors.As(m.err, new(*json.SyntaxError)):
+				fmt.Fprintf(s.stderr, "errlint: lsp: %v\n", m.err)
+				continue
+			case m.err != nil:
+				fmt.Fprintf(s.stderr, "errlint: lsp: %v\n", m.err)
+				return 1
+			}
+			if m.msg.Method == "exit" {
+				return s.exitCode()
+			}
+			s.handle(m.msg)
+		}
+	}
+}
+
+// exitCode returns the exit code of errlint lsp: 0 if the client requested
+// shutdown before it exited, as the protocol requires, and 1 otherwise.
+func (s *lspServer) exitCode() int {
+	if s.shutdown {
+		return 0
+	}
+	return 1
+}
+
+// handle handles a request or notification of the client.
+func (s *lspServer) handle(m *rpcMessage) {
+	if s.shutdown && m.ID != nil {
+		s.replyError(m, rpcInvalidRequest, "server is shut down")
+		return
+	}
+	switch m.Method {
+	case "initialize":
+		s.reply(m, map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync": map[string]any{
+					"openClose": true,
+					// Changes send the full text of the document.
+					"change": 1,
+					"save":   map[string]any{},
+				},
+				"codeActionProvider": map[string]any{
+					"codeActionKinds": []string{"quickfix"},
+				},
+			},
+			"serverInfo": map[string]any{"name": "errlint"},
+		})
+	case "shutdown":
+		s.shutdown = true
+		s.reply(m, nil)
+	case "textDocument/didOpen":
+		var p struct {
+			TextDocument struct {
+				URI  string `json:"uri"`
+				Text string `json:"text"`
+			} `json:"textDocument"`
+		}
+		if !s.params(m, &p) {
+			return
+		}
+		path, err := uriPath(p.TextDocument.URI)
+		if err != nil {
+			fmt.Fprintf(s.stderr, "errlint: lsp: %v\n", err)
+			return
+		}
+		s.docs[p.TextDocument.URI] = &lspDocument{
+			uri:   p.TextDocument.URI,
+			path:  path,
+			text:  []byte(p.TextDocument.Text),
+			dirty: true,
+		}
+		s.analyze()
+	case "textDocument/didChange":
+		var p struct {
+			TextDocument struct {
+				URI string `json:"uri"`
+			} `json:"textDocument"`
+			ContentChanges []struct {
+				Text string `json:"text"`
+			} `json:"contentChanges"`
+		}
+		if !s.params(m, &p) {
+			return
+		}
+		doc, ok := s.docs[p.TextDocument.URI]
+		if !ok || len(p.ContentChanges) == 0 {
+			return
+		}
+		doc.text = []byte(p.ContentChanges[len(p.ContentChanges)-1].Text)
+		doc.dirty = true
+		s.timer.Reset(lspDebounce)
+	case "textDocument/didSave":
+		s.timer.Stop()
+		s.analyze()
+	case "textDocument/didClose":
+		var p struct {
+			TextDocument struct {
+				URI string `json:"uri"`
+			} `json:"textDocument"`
+		}
+		if !s.params(m, &p) {
+			return
+		}
+		delete(s.docs, p.TextDocument.URI)
+		s.publish(p.TextDocument.URI, nil)
+	case "textDocument/codeAction":
+		var p struct {
+			TextDocument struct {
+				URI string `json:"uri"`
+			} `json:"textDocument"`
+			Range lspRange `json:"range"`
+		}
+		if !s.params(m, &p) {
+			return
+		}
+		actions := []lspCodeAction{}
+		if doc, ok := s.docs[p.TextDocument.URI]; ok && !doc.dirty {
+			for _, a := range doc.actions {
+				if a.Diagnostics[0].Range.overlaps(p.Range) {
+					actions = append(actions, a)
+				}
+			}
+		}
+		s.reply(m, actions)
+	default:
+		// Notifications the server does not handle, such as initialized
+		// and $/cancelRequest, are ignored.
+		if m.ID != nil {
+			s.replyError(m, rpcMethodNotFound, "method not found: "+m.Method)
+		}
+	}
+}
+
+// params decodes the params of m into v. If they are invalid, it reports
+// so to the client, or to stderr for notifications, and returns false.
+func (s *lspServer) params(m *rpcMessage, v any) bool {
+	err := json.Unmarshal(m.Params, v)
+	if err == nil {
+		return true
+	}
+	if m.ID != nil {
+		s.replyError(m, rpcInvalidParams, err.Error())
+	} else {
+		fmt.Fprintf(s.stderr, "errlint: lsp: %s: %v\n", m.Method, err)
+	}
+	return false
+}
+
+// analyze analyzes the packages of the open documents, with their text in
+// place of the files on disk, and publishes the diagnostics of every open
+// document. If the analysis fails, for example because a document does not
+// parse yet, the diagnostics are left as they are.
+func (s *lspServer) analyze() {
+	if len(s.docs) == 0 {
+		return
+	}
+	overlay := make(map[string][]byte)
+	patterns := make(map[string]bool)
+	for _, doc := range s.docs {
+		overlay[doc.path] = doc.text
+		patterns["file="+doc.path] = true
+	}
+	for _, doc := range s.docs {
+		doc.dirty = true
+	}
+	findings, err := analyze(slices.Sorted(maps.Keys(patterns)), s.tests, overlay)
+	if err != nil {
+		fmt.Fprintf(s.stderr, "errlint: %v\n", err)
+		return
+	}
+	findings = filterFindings(s.cfg, findings)
+
+	byFile := make(map[string][]finding)
+	for _, f := range findings {
+		name := f.Fset.File(f.Pos).Name()
+		byFile[name] = append(byFile[name], f)
+	}
+	checks := checksByName()
+	files := &lspFiles{docs: s.docs, disk: make(map[string][]byte)}
+	for _, uri := range slices.Sorted(maps.Keys(s.docs)) {
+		doc := s.docs[uri]
+		doc.dirty = false
+		doc.diagnostics = []lspDiagnostic{}
+		doc.actions = nil
+		for _, f := range byFile[doc.path] {
+			d := newLSPDiagnostic(checks, f, doc.text)
+			doc.diagnostics = append(doc.diagnostics, d)
+			for _, fix := range f.SuggestedFixes {
+				doc.actions = append(doc.actions, files.codeAction(d, f, fix.Message, fix.TextEdits))
+			}
+		}
+		s.publish(uri, doc.diagnostics)
+	}
+}
+
+// publish sends the diagnostics of the document uri to the client.
+func (s *lspServer) publish(uri string, diagnostics []lspDiagnostic) {
+	if diagnostics == nil {
+		diagnostics = []lspDiagnostic{}
+	}
+	s.notify("textDocument/publishDiagnostics", map[string]any{
+		"uri":         uri,
+		"diagnostics": diagnostics,
+	})
+}
+
+// rpcMessage is a JSON-RPC 2.0 request, notification or response.
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// readMessage reads a message framed by the headers of the Language Server
+// Protocol from r. It returns io.EOF if r ends before the message starts,
+// and a *json.SyntaxError if the message was read but is not JSON.
+func readMessage(r *bufio.Reader) (*rpcMessage, error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if errors.Is(err, io.EOF) && line != "" {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || !strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			continue
+		}
+		length, err = strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || length < 0 {
+			return nil, fmt.Errorf("invalid Content-Length %q", strings.TrimSpace(value))
+		}
+	}
+	if length < 0 {
+		return nil, errors.New("message without Content-Length")
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, fmt.Errorf("reading message: %w", err)
+	}
+	var m rpcMessage
+	if err := json.Unmarshal(body, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// send writes m to the client, framed by the headers of the protocol.
+func (s *lspServer) send(m *rpcMessage) {
+	m.JSONRPC = "2.0"
+	body, err := json.Marshal(m)
+	if err != nil {
+		fmt.Fprintf(s.stderr, "errlint: lsp: %v\n", err)
+		return
+	}
+	if _, err := fmt.Fprintf(s.stdout, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		fmt.Fprintf(s.stderr, "errlint: lsp: %v\n", err)
+	}
+}
+
+func (s *lspServer) reply(m *rpcMessage, result any) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		s.replyError(m, rpcInvalidRequest, err.Error())
+		return
+	}
+	s.send(&rpcMessage{ID: m.ID, Result: data})
+}
+
+func (s *lspServer) replyError(m *rpcMessage, code int, message string) {
+	s.send(&rpcMessage{ID: m.ID, Error: &rpcError{Code: code, Message: message}})
+}
+
+func (s *lspServer) notify(method string, params any) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		fmt.Fprintf(s.stderr, "errlint: lsp: %v\n", err)
+		return
+	}
+	s.send(&rpcMessage{Method: method, Params: data})
+}
+
+// lspPosition is a position in a document: a zero-based line and a
+// zero-based column counted in UTF-16 code units, as the protocol counts
+// them by default.
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+func (p lspPosition) before(q lspPosition) bool {
+	return p.Line < q.Line || p.Line == q.Line && p.Character < q.Character
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+// overlaps reports whether r and q overlap or touch, so that a cursor at
+// either end of a diagnostic gets its code actions.
+func (r lspRange) overlaps(q lspRange) bool {
+	return !r.End.before(q.Start) && !q.End.before(r.Start)
+}
+
+type lspDiagnostic struct {
+	Range lspRange `json:"range"`
+	// Severity is 1 for errors, 2 for warnings and 3 for information.
+	Severity int    `json:"severity"`
+	Code     string `json:"code"`
+	Source   string `json:"source"`
+	Message  string `json:"message"`
+}
+
+type lspTextEdit struct {
+	Range   lspRange `json:"range"`
+	NewText string   `json:"newText"`
+}
+
+type lspCodeAction struct {
+	Title       string          `json:"title"`
+	Kind        string          `json:"kind"`
+	Diagnostics []lspDiagnostic `json:"diagnostics"`
+	IsPreferred bool            `json:"isPreferred"`
+	Edit        struct {
+		Changes map[string][]lspTextEdit `json:"changes"`
+	} `json:"edit"`
+}
+
+// lspSeverities maps the severities of findings to those of diagnostics.
+var lspSeverities = map[string]int{
+	"error":   1,
+	"warning": 2,
+	"info":    3,
+}
+
+// newLSPDiagnostic returns the diagnostic of f, whose check is in checks,
+// in a document with the given text. The ID of the check is its code
+// rather than the end of its message, since editors show both.
+func newLSPDiagnostic(checks map[string]analyzer.Check, f finding, text []byte) lspDiagnostic {
+	id := checks[f.Category].ID
+	return lspDiagnostic{
+		Range:    lspRange{Start: newLSPPosition(text, f.Position.Offset), End: newLSPPosition(text, f.End.Offset)},
+		Severity: lspSeverities[f.Severity],
+		Code:     id,
+		Source:   "errlint",
+		Message:  strings.TrimSuffix(f.Message, " ["+id+"]"),
+	}
+}
+
+// newLSPPosition returns the position of the byte offset in text.
+func newLSPPosition(text []byte, offset int) lspPosition {
+	offset = min(offset, len(text))
+	start := bytes.LastIndexByte(text[:offset], '\n') + 1
+	character := 0
+	for _, r := range string(text[start:offset]) {
+		character += utf16.RuneLen(r)
+	}
+	return lspPosition{Line: bytes.Count(text[:start], []byte("\n")), Character: character}
+}
+
+// lspFiles resolves the positions of the edits of fixes, in open documents
+// or in files on disk.
+type lspFiles struct {
+	docs map[string]*lspDocument
+	// disk caches the files read from disk.
+	disk map[string][]byte
+}
+
+// text returns the URI and text of the file name.
+func (fs *lspFiles) text(name string) (string, []byte) {
+	for _, doc := range fs.docs {
+		if doc.path == name {
+			return doc.uri, doc.text
+		}
+	}
+	text, ok := fs.disk[name]
+	if !ok {
+		text, _ = os.ReadFile(name)
+		fs.disk[name] = text
+	}
+	return pathURI(name), text
+}
+
+// codeAction returns the quick fix that applies the suggested fix of f
+// with the given message and edits, for the diagnostic d of f.
+func (fs *lspFiles) codeAction(d lspDiagnostic, f finding, message string, edits []analysis.TextEdit) lspCodeAction {
+	a := lspCodeAction{
+		Title:       message,
+		Kind:        "quickfix",
+		Diagnostics: []lspDiagnostic{d},
+		IsPreferred: len(f.SuggestedFixes) == 1,
+	}
+	a.Edit.Changes = make(map[string][]lspTextEdit)
+	for _, te := range edits {
+		file := f.Fset.File(te.Pos)
+		end := te.End
+		if !end.IsValid() {
+			end = te.Pos
+		}
+		uri, text := fs.text(file.Name())
+		a.Edit.Changes[uri] = append(a.Edit.Changes[uri], lspTextEdit{
+			Range:   lspRange{Start: newLSPPosition(text, file.Offset(te.Pos)), End: newLSPPosition(text, file.Offset(end))},
+			NewText: string(te.NewText),
+		})
+	}
+	return a
+}
+
+// uriPath returns the file name of a file URI.
+func uriPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("%s: not a file URI", uri)
+	}
+	path := u.Path
+	if runtime.GOOS == "windows" {
+		// file:///C:/dir/file.go has the path /C:/dir/file.go.
+		path = strings.TrimPrefix(path, "/")
+	}
+	return filepath.FromSlash(path), nil
+}
+
+// pathURI returns the file URI of the absolute file name.
+func pathURI(name string) string {
+	path := filepath.ToSlash(name)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
@@ -0,0 +1,590 @@
+// Command errlint reports error handling anti-patterns in Go packages.
+//
+// Usage:
+//
+//	errlint [flags] [packages]
+//	errlint explain [ID or check]...
+//	errlint baseline generate [flags] [packages]
+//	errlint report -html dir [flags] [packages]
+//	errlint stats [-top n] [flags] [packages]
+//	errlint annotate [-write] [flags] file.go
+//	errlint migrate [flags] [packages]
+//	errlint watch [flags] [packages]
+//	errlint lsp [flags]
+//	errlint install-hook [-force] [-- flags]
+//	errlint clean-cache [-cache-dir dir]
+//	errlint quiz [-n number] [-seed seed]
+//	errlint gen-fixtures [-seed seed] dir
+//	errlint refactor extract-sentinel [-name name] file.go:line
+//	errlint doctor [-config file]
+//
+// Packages are go list patterns such as ./... and default to the package in
+// the current directory. Findings are printed as file:line:col: message,
+// and every message ends with the ID of the check that reported it, such
+// as [ERRLINT001]. errlint explain prints why the check reports the code,
+// examples of the reported code and its fix, and links to the Go
+// documentation; without arguments it lists the checks.
+//
+// errlint baseline generate writes the current findings to a baseline
+// file, .errlint-baseline.json next to the configuration file by default.
+// Later runs do not report the findings in the baseline, so errlint can be
+// adopted on an existing codebase and only fail on new findings. Findings
+// are matched by their check, file, message and source line, so they stay
+// known when code moves around them.
+//
+// errlint report -html dir writes a static HTML report of the findings to
+// dir/index.html, with a summary of the findings by check and by package
+// and an excerpt of the code of each finding, and their counts and the
+// findings as JSON to dir/report.json, to follow them from run to run. It
+// takes the flags of errlint except -fix and -format.
+//
+// errlint stats prints the number of findings by check and by package,
+// and the files with the most findings, ten unless -top says otherwise,
+// instead of the findings, to size a cleanup before enforcing the checks.
+// Like report, it takes the flags of errlint except -fix and -format.
+//
+// errlint annotate prints file.go with the findings in it as comments
+// beneath the lines they start on, indented like them, to paste into a
+// code review. With -write it writes the annotated file to
+// file.annotated.go next to it instead, with a //go:build ignore
+// constraint so that the copy is not built with its package. It analyzes
+// the package of the file, and takes the flags of errlint except -fix,
+// -format, -stdin and -staged.
+//
+// errlint migrate rewrites every comparison and switch over error values
+// that the comparison and switch checks report to errors.Is, and every type
+// switch over errors to errors.As, in one go, adding the errors import
+// where needed, and prints how many rewrites it made in each file. It
+// takes the -config, -tests, -concurrency and -allow flags, and leaves the findings it
+// cannot rewrite safely, such as switches with fallthrough, for errlint to
+// report.
+//
+// errlint watch reports the findings, then watches the files of the
+// packages and, shortly after files are saved, analyzes the packages they
+// affect again: their own and the packages importing them. It prints the
+// new findings and, prefixed with "fixed:", the findings that are gone,
+// until it is interrupted. It takes the -config, -tests and -concurrency
+// flags and the flags of the checks.
+//
+// errlint lsp runs a language server on stdin and stdout, for editors
+// without a golangci-lint integration. It analyzes the packages of the
+// documents open in the editor, with their unsaved text in place of their
+// files, when they are opened or saved and shortly after they change, and
+// publishes the findings in them as diagnostics and their suggested fixes
+// as quick fixes. It takes the -config and -tests flags and the flags of
+// the checks.
+//
+// errlint install-hook writes a git pre-commit hook that runs errlint
+// -staged with the flags after --, so that commits adding findings to the
+// staged files fail. It does not replace a pre-commit hook it did not
+// write unless -force is set.
+//
+// errlint quiz is a training exercise. It generates -n snippets of Go code,
+// five by default, each built from a few patterns of error handling code
+// in their buggy or corrected form, so a snippet may hold no anti-pattern
+// at all, and asks on stdin for the lines with an anti-pattern in each.
+// The findings of the default checks in the snippets grade the answers.
+// The snippets follow from -seed, which errlint quiz prints, so a team can
+// answer the same ones.
+//
+// errlint gen-fixtures writes a module of fixtures to dir, for the authors
+// of other linters and for regression tests: a package for each pattern
+// of the quiz, with a file of its buggy form, whose reported lines are
+// marked with // want comments as analysistest expects, and a file of its
+// fix, or a single file for the special cases the checks allow, such as
+// comparisons with io.EOF and sql.ErrNoRows and in Is methods. The names in
+// the fixtures follow from -seed, 1 by default, so the same seed writes the
+// same files.
+//
+// errlint refactor extract-sentinel lifts the errors.New call with a
+// constant message on the line of file.go out of its function into a
+// package-level sentinel declared before the function, and returns the
+// sentinel instead. The sentinel is named after the first words of the
+// message, such as ErrUserNotFound for "user not found", unless -name says
+// otherwise. Comparisons of err.Error() with the message, with == or !=,
+// in the files of the package, tests included, become errors.Is calls with
+// the sentinel, since they check for the same failure. It prints how many
+// rewrites it made in each file.
+//
+// errlint doctor checks the configuration file before a run does: its
+// keys, check names, severities and globs, the patterns of wrap-prefix and
+// of the CODEOWNERS file, and the baseline. It loads the packages of the
+// allowed sentinels from the module of the configuration file and reports
+// the sentinels that are not exported error variables, which never match a
+// comparison. It warns about settings that conflict or have no effect,
+// such as the passthrough list when the wrapcheck check does not run, or a
+// fail-on severity no check reaches, and prints the effective
+// configuration: the checks that run with their severities and the lists
+// of the checks with their defaults, as a configuration file. It exits with
+// status 1 if it finds errors, and 0 if it only finds warnings.
+//
+// When go vet runs errlint with -vettool, errlint analyzes the packages go
+// vet gives it instead, as the vet tools of the analysis framework do. Its
+// flags take the errlint. prefix then, such as -errlint.allow.
+//
+// errlint caches the findings of every package on disk, keyed by a hash
+// of its files, the packages it depends on, the errlint binary and the
+// flags of the checks, so that later runs only analyze the packages that
+// changed or depend on a package that changed. errlint clean-cache removes
+// the cached findings.
+//
+// errlint exits with status 1 if it reports any finding at least as severe
+// as -fail-on, and with status 2 if the packages cannot be loaded or
+// analyzed.
+//
+// Settings not given as flags are read from the configuration file, see
+// package config for its format. The flags are:
+//
+//	-checks list
+//		comma-separated list of checks to run (default: all but the
+//		opt-in checks)
+//	-enable list
+//		comma-separated list of checks to run in addition to -checks,
+//		such as the opt-in dynamic, wrapcheck and swallow checks
+//	-config file
+//		configuration file (default: .errlint.yaml in the working
+//		directory or its parents)
+//	-preset name
+//		settings to start from: standard, strict or teaching, for the
+//		settings the configuration file leaves empty; see config.Presets
+//	-fix
+//		apply suggested fixes and report only the findings left unfixed
+//	-fail-on severity
+//		least severe findings that make errlint exit with status 1:
+//		error, warning or info (default warning)
+//	-format name
+//		output format: text, json, sarif, junit, checkstyle or csv
+//		(default text)
+//	-owners file
+//		CODEOWNERS file whose owners -format=csv lists for the file of
+//		each finding (default: .github/CODEOWNERS, CODEOWNERS or
+//		docs/CODEOWNERS next to the configuration file, if one exists)
+//	-severity list
+//		comma-separated list of check=severity pairs, where severity is
+//		error, warning, info or off, overriding the configuration file
+//	-tests
+//		also analyze test files (default true)
+//	-skip-generated
+//		do not report findings in generated files, which have a
+//		"Code generated ... DO NOT EDIT." comment before the package
+//		clause, as protobuf and mock generators write (default true,
+//		or the inverse of generated in the configuration file)
+//	-skip-dirs list
+//		comma-separated list of globs of directories whose findings are
+//		not reported, relative to the configuration file; a glob
+//		without a slash matches directories of that name at any depth,
+//		such as mocks
+//	-concurrency n
+//		maximum number of packages to analyze at once (default
+//		GOMAXPROCS, the number of CPUs unless set otherwise)
+//	-cache-dir dir
+//		directory of the cache of findings, or off to analyze every
+//		package (default: errlint in the user cache directory)
+//	-baseline file
+//		baseline of known findings not to report, or to write with
+//		baseline generate
+//	-stdin
+//		read the source of the file named by -stdin-filename from stdin,
+//		for editors linting unsaved buffers, and only report its
+//		findings
+//	-stdin-filename file
+//		file whose source -stdin reads; its package is analyzed with the
+//		source in place of the file
+//	-staged
+//		analyze the Go files staged for commit as they are in the git
+//		index, with partially staged files as staged, and only report
+//		their findings
+//	-diff rev
+//		only report findings on lines changed since the git revision
+//		rev, including uncommitted changes and untracked files, or by
+//		the unified diff read from stdin if rev is -
+//	-allow list
+//		comma-separated list of additional sentinel errors, as
+//		pkg/path.Name, documented to be returned unwrapped
+//	-passthrough list
+//		comma-separated list of additional packages and functions, as
+//		pkg/path or pkg/path.Name, whose errors the wrapcheck check
+//		allows returning unwrapped
+//	-loggers list
+//		comma-separated list of additional packages and functions, as
+//		pkg/path or pkg/path.Name, whose calls the swallow check treats
+//		as logging an error
+//	-wrap-prefix expression
+//		regular expression the context of wrap messages, in front of
+//		": %w", must match for the wrapmsg check
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/analysis/unitchecker"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer"
+	"github.com/kakkoyun/demo-error-lint/config"
+)
+
+func main() {
+	if vetTool(os.Args[1:]) {
+		unitchecker.Main(analyzer.Analyzer)
+	}
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// vetTool reports whether go vet runs errlint as its -vettool: it asks
+// for the version or the flags of the tool, or passes the configuration
+// file of a package to analyze as the last argument.
+func vetTool(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	if args[0] == "-V=full" || args[0] == "-flags" {
+		return true
+	}
+	return strings.HasSuffix(args[len(args)-1], ".cfg")
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "explain" {
+		return explain(args[1:], stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == "migrate" {
+		return migrate(args[1:], stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == "watch" {
+		return watch(args[1:], stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == "lsp" {
+		return lsp(args[1:], stdin, stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == "install-hook" {
+		return installHook(args[1:], stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == "clean-cache" {
+		return cleanCacheCmd(args[1:], stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == "quiz" {
+		return quiz(args[1:], stdin, stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == "gen-fixtures" {
+		return genFixtures(args[1:], stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == "refactor" {
+		return refactor(args[1:], stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == "doctor" {
+		return doctor(args[1:], stdout, stderr)
+	}
+	generate, report, stats := false, false, false
+	if len(args) > 0 && args[0] == "baseline" {
+		if len(args) < 2 || args[1] != "generate" {
+			fmt.Fprintln(stderr, "usage: errlint baseline generate [flags] [packages]")
+			return 2
+		}
+		generate, args = true, args[2:]
+	}
+	if len(args) > 0 && args[0] == "report" {
+		report, args = true, args[1:]
+	}
+	if len(args) > 0 && args[0] == "stats" {
+		stats, args = true, args[1:]
+	}
+	annotating := false
+	if len(args) > 0 && args[0] == "annotate" {
+		annotating, args = true, args[1:]
+	}
+	flags := flag.NewFlagSet("errlint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: errlint [flags] [packages]\n       errlint explain [ID or check]...\n       errlint baseline generate [flags] [packages]\n       errlint report -html dir [flags] [packages]\n       errlint stats [-top n] [flags] [packages]\n       errlint annotate [-write] [flags] file.go\n       errlint migrate [flags] [packages]\n       errlint watch [flags] [packages]\n       errlint lsp [flags]\n       errlint install-hook [-force] [-- flags]\n       errlint clean-cache [-cache-dir dir]\n       errlint quiz [-n number] [-seed seed]\n       errlint gen-fixtures [-seed seed] dir\n       errlint refactor extract-sentinel [-name name] file.go:line\n       errlint doctor [-config file]\n\n%s\n\nFlags:\n", analyzer.Analyzer.Doc)
+		flags.PrintDefaults()
+	}
+	configFile := flags.String("config", "", "configuration `file` (default: "+config.FileName+" in the working directory or its parents)")
+	preset := flags.String("preset", "", "`name` of the preset of settings to start from: "+strings.Join(config.Presets, ", "))
+	fix := flags.Bool("fix", false, "apply suggested fixes")
+	tests := flags.Bool("tests", true, "also analyze test files")
+	concurrency := flags.Int("concurrency", 0, "maximum `number` of packages to analyze at once (default GOMAXPROCS)")
+	format := flags.String("format", "text", "output `format`: "+strings.Join(formatNames(), ", "))
+	ownersFile := flags.String("owners", "", "CODEOWNERS `file` whose owners -format=csv lists for each finding (default: .github/CODEOWNERS, CODEOWNERS or docs/CODEOWNERS next to the configuration file)")
+	severity := flags.String("severity", "", "comma-separated `list` of check=severity pairs, where severity is error, warning, info or off")
+	baselineFile := flags.String("baseline", "", "baseline `file` of known findings not to report (default: "+config.BaselineName+" next to the configuration file)")
+	fromStdin := flags.Bool("stdin", false, "read the source of the file named by -stdin-filename from stdin, and only report its findings")
+	stdinFilename := flags.String("stdin-filename", "", "`file` whose source -stdin reads; its package is analyzed with the source in place of the file")
+	staged := flags.Bool("staged", false, "analyze the Go files staged for commit as they are in the git index, and only report their findings")
+	diff := flags.String("diff", "", "only report findings on lines changed by git diff `rev`, or by the unified diff read from stdin if rev is -")
+	cacheDir := flags.String("cache-dir", "", "`dir`ectory of the cache of findings, or off (default: errlint in the user cache directory)")
+	failOn := flags.String("fail-on", "", "least severe `severity` of the findings that make errlint exit with status 1 (default "+config.DefaultFailOn+")")
+	skipGenerated := flags.Bool("skip-generated", true, "do not report findings in generated files, which have a \"Code generated ... DO NOT EDIT.\" comment")
+	skipDirs := flags.String("skip-dirs", "", "comma-separated `list` of globs of directories whose findings are not reported")
+	htmlDir := new(string)
+	if report {
+		flags.StringVar(htmlDir, "html", "", "`dir`ectory to write the HTML report index.html and its report.json to")
+	}
+	top := new(int)
+	if stats {
+		flags.IntVar(top, "top", 10, "`number` of files with the most findings to list, or 0 for all")
+	}
+	writeAnnotated := new(bool)
+	if annotating {
+		flags.BoolVar(writeAnnotated, "write", false, "write the annotated file to file.annotated.go next to it instead of printing it")
+	}
+	analyzer.Analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		flags.Var(f.Value, f.Name, f.Usage)
+	})
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if err := setConcurrency(*concurrency); err != nil {
+		fmt.Fprintf(stderr, "errlint: %v\n", err)
+		return 2
+	}
+	write, ok := formats[*format]
+	if !ok {
+		fmt.Fprintf(stderr, "errlint: unknown format %q\n", *format)
+		return 2
+	}
+	if *ownersFile != "" && *format != "csv" {
+		fmt.Fprintln(stderr, "errlint: -owners is only used by -format=csv")
+		return 2
+	}
+	if generate && *fix {
+		fmt.Fprintln(stderr, "errlint: -fix cannot be used with baseline generate")
+		return 2
+	}
+	if report && *htmlDir == "" {
+		fmt.Fprintln(stderr, "errlint: report needs -html dir")
+		return 2
+	}
+	if (report || stats) && *fix {
+		fmt.Fprintln(stderr, "errlint: -fix cannot be used with report or stats")
+		return 2
+	}
+	if annotating {
+		switch {
+		case flags.NArg() != 1 || !strings.HasSuffix(flags.Arg(0), ".go"):
+			fmt.Fprintln(stderr, "errlint: annotate takes a single Go file")
+			return 2
+		case *fix || *fromStdin || *staged:
+			fmt.Fprintln(stderr, "errlint: -fix, -stdin and -staged cannot be used with annotate")
+			return 2
+		}
+	}
+	if generate && *diff != "" {
+		fmt.Fprintln(stderr, "errlint: -diff cannot be used with baseline generate")
+		return 2
+	}
+	if *fromStdin {
+		switch {
+		case *stdinFilename == "":
+			fmt.Fprintln(stderr, "errlint: -stdin needs -stdin-filename")
+			return 2
+		case generate || report || stats || *fix:
+			fmt.Fprintln(stderr, "errlint: -stdin cannot be used with -fix, baseline generate, report or stats")
+			return 2
+		case *diff == "-":
+			fmt.Fprintln(stderr, "errlint: -stdin cannot be used with -diff=-")
+			return 2
+		case flags.NArg() > 0:
+			fmt.Fprintln(stderr, "errlint: -stdin analyzes the package of -stdin-filename; do not list packages")
+			return 2
+		}
+	}
+
+	if *staged {
+		switch {
+		case *fromStdin || generate || report || stats || *fix:
+			fmt.Fprintln(stderr, "errlint: -staged cannot be used with -stdin, -fix, baseline generate, report or stats")
+			return 2
+		case *diff != "":
+			fmt.Fprintln(stderr, "errlint: -staged cannot be used with -diff")
+			return 2
+		case flags.NArg() > 0:
+			fmt.Fprintln(stderr, "errlint: -staged analyzes the packages of the staged files; do not list packages")
+			return 2
+		}
+	}
+
+	cfg, err := loadConfig(*configFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "errlint: %v\n", err)
+		return 2
+	}
+	if *preset != "" {
+		if err := cfg.ApplyPreset(*preset); err != nil {
+			fmt.Fprintf(stderr, "errlint: -preset: %v\n", err)
+			return 2
+		}
+	}
+	if err := applyConfig(cfg, flags); err != nil {
+		fmt.Fprintf(stderr, "errlint: %s: %v\n", config.FileName, err)
+		return 2
+	}
+	if err := applySeverities(cfg, *severity, *failOn); err != nil {
+		fmt.Fprintf(stderr, "errlint: %v\n", err)
+		return 2
+	}
+	if err := applySkips(cfg, flags, *skipGenerated, *skipDirs); err != nil {
+		fmt.Fprintf(stderr, "errlint: %v\n", err)
+		return 2
+	}
+
+	patterns := flags.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	var (
+		// files are the files to report the findings of, analyzed with
+		// the overlay in place of the files on disk.
+		files   []string
+		overlay map[string][]byte
+	)
+	if *fromStdin {
+		var stdinFile, pattern string
+		stdinFile, overlay, pattern, err = readStdin(*stdinFilename, stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "errlint: %v\n", err)
+			return 2
+		}
+		files, patterns = []string{stdinFile}, []string{pattern}
+	}
+	var annotated []byte
+	if annotating {
+		var name string
+		name, annotated, err = readAnnotated(patterns[0])
+		if err != nil {
+			fmt.Fprintf(stderr, "errlint: %v\n", err)
+			return 2
+		}
+		files, patterns = []string{name}, []string{"file=" + name}
+	}
+	if *staged {
+		files, overlay, err = readStaged()
+		if err != nil {
+			fmt.Fprintf(stderr, "errlint: -staged: %v\n", err)
+			return 2
+		}
+		patterns = nil
+		for _, name := range files {
+			patterns = append(patterns, "file="+name)
+		}
+	}
+
+	var findings []finding
+	c, err := cacheFor(*cacheDir)
+	if err != nil {
+		fmt.Fprintf(stderr, "errlint: -cache-dir: %v\n", err)
+		return 2
+	}
+	switch {
+	case *staged && len(files) == 0:
+		// Nothing to analyze: only files the go command ignores, or no Go
+		// files at all, are staged.
+	case c != nil && overlay == nil:
+		findings, err = c.analyze(patterns, *tests)
+	default:
+		findings, err = analyze(patterns, *tests, overlay)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "errlint: %v\n", err)
+		return 2
+	}
+	if *fromStdin || *staged {
+		findings = findingsIn(files, overlay, findings)
+	}
+	if annotating {
+		findings = findingsIn(files, map[string][]byte{files[0]: annotated}, findings)
+	}
+	findings = filterFindings(cfg, findings)
+	if *diff != "" {
+		changed, err := readDiff(*diff, stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "errlint: -diff: %v\n", err)
+			return 2
+		}
+		findings = filterDiff(changed, findings)
+	}
+
+	name := baselinePath(cfg, *baselineFile, generate)
+	if generate {
+		if err := writeBaseline(name, findings); err != nil {
+			fmt.Fprintf(stderr, "errlint: %v\n", err)
+			return 2
+		}
+		fmt.Fprintf(stderr, "errlint: wrote %d findings to %s\n", len(findings), relative(workDir(), name))
+		return 0
+	}
+	if name != "" {
+		findings, err = filterBaseline(name, findings, overlay)
+		if err != nil {
+			fmt.Fprintf(stderr, "errlint: %v\n", err)
+			return 2
+		}
+	}
+
+	if report {
+		if err := writeReport(*htmlDir, findings); err != nil {
+			fmt.Fprintf(stderr, "errlint: %v\n", err)
+			return 2
+		}
+		fmt.Fprintf(stderr, "errlint: wrote a report of %s to %s\n", plural(len(findings), "finding"), filepath.Join(*htmlDir, "index.html"))
+		return 0
+	}
+
+	if stats {
+		if err := writeStats(stdout, findings, *top); err != nil {
+			fmt.Fprintf(stderr, "errlint: %v\n", err)
+			return 2
+		}
+		return 0
+	}
+
+	if annotating {
+		if !*writeAnnotated {
+			if _, err := stdout.Write(annotate(annotated, findings, false)); err != nil {
+				fmt.Fprintf(stderr, "errlint: %v\n", err)
+				return 2
+			}
+			return 0
+		}
+		name := annotatedName(files[0])
+		if err := os.WriteFile(name, annotate(annotated, findings, true), 0o644); err != nil {
+			fmt.Fprintf(stderr, "errlint: %v\n", err)
+			return 2
+		}
+		fmt.Fprintf(stderr, "errlint: wrote %s to %s\n", plural(len(findings), "finding"), relative(workDir(), name))
+		return 0
+	}
+
+	if *fix {
+		findings, _, err = applyFixes(findings)
+		if err != nil {
+			fmt.Fprintf(stderr, "errlint: %v\n", err)
+			return 2
+		}
+	}
+
+	if *format == "csv" {
+		if err := setOwners(cfg, *ownersFile, findings); err != nil {
+			fmt.Fprintf(stderr, "errlint: %v\n", err)
+			return 2
+		}
+	}
+	if cfg.Explain && *format == "text" {
+		write = writeExplained
+	}
+	if err := write(stdout, findings); err != nil {
+		fmt.Fprintf(stderr, "errlint: %v\n", err)
+		return 2
+	}
+	for _, f := range findings {
+		if cfg.Fails(f.Severity) {
+			return 1
+		}
+	}
+	return 0
+}
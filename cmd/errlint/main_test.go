@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/rogpeppe/go-internal/testscript"
+)
+
+var update = flag.Bool("update", false, "update the files that cmp compares stdout and stderr with in the scripts")
+
+// TestMain lets the scripts run the test binary as errlint.
+func TestMain(m *testing.M) {
+	testscript.Main(m, map[string]func(){"errlint": main})
+}
+
+// TestScript runs the end-to-end scripts in testdata/script. Each script
+// is a txtar archive in the testscript format: a sequence of commands such
+// as "exec errlint ./..." and "cmp stdout want.txt", followed by the files
+// of a module they run in. errlint is on the PATH of every script, and its
+// file name in $ERRLINT. Besides the commands of testscript, scripts can
+// use frame to write the messages of a language server client, see frame.
+func TestScript(t *testing.T) {
+	errlint, err := exec.LookPath("errlint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := goEnv("GOCACHE", "GOMODCACHE", "GOPATH")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testscript.Run(t, testscript.Params{
+		Dir:           "testdata/script",
+		UpdateScripts: *update,
+		Cmds:          map[string]func(*testscript.TestScript, bool, []string){"frame": frame},
+		Setup: func(e *testscript.Env) error {
+			e.Setenv("ERRLINT", errlint)
+			for _, kv := range env {
+				name, value, _ := strings.Cut(kv, "=")
+				e.Setenv(name, value)
+			}
+			// Scripts must not need the network.
+			e.Setenv("GOPROXY", "off")
+			e.Setenv("GOFLAGS", "-mod=mod")
+			return nil
+		},
+	})
+}
+
+// frame implements the frame command of the scripts: frame src dst writes
+// the JSON-RPC messages in the file src, one per line, to the file dst
+// with the Content-Length headers of the Language Server Protocol, so that
+// errlint lsp can read them from stdin. Environment variables such as
+// $WORK are expanded in the messages.
+func frame(ts *testscript.TestScript, neg bool, args []string) {
+	if neg {
+		ts.Fatalf("unsupported: ! frame")
+	}
+	if len(args) != 2 {
+		ts.Fatalf("usage: frame src dst")
+	}
+	var out bytes.Buffer
+	for line := range strings.Lines(ts.ReadFile(args[0])) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		msg := os.Expand(line, ts.Getenv)
+		fmt.Fprintf(&out, "Content-Length: %d\r\n\r\n%s", len(msg), msg)
+	}
+	ts.Check(os.WriteFile(ts.MkAbs(args[1]), out.Bytes(), 0o644))
+}
+
+// goEnv returns the given go environment variables as name=value pairs,
+// since scripts run without the home directory the defaults derive from.
+func goEnv(names ...string) ([]string, error) {
+	out, err := exec.Command("go", append([]string{"env"}, names...)...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("go env: %w", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	env := make([]string, len(names))
+	for i, name := range names {
+		env[i] = name + "=" + lines[i]
+	}
+	return env, nil
+}
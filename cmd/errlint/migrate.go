@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer"
+	"github.com/kakkoyun/demo-error-lint/config"
+)
+
+// migrateChecks are the checks whose fixes errlint migrate applies. They
+// rewrite == and != comparisons and switches over error values to
+// errors.Is, and type switches over errors to errors.As, which behave the
+// same for errors that are not wrapped.
+const migrateChecks = "comparison,switch"
+
+// maxMigratePasses bounds the number of times errlint migrate analyzes the
+// packages. Fixes that overlap a fix applied in one pass, such as a
+// comparison inside a rewritten switch, are applied in the next.
+const maxMigratePasses = 10
+
+// migrate applies the fixes of migrateChecks to the packages in args, prints
+// the number of rewrites in each changed file, and returns the exit code.
+func migrate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("errlint migrate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: errlint migrate [flags] [packages]\n\nRewrites comparisons and switches over error values to errors.Is, and\ntype switches over errors to errors.As.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	configFile := flags.String("config", "", "configuration `file` (default: "+config.FileName+" in the working directory or its parents)")
+	tests := flags.Bool("tests", true, "also rewrite test files")
+	concurrency := flags.Int("concurrency", 0, "maximum `number` of packages to analyze at once (default GOMAXPROCS)")
+	flags.Var(analyzer.Analyzer.Flags.Lookup("allow").Value, "allow", analyzer.Analyzer.Flags.Lookup("allow").Usage)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if err := setConcurrency(*concurrency); err != nil {
+		fmt.Fprintf(stderr, "errlint: %v\n", err)
+		return 2
+	}
+
+	cfg, err := loadConfig(*configFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "errlint: %v\n", err)
+		return 2
+	}
+	if err := applyConfig(cfg, flags); err != nil {
+		fmt.Fprintf(stderr, "errlint: %s: %v\n", config.FileName, err)
+		return 2
+	}
+	for name, value := range map[string]string{"checks": migrateChecks, "enable": ""} {
+		if err := analyzer.Analyzer.Flags.Set(name, value); err != nil {
+			fmt.Fprintf(stderr, "errlint: %v\n", err)
+			return 2
+		}
+	}
+
+	patterns := flags.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	rewrites := make(map[string]int)
+	var unfixed []finding
+	for range maxMigratePasses {
+		findings, err := analyze(patterns, *tests, nil)
+		if err != nil {
+			fmt.Fprintf(stderr, "errlint: %v\n", err)
+			return 2
+		}
+		var fixed map[string]int
+		unfixed, fixed, err = applyFixes(filterFindings(cfg, findings))
+		if err != nil {
+			fmt.Fprintf(stderr, "errlint: %v\n", err)
+			return 2
+		}
+		if len(fixed) == 0 {
+			break
+		}
+		for name, n := range fixed {
+			rewrites[name] += n
+		}
+	}
+
+	wd := workDir()
+	total := 0
+	for _, name := range slices.Sorted(maps.Keys(rewrites)) {
+		fmt.Fprintf(stdout, "%s: %s\n", relative(wd, name), plural(rewrites[name], "rewrite"))
+		total += rewrites[name]
+	}
+	fmt.Fprintf(stdout, "%s in %s\n", plural(total, "rewrite"), plural(len(rewrites), "file"))
+	if len(unfixed) > 0 {
+		fmt.Fprintf(stderr, "errlint: %s without a safe rewrite left; run errlint -checks=%s to list them\n", plural(len(unfixed), "finding"), migrateChecks)
+	}
+	return 0
+}
+
+// plural returns n followed by noun, with an s unless n is 1.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
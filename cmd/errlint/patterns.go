@@ -0,0 +1,721 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/doc/comment"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// pattern is the template of a piece of error handling code that one of
+// the checks reports, in its buggy and corrected forms. The templates are
+// rendered with a noun, such as order, so that the generated code varies:
+// {{.Var}} is the noun and {{.Type}} the noun capitalized. {{want}} at the
+// end of a line of bad marks it as reported by the check, and {{want 1}}
+// the line after it.
+type pattern struct {
+	// name names the pattern, as a Go package name.
+	name string
+	// check is the name of the check that reports bad.
+	check string
+	// bad and good are the buggy and the corrected code, top-level
+	// declarations that use the packages in imports, though not
+	// necessarily all of them. bad is empty for the special cases the
+	// checks allow, which look like an anti-pattern but are correct.
+	bad, good string
+	imports   []string
+}
+
+// patterns are the patterns generated code is built from.
+var patterns = []pattern{
+	{
+		name:  "comparison",
+		check: "comparison",
+		bad: `var Err{{.Type}}NotFound = errors.New("{{.Var}} not found")
+
+func {{.Var}}Missing(err error) bool {
+	return err == Err{{.Type}}NotFound{{want}}
+}`,
+		good: `var Err{{.Type}}NotFound = errors.New("{{.Var}} not found")
+
+func {{.Var}}Missing(err error) bool {
+	return errors.Is(err, Err{{.Type}}NotFound)
+}`,
+		imports: []string{"errors"},
+	},
+	{
+		name:  "assertion",
+		check: "assertion",
+		bad: `type {{.Type}}Error struct {
+	ID int
+}
+
+func (e *{{.Type}}Error) Error() string {
+	return fmt.Sprintf("{{.Var}} %d failed", e.ID)
+}
+
+func {{.Var}}ID(err error) int {
+	if e, ok := err.(*{{.Type}}Error); ok { {{- want}}
+		return e.ID
+	}
+	return 0
+}`,
+		good: `type {{.Type}}Error struct {
+	ID int
+}
+
+func (e *{{.Type}}Error) Error() string {
+	return fmt.Sprintf("{{.Var}} %d failed", e.ID)
+}
+
+func {{.Var}}ID(err error) int {
+	var e *{{.Type}}Error
+	if errors.As(err, &e) {
+		return e.ID
+	}
+	return 0
+}`,
+		imports: []string{"errors", "fmt"},
+	},
+	{
+		name:  "switchvalue",
+		check: "switch",
+		bad: `var (
+	Err{{.Type}}Locked  = errors.New("{{.Var}} locked")
+	Err{{.Type}}Expired = errors.New("{{.Var}} expired")
+)
+
+func {{.Var}}Status(err error) string {
+	switch err { {{- want}}
+	case nil:
+		return "ok"
+	case Err{{.Type}}Locked:
+		return "locked"
+	case Err{{.Type}}Expired:
+		return "expired"
+	}
+	return "failed"
+}`,
+		good: `var (
+	Err{{.Type}}Locked  = errors.New("{{.Var}} locked")
+	Err{{.Type}}Expired = errors.New("{{.Var}} expired")
+)
+
+func {{.Var}}Status(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, Err{{.Type}}Locked):
+		return "locked"
+	case errors.Is(err, Err{{.Type}}Expired):
+		return "expired"
+	}
+	return "failed"
+}`,
+		imports: []string{"errors"},
+	},
+	{
+		name:  "errorf",
+		check: "errorf",
+		bad: `func open{{.Type}}(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("opening {{.Var}} %s: %v", name, err){{want}}
+	}
+	return f.Close()
+}`,
+		good: `func open{{.Type}}(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("opening {{.Var}} %s: %w", name, err)
+	}
+	return f.Close()
+}`,
+		imports: []string{"fmt", "os"},
+	},
+	{
+		name:  "oserror",
+		check: "oserror",
+		bad: `func {{.Var}}Exists(name string) (bool, error) {
+	_, err := os.Stat(name)
+	if os.IsNotExist(err) { {{- want}}
+		return false, nil
+	}
+	return err == nil, err
+}`,
+		good: `func {{.Var}}Exists(name string) (bool, error) {
+	_, err := os.Stat(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}`,
+		imports: []string{"errors", "io/fs", "os"},
+	},
+	{
+		name:  "message",
+		check: "message",
+		bad: `func {{.Var}}Retryable(err error) bool {
+	return strings.Contains(err.Error(), "connection refused"){{want}}
+}`,
+		good: `func {{.Var}}Retryable(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED)
+}`,
+		imports: []string{"errors", "strings", "syscall"},
+	},
+	{
+		name:  "errorsnew",
+		check: "errorsnew",
+		bad: `func check{{.Type}}(n int) error {
+	if n < 0 {
+		return fmt.Errorf("negative {{.Var}} count"){{want}}
+	}
+	return nil
+}`,
+		good: `func check{{.Type}}(n int) error {
+	if n < 0 {
+		return errors.New("negative {{.Var}} count")
+	}
+	return nil
+}`,
+		imports: []string{"errors", "fmt"},
+	},
+	{
+		name:  "errortext",
+		check: "errortext",
+		bad: `func save{{.Type}}(name string) error {
+	if err := os.WriteFile(name, nil, 0o644); err != nil {
+		return fmt.Errorf("saving {{.Var}}: %s", err.Error()){{want}}
+	}
+	return nil
+}`,
+		good: `func save{{.Type}}(name string) error {
+	if err := os.WriteFile(name, nil, 0o644); err != nil {
+		return fmt.Errorf("saving {{.Var}}: %w", err)
+	}
+	return nil
+}`,
+		imports: []string{"fmt", "os"},
+	},
+	{
+		name:    "sentinelname",
+		check:   "sentinelname",
+		bad:     `var {{.Type}}Closed = errors.New("{{.Var}} closed"){{want}}`,
+		good:    `var Err{{.Type}}Closed = errors.New("{{.Var}} closed")`,
+		imports: []string{"errors"},
+	},
+	{
+		name:  "shadow",
+		check: "shadow",
+		bad: `func backup{{.Type}}(name string, keep bool) error {
+	var err error
+	if keep {
+		data, err := os.ReadFile(name){{want}}
+		if err == nil {
+			err = os.WriteFile(name+".bak", data, 0o644)
+		}
+	}
+	return err
+}`,
+		good: `func backup{{.Type}}(name string, keep bool) error {
+	var err error
+	if keep {
+		var data []byte
+		data, err = os.ReadFile(name)
+		if err == nil {
+			err = os.WriteFile(name+".bak", data, 0o644)
+		}
+	}
+	return err
+}`,
+		imports: []string{"os"},
+	},
+	{
+		name:  "overwrite",
+		check: "overwrite",
+		bad: `func remove{{.Type}}s(names []string) error {
+	var err error
+	for _, name := range names {
+		err = os.Remove(name){{want}}
+	}
+	return err
+}`,
+		good: `func remove{{.Type}}s(names []string) error {
+	var errs []error
+	for _, name := range names {
+		errs = append(errs, os.Remove(name))
+	}
+	return errors.Join(errs...)
+}`,
+		imports: []string{"errors", "os"},
+	},
+	{
+		name:  "typeswitch",
+		check: "switch",
+		bad: `type {{.Type}}Error struct {
+	Reason string
+}
+
+func (e *{{.Type}}Error) Error() string {
+	return "{{.Var}}: " + e.Reason
+}
+
+func {{.Var}}Reason(err error) string {
+	switch e := err.(type) { {{- want}}
+	case *{{.Type}}Error:
+		return e.Reason
+	}
+	return ""
+}`,
+		good: `type {{.Type}}Error struct {
+	Reason string
+}
+
+func (e *{{.Type}}Error) Error() string {
+	return "{{.Var}}: " + e.Reason
+}
+
+func {{.Var}}Reason(err error) string {
+	var e *{{.Type}}Error
+	if errors.As(err, &e) {
+		return e.Reason
+	}
+	return ""
+}`,
+		imports: []string{"errors"},
+	},
+	{
+		name:  "ignore",
+		check: "ignore",
+		bad: `var Err{{.Type}}Missing = errors.New("{{.Var}} missing")
+
+func is{{.Type}}Missing(err error) bool {
+	{{want 1}}
+	//errlint:ignore ERRLINT001 the comparison below was fixed long ago
+	return errors.Is(err, Err{{.Type}}Missing)
+}`,
+		good: `var Err{{.Type}}Missing = errors.New("{{.Var}} missing")
+
+func is{{.Type}}Missing(err error) bool {
+	return errors.Is(err, Err{{.Type}}Missing)
+}`,
+		imports: []string{"errors"},
+	},
+	{
+		name:  "isas",
+		check: "isas",
+		bad: `type {{.Type}}CodeError struct {
+	Code int
+}
+
+func (e *{{.Type}}CodeError) Error() string {
+	return fmt.Sprintf("{{.Var}} failed with code %d", e.Code)
+}
+
+func {{.Var}}Code(err error) int {
+	var e *{{.Type}}CodeError
+	if errors.As(err, e) { {{- want}}
+		return e.Code
+	}
+	return 0
+}`,
+		good: `type {{.Type}}CodeError struct {
+	Code int
+}
+
+func (e *{{.Type}}CodeError) Error() string {
+	return fmt.Sprintf("{{.Var}} failed with code %d", e.Code)
+}
+
+func {{.Var}}Code(err error) int {
+	var e *{{.Type}}CodeError
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return 0
+}`,
+		imports: []string{"errors", "fmt"},
+	},
+	{
+		name:  "typename",
+		check: "typename",
+		bad: `type {{.Type}}Failure struct { {{- want}}
+	Step string
+}
+
+func (e *{{.Type}}Failure) Error() string {
+	return "{{.Var}} failed at " + e.Step
+}`,
+		good: `type {{.Type}}FailureError struct {
+	Step string
+}
+
+func (e *{{.Type}}FailureError) Error() string {
+	return "{{.Var}} failed at " + e.Step
+}`,
+	},
+	{
+		name:  "deferwrap",
+		check: "deferwrap",
+		bad: `func copy{{.Type}}(dst, src string) (err error) {
+	defer func() {
+		err = fmt.Errorf("copying {{.Var}} %s: %w", src, err){{want}}
+	}()
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0o644)
+}`,
+		good: `func copy{{.Type}}(dst, src string) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("copying {{.Var}} %s: %w", src, err)
+		}
+	}()
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0o644)
+}`,
+		imports: []string{"fmt", "os"},
+	},
+	{
+		name:  "panic",
+		check: "panic",
+		bad: `func parse{{.Type}}ID(s string) (int, error) {
+	id, err := strconv.Atoi(s)
+	if err != nil {
+		panic(fmt.Errorf("parsing {{.Var}} ID %q: %w", s, err)){{want}}
+	}
+	return id, nil
+}`,
+		good: `func parse{{.Type}}ID(s string) (int, error) {
+	id, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("parsing {{.Var}} ID %q: %w", s, err)
+	}
+	return id, nil
+}`,
+		imports: []string{"fmt", "strconv"},
+	},
+	{
+		name:  "sprintf",
+		check: "sprintf",
+		bad: `func close{{.Type}}(id int, err error) error {
+	return errors.New(fmt.Sprintf("closing {{.Var}} %d: %v", id, err)){{want}}
+}`,
+		good: `func close{{.Type}}(id int, err error) error {
+	return fmt.Errorf("closing {{.Var}} %d: %w", id, err)
+}`,
+		imports: []string{"errors", "fmt"},
+	},
+	{
+		name:  "reassign",
+		check: "reassign",
+		bad: `var Err{{.Type}}Locked = errors.New("{{.Var}} locked")
+
+func translate{{.Type}}() {
+	Err{{.Type}}Locked = errors.New("{{.Var}} gesperrt"){{want}}
+}`,
+		good: `var Err{{.Type}}Locked = errors.New("{{.Var}} locked")
+
+func translate{{.Type}}(err error) string {
+	if errors.Is(err, Err{{.Type}}Locked) {
+		return "{{.Var}} gesperrt"
+	}
+	return err.Error()
+}`,
+		imports: []string{"errors"},
+	},
+	{
+		name:  "newcompare",
+		check: "newcompare",
+		bad: `func {{.Var}}Expired(err error) bool {
+	expired := errors.New("{{.Var}} expired")
+	return err == expired{{want}}
+}`,
+		good: `var Err{{.Type}}Expired = errors.New("{{.Var}} expired")
+
+func {{.Var}}Expired(err error) bool {
+	return errors.Is(err, Err{{.Type}}Expired)
+}`,
+		imports: []string{"errors"},
+	},
+	{
+		name:  "nilerror",
+		check: "nilerror",
+		bad: `func load{{.Type}}(name string) ([]byte, error) {
+	data, err := os.ReadFile(name)
+	log.Printf("loading {{.Var}} %s: %s", name, err.Error()){{want}}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}`,
+		good: `func load{{.Type}}(name string) ([]byte, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		log.Printf("loading {{.Var}} %s: %s", name, err.Error())
+		return nil, err
+	}
+	return data, nil
+}`,
+		imports: []string{"log", "os"},
+	},
+	{
+		name:  "dynamic",
+		check: "dynamic",
+		bad: `func validate{{.Type}}(name string) error {
+	if name == "" {
+		return errors.New("empty {{.Var}} name"){{want}}
+	}
+	return nil
+}`,
+		good: `var ErrEmpty{{.Type}}Name = errors.New("empty {{.Var}} name")
+
+func validate{{.Type}}(name string) error {
+	if name == "" {
+		return ErrEmpty{{.Type}}Name
+	}
+	return nil
+}`,
+		imports: []string{"errors"},
+	},
+	{
+		name:  "wrapcheck",
+		check: "wrapcheck",
+		bad: `func read{{.Type}}(name string) ([]byte, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err{{want}}
+	}
+	return data, nil
+}`,
+		good: `func read{{.Type}}(name string) ([]byte, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("reading {{.Var}}: %w", err)
+	}
+	return data, nil
+}`,
+		imports: []string{"fmt", "os"},
+	},
+	{
+		name:  "swallow",
+		check: "swallow",
+		bad: `func store{{.Type}}(name string, data []byte) error {
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		log.Printf("storing {{.Var}}: %v", err)
+		return nil{{want}}
+	}
+	return nil
+}`,
+		good: `func store{{.Type}}(name string, data []byte) error {
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		return fmt.Errorf("storing {{.Var}}: %w", err)
+	}
+	return nil
+}`,
+		imports: []string{"fmt", "log", "os"},
+	},
+	{
+		name:  "eof",
+		check: "comparison",
+		good: `func read{{.Type}}(r io.Reader) ([]byte, error) {
+	var b []byte
+	buf := make([]byte, 512)
+	for {
+		n, err := r.Read(buf)
+		b = append(b, buf[:n]...)
+		if err == io.EOF {
+			return b, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}`,
+		imports: []string{"io"},
+	},
+	{
+		name:  "norows",
+		check: "comparison",
+		good: `func find{{.Type}}(db *sql.DB, id int) (string, bool, error) {
+	var name string
+	err := db.QueryRow("SELECT name FROM {{.Var}}s WHERE id = ?", id).Scan(&name)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	return name, err == nil, err
+}`,
+		imports: []string{"database/sql"},
+	},
+	{
+		name:  "customis",
+		check: "comparison",
+		good: `var Err{{.Type}}Timeout = errors.New("{{.Var}} timed out")
+
+type {{.Type}}Error struct {
+	Timeout bool
+}
+
+func (e *{{.Type}}Error) Error() string {
+	return "{{.Var}} failed"
+}
+
+func (e *{{.Type}}Error) Is(target error) bool {
+	return e.Timeout && target == Err{{.Type}}Timeout
+}`,
+		imports: []string{"errors"},
+	},
+	{
+		name:  "unwrapped",
+		check: "comparison",
+		good: `// Err{{.Type}}Done is returned by the next function of {{.Var}}sLeft
+// when there are no {{.Var}}s left.
+//
+//errlint:unwrapped
+var Err{{.Type}}Done = errors.New("no {{.Var}}s left")
+
+func {{.Var}}sLeft(next func() error) int {
+	n := 0
+	for next() != Err{{.Type}}Done {
+		n++
+	}
+	return n
+}`,
+		imports: []string{"errors"},
+	},
+	{
+		name:  "unwrapassert",
+		check: "assertion",
+		good: `func {{.Var}}Cause(err error) error {
+	if u, ok := err.(interface{ Unwrap() error }); ok {
+		return u.Unwrap()
+	}
+	return nil
+}`,
+	},
+	{
+		name:  "retry",
+		check: "overwrite",
+		good: `func fetch{{.Type}}(get func() error) error {
+	var err error
+	for range 3 {
+		err = get()
+		if err == nil {
+			break
+		}
+	}
+	return err
+}`,
+	},
+}
+
+// nouns are the nouns the patterns are rendered with.
+var nouns = []string{"account", "cart", "invoice", "order", "payment", "report", "session", "ticket", "upload", "user"}
+
+// part is a pattern as it is rendered into a generated file.
+type part struct {
+	pattern
+	noun  string
+	buggy bool
+}
+
+var (
+	// wantLine and wantSuffix match the // want comments rendered on a
+	// line of their own and at the end of a line.
+	wantLine   = regexp.MustCompile(`(?m)^[ \t]*// want .*\n`)
+	wantSuffix = regexp.MustCompile(`(?m)[ \t]*// want .*$`)
+)
+
+// generate renders parts into a file of package pkg, with the package
+// documentation doc unless it is empty. Parts are rendered in their buggy form
+// if buggy is set and they have one. If want is set, the lines the checks
+// report are marked with // want comments, as analysistest expects them in
+// fixtures, with the ID of the check as the expected message.
+func generate(doc, pkg string, parts []part, want bool) ([]byte, error) {
+	var decls bytes.Buffer
+	imports := make(map[string]bool)
+	for _, p := range parts {
+		c, ok := findCheck(p.check)
+		if !ok {
+			return nil, fmt.Errorf("pattern %s: unknown check %q", p.name, p.check)
+		}
+		text := p.good
+		if p.buggy && p.bad != "" {
+			text = p.bad
+		}
+		t, err := template.New(p.name).Funcs(template.FuncMap{
+			"want": func(next ...int) string {
+				if len(next) > 0 {
+					return fmt.Sprintf("// want +%d %q", next[0], c.ID)
+				}
+				return fmt.Sprintf(" // want %q", c.ID)
+			},
+		}).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %s: %w", p.name, err)
+		}
+		decls.WriteString("\n")
+		if err := t.Execute(&decls, map[string]string{"Var": p.noun, "Type": strings.ToUpper(p.noun[:1]) + p.noun[1:]}); err != nil {
+			return nil, fmt.Errorf("pattern %s: %w", p.name, err)
+		}
+		decls.WriteString("\n")
+		for _, imp := range p.imports {
+			imports[imp] = true
+		}
+	}
+
+	var src bytes.Buffer
+	if doc != "" {
+		var parser comment.Parser
+		printer := comment.Printer{TextPrefix: "// ", TextWidth: 74}
+		src.Write(printer.Text(parser.Parse(doc)))
+	}
+	fmt.Fprintf(&src, "package %s\n", pkg)
+	if len(imports) > 0 {
+		src.WriteString("\nimport (\n")
+		for _, imp := range slices.Sorted(maps.Keys(imports)) {
+			fmt.Fprintf(&src, "\t%q\n", imp)
+		}
+		src.WriteString(")\n")
+	}
+	if want {
+		src.Write(decls.Bytes())
+	} else {
+		src.Write(wantSuffix.ReplaceAll(wantLine.ReplaceAll(decls.Bytes(), nil), nil))
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src.Bytes(), parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parsing generated code: %w", err)
+	}
+	for imp := range imports {
+		if !astutil.UsesImport(f, imp) {
+			astutil.DeleteImport(fset, f, imp)
+		}
+	}
+	if len(f.Imports) == 1 {
+		decl := f.Decls[0].(*ast.GenDecl)
+		decl.Lparen, decl.Rparen = token.NoPos, token.NoPos
+	}
+	var out bytes.Buffer
+	if err := format.Node(&out, fset, f); err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/kakkoyun/demo-error-lint/internal/lint"
+)
+
+// quizPatterns is the number of patterns each snippet of a quiz is built
+// from.
+const quizPatterns = 3
+
+// quiz generates snippets of Go code from the patterns, each with none or
+// some of its patterns in their buggy form, asks on stdin for the lines
+// with an anti-pattern and grades the answers with the findings of the
+// analyzer, and returns the exit code.
+func quiz(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("errlint quiz", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: errlint quiz [-n number] [-seed seed]\n\nFlags:")
+		flags.PrintDefaults()
+	}
+	n := flags.Int("n", 5, "`number` of snippets to ask about")
+	seed := flags.Uint64("seed", 0, "`seed` of the snippets, to ask about the same snippets again (default: random)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 || *n < 1 {
+		flags.Usage()
+		return 2
+	}
+	if *seed == 0 {
+		*seed = rand.Uint64()
+	}
+
+	snippets, findings, err := quizSnippets(*n, *seed)
+	if err != nil {
+		fmt.Fprintf(stderr, "errlint: %v\n", err)
+		return 2
+	}
+
+	fmt.Fprintf(stdout, "%d snippets with seed %d; errlint quiz -seed=%d asks about them again.\n", *n, *seed, *seed)
+	answers := bufio.NewScanner(stdin)
+	right := 0
+	for i, src := range snippets {
+		lines := strings.Split(strings.TrimSuffix(string(src), "\n"), "\n")
+		fmt.Fprintf(stdout, "\n== Snippet %d/%d\n\n", i+1, len(snippets))
+		for l, line := range lines {
+			fmt.Fprintf(stdout, "%3d  %s\n", l+1, line)
+		}
+
+		var picked []int
+		for {
+			fmt.Fprint(stdout, "\nWhich lines have an error handling anti-pattern? Enter their numbers, none, or q to quit: ")
+			if !answers.Scan() {
+				fmt.Fprintln(stdout)
+				quizSummary(stdout, right, i)
+				return 0
+			}
+			answer := strings.TrimSpace(answers.Text())
+			if answer == "q" {
+				quizSummary(stdout, right, i)
+				return 0
+			}
+			var ok bool
+			if picked, ok = parseLines(answer, len(lines)); ok {
+				break
+			}
+			fmt.Fprintf(stdout, "Enter line numbers from 1 to %d, separated by spaces or commas, or none.", len(lines))
+		}
+
+		found := findings[i]
+		var want []int
+		for _, f := range found {
+			want = append(want, f.Position.Line)
+		}
+		want = slices.Compact(want)
+		if slices.Equal(picked, want) {
+			right++
+			fmt.Fprintln(stdout, "Right.")
+		} else {
+			fmt.Fprintln(stdout, "Not quite.")
+		}
+		if len(found) == 0 {
+			fmt.Fprintln(stdout, "errlint reports nothing in this snippet.")
+		}
+		for _, f := range found {
+			mark := "missed"
+			if slices.Contains(picked, f.Position.Line) {
+				mark = "found"
+			}
+			fmt.Fprintf(stdout, "  line %d, %s: %s\n", f.Position.Line, mark, f.Message)
+		}
+		for _, l := range picked {
+			if !slices.Contains(want, l) {
+				fmt.Fprintf(stdout, "  line %d: errlint reports nothing here\n", l)
+			}
+		}
+	}
+	quizSummary(stdout, right, len(snippets))
+	return 0
+}
+
+// quizSnippets generates n snippets from the patterns with the given seed
+// and returns them with the findings of the analyzer in each, sorted by
+// position.
+func quizSnippets(n int, seed uint64) ([][]byte, [][]finding, error) {
+	// The answers are graded with the default checks, so the patterns of
+	// the opt-in checks are left out.
+	var pats []pattern
+	for _, p := range patterns {
+		if c, _ := findCheck(p.check); !c.OptIn {
+			pats = append(pats, p)
+		}
+	}
+	r := rand.New(rand.NewPCG(seed, 0))
+	snippets := make([][]byte, n)
+	for i := range snippets {
+		picked := r.Perm(len(nouns))
+		var parts []part
+		for j, p := range r.Perm(len(pats))[:quizPatterns] {
+			parts = append(parts, part{pats[p], nouns[picked[j]], r.IntN(2) == 0})
+		}
+		src, err := generate("", "quiz", parts, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		snippets[i] = src
+	}
+
+	// The snippets are analyzed as the packages of a scratch module, one
+	// package each, so the analyzer is the judge of the answers.
+	dir, err := os.MkdirTemp("", "errlint-quiz")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(dir)
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module quiz\n\ngo 1.25\n"), 0o644); err != nil {
+		return nil, nil, err
+	}
+	for i, src := range snippets {
+		pkg := filepath.Join(dir, "snippet"+strconv.Itoa(i))
+		if err := os.Mkdir(pkg, 0o755); err != nil {
+			return nil, nil, err
+		}
+		if err := os.WriteFile(filepath.Join(pkg, "quiz.go"), src, 0o644); err != nil {
+			return nil, nil, err
+		}
+	}
+	cfg := &packages.Config{
+		Mode: packages.LoadAllSyntax,
+		Dir:  dir,
+		Env:  append(os.Environ(), "GOWORK=off"),
+	}
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading snippets: %w", err)
+	}
+	if err := lint.PackageErrors(pkgs); err != nil {
+		return nil, nil, fmt.Errorf("loading snippets: %w", err)
+	}
+	all, err := analyzePackages(pkgs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("analyzing snippets: %w", err)
+	}
+	findings := make([][]finding, n)
+	for _, f := range all {
+		i, err := strconv.Atoi(strings.TrimPrefix(f.Package, "quiz/snippet"))
+		if err != nil {
+			return nil, nil, fmt.Errorf("finding in unexpected package %s", f.Package)
+		}
+		findings[i] = append(findings[i], f)
+	}
+	return snippets, findings, nil
+}
+
+// parseLines parses an answer of the quiz, none or line numbers from 1 to
+// maxLine separated by spaces or commas, into the sorted line numbers.
+func parseLines(answer string, maxLine int) ([]int, bool) {
+	if answer == "none" {
+		return nil, true
+	}
+	fields := strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	if len(fields) == 0 {
+		return nil, false
+	}
+	var lines []int
+	for _, field := range fields {
+		l, err := strconv.Atoi(field)
+		if err != nil || l < 1 || l > maxLine {
+			return nil, false
+		}
+		lines = append(lines, l)
+	}
+	slices.Sort(lines)
+	return slices.Compact(lines), true
+}
+
+// quizSummary prints how many of the done snippets were answered right.
+func quizSummary(w io.Writer, right, done int) {
+	fmt.Fprintf(w, "\nRight: %d of %d.\n", right, done)
+}
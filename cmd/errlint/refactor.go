@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+)
+
+// maxSentinelWords bounds the number of words of the message that make up
+// the name of an extracted sentinel.
+const maxSentinelWords = 4
+
+// initialisms are the words that sentinel names spell in capitals, as Go
+// names do.
+var initialisms = []string{"API", "DNS", "EOF", "HTTP", "ID", "IO", "IP", "JSON", "SQL", "TCP", "TLS", "UDP", "URL", "UUID"}
+
+// refactor runs the refactoring named by args[0] on the code at the
+// position that follows it, and returns the exit code.
+func refactor(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "extract-sentinel" {
+		fmt.Fprintln(stderr, "usage: errlint refactor extract-sentinel [-name name] file.go:line")
+		return 2
+	}
+	flags := flag.NewFlagSet("errlint refactor extract-sentinel", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: errlint refactor extract-sentinel [-name name] file.go:line\n\nLifts the errors.New call on the line into a package-level sentinel, and\nrewrites the comparisons of error messages with its text to errors.Is.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	name := flags.String("name", "", "`name` of the sentinel (default: Err followed by the first words of the message)")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	if *name != "" && !token.IsIdentifier(*name) {
+		fmt.Fprintf(stderr, "errlint: -name: %q is not a Go identifier\n", *name)
+		return 2
+	}
+	file, line, err := parseLocation(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "errlint: %v\n", err)
+		return 2
+	}
+
+	rewrites, err := extractSentinel(file, line, *name)
+	if err != nil {
+		fmt.Fprintf(stderr, "errlint: %v\n", err)
+		return 2
+	}
+	wd := workDir()
+	total := 0
+	for _, name := range slices.Sorted(maps.Keys(rewrites)) {
+		fmt.Fprintf(stdout, "%s: %s\n", relative(wd, name), plural(rewrites[name], "rewrite"))
+		total += rewrites[name]
+	}
+	fmt.Fprintf(stdout, "%s in %s\n", plural(total, "rewrite"), plural(len(rewrites), "file"))
+	return 0
+}
+
+// parseLocation splits loc, file.go:line, into the absolute name of the
+// file and the line.
+func parseLocation(loc string) (string, int, error) {
+	file, lineText, ok := strings.Cut(loc, ":")
+	line, err := strconv.Atoi(lineText)
+	if !ok || err != nil || line < 1 || !strings.HasSuffix(file, ".go") {
+		return "", 0, fmt.Errorf("%q is not a location of the form file.go:line", loc)
+	}
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return "", 0, err
+	}
+	return abs, line, nil
+}
+
+// extractSentinel replaces the errors.New call with a constant message on
+// the line of file by a new package-level sentinel, declared before the
+// function holding the call and named name, or after the message if name
+// is empty. Comparisons of the result of the Error method of an error with
+// the message, with == or !=, in the files of the package become calls of
+// errors.Is with the sentinel. It returns the number of rewrites in each
+// file.
+func extractSentinel(file string, line int, name string) (map[string]int, error) {
+	pkgs, err := load([]string{"file=" + file}, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	// The test variant of the package, if any, has the most files, and so
+	// the most comparisons to rewrite.
+	var pkg *packages.Package
+	var syntax *ast.File
+	for _, p := range pkgs {
+		for i, name := range p.CompiledGoFiles {
+			if name == file && i < len(p.Syntax) && (pkg == nil || len(p.Syntax) > len(pkg.Syntax)) {
+				pkg, syntax = p, p.Syntax[i]
+			}
+		}
+	}
+	if pkg == nil {
+		return nil, fmt.Errorf("%s is not in a package", relative(workDir(), file))
+	}
+	loc := fmt.Sprintf("%s:%d", relative(workDir(), file), line)
+
+	call, decl, err := newCallOn(pkg, syntax, line)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", loc, err)
+	}
+	message := constant.StringVal(pkg.TypesInfo.Types[call.Args[0]].Value)
+	if name == "" {
+		name = sentinelName(message)
+	}
+	if obj := pkg.Types.Scope().Lookup(name); obj != nil {
+		return nil, fmt.Errorf("%s: %s is already declared at %s; choose another name with -name", loc, name, relative(workDir(), pkg.Fset.Position(obj.Pos()).String()))
+	}
+
+	edits := make(map[string][]edit)
+	rewrites := make(map[string]int)
+	add := func(start, end token.Pos, text string) {
+		tf := pkg.Fset.File(start)
+		edits[tf.Name()] = append(edits[tf.Name()], edit{start: tf.Offset(start), end: tf.Offset(end), text: text})
+	}
+	errorsName, _ := errorsImport(syntax)
+	start := decl.Pos()
+	if doc := decl.Doc; doc != nil {
+		start = doc.Pos()
+	}
+	add(start, start, fmt.Sprintf("var %s = %s.New(%s)\n\n", name, errorsName, strconv.Quote(message)))
+	add(call.Pos(), call.End(), name)
+	rewrites[file]++
+
+	var missing []string
+	for _, f := range pkg.Syntax {
+		local, imported := errorsImport(f)
+		count := 0
+		ast.Inspect(f, func(n ast.Node) bool {
+			bin, ok := n.(*ast.BinaryExpr)
+			if !ok || bin.Op != token.EQL && bin.Op != token.NEQ {
+				return true
+			}
+			for _, pair := range [][2]ast.Expr{{bin.X, bin.Y}, {bin.Y, bin.X}} {
+				recv, ok := errorMessage(pkg.TypesInfo, pair[0])
+				tv := pkg.TypesInfo.Types[pair[1]]
+				if !ok || tv.Value == nil || tv.Value.Kind() != constant.String || constant.StringVal(tv.Value) != message {
+					continue
+				}
+				text := fmt.Sprintf("%s.Is(%s, %s)", local, nodeText(pkg.Fset, recv), name)
+				if bin.Op == token.NEQ {
+					text = "!" + text
+				}
+				add(bin.Pos(), bin.End(), text)
+				count++
+				return false
+			}
+			return true
+		})
+		if count == 0 {
+			continue
+		}
+		fileName := pkg.Fset.File(f.Pos()).Name()
+		rewrites[fileName] += count
+		if !imported {
+			missing = append(missing, fileName)
+		}
+	}
+
+	for fileName, list := range edits {
+		slices.SortStableFunc(list, func(a, b edit) int { return cmp.Compare(a.start, b.start) })
+		if err := rewrite(fileName, list); err != nil {
+			return nil, err
+		}
+	}
+	for _, fileName := range missing {
+		if err := addErrorsImport(fileName); err != nil {
+			return nil, err
+		}
+	}
+	return rewrites, nil
+}
+
+// newCallOn returns the errors.New call with a constant message on the line
+// of file, and the declaration of the function holding it.
+func newCallOn(pkg *packages.Package, file *ast.File, line int) (*ast.CallExpr, *ast.FuncDecl, error) {
+	var calls []*ast.CallExpr
+	var decls []*ast.FuncDecl
+	topLevel := false
+	for _, d := range file.Decls {
+		ast.Inspect(d, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || pkg.Fset.Position(call.Pos()).Line != line || !isErrorsNew(pkg.TypesInfo, call) {
+				return true
+			}
+			fn, ok := d.(*ast.FuncDecl)
+			if !ok {
+				topLevel = true
+				return true
+			}
+			calls, decls = append(calls, call), append(decls, fn)
+			return true
+		})
+	}
+	switch {
+	case len(calls) == 1:
+		return calls[0], decls[0], nil
+	case len(calls) > 1:
+		return nil, nil, errors.New("more than one errors.New call with a constant message on the line")
+	case topLevel:
+		return nil, nil, errors.New("the errors.New call on the line is already at package level")
+	}
+	return nil, nil, errors.New("no errors.New call with a constant message on the line")
+}
+
+// isErrorsNew reports whether call is a call of errors.New with a constant
+// message.
+func isErrorsNew(info *types.Info, call *ast.CallExpr) bool {
+	var id *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	case *ast.Ident:
+		id = fun
+	default:
+		return false
+	}
+	fn, ok := info.Uses[id].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "errors" || fn.Name() != "New" || len(call.Args) != 1 {
+		return false
+	}
+	tv := info.Types[call.Args[0]]
+	return tv.Value != nil && tv.Value.Kind() == constant.String
+}
+
+// errorMessage returns x if expr is x.Error(), a call of the Error method
+// of an error.
+func errorMessage(info *types.Info, expr ast.Expr) (ast.Expr, bool) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 {
+		return nil, false
+	}
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Error" {
+		return nil, false
+	}
+	tv, ok := info.Types[sel.X]
+	if !ok || !tv.IsValue() || !types.Implements(tv.Type, types.Universe.Lookup("error").Type().Underlying().(*types.Interface)) {
+		return nil, false
+	}
+	return sel.X, true
+}
+
+// errorsImport returns the name under which file refers to package errors,
+// and whether it imports it.
+func errorsImport(file *ast.File) (string, bool) {
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || path != "errors" {
+			continue
+		}
+		if spec.Name == nil {
+			return "errors", true
+		}
+		if spec.Name.Name != "_" && spec.Name.Name != "." {
+			return spec.Name.Name, true
+		}
+	}
+	return "errors", false
+}
+
+// addErrorsImport adds the import of package errors to the named file.
+func addErrorsImport(name string) error {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	astutil.AddImport(fset, f, "errors")
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, f); err != nil {
+		return err
+	}
+	return os.WriteFile(name, buf.Bytes(), 0o644)
+}
+
+// nodeText returns the source of node as gofmt prints it.
+func nodeText(fset *token.FileSet, node ast.Node) string {
+	var buf bytes.Buffer
+	format.Node(&buf, fset, node)
+	return buf.String()
+}
+
+// sentinelName returns the name of a sentinel for an error with message:
+// Err followed by the first words of the message, up to its first
+// punctuation, capitalized, such as ErrUserNotFound for "user not found".
+func sentinelName(message string) string {
+	if i := strings.IndexAny(message, ":;,.("); i >= 0 {
+		message = message[:i]
+	}
+	words := strings.FieldsFunc(message, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	b.WriteString("Err")
+	for i, word := range words {
+		if i == maxSentinelWords {
+			break
+		}
+		if j := slices.IndexFunc(initialisms, func(s string) bool { return strings.EqualFold(s, word) }); j >= 0 {
+			b.WriteString(initialisms[j])
+			continue
+		}
+		r := []rune(word)
+		b.WriteString(strings.ToUpper(string(r[0])) + string(r[1:]))
+	}
+	if b.Len() == len("Err") {
+		b.WriteString("Sentinel")
+	}
+	return b.String()
+}
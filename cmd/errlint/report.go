@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"html/template"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// excerptContext is the number of lines shown before and after the lines
+// of a finding in the excerpts of the HTML report.
+const excerptContext = 2
+
+// reportSummary is the content of report.json: the counts of the findings
+// by severity, check and package, which stay comparable from run to run to
+// follow a trend, and the findings themselves.
+type reportSummary struct {
+	Total      int                 `json:"total"`
+	Severities map[string]int      `json:"severities"`
+	Rules      []reportRuleCount   `json:"rules"`
+	Packages   []reportPackageJSON `json:"packages"`
+	Findings   []jsonFinding       `json:"findings"`
+}
+
+type reportRuleCount struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type reportPackageJSON struct {
+	Path  string         `json:"path"`
+	Count int            `json:"count"`
+	Rules map[string]int `json:"rules"`
+}
+
+// reportData is what reportPage renders.
+type reportData struct {
+	Total      int
+	Severities []reportCount
+	Rules      []reportRule
+	Packages   []reportPackage
+}
+
+type reportCount struct {
+	Name  string
+	Count int
+}
+
+// reportRule holds the findings of a check, by package.
+type reportRule struct {
+	ID, Name, Doc string
+	Count         int
+	Packages      []reportRulePackage
+}
+
+type reportRulePackage struct {
+	Path     string
+	Findings []reportFinding
+}
+
+// reportPackage holds the number of findings of a package by check.
+type reportPackage struct {
+	Path  string
+	Count int
+	Rules []reportCount
+}
+
+type reportFinding struct {
+	Position, Severity, Message string
+	Excerpt                     []excerptLine
+}
+
+// excerptLine is a line of source, split around the part a finding
+// reports.
+type excerptLine struct {
+	Number              int
+	Before, Mark, After string
+	Hit                 bool
+}
+
+var reportPage = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>errlint report</title>
+<style>
+body { font-family: sans-serif; max-width: 70em; margin: 2em auto; padding: 0 1em; }
+table { border-collapse: collapse; }
+th, td { padding: 0.2em 0.6em; text-align: left; vertical-align: top; }
+tr:nth-child(even) { background: #f8f8f8; }
+td.count { text-align: right; }
+pre { background: #f4f4f4; padding: 0.5em 0; overflow-x: auto; tab-size: 4; font-size: 0.9em; }
+pre span.line { display: block; padding: 0 1em; }
+pre span.hit { background: #fff3c4; }
+pre span.num { display: inline-block; width: 3em; color: #888; user-select: none; }
+mark { background: #ffb74d; }
+.severity-error { color: #b00020; }
+.severity-warning { color: #a15c00; }
+.severity-info { color: #1565c0; }
+</style>
+</head>
+<body>
+<h1>errlint report</h1>
+{{if .Total}}
+<p>{{.Total}} findings:{{range $i, $s := .Severities}}{{if $i}},{{end}} <span class="severity-{{$s.Name}}">{{$s.Count}} {{$s.Name}}</span>{{end}}.</p>
+<h2>By check</h2>
+<table>
+<tr><th>ID</th><th>Check</th><th>Findings</th><th>Packages</th><th>Reports</th></tr>
+{{range .Rules}}<tr><td><a href="#{{.ID}}">{{.ID}}</a></td><td>{{.Name}}</td><td class="count">{{.Count}}</td><td class="count">{{len .Packages}}</td><td>{{.Doc}}</td></tr>
+{{end}}</table>
+<h2>By package</h2>
+<table>
+<tr><th>Package</th><th>Findings</th><th>Checks</th></tr>
+{{range .Packages}}<tr><td>{{.Path}}</td><td class="count">{{.Count}}</td><td>{{range $i, $r := .Rules}}{{if $i}}, {{end}}{{$r.Name}} ({{$r.Count}}){{end}}</td></tr>
+{{end}}</table>
+{{range .Rules}}
+<h2 id="{{.ID}}">{{.ID}} {{.Name}}</h2>
+<p>{{.Doc}} Run <code>errlint explain {{.ID}}</code> for why and how to fix it.</p>
+{{range .Packages}}
+<h3>{{.Path}}</h3>
+{{range .Findings}}
+<p><code>{{.Position}}</code> <span class="severity-{{.Severity}}">{{.Severity}}</span>: {{.Message}}</p>
+{{with .Excerpt}}<pre>{{range .}}<span class="line{{if .Hit}} hit{{end}}"><span class="num">{{.Number}}</span>{{.Before}}{{with .Mark}}<mark>{{.}}</mark>{{end}}{{.After}}</span>{{end}}</pre>{{end}}
+{{end}}
+{{end}}
+{{end}}
+{{else}}
+<p>No findings.</p>
+{{end}}
+</body>
+</html>
+`))
+
+// writeReport writes a static HTML report of findings, grouped by check
+// and package with an excerpt of the code of each finding, to index.html
+// in dir, and their counts and the findings as JSON to report.json.
+func writeReport(dir string, findings []finding) error {
+	checks := checksByName()
+	summary := reportSummary{
+		Total:      len(findings),
+		Severities: make(map[string]int),
+		Rules:      []reportRuleCount{},
+		Packages:   []reportPackageJSON{},
+		Findings:   []jsonFinding{},
+	}
+	data := reportData{Total: len(findings)}
+	rules := make(map[string]*reportRule)
+	ruleFindings := make(map[string]map[string][]reportFinding)
+	pkgs := make(map[string]*reportPackageJSON)
+	sources := make(map[string][]string)
+	for _, f := range findings {
+		summary.Severities[f.Severity]++
+		summary.Findings = append(summary.Findings, newJSONFinding(checks, f))
+
+		r := rules[f.Category]
+		if r == nil {
+			c := checks[f.Category]
+			r = &reportRule{ID: c.ID, Name: c.Name, Doc: c.Doc}
+			rules[f.Category] = r
+		}
+		r.Count++
+		if ruleFindings[f.Category] == nil {
+			ruleFindings[f.Category] = make(map[string][]reportFinding)
+		}
+		ruleFindings[f.Category][f.Package] = append(ruleFindings[f.Category][f.Package], reportFinding{
+			Position: f.Position.String(),
+			Severity: f.Severity,
+			Message:  f.Message,
+			Excerpt:  excerpt(f, sources),
+		})
+
+		p := pkgs[f.Package]
+		if p == nil {
+			p = &reportPackageJSON{Path: f.Package, Rules: make(map[string]int)}
+			pkgs[f.Package] = p
+		}
+		p.Count++
+		p.Rules[f.Category]++
+	}
+
+	for _, severity := range []string{"error", "warning", "info"} {
+		if n := summary.Severities[severity]; n > 0 {
+			data.Severities = append(data.Severities, reportCount{Name: severity, Count: n})
+		}
+	}
+	for name, r := range rules {
+		for _, path := range slices.Sorted(maps.Keys(ruleFindings[name])) {
+			r.Packages = append(r.Packages, reportRulePackage{Path: path, Findings: ruleFindings[name][path]})
+		}
+		data.Rules = append(data.Rules, *r)
+	}
+	slices.SortFunc(data.Rules, func(a, b reportRule) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.ID, b.ID))
+	})
+	for _, r := range data.Rules {
+		summary.Rules = append(summary.Rules, reportRuleCount{ID: r.ID, Name: r.Name, Count: r.Count})
+	}
+	for _, p := range pkgs {
+		summary.Packages = append(summary.Packages, *p)
+		rp := reportPackage{Path: p.Path, Count: p.Count}
+		for _, r := range data.Rules {
+			if n := p.Rules[r.Name]; n > 0 {
+				rp.Rules = append(rp.Rules, reportCount{Name: r.Name, Count: n})
+			}
+		}
+		data.Packages = append(data.Packages, rp)
+	}
+	slices.SortFunc(summary.Packages, func(a, b reportPackageJSON) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Path, b.Path))
+	})
+	slices.SortFunc(data.Packages, func(a, b reportPackage) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Path, b.Path))
+	})
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	var page bytes.Buffer
+	if err := reportPage.Execute(&page, data); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), page.Bytes(), 0o644); err != nil {
+		return err
+	}
+	js, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "report.json"), append(js, '\n'), 0o644)
+}
+
+// excerpt returns the lines of f and excerptContext lines around them, with
+// the part f reports marked. sources holds the lines of the files read so
+// far by name; an excerpt of a file that cannot be read is empty.
+func excerpt(f finding, sources map[string][]string) []excerptLine {
+	name := f.Fset.File(f.Pos).Name()
+	lines, ok := sources[name]
+	if !ok {
+		content, err := os.ReadFile(name)
+		if err == nil {
+			lines = strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+		}
+		sources[name] = lines
+	}
+	start, end := f.Position, f.End
+	if end.Line < start.Line || end.Line == start.Line && end.Column < start.Column {
+		end = start
+	}
+	if start.Line < 1 || end.Line > len(lines) {
+		return nil
+	}
+
+	var out []excerptLine
+	for n := max(1, start.Line-excerptContext); n <= min(len(lines), end.Line+excerptContext); n++ {
+		text := lines[n-1]
+		l := excerptLine{Number: n, Before: text}
+		if n >= start.Line && n <= end.Line {
+			from, to := 0, len(text)
+			if n == start.Line {
+				from = min(start.Column-1, len(text))
+			}
+			if n == end.Line {
+				to = max(from, min(end.Column-1, len(text)))
+			}
+			l = excerptLine{Number: n, Before: text[:from], Mark: text[from:to], After: text[to:], Hit: true}
+		}
+		out = append(out, l)
+	}
+	return out
+}
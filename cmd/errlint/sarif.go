@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"go/token"
+	"io"
+	"net/url"
+	"path/filepath"
+)
+
+// The types below model the subset of SARIF 2.1.0 that errlint emits. See
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	FullDescription  sarifMessage `json:"fullDescription"`
+	HelpURI          string       `json:"helpUri,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+	Fixes     []sarifFix      `json:"fixes,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion  `json:"deletedRegion"`
+	InsertedContent sarifMessage `json:"insertedContent"`
+}
+
+// writeSARIF writes findings as a SARIF 2.1.0 log with one rule per check.
+func writeSARIF(w io.Writer, findings []finding) error {
+	driver := sarifDriver{
+		Name:           "errlint",
+		InformationURI: "https://github.com/kakkoyun/demo-error-lint",
+	}
+	ruleIndex := make(map[string]int)
+	for i, c := range allChecks() {
+		ruleIndex[c.Name] = i
+		rule := sarifRule{
+			ID:               c.ID,
+			Name:             c.Name,
+			ShortDescription: sarifMessage{Text: c.Doc},
+			FullDescription:  sarifMessage{Text: c.Rationale},
+		}
+		if len(c.Links) > 0 {
+			rule.HelpURI = c.Links[0]
+		}
+		driver.Rules = append(driver.Rules, rule)
+	}
+
+	results := make([]sarifResult, 0, len(findings))
+	for _, f := range findings {
+		r := sarifResult{
+			RuleID:    driver.Rules[ruleIndex[f.Category]].ID,
+			RuleIndex: ruleIndex[f.Category],
+			Level:     sarifLevel(f.Severity),
+			Message:   sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: artifactLocation(f.Position.Filename),
+					Region:           region(f.Position, f.End),
+				},
+			}},
+		}
+		for _, fix := range f.SuggestedFixes {
+			changes := make(map[string]*sarifArtifactChange)
+			var order []string
+			for _, te := range fix.TextEdits {
+				start := f.Fset.Position(te.Pos)
+				end := start
+				if te.End.IsValid() {
+					end = f.Fset.Position(te.End)
+				}
+				name := start.Filename
+				c, ok := changes[name]
+				if !ok {
+					c = &sarifArtifactChange{ArtifactLocation: artifactLocation(relative(workDir(), name))}
+					changes[name] = c
+					order = append(order, name)
+				}
+				c.Replacements = append(c.Replacements, sarifReplacement{
+					DeletedRegion:   region(start, end),
+					InsertedContent: sarifMessage{Text: string(te.NewText)},
+				})
+			}
+			sf := sarifFix{Description: sarifMessage{Text: fix.Message}}
+			for _, name := range order {
+				sf.ArtifactChanges = append(sf.ArtifactChanges, *changes[name])
+			}
+			r.Fixes = append(r.Fixes, sf)
+		}
+		results = append(results, r)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
+
+// artifactLocation returns the location of filename, relative to the source
+// root if filename is relative.
+func artifactLocation(filename string) sarifArtifactLocation {
+	if filepath.IsAbs(filename) {
+		u := url.URL{Scheme: "file", Path: filepath.ToSlash(filename)}
+		return sarifArtifactLocation{URI: u.String()}
+	}
+	return sarifArtifactLocation{URI: filepath.ToSlash(filename), URIBaseID: "%SRCROOT%"}
+}
+
+func region(start, end token.Position) sarifRegion {
+	return sarifRegion{
+		StartLine:   start.Line,
+		StartColumn: start.Column,
+		EndLine:     end.Line,
+		EndColumn:   end.Column,
+	}
+}
+
+// sarifLevel maps an errlint severity to a SARIF result level.
+func sarifLevel(severity string) string {
+	if severity == "info" {
+		return "note"
+	}
+	return severity
+}
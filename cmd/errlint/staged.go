@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// readStaged returns the absolute names of the Go files staged for commit
+// in the git repository of the working directory, and the overlay that
+// puts their contents in the index in place of the files in the working
+// tree. The overlay also covers the other files whose changes are not
+// staged, so the packages are analyzed as they will be committed, down to
+// partially staged hunks. Files the go command ignores, such as those in
+// testdata directories, are left out.
+func readStaged() ([]string, map[string][]byte, error) {
+	top, err := git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, nil, err
+	}
+	root := string(bytes.TrimSpace(top))
+	staged, err := gitFiles("diff", "--cached", "--name-only", "-z", "--diff-filter=ACMR")
+	if err != nil {
+		return nil, nil, err
+	}
+	unstaged, err := gitFiles("diff", "--name-only", "-z")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var names []string
+	overlay := make(map[string][]byte)
+	for i, name := range append(staged, unstaged...) {
+		if !goSource(name) {
+			continue
+		}
+		abs := filepath.Join(root, filepath.FromSlash(name))
+		if _, ok := overlay[abs]; ok {
+			continue
+		}
+		// A path starting with : names the file in the index, relative to
+		// the top of the working tree.
+		content, err := git("cat-file", "blob", ":"+name)
+		if i >= len(staged) && err != nil {
+			// Files whose removal is not staged yet, or that were
+			// added to the index with git add -N, stay as they are.
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		overlay[abs] = content
+		if i < len(staged) {
+			names = append(names, abs)
+		}
+	}
+	return names, overlay, nil
+}
+
+// goSource reports whether the file name, relative to the top of the
+// working tree, is a Go file that the go command does not ignore.
+func goSource(name string) bool {
+	if !strings.HasSuffix(name, ".go") {
+		return false
+	}
+	for elem := range strings.SplitSeq(name, "/") {
+		if elem == "testdata" || elem == "vendor" || strings.HasPrefix(elem, ".") || strings.HasPrefix(elem, "_") {
+			return false
+		}
+	}
+	return true
+}
+
+// gitFiles returns the NUL-separated file names git prints for args.
+func gitFiles(args ...string) ([]string, error) {
+	out, err := git(args...)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for name := range strings.SplitSeq(string(out), "\x00") {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// git runs git with args in the working directory and returns its output.
+func git(args ...string) ([]byte, error) {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		var exit *exec.ExitError
+		if errors.As(err, &exit) && len(exit.Stderr) > 0 {
+			return nil, fmt.Errorf("git %s: %s", args[0], bytes.TrimSpace(exit.Stderr))
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// findingsIn returns the findings in the files names, which were analyzed
+// with the contents in overlay in place of the files on disk. Suggested
+// fixes are computed from the overlay, so they are dropped for files whose
+// contents in it differ from the file on disk.
+func findingsIn(names []string, overlay map[string][]byte, findings []finding) []finding {
+	stale := make(map[string]bool)
+	for _, name := range names {
+		disk, err := os.ReadFile(name)
+		stale[name] = err != nil || !bytes.Equal(disk, overlay[name])
+	}
+	var out []finding
+	for _, f := range findings {
+		name := f.Fset.File(f.Pos).Name()
+		isStale, ok := stale[name]
+		if !ok {
+			continue
+		}
+		if isStale {
+			f.SuggestedFixes = nil
+		}
+		out = append(out, f)
+	}
+	return out
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"text/tabwriter"
+)
+
+// statsRow is a row of a table of errlint stats: a check, package or file
+// and the number of its findings, of which fixable have a suggested fix.
+type statsRow struct {
+	name, id       string
+	count, fixable int
+}
+
+// writeStats prints the number of findings by check and by package, and
+// the top files with the most findings, instead of the findings.
+func writeStats(w io.Writer, findings []finding, top int) error {
+	checks := checksByName()
+	rules := make(map[string]*statsRow)
+	pkgs := make(map[string]*statsRow)
+	files := make(map[string]*statsRow)
+	fixable := 0
+	add := func(rows map[string]*statsRow, name string, f finding) {
+		r := rows[name]
+		if r == nil {
+			r = &statsRow{name: name}
+			rows[name] = r
+		}
+		r.count++
+		if len(f.SuggestedFixes) > 0 {
+			r.fixable++
+		}
+	}
+	for _, f := range findings {
+		add(rules, f.Category, f)
+		rules[f.Category].id = checks[f.Category].ID
+		add(pkgs, f.Package, f)
+		add(files, f.Position.Filename, f)
+		if len(f.SuggestedFixes) > 0 {
+			fixable++
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "%s in %s and %s, %d with a suggested fix\n", plural(len(findings), "finding"), plural(len(pkgs), "package"), plural(len(files), "file"), fixable); err != nil {
+		return err
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	table := func(title string, rows []*statsRow) {
+		fmt.Fprintf(tw, "\n%s\tFindings\tFixable\n", title)
+		for _, r := range rows {
+			name := r.name
+			if r.id != "" {
+				name = r.id + " " + r.name
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%d\n", name, r.count, r.fixable)
+		}
+	}
+	table("Check", sortedStats(rules))
+	table("Package", sortedStats(pkgs))
+	sorted := sortedStats(files)
+	if top > 0 && len(sorted) > top {
+		sorted = sorted[:top]
+	}
+	table(fmt.Sprintf("Top %s", plural(len(sorted), "file")), sorted)
+	return tw.Flush()
+}
+
+// sortedStats returns the rows with the most findings first.
+func sortedStats(rows map[string]*statsRow) []*statsRow {
+	return slices.SortedFunc(maps.Values(rows), func(a, b *statsRow) int {
+		return cmp.Or(cmp.Compare(b.count, a.count), cmp.Compare(a.id, b.id), cmp.Compare(a.name, b.name))
+	})
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+// readStdin reads the source of the file name from stdin, for editors
+// linting an unsaved buffer. It returns the absolute file name, the overlay
+// that puts the source in place of the file, and the pattern of the
+// package containing the file.
+func readStdin(name string, stdin io.Reader) (string, map[string][]byte, string, error) {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return "", nil, "", err
+	}
+	content, err := io.ReadAll(stdin)
+	if err != nil {
+		return "", nil, "", fmt.Errorf("reading stdin: %w", err)
+	}
+	return abs, map[string][]byte{abs: content}, "file=" + abs, nil
+}
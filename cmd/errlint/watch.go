@@ -0,0 +1,323 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer"
+	"github.com/kakkoyun/demo-error-lint/config"
+)
+
+// watchDebounce is how long errlint watch waits after a file changes for
+// further changes, such as the other files of a save-all, before it
+// analyzes the packages again.
+const watchDebounce = 300 * time.Millisecond
+
+// watch analyzes the packages in args, then analyzes them again whenever
+// their files change until it is interrupted, and returns the exit code.
+func watch(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("errlint watch", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: errlint watch [flags] [packages]\n\nReports findings again whenever the files of the packages change.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	configFile := flags.String("config", "", "configuration `file` (default: "+config.FileName+" in the working directory or its parents)")
+	tests := flags.Bool("tests", true, "also analyze test files")
+	concurrency := flags.Int("concurrency", 0, "maximum `number` of packages to analyze at once (default GOMAXPROCS)")
+	analyzer.Analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		flags.Var(f.Value, f.Name, f.Usage)
+	})
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if err := setConcurrency(*concurrency); err != nil {
+		fmt.Fprintf(stderr, "errlint: %v\n", err)
+		return 2
+	}
+
+	cfg, err := loadConfig(*configFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "errlint: %v\n", err)
+		return 2
+	}
+	if err := applyConfig(cfg, flags); err != nil {
+		fmt.Fprintf(stderr, "errlint: %s: %v\n", config.FileName, err)
+		return 2
+	}
+	patterns := flags.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		fmt.Fprintf(stderr, "errlint: %v\n", err)
+		return 2
+	}
+	defer fsw.Close()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	w := &watcher{
+		patterns: patterns,
+		tests:    *tests,
+		cfg:      cfg,
+		fsw:      fsw,
+		stdout:   stdout,
+		stderr:   stderr,
+		findings: make(map[string][]finding),
+		files:    make(map[string]string),
+		imports:  make(map[string][]string),
+	}
+	w.analyze(nil)
+
+	pending := make(map[string]bool)
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return 0
+		case ev, ok := <-fsw.Events:
+			if !ok {
+				return 0
+			}
+			if watched(ev.Name) {
+				pending[ev.Name] = true
+				timer.Reset(watchDebounce)
+			}
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return 0
+			}
+			fmt.Fprintf(stderr, "errlint: watch: %v\n", err)
+		case <-timer.C:
+			changed := slices.Sorted(maps.Keys(pending))
+			clear(pending)
+			w.analyze(changed)
+		}
+	}
+}
+
+// watched reports whether a change of the file name can change the
+// findings: it is a Go file that the go command does not ignore, or the
+// go.mod or go.sum file.
+func watched(name string) bool {
+	base := filepath.Base(name)
+	if strings.HasPrefix(base, ".") || strings.HasPrefix(base, "_") {
+		return false
+	}
+	return strings.HasSuffix(base, ".go") || base == "go.mod" || base == "go.sum"
+}
+
+// watcher keeps the findings of the packages errlint watch analyzes, so
+// that a change of some files only analyzes the packages it can affect.
+type watcher struct {
+	patterns       []string
+	tests          bool
+	cfg            *config.Config
+	fsw            *fsnotify.Watcher
+	stdout, stderr io.Writer
+
+	// findings holds the findings of each root package by path, see
+	// finding.Package.
+	findings map[string][]finding
+	// files maps the names of the files of the root packages to their
+	// package path.
+	files map[string]string
+	// imports maps each root package path to the paths of the packages the
+	// package and its tests import.
+	imports map[string][]string
+	// reported holds the findings printed so far, by fingerprint.
+	reported map[string][]finding
+	// stale is set after a failed analysis, whose packages are left with
+	// the findings of the analysis before it.
+	stale bool
+}
+
+// analyze analyzes the packages that the changed files can affect again,
+// or all of them if changed is nil, and prints the findings that are new
+// and the ones that were fixed since the last analysis.
+func (w *watcher) analyze(changed []string) {
+	start := time.Now()
+	roots := w.affected(changed)
+	patterns := roots
+	if roots == nil {
+		patterns = w.patterns
+	}
+	pkgs, err := load(patterns, w.tests, nil)
+	var findings []finding
+	if err == nil {
+		findings, err = analyzePackages(pkgs)
+	}
+	if err != nil {
+		fmt.Fprintf(w.stderr, "errlint: %v\n", err)
+		w.stale = true
+		return
+	}
+	w.stale = false
+	analyzed := w.update(roots, pkgs, findings)
+
+	added, fixed, total, err := w.report()
+	if err != nil {
+		fmt.Fprintf(w.stderr, "errlint: %v\n", err)
+		return
+	}
+	summary := fmt.Sprintf("analyzed %s in %v: %s", plural(analyzed, "package"), time.Since(start).Round(time.Millisecond), plural(total, "finding"))
+	if changed == nil {
+		fmt.Fprintf(w.stderr, "errlint: %s; watching for changes\n", summary)
+		return
+	}
+	names := make([]string, len(changed))
+	for i, name := range changed {
+		names[i] = relative(workDir(), name)
+	}
+	fmt.Fprintf(w.stderr, "errlint: %s changed; %s, %d new, %d fixed\n", strings.Join(names, ", "), summary, added, fixed)
+}
+
+// affected returns the paths of the root packages that must be analyzed
+// again after the changed files changed: the packages of the files and the
+// root packages importing them, directly or not, whose facts may change
+// with them. It returns nil if every package must be analyzed again, for
+// example because a file or package was added or removed.
+func (w *watcher) affected(changed []string) []string {
+	if changed == nil || w.stale {
+		return nil
+	}
+	roots := make(map[string]bool)
+	for _, name := range changed {
+		path, ok := w.files[name]
+		if _, err := os.Stat(name); !ok || err != nil {
+			return nil
+		}
+		roots[path] = true
+	}
+	for grew := true; grew; {
+		grew = false
+		for path, imports := range w.imports {
+			if !roots[path] && slices.ContainsFunc(imports, func(imp string) bool { return roots[imp] }) {
+				roots[path] = true
+				grew = true
+			}
+		}
+	}
+	return slices.Sorted(maps.Keys(roots))
+}
+
+// update replaces what the watcher knows of the root packages roots, or of
+// every package if roots is nil, with the packages pkgs analyzed again and
+// their findings, keeping the findings of the other packages. It returns
+// the number of packages analyzed, like index.
+func (w *watcher) update(roots []string, pkgs []*packages.Package, findings []finding) int {
+	if roots == nil {
+		clear(w.findings)
+		clear(w.files)
+		clear(w.imports)
+	}
+	for _, path := range roots {
+		delete(w.findings, path)
+		delete(w.imports, path)
+	}
+	maps.DeleteFunc(w.files, func(_, path string) bool { return slices.Contains(roots, path) })
+	analyzed := w.index(pkgs)
+	for _, f := range findings {
+		w.findings[f.Package] = append(w.findings[f.Package], f)
+	}
+	return analyzed
+}
+
+// index records the files and imports of the root packages pkgs, watches
+// their directories and module roots, and returns the number of packages,
+// not counting test variants.
+func (w *watcher) index(pkgs []*packages.Package) int {
+	dirs := make(map[string]bool)
+	paths := make(map[string]bool)
+	for _, pkg := range pkgs {
+		if strings.HasSuffix(pkg.ID, ".test") {
+			// The generated main package of a test binary.
+			continue
+		}
+		path := cmp.Or(pkg.ForTest, pkg.PkgPath)
+		paths[path] = true
+		for _, name := range pkg.GoFiles {
+			w.files[name] = path
+			dirs[filepath.Dir(name)] = true
+		}
+		for imp := range pkg.Imports {
+			if !slices.Contains(w.imports[path], imp) {
+				w.imports[path] = append(w.imports[path], imp)
+			}
+		}
+		if pkg.Module != nil {
+			dirs[pkg.Module.Dir] = true
+		}
+	}
+	for dir := range dirs {
+		if err := w.fsw.Add(dir); err != nil {
+			fmt.Fprintf(w.stderr, "errlint: watch: %v\n", err)
+		}
+	}
+	return len(paths)
+}
+
+// report prints the findings that were not reported before and the ones
+// that were reported before but are gone, and returns their numbers and
+// the number of current findings. Findings are matched by their baseline
+// fingerprint, so they are not reported again when code moves around them.
+func (w *watcher) report() (added, fixed, total int, err error) {
+	var findings []finding
+	for _, fs := range w.findings {
+		findings = append(findings, fs...)
+	}
+	findings = filterFindings(w.cfg, findings)
+	sortFindings(findings)
+
+	fp, err := newFingerprinter(filepath.Join(w.cfg.Dir, config.BaselineName))
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	current := make(map[string][]finding)
+	var news []finding
+	for _, f := range findings {
+		e, err := fp.entry(f)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		current[e.Fingerprint] = append(current[e.Fingerprint], f)
+		if len(current[e.Fingerprint]) > len(w.reported[e.Fingerprint]) {
+			news = append(news, f)
+		}
+	}
+	var gone []finding
+	for key, fs := range w.reported {
+		if n := len(current[key]); n < len(fs) {
+			gone = append(gone, fs[n:]...)
+		}
+	}
+	sortFindings(gone)
+	w.reported = current
+
+	if err := writeText(w.stdout, news); err != nil {
+		return 0, 0, 0, err
+	}
+	for _, f := range gone {
+		if _, err := fmt.Fprintf(w.stdout, "fixed: %s: %s: %s\n", f.Position, f.Severity, f.Message); err != nil {
+			return 0, 0, 0, err
+		}
+	}
+	return len(news), len(gone), len(findings), nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fsnotify/fsnotify"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/kakkoyun/demo-error-lint/config"
+)
+
+// writeFiles creates empty files with the names, relative to dir, and
+// returns their full names.
+func writeFiles(t *testing.T, dir string, names ...string) []string {
+	t.Helper()
+	var full []string
+	for _, name := range names {
+		name = filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte("package p\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		full = append(full, name)
+	}
+	return full
+}
+
+// TestAffected checks the packages analyzed again after files change: the
+// packages of the files and the ones importing them, directly or not, or
+// every package when a file is not known or is gone.
+func TestAffected(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "a/a.go", "b/b.go", "c/c.go", "d/d.go", "a/new.go")
+	w := &watcher{
+		files: map[string]string{
+			filepath.Join(dir, "a/a.go"):   "ex/a",
+			filepath.Join(dir, "a/old.go"): "ex/a",
+			filepath.Join(dir, "b/b.go"):   "ex/b",
+			filepath.Join(dir, "c/c.go"):   "ex/c",
+			filepath.Join(dir, "d/d.go"):   "ex/d",
+		},
+		imports: map[string][]string{
+			"ex/a": {"errors"},
+			"ex/b": {"fmt", "ex/a"},
+			"ex/c": {"ex/b"},
+			"ex/d": {"fmt"},
+		},
+	}
+	tests := []struct {
+		name    string
+		changed []string
+		want    []string
+	}{
+		{"start", nil, nil},
+		{"not imported", []string{"c/c.go"}, []string{"ex/c"}},
+		{"imported", []string{"a/a.go"}, []string{"ex/a", "ex/b", "ex/c"}},
+		{"several", []string{"b/b.go", "d/d.go"}, []string{"ex/b", "ex/c", "ex/d"}},
+		{"added file", []string{"c/c.go", "a/new.go"}, nil},
+		{"removed file", []string{"a/old.go"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var changed []string
+			for _, name := range tt.changed {
+				changed = append(changed, filepath.Join(dir, name))
+			}
+			if got := w.affected(changed); fmt.Sprint(got) != fmt.Sprint(tt.want) || (got == nil) != (tt.want == nil) {
+				t.Errorf("affected(%v) = %#v, want %#v", tt.changed, got, tt.want)
+			}
+		})
+	}
+
+	w.stale = true
+	if got := w.affected([]string{filepath.Join(dir, "c/c.go")}); got != nil {
+		t.Errorf("affected after a failed analysis = %v, want nil", got)
+	}
+}
+
+// TestUpdate checks that analyzing some packages again replaces their
+// files, imports and findings and keeps the ones of the other packages,
+// and that only the findings that changed are reported.
+func TestUpdate(t *testing.T) {
+	dir := t.TempDir()
+	files := writeFiles(t, dir, "a/a.go", "a/a_test.go", "a/more.go", "b/b.go")
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fsw.Close()
+	var stdout, stderr bytes.Buffer
+	w := &watcher{
+		cfg:      &config.Config{Dir: dir},
+		fsw:      fsw,
+		stdout:   &stdout,
+		stderr:   &stderr,
+		findings: make(map[string][]finding),
+		files:    make(map[string]string),
+		imports:  make(map[string][]string),
+	}
+	found := func(file, pkg, msg string) finding {
+		return finding{
+			Diagnostic: analysis.Diagnostic{Category: "comparison", Message: msg},
+			Position:   token.Position{Filename: file, Line: 1, Column: 1},
+			Package:    pkg,
+		}
+	}
+	pkgA := &packages.Package{ID: "ex/a", PkgPath: "ex/a", GoFiles: files[:1], Imports: map[string]*packages.Package{"errors": nil}}
+	pkgB := &packages.Package{ID: "ex/b", PkgPath: "ex/b", GoFiles: files[3:], Imports: map[string]*packages.Package{"ex/a": pkgA}}
+
+	analyzed := w.update(nil, []*packages.Package{pkgA, pkgB}, []finding{
+		found(files[0], "ex/a", "in a"),
+		found(files[3], "ex/b", "in b"),
+	})
+	if analyzed != 2 {
+		t.Errorf("analyzed %d packages at the start, want 2", analyzed)
+	}
+	if added, fixed, total, err := w.report(); err != nil || added != 2 || fixed != 0 || total != 2 {
+		t.Errorf("report at the start = %d new, %d fixed, %d total, %v; want 2, 0, 2", added, fixed, total, err)
+	}
+
+	// ex/a is analyzed again with a new file and its test variant.
+	stdout.Reset()
+	pkgA = &packages.Package{ID: "ex/a", PkgPath: "ex/a", GoFiles: []string{files[0], files[2]}, Imports: map[string]*packages.Package{"errors": nil}}
+	pkgATest := &packages.Package{ID: "ex/a [ex/a.test]", PkgPath: "ex/a", ForTest: "ex/a", GoFiles: files[:3], Imports: map[string]*packages.Package{"testing": nil}}
+	pkgMain := &packages.Package{ID: "ex/a.test", PkgPath: "ex/a.test", GoFiles: []string{filepath.Join(dir, "cache/main.go")}}
+	analyzed = w.update([]string{"ex/a"}, []*packages.Package{pkgA, pkgATest, pkgMain}, []finding{
+		found(files[2], "ex/a", "in more"),
+	})
+	if analyzed != 1 {
+		t.Errorf("analyzed %d packages again, want 1", analyzed)
+	}
+	wantFiles := fmt.Sprint(map[string]string{files[0]: "ex/a", files[1]: "ex/a", files[2]: "ex/a", files[3]: "ex/b"})
+	if got := fmt.Sprint(w.files); got != wantFiles {
+		t.Errorf("files = %s, want %s", got, wantFiles)
+	}
+	if got, want := fmt.Sprint(w.imports), "map[ex/a:[errors testing] ex/b:[ex/a]]"; got != want {
+		t.Errorf("imports = %s, want %s", got, want)
+	}
+	var msgs []string
+	for _, path := range []string{"ex/a", "ex/b"} {
+		for _, f := range w.findings[path] {
+			msgs = append(msgs, path+": "+f.Message)
+		}
+	}
+	if got, want := fmt.Sprint(msgs), "[ex/a: in more ex/b: in b]"; got != want {
+		t.Errorf("findings = %s, want %s", got, want)
+	}
+
+	added, fixed, total, err := w.report()
+	if err != nil || added != 1 || fixed != 1 || total != 2 {
+		t.Errorf("report after the change = %d new, %d fixed, %d total, %v; want 1, 1, 2", added, fixed, total, err)
+	}
+	out := stdout.String()
+	if !strings.Contains(out, ": in more\n") || !strings.Contains(out, "fixed: ") || !strings.Contains(out, ": in a\n") || strings.Contains(out, "in b") {
+		t.Errorf("report after the change printed:\n%s\nwant the new finding in more.go and the fixed one in a.go only", out)
+	}
+	if stderr.Len() > 0 {
+		t.Errorf("stderr: %s", &stderr)
+	}
+}
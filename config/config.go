@@ -0,0 +1,335 @@
+// Package config loads errlint configuration files.
+//
+// A configuration file is named .errlint.yaml and usually lives at the root
+// of a repository:
+//
+//	# Checks to run. All checks that are not opt-in run if the list is
+//	# empty.
+//	checks: [comparison, assertion, switch, errorf]
+//
+//	# Checks to run in addition, such as the opt-in dynamic check.
+//	enable: [dynamic]
+//
+//	# Sentinels documented to be returned unwrapped, added to the defaults.
+//	allow:
+//	  - example.com/store.ErrMiss
+//
+//	# Packages and functions whose errors the opt-in wrapcheck check
+//	# allows returning unwrapped, added to the defaults.
+//	passthrough:
+//	  - example.com/store
+//	  - example.com/cache.Client.Get
+//
+//	# Packages and functions whose calls the opt-in swallow check treats
+//	# as logging an error, added to log and log/slog.
+//	loggers:
+//	  - go.uber.org/zap.Logger.Error
+//
+//	# Regular expression the context of wrap messages, in front of ": %w",
+//	# must match for the opt-in wrapmsg check.
+//	wrap-prefix: "^[a-z]+ing\\b"
+//
+//	# Checks of your own, reporting the calls of a function or the
+//	# comparisons with a sentinel, with a text/template message in which
+//	# {{.Expr}} is the reported code and {{.Target}} the pattern. They
+//	# always run.
+//	custom:
+//	  - name: legacywrap
+//	    call: example.com/app/errs.LegacyWrap
+//	    message: "{{.Expr}} is deprecated; wrap with fmt.Errorf and %w"
+//	  - name: errfoo
+//	    compare: example.com/app/store.ErrFoo
+//	    message: "{{.Target}} is going away; check for store.ErrNotFound"
+//
+//	# Files to skip, as slash-separated globs relative to this file.
+//	# "**" matches any number of directories.
+//	exclude:
+//	  - "internal/legacy/**"
+//	  - "**/*_mock.go"
+//
+//	# Directories to skip, as slash-separated globs relative to this file.
+//	# A glob without a slash matches directories of that name at any
+//	# depth.
+//	skip-dirs:
+//	  - testdata
+//	  - "internal/gen"
+//
+//	# Severity of the findings of each check: error, warning, info, or off
+//	# to drop them.
+//	severity:
+//	  comparison: error
+//	  errorf: info
+//
+//	# Least severe findings that make errlint exit with status 1.
+//	fail-on: warning
+//
+//	# Whether to report findings in generated files.
+//	generated: false
+//
+//	# Whether to explain each check under its first finding, in the text
+//	# output.
+//	explain: false
+//
+//	# Baseline of known findings not to report, relative to this file
+//	# (default: .errlint-baseline.json next to it, if it exists).
+//	baseline: .errlint-baseline.json
+//
+//	# CODEOWNERS file that maps files to their owners for -format=csv,
+//	# relative to this file (default: .github/CODEOWNERS, CODEOWNERS or
+//	# docs/CODEOWNERS next to it, if one exists).
+//	owners: .github/CODEOWNERS
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer"
+)
+
+// FileName is the name of the configuration file Find looks for.
+const FileName = ".errlint.yaml"
+
+// BaselineName is the name of the baseline file used if Config.Baseline is
+// empty.
+const BaselineName = ".errlint-baseline.json"
+
+// Severities are the valid values of Config.Severity, from most to least
+// severe. The findings of checks that are off are dropped.
+var Severities = []string{"error", "warning", "info", "off"}
+
+// DefaultSeverity is the severity of checks without an override.
+const DefaultSeverity = "warning"
+
+// DefaultFailOn is the least severe severity that fails a run by default.
+const DefaultFailOn = "warning"
+
+// Config is the contents of a configuration file.
+type Config struct {
+	// Checks lists the checks to run. If empty, all checks that are not
+	// opt-in run.
+	Checks []string `yaml:"checks"`
+	// Enable lists checks to run in addition to Checks, such as the
+	// opt-in ones.
+	Enable []string `yaml:"enable"`
+	// Allow lists additional sentinel errors, as "pkg/path.Name", that may
+	// be compared with ==.
+	Allow []string `yaml:"allow"`
+	// Passthrough lists additional packages, as "pkg/path", and functions,
+	// as "pkg/path.Name", whose errors may be returned unwrapped.
+	Passthrough []string `yaml:"passthrough"`
+	// Loggers lists additional packages, as "pkg/path", and functions, as
+	// "pkg/path.Name", whose calls log an error.
+	Loggers []string `yaml:"loggers"`
+	// WrapPrefix is a regular expression the context of wrap messages must
+	// match.
+	WrapPrefix string `yaml:"wrap-prefix"`
+	// Custom lists checks defined by a call or comparison pattern and a
+	// message template.
+	Custom []analyzer.CustomCheck `yaml:"custom"`
+	// Exclude lists globs of files whose findings are dropped. They are
+	// matched against slash-separated paths relative to Dir.
+	Exclude []string `yaml:"exclude"`
+	// SkipDirs lists globs of directories whose findings are dropped. A
+	// glob with a slash is matched against slash-separated paths relative
+	// to Dir, and one without against the name of each directory.
+	SkipDirs []string `yaml:"skip-dirs"`
+	// Severity maps check names to the severity of their findings.
+	Severity map[string]string `yaml:"severity"`
+	// FailOn is the least severe severity whose findings fail a run. If
+	// empty, it is DefaultFailOn.
+	FailOn string `yaml:"fail-on"`
+	// Generated reports findings in generated files when set.
+	Generated bool `yaml:"generated"`
+	// Explain prints, under the first finding of each check, why the
+	// check reports the code and what the fix looks like, in the text
+	// output.
+	Explain bool `yaml:"explain"`
+	// Baseline is the file, relative to Dir, listing known findings that
+	// are not reported. If empty, BaselineName is used if it exists.
+	Baseline string `yaml:"baseline"`
+	// Owners is the CODEOWNERS file, relative to Dir, that maps files to
+	// their owners. If empty, FindOwners looks for one in Dir.
+	Owners string `yaml:"owners"`
+
+	// Dir is the directory the configuration was loaded from.
+	Dir string `yaml:"-"`
+}
+
+// Find looks for a configuration file in dir and its parents and returns its
+// path. It returns an error wrapping fs.ErrNotExist if there is none.
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		name := filepath.Join(dir, FileName)
+		if _, err := os.Stat(name); err == nil {
+			return name, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("%s: %w", FileName, fs.ErrNotExist)
+		}
+		dir = parent
+	}
+}
+
+// Load reads and validates the configuration file at name. Unknown keys are
+// an error.
+func Load(name string) (*Config, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Config
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&c); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	c.Dir, err = filepath.Abs(filepath.Dir(name))
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Validate reports unknown check names, unknown severities, invalid custom
+// checks and malformed exclude and skip-dirs globs.
+func (c *Config) Validate() error {
+	var errs []error
+	for _, name := range c.Checks {
+		if !isCheck(name) {
+			errs = append(errs, fmt.Errorf("checks: unknown check %q", name))
+		}
+	}
+	for _, name := range c.Enable {
+		if !isCheck(name) {
+			errs = append(errs, fmt.Errorf("enable: unknown check %q", name))
+		}
+	}
+	if err := analyzer.ValidateCustom(c.Custom); err != nil {
+		errs = append(errs, fmt.Errorf("custom: %w", err))
+	}
+	for name, severity := range c.Severity {
+		if !isCheck(name) && !slices.ContainsFunc(c.Custom, func(cc analyzer.CustomCheck) bool { return cc.Name == name }) {
+			errs = append(errs, fmt.Errorf("severity: unknown check %q", name))
+		}
+		if !slices.Contains(Severities, severity) {
+			errs = append(errs, fmt.Errorf("severity: %s: unknown severity %q, want one of %s", name, severity, strings.Join(Severities, ", ")))
+		}
+	}
+	if c.FailOn != "" && (c.FailOn == "off" || !slices.Contains(Severities, c.FailOn)) {
+		errs = append(errs, fmt.Errorf("fail-on: unknown severity %q, want one of %s", c.FailOn, strings.Join(Severities[:len(Severities)-1], ", ")))
+	}
+	for _, glob := range c.Exclude {
+		if _, err := path.Match(strings.ReplaceAll(glob, "**", "*"), ""); err != nil {
+			errs = append(errs, fmt.Errorf("exclude: %q: %w", glob, err))
+		}
+	}
+	for _, glob := range c.SkipDirs {
+		if _, err := path.Match(strings.ReplaceAll(glob, "**", "*"), ""); err != nil {
+			errs = append(errs, fmt.Errorf("skip-dirs: %q: %w", glob, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SeverityOf returns the severity of the findings of check.
+func (c *Config) SeverityOf(check string) string {
+	if s, ok := c.Severity[check]; ok {
+		return s
+	}
+	return DefaultSeverity
+}
+
+// Fails reports whether findings of the given severity fail a run, that
+// is whether it is at least as severe as FailOn.
+func (c *Config) Fails(severity string) bool {
+	failOn := c.FailOn
+	if failOn == "" {
+		failOn = DefaultFailOn
+	}
+	return severity != "off" && slices.Index(Severities, severity) <= slices.Index(Severities, failOn)
+}
+
+// Excluded reports whether filename matches one of the exclude globs, or
+// is in a directory that matches one of the skip-dirs globs.
+func (c *Config) Excluded(filename string) bool {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(c.Dir, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	for _, glob := range c.Exclude {
+		if match(strings.Split(glob, "/"), segments) {
+			return true
+		}
+	}
+	dirs := segments[:len(segments)-1]
+	for _, glob := range c.SkipDirs {
+		pattern := append(strings.Split(glob, "/"), "**")
+		if !strings.Contains(glob, "/") {
+			pattern = []string{"**", glob, "**"}
+		}
+		if match(pattern, dirs) {
+			return true
+		}
+	}
+	return false
+}
+
+// match reports whether the path segments name match the glob segments
+// pattern. A "**" segment matches zero or more path segments.
+func match(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if match(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+func isCheck(name string) bool {
+	for _, c := range analyzer.Checks() {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// OwnersNames are the places, relative to the root of a repository, where
+// FindOwners looks for a CODEOWNERS file, in the order GitHub does.
+var OwnersNames = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Owners maps files to the teams or people that own them, as a CODEOWNERS
+// file does: each line holds a pattern and the owners of the files that
+// match it, and the last matching line wins.
+//
+//	# Default owners of everything in the repository.
+//	*                @example/platform
+//	/api/            @example/api
+//	*_test.go        @example/qa
+//	/internal/legacy/
+//
+// Patterns follow the rules of .gitignore files. A pattern that starts with
+// or contains a slash is relative to the root of the repository, and one
+// without matches at any depth. A pattern matching a directory matches the
+// files under it, and "**" matches any number of directories. A line
+// without owners, such as the last one, leaves the files it matches
+// without an owner.
+type Owners struct {
+	// Dir is the root of the repository the patterns are relative to.
+	Dir   string
+	rules []ownersRule
+}
+
+// ownersRule is a line of a CODEOWNERS file.
+type ownersRule struct {
+	pattern []string
+	owners  []string
+}
+
+// FindOwners returns the CODEOWNERS file of the repository rooted at dir,
+// or "" if it has none.
+func FindOwners(dir string) string {
+	for _, name := range OwnersNames {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if _, err := os.Stat(name); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// LoadOwners reads the CODEOWNERS file name. Its patterns are relative to
+// the directory of the file, or to its parent if the file is in a .github
+// or docs directory, as GitHub has them.
+func LoadOwners(name string) (*Owners, error) {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(abs)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	o := &Owners{Dir: filepath.Dir(abs)}
+	if base := filepath.Base(o.Dir); base == ".github" || base == "docs" {
+		o.Dir = filepath.Dir(o.Dir)
+	}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if i := slices.IndexFunc(fields, func(s string) bool { return strings.HasPrefix(s, "#") }); i >= 0 {
+			fields = fields[:i]
+		}
+		pattern, err := ownersPattern(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, line, err)
+		}
+		o.rules = append(o.rules, ownersRule{pattern: pattern, owners: fields[1:]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return o, nil
+}
+
+// ownersPattern returns the glob segments of a CODEOWNERS pattern, as
+// match takes them.
+func ownersPattern(glob string) ([]string, error) {
+	anchored := strings.Contains(strings.TrimSuffix(glob, "/"), "/")
+	glob = strings.Trim(glob, "/")
+	if glob == "" {
+		return nil, fmt.Errorf("pattern %q matches nothing", "/")
+	}
+	segments := strings.Split(glob, "/")
+	for _, s := range segments {
+		if _, err := path.Match(s, ""); err != nil {
+			return nil, fmt.Errorf("pattern %q: %w", glob, err)
+		}
+	}
+	if !anchored {
+		segments = append([]string{"**"}, segments...)
+	}
+	// A pattern matching a directory matches the files under it.
+	return append(segments, "**"), nil
+}
+
+// Of returns the owners of filename, from the last line of the file whose
+// pattern matches it. It returns nil for files outside of Dir and for
+// files without an owner.
+func (o *Owners) Of(filename string) []string {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return nil
+	}
+	rel, err := filepath.Rel(o.Dir, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return nil
+	}
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	for _, r := range slices.Backward(o.rules) {
+		if match(r.pattern, segments) {
+			return r.owners
+		}
+	}
+	return nil
+}
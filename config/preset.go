@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer"
+)
+
+// Presets are the names of the presets ApplyPreset takes:
+//
+//   - standard runs the checks that are not opt-in, with the naming and
+//     style checks as info, so that only findings that are bugs fail a run.
+//   - strict also runs the opt-in checks, such as dynamic, which reports
+//     errors created inline like err113 does, and wrapcheck, and fails a
+//     run on any finding.
+//   - teaching runs the checks that are not opt-in, explains each check
+//     under its first finding, and only fails a run on errors, for
+//     learning the patterns on an existing codebase.
+var Presets = []string{"standard", "strict", "teaching"}
+
+// styleChecks are the checks that report style rather than bugs.
+var styleChecks = []string{"errorsnew", "sentinelname", "typename"}
+
+// preset returns the configuration of the preset name.
+func preset(name string) (Config, bool) {
+	switch name {
+	case "standard":
+		c := Config{Severity: make(map[string]string)}
+		for _, check := range styleChecks {
+			c.Severity[check] = "info"
+		}
+		return c, true
+	case "strict":
+		c := Config{FailOn: "info"}
+		for _, check := range analyzer.Checks() {
+			if check.OptIn {
+				c.Enable = append(c.Enable, check.Name)
+			}
+		}
+		return c, true
+	case "teaching":
+		return Config{Explain: true, FailOn: "error"}, true
+	}
+	return Config{}, false
+}
+
+// ApplyPreset sets the settings of c from the preset name, one of Presets,
+// where c leaves them empty: the checks the preset enables are added to
+// those of c, severities are set for the checks c has none for, and the
+// fail-on level if c has none.
+func (c *Config) ApplyPreset(name string) error {
+	p, ok := preset(name)
+	if !ok {
+		return fmt.Errorf("unknown preset %q, want one of %s", name, strings.Join(Presets, ", "))
+	}
+	enable := slices.Clone(c.Enable)
+	for _, check := range p.Enable {
+		if !slices.Contains(enable, check) {
+			enable = append(enable, check)
+		}
+	}
+	c.Enable = enable
+	if len(p.Severity) > 0 {
+		severity := maps.Clone(p.Severity)
+		maps.Copy(severity, c.Severity)
+		c.Severity = severity
+	}
+	if c.FailOn == "" {
+		c.FailOn = p.FailOn
+	}
+	c.Explain = c.Explain || p.Explain
+	return c.Validate()
+}
@@ -0,0 +1,73 @@
+// Package api is the public API of the demo. It reads users from the
+// internal storage package, and translates the errors of the driver into
+// its own sentinels, the only errors its callers can match.
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kakkoyun/demo-error-lint/demos/apiboundary/internal/storage"
+)
+
+// Sentinel errors
+var (
+	ErrNotFound    = errors.New("user not found")
+	ErrUnavailable = errors.New("user store unavailable")
+)
+
+// User is a user of the API.
+type User struct {
+	ID, Name string
+}
+
+// Client reads users.
+type Client struct {
+	db *storage.DB
+}
+
+// NewClient returns a client of a database holding users, which maps the
+// IDs of users to their names.
+func NewClient(users map[string]string) *Client {
+	return &Client{db: &storage.DB{
+		Addr:   "db.internal:5432",
+		Tables: map[string]map[string]string{"users": users},
+	}}
+}
+
+// Disconnect simulates an outage of the database.
+func (c *Client) Disconnect() {
+	c.db.Down = true
+}
+
+// Method that hands the errors of the driver to its callers
+func (c *Client) GetUserLeaky(id string) (User, error) {
+	name, err := c.db.Get("users", id)
+	var nf *storage.NotFoundError
+	if errors.As(err, &nf) {
+		// ISSUE: Callers cannot import storage to match the error with
+		// errors.As, and no sentinel of this package matches it
+		return User{}, nf
+	}
+	if err != nil {
+		return User{}, err
+	}
+	return User{ID: id, Name: name}, nil
+}
+
+// Correct way: the errors of the driver are matched here and translated
+// into the sentinels of the package, with the context of the call
+func (c *Client) GetUser(id string) (User, error) {
+	name, err := c.db.Get("users", id)
+	var nf *storage.NotFoundError
+	switch {
+	case errors.As(err, &nf):
+		return User{}, fmt.Errorf("getting user %s: %w", id, ErrNotFound)
+	case err != nil:
+		// The message of the driver error is kept for the logs, but not the
+		// error itself, whose types callers must not depend on.
+		//errlint:ignore errorf the driver error is not part of the API
+		return User{}, fmt.Errorf("getting user %s: %v: %w", id, err, ErrUnavailable)
+	}
+	return User{ID: id, Name: name}, nil
+}
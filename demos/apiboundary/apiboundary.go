@@ -0,0 +1,52 @@
+// Package apiboundary demonstrates errors crossing the boundary of an API.
+// Package api reads users through a database driver in an internal
+// package, whose error types its callers, such as this package, cannot
+// import. Passing the errors of the driver on leaves callers with nothing
+// to match but the message; translating them into the sentinels of
+// package api gives callers errors they can match, and keeps the driver
+// free to change.
+package apiboundary
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kakkoyun/demo-error-lint/demos/apiboundary/api"
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	client := api.NewClient(map[string]string{"1": "Ada"})
+
+	// ISSUE: The error is a *storage.NotFoundError, which this package
+	// cannot name, and no sentinel of package api matches it
+	_, err := client.GetUserLeaky("7")
+	fmt.Fprintf(w, "Leaky error: %v (%T)\n", err, err)
+	fmt.Fprintf(w, "errors.Is(err, api.ErrNotFound): %t\n", errors.Is(err, api.ErrNotFound))
+
+	// Correct way: the error matches the sentinel of package api
+	_, err = client.GetUser("7")
+	fmt.Fprintf(w, "Translated error: %v\n", err)
+	fmt.Fprintf(w, "errors.Is(err, api.ErrNotFound): %t\n", errors.Is(err, api.ErrNotFound))
+
+	user, err := client.GetUser("1")
+	fmt.Fprintf(w, "Found user %s: %s, error: %v\n", user.ID, user.Name, err)
+
+	// The details of an outage stay in the message, for the logs
+	client.Disconnect()
+	_, err = client.GetUser("1")
+	fmt.Fprintf(w, "Outage: %v\n", err)
+	fmt.Fprintf(w, "errors.Is(err, api.ErrUnavailable): %t\n", errors.Is(err, api.ErrUnavailable))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "api-boundary",
+	Title:   "Errors at API boundaries",
+	Buggy:   "if errors.As(err, &nf) {\n\treturn User{}, nf\n}",
+	Correct: "if errors.As(err, &nf) {\n\treturn User{}, fmt.Errorf(\"getting user %s: %w\", id, ErrNotFound)\n}",
+	Explain: "Go only lets the packages of its tree import an internal package, so callers of an API cannot name the error types of its internal driver: errors.As needs a target of the type, and they are left parsing messages. Match the errors of the driver inside the API and return its own exported sentinels, wrapped with the context of the call. The internaltype check reports exported functions returning internal error types.",
+	Run:     Run,
+}
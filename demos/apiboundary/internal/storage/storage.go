@@ -0,0 +1,56 @@
+// Package storage is a fake database driver, internal to the demo. Its
+// errors carry the details of the driver in types that only the packages
+// under demos/apiboundary can import.
+package storage
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NotFoundError is returned by DB.Get for keys without a row.
+type NotFoundError struct {
+	Table, Key string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("storage: no row in %s with key %q", e.Table, e.Key)
+}
+
+// ConnError is returned by DB.Get when the connection to the database
+// fails.
+type ConnError struct {
+	Addr string
+	Err  error
+}
+
+func (e *ConnError) Error() string {
+	return fmt.Sprintf("storage: connection to %s: %v", e.Addr, e.Err)
+}
+
+func (e *ConnError) Unwrap() error {
+	return e.Err
+}
+
+// ErrConnReset is the cause of the ConnError of a database that is down.
+var ErrConnReset = errors.New("connection reset by peer")
+
+// DB is a database of tables of rows, indexed by key.
+type DB struct {
+	Addr   string
+	Tables map[string]map[string]string
+	// Down makes every call fail with a ConnError.
+	Down bool
+}
+
+// Get returns the row of table with key.
+func (db *DB) Get(table, key string) (string, error) {
+	if db.Down {
+		return "", &ConnError{Addr: db.Addr, Err: ErrConnReset}
+	}
+	row, ok := db.Tables[table][key]
+	if !ok {
+		return "", &NotFoundError{Table: table, Key: key}
+	}
+	return row, nil
+}
@@ -0,0 +1,52 @@
+// Package assertion demonstrates type assertions on errors instead of
+// errors.As, which only see the outermost error of a chain.
+package assertion
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Custom error types for demonstration
+type NotFoundError struct {
+	Item string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s not found", e.Item)
+}
+
+// Function that returns a custom error
+func findItem(item string) error {
+	return &NotFoundError{Item: item}
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	err := findItem("document")
+
+	// ISSUE: Type assertion instead of errors.As
+	notFoundErr, ok := err.(*NotFoundError)
+	if ok {
+		fmt.Fprintf(w, "Not found error: %s\n", notFoundErr.Item)
+	}
+
+	// Correct way - FIX: use *NotFoundError instead of NotFoundError
+	var notFound *NotFoundError
+	if errors.As(err, &notFound) {
+		fmt.Fprintf(w, "Not found error (correctly checked): %s\n", notFound.Item)
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "assertions",
+	Title:   "Type assertions on errors instead of errors.As",
+	Buggy:   "notFoundErr, ok := err.(*NotFoundError)",
+	Correct: "var notFound *NotFoundError\nok := errors.As(err, &notFound)",
+	Explain: "A type assertion only sees the outermost error. errors.As walks the chain and sets the target to the first error of the requested type.",
+	Run:     Run,
+}
@@ -0,0 +1,99 @@
+// Package astarget demonstrates reusing the target of errors.As. errors.As
+// only sets its target when it matches, so code that ignores its result and
+// checks the target instead acts on the error of an earlier match once the
+// target is reused, here in the next iteration of a loop.
+package astarget
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Sentinel errors
+var ErrTimeout = errors.New("timeout")
+
+// NotFoundError is returned for files that do not exist.
+type NotFoundError struct {
+	Name string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s not found", e.Name)
+}
+
+// Function that fetches a file, which fails for some of them
+func fetch(name string) error {
+	switch name {
+	case "a.txt", "c.txt":
+		return fmt.Errorf("fetching: %w", &NotFoundError{Name: name})
+	case "b.txt":
+		return fmt.Errorf("fetching %s: %w", name, ErrTimeout)
+	}
+	return nil
+}
+
+// ISSUE: The target is declared once for the loop and the result of
+// errors.As is ignored, so when it does not match, nf still holds the
+// error of an earlier iteration
+func reportStale(names []string) []string {
+	var lines []string
+	var nf *NotFoundError
+	for _, name := range names {
+		err := fetch(name)
+		if err == nil {
+			continue
+		}
+		errors.As(err, &nf)
+		if nf != nil {
+			lines = append(lines, fmt.Sprintf("%s: missing %s", name, nf.Name))
+		} else {
+			lines = append(lines, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	return lines
+}
+
+// Correct way: the result of errors.As says whether this error matched,
+// and the target starts from nil in each iteration
+func report(names []string) []string {
+	var lines []string
+	for _, name := range names {
+		err := fetch(name)
+		if err == nil {
+			continue
+		}
+		var nf *NotFoundError
+		if errors.As(err, &nf) {
+			lines = append(lines, fmt.Sprintf("%s: missing %s", name, nf.Name))
+		} else {
+			lines = append(lines, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	return lines
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	names := []string{"a.txt", "b.txt", "ok.txt", "c.txt", "b.txt"}
+
+	// ISSUE: b.txt timed out, but is reported missing, as a.txt and c.txt
+	// were before it
+	fmt.Fprintf(w, "Reused target:\n  %s\n", strings.Join(reportStale(names), "\n  "))
+
+	// Correct way: each error is reported as what it is
+	fmt.Fprintf(w, "Checked result:\n  %s\n", strings.Join(report(names), "\n  "))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "as-target",
+	Title:   "Reused errors.As targets",
+	Buggy:   "errors.As(err, &nf)\nif nf != nil {",
+	Correct: "var nf *NotFoundError\nif errors.As(err, &nf) {",
+	Explain: "errors.As only sets its target when it matches, and leaves it alone otherwise. Checking the target instead of the result works for the first error, but once the target is reused, for a second error or in a loop, a failed match leaves the error of the earlier match in place. Check the result of errors.As, and declare the target where it is used.",
+	Run:     Run,
+}
@@ -0,0 +1,106 @@
+// Package batch demonstrates validating a batch of inputs and reporting
+// all of their failures at once with errcollect, instead of returning at
+// the first one.
+package batch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/errcollect"
+)
+
+// Sentinel errors
+var (
+	ErrEmptyName     = errors.New("name is empty")
+	ErrBadEmail      = errors.New("email is invalid")
+	ErrEmailTaken    = errors.New("email is taken")
+	ErrDirectoryDown = errors.New("user directory unavailable")
+)
+
+type signup struct {
+	Name, Email string
+}
+
+var signups = []signup{
+	{Name: "Ada", Email: "ada@example.com"},
+	{Name: "", Email: "nobody@example.com"},
+	{Name: "Grace", Email: "grace.example.com"},
+	{Name: "Linus", Email: "taken@example.com"},
+	{Name: "Ken", Email: "ken@example.org"},
+	{Name: "Rob", Email: "rob@example.org"},
+}
+
+// Function that checks a signup on its own
+func validate(i int, s signup) error {
+	switch {
+	case s.Name == "":
+		return fmt.Errorf("signup %d: %w", i, ErrEmptyName)
+	case !strings.Contains(s.Email, "@"):
+		return fmt.Errorf("signup %d: %q: %w", i, s.Email, ErrBadEmail)
+	}
+	return nil
+}
+
+// Function that asks a slow user directory whether the email of a signup
+// is free; the directory for example.org is down
+func checkFree(i int, s signup) error {
+	switch {
+	case strings.HasSuffix(s.Email, "@example.org"):
+		return ErrDirectoryDown
+	case strings.HasPrefix(s.Email, "taken@"):
+		return fmt.Errorf("signup %d: %q: %w", i, s.Email, ErrEmailTaken)
+	}
+	return nil
+}
+
+// ISSUE: Returning the first error hides the others, so the user fixes one
+// signup per attempt
+func validateFirst(signups []signup) error {
+	for i, s := range signups {
+		if err := validate(i, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Correct way: collect every failure, checking the directory concurrently
+func validateAll(signups []signup) error {
+	var c errcollect.Collector
+	for i, s := range signups {
+		c.Add(validate(i, s))
+		c.Go(func() error {
+			return checkFree(i, s)
+		})
+	}
+	return c.Err()
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	// ISSUE: Only the first failure is reported
+	err := validateFirst(signups)
+	fmt.Fprintf(w, "First failure only: %v\n", err)
+
+	// Correct way: every failure is reported, in the order of the signups,
+	// with the unavailable directory reported once
+	err = validateAll(signups)
+	fmt.Fprintf(w, "All failures:\n%v\n", err)
+	for _, sentinel := range []error{ErrEmptyName, ErrBadEmail, ErrEmailTaken, ErrDirectoryDown} {
+		fmt.Fprintf(w, "errors.Is(err, %q): %t\n", sentinel, errors.Is(err, sentinel))
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "batch",
+	Title:   "Reporting every failure of a batch",
+	Buggy:   "if err := validate(i, s); err != nil {\n\treturn err\n}",
+	Correct: "c.Add(validate(i, s))\n...\nreturn c.Err()",
+	Explain: "Returning the first error of a batch makes users fix one input per attempt. An errcollect.Collector gathers every failure in order, including those of concurrent checks, and joins them so errors.Is still matches each one.",
+	Run:     Run,
+}
@@ -0,0 +1,157 @@
+package bench
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kakkoyun/demo-error-lint/errkit"
+)
+
+// Sentinel errors
+var (
+	ErrNotFound = errors.New("not found")
+	ErrOther    = errors.New("other")
+)
+
+type NotFoundError struct {
+	Item string
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Item + " not found"
+}
+
+// Function that wraps err depth times
+func wrapN(err error, depth int) error {
+	for i := range depth {
+		err = fmt.Errorf("layer %d: %w", i, err)
+	}
+	return err
+}
+
+// Function that wraps err depth times with errkit.Wrap
+func wrapStackN(err error, depth int) error {
+	for i := range depth {
+		err = errkit.Wrapf(err, "layer %d", i)
+	}
+	return err
+}
+
+// sink keeps the compiler from optimizing the measured code away.
+var sink any
+
+// BenchmarkIs measures errors.Is over chains of different depths. == is
+// only correct for the unwrapped error, so it is the baseline.
+func BenchmarkIs(b *testing.B) {
+	unwrapped := error(ErrNotFound)
+	depth10 := wrapN(ErrNotFound, 10)
+	depth100 := wrapN(ErrNotFound, 100)
+	b.Run("==/unwrapped", func(b *testing.B) {
+		for b.Loop() {
+			sink = unwrapped == ErrNotFound
+		}
+	})
+	b.Run("unwrapped", func(b *testing.B) {
+		for b.Loop() {
+			sink = errors.Is(unwrapped, ErrNotFound)
+		}
+	})
+	b.Run("depth10", func(b *testing.B) {
+		for b.Loop() {
+			sink = errors.Is(depth10, ErrNotFound)
+		}
+	})
+	b.Run("depth100", func(b *testing.B) {
+		for b.Loop() {
+			sink = errors.Is(depth100, ErrNotFound)
+		}
+	})
+	b.Run("miss/depth100", func(b *testing.B) {
+		for b.Loop() {
+			sink = errors.Is(depth100, ErrOther)
+		}
+	})
+}
+
+// BenchmarkAs measures errors.As against a type switch, which is only
+// correct for the unwrapped error.
+func BenchmarkAs(b *testing.B) {
+	typed := error(&NotFoundError{Item: "document"})
+	typed10 := wrapN(typed, 10)
+	b.Run("switch/unwrapped", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			switch e := typed.(type) {
+			case *NotFoundError:
+				sink = e
+			}
+		}
+	})
+	b.Run("unwrapped", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			var nf *NotFoundError
+			sink = errors.As(typed, &nf)
+		}
+	})
+	b.Run("depth10", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			var nf *NotFoundError
+			sink = errors.As(typed10, &nf)
+		}
+	})
+}
+
+// BenchmarkErrorf measures fmt.Errorf with %w against %v, and a chain of
+// ten fmt.Errorf calls against one of ten errkit.Wrapf calls.
+func BenchmarkErrorf(b *testing.B) {
+	b.Run("%v", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			sink = fmt.Errorf("loading: %v", ErrNotFound)
+		}
+	})
+	b.Run("%w", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			sink = fmt.Errorf("loading: %w", ErrNotFound)
+		}
+	})
+	b.Run("depth10", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			sink = wrapN(ErrNotFound, 10)
+		}
+	})
+	b.Run("errkit/depth10", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			sink = wrapStackN(ErrNotFound, 10)
+		}
+	})
+}
+
+// BenchmarkErrkit measures lookups through chains of errkit wrappers.
+func BenchmarkErrkit(b *testing.B) {
+	stacked10 := wrapStackN(ErrNotFound, 10)
+	retry10 := wrapN(errkit.Retryable(ErrNotFound), 10)
+	b.Run("Is/depth10", func(b *testing.B) {
+		for b.Loop() {
+			sink = errors.Is(stacked10, ErrNotFound)
+		}
+	})
+	b.Run("IsRetryable/depth10", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			sink = errkit.IsRetryable(retry10)
+		}
+	})
+	b.Run("StackTrace/depth10", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			sink = errkit.StackTrace(stacked10)
+		}
+	})
+}
@@ -0,0 +1,124 @@
+// Package boxed demonstrates errors stored in interfaces that do not
+// compare as they seem to: a nil *FieldError stored in a map of errors is
+// not a nil error, and an error received as any and compared with ==
+// misses the sentinel once it is wrapped.
+package boxed
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Sentinel errors
+var ErrCanceled = errors.New("canceled")
+
+// FieldError is returned for fields with an invalid value.
+type FieldError struct {
+	Field  string
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+}
+
+// Function that validates a field, returning a nil *FieldError if it is
+// valid
+func validate(field, value string) *FieldError {
+	if strings.TrimSpace(value) == "" {
+		return &FieldError{Field: field, Reason: "must not be empty"}
+	}
+	return nil
+}
+
+// ISSUE: The map stores every result, and a nil *FieldError in an error
+// is not a nil error, so every field counts as invalid
+func invalidFieldsBoxed(form map[string]string) []string {
+	results := make(map[string]error)
+	for field, value := range form {
+		results[field] = validate(field, value)
+	}
+	var invalid []string
+	for field, err := range results {
+		if err != nil {
+			invalid = append(invalid, field)
+		}
+	}
+	slices.Sort(invalid)
+	return invalid
+}
+
+// Correct way: check the pointer before storing it in an interface
+func invalidFields(form map[string]string) []string {
+	results := make(map[string]error)
+	for field, value := range form {
+		if fe := validate(field, value); fe != nil {
+			results[field] = fe
+		}
+	}
+	var invalid []string
+	for field := range results {
+		invalid = append(invalid, field)
+	}
+	slices.Sort(invalid)
+	return invalid
+}
+
+// Function that runs jobs and sends their results as values, an error for
+// those that failed
+func runJobs(names []string) <-chan any {
+	results := make(chan any, len(names))
+	for _, name := range names {
+		if strings.HasPrefix(name, "stale-") {
+			results <- fmt.Errorf("job %s: %w", name, ErrCanceled)
+			continue
+		}
+		results <- "done " + name
+	}
+	close(results)
+	return results
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	form := map[string]string{"name": "Ada", "email": "", "city": "London"}
+
+	// ISSUE: only the email is empty, but all three fields are reported
+	fmt.Fprintf(w, "Invalid fields, nil pointers stored: %v\n", invalidFieldsBoxed(form))
+
+	// Correct way: only the field that failed is in the map
+	fmt.Fprintf(w, "Invalid fields, checked first: %v\n", invalidFields(form))
+
+	// ISSUE: == compares the value with the sentinel, which the job wrapped
+	canceled := 0
+	for v := range runJobs([]string{"report", "stale-export", "stale-import"}) {
+		if v == ErrCanceled {
+			canceled++
+		}
+	}
+	fmt.Fprintf(w, "Canceled jobs, with ==: %d\n", canceled)
+
+	// Correct way: assert the value to error and use errors.Is
+	canceled = 0
+	for v := range runJobs([]string{"report", "stale-export", "stale-import"}) {
+		if err, ok := v.(error); ok && errors.Is(err, ErrCanceled) {
+			canceled++
+		}
+	}
+	fmt.Fprintf(w, "Canceled jobs, with errors.Is: %d\n", canceled)
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "boxed-errors",
+	Title:   "Errors boxed in interfaces",
+	Buggy:   "results[field] = validate(field, value) // returns *FieldError\nif results[field] != nil {",
+	Correct: "if fe := validate(field, value); fe != nil {\n\tresults[field] = fe\n}",
+	Explain: "An interface is nil only if it holds nothing. A nil *FieldError stored in an error, or in a map of errors, is an interface holding a nil pointer, which is not nil, so the nil check reports an error that does not exist. Check the pointer before storing it, or return error from the start. An error received as any and compared with == is compared as a value, so a wrapped error does not match; assert it to error and use errors.Is.",
+	Run:     Run,
+}
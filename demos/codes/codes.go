@@ -0,0 +1,85 @@
+// Package codes demonstrates attaching error codes with errcode.WithCode
+// and branching on errcode.CodeOf, instead of type assertions and
+// sentinel comparisons in every layer.
+package codes
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errtree"
+)
+
+// Custom error types for demonstration
+type NotFoundError struct {
+	Item string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s not found", e.Item)
+}
+
+func (e *NotFoundError) Code() errcode.Code {
+	return errcode.NotFound
+}
+
+// Sentinel errors
+var (
+	ErrInvalidInput = errcode.WithCode(errors.New("invalid input"), errcode.InvalidInput)
+)
+
+// Function that returns an error
+func fetchData() error {
+	return ErrInvalidInput
+}
+
+// Function that returns a wrapped error
+func processData() error {
+	err := fetchData()
+	if err != nil {
+		// ISSUE: Using %v instead of %w in fmt.Errorf
+		return fmt.Errorf("failed to process data: %v", err)
+	}
+	return nil
+}
+
+// Function that returns a custom error
+func findItem(item string) error {
+	return &NotFoundError{Item: item}
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	// ISSUE: processData formats the error with %v, which drops its code
+	fmt.Fprintln(w, "Code of processData error:", errcode.CodeOf(processData()))
+
+	// Correct way: codes survive %w wrapping, so callers branch on the code
+	// instead of asserting on types or comparing with sentinels
+	for _, err := range []error{
+		fmt.Errorf("loading: %w", fetchData()),
+		fmt.Errorf("loading: %w", findItem("document")),
+	} {
+		fmt.Fprintln(w, errtree.Chain(err))
+		switch errcode.CodeOf(err) {
+		case errcode.InvalidInput:
+			fmt.Fprintln(w, "Rejected input:", err)
+		case errcode.NotFound:
+			fmt.Fprintln(w, "Missing item:", err)
+		default:
+			fmt.Fprintln(w, "Unexpected error:", err)
+		}
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "codes",
+	Title:   "Branching on error codes",
+	Buggy:   "if _, ok := err.(*NotFoundError); ok {",
+	Correct: "if errcode.CodeOf(err) == errcode.NotFound {",
+	Explain: "Checking concrete types couples callers to the errors of every layer. A code carried in the chain, read with errcode.CodeOf, gives them one thing to branch on.",
+	Run:     Run,
+}
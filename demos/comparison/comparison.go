@@ -0,0 +1,50 @@
+// Package comparison demonstrates comparing errors with == instead of
+// errors.Is, which stops matching once the error is wrapped.
+package comparison
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Sentinel errors
+var (
+	ErrInvalidInput = errors.New("invalid input")
+)
+
+// Function that returns an error
+func fetchData() error {
+	return ErrInvalidInput
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	err := fetchData()
+
+	// ISSUE: Direct comparison instead of errors.Is
+	if err == ErrInvalidInput {
+		fmt.Fprintln(w, "Invalid input detected")
+	}
+
+	// Correct way
+	if errors.Is(err, ErrInvalidInput) {
+		fmt.Fprintln(w, "Invalid input detected (correctly checked)")
+	}
+
+	// ISSUE: Once the error is wrapped, only errors.Is still matches
+	err = fmt.Errorf("loading: %w", err)
+	fmt.Fprintf(w, "After wrapping: == matches %t, errors.Is matches %t\n", err == ErrInvalidInput, errors.Is(err, ErrInvalidInput))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "comparisons",
+	Title:   "Comparing errors with == instead of errors.Is",
+	Buggy:   "if err == ErrInvalidInput {",
+	Correct: "if errors.Is(err, ErrInvalidInput) {",
+	Explain: "== only matches the error itself. Once a caller wraps it with fmt.Errorf and %w, the comparison fails; errors.Is walks the whole chain.",
+	Run:     Run,
+}
@@ -0,0 +1,94 @@
+// Package contexterr demonstrates matching context.Canceled and
+// context.DeadlineExceeded after they crossed wrapping layers.
+package contexterr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Function that wraps the context error like most libraries do
+func loadReport(ctx context.Context) error {
+	<-ctx.Done()
+	return fmt.Errorf("loading report: %w", ctx.Err())
+}
+
+// Function that calls a slow server with a client timeout
+func fetchSlowPage(w io.Writer) error {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Timeout: 50 * time.Millisecond}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		// The client returns a *url.Error wrapping the timeout
+		fmt.Fprintf(w, "HTTP client error type: %T\n", err)
+		return fmt.Errorf("fetching page: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := loadReport(ctx)
+
+	// ISSUE: Direct comparison fails once the context error is wrapped
+	if err == context.Canceled {
+		fmt.Fprintln(w, "Canceled")
+	}
+
+	// Correct way
+	if errors.Is(err, context.Canceled) {
+		fmt.Fprintln(w, "Canceled (correctly checked)")
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	err = loadReport(ctx)
+
+	// ISSUE: Direct comparison fails once the context error is wrapped
+	if err == context.DeadlineExceeded {
+		fmt.Fprintln(w, "Deadline exceeded")
+	}
+
+	// Correct way
+	if errors.Is(err, context.DeadlineExceeded) {
+		fmt.Fprintln(w, "Deadline exceeded (correctly checked)")
+	}
+
+	// HTTP client timeouts match context.DeadlineExceeded through the
+	// *url.Error the client returns
+	err = fetchSlowPage(w)
+	fmt.Fprintf(w, "HTTP timeout matches context.DeadlineExceeded: %t\n", errors.Is(err, context.DeadlineExceeded))
+
+	// Correct way: errors.As extracts the *url.Error for its details
+	var netErr interface{ Timeout() bool }
+	if errors.As(err, &netErr) {
+		fmt.Fprintf(w, "HTTP error reports a timeout: %t\n", netErr.Timeout())
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "context",
+	Title:   "Wrapped context errors",
+	Buggy:   "if err == context.DeadlineExceeded {",
+	Correct: "if errors.Is(err, context.DeadlineExceeded) {",
+	Explain: "net/http and database drivers wrap context.Canceled and context.DeadlineExceeded, so == misses them. errors.Is finds them, and context.Cause tells you why.",
+	Run:     Run,
+}
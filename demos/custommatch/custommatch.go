@@ -0,0 +1,113 @@
+// Package custommatch demonstrates error types that customize how errors.Is
+// and errors.As match them, and errors.As calls with an interface target.
+package custommatch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Sentinel errors
+var ErrTemporary = errors.New("temporary failure")
+
+// RateLimitError matches ErrTemporary through its Is method, so
+// errors.Is(err, ErrTemporary) succeeds although err != ErrTemporary.
+type RateLimitError struct {
+	Limit int
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit of %d requests exceeded", e.Limit)
+}
+
+// Is compares the target with ==, which is correct here: errors.Is calls Is
+// for each error in the chain with its original, unwrapped target.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrTemporary
+}
+
+// Temporary reports that the request can be retried.
+func (e *RateLimitError) Temporary() bool {
+	return true
+}
+
+// StatusError matches any other StatusError with the same code, so callers
+// can check errors.Is(err, &StatusError{Code: 404}) without comparing
+// pointers.
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status %d", e.Code)
+}
+
+func (e *StatusError) Is(target error) bool {
+	t, ok := target.(*StatusError)
+	return ok && t.Code == e.Code
+}
+
+// LegacyError converts itself into a StatusError through its As method, so
+// errors.As(err, &statusErr) succeeds although err holds no *StatusError.
+type LegacyError struct {
+	Msg string
+}
+
+func (e *LegacyError) Error() string {
+	return e.Msg
+}
+
+func (e *LegacyError) As(target any) bool {
+	if t, ok := target.(**StatusError); ok {
+		*t = &StatusError{Code: 500}
+		return true
+	}
+	return false
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	err := fmt.Errorf("fetching page: %w", &RateLimitError{Limit: 100})
+
+	// ISSUE: Direct comparison ignores the custom Is method
+	if err == ErrTemporary {
+		fmt.Fprintln(w, "Temporary failure")
+	}
+
+	// Correct way: errors.Is calls RateLimitError.Is
+	if errors.Is(err, ErrTemporary) {
+		fmt.Fprintln(w, "Temporary failure (matched through Is method)")
+	}
+
+	// Correct way: errors.As with an interface target matches any error in
+	// the chain that implements the interface, whatever its concrete type
+	var temporary interface{ Temporary() bool }
+	if errors.As(err, &temporary) {
+		fmt.Fprintf(w, "Retryable: %t\n", temporary.Temporary())
+	}
+
+	// Value-based matching: a different *StatusError with the same code
+	err = fmt.Errorf("loading user: %w", &StatusError{Code: 404})
+	fmt.Fprintf(w, "Matches status 404: %t\n", errors.Is(err, &StatusError{Code: 404}))
+	fmt.Fprintf(w, "Matches status 500: %t\n", errors.Is(err, &StatusError{Code: 500}))
+
+	// Conversion-based matching through the As method
+	err = fmt.Errorf("calling legacy API: %w", &LegacyError{Msg: "backend exploded"})
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		fmt.Fprintf(w, "Legacy error as status: %d\n", statusErr.Code)
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "custom-match",
+	Title:   "Custom Is and As methods",
+	Buggy:   "if err == ErrTemporary {",
+	Correct: "if errors.Is(err, ErrTemporary) {",
+	Explain: "An Is or As method lets an error match targets other than itself, such as a temporary error matching ErrTemporary. Only errors.Is and errors.As call these methods.",
+	Run:     Run,
+}
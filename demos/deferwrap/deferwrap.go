@@ -0,0 +1,110 @@
+// Package deferwrap demonstrates wrapping the error of a function once, in
+// a deferred function that changes its named error result, and the two
+// ways the pattern goes wrong: wrapping an err that shadows the result, so
+// the wrapped error is never returned, and wrapping the result without
+// checking it, so the function fails when it succeeds. errlint reports both
+// with the deferwrap check:
+//
+//	errlint -checks=deferwrap ./demos/deferwrap
+package deferwrap
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Sentinel errors
+var (
+	ErrDiskFull = errors.New("disk full")
+	ErrReadOnly = errors.New("read-only file system")
+)
+
+// file is an in-memory file whose writes and Close fail with the given
+// errors.
+type file struct {
+	data               []byte
+	writeErr, closeErr error
+}
+
+func (f *file) Write(p []byte) (int, error) {
+	if f.writeErr != nil {
+		return 0, f.writeErr
+	}
+	f.data = append(f.data, p...)
+	return len(p), nil
+}
+
+func (f *file) Close() error {
+	return f.closeErr
+}
+
+// ISSUE: The deferred function declares its own err, which shadows the
+// named result, so the error of Close is wrapped and then lost
+func saveShadowed(name string, f *file, report string) (err error) {
+	defer func() {
+		if err := f.Close(); err != nil {
+			err = fmt.Errorf("closing %s: %w", name, err)
+		}
+	}()
+	_, err = io.WriteString(f, report)
+	return err
+}
+
+// ISSUE: The deferred function wraps the result even when it is nil
+func saveUnchecked(name string, f *file, report string) (err error) {
+	defer func() {
+		err = fmt.Errorf("saving %s: %w", name, err)
+		_ = f.Close()
+	}()
+	_, err = io.WriteString(f, report)
+	return err
+}
+
+// Correct way: the deferred function joins the error of Close to the
+// named result and wraps it only if it is not nil
+func save(name string, f *file, report string) (err error) {
+	defer func() {
+		err = errors.Join(err, f.Close())
+		if err != nil {
+			err = fmt.Errorf("saving %s: %w", name, err)
+		}
+	}()
+	_, err = io.WriteString(f, report)
+	return err
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	const name, report = "report.txt", "all good\n"
+
+	// ISSUE: Close fails, but the caller is told the report was saved
+	err := saveShadowed(name, &file{closeErr: ErrDiskFull}, report)
+	fmt.Fprintf(w, "Shadowed, Close fails: %v\n", err)
+
+	// ISSUE: Nothing fails, but the caller gets an error wrapping nil
+	err = saveUnchecked(name, &file{}, report)
+	fmt.Fprintf(w, "Unchecked, nothing fails: %v\n", err)
+
+	// Correct way: success stays nil, and every failure is wrapped once
+	err = save(name, &file{}, report)
+	fmt.Fprintf(w, "Nothing fails: %v\n", err)
+
+	err = save(name, &file{closeErr: ErrDiskFull}, report)
+	fmt.Fprintf(w, "Close fails: %v, disk full: %t\n", err, errors.Is(err, ErrDiskFull))
+
+	err = save(name, &file{writeErr: ErrReadOnly, closeErr: ErrDiskFull}, report)
+	fmt.Fprintf(w, "Both fail, read-only: %t, disk full: %t\n", errors.Is(err, ErrReadOnly), errors.Is(err, ErrDiskFull))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "defer-wrap",
+	Title:   "Wrapping errors in a deferred function",
+	Buggy:   "defer func() {\n\terr = fmt.Errorf(\"saving %s: %w\", name, err)\n}()",
+	Correct: "defer func() {\n\tif err != nil {\n\t\terr = fmt.Errorf(\"saving %s: %w\", name, err)\n\t}\n}()",
+	Explain: "A deferred function can wrap every error a function returns through its named result, but only if it changes that result and only when it is not nil. Wrapping nil makes a successful call fail, and wrapping an err that shadows the result returns nothing.",
+	Run:     Run,
+}
@@ -0,0 +1,19 @@
+// Package demo defines the demos the demo-error-lint command runs. Each
+// demo package exports a Demo value, which the demos package lists.
+package demo
+
+import "io"
+
+// Demo is a demo the command can run, with the code it is about.
+type Demo struct {
+	// Name is the name of the demo on the command line.
+	Name  string
+	Title string
+	// Buggy is the anti-pattern the demo shows, and Correct its fix.
+	Buggy   string
+	Correct string
+	// Explain tells why Correct fixes Buggy.
+	Explain string
+	// Run prints the output of the demo to w.
+	Run func(w io.Writer)
+}
@@ -0,0 +1,122 @@
+// Package demos lists the demos of the demo-error-lint command. To add a
+// demo, write a package under demos that exports a demo.Demo value named
+// Demo and add it to All.
+package demos
+
+import (
+	"github.com/kakkoyun/demo-error-lint/demos/apiboundary"
+	"github.com/kakkoyun/demo-error-lint/demos/assertion"
+	"github.com/kakkoyun/demo-error-lint/demos/astarget"
+	"github.com/kakkoyun/demo-error-lint/demos/batch"
+	"github.com/kakkoyun/demo-error-lint/demos/bench"
+	"github.com/kakkoyun/demo-error-lint/demos/boxed"
+	"github.com/kakkoyun/demo-error-lint/demos/codes"
+	"github.com/kakkoyun/demo-error-lint/demos/comparison"
+	"github.com/kakkoyun/demo-error-lint/demos/contexterr"
+	"github.com/kakkoyun/demo-error-lint/demos/custommatch"
+	"github.com/kakkoyun/demo-error-lint/demos/deferwrap"
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/demos/dynamic"
+	"github.com/kakkoyun/demo-error-lint/demos/errno"
+	"github.com/kakkoyun/demo-error-lint/demos/errortext"
+	"github.com/kakkoyun/demo-error-lint/demos/fields"
+	"github.com/kakkoyun/demo-error-lint/demos/goroutines"
+	"github.com/kakkoyun/demo-error-lint/demos/grpcstatus"
+	"github.com/kakkoyun/demo-error-lint/demos/httpproblem"
+	"github.com/kakkoyun/demo-error-lint/demos/joinwrap"
+	"github.com/kakkoyun/demo-error-lint/demos/jsonerrors"
+	"github.com/kakkoyun/demo-error-lint/demos/metrics"
+	"github.com/kakkoyun/demo-error-lint/demos/middleware"
+	"github.com/kakkoyun/demo-error-lint/demos/multierror"
+	"github.com/kakkoyun/demo-error-lint/demos/multiwrap"
+	"github.com/kakkoyun/demo-error-lint/demos/neterrors"
+	"github.com/kakkoyun/demo-error-lint/demos/oserrors"
+	"github.com/kakkoyun/demo-error-lint/demos/pagination"
+	"github.com/kakkoyun/demo-error-lint/demos/pipeline"
+	"github.com/kakkoyun/demo-error-lint/demos/ratelimit"
+	"github.com/kakkoyun/demo-error-lint/demos/readfull"
+	"github.com/kakkoyun/demo-error-lint/demos/reassign"
+	"github.com/kakkoyun/demo-error-lint/demos/recovery"
+	"github.com/kakkoyun/demo-error-lint/demos/registry"
+	"github.com/kakkoyun/demo-error-lint/demos/result"
+	"github.com/kakkoyun/demo-error-lint/demos/retry"
+	"github.com/kakkoyun/demo-error-lint/demos/sametext"
+	"github.com/kakkoyun/demo-error-lint/demos/shadow"
+	"github.com/kakkoyun/demo-error-lint/demos/specialcases"
+	"github.com/kakkoyun/demo-error-lint/demos/sqlerrors"
+	"github.com/kakkoyun/demo-error-lint/demos/stacktrace"
+	"github.com/kakkoyun/demo-error-lint/demos/stringmatch"
+	"github.com/kakkoyun/demo-error-lint/demos/structlog"
+	"github.com/kakkoyun/demo-error-lint/demos/swallow"
+	"github.com/kakkoyun/demo-error-lint/demos/switchstmt"
+	"github.com/kakkoyun/demo-error-lint/demos/thirdparty"
+	"github.com/kakkoyun/demo-error-lint/demos/timeouts"
+	"github.com/kakkoyun/demo-error-lint/demos/tracing"
+	"github.com/kakkoyun/demo-error-lint/demos/usermessage"
+	"github.com/kakkoyun/demo-error-lint/demos/wrapcheck"
+	"github.com/kakkoyun/demo-error-lint/demos/wrapping"
+)
+
+// All holds the demos in the order the command lists and runs them.
+var All = []demo.Demo{
+	comparison.Demo,
+	assertion.Demo,
+	switchstmt.Demo,
+	wrapping.Demo,
+	specialcases.Demo,
+	stringmatch.Demo,
+	multierror.Demo,
+	multiwrap.Demo,
+	custommatch.Demo,
+	contexterr.Demo,
+	oserrors.Demo,
+	stacktrace.Demo,
+	registry.Demo,
+	codes.Demo,
+	httpproblem.Demo,
+	grpcstatus.Demo,
+	retry.Demo,
+	neterrors.Demo,
+	fields.Demo,
+	dynamic.Demo,
+	wrapcheck.Demo,
+	deferwrap.Demo,
+	goroutines.Demo,
+	middleware.Demo,
+	sqlerrors.Demo,
+	batch.Demo,
+	thirdparty.Demo,
+	errortext.Demo,
+	swallow.Demo,
+	shadow.Demo,
+	recovery.Demo,
+	result.Demo,
+	structlog.Demo,
+	tracing.Demo,
+	metrics.Demo,
+	errno.Demo,
+	jsonerrors.Demo,
+	pagination.Demo,
+	reassign.Demo,
+	sametext.Demo,
+	ratelimit.Demo,
+	readfull.Demo,
+	pipeline.Demo,
+	usermessage.Demo,
+	apiboundary.Demo,
+	astarget.Demo,
+	boxed.Demo,
+	timeouts.Demo,
+	joinwrap.Demo,
+	bench.Demo,
+}
+
+// Lookup returns the demo with the given name.
+func Lookup(name string) (demo.Demo, bool) {
+	for _, d := range All {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return demo.Demo{}, false
+}
@@ -0,0 +1,58 @@
+// Package dynamic demonstrates why errors created inline with errors.New
+// cannot be matched by callers and how package-level sentinels fix it.
+// errlint reports them with the opt-in dynamic check:
+//
+//	errlint -enable=dynamic ./demos/dynamic
+package dynamic
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// ErrEmptyName is returned, wrapped, for users without a name.
+var ErrEmptyName = errors.New("empty name")
+
+// ISSUE: Every call creates a new error, which callers cannot match
+func validateInline(name string) error {
+	if name == "" {
+		return errors.New("empty name")
+	}
+	return nil
+}
+
+// Correct way: return the sentinel, wrapped with the details of the call
+func validate(id int, name string) error {
+	if name == "" {
+		return fmt.Errorf("user %d: %w", id, ErrEmptyName)
+	}
+	return nil
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	err := validateInline("")
+
+	// ISSUE: A new error with the same message is a different error
+	fmt.Fprintf(w, "errors.Is(err, errors.New(\"empty name\")): %t\n", errors.Is(err, errors.New("empty name")))
+
+	// The sentinel does not match either, only the message does
+	fmt.Fprintf(w, "errors.Is(err, ErrEmptyName): %t, same message: %t\n", errors.Is(err, ErrEmptyName), err.Error() == ErrEmptyName.Error())
+
+	// Correct way
+	err = validate(42, "")
+	fmt.Fprintf(w, "%v: errors.Is(err, ErrEmptyName): %t\n", err, errors.Is(err, ErrEmptyName))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "dynamic",
+	Title:   "Errors created inline instead of sentinels",
+	Buggy:   `return errors.New("empty name")`,
+	Correct: `return fmt.Errorf("user %d: %w", id, ErrEmptyName)`,
+	Explain: "errors.New inside a function creates a new error on every call, so callers can only match its message. A package-level sentinel, wrapped with the details, can be matched with errors.Is.",
+	Run:     Run,
+}
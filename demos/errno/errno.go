@@ -0,0 +1,95 @@
+// Package errno demonstrates drilling into the error of a failed system
+// call: os.Open returns an *fs.PathError that records the operation and
+// the path and wraps the syscall.Errno the kernel returned, which
+// errors.As and errors.Is find through any wrapping, while matching the
+// message breaks with the platform and the locale.
+package errno
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Function that wraps the error of os.Open
+func readConfig(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	return f.Close()
+}
+
+// Function that wraps the error of opening a file for writing
+func writeConfig(name string) error {
+	f, err := os.OpenFile(name, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	return f.Close()
+}
+
+// describe prints what errors.As and errors.Is find in the chain of err.
+func describe(w io.Writer, err error) {
+	fmt.Fprintf(w, "  %s\n", strings.ReplaceAll(err.Error(), os.TempDir(), "$TMPDIR"))
+
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		fmt.Fprintf(w, "  *fs.PathError: op=%s path=%s\n", pathErr.Op, filepath.Base(pathErr.Path))
+	}
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		fmt.Fprintf(w, "  syscall.Errno: %v\n", errno)
+	}
+	// syscall.Errno implements Is for the io/fs sentinels, so portable code
+	// does not need to name the errno at all
+	fmt.Fprintf(w, "  errors.Is(err, fs.ErrNotExist): %t\n", errors.Is(err, fs.ErrNotExist))
+	fmt.Fprintf(w, "  errors.Is(err, fs.ErrPermission): %t\n", errors.Is(err, fs.ErrPermission))
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	missing := filepath.Join(os.TempDir(), "demo-error-lint-missing.yaml")
+	err := readConfig(missing)
+
+	// ISSUE: The message of an errno depends on the platform, so this
+	// matches on Linux and macOS but not on Windows
+	fmt.Fprintf(w, "strings.Contains(err.Error(), \"no such file\"): %t\n", strings.Contains(err.Error(), "no such file"))
+
+	// Correct way: errors.Is compares with the errno itself, which wrapping
+	// with %w preserves
+	fmt.Fprintf(w, "errors.Is(err, syscall.ENOENT): %t\n", errors.Is(err, syscall.ENOENT))
+
+	fmt.Fprintln(w, "Opening a missing file:")
+	describe(w, err)
+
+	fmt.Fprintln(w, "Opening a directory for writing:")
+	err = writeConfig(os.TempDir())
+	describe(w, err)
+	fmt.Fprintf(w, "  errors.Is(err, syscall.EISDIR): %t\n", errors.Is(err, syscall.EISDIR))
+
+	// os.Open fails this way on a file without read permission. The error
+	// is built here because the demo may run as root, which may read any
+	// file.
+	fmt.Fprintln(w, "Opening a file without read permission:")
+	err = fmt.Errorf("reading config: %w", &fs.PathError{Op: "open", Path: filepath.Join(os.TempDir(), "secret.yaml"), Err: syscall.EACCES})
+	describe(w, err)
+	fmt.Fprintf(w, "  errors.Is(err, syscall.EACCES): %t\n", errors.Is(err, syscall.EACCES))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "errno",
+	Title:   "Drilling into *fs.PathError and syscall.Errno",
+	Buggy:   `if strings.Contains(err.Error(), "no such file") {`,
+	Correct: "var errno syscall.Errno\nif errors.As(err, &errno) && errno == syscall.ENOENT {\nif errors.Is(err, fs.ErrNotExist) {",
+	Explain: "os.Open returns an *fs.PathError with the operation and the path, which wraps the syscall.Errno the kernel returned. errors.As finds both through %w wrapping, and errors.Is matches the errno itself or, portably, the io/fs sentinels that syscall.Errno implements Is for. The message of an errno differs between platforms.",
+	Run:     Run,
+}
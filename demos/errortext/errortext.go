@@ -0,0 +1,85 @@
+// Package errortext demonstrates why passing err.Error() to fmt.Errorf,
+// formatted with %s or concatenated into the format, loses the error: the
+// message reads the same, but the new error wraps nothing. errlint reports
+// both forms and fixes them to wrap the error with %w.
+package errortext
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Sentinel errors
+var (
+	ErrQuotaExceeded = errors.New("quota exceeded")
+)
+
+type bucket struct {
+	name string
+	used int
+}
+
+func upload(b bucket, size int) error {
+	if b.used+size > 100 {
+		return fmt.Errorf("%d of 100%% used: %w", b.used, ErrQuotaExceeded)
+	}
+	return nil
+}
+
+// ISSUE: The message of the error is formatted with %s
+func saveFormatted(b bucket, size int) error {
+	if err := upload(b, size); err != nil {
+		return fmt.Errorf("saving %d bytes to %s: %s", size, b.name, err.Error())
+	}
+	return nil
+}
+
+// ISSUE: The message of the error is concatenated into the format, where a
+// % in it would be read as a verb
+func saveConcatenated(b bucket, size int) error {
+	if err := upload(b, size); err != nil {
+		return fmt.Errorf("saving %d bytes to "+b.name+": "+err.Error(), size)
+	}
+	return nil
+}
+
+// Correct way: wrap the error itself with %w
+func save(b bucket, size int) error {
+	if err := upload(b, size); err != nil {
+		return fmt.Errorf("saving %d bytes to %s: %w", size, b.name, err)
+	}
+	return nil
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	b := bucket{name: "photos", used: 95}
+
+	// ISSUE: The message looks right, but the sentinel is gone
+	err := saveFormatted(b, 10)
+	fmt.Fprintf(w, "Formatted: %v\n", err)
+	fmt.Fprintf(w, "errors.Is(err, ErrQuotaExceeded): %t\n", errors.Is(err, ErrQuotaExceeded))
+
+	// ISSUE: The % of the message is read as a verb without an argument
+	err = saveConcatenated(b, 10)
+	fmt.Fprintf(w, "Concatenated: %v\n", err)
+	fmt.Fprintf(w, "errors.Is(err, ErrQuotaExceeded): %t\n", errors.Is(err, ErrQuotaExceeded))
+
+	// Correct way: same message, and the sentinel is still in the chain
+	err = save(b, 10)
+	fmt.Fprintf(w, "Wrapped: %v\n", err)
+	fmt.Fprintf(w, "errors.Is(err, ErrQuotaExceeded): %t\n", errors.Is(err, ErrQuotaExceeded))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "error-text",
+	Title:   "Passing err.Error() to fmt.Errorf",
+	Buggy:   "return fmt.Errorf(\"saving %d bytes to %s: %s\", size, b.name, err.Error())",
+	Correct: "return fmt.Errorf(\"saving %d bytes to %s: %w\", size, b.name, err)",
+	Explain: "err.Error() is only the text of the error, so the new error wraps nothing and errors.Is no longer finds the sentinel. Concatenated into the format, a % in the message even turns into a broken verb. Passing the error under %w prints the same message and keeps the chain.",
+	Run:     Run,
+}
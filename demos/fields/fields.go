@@ -0,0 +1,81 @@
+// Package fields demonstrates attaching key/value fields to errors with
+// errfields instead of declaring an error type for each set of fields.
+package fields
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/errfields"
+)
+
+// Sentinel errors
+var ErrNotFound = errors.New("not found")
+
+// ShelfNotFoundError exists only to carry the data of one failure
+type ShelfNotFoundError struct {
+	Item  string
+	Shelf int
+}
+
+func (e *ShelfNotFoundError) Error() string {
+	return fmt.Sprintf("%s not found on shelf %d", e.Item, e.Shelf)
+}
+
+// Function that needs a new type for every set of fields
+func findOnShelfLegacy(item string, shelf int) error {
+	return &ShelfNotFoundError{Item: item, Shelf: shelf}
+}
+
+// Function that attaches the fields to a sentinel instead
+func findOnShelf(item string, shelf int) error {
+	return errfields.With(ErrNotFound, "item", item, "shelf", shelf)
+}
+
+// Function that adds a field while wrapping
+func restock(item string) error {
+	if err := findOnShelf(item, 3); err != nil {
+		return errfields.With(fmt.Errorf("restocking: %w", err), "warehouse", "north")
+	}
+	return nil
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	// ISSUE: Reading the data requires knowing the exact error type
+	var shelfErr *ShelfNotFoundError
+	if err := findOnShelfLegacy("stapler", 3); errors.As(err, &shelfErr) {
+		fmt.Fprintf(w, "Legacy error: item %s, shelf %d\n", shelfErr.Item, shelfErr.Shelf)
+	}
+
+	// Correct way: the fields are readable from anywhere in the chain, and
+	// the error still matches its sentinel
+	err := restock("stapler")
+	item, _ := errfields.Get(err, "item")
+	fmt.Fprintf(w, "Error: %v, item: %v, matches ErrNotFound: %t\n", err, item, errors.Is(err, ErrNotFound))
+	fmt.Fprintf(w, "Fields: %v\n", errfields.Fields(err))
+
+	// The fields end up in structured logs
+	logger := slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Error("restock failed", errfields.Attr(err))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "fields",
+	Title:   "Structured error fields",
+	Buggy:   `&ShelfNotFoundError{Item: item, Shelf: shelf}`,
+	Correct: `errfields.With(ErrNotFound, "item", item, "shelf", shelf)`,
+	Explain: "An error type per combination of details does not scale and loses the details when wrapped with %v. errfields attaches key-value pairs that survive wrapping and log as slog attributes.",
+	Run:     Run,
+}
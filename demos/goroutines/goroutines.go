@@ -0,0 +1,127 @@
+// Package goroutines demonstrates how errors cross goroutine boundaries:
+// sent as values over a channel or returned by errgroup, they keep their
+// chains, so errors.Is still finds the sentinel behind any wrapping, while
+// err.Error() strings sent instead lose everything but the message.
+package goroutines
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Sentinel errors
+var ErrNotFound = errors.New("not found")
+
+// Function that fetches the user with the given id, which fails for id 0
+// at once and otherwise takes a while, unless ctx is done first
+func fetch(ctx context.Context, id int) error {
+	if id == 0 {
+		return fmt.Errorf("fetching user %d: %w", id, ErrNotFound)
+	}
+	select {
+	case <-time.After(10 * time.Millisecond):
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("fetching user %d: %w", id, ctx.Err())
+	}
+}
+
+// ISSUE: The workers send the messages of their errors, so the receiver
+// can only rebuild errors that match nothing
+func fetchAllMessages(ctx context.Context, ids []int) []error {
+	msgs := make(chan string)
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Go(func() {
+			if err := fetch(ctx, id); err != nil {
+				msgs <- err.Error()
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(msgs)
+	}()
+
+	var errs []error
+	for msg := range msgs {
+		errs = append(errs, errors.New(msg))
+	}
+	return errs
+}
+
+// Correct way: the workers send the errors themselves
+func fetchAll(ctx context.Context, ids []int) []error {
+	results := make(chan error)
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Go(func() {
+			if err := fetch(ctx, id); err != nil {
+				results <- err
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var errs []error
+	for err := range results {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Correct way: errgroup returns the first error and cancels the context of
+// the others, which stop early
+func fetchGroup(ctx context.Context, ids []int) error {
+	g, ctx := errgroup.WithContext(ctx)
+	for _, id := range ids {
+		g.Go(func() error {
+			return fetch(ctx, id)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return fmt.Errorf("fetching %d users: %w", len(ids), err)
+	}
+	return nil
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	ctx := context.Background()
+	ids := []int{1, 0, 2}
+
+	// ISSUE: The message made it across, the sentinel did not
+	for _, err := range fetchAllMessages(ctx, ids) {
+		fmt.Fprintf(w, "From a string: %v, not found: %t\n", err, errors.Is(err, ErrNotFound))
+	}
+
+	// Correct way: the error keeps its chain across the channel
+	for _, err := range fetchAll(ctx, ids) {
+		fmt.Fprintf(w, "From a channel: %v, not found: %t\n", err, errors.Is(err, ErrNotFound))
+	}
+
+	// Correct way: the first error, wrapped again, still matches
+	err := fetchGroup(ctx, ids)
+	fmt.Fprintf(w, "From errgroup: %v, not found: %t\n", err, errors.Is(err, ErrNotFound))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "goroutines",
+	Title:   "Errors across goroutines",
+	Buggy:   "msgs <- err.Error()",
+	Correct: "results <- err",
+	Explain: "An error sent as its message arrives as a string, and errors.New on the other side creates an error that matches nothing. Error values sent over a channel or returned by errgroup keep their chains, so errors.Is still finds the sentinel.",
+	Run:     Run,
+}
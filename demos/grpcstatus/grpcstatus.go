@@ -0,0 +1,126 @@
+// Package grpcstatus demonstrates errors crossing a gRPC call with the
+// errgrpc interceptors, over an in-memory connection.
+package grpcstatus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errgrpc"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+)
+
+var sentinels = errkit.NewRegistry()
+
+// Sentinel errors
+var ErrSoldOut = sentinels.Register("tickets.sold_out", errcode.WithCode(errors.New("tickets sold out"), errcode.NotFound))
+
+const reserveMethod = "/demo.Tickets/Reserve"
+
+// Function that fails on the server
+func reserve(ctx context.Context, event *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+	return nil, errkit.Wrapf(ErrSoldOut, "reserving a ticket for %s", event.GetValue())
+}
+
+// ticketsService describes the Tickets service without generated code.
+var ticketsService = grpc.ServiceDesc{
+	ServiceName: "demo.Tickets",
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Reserve",
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			in := new(wrapperspb.StringValue)
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, req any) (any, error) {
+				return reserve(ctx, req.(*wrapperspb.StringValue))
+			}
+			if interceptor == nil {
+				return handler(ctx, in)
+			}
+			return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: reserveMethod}, handler)
+		},
+	}},
+}
+
+// Function that serves the Tickets service and dials it in memory
+func dial(serverOpts []grpc.ServerOption, dialOpts ...grpc.DialOption) (*grpc.ClientConn, func(), error) {
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(serverOpts...)
+	server.RegisterService(&ticketsService, struct{}{})
+	go server.Serve(lis)
+
+	dialOpts = append(dialOpts,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	conn, err := grpc.NewClient("passthrough:///tickets", dialOpts...)
+	if err != nil {
+		server.Stop()
+		return nil, nil, err
+	}
+	return conn, func() {
+		conn.Close()
+		server.Stop()
+	}, nil
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	ctx := context.Background()
+	event := wrapperspb.String("gophercon")
+
+	// ISSUE: Without interceptors every error arrives as codes.Unknown,
+	// and only its message survives
+	conn, stop, err := dial(nil)
+	if err != nil {
+		fmt.Fprintln(w, "Dial failed:", err)
+		return
+	}
+	err = conn.Invoke(ctx, reserveMethod, event, new(wrapperspb.StringValue))
+	stop()
+	fmt.Fprintf(w, "Without interceptors: code %s, matches ErrSoldOut: %t\n", status.Code(err), errors.Is(err, ErrSoldOut))
+
+	// Correct way: the interceptors carry the code and the sentinel name
+	conn, stop, err = dial(
+		[]grpc.ServerOption{grpc.UnaryInterceptor(errgrpc.UnaryServerInterceptor(sentinels))},
+		grpc.WithUnaryInterceptor(errgrpc.UnaryClientInterceptor(sentinels)),
+	)
+	if err != nil {
+		fmt.Fprintln(w, "Dial failed:", err)
+		return
+	}
+	defer stop()
+	err = conn.Invoke(ctx, reserveMethod, event, new(wrapperspb.StringValue))
+	fmt.Fprintf(w, "With interceptors: %v\n", err)
+	fmt.Fprintf(w, "Code %s, errcode %s, matches ErrSoldOut: %t\n", status.Code(err), errcode.CodeOf(err), errors.Is(err, ErrSoldOut))
+
+	var rpcErr *errgrpc.Error
+	if errors.As(err, &rpcErr) {
+		fmt.Fprintf(w, "Status details: %v\n", rpcErr.GRPCStatus().Details())
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "grpc",
+	Title:   "gRPC statuses",
+	Buggy:   "grpc.NewServer()",
+	Correct: "grpc.NewServer(grpc.UnaryInterceptor(errgrpc.UnaryServerInterceptor(registry)))",
+	Explain: "Without an interceptor, handler errors reach clients as codes.Unknown with only a message. errgrpc converts them to statuses with the right code and back to the sentinels on the client.",
+	Run:     Run,
+}
@@ -0,0 +1,90 @@
+// Package httpproblem demonstrates an HTTP server that returns handler
+// errors as RFC 7807 problem+json responses with errhttp.
+package httpproblem
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errhttp"
+)
+
+// Sentinel errors
+var (
+	ErrBadID   = errcode.WithCode(errors.New("item IDs must not be empty"), errcode.InvalidInput)
+	ErrNoItem  = errcode.WithCode(errors.New("no such item"), errcode.NotFound)
+	ErrDBCrash = errors.New("database connection reset")
+)
+
+// Function that looks an item up in a fake store
+func getItem(id string) (string, error) {
+	switch id {
+	case "":
+		return "", ErrBadID
+	case "42":
+		return "answer", nil
+	case "crash":
+		return "", fmt.Errorf("querying items: %w", ErrDBCrash)
+	}
+	return "", fmt.Errorf("item %q: %w", id, ErrNoItem)
+}
+
+// Handler that returns errors and lets errhttp write the response
+func itemHandler(w http.ResponseWriter, r *http.Request) error {
+	item, err := getItem(strings.TrimPrefix(r.URL.Path, "/items/"))
+	if err != nil {
+		return fmt.Errorf("getting item: %w", err)
+	}
+	_, err = io.WriteString(w, item)
+	return err
+}
+
+// Handler that writes every error itself
+func legacyItemHandler(w http.ResponseWriter, r *http.Request) {
+	item, err := getItem(strings.TrimPrefix(r.URL.Path, "/legacy/items/"))
+	if err != nil {
+		// ISSUE: Every error becomes a 500 with the internal message
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	io.WriteString(w, item)
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	mux := http.NewServeMux()
+	mux.Handle("/items/", errhttp.Handler(itemHandler))
+	mux.HandleFunc("/legacy/items/", legacyItemHandler)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	for _, path := range []string{"/legacy/items/7", "/items/42", "/items/", "/items/7", "/items/crash"} {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			fmt.Fprintf(w, "GET %s: %v\n", path, err)
+			continue
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		fmt.Fprintf(w, "GET %s: %s %s", path, resp.Status, body)
+		if !strings.HasSuffix(string(body), "\n") {
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "http",
+	Title:   "HTTP problem responses",
+	Buggy:   "http.Error(w, err.Error(), http.StatusInternalServerError)",
+	Correct: "errhttp.WriteError(w, r, err)",
+	Explain: "Writing err.Error() leaks internals and always answers 500. errhttp maps the code in the chain to a status and writes an RFC 7807 problem response, with details only for client errors.",
+	Run:     Run,
+}
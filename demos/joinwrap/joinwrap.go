@@ -0,0 +1,143 @@
+// Package joinwrap demonstrates the two shapes of an error with several
+// errors in it: a chain, where fmt.Errorf with %w adds what each layer was
+// doing to a single cause, and a tree, where errors.Join gathers causes
+// that happened side by side, and what errors.Is, errtree and errjson make
+// of each.
+package joinwrap
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/errjson"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+	"github.com/kakkoyun/demo-error-lint/errtree"
+)
+
+var registry = errkit.NewRegistry()
+
+// Sentinel errors
+var (
+	ErrRegistryDown = registry.Register("deploy.registry_down", errors.New("image registry unavailable"))
+	ErrUnhealthy    = registry.Register("deploy.unhealthy", errors.New("replica unhealthy"))
+	ErrDiskFull     = registry.Register("deploy.disk_full", errors.New("disk full"))
+)
+
+// Function that pushes an image, one step of a deployment
+func push(image string) error {
+	return fmt.Errorf("pushing %s: %w", image, ErrRegistryDown)
+}
+
+// Function that checks a replica, one of several checked side by side
+func check(replica string) error {
+	switch replica {
+	case "web-2":
+		return fmt.Errorf("checking %s: %w", replica, ErrUnhealthy)
+	case "web-3":
+		return fmt.Errorf("checking %s: %w", replica, ErrDiskFull)
+	}
+	return nil
+}
+
+var replicas = []string{"web-1", "web-2", "web-3"}
+
+// ISSUE: Adding context with errors.Join makes the context a second
+// failure, beside the one it describes, and splits the message over lines
+func deployJoined(image string) error {
+	if err := push(image); err != nil {
+		return errors.Join(errors.New("deploying web"), err)
+	}
+	return nil
+}
+
+// Correct way: each step wraps the error of the step below with %w
+func deployWrapped(image string) error {
+	if err := push(image); err != nil {
+		return fmt.Errorf("deploying web: %w", err)
+	}
+	return nil
+}
+
+// ISSUE: Folding independent failures into a chain with %w only wraps the
+// first one, and formats the others with %v, so errors.Is misses them
+func checkChained() error {
+	var err error
+	for _, r := range replicas {
+		if cerr := check(r); cerr != nil {
+			if err == nil {
+				err = cerr
+			} else {
+				err = fmt.Errorf("%w; %v", err, cerr)
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("checking replicas: %w", err)
+	}
+	return nil
+}
+
+// Correct way: join the failures, which happened side by side, and wrap
+// the join with what the function was doing
+func checkJoined() error {
+	var errs []error
+	for _, r := range replicas {
+		errs = append(errs, check(r))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("checking replicas: %w", err)
+	}
+	return nil
+}
+
+// show prints the message of err, its tree and its JSON form, and whether
+// err and the error errjson rebuilds from it match each of sentinels.
+func show(w io.Writer, label string, err error, sentinels ...error) {
+	fmt.Fprintf(w, "%s: %q\n", label, err.Error())
+	fmt.Fprintln(w, errtree.Chain(err))
+	data, jerr := errjson.Marshal(err, registry)
+	if jerr != nil {
+		fmt.Fprintf(w, "marshaling: %v\n", jerr)
+		return
+	}
+	fmt.Fprintf(w, "%s\n", data)
+	rebuilt, jerr := errjson.Unmarshal(data, registry)
+	if jerr != nil {
+		fmt.Fprintf(w, "unmarshaling: %v\n", jerr)
+		return
+	}
+	for _, sentinel := range sentinels {
+		fmt.Fprintf(w, "Matches %q: %t, after JSON: %t\n", sentinel, errors.Is(err, sentinel), errors.Is(rebuilt, sentinel))
+	}
+	fmt.Fprintln(w)
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	// ISSUE: the context is a sibling of the failure in the tree and in
+	// the JSON, as if the deployment had failed twice
+	show(w, "Joined context", deployJoined("web:v2"), ErrRegistryDown)
+
+	// Correct way: a single cause, with each layer's context in front
+	show(w, "Wrapped context", deployWrapped("web:v2"), ErrRegistryDown)
+
+	// ISSUE: ErrDiskFull is in the message, but neither the tree nor
+	// errors.Is can find it
+	show(w, "Chained causes", checkChained(), ErrUnhealthy, ErrDiskFull)
+
+	// Correct way: both causes are branches of the tree, and errors.Is
+	// finds each of them, before and after the JSON round trip
+	show(w, "Joined causes", checkJoined(), ErrUnhealthy, ErrDiskFull)
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "joinwrap",
+	Title:   "Wrapping with %w versus errors.Join",
+	Buggy:   "err = fmt.Errorf(\"%w; %v\", err, cerr)",
+	Correct: "errs = append(errs, check(r))\n...\nif err := errors.Join(errs...); err != nil {\n\treturn fmt.Errorf(\"checking replicas: %w\", err)\n}",
+	Explain: "Wrap with %w when one failure travels up through layers: each layer adds what it was doing, and the result is a chain with a single cause at the bottom. Join with errors.Join when several operations fail independently, such as checks of replicas run side by side: each failure is a branch of a tree, and errors.Is and errors.As search every branch. Mixing them up loses information either way. Folding independent failures into a chain, as in fmt.Errorf(\"%w; %v\", err, cerr), wraps only the first, and the others become text that errors.Is cannot find. Joining a context message with the error it describes makes the context a failure of its own, and errors.Join puts each on its own line. Wrap the join when the failures share a context, as in fmt.Errorf(\"checking replicas: %w\", errors.Join(errs...)); errtree draws both shapes, and errjson keeps the branches of a join through a round trip.",
+	Run:     Run,
+}
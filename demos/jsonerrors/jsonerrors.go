@@ -0,0 +1,146 @@
+// Package jsonerrors demonstrates handling the errors of a json.Decoder:
+// *json.SyntaxError and *json.UnmarshalTypeError record where the input is
+// wrong, and a body that ends early fails with io.ErrUnexpectedEOF. Found
+// with errors.As and errors.Is, they become invalid input errors with
+// messages that point the client at the mistake, instead of the messages
+// of encoding/json, which name Go types and count bytes.
+package jsonerrors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/errcode"
+)
+
+// Sentinel errors
+var (
+	ErrEmptyBody = errcode.WithCode(errors.New("request body is empty"), errcode.InvalidInput)
+)
+
+// Order is the body of a request to place an order.
+type Order struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+// badRequestError is the error returned for a body that is not a valid
+// order, with a message for the client.
+type badRequestError struct {
+	msg string
+	err error
+}
+
+func (e *badRequestError) Error() string {
+	return e.msg
+}
+
+func (e *badRequestError) Unwrap() error {
+	return e.err
+}
+
+func (e *badRequestError) Code() errcode.Code {
+	return errcode.InvalidInput
+}
+
+// Function that decodes a request body and passes the error on as it is
+func decodeOrderRaw(body string) (Order, error) {
+	var o Order
+	err := json.NewDecoder(strings.NewReader(body)).Decode(&o)
+	return o, err
+}
+
+// Function that decodes a request body into an invalid input error that
+// says where the body is wrong
+func decodeOrder(body string) (Order, error) {
+	var o Order
+	err := json.NewDecoder(strings.NewReader(body)).Decode(&o)
+	if err == nil {
+		return o, nil
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		line, col := position(body, syntaxErr.Offset)
+		return o, &badRequestError{msg: fmt.Sprintf("malformed JSON at line %d, column %d", line, col), err: err}
+	case errors.As(err, &typeErr):
+		line, _ := position(body, typeErr.Offset)
+		return o, &badRequestError{msg: fmt.Sprintf("field %q at line %d must be a %s, not a %s", typeErr.Field, line, jsonType(typeErr.Type), typeErr.Value), err: err}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return o, &badRequestError{msg: "request body ends in the middle of the JSON", err: err}
+	case errors.Is(err, io.EOF):
+		return o, ErrEmptyBody
+	}
+	return o, fmt.Errorf("decoding order: %w", err)
+}
+
+// position returns the line and column, both starting at 1, of the last of
+// the first offset bytes of s. The offsets of encoding/json count the bytes
+// read when decoding failed, so this is the byte that made it fail.
+func position(s string, offset int64) (line, col int) {
+	before := s[:max(min(int(offset), len(s))-1, 0)]
+	line = strings.Count(before, "\n") + 1
+	col = len(before) - strings.LastIndex(before, "\n")
+	return line, col
+}
+
+// jsonType returns the name of the JSON type that decodes into t.
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return "number"
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	bodies := []string{
+		`{"sku": "book-42", "quantity": 2}`,
+		"{\n  \"sku\": \"book-42\",\n  \"quantity\": 2,\n}",
+		"{\n  \"sku\": \"book-42\",\n  \"quantity\": \"two\"\n}",
+		`{"sku": "book-42", "quant`,
+		``,
+	}
+	for _, body := range bodies {
+		fmt.Fprintf(w, "Body %q:\n", body)
+
+		// ISSUE: The message of encoding/json names Go types and byte
+		// offsets, and does not say the client is at fault
+		if _, err := decodeOrderRaw(body); err != nil {
+			fmt.Fprintf(w, "  raw:     %v (code %s)\n", err, errcode.CodeOf(err))
+		}
+
+		// Correct way: errors.As finds the structured error, which says
+		// where the body is wrong
+		o, err := decodeOrder(body)
+		if err != nil {
+			fmt.Fprintf(w, "  decoded: %v (code %s)\n", err, errcode.CodeOf(err))
+			continue
+		}
+		fmt.Fprintf(w, "  decoded: %d x %s\n", o.Quantity, o.SKU)
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "json-errors",
+	Title:   "Handling the errors of encoding/json",
+	Buggy:   "http.Error(w, err.Error(), http.StatusBadRequest)",
+	Correct: "var syntaxErr *json.SyntaxError\nif errors.As(err, &syntaxErr) {\n\tline, col := position(body, syntaxErr.Offset)",
+	Explain: "A json.Decoder fails with *json.SyntaxError for malformed input, *json.UnmarshalTypeError for a value of the wrong type, io.ErrUnexpectedEOF for a body that ends early and io.EOF for an empty one. Their messages name Go types and byte offsets. errors.As and errors.Is find them, so the handler can return an invalid input error with the line, column and field of the mistake.",
+	Run:     Run,
+}
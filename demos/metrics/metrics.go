@@ -0,0 +1,95 @@
+// Package metrics demonstrates counting errors with Prometheus: a counter
+// labeled with the message of the error grows a series for every item,
+// order or address it names, while errmetrics labels errors with their
+// errcode code and registered sentinel, which keeps the series bounded and
+// shows which kinds of error spike. The counters are served on /metrics as
+// a Prometheus server would scrape them.
+package metrics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+	"github.com/kakkoyun/demo-error-lint/errmetrics"
+)
+
+var sentinels = errkit.NewRegistry()
+
+// Sentinel errors
+var (
+	ErrOutOfStock = sentinels.Register("inventory.out_of_stock", errcode.WithCode(errors.New("out of stock"), errcode.Unavailable))
+	ErrDeclined   = sentinels.Register("payment.declined", errcode.WithCode(errors.New("card declined"), errcode.InvalidInput))
+)
+
+// Function that fails in different ways depending on the order
+func checkout(order int) error {
+	switch order % 3 {
+	case 0:
+		return fmt.Errorf("reserving items of order %d: %w", order, ErrOutOfStock)
+	case 1:
+		return fmt.Errorf("charging order %d: %w", order, ErrDeclined)
+	}
+	return nil
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	registry := prometheus.NewRegistry()
+
+	// ISSUE: Labeling the counter with the message creates a series for
+	// each order, which cannot be aggregated and exhausts the memory of the
+	// Prometheus server
+	byMessage := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "checkout_errors_total",
+		Help: "Checkout errors by message.",
+	}, []string{"message"})
+	registry.MustRegister(byMessage)
+
+	// Correct way: label errors with their code and registered sentinel
+	m := errmetrics.New(sentinels)
+	registry.MustRegister(m)
+
+	for order := range 6 {
+		if err := checkout(order); err != nil {
+			byMessage.WithLabelValues(err.Error()).Inc()
+			m.Observe("checkout", err)
+		}
+	}
+
+	server := httptest.NewServer(promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		fmt.Fprintf(w, "GET /metrics: %v\n", err)
+		return
+	}
+	defer resp.Body.Close()
+	fmt.Fprintf(w, "GET /metrics: %s\n", resp.Status)
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if line := scanner.Text(); !strings.HasPrefix(line, "#") {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "metrics",
+	Title:   "Counting errors with Prometheus by classification",
+	Buggy:   "errorsTotal.WithLabelValues(err.Error()).Inc()",
+	Correct: "metrics.Observe(\"checkout\", err)",
+	Explain: "A counter labeled with the message of an error grows a series for each value the message names, so it cannot be aggregated and can exhaust the memory of the Prometheus server. errmetrics labels errors with the operation, their errcode code and the sentinel they match in an errkit.Registry, which keeps the series bounded and shows which kinds of error spike.",
+	Run:     Run,
+}
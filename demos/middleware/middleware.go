@@ -0,0 +1,140 @@
+// Package middleware demonstrates HTTP handlers that return errors and a
+// middleware that maps them to statuses with errors.As and errors.Is, next
+// to a middleware that guesses the status by sniffing the response body.
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Custom error types for demonstration
+type NotFoundError struct {
+	Item string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("item %s not found", e.Item)
+}
+
+// Sentinel errors
+var (
+	ErrInvalidInput = errors.New("invalid input")
+	ErrTimeout      = errors.New("operation timed out")
+)
+
+// handlerFunc is an HTTP handler that returns its error instead of writing
+// it.
+type handlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Function that looks an item up in a fake store
+func getItem(id string) (string, error) {
+	switch id {
+	case "":
+		return "", fmt.Errorf("item ID: %w", ErrInvalidInput)
+	case "42":
+		return "the answer", nil
+	case "faq":
+		return "what to do when a page is not found", nil
+	case "slow":
+		return "", fmt.Errorf("querying stock: %w", ErrTimeout)
+	}
+	return "", &NotFoundError{Item: id}
+}
+
+func itemHandler(w http.ResponseWriter, r *http.Request) error {
+	item, err := getItem(r.PathValue("id"))
+	if err != nil {
+		return fmt.Errorf("getting item: %w", err)
+	}
+	_, err = io.WriteString(w, item+"\n")
+	return err
+}
+
+// ISSUE: The middleware writes the error as text and guesses the status
+// from whatever the body says, so it misreads bodies that merely mention
+// an error and leaks internal messages
+func sniffErrors(h handlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		if err := h(rec, r); err != nil {
+			fmt.Fprintln(rec.Body, err)
+		}
+		body := rec.Body.String()
+		status := http.StatusOK
+		switch {
+		case strings.Contains(body, "not found"):
+			status = http.StatusNotFound
+		case strings.Contains(body, "invalid"):
+			status = http.StatusBadRequest
+		case strings.Contains(body, "timed out"):
+			status = http.StatusGatewayTimeout
+		}
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	})
+}
+
+// Correct way: the middleware asks the error chain what went wrong
+func handleErrors(h handlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := h(w, r); err != nil {
+			status, msg := statusOf(err)
+			http.Error(w, msg, status)
+		}
+	})
+}
+
+// statusOf returns the status of err and the message to show the client,
+// which leaves out the internals of unexpected errors.
+func statusOf(err error) (int, string) {
+	var notFound *NotFoundError
+	switch {
+	case errors.As(err, &notFound):
+		return http.StatusNotFound, notFound.Error()
+	case errors.Is(err, ErrInvalidInput):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, ErrTimeout):
+		return http.StatusGatewayTimeout, "upstream timed out"
+	}
+	return http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError)
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	mux := http.NewServeMux()
+	mux.Handle("/sniff/items/{id...}", sniffErrors(itemHandler))
+	mux.Handle("/items/{id...}", handleErrors(itemHandler))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	for _, prefix := range []string{"/sniff/items/", "/items/"} {
+		for _, id := range []string{"42", "faq", "", "7", "slow"} {
+			path := prefix + id
+			resp, err := http.Get(server.URL + path)
+			if err != nil {
+				fmt.Fprintf(w, "GET %s: %v\n", path, err)
+				continue
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			fmt.Fprintf(w, "GET %s: %s %s", path, resp.Status, body)
+		}
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "middleware",
+	Title:   "HTTP error middleware",
+	Buggy:   `case strings.Contains(body, "not found"):`,
+	Correct: "case errors.As(err, &notFound):",
+	Explain: "Guessing the status from the response body misreads pages that merely mention an error and sends internal messages to clients. Handlers that return errors let one middleware map them to statuses with errors.As and errors.Is.",
+	Run:     Run,
+}
@@ -0,0 +1,116 @@
+// Package multierror demonstrates errors that aggregate several causes,
+// either with errors.Join or with a custom Unwrap() []error method.
+package multierror
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+	"github.com/kakkoyun/demo-error-lint/errtree"
+)
+
+// Sentinel errors
+var (
+	ErrDiskFull = errors.New("disk full")
+	ErrReadOnly = errors.New("read-only file system")
+)
+
+// FieldError reports an invalid field.
+type FieldError struct {
+	Field string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("invalid field %q", e.Field)
+}
+
+// MultiError is a custom aggregate. Like the result of errors.Join, it
+// exposes its causes with Unwrap() []error, which errors.Is and errors.As
+// traverse depth-first.
+type MultiError struct {
+	Errs []error
+}
+
+func (m *MultiError) Error() string {
+	msgs := make([]string, len(m.Errs))
+	for i, err := range m.Errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (m *MultiError) Unwrap() []error {
+	return m.Errs
+}
+
+// Function that returns several errors at once
+func saveAll() error {
+	return errors.Join(ErrDiskFull, &FieldError{Field: "name"})
+}
+
+// Function that returns a custom aggregate
+func validate() error {
+	return &MultiError{Errs: []error{&FieldError{Field: "email"}, ErrReadOnly}}
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	err := saveAll()
+
+	// ISSUE: Direct comparison with a joined error never matches
+	if err == ErrDiskFull {
+		fmt.Fprintln(w, "Disk full")
+	}
+
+	// Correct way: errors.Is checks every joined error
+	if errors.Is(err, ErrDiskFull) {
+		fmt.Fprintln(w, "Disk full (correctly checked)")
+	}
+
+	// ISSUE: Type assertion on a joined error never matches
+	if fieldErr, ok := err.(*FieldError); ok {
+		fmt.Fprintf(w, "Invalid field: %s\n", fieldErr.Field)
+	}
+
+	// Correct way: errors.As finds the first matching joined error
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		fmt.Fprintf(w, "Invalid field (correctly checked): %s\n", fieldErr.Field)
+	}
+
+	// Correct way: asserting to interface{ Unwrap() []error } to visit every
+	// joined error is not an issue, it inspects the structure of err itself
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			fmt.Fprintf(w, "Joined error: %v\n", e)
+		}
+	}
+
+	// Wrapping a joined error with %w keeps every joined error matchable
+	wrapped := fmt.Errorf("saving settings: %w", err)
+	fmt.Fprintf(w, "Wrapped joined error matches ErrDiskFull: %t\n", errors.Is(wrapped, ErrDiskFull))
+	fmt.Fprintln(w, errtree.Chain(wrapped))
+	fmt.Fprintf(w, "Root causes:\n%v\n", errkit.Root(wrapped))
+
+	// A custom Unwrap() []error behaves exactly like errors.Join
+	err = validate()
+	fmt.Fprintf(w, "Custom aggregate matches ErrReadOnly: %t\n", errors.Is(err, ErrReadOnly))
+	fmt.Fprintln(w, errtree.Chain(err))
+	if errors.As(err, &fieldErr) {
+		fmt.Fprintf(w, "Custom aggregate invalid field: %s\n", fieldErr.Field)
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "joined",
+	Title:   "Joined errors compared with ==",
+	Buggy:   "if err == ErrDiskFull {",
+	Correct: "if errors.Is(err, ErrDiskFull) {",
+	Explain: "errors.Join returns an error that unwraps to all of its errors. == matches none of them; errors.Is checks every branch.",
+	Run:     Run,
+}
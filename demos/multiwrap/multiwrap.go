@@ -0,0 +1,58 @@
+// Package multiwrap demonstrates fmt.Errorf calls with several %w verbs,
+// which wrap every matching argument since Go 1.20.
+package multiwrap
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/errtree"
+)
+
+// Sentinel errors
+var (
+	ErrPrimaryDown = errors.New("primary database down")
+	ErrReplicaDown = errors.New("replica database down")
+)
+
+// Function that fails on both databases
+func query() error {
+	return fmt.Errorf("query failed: %w, then %w", ErrPrimaryDown, ErrReplicaDown)
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	err := query()
+	fmt.Fprintln(w, errtree.Chain(err))
+
+	// Every %w argument is wrapped, so errors.Is matches each of them
+	fmt.Fprintf(w, "Matches ErrPrimaryDown: %t\n", errors.Is(err, ErrPrimaryDown))
+	fmt.Fprintf(w, "Matches ErrReplicaDown: %t\n", errors.Is(err, ErrReplicaDown))
+
+	// ISSUE: errors.Unwrap only follows Unwrap() error, and an error with
+	// several %w verbs implements Unwrap() []error instead
+	fmt.Fprintf(w, "errors.Unwrap: %v\n", errors.Unwrap(err))
+
+	// Correct way: visit the wrapped errors through Unwrap() []error
+	if multi, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range multi.Unwrap() {
+			fmt.Fprintf(w, "Wrapped error: %v\n", e)
+		}
+	}
+
+	// The errlint analyzer reports multiple %w verbs in modules whose go
+	// directive is older than go1.20, where only the first one wraps and
+	// the others are formatted as %!w(...).
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "multiwrap",
+	Title:   "Several %w verbs in one fmt.Errorf call",
+	Buggy:   "errors.Unwrap(err)",
+	Correct: "err.(interface{ Unwrap() []error }).Unwrap()",
+	Explain: "An error with several %w verbs unwraps to a slice, which errors.Unwrap does not see and returns nil for. errors.Is and errors.As follow every branch.",
+	Run:     Run,
+}
@@ -0,0 +1,224 @@
+// Package neterrors demonstrates checking network errors through the
+// net.Error interface and the *net.OpError and *net.DNSError types with
+// errors.As, next to sentinel checks with errors.Is, instead of matching
+// their messages.
+package neterrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Function that reads from a server that never answers
+func readSilentServer() error {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	defer lis.Close()
+	go func() {
+		if conn, err := lis.Accept(); err == nil {
+			defer conn.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+
+	conn, err := net.Dial("tcp", lis.Addr().String())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(10 * time.Millisecond))
+	if _, err := conn.Read(make([]byte, 1)); err != nil {
+		return fmt.Errorf("reading greeting: %w", err)
+	}
+	return nil
+}
+
+// Function that dials a port nobody listens on
+func dialClosedPort() error {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("connecting to backend: %w", err)
+	}
+	conn.Close()
+	return nil
+}
+
+// Function that dials with a deadline that has already passed, as a dial
+// to an unreachable host ends
+func dialTimeout() error {
+	d := net.Dialer{Deadline: time.Now()}
+	conn, err := d.Dial("tcp", "192.0.2.1:80")
+	if err != nil {
+		return fmt.Errorf("connecting to backend: %w", err)
+	}
+	conn.Close()
+	return nil
+}
+
+// Function that looks up a host the DNS server does not know
+func lookupMissingHost() error {
+	server, err := nxdomainServer()
+	if err != nil {
+		return err
+	}
+	defer server.Close()
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "udp", server.LocalAddr().String())
+		},
+	}
+	if _, err := resolver.LookupHost(context.Background(), "inventory.example."); err != nil {
+		return fmt.Errorf("resolving backend: %w", err)
+	}
+	return nil
+}
+
+// nxdomainServer starts a DNS server that answers each query with NXDOMAIN,
+// so the demo does not depend on the network it runs on.
+func nxdomainServer() (net.PacketConn, error) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var p dnsmessage.Parser
+			h, err := p.Start(buf[:n])
+			if err != nil {
+				continue
+			}
+			q, err := p.Question()
+			if err != nil {
+				continue
+			}
+			b := dnsmessage.NewBuilder(nil, dnsmessage.Header{
+				ID:                 h.ID,
+				Response:           true,
+				RecursionDesired:   h.RecursionDesired,
+				RecursionAvailable: true,
+				RCode:              dnsmessage.RCodeNameError,
+			})
+			if b.StartQuestions() != nil || b.Question(q) != nil {
+				continue
+			}
+			if msg, err := b.Finish(); err == nil {
+				conn.WriteTo(msg, addr)
+			}
+		}
+	}()
+	return conn, nil
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	err := readSilentServer()
+
+	// ISSUE: Type assertion to net.Error instead of errors.As, which fails
+	// once the error is wrapped
+	if ne, ok := err.(net.Error); ok && ne.Timeout() {
+		fmt.Fprintln(w, "Read timed out")
+	}
+
+	// Correct way: errors.As finds the net.Error in the chain
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		fmt.Fprintln(w, "Read timed out (checked with errors.As)")
+	}
+
+	// Deadlines also match a sentinel, which needs no interface at all
+	fmt.Fprintf(w, "Matches os.ErrDeadlineExceeded: %t\n", errors.Is(err, os.ErrDeadlineExceeded))
+
+	err = dialTimeout()
+
+	// ISSUE: Matching "i/o timeout" misses timeouts reported in other
+	// words, such as those of a context deadline or of another transport
+	fmt.Fprintf(w, "Dial error contains \"i/o timeout\": %t\n", strings.Contains(err.Error(), "i/o timeout"))
+
+	// Correct way: a dial that times out is a net.Error that reports it
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		fmt.Fprintln(w, "Dial timed out (checked with errors.As)")
+	}
+
+	err = dialClosedPort()
+
+	// ISSUE: The text of the errno differs between platforms, Windows
+	// reports "No connection could be made because the target machine
+	// actively refused it."
+	fmt.Fprintf(w, "Dial error contains \"connection refused\": %t\n", strings.Contains(err.Error(), "connection refused"))
+
+	// Correct way: *net.OpError says which operation failed on which
+	// network, and wraps the errno, which errors.Is matches
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		fmt.Fprintf(w, "*net.OpError: op=%s net=%s refused=%t\n", opErr.Op, opErr.Net, errors.Is(opErr, syscall.ECONNREFUSED))
+	}
+
+	// ISSUE: Temporary is deprecated since Go 1.18: most errors that are
+	// worth retrying, such as a refused connection, report false, and some
+	// that are not report true
+	if errors.As(err, &netErr) {
+		fmt.Fprintf(w, "Connection refused is temporary: %t\n", netErr.Temporary())
+	}
+
+	// Correct way: combine the interface check for timeouts with sentinel
+	// checks for the conditions the caller knows how to handle
+	switch {
+	case errors.As(err, &netErr) && netErr.Timeout():
+		fmt.Fprintln(w, "Backend timed out, retrying")
+	case errors.Is(err, syscall.ECONNREFUSED):
+		fmt.Fprintln(w, "Backend is down, retrying")
+	case err != nil:
+		fmt.Fprintln(w, "Backend failed:", err)
+	}
+
+	err = lookupMissingHost()
+
+	// ISSUE: The message of a failed lookup depends on the resolver, the
+	// cgo one on some platforms words it differently
+	fmt.Fprintf(w, "Lookup error contains \"no such host\": %t\n", strings.Contains(err.Error(), "no such host"))
+
+	// Correct way: *net.DNSError says whether the host does not exist, as
+	// opposed to a resolver that failed or timed out, which is worth
+	// retrying
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		fmt.Fprintf(w, "*net.DNSError: name=%s not found=%t timeout=%t\n", dnsErr.Name, dnsErr.IsNotFound, dnsErr.IsTimeout)
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "net-errors",
+	Title:   "net.Error timeouts, *net.OpError and *net.DNSError",
+	Buggy:   "if ne, ok := err.(net.Error); ok && ne.Timeout() {",
+	Correct: "var ne net.Error\nif errors.As(err, &ne) && ne.Timeout() {",
+	Explain: "net.Error is usually wrapped, by *url.Error for example, so asserting it fails. errors.As accepts an interface type as the target and finds it anywhere in the chain. It also finds the *net.OpError of a failed dial, which wraps the errno that errors.Is matches with syscall.ECONNREFUSED, and the *net.DNSError of a failed lookup, whose IsNotFound tells a missing host from a failing resolver. Their messages differ between platforms and resolvers, so matching them with strings.Contains is fragile.",
+	Run:     Run,
+}
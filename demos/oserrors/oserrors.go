@@ -0,0 +1,57 @@
+// Package oserrors demonstrates why the os.IsNotExist family of helpers
+// misses wrapped errors and how errors.Is with the io/fs sentinels fixes it.
+package oserrors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Function that wraps the error of os.Open
+func loadConfig(name string) ([]byte, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	return data, nil
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	_, err := loadConfig(filepath.Join(os.TempDir(), "demo-error-lint-missing.yaml"))
+
+	// ISSUE: os.IsNotExist only looks through *fs.PathError and a few other
+	// types, not through errors wrapped with %w
+	fmt.Fprintf(w, "os.IsNotExist: %t\n", os.IsNotExist(err))
+
+	// Correct way
+	fmt.Fprintf(w, "errors.Is(err, fs.ErrNotExist): %t\n", errors.Is(err, fs.ErrNotExist))
+
+	// os.ErrNotExist is the same value as fs.ErrNotExist
+	fmt.Fprintf(w, "errors.Is(err, os.ErrNotExist): %t\n", errors.Is(err, os.ErrNotExist))
+
+	// ISSUE: The same applies to os.IsPermission and os.IsExist
+	fmt.Fprintf(w, "os.IsPermission: %t, errors.Is(err, fs.ErrPermission): %t\n", os.IsPermission(err), errors.Is(err, fs.ErrPermission))
+
+	// Correct way: errors.As still gives access to the path
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		fmt.Fprintf(w, "Failed %s on %s\n", pathErr.Op, filepath.Base(pathErr.Path))
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "os-errors",
+	Title:   "os.IsNotExist and friends, which do not unwrap",
+	Buggy:   "if os.IsNotExist(err) {",
+	Correct: "if errors.Is(err, fs.ErrNotExist) {",
+	Explain: "os.IsNotExist, os.IsExist and os.IsPermission predate wrapping and only look through a few os error types. errors.Is with the fs sentinels works on any chain.",
+	Run:     Run,
+}
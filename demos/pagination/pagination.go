@@ -0,0 +1,103 @@
+// Package pagination demonstrates how an err assigned in each iteration of
+// a loop and checked after it only holds the error of the last iteration:
+// a page that fails in the middle of a paginated fetch is silently skipped.
+// errlint reports it with the overwrite check:
+//
+//	errlint -checks=overwrite ./demos/pagination
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Sentinel errors
+var (
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// pageSize is the number of items on a page.
+const pageSize = 2
+
+// fetchPage returns the items on a page of the catalog, which is rate
+// limited on page 2.
+func fetchPage(page int) ([]string, error) {
+	if page == 2 {
+		return nil, fmt.Errorf("fetching page %d: %w", page, ErrRateLimited)
+	}
+	items := make([]string, pageSize)
+	for i := range items {
+		items[i] = fmt.Sprintf("item-%d", page*pageSize+i)
+	}
+	return items, nil
+}
+
+// ISSUE: err is overwritten by each page, so the error of page 2 is lost
+// once page 3 succeeds and the catalog comes back with a gap
+func fetchCatalog(pages int) ([]string, error) {
+	var all []string
+	var err error
+	for page := range pages {
+		var items []string
+		items, err = fetchPage(page)
+		all = append(all, items...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// Correct way: check the error of each page in the loop
+func fetchCatalogChecked(pages int) ([]string, error) {
+	var all []string
+	for page := range pages {
+		items, err := fetchPage(page)
+		if err != nil {
+			return nil, fmt.Errorf("fetching catalog: %w", err)
+		}
+		all = append(all, items...)
+	}
+	return all, nil
+}
+
+// Correct way: collect the errors of all pages, to keep the items of the
+// pages that succeeded and still report the ones that failed
+func fetchCatalogJoined(pages int) ([]string, error) {
+	var all []string
+	var errs []error
+	for page := range pages {
+		items, err := fetchPage(page)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		all = append(all, items...)
+	}
+	return all, errors.Join(errs...)
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	items, err := fetchCatalog(4)
+	fmt.Fprintf(w, "Overwritten err: %d items %v, error: %v\n", len(items), items, err)
+
+	items, err = fetchCatalogChecked(4)
+	fmt.Fprintf(w, "Checked in the loop: %d items, error: %v\n", len(items), err)
+
+	items, err = fetchCatalogJoined(4)
+	fmt.Fprintf(w, "Joined: %d items, error: %v, rate limited: %t\n", len(items), err, errors.Is(err, ErrRateLimited))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "pagination",
+	Title:   "Errors overwritten in a loop",
+	Buggy:   "var err error\nfor page := range pages {\n\tvar items []string\n\titems, err = fetchPage(page)\n\tall = append(all, items...)\n}\nif err != nil {\n\treturn nil, err\n}",
+	Correct: "for page := range pages {\n\titems, err := fetchPage(page)\n\tif err != nil {\n\t\treturn nil, fmt.Errorf(\"fetching catalog: %w\", err)\n\t}\n\tall = append(all, items...)\n}",
+	Explain: "An err declared before a loop and assigned in it holds the error of the last iteration only. When a page in the middle fails and the next one succeeds, the check after the loop sees nil and the catalog comes back with a gap. Check the error in the loop, or collect the errors of all iterations with errors.Join.",
+	Run:     Run,
+}
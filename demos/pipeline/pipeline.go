@@ -0,0 +1,196 @@
+// Package pipeline demonstrates errors in a channel pipeline, where a
+// producer feeds records to a pool of workers whose results a collector
+// gathers. Closing a channel to say that a worker failed stops the
+// pipeline but loses the error: the collector only learns that something
+// went wrong. Cancelling a context with the error as its cause stops the
+// other workers as well, and errcollect gathers every failure into one
+// error that errors.Is can still match.
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/errcollect"
+)
+
+// Sentinel errors
+var ErrCorrupt = errors.New("corrupt record")
+
+// workers is the number of workers of the pipelines.
+const workers = 3
+
+// Function that checks a record, which fails at once for negative records
+func validate(record int) error {
+	if record < 0 {
+		return fmt.Errorf("record %d: %w", -record, ErrCorrupt)
+	}
+	return nil
+}
+
+// Function that processes a valid record, which takes a while unless ctx
+// is done first
+func process(ctx context.Context, record int) (int, error) {
+	select {
+	case <-time.After(time.Millisecond):
+		return record * record, nil
+	case <-ctx.Done():
+		return 0, context.Cause(ctx)
+	}
+}
+
+// Function that sends the records on a channel it closes once they are
+// all sent
+func produce(records []int) <-chan int {
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		for _, record := range records {
+			out <- record
+		}
+	}()
+	return out
+}
+
+// ISSUE: The workers close a channel to signal a failure, so the collector
+// knows the pipeline failed but not why; without the sync.Once, the second
+// failing worker would panic closing it again
+func sumClosing(records []int) (int, error) {
+	in := produce(records)
+	results := make(chan int)
+	failed := make(chan struct{})
+	var once sync.Once
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for record := range in {
+				if err := validate(record); err != nil {
+					once.Do(func() { close(failed) })
+					continue
+				}
+				select {
+				case <-failed:
+					// Drain the records without doing the work.
+				case results <- record * record:
+				}
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	sum := 0
+	for result := range results {
+		sum += result
+	}
+	select {
+	case <-failed:
+		return 0, errors.New("pipeline failed")
+	default:
+		return sum, nil
+	}
+}
+
+// Correct way: the first failure cancels the context of the pipeline with
+// the error as its cause, which stops the work of the other workers, and
+// errcollect joins the failures of every worker, each reported once
+func sum(ctx context.Context, records []int) (int, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	in := produce(records)
+	results := make(chan int)
+	var c errcollect.Collector
+	for range workers {
+		c.Go(func() error {
+			var errs []error
+			for record := range in {
+				// Keep draining the records, so the producer is not left
+				// blocked, but only check them once the pipeline stops.
+				if err := validate(record); err != nil {
+					cancel(err)
+					errs = append(errs, err)
+					continue
+				}
+				if ctx.Err() != nil {
+					continue
+				}
+				result, err := process(ctx, record)
+				if err != nil {
+					continue // the cause is a failure reported by its worker
+				}
+				results <- result
+			}
+			return errors.Join(errs...)
+		})
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Err()
+		close(results)
+	}()
+
+	total := 0
+	for result := range results {
+		total += result
+	}
+	if err := <-done; err != nil {
+		return 0, fmt.Errorf("summing %d records: %w", len(records), err)
+	}
+	return total, nil
+}
+
+// failures returns the number of errors joined in err, counting the
+// errors each worker joined separately.
+func failures(err error) int {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		if err == nil {
+			return 0
+		}
+		if inner := errors.Unwrap(err); inner != nil {
+			return failures(inner)
+		}
+		return 1
+	}
+	n := 0
+	for _, err := range joined.Unwrap() {
+		n += failures(err)
+	}
+	return n
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	records := []int{1, 2, -3, 4, 5, -6, 7, 8}
+
+	// ISSUE: The collector knows the pipeline failed, not which records
+	// did or why
+	_, err := sumClosing(records)
+	fmt.Fprintf(w, "Closing a channel: %v, corrupt: %t\n", err, errors.Is(err, ErrCorrupt))
+
+	// Correct way: the joined error holds both failures, and errors.Is
+	// finds the sentinel in them
+	_, err = sum(context.Background(), records)
+	fmt.Fprintf(w, "Cancelling with a cause: %d failures, corrupt: %t\n", failures(err), errors.Is(err, ErrCorrupt))
+
+	total, err := sum(context.Background(), []int{1, 2, 3})
+	fmt.Fprintf(w, "Without failures: sum %d, error: %v\n", total, err)
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "pipeline",
+	Title:   "Errors in channel pipelines",
+	Buggy:   "once.Do(func() { close(failed) })",
+	Correct: "cancel(err)\nerrs = append(errs, err)",
+	Explain: "Closing a channel tells the collector that a worker failed, but not which error it hit, so the pipeline can only return a new error that matches nothing. context.WithCancelCause stops the other workers with the error as the cause, and errcollect joins the failures of all workers, which errors.Is can still match.",
+	Run:     Run,
+}
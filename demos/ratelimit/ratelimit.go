@@ -0,0 +1,169 @@
+// Package ratelimit demonstrates a server that sheds load with HTTP 429
+// and tells clients when to come back, and a client that honors the hint
+// instead of retrying right away. The hint travels with the error:
+// errkit.WithRetryAfter attaches it, errhttp writes it as a Retry-After
+// header and errgrpc as a RetryInfo detail, and errkit.RetryAfter reads it
+// back on the other side.
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errgrpc"
+	"github.com/kakkoyun/demo-error-lint/errhttp"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+)
+
+// Sentinel errors
+var (
+	ErrRateLimited = errcode.WithCode(errors.New("rate limit exceeded"), errcode.RateLimited)
+)
+
+// clock is a fake clock that the server reads and the client advances
+// instead of sleeping, so the demo runs instantly.
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// limiter allows one request every interval.
+type limiter struct {
+	clock    *clock
+	interval time.Duration
+	next     time.Time
+}
+
+// allow returns an error with a hint of how long to wait if the request
+// comes too early.
+func (l *limiter) allow() error {
+	now := l.clock.Now()
+	if wait := l.next.Sub(now); wait > 0 {
+		return errkit.WithRetryAfter(ErrRateLimited, wait)
+	}
+	l.next = now.Add(l.interval)
+	return nil
+}
+
+// Handler that returns the errors of the limiter to errhttp, which writes
+// the hint as a Retry-After header
+func quoteHandler(l *limiter) errhttp.Handler {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		if err := l.allow(); err != nil {
+			return fmt.Errorf("getting quote: %w", err)
+		}
+		_, err := io.WriteString(w, "42.00")
+		return err
+	}
+}
+
+// Function that fetches a quote, and turns a 429 response into an error
+// that carries the Retry-After hint of the server
+func fetchQuote(c *clock, url string) (string, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("fetching quote: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading quote: %w", err)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		err := fmt.Errorf("fetching quote: %w", ErrRateLimited)
+		if d, ok := errhttp.ParseRetryAfter(resp.Header.Get("Retry-After"), c.Now()); ok {
+			err = errkit.WithRetryAfter(err, d)
+		}
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching quote: %s", resp.Status)
+	}
+	return string(body), nil
+}
+
+// ISSUE: Retrying right away hammers a server that asked for a pause, and
+// every attempt is rejected again
+func retryImmediately(w io.Writer, c *clock, url string, attempts int) (string, error) {
+	var err error
+	for range attempts {
+		var quote string
+		if quote, err = fetchQuote(c, url); err == nil {
+			return quote, nil
+		}
+		fmt.Fprintf(w, "  Retrying at once: %v\n", err)
+	}
+	return "", err
+}
+
+// Correct way: wait as long as the server asks before trying again
+func retryAfterHint(w io.Writer, c *clock, url string, attempts int) (string, error) {
+	var err error
+	for range attempts {
+		var quote string
+		if quote, err = fetchQuote(c, url); err == nil || !errkit.IsRetryable(err) {
+			return quote, err
+		}
+		d, ok := errkit.RetryAfter(err)
+		if !ok {
+			d = time.Second
+		}
+		fmt.Fprintf(w, "  Waiting %v as the server asks: %v\n", d, err)
+		c.sleep(d)
+	}
+	return "", err
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	c := &clock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	l := &limiter{clock: c, interval: 2 * time.Second}
+	srv := httptest.NewServer(quoteHandler(l))
+	defer srv.Close()
+
+	// The first request is allowed, and starts the interval
+	quote, err := fetchQuote(c, srv.URL)
+	fmt.Fprintf(w, "First request: %q, %v\n", quote, err)
+
+	fmt.Fprintln(w, "Retrying immediately:")
+	quote, err = retryImmediately(w, c, srv.URL, 3)
+	fmt.Fprintf(w, "  Gave up: %q, %v\n", quote, err)
+
+	fmt.Fprintln(w, "Honoring Retry-After:")
+	quote, err = retryAfterHint(w, c, srv.URL, 3)
+	fmt.Fprintf(w, "  Got: %q, %v\n", quote, err)
+
+	// The hint crosses gRPC as a RetryInfo detail
+	st := errgrpc.ToStatus(l.allow(), nil)
+	d, ok := errkit.RetryAfter(errgrpc.FromStatus(st, nil))
+	fmt.Fprintf(w, "gRPC status: %v, retry after %v (%t)\n", st.Code(), d, ok)
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "ratelimit",
+	Title:   "Retrying rate-limited requests without the server's hint",
+	Buggy:   "for range attempts {\n\tif quote, err = fetchQuote(url); err == nil {\n\t\treturn quote, nil\n\t}\n}",
+	Correct: "if d, ok := errkit.RetryAfter(err); ok {\n\ttime.Sleep(d)\n}",
+	Explain: "A server that answers 429 Too Many Requests says how long to wait in its Retry-After header. Retrying at once is rejected again and adds to the load. errkit.WithRetryAfter attaches the hint to the error on either side, errhttp writes it as the header and errgrpc as a RetryInfo detail, and errkit.RetryAfter reads it back through any wrapping.",
+	Run:     Run,
+}
@@ -0,0 +1,123 @@
+// Package readfull demonstrates io.ReadFull and the two end-of-file errors
+// it returns: io.EOF if the reader had no bytes left at all, and
+// io.ErrUnexpectedEOF if it ended partway through the buffer. io.ReadFull
+// returns both unwrapped, so the function calling it may compare them with
+// ==, as errlint allows. Once a layer in between wraps them, its callers
+// must use errors.Is, and errlint reports the comparisons that no longer
+// match.
+package readfull
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing/iotest"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// readRecord reads a record: a 2-byte big-endian length followed by as
+// many bytes. io.EOF means the stream ended cleanly between records.
+func readRecord(r io.Reader) ([]byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		// io.EOF and io.ErrUnexpectedEOF straight from io.ReadFull
+		return nil, err
+	}
+	body := make([]byte, binary.BigEndian.Uint16(header[:]))
+	if _, err := io.ReadFull(r, body); err != nil {
+		// The header promised a body, so a stream ending before it is
+		// cut short, not finished
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("reading %d-byte record: %w", len(body), err)
+	}
+	return body, nil
+}
+
+// ISSUE: readRecord wraps the error of a body cut short, so == no longer
+// matches io.ErrUnexpectedEOF and a truncated stream looks like a broken
+// one
+func countRecordsEqual(r io.Reader) (int, string) {
+	for n := 0; ; n++ {
+		_, err := readRecord(r)
+		if err == io.EOF {
+			return n, "complete"
+		}
+		if err == io.ErrUnexpectedEOF {
+			return n, "truncated"
+		}
+		if err != nil {
+			return n, "failed: " + err.Error()
+		}
+	}
+}
+
+// Correct way: errors.Is matches the end-of-file errors however readRecord
+// returns them
+func countRecords(r io.Reader) (int, string) {
+	for n := 0; ; n++ {
+		_, err := readRecord(r)
+		switch {
+		case errors.Is(err, io.EOF):
+			return n, "complete"
+		case errors.Is(err, io.ErrUnexpectedEOF):
+			return n, "truncated"
+		case err != nil:
+			return n, "failed: " + err.Error()
+		}
+	}
+}
+
+// record encodes a record with the given body.
+func record(body string) string {
+	return string(binary.BigEndian.AppendUint16(nil, uint16(len(body)))) + body
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	// io.ReadFull fills the buffer or says why it could not
+	fmt.Fprintln(w, "io.ReadFull into 4 bytes:")
+	for _, input := range []string{"", "ab", "abcd", "abcdef"} {
+		buf := make([]byte, 4)
+		n, err := io.ReadFull(strings.NewReader(input), buf)
+		fmt.Fprintf(w, "  %-8q n=%d err=%v\n", input, n, err)
+	}
+
+	// A single Read may return fewer bytes without an error
+	r := iotest.OneByteReader(strings.NewReader("abcd"))
+	buf := make([]byte, 4)
+	n, err := r.Read(buf)
+	fmt.Fprintf(w, "Read from a slow reader: n=%d err=%v\n", n, err)
+	n, err = io.ReadFull(iotest.OneByteReader(strings.NewReader("abcd")), buf)
+	fmt.Fprintf(w, "io.ReadFull from a slow reader: n=%d err=%v\n", n, err)
+
+	streams := []struct {
+		name, data string
+	}{
+		{"complete", record("tea") + record("mug")},
+		{"cut in a header", record("tea") + record("mug")[:1]},
+		{"cut in a body", record("tea") + record("mug")[:3]},
+		{"body missing", record("tea") + record("mug")[:2]},
+	}
+	for _, s := range streams {
+		n, state := countRecordsEqual(bytes.NewReader([]byte(s.data)))
+		fmt.Fprintf(w, "Stream %s, with ==: %d records, %s\n", s.name, n, state)
+		n, state = countRecords(bytes.NewReader([]byte(s.data)))
+		fmt.Fprintf(w, "Stream %s, with errors.Is: %d records, %s\n", s.name, n, state)
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "readfull",
+	Title:   "io.ReadFull, io.EOF and io.ErrUnexpectedEOF",
+	Buggy:   "if err == io.ErrUnexpectedEOF {",
+	Correct: "if errors.Is(err, io.ErrUnexpectedEOF) {",
+	Explain: "io.ReadFull returns io.EOF if the reader had no bytes left and io.ErrUnexpectedEOF if it ended partway through the buffer, both unwrapped, so its caller may compare them with ==. A layer that reads structured data turns an io.EOF in the middle of a record into io.ErrUnexpectedEOF and wraps it with context, and from then on its callers must match both with errors.Is.",
+	Run:     Run,
+}
@@ -0,0 +1,88 @@
+// Package reassign demonstrates how assigning a new value to a sentinel
+// error, here to translate its message, breaks errors.Is for every error
+// created before the assignment: they wrap the old value, which no longer
+// matches the sentinel. errlint reports it with the reassign check:
+//
+//	errlint -checks=reassign ./demos/reassign
+package reassign
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Sentinel errors
+var (
+	ErrNotFound = errors.New("not found")
+)
+
+// users is a directory of users that remembers the users it did not find,
+// with the error it returned for them.
+type users struct {
+	names  map[string]bool
+	misses map[string]error
+}
+
+func newUsers(names ...string) *users {
+	u := &users{names: make(map[string]bool), misses: make(map[string]error)}
+	for _, name := range names {
+		u.names[name] = true
+	}
+	return u
+}
+
+// lookup reports an error wrapping ErrNotFound for users that do not
+// exist, and returns the remembered error on later lookups.
+func (u *users) lookup(name string) error {
+	if err, ok := u.misses[name]; ok {
+		return err
+	}
+	if !u.names[name] {
+		err := fmt.Errorf("user %q: %w", name, ErrNotFound)
+		u.misses[name] = err
+		return err
+	}
+	return nil
+}
+
+// ISSUE: localize replaces the sentinel to translate its message, so the
+// errors created before it wrap a value that errors.Is no longer matches
+func localize() {
+	ErrNotFound = errors.New("introuvable")
+}
+
+// Correct way: keep the sentinel and translate the message where the error
+// is shown
+func message(err error) string {
+	if errors.Is(err, ErrNotFound) {
+		return "introuvable"
+	}
+	return err.Error()
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	dir := newUsers("alice")
+	before := dir.lookup("bob")
+	fmt.Fprintf(w, "Correct way: %v is shown as %q\n", before, message(before))
+
+	// ISSUE: The remembered error no longer matches the sentinel, while
+	// a new one does
+	localize()
+	remembered, fresh := dir.lookup("bob"), dir.lookup("carol")
+	fmt.Fprintf(w, "Reassigned: errors.Is(%v, ErrNotFound): %t\n", remembered, errors.Is(remembered, ErrNotFound))
+	fmt.Fprintf(w, "Reassigned: errors.Is(%v, ErrNotFound): %t\n", fresh, errors.Is(fresh, ErrNotFound))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "reassign",
+	Title:   "Sentinel errors reassigned at run time",
+	Buggy:   "func localize() {\n\tErrNotFound = errors.New(\"introuvable\")\n}",
+	Correct: "func message(err error) string {\n\tif errors.Is(err, ErrNotFound) {\n\t\treturn \"introuvable\"\n\t}\n\treturn err.Error()\n}",
+	Explain: "errors.Is compares the errors of a chain with the current value of the sentinel. After ErrNotFound = errors.New(...), the errors created before the assignment, such as the one the directory remembers for bob, still wrap the old value, so they no longer match, and only the new errors do. Keep sentinels fixed, declare a new one if the meaning changes, and translate messages where errors are shown.",
+	Run:     Run,
+}
@@ -0,0 +1,124 @@
+// Package recovery demonstrates converting panics into errors with recover
+// at the boundary of code that may panic, such as a plugin or a request
+// handler, and why the conversion should wrap a recovered error rather than
+// turn the panic into text. It also shows the panic that should have been
+// an error in the first place, which errlint reports with the panic check:
+//
+//	errlint -checks=panic ./demos/recovery
+package recovery
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Sentinel errors
+var (
+	ErrPanicked  = errors.New("panicked")
+	ErrBadRecord = errors.New("bad record")
+)
+
+// ISSUE: parseRecord returns an error, yet panics with one, so every
+// caller has to recover to handle bad input
+func parseRecord(line string) (int, error) {
+	_, value, ok := strings.Cut(line, "=")
+	if !ok {
+		return 0, fmt.Errorf("%q: %w", line, ErrBadRecord)
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		panic(fmt.Errorf("%q: %w: %w", line, ErrBadRecord, err))
+	}
+	return n, nil
+}
+
+// Correct way: return the error like the other failures
+func parseRecordChecked(line string) (int, error) {
+	_, value, ok := strings.Cut(line, "=")
+	if !ok {
+		return 0, fmt.Errorf("%q: %w", line, ErrBadRecord)
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q: %w: %w", line, ErrBadRecord, err)
+	}
+	return n, nil
+}
+
+// ISSUE: The panic is turned into text, so a recovered error loses its
+// chain and errors.Is no longer finds ErrBadRecord
+func callStringified(f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New("panicked: " + fmt.Sprint(r))
+		}
+	}()
+	f()
+	return nil
+}
+
+// Correct way: wrap a recovered error with %w, along with ErrPanicked,
+// and only format values that are not errors, such as panic("...")
+func call(f func()) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		if e, ok := r.(error); ok {
+			err = fmt.Errorf("%w: %w", ErrPanicked, e)
+			return
+		}
+		err = fmt.Errorf("%w: %v", ErrPanicked, r)
+	}()
+	f()
+	return nil
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	parse := func() { _, _ = parseRecord("port=http") }
+
+	// ISSUE: The message survives, the sentinel does not
+	err := callStringified(parse)
+	fmt.Fprintf(w, "Stringified: %v\n", err)
+	fmt.Fprintf(w, "errors.Is(err, ErrBadRecord): %t\n", errors.Is(err, ErrBadRecord))
+
+	// Correct way: the error passed to panic stays in the chain
+	err = call(parse)
+	fmt.Fprintf(w, "Wrapped: %v\n", err)
+	fmt.Fprintf(w, "errors.Is(err, ErrPanicked): %t\n", errors.Is(err, ErrPanicked))
+	fmt.Fprintf(w, "errors.Is(err, ErrBadRecord): %t\n", errors.Is(err, ErrBadRecord))
+	var numErr *strconv.NumError
+	fmt.Fprintf(w, "errors.As(err, &numErr): %t\n", errors.As(err, &numErr))
+
+	// Runtime panics are errors too, of type runtime.Error
+	err = call(func() {
+		var records []int
+		_ = records[3]
+	})
+	var rtErr runtime.Error
+	fmt.Fprintf(w, "Runtime: %v\n", err)
+	fmt.Fprintf(w, "errors.As(err, &rtErr): %t\n", errors.As(err, &rtErr))
+
+	// Correct way: no recover needed when bad input is an error
+	_, err = parseRecordChecked("port=http")
+	fmt.Fprintf(w, "Returned: %v\n", err)
+	fmt.Fprintf(w, "errors.Is(err, ErrBadRecord): %t\n", errors.Is(err, ErrBadRecord))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "recover",
+	Title:   "Converting panics to errors with recover",
+	Buggy:   "if r := recover(); r != nil {\n\terr = errors.New(\"panicked: \" + fmt.Sprint(r))\n}",
+	Correct: "if e, ok := r.(error); ok {\n\terr = fmt.Errorf(\"%w: %w\", ErrPanicked, e)\n\treturn\n}\nerr = fmt.Errorf(\"%w: %v\", ErrPanicked, r)",
+	Explain: "recover returns whatever was passed to panic. Formatting it into a new error keeps the text but drops the chain, so a recovered error, or a runtime.Error, can no longer be matched. Wrapping it with %w keeps errors.Is and errors.As working. Better still, a function that returns an error should return bad input as an error instead of panicking.",
+	Run:     Run,
+}
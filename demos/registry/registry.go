@@ -0,0 +1,95 @@
+// Package registry demonstrates sending sentinel errors across a
+// serialization boundary by name with an errkit.Registry.
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+)
+
+var sentinels = errkit.NewRegistry()
+
+// Sentinel errors
+var (
+	ErrOutOfStock   = sentinels.Register("inventory.out_of_stock", errors.New("out of stock"))
+	ErrDiscontinued = sentinels.Register("inventory.discontinued", errors.New("product discontinued"))
+)
+
+// wireError is an error as it is sent over the wire or written to a log.
+type wireError struct {
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// remoteError is an error rebuilt on the client, with the message of the
+// server and the registered sentinel it matched there.
+type remoteError struct {
+	msg string
+	err error
+}
+
+func (e *remoteError) Error() string {
+	return e.msg
+}
+
+func (e *remoteError) Unwrap() error {
+	return e.err
+}
+
+// Function that fails on the server
+func reserve(sku string) error {
+	return fmt.Errorf("reserving %s: %w", sku, ErrOutOfStock)
+}
+
+// Function that serializes an error on the server
+func encode(err error) []byte {
+	code, _ := sentinels.Name(err)
+	data, _ := json.Marshal(wireError{Code: code, Message: err.Error()})
+	return data
+}
+
+// Function that rebuilds an error on the client
+func decode(data []byte) error {
+	var we wireError
+	if err := json.Unmarshal(data, &we); err != nil {
+		return err
+	}
+	if sentinel, ok := sentinels.Lookup(we.Code); ok {
+		return &remoteError{msg: we.Message, err: sentinel}
+	}
+	return errors.New(we.Message)
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	data := encode(reserve("sku-42"))
+	fmt.Fprintf(w, "On the wire: %s\n", data)
+
+	// ISSUE: Rebuilding the error from its message creates a new value,
+	// which no longer matches the sentinel
+	var we wireError
+	_ = json.Unmarshal(data, &we)
+	fmt.Fprintf(w, "errors.New(message) matches ErrOutOfStock: %t\n", errors.Is(errors.New(we.Message), ErrOutOfStock))
+
+	// Correct way: look the sentinel up by its registered name
+	err := decode(data)
+	fmt.Fprintf(w, "Decoded error: %v\n", err)
+	fmt.Fprintf(w, "Registry lookup matches ErrOutOfStock: %t\n", errors.Is(err, ErrOutOfStock))
+
+	fmt.Fprintf(w, "Registered sentinels: %v\n", sentinels.Names())
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "registry",
+	Title:   "Sentinels across serialization boundaries",
+	Buggy:   "err := errors.New(wire.Message)",
+	Correct: "sentinel, ok := registry.Lookup(wire.Code)",
+	Explain: "Only the message survives JSON and other wire formats, so errors.New on the other side creates a new error that matches nothing. A registry maps stable names back to the sentinels.",
+	Run:     Run,
+}
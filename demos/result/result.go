@@ -0,0 +1,115 @@
+// Package result demonstrates the experimental result.Result[T] type next
+// to the idiomatic (value, error) returns it replaces, so the trade-offs can
+// be judged side by side: the chain of combinators saves the if err != nil
+// checks, and errors.Is and errors.As work on the error it carries, but it
+// is easy to pass that error along without saying which step failed.
+package result
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/result"
+)
+
+// Sentinel errors
+var (
+	ErrMissing    = errors.New("missing setting")
+	ErrOutOfRange = errors.New("out of range")
+)
+
+func lookup(settings map[string]string, key string) (string, error) {
+	value, ok := settings[key]
+	if !ok {
+		return "", fmt.Errorf("%q: %w", key, ErrMissing)
+	}
+	return value, nil
+}
+
+func validPort(port int) (int, error) {
+	if port < 1 || port > 65535 {
+		return 0, fmt.Errorf("%d: %w", port, ErrOutOfRange)
+	}
+	return port, nil
+}
+
+// Idiomatic: every step is checked, and wrapped with what it was doing
+func port(settings map[string]string) (int, error) {
+	value, err := lookup(settings, "port")
+	if err != nil {
+		return 0, fmt.Errorf("looking up port: %w", err)
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("parsing port: %w", err)
+	}
+	n, err = validPort(n)
+	if err != nil {
+		return 0, fmt.Errorf("validating port: %w", err)
+	}
+	return n, nil
+}
+
+// ISSUE: The chain is shorter, but passes the bare error along, so the
+// message does not say which step failed
+func portChained(settings map[string]string) result.Result[int] {
+	r := result.Of(lookup(settings, "port"))
+	n := result.AndThen(r, func(value string) result.Result[int] {
+		return result.Of(strconv.Atoi(value))
+	})
+	return result.AndThen(n, func(n int) result.Result[int] {
+		return result.Of(validPort(n))
+	})
+}
+
+// Correct way: Wrap each step, which keeps the chain short and the message
+// as informative as the idiomatic version
+func portWrapped(settings map[string]string) result.Result[int] {
+	r := result.Of(lookup(settings, "port")).Wrap("looking up port")
+	n := result.AndThen(r, func(value string) result.Result[int] {
+		return result.Of(strconv.Atoi(value)).Wrap("parsing port")
+	})
+	return result.AndThen(n, func(n int) result.Result[int] {
+		return result.Of(validPort(n)).Wrap("validating port")
+	})
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	configs := []map[string]string{
+		{"port": "8080"},
+		{"host": "localhost"},
+		{"port": "http"},
+		{"port": "70000"},
+	}
+	for _, settings := range configs {
+		fmt.Fprintf(w, "Settings %v:\n", settings)
+
+		n, err := port(settings)
+		fmt.Fprintf(w, "  Idiomatic: %d, %v\n", n, err)
+
+		// ISSUE: The sentinel is still found, but the message lacks context
+		r := portChained(settings)
+		fmt.Fprintf(w, "  Chained:   %d, %v\n", r.Or(0), r.Err())
+
+		// Correct way: Get returns to (value, error) at the boundary
+		n, err = portWrapped(settings).Get()
+		var numErr *strconv.NumError
+		fmt.Fprintf(w, "  Wrapped:   %d, %v\n", n, err)
+		fmt.Fprintf(w, "  errors.Is(err, ErrMissing): %t, errors.Is(err, ErrOutOfRange): %t, errors.As(err, &numErr): %t\n",
+			errors.Is(err, ErrMissing), errors.Is(err, ErrOutOfRange), errors.As(err, &numErr))
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "result",
+	Title:   "Result[T] chains against (value, error) returns",
+	Buggy:   "n := result.AndThen(result.Of(lookup(settings, \"port\")), func(value string) result.Result[int] {\n\treturn result.Of(strconv.Atoi(value))\n})",
+	Correct: "r := result.Of(lookup(settings, \"port\")).Wrap(\"looking up port\")\nn := result.AndThen(r, func(value string) result.Result[int] {\n\treturn result.Of(strconv.Atoi(value)).Wrap(\"parsing port\")\n})",
+	Explain: "A Result chain skips the if err != nil after each step and keeps the error intact, so errors.Is and errors.As still work. But each step passes the bare error along, so the message no longer says which step failed. Wrap each step, as the idiomatic version does with fmt.Errorf and %w, or keep (value, error) returns.",
+	Run:     Run,
+}
@@ -0,0 +1,84 @@
+// Package retry demonstrates a retry loop with backoff that decides what
+// to retry with errkit.IsRetryable instead of matching error messages.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+)
+
+// Sentinel errors
+var (
+	ErrConnReset   = errors.New("connection reset by peer")
+	ErrBadChecksum = errors.New("checksum mismatch")
+)
+
+// Function that fails twice with a transient error, then succeeds
+func flakyUpload() func() error {
+	calls := 0
+	return func() error {
+		calls++
+		if calls < 3 {
+			return fmt.Errorf("upload attempt %d: %w", calls, errkit.Retryable(ErrConnReset))
+		}
+		return nil
+	}
+}
+
+// Function that fails with an error no retry can fix
+func corruptUpload() error {
+	return fmt.Errorf("upload: %w", errkit.Permanent(ErrBadChecksum))
+}
+
+// Function that retries fn with exponential backoff while its errors are
+// retryable
+func withRetry(ctx context.Context, w io.Writer, attempts int, fn func() error) error {
+	backoff := 10 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !errkit.IsRetryable(err) || attempt == attempts {
+			return err
+		}
+		fmt.Fprintf(w, "Retrying after %v: %v\n", backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return fmt.Errorf("%w (last error: %w)", ctx.Err(), err)
+		}
+		backoff *= 2
+	}
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	ctx := context.Background()
+
+	// ISSUE: Guessing from the message breaks when the message changes,
+	// and retries errors that merely mention the phrase
+	err := errkit.Permanent(errors.New("invalid request: timeout must be positive"))
+	fmt.Fprintf(w, "Message says retry: %t\n", strings.Contains(err.Error(), "reset") || strings.Contains(err.Error(), "timeout"))
+
+	// Correct way: the classification travels with the error
+	err = withRetry(ctx, w, 5, flakyUpload())
+	fmt.Fprintf(w, "Flaky upload: %v\n", err)
+
+	err = withRetry(ctx, w, 5, corruptUpload)
+	fmt.Fprintf(w, "Corrupt upload: %v, retryable: %t\n", err, errkit.IsRetryable(err))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "retry",
+	Title:   "Classifying retryable errors",
+	Buggy:   `if strings.Contains(err.Error(), "timeout") {`,
+	Correct: "if errkit.IsRetryable(err) {",
+	Explain: "Matching \"timeout\" in messages retries errors that say timeout but are permanent and misses retryable ones that do not. errkit.IsRetryable asks the errors in the chain.",
+	Run:     Run,
+}
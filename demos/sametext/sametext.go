@@ -0,0 +1,57 @@
+// Package sametext demonstrates comparing an error with one created by
+// errors.New from the same text, which never matches: every call of
+// errors.New returns a new value, and == and errors.Is compare values, not
+// messages. errlint reports it with the newcompare check:
+//
+//	errlint -checks=newcompare ./demos/sametext
+package sametext
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Sentinel errors
+var (
+	ErrNotFound = errors.New("not found")
+)
+
+// ISSUE: lookup creates a new error on every call, with the same text as
+// the one its callers create to compare with
+func lookup(key string) error {
+	return errors.New("not found")
+}
+
+// Correct way: return the sentinel, wrapped with the details of the call
+func find(key string) error {
+	return fmt.Errorf("key %q: %w", key, ErrNotFound)
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	err := lookup("session")
+
+	// ISSUE: Two errors from errors.New are different values, even with the
+	// same message, so neither == nor errors.Is matches
+	notFound := errors.New("not found")
+	fmt.Fprintf(w, "Same text: %q and %q\n", err.Error(), notFound.Error())
+	fmt.Fprintf(w, "== matches %t, errors.Is matches %t\n", err == notFound, errors.Is(err, notFound))
+	fmt.Fprintf(w, "errors.New(\"not found\") == errors.New(\"not found\"): %t\n", errors.New("not found") == errors.New("not found"))
+
+	// Correct way: compare with the sentinel the function returns
+	err = find("session")
+	fmt.Fprintf(w, "Sentinel: errors.Is(%v, ErrNotFound) matches %t\n", err, errors.Is(err, ErrNotFound))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "sametext",
+	Title:   "Comparing with a new error from errors.New with the same text",
+	Buggy:   "notFound := errors.New(\"not found\")\nif err == notFound {",
+	Correct: "var ErrNotFound = errors.New(\"not found\")\n\nif errors.Is(err, ErrNotFound) {",
+	Explain: "errors.New returns a pointer to a new value on every call, so two errors created from the same text are not equal. Comparing with an error created on the spot never matches, and errors.Is does not help: it compares the errors of the chain with == as well. Declare the error once as a package-level sentinel, return it, and compare with it.",
+	Run:     Run,
+}
@@ -0,0 +1,113 @@
+// Package shadow demonstrates how err := inside a block declares a new err
+// that shadows the outer one, so the error assigned in the block never
+// reaches the err the function returns or checks afterwards. errlint
+// reports it with the shadow check:
+//
+//	errlint -checks=shadow ./demos/shadow
+package shadow
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Sentinel errors
+var (
+	ErrSyntax = errors.New("syntax error")
+)
+
+// config is the configuration of a server.
+type config struct {
+	port  int
+	debug bool
+}
+
+// decode parses key=value lines into settings.
+func decode(text string) (map[string]string, error) {
+	settings := make(map[string]string)
+	for i, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: %q: %w", i+1, line, ErrSyntax)
+		}
+		settings[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return settings, nil
+}
+
+// apply sets the fields of cfg from settings.
+func apply(cfg *config, settings map[string]string) {
+	if port, err := strconv.Atoi(settings["port"]); err == nil {
+		cfg.port = port
+	}
+	cfg.debug = cfg.debug || settings["debug"] == "true"
+}
+
+// ISSUE: err := declares a new err in the if block, so the error of the
+// override is dropped and load returns the defaults with a nil error
+func load(defaults, override string) (config, error) {
+	var cfg config
+	settings, err := decode(defaults)
+	if err != nil {
+		return config{}, err
+	}
+	apply(&cfg, settings)
+	if override != "" {
+		settings, err := decode(override)
+		if err == nil {
+			apply(&cfg, settings)
+		}
+	}
+	return cfg, err
+}
+
+// Correct way: handle the error where it is declared, or assign to the
+// outer err with = instead of declaring a new one with :=
+func loadChecked(defaults, override string) (config, error) {
+	var cfg config
+	settings, err := decode(defaults)
+	if err != nil {
+		return config{}, fmt.Errorf("reading defaults: %w", err)
+	}
+	apply(&cfg, settings)
+	if override != "" {
+		settings, err := decode(override)
+		if err != nil {
+			return config{}, fmt.Errorf("reading override: %w", err)
+		}
+		apply(&cfg, settings)
+	}
+	return cfg, nil
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	const defaults = "port = 8080\ndebug = false"
+	// The override is missing its = sign.
+	const override = "port 9090"
+
+	// ISSUE: The override is silently ignored
+	cfg, err := load(defaults, override)
+	fmt.Fprintf(w, "Shadowed: port = %d, err = %v\n", cfg.port, err)
+	fmt.Fprintf(w, "errors.Is(err, ErrSyntax): %t\n", errors.Is(err, ErrSyntax))
+
+	// Correct way: the syntax error reaches the caller
+	cfg, err = loadChecked(defaults, override)
+	fmt.Fprintf(w, "Checked: port = %d, err = %v\n", cfg.port, err)
+	fmt.Fprintf(w, "errors.Is(err, ErrSyntax): %t\n", errors.Is(err, ErrSyntax))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "shadow",
+	Title:   "Shadowing err with :=",
+	Buggy:   "if override != \"\" {\n\tsettings, err := decode(override)\n\tif err == nil {\n\t\tapply(&cfg, settings)\n\t}\n}\nreturn cfg, err",
+	Correct: "if override != \"\" {\n\tsettings, err := decode(override)\n\tif err != nil {\n\t\treturn config{}, fmt.Errorf(\"reading override: %w\", err)\n\t}\n\tapply(&cfg, settings)\n}\nreturn cfg, nil",
+	Explain: "settings, err := declares a new err that lives until the end of the if block and hides the outer one. The syntax error of the override is assigned to it and then forgotten, so return cfg, err returns the outer err, which is nil, and the override is silently ignored. Handle the error in the block, or assign to the outer err with =.",
+	Run:     Run,
+}
@@ -0,0 +1,51 @@
+// Package specialcases demonstrates the sentinels documented to be
+// returned unwrapped, such as io.EOF and sql.ErrNoRows, which may be
+// compared with == and which the linter allows.
+package specialcases
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+func openDbErr() error {
+	return sql.ErrNoRows
+}
+
+// Function demonstrating EOF handling (documented special case); the
+// readfull demo covers io.ReadFull and io.ErrUnexpectedEOF
+func readFullBuffer(r io.Reader, buf []byte) (int, error) {
+	n, err := r.Read(buf)
+	// This is actually allowed by the linter because io.EOF is documented
+	// to be returned unwrapped
+	if err == io.EOF {
+		return n, nil
+	}
+	return n, err
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	if openDbErr() == sql.ErrNoRows {
+		// This is actually allowed by the linter because sql.ErrNoRows is documented
+		// to be returned unwrapped
+		fmt.Fprintln(w, "No rows found")
+	}
+
+	n, err := readFullBuffer(strings.NewReader(""), make([]byte, 8))
+	fmt.Fprintf(w, "Read %d bytes, error: %v\n", n, err)
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "special-cases",
+	Title:   "Sentinels documented to be returned unwrapped",
+	Buggy:   "// None: these comparisons are allowed",
+	Correct: "if err == sql.ErrNoRows {\nif err == io.EOF {",
+	Explain: "A few sentinels, such as io.EOF and sql.ErrNoRows, are documented to be returned unwrapped, so comparing them with == is correct and the linter allows it.",
+	Run:     Run,
+}
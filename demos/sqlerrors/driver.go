@@ -0,0 +1,201 @@
+package sqlerrors
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Error is an error of the in-memory database, with the SQLSTATE code of
+// what went wrong, as drivers such as pgx and go-sql-driver/mysql return.
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("memdb: %s (SQLSTATE %s)", e.Message, e.Code)
+}
+
+// SQLSTATE codes of the errors the in-memory database returns
+const (
+	CodeUniqueViolation      = "23505"
+	CodeSerializationFailure = "40001"
+)
+
+// The queries the in-memory database understands
+const (
+	selectUser = "SELECT name FROM users WHERE id = ?"
+	insertUser = "INSERT INTO users (id, name) VALUES (?, ?)"
+)
+
+// store is the content of the in-memory database, shared by its
+// connections.
+type store struct {
+	mu    sync.Mutex
+	users map[int64]string
+	// conflicts is the number of commits still to fail with a
+	// serialization failure, as if another transaction got there first.
+	conflicts int
+}
+
+func (s *store) failCommits(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conflicts = n
+}
+
+// insert adds the users in inserts, or none of them if one exists.
+func (s *store) insert(inserts []user) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, u := range inserts {
+		if _, ok := s.users[u.id]; ok {
+			return &Error{Code: CodeUniqueViolation, Message: fmt.Sprintf("duplicate key id=%d", u.id)}
+		}
+	}
+	for _, u := range inserts {
+		s.users[u.id] = u.name
+	}
+	return nil
+}
+
+type user struct {
+	id   int64
+	name string
+}
+
+// connector opens connections to a store for sql.OpenDB.
+type connector struct {
+	store *store
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{store: c.store}, nil
+}
+
+func (c connector) Driver() driver.Driver {
+	return memDriver{}
+}
+
+// memDriver only exists for connector.Driver; the database is opened with
+// sql.OpenDB, not by name.
+type memDriver struct{}
+
+func (memDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("memdb: open with sql.OpenDB")
+}
+
+// conn is a connection, with the inserts of its open transaction, which
+// the store only sees once it is committed.
+type conn struct {
+	store *store
+	tx    *tx
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	if query != selectUser && query != insertUser {
+		return nil, fmt.Errorf("memdb: unsupported query %q", query)
+	}
+	return &stmt{conn: c, query: query}, nil
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	c.tx = &tx{conn: c}
+	return c.tx, nil
+}
+
+type tx struct {
+	conn    *conn
+	inserts []user
+}
+
+func (t *tx) Commit() error {
+	t.conn.tx = nil
+	t.conn.store.mu.Lock()
+	conflict := t.conn.store.conflicts > 0
+	if conflict {
+		t.conn.store.conflicts--
+	}
+	t.conn.store.mu.Unlock()
+	if conflict {
+		return &Error{Code: CodeSerializationFailure, Message: "could not serialize access due to concurrent update"}
+	}
+	return t.conn.store.insert(t.inserts)
+}
+
+func (t *tx) Rollback() error {
+	t.conn.tx = nil
+	return nil
+}
+
+type stmt struct {
+	conn  *conn
+	query string
+}
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+func (s *stmt) NumInput() int {
+	if s.query == insertUser {
+		return 2
+	}
+	return 1
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	if s.query != insertUser {
+		return nil, fmt.Errorf("memdb: %q is not a statement", s.query)
+	}
+	u := user{id: args[0].(int64), name: args[1].(string)}
+	if t := s.conn.tx; t != nil {
+		t.inserts = append(t.inserts, u)
+		return driver.RowsAffected(1), nil
+	}
+	if err := s.conn.store.insert([]user{u}); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	if s.query != selectUser {
+		return nil, fmt.Errorf("memdb: %q is not a query", s.query)
+	}
+	s.conn.store.mu.Lock()
+	defer s.conn.store.mu.Unlock()
+	r := &rows{}
+	if name, ok := s.conn.store.users[args[0].(int64)]; ok {
+		r.names = []string{name}
+	}
+	return r, nil
+}
+
+type rows struct {
+	names []string
+}
+
+func (r *rows) Columns() []string {
+	return []string{"name"}
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.names) == 0 {
+		return io.EOF
+	}
+	dest[0], r.names = r.names[0], r.names[1:]
+	return nil
+}
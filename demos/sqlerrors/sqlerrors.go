@@ -0,0 +1,154 @@
+// Package sqlerrors demonstrates the errors of database/sql beyond
+// sql.ErrNoRows, using a small in-memory driver: sql.ErrTxDone and
+// sql.ErrConnDone, driver errors found with errors.As and their SQLSTATE
+// codes, and retrying transactions that fail with a serialization failure.
+package sqlerrors
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Function that returns the name of the user with the given id
+func userName(ctx context.Context, db *sql.DB, id int64) (string, error) {
+	var name string
+	if err := db.QueryRowContext(ctx, selectUser, id).Scan(&name); err != nil {
+		return "", fmt.Errorf("user %d: %w", id, err)
+	}
+	return name, nil
+}
+
+// Function that adds a user
+func addUser(ctx context.Context, db *sql.DB, id int64, name string) error {
+	if _, err := db.ExecContext(ctx, insertUser, id, name); err != nil {
+		return fmt.Errorf("adding user %d: %w", id, err)
+	}
+	return nil
+}
+
+// isDuplicate reports whether err holds a unique violation of the driver.
+func isDuplicate(err error) bool {
+	var dbErr *Error
+	return errors.As(err, &dbErr) && dbErr.Code == CodeUniqueViolation
+}
+
+// isSerializationFailure reports whether err holds a serialization failure
+// of the driver, after which the whole transaction may succeed if retried.
+func isSerializationFailure(err error) bool {
+	var dbErr *Error
+	return errors.As(err, &dbErr) && dbErr.Code == CodeSerializationFailure
+}
+
+// Function that runs fn in a transaction, and runs it again in a new one
+// while it fails with a serialization failure
+func inTx(ctx context.Context, db *sql.DB, attempts int, fn func(*sql.Tx) error) error {
+	for attempt := 1; ; attempt++ {
+		err := runTx(ctx, db, fn)
+		if err == nil || !isSerializationFailure(err) || attempt == attempts {
+			return err
+		}
+	}
+}
+
+func runTx(ctx context.Context, db *sql.DB, fn func(*sql.Tx) error) (err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() {
+		// Rolling back a committed transaction returns sql.ErrTxDone,
+		// which only means there is nothing left to undo.
+		if rerr := tx.Rollback(); rerr != nil && !errors.Is(rerr, sql.ErrTxDone) {
+			err = errors.Join(err, rerr)
+		}
+	}()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing: %w", err)
+	}
+	return nil
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	ctx := context.Background()
+	s := &store{users: map[int64]string{1: "gopher"}}
+	db := sql.OpenDB(connector{store: s})
+	defer db.Close()
+
+	// ISSUE: sql.ErrNoRows only equals itself until a caller wraps it
+	_, err := userName(ctx, db, 2)
+	fmt.Fprintf(w, "Missing user: %v, == sql.ErrNoRows: %t\n", err, err == sql.ErrNoRows)
+
+	// Correct way: errors.Is finds it behind the wrapping
+	fmt.Fprintf(w, "errors.Is(err, sql.ErrNoRows): %t\n", errors.Is(err, sql.ErrNoRows))
+
+	// ISSUE: Matching the message of a driver error breaks with another
+	// driver, or another version of the same one
+	err = addUser(ctx, db, 1, "gopher")
+	fmt.Fprintf(w, "Duplicate by message: %t\n", strings.Contains(err.Error(), "duplicate key"))
+
+	// Correct way: errors.As finds the error of the driver, and its code
+	// says what went wrong
+	fmt.Fprintf(w, "Duplicate user: %v, unique violation: %t\n", err, isDuplicate(err))
+
+	// Using a transaction after Commit or Rollback returns sql.ErrTxDone
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		fmt.Fprintf(w, "Beginning transaction: %v\n", err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		fmt.Fprintf(w, "Committing: %v\n", err)
+		return
+	}
+	_, err = tx.ExecContext(ctx, insertUser, int64(3), "late")
+	fmt.Fprintf(w, "Exec after Commit: %v, tx done: %t\n", err, errors.Is(err, sql.ErrTxDone))
+
+	// Using a connection after Close returns sql.ErrConnDone
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		fmt.Fprintf(w, "Getting connection: %v\n", err)
+		return
+	}
+	conn.Close()
+	_, err = conn.ExecContext(ctx, insertUser, int64(3), "late")
+	fmt.Fprintf(w, "Exec after Close: %v, conn done: %t\n", err, errors.Is(err, sql.ErrConnDone))
+
+	// Correct way: retry the whole transaction on serialization failures,
+	// and only on them
+	s.failCommits(2)
+	attempts := 0
+	err = inTx(ctx, db, 3, func(tx *sql.Tx) error {
+		attempts++
+		_, err := tx.ExecContext(ctx, insertUser, int64(4), "retried")
+		return err
+	})
+	name, _ := userName(ctx, db, 4)
+	fmt.Fprintf(w, "Transaction after %d attempts: %v, user 4: %s\n", attempts, err, name)
+
+	s.failCommits(5)
+	err = inTx(ctx, db, 3, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, insertUser, int64(5), "unlucky")
+		return err
+	})
+	fmt.Fprintf(w, "Giving up: %v, serialization failure: %t\n", err, isSerializationFailure(err))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "sql",
+	Title:   "database/sql errors beyond sql.ErrNoRows",
+	Buggy:   `if strings.Contains(err.Error(), "duplicate key") {`,
+	Correct: "var dbErr *Error\nif errors.As(err, &dbErr) && dbErr.Code == CodeUniqueViolation {",
+	Explain: "database/sql wraps nothing, but callers do, and drivers word their messages as they like. errors.Is finds sql.ErrNoRows, sql.ErrTxDone and sql.ErrConnDone behind any wrapping, and errors.As finds the error of the driver with a code that says whether a retry can help.",
+	Run:     Run,
+}
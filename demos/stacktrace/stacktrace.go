@@ -0,0 +1,91 @@
+// Package stacktrace demonstrates errkit.Wrap, errkit.Wrapf and
+// errkit.WithStack next to plain fmt.Errorf wrapping, and errkit.StackTrace
+// merging the stacks of two goroutines.
+package stacktrace
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+	"github.com/kakkoyun/demo-error-lint/errtree"
+)
+
+// Sentinel errors
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Function that records where the failure happened
+func reserve(bytes int) error {
+	return errkit.Wrapf(ErrQuotaExceeded, "reserving %d bytes", bytes)
+}
+
+// Function that adds context without a second stack
+func upload(name string) error {
+	if err := reserve(1 << 20); err != nil {
+		return errkit.Wrap(err, "uploading "+name)
+	}
+	return nil
+}
+
+// Function that wraps the error of a worker goroutine, whose stack is not
+// its own
+func uploadAsync(name string) error {
+	errc := make(chan error, 1)
+	go func() {
+		errc <- reserve(1 << 20)
+	}()
+	if err := <-errc; err != nil {
+		return errkit.Wrap(err, "uploading "+name)
+	}
+	return nil
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	// fmt.Errorf wraps the error, but records nothing about the call site
+	plain := fmt.Errorf("uploading report.csv: %w", ErrQuotaExceeded)
+	fmt.Fprintf(w, "fmt.Errorf: %+v\n", plain)
+
+	// errkit.Wrap formats like fmt.Errorf with %v, and unwraps the same way
+	err := upload("report.csv")
+	fmt.Fprintf(w, "errkit.Wrap: %v\n", err)
+	fmt.Fprintf(w, "Matches ErrQuotaExceeded: %t\n", errors.Is(err, ErrQuotaExceeded))
+
+	// %+v adds the stack recorded by reserve, the innermost errkit call
+	fmt.Fprintf(w, "errkit.Wrap with %%+v: %+v\n", err)
+
+	// ISSUE: fmt.Errorf keeps the stack in the chain, but does not print it
+	outer := fmt.Errorf("handling request: %w", err)
+	fmt.Fprintf(w, "fmt.Errorf around errkit.Wrap: %+v\n", outer)
+	fmt.Fprintln(w, errtree.Chain(outer))
+
+	// Correct way: errkit.StackOf finds the stack anywhere in the chain
+	if st := errkit.StackOf(outer); st != nil {
+		fmt.Fprintf(w, "Created at %s:%d\n", st[0].File, st[0].Line)
+	}
+
+	// The wrap in uploadAsync adds the frames of its goroutine, which the
+	// stack of the worker does not hold, and errkit.StackTrace merges them
+	async := uploadAsync("report.csv")
+	fmt.Fprintf(w, "Frames recorded: %d where created, %d in all\n", len(errkit.StackOf(async)), len(errkit.StackTrace(async)))
+
+	// errkit.SetFrameFilter leaves the frames of the runtime out of %+v
+	errkit.SetFrameFilter(errkit.UserFrame)
+	fmt.Fprintf(w, "Across goroutines without runtime frames: %+v\n", async)
+	errkit.SetFrameFilter(nil)
+
+	// errkit.WithStack records a stack without changing the message
+	fmt.Fprintf(w, "errkit.WithStack: %v\n", errkit.WithStack(io.ErrClosedPipe))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "stack-traces",
+	Title:   "Recording stack traces with errkit",
+	Buggy:   `fmt.Errorf("reserving %d bytes: %w", n, err)`,
+	Correct: `errkit.Wrapf(err, "reserving %d bytes", n)`,
+	Explain: "fmt.Errorf records where nothing happened. errkit records the stack the first time an error is wrapped, prints it with %+v, and keeps the chain intact for errors.Is. Wrapping the error again records only the frames its stack does not hold, such as those of another goroutine, which errkit.StackTrace merges.",
+	Run:     Run,
+}
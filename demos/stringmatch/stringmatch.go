@@ -0,0 +1,54 @@
+// Package stringmatch demonstrates matching the message of an error
+// instead of the error: errors.Is finds fs.ErrPermission through the
+// *fs.PathError of a failed os.Open, whatever its message says.
+package stringmatch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+func customOperation() error {
+	file, err := os.Open("nonexistent.txt")
+	if err != nil {
+		// Correct way: errors.Is finds fs.ErrPermission through the
+		// *fs.PathError and the syscall.Errno it wraps
+		if errors.Is(err, fs.ErrPermission) {
+			return fmt.Errorf("permission issue: %w", err)
+		}
+		// ISSUE: Not using %w
+		return fmt.Errorf("could not open file: %v", err)
+	}
+	defer file.Close()
+
+	data := make([]byte, 100)
+	_, err = file.Read(data)
+	if err != nil && err != io.EOF {
+		// ISSUE: Direct comparison with io.EOF (though this one is allowed)
+		return fmt.Errorf("could not read file: %w", err)
+	}
+
+	return nil
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	if err := customOperation(); err != nil {
+		fmt.Fprintln(w, "Custom operation failed:", err)
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "string-matching",
+	Title:   "Matching error messages with strings.Contains",
+	Buggy:   `if strings.Contains(err.Error(), "permission denied") {`,
+	Correct: "if errors.Is(err, fs.ErrPermission) {",
+	Explain: "Messages are for people and change between versions and platforms. Sentinels such as fs.ErrPermission are part of the API and errors.Is finds them through wrapping.",
+	Run:     Run,
+}
@@ -0,0 +1,80 @@
+// Package structlog demonstrates logging errors with log/slog: an error
+// passed as a plain attribute is logged as its flat message, which keeps
+// none of its code, fields or wrapped errors, while errslog logs the whole
+// chain as structured attributes that a log pipeline can query.
+package structlog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errfields"
+	"github.com/kakkoyun/demo-error-lint/errslog"
+)
+
+// Sentinel errors
+var (
+	ErrCardDeclined = errcode.WithCode(errors.New("card declined"), errcode.InvalidInput)
+)
+
+func charge(order int, cents int) error {
+	err := fmt.Errorf("charging %d cents: %w", cents, ErrCardDeclined)
+	return errfields.With(err, "order", order, "provider", "acme-pay")
+}
+
+func checkout(order int) error {
+	if err := charge(order, 4200); err != nil {
+		return fmt.Errorf("checking out order %d: %w", order, err)
+	}
+	return nil
+}
+
+// newLogger returns a logger that writes to w without timestamps, so the
+// output of the demo does not change between runs.
+func newLogger(w io.Writer, json bool) *slog.Logger {
+	opts := &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}
+	if json {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	err := checkout(42)
+	logger := newLogger(w, false)
+
+	// ISSUE: The error is logged as its message, so the code, the fields
+	// and the wrapped errors cannot be queried
+	fmt.Fprintln(w, "Flat:")
+	logger.Error("checkout failed", "err", err)
+
+	// Correct way: errslog logs the chain as structured attributes
+	fmt.Fprintln(w, "Structured:")
+	logger.Error("checkout failed", errslog.Attr(err))
+
+	// The JSON handler nests the groups
+	fmt.Fprintln(w, "Structured as JSON:")
+	newLogger(w, true).Error("checkout failed", errslog.Attr(err))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "structured-logging",
+	Title:   "Logging error chains with log/slog",
+	Buggy:   "logger.Error(\"checkout failed\", \"err\", err)",
+	Correct: "logger.Error(\"checkout failed\", errslog.Attr(err))",
+	Explain: "A handler logs an error value as its message, so the code, the fields and the errors it wraps are lost to anything that queries the logs. errslog.Attr logs the chain as attributes: the message, the errcode code, the errfields fields and the type, message and code of each wrapped error. errslog.Chain can add the stack trace recorded by errkit.",
+	Run:     Run,
+}
@@ -0,0 +1,93 @@
+// Package swallow demonstrates why an error that is logged and then dropped
+// by returning nil leaves the caller believing the call succeeded, and how
+// returning the wrapped error lets the caller decide what to do, including
+// whether to log it. errlint reports these with the opt-in swallow check:
+//
+//	errlint -enable=swallow ./demos/swallow
+package swallow
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Sentinel errors
+var (
+	ErrQuotaExceeded = errors.New("quota exceeded")
+)
+
+// store keeps uploaded files in memory, up to a quota of bytes.
+type store struct {
+	files map[string][]byte
+	used  int
+	quota int
+}
+
+func newStore(quota int) *store {
+	return &store{files: make(map[string][]byte), quota: quota}
+}
+
+func (s *store) write(name string, data []byte) error {
+	if s.used+len(data) > s.quota {
+		return fmt.Errorf("writing %d bytes: %w", len(data), ErrQuotaExceeded)
+	}
+	s.files[name] = data
+	s.used += len(data)
+	return nil
+}
+
+// ISSUE: The error is logged, and the caller is told the upload worked
+func upload(logger *log.Logger, s *store, name string, data []byte) error {
+	if err := s.write(name, data); err != nil {
+		logger.Printf("uploading %s: %v", name, err)
+		return nil
+	}
+	return nil
+}
+
+// Correct way: return the error with what was being done, and let the caller
+// decide whether to log it, retry or tell the user
+func uploadReturned(s *store, name string, data []byte) error {
+	if err := s.write(name, data); err != nil {
+		return fmt.Errorf("uploading %s: %w", name, err)
+	}
+	return nil
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	logger := log.New(w, "log: ", 0)
+	data := []byte("report for the third quarter")
+
+	// ISSUE: The upload is logged as failed, yet reported as done, and the
+	// file is not there
+	s := newStore(16)
+	err := upload(logger, s, "report.txt", data)
+	_, stored := s.files["report.txt"]
+	fmt.Fprintf(w, "Swallowed: err = %v, stored = %t\n", err, stored)
+	fmt.Fprintf(w, "errors.Is(err, ErrQuotaExceeded): %t\n", errors.Is(err, ErrQuotaExceeded))
+
+	// Correct way: the caller sees the failure and can match the sentinel,
+	// here to log it once, with the whole story
+	s = newStore(16)
+	err = uploadReturned(s, "report.txt", data)
+	fmt.Fprintf(w, "Returned: %v\n", err)
+	fmt.Fprintf(w, "errors.Is(err, ErrQuotaExceeded): %t\n", errors.Is(err, ErrQuotaExceeded))
+	if errors.Is(err, ErrQuotaExceeded) {
+		logger.Printf("%v; ask the user to free some space", err)
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "swallow",
+	Title:   "Logging an error and returning nil",
+	Buggy:   "if err := s.write(name, data); err != nil {\n\tlogger.Printf(\"uploading %s: %v\", name, err)\n\treturn nil\n}",
+	Correct: "if err := s.write(name, data); err != nil {\n\treturn fmt.Errorf(\"uploading %s: %w\", name, err)\n}",
+	Explain: "After return nil the caller believes the upload worked and carries on without the file, and errors.Is has nothing to match. The log line is no substitute, since nobody reading it can act on the call. Returning the wrapped error lets the caller decide, and log it once with the whole story.",
+	Run:     Run,
+}
@@ -0,0 +1,89 @@
+// Package switchstmt demonstrates switches on error values and types,
+// which compare with == and assert types under the hood, instead of a
+// tagless switch with errors.Is and errors.As cases.
+package switchstmt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Custom error types for demonstration
+type NotFoundError struct {
+	Item string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s not found", e.Item)
+}
+
+// Sentinel errors
+var (
+	ErrInvalidInput = errors.New("invalid input")
+	ErrTimeout      = errors.New("operation timed out")
+)
+
+// Function that returns an error
+func fetchData() error {
+	return ErrInvalidInput
+}
+
+// Function that returns a wrapped error
+func processData() error {
+	err := fetchData()
+	if err != nil {
+		// ISSUE: Using %v instead of %w in fmt.Errorf
+		return fmt.Errorf("failed to process data: %v", err)
+	}
+	return nil
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	err := processData()
+
+	// ISSUE: Switch on error value
+	switch err {
+	case ErrInvalidInput:
+		fmt.Fprintln(w, "Invalid input")
+	case ErrTimeout:
+		fmt.Fprintln(w, "Timeout")
+	default:
+		fmt.Fprintln(w, "Unknown error")
+	}
+
+	// ISSUE: Type switch instead of errors.As
+	switch e := err.(type) {
+	case *NotFoundError:
+		fmt.Fprintf(w, "Not found: %s\n", e.Item)
+	default:
+		fmt.Fprintln(w, "Other error type")
+	}
+
+	// Correct way
+	err = fmt.Errorf("processing: %w", fetchData())
+	var notFound *NotFoundError
+	switch {
+	case errors.Is(err, ErrInvalidInput):
+		fmt.Fprintln(w, "Invalid input (correctly checked)")
+	case errors.Is(err, ErrTimeout):
+		fmt.Fprintln(w, "Timeout (correctly checked)")
+	case errors.As(err, &notFound):
+		fmt.Fprintf(w, "Not found (correctly checked): %s\n", notFound.Item)
+	default:
+		fmt.Fprintln(w, "Unknown error")
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "switch",
+	Title:   "Switches on error values and types",
+	Buggy:   "switch err {\ncase ErrInvalidInput:\n\t...\n}\n\nswitch e := err.(type) {\ncase *NotFoundError:\n\t...\n}",
+	Correct: "var notFound *NotFoundError\nswitch {\ncase errors.Is(err, ErrInvalidInput):\n\t...\ncase errors.As(err, &notFound):\n\t...\n}",
+	Explain: "Switches compare with == and type assertions under the hood, so they miss wrapped errors just the same. A tagless switch with errors.Is and errors.As cases keeps the shape and matches through wrapping.",
+	Run:     Run,
+}
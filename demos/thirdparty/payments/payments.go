@@ -0,0 +1,33 @@
+// Package payments is a fake client of a payment service, standing in for
+// a third-party SDK. Errors returned by Client wrap its sentinels with the
+// details of the request, so callers match them with errors.Is.
+package payments
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors
+var (
+	ErrCardDeclined = errors.New("card declined")
+	ErrRateLimited  = errors.New("rate limited")
+)
+
+// Client charges cards.
+type Client struct {
+	calls int
+}
+
+// Charge charges amount cents to the card. Cards ending in 0 are declined,
+// and every third call is rate limited.
+func (c *Client) Charge(card string, amount int) error {
+	c.calls++
+	switch {
+	case c.calls%3 == 0:
+		return fmt.Errorf("payments: charging %s: %w", card, ErrRateLimited)
+	case card[len(card)-1] == '0':
+		return fmt.Errorf("payments: charging %d cents to %s: %w", amount, card, ErrCardDeclined)
+	}
+	return nil
+}
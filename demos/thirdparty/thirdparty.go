@@ -0,0 +1,59 @@
+// Package thirdparty demonstrates comparing the sentinels of third-party
+// clients. The payments client wraps its sentinels, so == never matches
+// them, while the cache client documents with an //errlint:unwrapped
+// directive that it returns them as they are, as io.EOF is, so errlint
+// allows == for them. errlint learns both from the client packages
+// themselves, through analysis facts:
+//
+//	errlint ./demos/thirdparty/...
+package thirdparty
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/demos/thirdparty/cache"
+	"github.com/kakkoyun/demo-error-lint/demos/thirdparty/payments"
+)
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	client := &payments.Client{}
+
+	// ISSUE: The payments client wraps ErrCardDeclined, so == never matches
+	err := client.Charge("4000-0000", 1299)
+	if err == payments.ErrCardDeclined {
+		fmt.Fprintln(w, "Declined, ask for another card")
+	} else {
+		fmt.Fprintf(w, "== missed the declined card: %v\n", err)
+	}
+
+	// Correct way: errors.Is finds the sentinel behind the details
+	err = client.Charge("4000-0000", 1299)
+	fmt.Fprintf(w, "errors.Is(err, payments.ErrCardDeclined): %t\n", errors.Is(err, payments.ErrCardDeclined))
+	err = client.Charge("4000-1234", 1299)
+	fmt.Fprintf(w, "errors.Is(err, payments.ErrRateLimited): %t\n", errors.Is(err, payments.ErrRateLimited))
+
+	// Allowed: the cache client documents that it returns ErrNil unwrapped
+	kv := cache.NewClient(map[string]string{"greeting": "hello"})
+	for _, key := range []string{"greeting", "farewell"} {
+		v, err := kv.Get(key)
+		if err == cache.ErrNil {
+			fmt.Fprintf(w, "Cache miss for %q\n", key)
+			continue
+		}
+		fmt.Fprintf(w, "Cache hit for %q: %s\n", key, v)
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "third-party",
+	Title:   "Sentinels of third-party clients",
+	Buggy:   "if err == payments.ErrCardDeclined {",
+	Correct: "if errors.Is(err, payments.ErrCardDeclined) {",
+	Explain: "Clients usually wrap their sentinels with the details of the request, so == never matches. Only sentinels a package documents to be returned unwrapped, as io.EOF is, may be compared; //errlint:unwrapped tells errlint which ones.",
+	Run:     Run,
+}
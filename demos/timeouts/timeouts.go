@@ -0,0 +1,167 @@
+// Package timeouts demonstrates a domain timeout error unified with
+// context.DeadlineExceeded through an Is method, so that code that only
+// knows one of the two sentinels still recognizes the timeout, and the
+// pitfall of an Is method that matches more than the error is.
+package timeouts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// Sentinel errors
+var ErrTimeout = errors.New("timed out")
+
+// TimeoutError is returned when waiting runs out of time, whether its own
+// limit expired or the deadline of its context did first.
+type TimeoutError struct {
+	Op string
+	// Limit is the limit of the wait, if it expired.
+	Limit time.Duration
+	// Err is the error of the context, if its deadline expired first.
+	Err error
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Op, e.Err)
+	}
+	return fmt.Sprintf("%s: timed out after %v", e.Op, e.Limit)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes the error match both ErrTimeout and context.DeadlineExceeded,
+// and nothing else: a timeout is not a cancellation.
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout || target == context.DeadlineExceeded
+}
+
+// Function that polls ready on each tick of a ticker until it returns
+// true, the limit expires or ctx is done, and returns the error timeout
+// builds for the expired limit or the expired context
+func waitReady(ctx context.Context, op string, ready func() bool, limit time.Duration, timeout func(op string, limit time.Duration, ctxErr error) error) error {
+	ticker := time.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	timer := time.NewTimer(limit)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if ready() {
+				return nil
+			}
+		case <-timer.C:
+			return timeout(op, limit, nil)
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return timeout(op, limit, ctx.Err())
+			}
+			return fmt.Errorf("%s: %w", op, ctx.Err())
+		}
+	}
+}
+
+// ISSUE: The limit wraps the sentinel alone, so only code that knows
+// ErrTimeout recognizes it, and an expired context only matches
+// context.DeadlineExceeded
+func sentinelTimeout(op string, _ time.Duration, ctxErr error) error {
+	if ctxErr != nil {
+		return fmt.Errorf("%s: %w", op, ctxErr)
+	}
+	return fmt.Errorf("%s: %w", op, ErrTimeout)
+}
+
+// Correct way: both are a *TimeoutError, which matches both sentinels
+func unifiedTimeout(op string, limit time.Duration, ctxErr error) error {
+	return &TimeoutError{Op: op, Limit: limit, Err: ctxErr}
+}
+
+// broadTimeoutError is a timeout whose Is method matches every error a
+// context can end with.
+type broadTimeoutError struct {
+	TimeoutError
+}
+
+// ISSUE: Matching context.Canceled as well, since it also comes from a
+// context, makes every timeout look like a request its client gave up
+func (e *broadTimeoutError) Is(target error) bool {
+	return e.TimeoutError.Is(target) || target == context.Canceled
+}
+
+func broadTimeout(op string, limit time.Duration, ctxErr error) error {
+	return &broadTimeoutError{TimeoutError{Op: op, Limit: limit, Err: ctxErr}}
+}
+
+// Function of a generic layer, such as an HTTP middleware, that only knows
+// the errors of package context
+func status(err error) int {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return 504
+	case errors.Is(err, context.Canceled):
+		return 499
+	default:
+		return 500
+	}
+}
+
+// Function of the domain layer, that only knows its own sentinel
+func action(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "client gave up, nothing to report"
+	case errors.Is(err, ErrTimeout):
+		return "timed out, retry on another replica"
+	default:
+		return "failed"
+	}
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	never := func() bool { return false }
+	wait := func(timeout func(string, time.Duration, error) error, deadline time.Duration) error {
+		ctx, cancel := context.WithTimeout(context.Background(), deadline)
+		defer cancel()
+		return waitReady(ctx, "waiting for replica", never, 20*time.Millisecond, timeout)
+	}
+	show := func(label string, err error) {
+		fmt.Fprintf(w, "%s: %v\n  status %d, %s\n", label, err, status(err), action(err))
+	}
+
+	// ISSUE: the expired limit is a 500 for the middleware, and the expired
+	// deadline is not retried by the domain layer
+	show("Sentinel, limit", wait(sentinelTimeout, time.Second))
+	show("Sentinel, deadline", wait(sentinelTimeout, 5*time.Millisecond))
+
+	// Correct way: each layer recognizes both timeouts by its own sentinel
+	show("Unified, limit", wait(unifiedTimeout, time.Second))
+	show("Unified, deadline", wait(unifiedTimeout, 5*time.Millisecond))
+
+	// ISSUE: an Is method that matches too much hides the timeout behind a
+	// cancellation
+	show("Broad Is, limit", wait(broadTimeout, time.Second))
+
+	// A real cancellation is not a timeout for any of them
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	show("Canceled", waitReady(ctx, "waiting for replica", never, 20*time.Millisecond, unifiedTimeout))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "timeouts",
+	Title:   "Timeouts unified with context.DeadlineExceeded",
+	Buggy:   "return fmt.Errorf(\"%s: %w\", op, ErrTimeout)",
+	Correct: "func (e *TimeoutError) Is(target error) bool {\n\treturn target == ErrTimeout || target == context.DeadlineExceeded\n}",
+	Explain: "A package that times out with its own ErrTimeout is only understood by code that knows it, while generic code, such as middleware mapping errors to statuses, checks context.DeadlineExceeded. An error type whose Is method matches both sentinels is recognized by either check, whether its own limit or the deadline of its context expired. Matching is one way: errors.Is(context.DeadlineExceeded, ErrTimeout) stays false, so turn expired contexts into the error type where they are detected. Keep Is narrow: matching context.Canceled too, or any error whose message mentions a timeout, makes errors.Is answer yes to questions about other failures, such as whether the client gave up.",
+	Run:     Run,
+}
@@ -0,0 +1,107 @@
+// Package tracing demonstrates recording errors on OpenTelemetry spans:
+// span.RecordError keeps only the flat message and type of the outermost
+// error and leaves the status to the caller, while errotel sets the status
+// from the errcode code, adds an event for each error in the chain and
+// records whether the operation may be retried. The spans are exported to
+// memory and printed, so the demo shows what a tracing backend receives;
+// the tests of errotel check the same spans.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+	"github.com/kakkoyun/demo-error-lint/errotel"
+)
+
+// Sentinel errors
+var (
+	ErrInventoryDown = errcode.WithCode(errors.New("inventory unavailable"), errcode.Unavailable)
+	ErrNoSuchItem    = errcode.WithCode(errors.New("no such item"), errcode.NotFound)
+)
+
+func reserve(item string) error {
+	if item != "book" {
+		return fmt.Errorf("reserving %s: %w", item, ErrNoSuchItem)
+	}
+	return fmt.Errorf("reserving %s: %w", item, errkit.Retryable(ErrInventoryDown))
+}
+
+func checkout(ctx context.Context, tracer trace.Tracer, item string, record func(trace.Span, error)) error {
+	_, span := tracer.Start(ctx, "checkout "+item)
+	defer span.End()
+	if err := reserve(item); err != nil {
+		err = fmt.Errorf("checking out: %w", err)
+		record(span, err)
+		return err
+	}
+	return nil
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background())
+	tracer := provider.Tracer("demo")
+	ctx := context.Background()
+
+	// ISSUE: span.RecordError adds a single event with the flat message and
+	// leaves the span looking successful
+	_ = checkout(ctx, tracer, "book", func(span trace.Span, err error) {
+		span.RecordError(err)
+	})
+	fmt.Fprintln(w, "span.RecordError:")
+	printSpan(w, exporter.GetSpans()[0])
+	exporter.Reset()
+
+	// Correct way: errotel records the chain, the code and retryability
+	_ = checkout(ctx, tracer, "book", errotel.RecordError)
+	fmt.Fprintln(w, "errotel.RecordError:")
+	printSpan(w, exporter.GetSpans()[0])
+	exporter.Reset()
+
+	// A missing item is the fault of the caller, so the status is left
+	// unset
+	_ = checkout(ctx, tracer, "pencil", errotel.RecordError)
+	fmt.Fprintln(w, "errotel.RecordError for a caller error:")
+	printSpan(w, exporter.GetSpans()[0])
+}
+
+// printSpan prints the name, status, attributes and events of span.
+func printSpan(w io.Writer, span tracetest.SpanStub) {
+	fmt.Fprintf(w, "  span %q status=%s", span.Name, span.Status.Code)
+	if span.Status.Description != "" {
+		fmt.Fprintf(w, " %q", span.Status.Description)
+	}
+	fmt.Fprintln(w)
+	for _, kv := range span.Attributes {
+		fmt.Fprintf(w, "    %s=%s\n", kv.Key, kv.Value.Emit())
+	}
+	for _, event := range span.Events {
+		fmt.Fprintf(w, "    event %s:", event.Name)
+		for _, kv := range event.Attributes {
+			fmt.Fprintf(w, " %s=%q", kv.Key, kv.Value.Emit())
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "tracing",
+	Title:   "Recording error chains on OpenTelemetry spans",
+	Buggy:   "span.RecordError(err)",
+	Correct: "errotel.RecordError(span, err)",
+	Explain: "span.RecordError adds one event with the message of the outermost error and leaves the status of the span unset, so a failed operation looks successful. errotel.RecordError sets the status from the errcode code, leaving it unset for errors the caller caused, adds an exception event for each error in the chain and marks whether the operation may be retried.",
+	Run:     Run,
+}
@@ -0,0 +1,154 @@
+// Package usermessage demonstrates an API that logs the technical chain of
+// an error but answers clients with a message in their language, attached
+// with errmsg and rendered from the Accept-Language header of the request.
+package usermessage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errhttp"
+	"github.com/kakkoyun/demo-error-lint/errmsg"
+)
+
+// Sentinel errors
+var (
+	ErrNoID    = errcode.WithCode(errors.New("order IDs must not be empty"), errcode.InvalidInput)
+	ErrNoOrder = errcode.WithCode(errors.New("no such order"), errcode.NotFound)
+	ErrDBCrash = errors.New("database connection reset")
+)
+
+// renderer translates the user messages of the API.
+var renderer = newRenderer()
+
+func newRenderer() *errmsg.Renderer {
+	r := errmsg.NewRenderer("en")
+	r.Register("en", errmsg.Catalog{
+		"order.missing_id": "Please enter an order number.",
+		"order.not_found":  "We could not find order %s.",
+		errmsg.Unknown:     "Something went wrong on our side. Please try again later.",
+	})
+	r.Register("de", errmsg.Catalog{
+		"order.missing_id": "Bitte geben Sie eine Bestellnummer ein.",
+		"order.not_found":  "Wir konnten die Bestellung %s nicht finden.",
+		errmsg.Unknown:     "Bei uns ist etwas schiefgelaufen. Bitte versuchen Sie es später noch einmal.",
+	})
+	return r
+}
+
+// Function that queries an order in a fake database
+func queryOrder(id string) (string, error) {
+	switch id {
+	case "42":
+		return "2 books", nil
+	case "crash":
+		return "", fmt.Errorf("querying orders: %w", ErrDBCrash)
+	}
+	return "", fmt.Errorf("order %q: %w", id, ErrNoOrder)
+}
+
+// Function that looks an order up, and says what went wrong in terms the
+// user understands
+func getOrder(id string) (string, error) {
+	if id == "" {
+		return "", errmsg.WithUserMessage(ErrNoID, "order.missing_id")
+	}
+	order, err := queryOrder(id)
+	if errors.Is(err, ErrNoOrder) {
+		return "", errmsg.WithUserMessage(fmt.Errorf("getting order: %w", err), "order.not_found", id)
+	}
+	if err != nil {
+		return "", fmt.Errorf("getting order: %w", err)
+	}
+	return order, nil
+}
+
+// Handler that sends the message of the error to the client
+func legacyOrderHandler(w http.ResponseWriter, r *http.Request) {
+	order, err := getOrder(strings.TrimPrefix(r.URL.Path, "/legacy/orders/"))
+	if err != nil {
+		// ISSUE: The client gets the internal chain, in English
+		http.Error(w, err.Error(), errhttp.StatusOf(err))
+		return
+	}
+	io.WriteString(w, order)
+}
+
+// Handler that logs the chain of the error and sends the client its user
+// message in the language of the request
+func orderHandler(logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		order, err := getOrder(strings.TrimPrefix(r.URL.Path, "/orders/"))
+		if err != nil {
+			logger.Error("getting order failed", "path", r.URL.Path, "err", err)
+			http.Error(w, renderer.Render(err, errmsg.ParseAcceptLanguage(r.Header.Get("Accept-Language"))...), errhttp.StatusOf(err))
+			return
+		}
+		io.WriteString(w, order)
+	}
+}
+
+// newLogger returns a logger that writes to w without timestamps, so the
+// output of the demo does not change between runs.
+func newLogger(w io.Writer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/legacy/orders/", legacyOrderHandler)
+	mux.Handle("/orders/", orderHandler(newLogger(w)))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	requests := []struct{ path, language string }{
+		{"/legacy/orders/7", "de-CH, de;q=0.9"},
+		{"/legacy/orders/crash", "de"},
+		{"/orders/42", "en"},
+		{"/orders/7", "de-CH, de;q=0.9"},
+		{"/orders/7", "fr, en;q=0.5"},
+		{"/orders/", "pt-BR"},
+		{"/orders/crash", "de"},
+	}
+	for _, req := range requests {
+		httpReq, err := http.NewRequest(http.MethodGet, server.URL+req.path, nil)
+		if err != nil {
+			fmt.Fprintf(w, "GET %s: %v\n", req.path, err)
+			continue
+		}
+		httpReq.Header.Set("Accept-Language", req.language)
+		resp, err := http.DefaultClient.Do(httpReq)
+		if err != nil {
+			fmt.Fprintf(w, "GET %s: %v\n", req.path, err)
+			continue
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		fmt.Fprintf(w, "GET %s (%s): %s %s\n", req.path, req.language, resp.Status, strings.TrimSuffix(string(body), "\n"))
+	}
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "user-messages",
+	Title:   "Localized user messages",
+	Buggy:   "http.Error(w, err.Error(), errhttp.StatusOf(err))",
+	Correct: "logger.Error(\"getting order failed\", \"err\", err)\nhttp.Error(w, renderer.Render(err, errmsg.ParseAcceptLanguage(r.Header.Get(\"Accept-Language\"))...), errhttp.StatusOf(err))",
+	Explain: "The message of an error is written for the developers who read the logs: it holds internal details and is in one language. errmsg.WithUserMessage attaches a message key and its arguments to the error without changing its chain, so the handler logs the chain and a Renderer answers the client in the first language of Accept-Language it has a catalog for. Errors without a user message get a generic one.",
+	Run:     Run,
+}
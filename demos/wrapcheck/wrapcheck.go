@@ -0,0 +1,80 @@
+// Package wrapcheck demonstrates why errors returned as they are from other
+// packages lose the context a caller needs, and how wrapping them with %w
+// keeps both the story and the sentinel. errlint reports them with the
+// opt-in wrapcheck check:
+//
+//	errlint -enable=wrapcheck ./demos/wrapcheck
+package wrapcheck
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// ISSUE: The error of os.ReadFile is returned as it is
+func readPort(dir string) (int, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "port"))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+// ISSUE: The error is passed up again without saying what was being done
+func startServer(dir string) error {
+	_, err := readPort(dir)
+	return err
+}
+
+// Correct way: each layer adds what it was doing
+func readPortWrapped(dir string) (int, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "port"))
+	if err != nil {
+		return 0, fmt.Errorf("reading port: %w", err)
+	}
+	port, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("parsing port: %w", err)
+	}
+	return port, nil
+}
+
+func startServerWrapped(dir string) error {
+	if _, err := readPortWrapped(dir); err != nil {
+		return fmt.Errorf("starting server: %w", err)
+	}
+	return nil
+}
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	dir := filepath.Join(os.TempDir(), "errlint-wrapcheck-demo")
+
+	// ISSUE: The message says which file is missing, but not why it was read
+	err := startServer(dir)
+	fmt.Fprintf(w, "Bare: %v\n", err)
+
+	// Correct way: the message tells the whole story, and errors.Is still
+	// finds the sentinel
+	err = startServerWrapped(dir)
+	fmt.Fprintf(w, "Wrapped: %v\n", err)
+	fmt.Fprintf(w, "errors.Is(err, fs.ErrNotExist): %t\n", errors.Is(err, fs.ErrNotExist))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "wrapcheck",
+	Title:   "Errors returned without context",
+	Buggy:   "return 0, err",
+	Correct: `return 0, fmt.Errorf("reading port: %w", err)`,
+	Explain: "An error passed up as it is only says what failed deep down, such as a missing file, not what the program was doing. Wrapping it with %w at each layer tells the whole story, and errors.Is still finds the sentinel.",
+	Run:     Run,
+}
@@ -0,0 +1,59 @@
+// Package wrapping demonstrates formatting errors into fmt.Errorf messages
+// with %v, which drops them from the chain, instead of wrapping them with
+// %w or errkit.Wrap.
+package wrapping
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+	"github.com/kakkoyun/demo-error-lint/errtree"
+)
+
+// Run prints the output of the demo to w.
+func Run(w io.Writer) {
+	inputErr := errors.New("input validation failed")
+
+	// ISSUE: Using fmt.Errorf without %w
+	wrappedErr := fmt.Errorf("operation failed: %v", inputErr)
+	fmt.Fprintln(w, wrappedErr)
+	fmt.Fprintln(w, errtree.Chain(wrappedErr))
+	// The flattened error wraps nothing, so it is its own root cause
+	fmt.Fprintf(w, "Root cause: %v\n", errkit.Root(wrappedErr))
+
+	// Correct way
+	properlyWrappedErr := fmt.Errorf("operation failed: %w", inputErr)
+	fmt.Fprintln(w, properlyWrappedErr)
+	fmt.Fprintln(w, errtree.Chain(properlyWrappedErr))
+	fmt.Fprintf(w, "Root cause: %v\n", errkit.Root(properlyWrappedErr))
+
+	// Correct way, also recording the stack for %+v
+	stackWrappedErr := errkit.Wrap(inputErr, "operation failed")
+	fmt.Fprintln(w, stackWrappedErr)
+
+	err1 := errors.New("first error")
+	err2 := errors.New("second error")
+
+	// ISSUE: Multiple %v in fmt.Errorf
+	combinedErr := fmt.Errorf("multiple errors: %v and %v", err1, err2)
+	fmt.Fprintln(w, combinedErr)
+
+	// Correct way (Go 1.20+): multiple %w verbs wrap every error
+	properlyCombinedErr := fmt.Errorf("multiple errors: %w and %w", err1, err2)
+	fmt.Fprintln(w, properlyCombinedErr)
+	fmt.Fprintln(w, errtree.Chain(properlyCombinedErr))
+	fmt.Fprintf(w, "Root causes:\n%v\n", errkit.Root(properlyCombinedErr))
+}
+
+// Demo is the demo of the package, as the demo-error-lint command runs it.
+var Demo = demo.Demo{
+	Name:    "wrapping",
+	Title:   "Formatting errors with %v instead of wrapping them with %w",
+	Buggy:   `fmt.Errorf("operation failed: %v", err)` + "\n" + `fmt.Errorf("multiple errors: %v and %v", err1, err2)`,
+	Correct: `fmt.Errorf("operation failed: %w", err)` + "\n" + `fmt.Errorf("multiple errors: %w and %w", err1, err2)` + "\n" + `errkit.Wrap(err, "operation failed")`,
+	Explain: "%v formats the error into the message and drops it, so errors.Is and errors.As can no longer find it. %w keeps it in the chain; Go 1.20 and later accept several %w verbs.",
+	Run:     Run,
+}
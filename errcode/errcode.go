@@ -0,0 +1,125 @@
+// Package errcode attaches typed codes to errors, so callers can branch on
+// the kind of a failure without knowing which error type or sentinel
+// produced it.
+//
+// Wrap an error with a code where it is created:
+//
+//	var ErrInvalidInput = errcode.WithCode(errors.New("invalid input"), errcode.InvalidInput)
+//
+// or implement the Coder interface on an error type, and read the code
+// back anywhere up the chain:
+//
+//	switch errcode.CodeOf(err) {
+//	case errcode.NotFound:
+//		...
+//	}
+package errcode
+
+import (
+	"strconv"
+
+	"github.com/kakkoyun/demo-error-lint/errkit"
+)
+
+// Code classifies an error.
+type Code int
+
+const (
+	// OK is the code of a nil error.
+	OK Code = iota
+	// Unknown is the code of errors that carry no code.
+	Unknown
+	// InvalidInput means the caller supplied invalid arguments.
+	InvalidInput
+	// NotFound means a requested entity does not exist.
+	NotFound
+	// AlreadyExists means an entity the caller tried to create exists.
+	AlreadyExists
+	// PermissionDenied means the caller may not perform the operation.
+	PermissionDenied
+	// Timeout means the operation did not finish in time.
+	Timeout
+	// Unavailable means a dependency is temporarily unavailable.
+	Unavailable
+	// Internal means an invariant was broken.
+	Internal
+	// RateLimited means the caller made too many requests and should try
+	// again later.
+	RateLimited
+)
+
+var names = [...]string{
+	OK:               "OK",
+	Unknown:          "Unknown",
+	InvalidInput:     "InvalidInput",
+	NotFound:         "NotFound",
+	AlreadyExists:    "AlreadyExists",
+	PermissionDenied: "PermissionDenied",
+	Timeout:          "Timeout",
+	Unavailable:      "Unavailable",
+	Internal:         "Internal",
+	RateLimited:      "RateLimited",
+}
+
+func (c Code) String() string {
+	if c >= 0 && int(c) < len(names) {
+		return names[c]
+	}
+	return "Code(" + strconv.Itoa(int(c)) + ")"
+}
+
+// Codes returns the codes the package defines, in the order of their
+// values, so that code names can be parsed without listing them.
+func Codes() []Code {
+	codes := make([]Code, len(names))
+	for i := range codes {
+		codes[i] = Code(i)
+	}
+	return codes
+}
+
+// Coder is implemented by errors that carry a code.
+type Coder interface {
+	error
+	Code() Code
+}
+
+// WithCode returns an error that formats as err and carries code. It
+// returns nil if err is nil.
+func WithCode(err error, code Code) error {
+	if err == nil {
+		return nil
+	}
+	return &codeError{err: err, code: code}
+}
+
+// codeError is the error returned by WithCode.
+type codeError struct {
+	err  error
+	code Code
+}
+
+func (e *codeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codeError) Unwrap() error {
+	return e.err
+}
+
+func (e *codeError) Code() Code {
+	return e.code
+}
+
+// CodeOf returns the code of the first error in the chain of err that
+// implements Coder. It returns OK if err is nil and Unknown if no error in
+// the chain carries a code.
+func CodeOf(err error) Code {
+	if err == nil {
+		return OK
+	}
+	if coder, ok := errkit.As[Coder](err); ok {
+		return coder.Code()
+	}
+	return Unknown
+}
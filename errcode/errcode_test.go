@@ -0,0 +1,75 @@
+package errcode_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+)
+
+var errBoom = errors.New("boom")
+
+// TestCodeOf checks the code of nil, uncoded and coded errors, wrapped or
+// not.
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want errcode.Code
+	}{
+		{"nil", nil, errcode.OK},
+		{"uncoded", errBoom, errcode.Unknown},
+		{"coded", errcode.WithCode(errBoom, errcode.NotFound), errcode.NotFound},
+		{"wrapped", fmt.Errorf("loading: %w", errcode.WithCode(errBoom, errcode.Timeout)), errcode.Timeout},
+		{"errkit wrapped", errkit.Wrap(errcode.WithCode(errBoom, errcode.Internal), "loading"), errcode.Internal},
+		{"outermost wins", errcode.WithCode(errcode.WithCode(errBoom, errcode.NotFound), errcode.Unavailable), errcode.Unavailable},
+		{"joined", errors.Join(errBoom, errcode.WithCode(errBoom, errcode.RateLimited)), errcode.RateLimited},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errcode.CodeOf(tt.err); got != tt.want {
+				t.Errorf("CodeOf(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWithCode checks that the error WithCode returns formats and unwraps
+// to the error it was given.
+func TestWithCode(t *testing.T) {
+	if err := errcode.WithCode(nil, errcode.NotFound); err != nil {
+		t.Errorf("WithCode(nil) = %v, want nil", err)
+	}
+	err := errcode.WithCode(errBoom, errcode.NotFound)
+	if err.Error() != errBoom.Error() || !errors.Is(err, errBoom) {
+		t.Errorf("WithCode(errBoom) = %v, want an error that formats and matches as errBoom", err)
+	}
+}
+
+// TestCodes checks that Codes lists every code once, in order, with a
+// name, and that String names codes outside the list by value.
+func TestCodes(t *testing.T) {
+	codes := errcode.Codes()
+	if len(codes) == 0 || codes[0] != errcode.OK || codes[len(codes)-1] != errcode.RateLimited {
+		t.Fatalf("Codes = %v, want OK to RateLimited", codes)
+	}
+	names := make(map[string]bool)
+	for i, c := range codes {
+		if int(c) != i {
+			t.Errorf("Codes()[%d] = %d", i, int(c))
+		}
+		name := c.String()
+		if name == "" || names[name] {
+			t.Errorf("code %d has the empty or duplicate name %q", int(c), name)
+		}
+		names[name] = true
+	}
+	if got := errcode.Code(-1).String(); got != "Code(-1)" {
+		t.Errorf("Code(-1).String() = %q, want Code(-1)", got)
+	}
+	if got := errcode.Code(len(codes)).String(); got != fmt.Sprintf("Code(%d)", len(codes)) {
+		t.Errorf("String of the code past the last = %q", got)
+	}
+}
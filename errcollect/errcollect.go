@@ -0,0 +1,97 @@
+// Package errcollect collects the errors of a batch operation, so it can
+// report every failure at once instead of stopping at the first.
+//
+// Add the error of each step, or run steps concurrently with Go, and join
+// the failures with Err:
+//
+//	var c errcollect.Collector
+//	for _, u := range users {
+//		c.Add(validate(u))
+//	}
+//	if err := c.Err(); err != nil {
+//		return fmt.Errorf("importing users: %w", err)
+//	}
+//
+// errors.Is and errors.As look through the joined error, so callers can
+// still match each of the failures.
+package errcollect
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// Collector collects errors. The zero value is an empty collector, ready
+// to use. A Collector is safe for concurrent use, but must not be copied
+// after first use.
+type Collector struct {
+	mu sync.Mutex
+	wg sync.WaitGroup
+	// errs holds the errors in the order Add and Go were called, with nil
+	// for the functions started by Go that did not fail or are still
+	// running.
+	errs []error
+}
+
+// Add adds err, if it is not nil.
+func (c *Collector) Add(err error) {
+	if err == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errs = append(c.errs, err)
+}
+
+// Go calls fn in a new goroutine and adds the error it returns. The error
+// takes the place of the call of Go among the others, not of the time fn
+// returns.
+func (c *Collector) Go(fn func() error) {
+	c.mu.Lock()
+	i := len(c.errs)
+	c.errs = append(c.errs, nil)
+	c.mu.Unlock()
+
+	c.wg.Go(func() {
+		err := fn()
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.errs[i] = err
+	})
+}
+
+// Err waits for the functions started by Go to return and returns the
+// collected errors joined with errors.Join, in the order they were added,
+// or nil if there are none. An error added more than once, such as the
+// same sentinel from several steps, is only included the first time;
+// errors that merely match it, such as the sentinel wrapped with details,
+// are all included.
+func (c *Collector) Err() error {
+	c.wg.Wait()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	for _, err := range c.errs {
+		if err != nil && !contains(errs, err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// contains reports whether errs holds err itself.
+func contains(errs []error, err error) bool {
+	// Comparing errors of types that are not comparable, such as
+	// structs with slices, would panic.
+	if !reflect.TypeOf(err).Comparable() {
+		return false
+	}
+	for _, e := range errs {
+		if reflect.TypeOf(e) == reflect.TypeOf(err) && any(e) == any(err) {
+			return true
+		}
+	}
+	return false
+}
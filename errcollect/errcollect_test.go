@@ -0,0 +1,79 @@
+package errcollect_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kakkoyun/demo-error-lint/errcollect"
+)
+
+var errInvalid = errors.New("invalid")
+
+// listError is an error of a type that is not comparable.
+type listError []string
+
+func (e listError) Error() string { return fmt.Sprint([]string(e)) }
+
+// joined returns the errors err joins, or nil.
+func joined(err error) []error {
+	if u, ok := err.(interface{ Unwrap() []error }); ok {
+		return u.Unwrap()
+	}
+	return nil
+}
+
+// TestCollector checks the errors Err joins: in the order they were
+// added, without nil errors and without repeating the same error.
+func TestCollector(t *testing.T) {
+	var c errcollect.Collector
+	if err := c.Err(); err != nil {
+		t.Errorf("Err of an empty collector = %v, want nil", err)
+	}
+
+	detailed := fmt.Errorf("user 2: %w", errInvalid)
+	c.Add(nil)
+	c.Add(errInvalid)
+	c.Add(detailed)
+	c.Add(errInvalid)
+	c.Add(listError{"a"})
+	c.Add(listError{"a"})
+	err := c.Err()
+
+	want := []string{"invalid", "user 2: invalid", "[a]", "[a]"}
+	var got []string
+	for _, e := range joined(err) {
+		got = append(got, e.Error())
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Err joins %q, want %q", got, want)
+	}
+	if !errors.Is(err, errInvalid) {
+		t.Errorf("errors.Is(Err, errInvalid) = false, want true")
+	}
+}
+
+// TestGo checks that the errors of the functions started by Go keep the
+// order of the calls of Go, not of the time they return.
+func TestGo(t *testing.T) {
+	var c errcollect.Collector
+	for i := range 5 {
+		c.Go(func() error {
+			time.Sleep(time.Duration(5-i) * time.Millisecond)
+			if i%2 == 1 {
+				return nil
+			}
+			return fmt.Errorf("step %d", i)
+		})
+	}
+	c.Add(errInvalid)
+
+	var got []string
+	for _, e := range joined(c.Err()) {
+		got = append(got, e.Error())
+	}
+	if want := []string{"step 0", "step 2", "step 4", "invalid"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Err joins %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,121 @@
+// Package errexit maps errors to process exit codes, so a command line
+// program decides how it exits in one place instead of calling os.Exit
+// wherever something fails.
+//
+// Return errors from a run function and let Run print and exit:
+//
+//	func main() {
+//		errexit.Run(run)
+//	}
+//
+// Code picks the exit code from the chain of the error: the code of an
+// error that carries one, such as an *exec.ExitError or an error created
+// with WithExitCode, or else one derived from its errcode.Code and context
+// errors, so errors wrapping ErrInvalidInput exit with 2 and timeouts with
+// 124.
+package errexit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+)
+
+// Exit codes of the errors Code maps by default
+const (
+	// Failure is the exit code of errors without a more specific one.
+	Failure = 1
+	// Usage is the exit code of errors with the errcode.InvalidInput
+	// code, such as invalid flags or arguments.
+	Usage = 2
+	// Timeout is the exit code of errors with the errcode.Timeout code
+	// and of context.DeadlineExceeded, as timeout(1) exits.
+	Timeout = 124
+	// Interrupted is the exit code of context.Canceled, as shells report
+	// a program stopped by SIGINT.
+	Interrupted = 130
+)
+
+// ExitCoder is implemented by errors that carry an exit code, such as
+// *exec.ExitError.
+type ExitCoder interface {
+	error
+	ExitCode() int
+}
+
+// WithExitCode returns an error that formats as err and exits with code,
+// or with Failure if code is not between 1 and 255. It returns nil if err
+// is nil.
+func WithExitCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{err: err, code: code}
+}
+
+// exitError is the error returned by WithExitCode.
+type exitError struct {
+	err  error
+	code int
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+func (e *exitError) ExitCode() int {
+	return e.code
+}
+
+// Code returns the exit code for err: 0 if err is nil, the code of the
+// first ExitCoder in its chain, Timeout for context.DeadlineExceeded and
+// the errcode.Timeout code, Interrupted for context.Canceled, Usage for
+// the errcode.InvalidInput code, and Failure otherwise. ExitCoders that
+// report a code outside 1 to 255, as *exec.ExitError does with -1 for a
+// process killed by a signal, exit with Failure: an error never exits 0,
+// which os.Exit(256) would on Unix, where only the low 8 bits of the
+// status are kept.
+func Code(err error) int {
+	if err == nil {
+		return 0
+	}
+	var coder ExitCoder
+	if errors.As(err, &coder) {
+		if code := coder.ExitCode(); code > 0 && code <= 255 {
+			return code
+		}
+		return Failure
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return Timeout
+	case errors.Is(err, context.Canceled):
+		return Interrupted
+	}
+	switch errcode.CodeOf(err) {
+	case errcode.Timeout:
+		return Timeout
+	case errcode.InvalidInput:
+		return Usage
+	}
+	return Failure
+}
+
+// Run calls fn and, if it fails, prints the error to standard error after
+// the name of the program and exits with the code Code returns for it.
+func Run(fn func() error) {
+	err := fn()
+	if err == nil {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s: %v\n", filepath.Base(os.Args[0]), err)
+	os.Exit(Code(err))
+}
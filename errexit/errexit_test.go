@@ -0,0 +1,61 @@
+package errexit_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errexit"
+)
+
+var errBoom = errors.New("boom")
+
+// TestCode checks the exit code of each kind of error, including exit
+// codes that os.Exit cannot report.
+func TestCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, 0},
+		{"plain", errBoom, errexit.Failure},
+		{"exit code", errexit.WithExitCode(errBoom, 3), 3},
+		{"wrapped exit code", fmt.Errorf("running: %w", errexit.WithExitCode(errBoom, 3)), 3},
+		{"exit code 255", errexit.WithExitCode(errBoom, 255), 255},
+		{"exit code 0", errexit.WithExitCode(errBoom, 0), errexit.Failure},
+		{"negative exit code", errexit.WithExitCode(errBoom, -1), errexit.Failure},
+		{"exit code 256", errexit.WithExitCode(errBoom, 256), errexit.Failure},
+		{"exit code 512", errexit.WithExitCode(errBoom, 512), errexit.Failure},
+		{"exit code before context", errexit.WithExitCode(context.DeadlineExceeded, 3), 3},
+		{"deadline", fmt.Errorf("waiting: %w", context.DeadlineExceeded), errexit.Timeout},
+		{"canceled", fmt.Errorf("waiting: %w", context.Canceled), errexit.Interrupted},
+		{"timeout code", errcode.WithCode(errBoom, errcode.Timeout), errexit.Timeout},
+		{"invalid input code", errcode.WithCode(errBoom, errcode.InvalidInput), errexit.Usage},
+		{"other code", errcode.WithCode(errBoom, errcode.NotFound), errexit.Failure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errexit.Code(tt.err); got != tt.want {
+				t.Errorf("Code(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWithExitCode checks that the error WithExitCode returns formats and
+// unwraps to the error it was given.
+func TestWithExitCode(t *testing.T) {
+	if err := errexit.WithExitCode(nil, 3); err != nil {
+		t.Errorf("WithExitCode(nil, 3) = %v, want nil", err)
+	}
+	err := errexit.WithExitCode(errBoom, 3)
+	if !errors.Is(err, errBoom) {
+		t.Errorf("errors.Is(%v, errBoom) = false, want true", err)
+	}
+	if err.Error() != errBoom.Error() {
+		t.Errorf("Error() = %q, want %q", err.Error(), errBoom.Error())
+	}
+}
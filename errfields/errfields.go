@@ -0,0 +1,122 @@
+// Package errfields attaches structured key/value fields to errors.
+//
+// Fields replace one-off error types that exist only to carry data, such
+// as a NotFoundError with an Item field:
+//
+//	return errfields.With(ErrNotFound, "item", name, "shelf", shelf)
+//
+// Any layer can read the fields back, wherever they are in the chain:
+//
+//	item, ok := errfields.Get(err, "item")
+//
+// Errors returned by With implement slog.LogValuer, and Attr builds an
+// attribute with the message and all fields of a chain, so fields end up
+// in structured logs:
+//
+//	logger.Error("request failed", errfields.Attr(err))
+package errfields
+
+import (
+	"errors"
+	"log/slog"
+)
+
+// With returns an error that formats as err and carries the fields in
+// args, which are key/value pairs or slog.Attr values as accepted by
+// slog.Logger.Info. It returns nil if err is nil.
+func With(err error, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return &fieldsError{err: err, attrs: slog.Group("", args...).Value.Group()}
+}
+
+// fieldsError is the error returned by With.
+type fieldsError struct {
+	err   error
+	attrs []slog.Attr
+}
+
+func (e *fieldsError) Error() string {
+	return e.err.Error()
+}
+
+func (e *fieldsError) Unwrap() error {
+	return e.err
+}
+
+// LogValue logs the error as a group with its message and the fields of
+// its chain.
+func (e *fieldsError) LogValue() slog.Value {
+	return value(e)
+}
+
+// Fields returns the fields of every error in the chain of err, including
+// the errors joined by errors.Join. If several errors have a field with the
+// same key, the outermost one wins.
+func Fields(err error) []slog.Attr {
+	var attrs []slog.Attr
+	seen := make(map[string]bool)
+	walk(err, func(err error) {
+		for _, a := range own(err) {
+			if !seen[a.Key] {
+				seen[a.Key] = true
+				attrs = append(attrs, a)
+			}
+		}
+	})
+	return attrs
+}
+
+// Get returns the value of the field with the given key, as Fields would
+// return it.
+func Get(err error, key string) (any, bool) {
+	for _, a := range Fields(err) {
+		if a.Key == key {
+			return a.Value.Any(), true
+		}
+	}
+	return nil, false
+}
+
+// Attr returns an attribute with key "error" whose value is a group with
+// the message of err and its fields.
+func Attr(err error) slog.Attr {
+	return slog.Attr{Key: "error", Value: value(err)}
+}
+
+func value(err error) slog.Value {
+	if err == nil {
+		return slog.AnyValue(nil)
+	}
+	attrs := append([]slog.Attr{slog.String("msg", err.Error())}, Fields(err)...)
+	return slog.GroupValue(attrs...)
+}
+
+// own returns the fields err itself carries, not counting the errors it
+// wraps.
+func own(err error) []slog.Attr {
+	// errors.As finds the first *fieldsError anywhere below err; walk
+	// visits the others, so only use it if it is err itself.
+	var fe *fieldsError
+	if errors.As(err, &fe) && any(fe) == any(err) {
+		return fe.attrs
+	}
+	return nil
+}
+
+// walk calls fn for err and every error it wraps, in depth-first order.
+func walk(err error, fn func(error)) {
+	if err == nil {
+		return
+	}
+	fn(err)
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		walk(u.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, err := range u.Unwrap() {
+			walk(err, fn)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package errfields_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/kakkoyun/demo-error-lint/errfields"
+)
+
+var errNotFound = errors.New("not found")
+
+// TestFields checks the fields of chains and trees of errors, and that
+// the outermost field with a key wins.
+func TestFields(t *testing.T) {
+	inner := errfields.With(errNotFound, "item", "sku-1", "shelf", 3)
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, "[]"},
+		{"none", errNotFound, "[]"},
+		{"own", inner, "[item=sku-1 shelf=3]"},
+		{"wrapped", fmt.Errorf("reserving: %w", inner), "[item=sku-1 shelf=3]"},
+		{"outermost wins", errfields.With(fmt.Errorf("reserving: %w", inner), "item", "sku-2", "order", 7), "[item=sku-2 order=7 shelf=3]"},
+		{"attrs", errfields.With(errNotFound, slog.Int("n", 1), slog.Group("g", "k", "v")), "[n=1 g=[k=v]]"},
+		{"joined", errors.Join(errfields.With(errNotFound, "a", 1), errfields.With(errNotFound, "b", 2, "a", 3)), "[a=1 b=2]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmt.Sprint(errfields.Fields(tt.err)); got != tt.want {
+				t.Errorf("Fields = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWith checks that the error With returns formats and matches as the
+// error it was given, and that Get reads its fields back.
+func TestWith(t *testing.T) {
+	if err := errfields.With(nil, "item", "sku-1"); err != nil {
+		t.Errorf("With(nil) = %v, want nil", err)
+	}
+	err := fmt.Errorf("reserving: %w", errfields.With(errNotFound, "item", "sku-1"))
+	if got := fmt.Sprint(err); got != "reserving: not found" || !errors.Is(err, errNotFound) {
+		t.Errorf("err = %v, want an error that formats and matches as errNotFound", err)
+	}
+	if v, ok := errfields.Get(err, "item"); !ok || v != "sku-1" {
+		t.Errorf("Get(item) = %v, %t, want sku-1", v, ok)
+	}
+	if v, ok := errfields.Get(err, "shelf"); ok {
+		t.Errorf("Get(shelf) = %v, want nothing", v)
+	}
+}
+
+// TestLog checks the group Attr and the LogValue method log for an error.
+func TestLog(t *testing.T) {
+	err := fmt.Errorf("reserving: %w", errfields.With(errNotFound, "item", "sku-1"))
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Error("failed", errfields.Attr(err), "own", errfields.With(errNotFound, "shelf", 3))
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"msg":   "failed",
+		"error": map[string]any{"msg": "reserving: not found", "item": "sku-1"},
+		"own":   map[string]any{"msg": "not found", "shelf": float64(3)},
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("logged %v, want %v", got, want)
+	}
+}
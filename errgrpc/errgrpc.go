@@ -0,0 +1,198 @@
+// Package errgrpc converts errors to and from gRPC statuses.
+//
+// ToStatus maps the errcode.Code of an error to a gRPC code, and records
+// the name of the registered sentinel it matches, if any, as an
+// errdetails.ErrorInfo detail. FromStatus turns the status back into an
+// error that carries the same code and unwraps to the same sentinel, so
+// clients can use errcode.CodeOf and errors.Is as if the error had not
+// crossed the wire. An errkit.RetryAfter hint travels as an
+// errdetails.RetryInfo detail, and errkit.RetryAfter reads it back from the
+// error on the client:
+//
+//	server := grpc.NewServer(grpc.UnaryInterceptor(errgrpc.UnaryServerInterceptor(registry)))
+//	conn, err := grpc.NewClient(target, grpc.WithUnaryInterceptor(errgrpc.UnaryClientInterceptor(registry)))
+package errgrpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+)
+
+// Domain is the domain of the errdetails.ErrorInfo details that name
+// registered sentinels.
+const Domain = "errkit.demo-error-lint.kakkoyun.github.com"
+
+var grpcCodes = map[errcode.Code]codes.Code{
+	errcode.OK:               codes.OK,
+	errcode.Unknown:          codes.Unknown,
+	errcode.InvalidInput:     codes.InvalidArgument,
+	errcode.NotFound:         codes.NotFound,
+	errcode.AlreadyExists:    codes.AlreadyExists,
+	errcode.PermissionDenied: codes.PermissionDenied,
+	errcode.Timeout:          codes.DeadlineExceeded,
+	errcode.Unavailable:      codes.Unavailable,
+	errcode.Internal:         codes.Internal,
+	errcode.RateLimited:      codes.ResourceExhausted,
+}
+
+var errCodes = map[codes.Code]errcode.Code{}
+
+func init() {
+	for code, grpcCode := range grpcCodes {
+		errCodes[grpcCode] = code
+	}
+}
+
+// grpcStatus is implemented by the errors of the status package, and by
+// Error.
+type grpcStatus interface {
+	GRPCStatus() *status.Status
+}
+
+// ToStatus returns the gRPC status for err. If an error in the chain of
+// err already has a status other than OK, ToStatus returns that status
+// unchanged, with its details. Otherwise the code follows errcode.CodeOf,
+// or the context error err matches, and the message is the message of err.
+// A code without a gRPC code, and errcode.OK, map to codes.Unknown, so that
+// the status of an error is never OK. If reg is not nil and err matches a
+// sentinel registered in it, the status has an errdetails.ErrorInfo detail
+// with the name of the sentinel as reason. If err has an errkit.RetryAfter
+// hint, the status has an errdetails.RetryInfo detail with the hint as
+// retry delay.
+func ToStatus(err error, reg *errkit.Registry) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+	var gs grpcStatus
+	if errors.As(err, &gs) {
+		if st := gs.GRPCStatus(); st.Code() != codes.OK {
+			return st
+		}
+	}
+
+	code, ok := grpcCodes[errcode.CodeOf(err)]
+	if !ok || code == codes.OK {
+		code = codes.Unknown
+	}
+	if code == codes.Unknown {
+		code = status.FromContextError(err).Code()
+	}
+	st := status.New(code, err.Error())
+	var details []protoadapt.MessageV1
+	if reg != nil {
+		if name, ok := reg.Name(err); ok {
+			details = append(details, &errdetails.ErrorInfo{Reason: name, Domain: Domain})
+		}
+	}
+	if d, ok := errkit.RetryAfter(err); ok {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(d)})
+	}
+	if len(details) == 0 {
+		return st
+	}
+	withDetails, detailsErr := st.WithDetails(details...)
+	if detailsErr != nil {
+		return st
+	}
+	return withDetails
+}
+
+// FromStatus returns the error for st, or nil if its code is OK. The error
+// is an *Error; if reg is not nil and st names a sentinel registered in
+// it, the error unwraps to that sentinel, and if st has an
+// errdetails.RetryInfo detail, errkit.RetryAfter returns its retry delay.
+func FromStatus(st *status.Status, reg *errkit.Registry) error {
+	if st.Code() == codes.OK {
+		return nil
+	}
+	e := &Error{status: st}
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if reg == nil || e.sentinel != nil || detail.GetDomain() != Domain {
+				continue
+			}
+			if sentinel, ok := reg.Lookup(detail.GetReason()); ok {
+				e.sentinel = sentinel
+			}
+		case *errdetails.RetryInfo:
+			e.retryAfter = detail.GetRetryDelay()
+		}
+	}
+	return e
+}
+
+// Error is an error rebuilt from a gRPC status by FromStatus.
+type Error struct {
+	status     *status.Status
+	sentinel   error
+	retryAfter *durationpb.Duration
+}
+
+func (e *Error) Error() string {
+	return e.status.Message()
+}
+
+// Unwrap returns the registered sentinel named by the status, or nil.
+func (e *Error) Unwrap() error {
+	return e.sentinel
+}
+
+// GRPCStatus returns the status the error was rebuilt from, so that
+// status.FromError and ToStatus return it unchanged.
+func (e *Error) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// RetryAfter returns the retry delay of the errdetails.RetryInfo detail of
+// the status, which errkit.RetryAfter reports.
+func (e *Error) RetryAfter() (time.Duration, bool) {
+	if e.retryAfter == nil {
+		return 0, false
+	}
+	return e.retryAfter.AsDuration(), true
+}
+
+// Code returns the errcode.Code of the gRPC code of the status.
+func (e *Error) Code() errcode.Code {
+	if code, ok := errCodes[e.status.Code()]; ok {
+		return code
+	}
+	return errcode.Unknown
+}
+
+// UnaryServerInterceptor returns an interceptor that converts the errors
+// returned by handlers with ToStatus.
+func UnaryServerInterceptor(reg *errkit.Registry) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, ToStatus(err, reg).Err()
+		}
+		return resp, nil
+	}
+}
+
+// UnaryClientInterceptor returns an interceptor that converts the errors
+// returned by calls with FromStatus.
+func UnaryClientInterceptor(reg *errkit.Registry) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err == nil {
+			return nil
+		}
+		st, _ := status.FromError(err)
+		return FromStatus(st, reg)
+	}
+}
@@ -0,0 +1,116 @@
+package errgrpc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errgrpc"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+)
+
+var (
+	errBoom = errors.New("boom")
+
+	registry   = errkit.NewRegistry()
+	errMissing = registry.Register("test.missing", errors.New("missing"))
+)
+
+// TestToStatus checks the codes and messages of the statuses of errors,
+// and that no error gets the OK code.
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+		msg  string
+	}{
+		{"nil", nil, codes.OK, ""},
+		{"uncoded", errBoom, codes.Unknown, "boom"},
+		{"ok code", errcode.WithCode(errBoom, errcode.OK), codes.Unknown, "boom"},
+		{"unknown code", errcode.WithCode(errBoom, errcode.Unknown), codes.Unknown, "boom"},
+		{"unmapped code", errcode.WithCode(errBoom, errcode.Code(100)), codes.Unknown, "boom"},
+		{"not found", fmt.Errorf("loading: %w", errcode.WithCode(errBoom, errcode.NotFound)), codes.NotFound, "loading: boom"},
+		{"invalid input", errcode.WithCode(errBoom, errcode.InvalidInput), codes.InvalidArgument, "boom"},
+		{"rate limited", errcode.WithCode(errBoom, errcode.RateLimited), codes.ResourceExhausted, "boom"},
+		{"deadline", fmt.Errorf("waiting: %w", context.DeadlineExceeded), codes.DeadlineExceeded, "waiting: context deadline exceeded"},
+		{"canceled", fmt.Errorf("waiting: %w", context.Canceled), codes.Canceled, "waiting: context canceled"},
+		{"status", fmt.Errorf("calling: %w", status.Error(codes.Aborted, "aborted")), codes.Aborted, "aborted"},
+		{"ok status", fmt.Errorf("calling: %w", okStatusError{}), codes.Unknown, "calling: ok"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := errgrpc.ToStatus(tt.err, nil)
+			if st.Code() != tt.code || st.Message() != tt.msg {
+				t.Errorf("ToStatus(%v) = %v %q, want %v %q", tt.err, st.Code(), st.Message(), tt.code, tt.msg)
+			}
+		})
+	}
+}
+
+// okStatusError is an error that reports the OK status.
+type okStatusError struct{}
+
+func (okStatusError) Error() string              { return "ok" }
+func (okStatusError) GRPCStatus() *status.Status { return status.New(codes.OK, "") }
+
+// TestRoundTrip checks that FromStatus rebuilds the code, the registered
+// sentinel and the retry delay of the status ToStatus returns.
+func TestRoundTrip(t *testing.T) {
+	err := errkit.WithRetryAfter(fmt.Errorf("reserving: %w", errcode.WithCode(errMissing, errcode.NotFound)), 3*time.Second)
+	st := errgrpc.ToStatus(err, registry)
+
+	var reason string
+	var delay time.Duration
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			reason = d.GetReason()
+		case *errdetails.RetryInfo:
+			delay = d.GetRetryDelay().AsDuration()
+		}
+	}
+	if reason != "test.missing" || delay != 3*time.Second {
+		t.Errorf("details name %q and delay %v, want test.missing and 3s", reason, delay)
+	}
+
+	got := errgrpc.FromStatus(st, registry)
+	if !errors.Is(got, errMissing) {
+		t.Errorf("FromStatus = %v, which does not match the sentinel", got)
+	}
+	if code := errcode.CodeOf(got); code != errcode.NotFound {
+		t.Errorf("CodeOf(FromStatus) = %v, want NotFound", code)
+	}
+	if d, ok := errkit.RetryAfter(got); !ok || d != 3*time.Second {
+		t.Errorf("RetryAfter(FromStatus) = %v, %t, want 3s", d, ok)
+	}
+	if again := errgrpc.ToStatus(got, nil); again != st {
+		t.Errorf("ToStatus(FromStatus(st)) is not st")
+	}
+}
+
+// TestFromStatus checks the errors of statuses with the OK code, codes
+// errcode does not know, and sentinels that are not registered.
+func TestFromStatus(t *testing.T) {
+	if err := errgrpc.FromStatus(status.New(codes.OK, ""), registry); err != nil {
+		t.Errorf("FromStatus(OK) = %v, want nil", err)
+	}
+	err := errgrpc.FromStatus(status.New(codes.DataLoss, "lost"), registry)
+	if got := fmt.Sprint(err); got != "lost" || errcode.CodeOf(err) != errcode.Unknown {
+		t.Errorf("FromStatus(DataLoss) = %v with code %v, want lost with Unknown", err, errcode.CodeOf(err))
+	}
+	st := errgrpc.ToStatus(errMissing, registry)
+	if err := errgrpc.FromStatus(st, errkit.NewRegistry()); errors.Is(err, errMissing) || fmt.Sprint(err) != errMissing.Error() {
+		t.Errorf("FromStatus with another registry = %v, want an error without the sentinel", err)
+	}
+	if err := errgrpc.FromStatus(st, nil); errors.Unwrap(err) != nil {
+		t.Errorf("FromStatus with no registry unwraps to %v", errors.Unwrap(err))
+	}
+}
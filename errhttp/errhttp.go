@@ -0,0 +1,159 @@
+// Package errhttp turns errors into HTTP responses.
+//
+// StatusOf maps an error to a status code using its errcode.Code, and
+// WriteError renders it as an RFC 7807 problem+json document. Handler lets
+// HTTP handlers return errors instead of writing error responses
+// themselves:
+//
+//	http.Handle("/items/", errhttp.Handler(func(w http.ResponseWriter, r *http.Request) error {
+//		item, err := store.Get(r.Context(), r.URL.Path)
+//		if err != nil {
+//			return fmt.Errorf("loading item: %w", err)
+//		}
+//		return json.NewEncoder(w).Encode(item)
+//	}))
+//
+// Errors with an errkit.RetryAfter hint, such as those of a rate limiter
+// returned with errkit.WithRetryAfter, get a Retry-After header, which
+// clients read back with ParseRetryAfter.
+package errhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+)
+
+// ContentType is the media type of problem details documents.
+const ContentType = "application/problem+json"
+
+// StatusClientClosedRequest is the non-standard status reported for
+// requests the client canceled before the handler returned.
+const StatusClientClosedRequest = 499
+
+var statuses = map[errcode.Code]int{
+	errcode.InvalidInput:     http.StatusBadRequest,
+	errcode.NotFound:         http.StatusNotFound,
+	errcode.AlreadyExists:    http.StatusConflict,
+	errcode.PermissionDenied: http.StatusForbidden,
+	errcode.Timeout:          http.StatusGatewayTimeout,
+	errcode.Unavailable:      http.StatusServiceUnavailable,
+	errcode.Internal:         http.StatusInternalServerError,
+	errcode.RateLimited:      http.StatusTooManyRequests,
+}
+
+// StatusCoder is implemented by errors that choose their own HTTP status.
+type StatusCoder interface {
+	error
+	StatusCode() int
+}
+
+// StatusOf returns the HTTP status for err, or http.StatusOK if err is
+// nil. The first error in the chain that implements StatusCoder decides
+// the status; otherwise the status follows the errcode.Code of err. Errors
+// without a code, or with errcode.OK, which no failure should carry, get
+// http.StatusGatewayTimeout if they match context.DeadlineExceeded,
+// StatusClientClosedRequest if they match context.Canceled, and
+// http.StatusInternalServerError otherwise.
+func StatusOf(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	var sc StatusCoder
+	if errors.As(err, &sc) {
+		return sc.StatusCode()
+	}
+	if status, ok := statuses[errcode.CodeOf(err)]; ok {
+		return status
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return StatusClientClosedRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// Problem is an RFC 7807 problem details document.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Code is the errcode.Code of the error, as an extension member.
+	Code string `json:"code,omitempty"`
+}
+
+// ProblemOf returns the problem details for err. The message of err is
+// only included as the detail of client errors, so server errors do not
+// leak internal details.
+func ProblemOf(err error) *Problem {
+	status := StatusOf(err)
+	p := &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+	}
+	if p.Title == "" {
+		p.Title = "Client Closed Request"
+	}
+	if code := errcode.CodeOf(err); code != errcode.Unknown && code != errcode.OK {
+		p.Code = code.String()
+	}
+	if status >= 400 && status < 500 {
+		p.Detail = err.Error()
+	}
+	return p
+}
+
+// WriteError writes err to w as a problem+json response for r. If err has
+// an errkit.RetryAfter hint, the response has a Retry-After header with
+// the hint in seconds, rounded up.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	p := ProblemOf(err)
+	p.Instance = r.URL.Path
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if d, ok := errkit.RetryAfter(err); ok {
+		w.Header().Set("Retry-After", strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10))
+	}
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// ParseRetryAfter returns the delay a Retry-After header value asks for,
+// given either as a number of seconds or as an HTTP date, which is taken
+// relative to now. Dates in the past give a delay of zero. It reports false
+// if value is empty or malformed.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(t.Sub(now), 0), true
+}
+
+// Handler is an HTTP handler that returns an error instead of writing an
+// error response. ServeHTTP writes errors it returns with WriteError.
+//
+// The handler must not write to w before it returns an error, since the
+// status and headers of the response cannot be changed afterwards.
+type Handler func(w http.ResponseWriter, r *http.Request) error
+
+func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h(w, r); err != nil {
+		WriteError(w, r, err)
+	}
+}
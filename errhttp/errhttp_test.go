@@ -0,0 +1,126 @@
+package errhttp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errhttp"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+)
+
+var errBoom = errors.New("boom")
+
+// statusError is an error that chooses its own status.
+type statusError struct{ status int }
+
+func (e statusError) Error() string   { return http.StatusText(e.status) }
+func (e statusError) StatusCode() int { return e.status }
+
+// TestStatusOf checks the status of errors with each code, of context
+// errors, and of errors that choose their own status.
+func TestStatusOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, http.StatusOK},
+		{"uncoded", errBoom, http.StatusInternalServerError},
+		{"ok", errcode.WithCode(errBoom, errcode.OK), http.StatusInternalServerError},
+		{"unknown", errcode.WithCode(errBoom, errcode.Unknown), http.StatusInternalServerError},
+		{"invalid input", errcode.WithCode(errBoom, errcode.InvalidInput), http.StatusBadRequest},
+		{"not found", errcode.WithCode(errBoom, errcode.NotFound), http.StatusNotFound},
+		{"already exists", errcode.WithCode(errBoom, errcode.AlreadyExists), http.StatusConflict},
+		{"permission denied", errcode.WithCode(errBoom, errcode.PermissionDenied), http.StatusForbidden},
+		{"timeout", errcode.WithCode(errBoom, errcode.Timeout), http.StatusGatewayTimeout},
+		{"unavailable", errcode.WithCode(errBoom, errcode.Unavailable), http.StatusServiceUnavailable},
+		{"internal", errcode.WithCode(errBoom, errcode.Internal), http.StatusInternalServerError},
+		{"rate limited", errcode.WithCode(errBoom, errcode.RateLimited), http.StatusTooManyRequests},
+		{"wrapped", fmt.Errorf("loading: %w", errcode.WithCode(errBoom, errcode.NotFound)), http.StatusNotFound},
+		{"deadline", fmt.Errorf("loading: %w", context.DeadlineExceeded), http.StatusGatewayTimeout},
+		{"canceled", fmt.Errorf("loading: %w", context.Canceled), errhttp.StatusClientClosedRequest},
+		{"status coder", fmt.Errorf("loading: %w", statusError{http.StatusTeapot}), http.StatusTeapot},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errhttp.StatusOf(tt.err); got != tt.want {
+				t.Errorf("StatusOf(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestHandler checks the problem+json responses Handler writes for the
+// errors of a handler.
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		status     int
+		detail     string
+		code       string
+		retryAfter string
+	}{
+		{"client error", errcode.WithCode(errBoom, errcode.NotFound), http.StatusNotFound, "boom", "NotFound", ""},
+		{"server error", errcode.WithCode(errBoom, errcode.Internal), http.StatusInternalServerError, "", "Internal", ""},
+		{"ok code", errcode.WithCode(errBoom, errcode.OK), http.StatusInternalServerError, "", "", ""},
+		{"retry after", errkit.WithRetryAfter(errcode.WithCode(errBoom, errcode.RateLimited), 1500*time.Millisecond), http.StatusTooManyRequests, "boom", "RateLimited", "2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := errhttp.Handler(func(w http.ResponseWriter, r *http.Request) error { return tt.err })
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest("GET", "/items/1", nil))
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Header().Get("Content-Type"); got != errhttp.ContentType {
+				t.Errorf("Content-Type = %q, want %q", got, errhttp.ContentType)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.retryAfter)
+			}
+			var p errhttp.Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+				t.Fatal(err)
+			}
+			want := errhttp.Problem{Type: "about:blank", Title: http.StatusText(tt.status), Status: tt.status, Detail: tt.detail, Instance: "/items/1", Code: tt.code}
+			if p != want {
+				t.Errorf("problem = %+v, want %+v", p, want)
+			}
+		})
+	}
+}
+
+// TestParseRetryAfter checks Retry-After values in seconds and as HTTP
+// dates.
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"120", 2 * time.Minute, true},
+		{"0", 0, true},
+		{now.Add(time.Minute).Format(http.TimeFormat), time.Minute, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"", 0, false},
+		{"-1", 0, false},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := errhttp.ParseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseRetryAfter(%q) = %v, %t, want %v, %t", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+// Package errjson serializes the tree of errors an error wraps to JSON and
+// rebuilds it, so an error can cross a service boundary or be written to a
+// log and read back without losing what callers match on.
+//
+// Marshal records the message, type and errcode code of every error in
+// the tree, the errfields fields it carries, and the names of the errors
+// registered as sentinels in an errkit.Registry:
+//
+//	data, err := errjson.Marshal(err, registry)
+//
+//	{"message":"reserving sku-42: out of stock","type":"*fmt.wrapError","wrapped":[
+//	  {"message":"out of stock","type":"*errors.errorString","sentinel":"inventory.out_of_stock"}]}
+//
+// Unmarshal rebuilds the tree with the registered sentinels themselves in
+// it, so errors.Is matches them, and errcode.CodeOf and errfields.Get
+// find the same codes and fields:
+//
+//	err, jsonErr := errjson.Unmarshal(data, registry)
+//	if errors.Is(err, ErrOutOfStock) {
+//		...
+//	}
+package errjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errfields"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+)
+
+// node is the JSON form of an error.
+type node struct {
+	Message string `json:"message"`
+	// Type is the type of the error, as formatted by %T.
+	Type string `json:"type"`
+	// Code is the name of the errcode.Code the error itself carries.
+	Code string `json:"code,omitempty"`
+	// Sentinel is the name of the error in the registry, if it is
+	// registered. The errors a sentinel wraps are not recorded.
+	Sentinel string `json:"sentinel,omitempty"`
+	// Fields are the errfields fields the error itself carries.
+	Fields  map[string]any `json:"fields,omitempty"`
+	Wrapped []*node        `json:"wrapped,omitempty"`
+}
+
+// Marshal returns the JSON encoding of the tree of errors wrapped by err,
+// or null if err is nil. If reg is not nil, the errors registered in it
+// are recorded by name. Marshal fails if the value of a field cannot be
+// encoded as JSON.
+func Marshal(err error, reg *errkit.Registry) ([]byte, error) {
+	return json.Marshal(newNode(err, reg))
+}
+
+func newNode(err error, reg *errkit.Registry) *node {
+	if err == nil {
+		return nil
+	}
+	n := &node{Message: err.Error(), Type: fmt.Sprintf("%T", err)}
+	// errors.As finds the first match anywhere below err; only use it if
+	// it is err itself. An error rebuilt by Unmarshal keeps the type and
+	// code of the original.
+	var rebuilt *Error
+	var coder errcode.Coder
+	switch {
+	case errors.As(err, &rebuilt) && same(rebuilt, err):
+		n.Type = rebuilt.typ
+		if rebuilt.code != errcode.Unknown {
+			n.Code = rebuilt.code.String()
+		}
+	case errors.As(err, &coder) && same(coder, err):
+		n.Code = coder.Code().String()
+	}
+	if name, ok := sentinelName(err, reg); ok {
+		n.Sentinel = name
+		return n
+	}
+	wrapped := errkit.Wrapped(err)
+	for _, w := range wrapped {
+		n.Wrapped = append(n.Wrapped, newNode(w, reg))
+	}
+	// The fields of the errors below err are recorded with them.
+	if len(n.Wrapped) == 1 {
+		own := ownFields(err, wrapped[0])
+		if len(own) > 0 {
+			n.Fields = make(map[string]any)
+			for _, a := range own {
+				n.Fields[a.Key] = fieldValue(a.Value)
+			}
+		}
+	}
+	return n
+}
+
+// sentinelName returns the name err itself is registered under in reg.
+func sentinelName(err error, reg *errkit.Registry) (string, bool) {
+	if reg == nil {
+		return "", false
+	}
+	// Registry.Name matches anywhere in the chain of err; only use it if
+	// the sentinel is err itself.
+	name, ok := reg.Name(err)
+	if !ok {
+		return "", false
+	}
+	sentinel, _ := reg.Lookup(name)
+	return name, same(sentinel, err)
+}
+
+// same reports whether a and b are the same error. Comparing errors of a
+// type that is not comparable with == panics, as it does for the sentinels
+// errkit.Guarded returns in guarded builds; errors of such a type are the
+// same if the Is method of a says so.
+func same(a, b error) bool {
+	t := reflect.TypeOf(a)
+	if t != reflect.TypeOf(b) {
+		return false
+	}
+	if t.Comparable() {
+		return any(a) == any(b)
+	}
+	//errlint:ignore ERRLINT002 the Is method of a itself decides, not its chain
+	x, ok := a.(interface{ Is(error) bool })
+	return ok && x.Is(b)
+}
+
+// ownFields returns the fields of err that the error it wraps does not
+// carry with the same value.
+func ownFields(err, wrapped error) []slog.Attr {
+	below := make(map[string]slog.Value)
+	for _, a := range errfields.Fields(wrapped) {
+		below[a.Key] = a.Value
+	}
+	var own []slog.Attr
+	for _, a := range errfields.Fields(err) {
+		if v, ok := below[a.Key]; !ok || !v.Equal(a.Value) {
+			own = append(own, a)
+		}
+	}
+	return own
+}
+
+// fieldValue returns the value of a field as it is encoded in JSON.
+func fieldValue(v slog.Value) any {
+	v = v.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		group := make(map[string]any)
+		for _, a := range v.Group() {
+			group[a.Key] = fieldValue(a.Value)
+		}
+		return group
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	}
+	if err, ok := v.Any().(error); ok {
+		return err.Error()
+	}
+	return v.Any()
+}
+
+// Unmarshal returns the error encoded in data by Marshal, or nil if data
+// is null, and an error if data is not valid JSON. Errors recorded as
+// sentinels registered in reg are those sentinels, errors with fields are
+// created with errfields.With, and the other errors are *Error values.
+// Field values are what encoding/json decodes them as, such as float64
+// for numbers.
+func Unmarshal(data []byte, reg *errkit.Registry) (error, error) {
+	var n *node
+	if err := json.Unmarshal(data, &n); err != nil {
+		return nil, fmt.Errorf("errjson: %w", err)
+	}
+	return n.rebuild(reg), nil
+}
+
+func (n *node) rebuild(reg *errkit.Registry) error {
+	if n == nil {
+		return nil
+	}
+	if reg != nil && n.Sentinel != "" {
+		if sentinel, ok := reg.Lookup(n.Sentinel); ok {
+			return sentinel
+		}
+	}
+	var wrapped []error
+	for _, child := range n.Wrapped {
+		wrapped = append(wrapped, child.rebuild(reg))
+	}
+	if len(n.Fields) > 0 && len(wrapped) == 1 {
+		// errfields.With formats as the error it wraps and carries the
+		// fields, which is all there was to the original error.
+		var args []any
+		for _, key := range slices.Sorted(maps.Keys(n.Fields)) {
+			args = append(args, slog.Any(key, n.Fields[key]))
+		}
+		return errfields.With(wrapped[0], args...)
+	}
+	return &Error{msg: n.Message, typ: n.Type, code: parseCode(n.Code), wrapped: wrapped}
+}
+
+// parseCode returns the errcode.Code named name, or errcode.Unknown.
+func parseCode(name string) errcode.Code {
+	for _, c := range errcode.Codes() {
+		if c.String() == name {
+			return c
+		}
+	}
+	return errcode.Unknown
+}
+
+// Error is an error rebuilt by Unmarshal. It formats as the original error
+// and wraps the rebuilt errors the original error wrapped.
+type Error struct {
+	msg, typ string
+	code     errcode.Code
+	wrapped  []error
+}
+
+func (e *Error) Error() string {
+	return e.msg
+}
+
+// Type returns the type of the original error, as formatted by %T.
+func (e *Error) Type() string {
+	return e.typ
+}
+
+func (e *Error) Unwrap() []error {
+	return e.wrapped
+}
+
+// Code returns the errcode.Code of the original error, or else the first
+// code found in the errors it wraps, so errcode.CodeOf returns what it
+// returned for the original error.
+func (e *Error) Code() errcode.Code {
+	if e.code != errcode.Unknown {
+		return e.code
+	}
+	for _, err := range e.wrapped {
+		if code := errcode.CodeOf(err); code != errcode.Unknown {
+			return code
+		}
+	}
+	return errcode.Unknown
+}
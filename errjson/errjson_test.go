@@ -0,0 +1,125 @@
+package errjson_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errfields"
+	"github.com/kakkoyun/demo-error-lint/errjson"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+)
+
+// TestGuarded checks that Marshal records a sentinel errkit.Guarded
+// returns by name, and that Unmarshal rebuilds it, without comparing it
+// with ==. Run it with go test -tags errkitguard or -race to guard the
+// sentinel.
+func TestGuarded(t *testing.T) {
+	reg := errkit.NewRegistry()
+	errGuarded := reg.Register("test.guarded", errkit.Guarded(errors.New("guarded")))
+	errOther := reg.Register("test.other", errkit.Guarded(errors.New("other")))
+
+	for _, err := range []error{
+		errGuarded,
+		fmt.Errorf("loading: %w", errGuarded),
+		errors.Join(errOther, fmt.Errorf("loading: %w", errGuarded)),
+	} {
+		data, jsonErr := errjson.Marshal(err, reg)
+		if jsonErr != nil {
+			t.Fatal(jsonErr)
+		}
+		if !strings.Contains(string(data), `"sentinel":"test.guarded"`) {
+			t.Errorf("Marshal(%v) = %s, want the sentinel test.guarded", err, data)
+		}
+		got, jsonErr := errjson.Unmarshal(data, reg)
+		if jsonErr != nil {
+			t.Fatal(jsonErr)
+		}
+		if !errors.Is(got, errGuarded) {
+			t.Errorf("Unmarshal(%s) = %v, which does not match the guarded sentinel", data, got)
+		}
+	}
+}
+
+// fields returns the fields of err as key=value, sorted.
+func fields(err error) []string {
+	var kv []string
+	for _, a := range errfields.Fields(err) {
+		kv = append(kv, a.String())
+	}
+	slices.Sort(kv)
+	return kv
+}
+
+// TestRoundTrip checks that Unmarshal rebuilds an error that Marshal
+// encoded with the same messages, types, codes, fields and sentinels.
+func TestRoundTrip(t *testing.T) {
+	reg := errkit.NewRegistry()
+	errOutOfStock := reg.Register("inventory.out_of_stock", errors.New("out of stock"))
+	errMissing := errors.New("missing")
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"sentinel", errOutOfStock},
+		{"wrapped sentinel", fmt.Errorf("reserving sku-42: %w", errOutOfStock)},
+		{"code", errcode.WithCode(fmt.Errorf("loading: %w", errMissing), errcode.NotFound)},
+		{"fields", fmt.Errorf("reserving: %w", errfields.With(errOutOfStock, "sku", "sku-42", "count", 3))},
+		{"joined", errors.Join(errcode.WithCode(errOutOfStock, errcode.Unavailable), errMissing)},
+		{"errkit", errkit.Wrap(errOutOfStock, "checkout")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := errjson.Marshal(tt.err, reg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := errjson.Unmarshal(data, reg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Error() != tt.err.Error() {
+				t.Errorf("message = %q, want %q", got.Error(), tt.err.Error())
+			}
+			if errors.Is(tt.err, errOutOfStock) != errors.Is(got, errOutOfStock) {
+				t.Errorf("errors.Is(errOutOfStock) = %t, want %t", errors.Is(got, errOutOfStock), errors.Is(tt.err, errOutOfStock))
+			}
+			if errors.Is(got, errMissing) {
+				t.Errorf("an unregistered error matches after the round trip")
+			}
+			if got, want := errcode.CodeOf(got), errcode.CodeOf(tt.err); got != want {
+				t.Errorf("code = %v, want %v", got, want)
+			}
+			// The fields are rebuilt in the order of their keys.
+			if got, want := fields(got), fields(tt.err); !slices.Equal(got, want) {
+				t.Errorf("fields = %q, want %q", got, want)
+			}
+			again, err := errjson.Marshal(got, reg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(again) != string(data) {
+				t.Errorf("Marshal after Unmarshal =\n%s\nwant\n%s", again, data)
+			}
+		})
+	}
+}
+
+// TestNil checks that nil encodes as null and null decodes as nil, and
+// that Unmarshal reports invalid JSON.
+func TestNil(t *testing.T) {
+	data, err := errjson.Marshal(nil, nil)
+	if err != nil || string(data) != "null" {
+		t.Errorf("Marshal(nil) = %s, %v, want null", data, err)
+	}
+	if got, err := errjson.Unmarshal([]byte("null"), nil); got != nil || err != nil {
+		t.Errorf("Unmarshal(null) = %v, %v, want nil", got, err)
+	}
+	if _, err := errjson.Unmarshal([]byte("{"), nil); err == nil {
+		t.Errorf("Unmarshal of invalid JSON did not fail")
+	}
+}
@@ -0,0 +1,147 @@
+package errkit
+
+import (
+	"errors"
+	"iter"
+)
+
+// Chain returns an iterator over err and every error it wraps, in
+// depth-first order: err, then the tree below the first error it wraps,
+// then the tree below the next one. It follows both Unwrap() error and
+// Unwrap() []error, the way errors.Is and errors.As do, and yields nothing
+// if err is nil.
+//
+//	for e := range errkit.Chain(err) {
+//		fmt.Printf("%T %q\n", e, e.Error())
+//	}
+func Chain(err error) iter.Seq[error] {
+	return func(yield func(error) bool) {
+		walk(err, yield)
+	}
+}
+
+// walk yields err and the errors it wraps, and reports whether to go on.
+func walk(err error, yield func(error) bool) bool {
+	if err == nil {
+		return true
+	}
+	if !yield(err) {
+		return false
+	}
+	for _, w := range Wrapped(err) {
+		if !walk(w, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// Wrapped returns the errors err wraps itself, in the order its Unwrap
+// method returns them, leaving out nil errors.
+func Wrapped(err error) []error {
+	var wrapped []error
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		wrapped = []error{u.Unwrap()}
+	case interface{ Unwrap() []error }:
+		wrapped = u.Unwrap()
+	}
+	var out []error
+	for _, w := range wrapped {
+		if w != nil {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// Find returns the first error in the chain of err, in the order of Chain,
+// whose type is T, or implements T if T is an interface. Unlike errors.As,
+// it does not call the As methods of the errors, so it only finds errors
+// that are in the chain themselves.
+//
+//	if pe, ok := errkit.Find[*fs.PathError](err); ok {
+//		fmt.Println(pe.Path)
+//	}
+func Find[T any](err error) (T, bool) {
+	for e := range Chain(err) {
+		//errlint:ignore ERRLINT002 Chain visits the wrapped errors one by one
+		if t, ok := e.(T); ok {
+			return t, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// As returns the first error in the chain of err that is of type T, or
+// implements T if T is an interface, as errors.As finds it, including
+// through the As methods of the errors. Without a pointer target and
+// without reflection, it neither allocates nor walks the chain twice,
+// unless an error in the chain has an As method; then it leaves the walk
+// to errors.As.
+//
+//	if coder, ok := errkit.As[errcode.Coder](err); ok {
+//		fmt.Println(coder.Code())
+//	}
+func As[T any](err error) (T, bool) {
+	if t, ok, done := assertChain[T](err); done {
+		return t, ok
+	}
+	var t T
+	ok := errors.As(err, &t)
+	return t, ok
+}
+
+// assertChain looks for T in the chain of err with type assertions, in
+// the order of errors.As, and reports whether it is done: false if it
+// reached an error with an As method before finding T.
+func assertChain[T any](err error) (t T, ok, done bool) {
+	for err != nil {
+		//errlint:ignore ERRLINT002 each error of the chain is asserted on its own
+		if t, ok := err.(T); ok {
+			return t, true, true
+		}
+		//errlint:ignore ERRLINT002 errors.As calls the As method of this error itself
+		if _, ok := err.(interface{ As(any) bool }); ok {
+			return t, false, false
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, w := range u.Unwrap() {
+				if t, ok, done := assertChain[T](w); ok || !done {
+					return t, ok, done
+				}
+			}
+			return t, false, true
+		default:
+			return t, false, true
+		}
+	}
+	return t, false, true
+}
+
+// Root returns the root cause of err: the deepest error in its chain, which
+// wraps no other error. An error that wraps nothing is its own root. If the
+// chain branches, through errors.Join or several %w verbs, Root returns
+// every leaf of the tree, in the order of Chain, joined with errors.Join;
+// the leaves are what the Unwrap() []error method of the result returns. A
+// leaf reached through several branches is returned for each of them. Root
+// returns nil if err is nil.
+//
+//	err := fmt.Errorf("saving %s: %w", name, fs.ErrPermission)
+//	fmt.Println(errkit.Root(err)) // permission denied
+func Root(err error) error {
+	var leaves []error
+	for e := range Chain(err) {
+		if len(Wrapped(e)) == 0 {
+			leaves = append(leaves, e)
+		}
+	}
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+	return errors.Join(leaves...)
+}
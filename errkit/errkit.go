@@ -0,0 +1,108 @@
+// Package errkit wraps errors with a message and the stack of the call that
+// created them, and keeps a registry of sentinel errors by name.
+//
+// Errors returned by Wrap, Wrapf and WithStack implement Unwrap, so
+// errors.Is and errors.As see through them, and print their stack trace
+// when formatted with %+v:
+//
+//	err := errkit.Wrap(ErrInvalidInput, "processing data")
+//	fmt.Printf("%v\n", err)  // processing data: invalid input
+//	fmt.Printf("%+v\n", err) // the same, followed by the stack trace
+//
+// Each frame is recorded once per chain: wrapping an error that already
+// carries a stack adds the message and only the frames the stack does not
+// hold, such as those of another goroutine. StackOf returns the stack of
+// where the failure happened, and StackTrace merges it with the frames of
+// the wraps. SetFrameFilter leaves frames, such as those of the runtime,
+// out of %+v.
+//
+// Chain iterates over an error and every error it wraps, depth first, Root
+// returns the errors at the bottom of that chain, and Find and As return the
+// first error of a type in it, As with the As methods errors.As calls:
+//
+//	if pe, ok := errkit.Find[*fs.PathError](err); ok {
+//		fmt.Println(pe.Path)
+//	}
+//
+// A Registry gives sentinel errors stable names, so they can be sent to
+// another process and matched with errors.Is on the other side.
+package errkit
+
+import (
+	"fmt"
+	"io"
+)
+
+// Wrap returns an error that formats as msg followed by the message of err,
+// and records the stack of its caller. It returns nil if err is nil.
+func Wrap(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	pcs, own := callers(err)
+	return &stackError{err: err, msg: msg, stack: pcs, own: own}
+}
+
+// Wrapf is like Wrap with a message formatted as with fmt.Sprintf.
+func Wrapf(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	pcs, own := callers(err)
+	return &stackError{err: err, msg: fmt.Sprintf(format, args...), stack: pcs, own: own}
+}
+
+// WithStack returns an error that formats as err and records the stack of
+// its caller. It returns err unchanged if err is nil or already carries a
+// stack.
+func WithStack(err error) error {
+	if err == nil || nearest(err) != nil {
+		return err
+	}
+	pcs, own := callers(err)
+	return &stackError{err: err, stack: pcs, own: own}
+}
+
+// stackError is the error returned by Wrap, Wrapf and WithStack.
+type stackError struct {
+	err error
+	msg string
+	// stack is the stack of the call that created the error, or nil if
+	// the stack of err already holds all of its frames.
+	stack []uintptr
+	// own is the number of frames at the top of stack that the stack of
+	// err does not hold, all of them if err has none.
+	own int
+}
+
+func (e *stackError) Error() string {
+	if e.msg == "" {
+		return e.err.Error()
+	}
+	return e.msg + ": " + e.err.Error()
+}
+
+func (e *stackError) Unwrap() error {
+	return e.err
+}
+
+// Format prints the stack trace StackTrace returns after the message with
+// %+v, without the frames the filter set with SetFrameFilter drops, and
+// only the message otherwise.
+func (e *stackError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		io.WriteString(s, e.Error())
+		if s.Flag('+') {
+			st := StackTrace(e)
+			if keep := frameFilter.Load(); keep != nil {
+				st = st.Filter(*keep)
+			}
+			st.Format(s, verb)
+		}
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	}
+}
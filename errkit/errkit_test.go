@@ -0,0 +1,474 @@
+package errkit_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+)
+
+// depth is the number of layers the benchmarks wrap errors in.
+const depth = 10
+
+var errNotFound = errors.New("not found")
+
+// sink keeps the compiler from optimizing the measured code away.
+var sink any
+
+// wrapErrorf wraps err depth times with fmt.Errorf.
+func wrapErrorf(err error) error {
+	for i := range depth {
+		err = fmt.Errorf("layer %d: %w", i, err)
+	}
+	return err
+}
+
+// wrapErrkit wraps err depth times with errkit.Wrapf.
+func wrapErrkit(err error) error {
+	for i := range depth {
+		err = errkit.Wrapf(err, "layer %d", i)
+	}
+	return err
+}
+
+// BenchmarkWrap measures a chain of errkit.Wrapf calls, which record the
+// frames the chain lacks, against a chain of fmt.Errorf calls.
+func BenchmarkWrap(b *testing.B) {
+	b.Run("fmt.Errorf", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			sink = wrapErrorf(errNotFound)
+		}
+	})
+	b.Run("errkit.Wrapf", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			sink = wrapErrkit(errNotFound)
+		}
+	})
+}
+
+// BenchmarkAs measures errkit.As against errors.As, looking for an
+// interface at the bottom of a chain of errkit wrappers.
+func BenchmarkAs(b *testing.B) {
+	err := wrapErrkit(errcode.WithCode(errNotFound, errcode.NotFound))
+	b.Run("errors.As", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			var coder errcode.Coder
+			sink = errors.As(err, &coder)
+		}
+	})
+	b.Run("errkit.As", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			sink, _ = errkit.As[errcode.Coder](err)
+		}
+	})
+}
+
+// BenchmarkCodeOf measures errcode.CodeOf, which finds the code with
+// errkit.As, through chains of fmt.Errorf and errkit wrappers.
+func BenchmarkCodeOf(b *testing.B) {
+	coded := errcode.WithCode(errNotFound, errcode.NotFound)
+	b.Run("fmt.Errorf", func(b *testing.B) {
+		err := wrapErrorf(coded)
+		b.ReportAllocs()
+		for b.Loop() {
+			sink = errcode.CodeOf(err)
+		}
+	})
+	b.Run("errkit.Wrapf", func(b *testing.B) {
+		err := wrapErrkit(coded)
+		b.ReportAllocs()
+		for b.Loop() {
+			sink = errcode.CodeOf(err)
+		}
+	})
+	b.Run("uncoded", func(b *testing.B) {
+		err := wrapErrkit(errNotFound)
+		b.ReportAllocs()
+		for b.Loop() {
+			sink = errcode.CodeOf(err)
+		}
+	})
+}
+
+// pathError returns a *fs.PathError for name.
+func pathError(name string) error {
+	return &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// TestWrap checks the messages of the errors Wrap, Wrapf and WithStack
+// return, and that errors.Is sees through them.
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"Wrap", errkit.Wrap(errNotFound, "loading"), "loading: not found"},
+		{"Wrapf", errkit.Wrapf(errNotFound, "loading %s", "a.txt"), "loading a.txt: not found"},
+		{"WithStack", errkit.WithStack(errNotFound), "not found"},
+		{"nested", errkit.Wrap(errkit.Wrapf(errNotFound, "loading %d", 1), "starting"), "starting: loading 1: not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+			if !errors.Is(tt.err, errNotFound) {
+				t.Errorf("errors.Is(%v, errNotFound) = false, want true", tt.err)
+			}
+			for _, format := range []string{"%v", "%s"} {
+				if got := fmt.Sprintf(format, tt.err); got != tt.want {
+					t.Errorf("Sprintf(%q) = %q, want %q", format, got, tt.want)
+				}
+			}
+			if got, want := fmt.Sprintf("%q", tt.err), strconv.Quote(tt.want); got != want {
+				t.Errorf("Sprintf(%%q) = %s, want %s", got, want)
+			}
+			plus := fmt.Sprintf("%+v", tt.err)
+			if !strings.HasPrefix(plus, tt.want+"\n") || !strings.Contains(plus, "errkit_test.TestWrap\n") {
+				t.Errorf("Sprintf(%%+v) = %q, want the message and a stack trace with TestWrap", plus)
+			}
+		})
+	}
+}
+
+// TestWrapNil checks that wrapping nil returns nil.
+func TestWrapNil(t *testing.T) {
+	for name, err := range map[string]error{
+		"Wrap":           errkit.Wrap(nil, "loading"),
+		"Wrapf":          errkit.Wrapf(nil, "loading %d", 1),
+		"WithStack":      errkit.WithStack(nil),
+		"Retryable":      errkit.Retryable(nil),
+		"Permanent":      errkit.Permanent(nil),
+		"WithRetryAfter": errkit.WithRetryAfter(nil, time.Second),
+	} {
+		if err != nil {
+			t.Errorf("%s(nil) = %v, want nil", name, err)
+		}
+	}
+}
+
+// TestWithStackKeepsStack checks that WithStack returns an error that
+// already carries a stack unchanged.
+func TestWithStackKeepsStack(t *testing.T) {
+	err := errkit.Wrap(errNotFound, "loading")
+	for _, wrapped := range []error{err, fmt.Errorf("starting: %w", err)} {
+		if got := errkit.WithStack(wrapped); any(got) != any(wrapped) {
+			t.Errorf("WithStack(%v) = %v, want it unchanged", wrapped, got)
+		}
+	}
+	if got := errkit.WithStack(errNotFound); any(got) == any(errNotFound) {
+		t.Errorf("WithStack(errNotFound) did not record a stack")
+	}
+}
+
+// load returns an error with a stack created in load.
+func load() error {
+	return errkit.Wrap(errNotFound, "loading")
+}
+
+// countFunc returns how many frames of st are in the function fn of this
+// package.
+func countFunc(st errkit.Stack, fn string) int {
+	n := 0
+	for _, f := range st {
+		if strings.HasSuffix(f.Function, "/errkit_test."+fn) {
+			n++
+		}
+	}
+	return n
+}
+
+// TestStackTrace checks that StackOf returns the stack where the error was
+// created and that StackTrace adds the frames of the wraps without
+// repeating any.
+func TestStackTrace(t *testing.T) {
+	t.Run("same stack", func(t *testing.T) {
+		err := errkit.Wrapf(errkit.Wrap(load(), "starting"), "running %d", 1)
+		st := errkit.StackTrace(err)
+		if len(st) == 0 || !strings.HasSuffix(st[0].Function, "/errkit_test.load") {
+			t.Fatalf("StackTrace starts with %v, want load", st)
+		}
+		if n := countFunc(st, "TestStackTrace.func1"); n != 1 {
+			t.Errorf("StackTrace has %d frames of the test, want 1:%+v", n, st)
+		}
+		if got, want := fmt.Sprintf("%+v", st), fmt.Sprintf("%+v", errkit.StackOf(err)); got != want {
+			t.Errorf("StackTrace = %s, want StackOf = %s", got, want)
+		}
+	})
+
+	t.Run("other goroutine", func(t *testing.T) {
+		ch := make(chan error)
+		go func() { ch <- errkit.Wrap(errNotFound, "loading") }()
+		err := errkit.Wrap(<-ch, "waiting")
+
+		if n := countFunc(errkit.StackOf(err), "TestStackTrace.func2"); n != 0 {
+			t.Errorf("StackOf has %d frames of the test, want 0", n)
+		}
+		st := errkit.StackTrace(err)
+		if n := countFunc(st, "TestStackTrace.func2.1"); n != 1 {
+			t.Errorf("StackTrace has %d frames of the goroutine, want 1:%+v", n, st)
+		}
+		if n := countFunc(st, "TestStackTrace.func2"); n != 1 {
+			t.Errorf("StackTrace has %d frames of the test, want 1:%+v", n, st)
+		}
+	})
+
+	t.Run("no stack", func(t *testing.T) {
+		err := fmt.Errorf("loading: %w", errNotFound)
+		if st := errkit.StackOf(err); st != nil {
+			t.Errorf("StackOf = %v, want nil", st)
+		}
+		if st := errkit.StackTrace(err); st != nil {
+			t.Errorf("StackTrace = %v, want nil", st)
+		}
+	})
+}
+
+// TestSetFrameFilter checks that the frame filter leaves frames out of
+// %+v, but not out of StackTrace.
+func TestSetFrameFilter(t *testing.T) {
+	err := errkit.Wrap(errNotFound, "loading")
+	if !strings.Contains(fmt.Sprintf("%+v", err), "runtime.goexit") {
+		t.Fatalf("%%+v has no runtime frames to filter")
+	}
+	errkit.SetFrameFilter(errkit.UserFrame)
+	t.Cleanup(func() { errkit.SetFrameFilter(nil) })
+	if plus := fmt.Sprintf("%+v", err); strings.Contains(plus, "runtime.goexit") || !strings.Contains(plus, "TestSetFrameFilter") {
+		t.Errorf("%%+v with UserFrame = %q, want the test frames only", plus)
+	}
+	if countFunc(errkit.StackTrace(err), "TestSetFrameFilter") != 1 || len(errkit.StackTrace(err)) < 2 {
+		t.Errorf("StackTrace with a filter lost frames")
+	}
+}
+
+// asError is an error whose As method finds a *fs.PathError it does not
+// wrap.
+type asError struct{}
+
+func (asError) Error() string { return "as" }
+
+func (asError) As(target any) bool {
+	pe, ok := target.(**fs.PathError)
+	if ok {
+		*pe = &fs.PathError{Op: "open", Path: "from-as", Err: fs.ErrNotExist}
+	}
+	return ok
+}
+
+// messages returns the messages of the errors of seq.
+func messages(seq iter.Seq[error]) []string {
+	var msgs []string
+	for err := range seq {
+		msgs = append(msgs, err.Error())
+	}
+	return msgs
+}
+
+// TestChain checks the order Chain visits a tree of errors in, and that
+// Find, As and Root find the errors of the tree.
+func TestChain(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+	tree := fmt.Errorf("top: %w", errors.Join(fmt.Errorf("left: %w", errA), pathError("right"), errB))
+
+	want := []string{
+		tree.Error(),
+		"left: a\nopen right: file does not exist\nb",
+		"left: a", "a",
+		"open right: file does not exist", "file does not exist",
+		"b",
+	}
+	if got := messages(errkit.Chain(tree)); !slices.Equal(got, want) {
+		t.Errorf("Chain = %q, want %q", got, want)
+	}
+	if got := messages(errkit.Chain(nil)); got != nil {
+		t.Errorf("Chain(nil) = %q, want nothing", got)
+	}
+	var first []string
+	for err := range errkit.Chain(tree) {
+		first = append(first, err.Error())
+		break
+	}
+	if len(first) != 1 {
+		t.Errorf("Chain went on after break: %q", first)
+	}
+
+	if pe, ok := errkit.Find[*fs.PathError](tree); !ok || pe.Path != "right" {
+		t.Errorf("Find[*fs.PathError] = %v, %t, want the error for right", pe, ok)
+	}
+	if _, ok := errkit.Find[*fs.PathError](errA); ok {
+		t.Errorf("Find[*fs.PathError](errA) found an error")
+	}
+	if _, ok := errkit.Find[*fs.PathError](asError{}); ok {
+		t.Errorf("Find[*fs.PathError] called the As method")
+	}
+	if pe, ok := errkit.As[*fs.PathError](fmt.Errorf("x: %w", asError{})); !ok || pe.Path != "from-as" {
+		t.Errorf("As[*fs.PathError] = %v, %t, want the error of the As method", pe, ok)
+	}
+	if coder, ok := errkit.As[errcode.Coder](fmt.Errorf("x: %w", errcode.WithCode(errA, errcode.NotFound))); !ok || coder.Code() != errcode.NotFound {
+		t.Errorf("As[errcode.Coder] = %v, %t, want the NotFound coder", coder, ok)
+	}
+	if _, ok := errkit.As[errcode.Coder](tree); ok {
+		t.Errorf("As[errcode.Coder] found a coder in a tree without one")
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{"nil", nil, nil},
+		{"leaf", errA, []string{"a"}},
+		{"chain", errkit.Wrap(fmt.Errorf("left: %w", errA), "top"), []string{"a"}},
+		{"tree", tree, []string{"a", "file does not exist", "b"}},
+	}
+	for _, tt := range tests {
+		root := errkit.Root(tt.err)
+		var got []string
+		switch {
+		case root == nil:
+		case len(tt.want) == 1:
+			got = []string{root.Error()}
+		default:
+			got = messages(slices.Values(errkit.Wrapped(root)))
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Root(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// temporaryError is an error that reports whether it is temporary, as
+// net.Error does.
+type temporaryError bool
+
+func (temporaryError) Error() string     { return "temporary" }
+func (e temporaryError) Temporary() bool { return bool(e) }
+
+// TestRetry checks IsRetryable and RetryAfter for errors classified by
+// Retryable, Permanent and WithRetryAfter, or by a Temporary method.
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		retryable  bool
+		retryAfter time.Duration
+	}{
+		{"nil", nil, false, 0},
+		{"plain", errNotFound, false, 0},
+		{"retryable", errkit.Retryable(errNotFound), true, 0},
+		{"wrapped retryable", errkit.Wrap(errkit.Retryable(errNotFound), "loading"), true, 0},
+		{"permanent", errkit.Permanent(errNotFound), false, 0},
+		{"permanent over retryable", fmt.Errorf("x: %w", errkit.Permanent(errkit.Retryable(errNotFound))), false, 0},
+		{"retryable over permanent", errkit.Retryable(errkit.Permanent(errNotFound)), true, 0},
+		{"temporary", fmt.Errorf("x: %w", temporaryError(true)), true, 0},
+		{"not temporary", temporaryError(false), false, 0},
+		{"permanent over temporary", errkit.Permanent(temporaryError(true)), false, 0},
+		{"retry after", fmt.Errorf("x: %w", errkit.WithRetryAfter(errNotFound, time.Second)), true, time.Second},
+		{"permanent with retry after", errkit.Permanent(errkit.WithRetryAfter(errNotFound, time.Second)), false, time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errkit.IsRetryable(tt.err); got != tt.retryable {
+				t.Errorf("IsRetryable = %t, want %t", got, tt.retryable)
+			}
+			d, ok := errkit.RetryAfter(tt.err)
+			if d != tt.retryAfter || ok != (tt.retryAfter != 0) {
+				t.Errorf("RetryAfter = %v, %t, want %v", d, ok, tt.retryAfter)
+			}
+		})
+	}
+}
+
+// listError is an error of a type that is not comparable.
+type listError []string
+
+func (e listError) Error() string { return strings.Join(e, ", ") }
+
+// panics returns the value f panics with, or nil.
+func panics(f func()) (v any) {
+	defer func() { v = recover() }()
+	f()
+	return nil
+}
+
+// TestRegistry checks that sentinels are looked up by name and named by
+// the errors that match them, and that Register rejects invalid
+// sentinels.
+func TestRegistry(t *testing.T) {
+	reg := errkit.NewRegistry()
+	errMiss := reg.Register("store.miss", errors.New("cache miss"))
+	errFull := reg.Register("store.full", errkit.Guarded(errors.New("cache full")))
+
+	if got := reg.Names(); !slices.Equal(got, []string{"store.full", "store.miss"}) {
+		t.Errorf("Names = %q, want them sorted", got)
+	}
+	if err, ok := reg.Lookup("store.miss"); !ok || !errors.Is(err, errMiss) {
+		t.Errorf("Lookup(store.miss) = %v, %t, want errMiss", err, ok)
+	}
+	if err, ok := reg.Lookup("store.other"); ok {
+		t.Errorf("Lookup(store.other) = %v, want nothing", err)
+	}
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{errNotFound, ""},
+		{errMiss, "store.miss"},
+		{errkit.Wrap(errMiss, "loading"), "store.miss"},
+		{fmt.Errorf("loading: %w", errFull), "store.full"},
+		{errors.Join(errNotFound, errFull, errMiss), "store.full"},
+	}
+	for _, tt := range tests {
+		name, ok := reg.Name(tt.err)
+		if name != tt.want || ok != (tt.want != "") {
+			t.Errorf("Name(%v) = %q, %t, want %q", tt.err, name, ok, tt.want)
+		}
+	}
+
+	for name, register := range map[string]func(){
+		"empty name":   func() { reg.Register("", errors.New("x")) },
+		"nil error":    func() { reg.Register("store.nil", nil) },
+		"twice":        func() { reg.Register("store.miss", errors.New("x")) },
+		"incomparable": func() { reg.Register("store.list", listError{"a", "b"}) },
+	} {
+		if panics(register) == nil {
+			t.Errorf("Register with %s did not panic", name)
+		}
+	}
+}
+
+// TestGuarded checks that a sentinel Guarded returns formats and matches
+// like the sentinel itself, in guarded builds as in others.
+func TestGuarded(t *testing.T) {
+	if errkit.Guarded(nil) != nil {
+		t.Errorf("Guarded(nil) is not nil")
+	}
+	errGuarded := errkit.Guarded(errNotFound)
+	if errGuarded.Error() != errNotFound.Error() {
+		t.Errorf("Error() = %q, want %q", errGuarded.Error(), errNotFound.Error())
+	}
+	wrapped := errkit.Wrap(errGuarded, "loading")
+	for _, target := range []error{errGuarded, errNotFound} {
+		if !errors.Is(wrapped, target) {
+			t.Errorf("errors.Is(%v, %v) = false, want true", wrapped, target)
+		}
+	}
+	if errors.Is(wrapped, errkit.Guarded(errors.New("not found"))) {
+		t.Errorf("errors.Is matched another guarded sentinel with the same message")
+	}
+}
@@ -0,0 +1,64 @@
+package errkit
+
+// Guarded returns sentinel as it is, unless the program is built with the
+// race detector or with the errkitguard build tag, as in go test -race or
+// go test -tags=errkitguard. Then it returns an error that formats as
+// sentinel and that errors.Is matches like sentinel, wrapped or not, but
+// that panics with "comparing uncomparable type errkit.guardedError" when
+// it is compared with ==, used in a switch case or as a map key, as the
+// same value, sentinel itself.
+//
+// The comparison check of errlint finds err == ErrNotFound in the source,
+// but not comparisons it cannot see, such as those of a generic function,
+// of an interface method implemented elsewhere or through reflection.
+// Guarding the sentinels of a package turns those into test failures at
+// the line that compares:
+//
+//	var ErrNotFound = errkit.Guarded(errors.New("not found"))
+//
+// Comparing errors of another type with a guarded sentinel, such as a
+// wrapped error, returns false without a panic, as == on the unguarded
+// sentinel does, so the panic only fires where the comparison would have
+// matched.
+func Guarded(sentinel error) error {
+	if !guarding || sentinel == nil {
+		return sentinel
+	}
+	return guardedError{guard: &guard{err: sentinel}}
+}
+
+// guard holds the sentinel of a guarded error. Its address identifies the
+// guarded error, which cannot be compared itself.
+type guard struct {
+	err error
+}
+
+// guardedError is the error returned by Guarded in guarded builds.
+type guardedError struct {
+	*guard
+	// incomparable makes the type incomparable, so that comparing two
+	// guardedErrors with == panics.
+	incomparable [0]func()
+}
+
+func (e guardedError) Error() string {
+	return e.err.Error()
+}
+
+func (e guardedError) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether target is the same guarded error, which errors.Is
+// cannot compare with == itself.
+func (e guardedError) Is(target error) bool {
+	t, ok := target.(guardedError)
+	return ok && t.guard == e.guard
+}
+
+// isGuarded reports whether err is an error returned by Guarded.
+func isGuarded(err error) bool {
+	//errlint:ignore ERRLINT002 only the guarded error itself is asked about
+	_, ok := err.(guardedError)
+	return ok
+}
@@ -0,0 +1,6 @@
+//go:build !race && !errkitguard
+
+package errkit
+
+// guarding reports whether Guarded guards sentinels.
+const guarding = false
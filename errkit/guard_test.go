@@ -0,0 +1,25 @@
+package errkit
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestGuardedPanics checks that comparing a guarded sentinel with itself
+// panics in guarded builds, as go test -race or -tags=errkitguard make.
+func TestGuardedPanics(t *testing.T) {
+	if !guarding {
+		t.Skip("sentinels are only guarded with -race or -tags=errkitguard")
+	}
+	errGuarded := Guarded(errors.New("not found"))
+	if !isGuarded(errGuarded) {
+		t.Fatalf("Guarded returned %T", errGuarded)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("comparing a guarded sentinel with itself did not panic")
+		}
+	}()
+	other := errGuarded
+	_ = any(errGuarded) == any(other)
+}
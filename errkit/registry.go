@@ -0,0 +1,87 @@
+package errkit
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// Registry maps stable names to sentinel errors, so a sentinel can cross a
+// serialization boundary such as a log line or an RPC as its name and be
+// turned back into the value errors.Is compares with.
+//
+// Sentinels are usually registered when their package is initialized:
+//
+//	var (
+//		registry = errkit.NewRegistry()
+//
+//		ErrMiss = registry.Register("store.miss", errors.New("cache miss"))
+//	)
+//
+// A Registry is safe for concurrent use.
+type Registry struct {
+	mu     sync.RWMutex
+	byName map[string]error
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{byName: make(map[string]error)}
+}
+
+// Register records err under name and returns err. It panics if name is
+// empty or already registered, or if err is nil or not comparable, unless
+// it is an error returned by Guarded, since these are programming errors.
+func (r *Registry) Register(name string, err error) error {
+	if name == "" {
+		panic("errkit: Register with empty name")
+	}
+	if err == nil {
+		panic(fmt.Sprintf("errkit: Register of nil error %q", name))
+	}
+	if !reflect.TypeOf(err).Comparable() && !isGuarded(err) {
+		panic(fmt.Sprintf("errkit: Register of error %q with incomparable type %T", name, err))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.byName[name]; ok {
+		panic(fmt.Sprintf("errkit: Register called twice for %q", name))
+	}
+	r.byName[name] = err
+	return err
+}
+
+// Lookup returns the sentinel registered under name.
+func (r *Registry) Lookup(name string) (error, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	err, ok := r.byName[name]
+	return err, ok
+}
+
+// Name returns the name of the registered sentinel that err matches with
+// errors.Is. If err matches several, Name returns the first in the order
+// of Names.
+func (r *Registry) Name(err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+	for _, name := range r.Names() {
+		sentinel, _ := r.Lookup(name)
+		if errors.Is(err, sentinel) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// Names returns the names of the registered sentinels in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Sorted(maps.Keys(r.byName))
+}
@@ -0,0 +1,95 @@
+package errkit
+
+import "time"
+
+// Retryable returns an error that formats as err and that IsRetryable
+// reports as retryable, even after it is wrapped. It returns nil if err is
+// nil.
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &retryError{err: err, retryable: true}
+}
+
+// Permanent returns an error that formats as err and that IsRetryable
+// reports as not retryable, even after it is wrapped. It returns nil if
+// err is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &retryError{err: err}
+}
+
+// retryError is the error returned by Retryable and Permanent.
+type retryError struct {
+	err       error
+	retryable bool
+}
+
+func (e *retryError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryError) Unwrap() error {
+	return e.err
+}
+
+func (e *retryError) Retryable() bool {
+	return e.retryable
+}
+
+// IsRetryable reports whether the operation that returned err may succeed
+// if it is tried again. The first error in the chain of err with a
+// Retryable() bool method decides, which includes the errors returned by
+// Retryable and Permanent, so the outermost classification wins. If there
+// is none, the first error with a Temporary() bool method decides. Other
+// errors are not retryable.
+func IsRetryable(err error) bool {
+	if r, ok := As[interface{ Retryable() bool }](err); ok {
+		return r.Retryable()
+	}
+	if t, ok := As[interface{ Temporary() bool }](err); ok {
+		return t.Temporary()
+	}
+	return false
+}
+
+// WithRetryAfter returns an error that formats as err, that IsRetryable
+// reports as retryable, and whose RetryAfter hint is d: the time the
+// caller should wait before trying again, as a server that sheds load
+// tells its clients. It returns nil if err is nil.
+func WithRetryAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &retryAfterError{retryError: retryError{err: err, retryable: true}, after: d}
+}
+
+// retryAfterError is the error returned by WithRetryAfter.
+type retryAfterError struct {
+	retryError
+	after time.Duration
+}
+
+func (e *retryAfterError) RetryAfter() (time.Duration, bool) {
+	return e.after, true
+}
+
+// RetryAfter returns how long to wait before retrying the operation that
+// returned err, if an error in its chain gives a hint: the first error
+// with a RetryAfter() (time.Duration, bool) method that returns true
+// decides, which includes the errors returned by WithRetryAfter. Whether
+// to retry at all is up to IsRetryable.
+func RetryAfter(err error) (time.Duration, bool) {
+	for e := range Chain(err) {
+		//errlint:ignore ERRLINT002 Chain visits the wrapped errors one by one
+		if r, ok := e.(interface{ RetryAfter() (time.Duration, bool) }); ok {
+			if d, ok := r.RetryAfter(); ok {
+				return d, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,222 @@
+package errkit
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// maxDepth is the maximum number of frames recorded in a stack.
+const maxDepth = 32
+
+// Stack is the stack of function calls that created an error, from the
+// innermost call outwards.
+type Stack []runtime.Frame
+
+// Format prints each frame as its function name followed by its file and
+// line on an indented line with %+v, and the file and line of each frame
+// in brackets otherwise.
+func (st Stack) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		for _, f := range st {
+			fmt.Fprintf(s, "\n%s\n\t%s:%d", f.Function, f.File, f.Line)
+		}
+		return
+	}
+	io.WriteString(s, "[")
+	for i, f := range st {
+		if i > 0 {
+			io.WriteString(s, " ")
+		}
+		fmt.Fprintf(s, "%s:%d", f.File, f.Line)
+	}
+	io.WriteString(s, "]")
+}
+
+// Filter returns the frames of st for which keep returns true.
+func (st Stack) Filter(keep func(runtime.Frame) bool) Stack {
+	var out Stack
+	for _, f := range st {
+		if keep(f) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// UserFrame reports whether f is a frame of the program itself: neither a
+// function of package runtime, such as runtime.main and runtime.goexit,
+// nor one in a vendor directory.
+func UserFrame(f runtime.Frame) bool {
+	return !strings.HasPrefix(f.Function, "runtime.") && !strings.Contains(f.File, "/vendor/")
+}
+
+// frameFilter holds the function set with SetFrameFilter, or nil.
+var frameFilter atomic.Pointer[func(runtime.Frame) bool]
+
+// SetFrameFilter makes the errors returned by Wrap, Wrapf and WithStack
+// print only the frames for which keep returns true with %+v, such as
+// those UserFrame keeps:
+//
+//	errkit.SetFrameFilter(errkit.UserFrame)
+//
+// A nil keep prints every frame again, which is the default. StackOf and
+// StackTrace still return every frame.
+func SetFrameFilter(keep func(runtime.Frame) bool) {
+	if keep == nil {
+		frameFilter.Store(nil)
+		return
+	}
+	frameFilter.Store(&keep)
+}
+
+// StackOf returns the stack recorded where err was created: the stack of
+// the innermost error in the chain of err that recorded one, or nil if
+// none did.
+func StackOf(err error) Stack {
+	stacks := recorded(err)
+	if len(stacks) == 0 {
+		return nil
+	}
+	return frames(stacks[len(stacks)-1].stack)
+}
+
+// StackTrace returns the stack recorded where err was created, followed by
+// the frames the errors that wrap it recorded and that it does not hold,
+// such as those of another goroutine that wrapped the error it received.
+// Each frame appears once, however often the error was wrapped on its way
+// up the same stack. StackTrace returns nil if no error in the chain of
+// err recorded a stack.
+//
+//	for _, f := range errkit.StackTrace(err) {
+//		fmt.Printf("%s:%d\n", f.File, f.Line)
+//	}
+func StackTrace(err error) Stack {
+	stacks := recorded(err)
+	if len(stacks) == 0 {
+		return nil
+	}
+	if len(stacks) == 1 {
+		return frames(stacks[0].stack)
+	}
+	var pcs []uintptr
+	for i := len(stacks) - 1; i >= 0; i-- {
+		pcs = append(pcs, stacks[i].stack[:stacks[i].own]...)
+	}
+	return frames(pcs)
+}
+
+// recorded returns the errors in the chain of err that recorded a stack,
+// from the outermost inwards. Below an error that wraps several, it
+// follows the first one that has any.
+func recorded(err error) []*stackError {
+	var stacks []*stackError
+	for err != nil {
+		//errlint:ignore ERRLINT003 each error of the chain is switched on by itself
+		switch e := err.(type) {
+		case *stackError:
+			if e.stack != nil {
+				stacks = append(stacks, e)
+			}
+			err = e.err
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, w := range e.Unwrap() {
+				if inner := recorded(w); inner != nil {
+					return append(stacks, inner...)
+				}
+			}
+			return stacks
+		default:
+			return stacks
+		}
+	}
+	return stacks
+}
+
+// nearest returns the first error in the chain of err that recorded a
+// stack, as the first of recorded does, without building the list.
+func nearest(err error) *stackError {
+	for err != nil {
+		//errlint:ignore ERRLINT003 each error of the chain is switched on by itself
+		switch e := err.(type) {
+		case *stackError:
+			if e.stack != nil {
+				return e
+			}
+			err = e.err
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, w := range e.Unwrap() {
+				if s := nearest(w); s != nil {
+					return s
+				}
+			}
+			return nil
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// pcPool holds the buffers callers records stacks in, which it copies to
+// a slice of the right size only if they hold new frames.
+var pcPool = sync.Pool{
+	New: func() any { return new([maxDepth]uintptr) },
+}
+
+// callers returns the program counters of the caller of the errkit
+// function that called it, and how many of them, from the innermost
+// outwards, are not already in the stack recorded by the chain of err. It
+// returns nil if there are none: the caller is on the stack err was
+// created on, or wrapped on, and at most moved to another line of a
+// function there.
+func callers(err error) ([]uintptr, int) {
+	buf := pcPool.Get().(*[maxDepth]uintptr)
+	defer pcPool.Put(buf)
+	pcs := buf[:runtime.Callers(3, buf[:])]
+	i := len(pcs)
+	if s := nearest(err); s != nil {
+		// The frames the stacks share at the bottom are already recorded.
+		inner := s.stack
+		j := len(inner)
+		for i > 0 && j > 0 && pcs[i-1] == inner[j-1] {
+			i--
+			j--
+		}
+		if i > 0 && j > 0 && sameFunc(pcs[i-1], inner[j-1]) {
+			i--
+		}
+	}
+	if i == 0 {
+		return nil, 0
+	}
+	return slices.Clone(pcs), i
+}
+
+// sameFunc reports whether the return addresses a and b are in the same
+// function, which they are for two calls made by it.
+func sameFunc(a, b uintptr) bool {
+	fa, fb := runtime.FuncForPC(a-1), runtime.FuncForPC(b-1)
+	return fa != nil && fb != nil && fa.Entry() == fb.Entry()
+}
+
+// frames resolves program counters to frames.
+func frames(pcs []uintptr) Stack {
+	st := make(Stack, 0, len(pcs))
+	fs := runtime.CallersFrames(pcs)
+	for {
+		f, more := fs.Next()
+		st = append(st, f)
+		if !more {
+			return st
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Package errlintlib runs the errlint checks on Go packages from another
+// program, such as an editor integration, a code review bot or a custom
+// report, without running the errlint command and parsing its output:
+//
+//	cfg, err := config.Load(".errlint.yaml")
+//	if err != nil {
+//		return err
+//	}
+//	findings, err := errlintlib.Run(ctx, cfg, []string{"./..."})
+//	if err != nil {
+//		return err
+//	}
+//	for _, f := range findings {
+//		fmt.Printf("%s: %s: %s\n", f.Pos, f.RuleID, f.Message)
+//	}
+//
+// Run applies the settings of the configuration that the errlint command
+// applies: which checks run and how, the files they skip, and the severity
+// of their findings. Baselines and the fail-on level are left to the
+// caller; Config.Fails tells which findings fail a run.
+//
+// Run builds its own analyzer from the configuration, so concurrent runs
+// with different configurations do not affect each other or
+// analyzer.Analyzer.
+package errlintlib
+
+import (
+	"context"
+	"go/token"
+	"os"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer"
+	"github.com/kakkoyun/demo-error-lint/config"
+	"github.com/kakkoyun/demo-error-lint/internal/lint"
+)
+
+// Finding is a finding of an errlint check.
+type Finding struct {
+	// RuleID is the stable ID of the check, such as ERRLINT001, or the
+	// name of a custom check.
+	RuleID string
+	// Rule is the name of the check, such as comparison.
+	Rule string
+	// Severity is error, warning or info, as set by the configuration.
+	Severity string
+	// Message describes the finding. It ends with the ID of the check, as
+	// in [ERRLINT001], like the output of the errlint command.
+	Message string
+	// Pos and End are where the finding starts and ends. Their filenames
+	// are absolute.
+	Pos, End token.Position
+	// Package is the path of the package the finding was reported in, or
+	// of the package under test for test packages.
+	Package string
+	// Fixes are the suggested fixes of the finding, if any. They are
+	// alternatives: apply at most one.
+	Fixes []Fix
+}
+
+// Fix is a suggested fix of a finding.
+type Fix struct {
+	// Message describes the fix, such as "Use errors.Is".
+	Message string
+	// Edits are the edits of the fix, which do not overlap.
+	Edits []Edit
+}
+
+// Edit replaces the text between Pos and End with NewText. Pos and End are
+// equal for an insertion.
+type Edit struct {
+	Pos, End token.Position
+	NewText  string
+}
+
+// Run loads the packages matching patterns, such as ./..., with their test
+// files, and returns the findings of the checks cfg enables, sorted by
+// position. Findings reported for both a package and its test variant
+// appear once. Patterns are relative to cfg.Dir, or to the working
+// directory if it is empty. A nil cfg runs the default checks with the
+// default severities.
+//
+// Run returns an error if cfg is invalid, if a package fails to load or
+// type-check, or if ctx is done before the packages are analyzed.
+func Run(ctx context.Context, cfg *config.Config, patterns []string) ([]Finding, error) {
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		c := *cfg
+		c.Dir = wd
+		cfg = &c
+	}
+	a, err := analyzer.New(analyzer.Config{
+		Checks:      cfg.Checks,
+		Enable:      cfg.Enable,
+		Allow:       cfg.Allow,
+		Passthrough: cfg.Passthrough,
+		Loggers:     cfg.Loggers,
+		WrapPrefix:  cfg.WrapPrefix,
+		Custom:      cfg.Custom,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pkgs, err := lint.Load(&packages.Config{Context: ctx, Dir: cfg.Dir, Tests: true}, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	diags, err := lint.Analyze(a, pkgs)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]string)
+	for _, c := range analyzer.Checks() {
+		ids[c.Name] = c.ID
+	}
+	for _, c := range cfg.Custom {
+		ids[c.Name] = c.Check().ID
+	}
+	var findings []Finding
+	for _, d := range diags {
+		severity, ok := lint.Severity(cfg, d.Category, d.Fset.Position(d.Pos).Filename, d.Generated)
+		if !ok {
+			continue
+		}
+		findings = append(findings, newFinding(d.Fset, d.Diagnostic, ids[d.Category], severity, d.Package))
+	}
+	return findings, nil
+}
+
+// newFinding returns the finding of the diagnostic d, whose positions are
+// in fset.
+func newFinding(fset *token.FileSet, d analysis.Diagnostic, id, severity, pkg string) Finding {
+	f := Finding{
+		RuleID:   id,
+		Rule:     d.Category,
+		Severity: severity,
+		Message:  d.Message,
+		Package:  pkg,
+	}
+	f.Pos, f.End = positions(fset, d.Pos, d.End)
+	for _, fix := range d.SuggestedFixes {
+		lf := Fix{Message: fix.Message}
+		for _, te := range fix.TextEdits {
+			e := Edit{NewText: string(te.NewText)}
+			e.Pos, e.End = positions(fset, te.Pos, te.End)
+			lf.Edits = append(lf.Edits, e)
+		}
+		f.Fixes = append(f.Fixes, lf)
+	}
+	return f
+}
+
+// positions returns the positions of pos and end in fset. An invalid end
+// is the same position as pos.
+func positions(fset *token.FileSet, pos, end token.Pos) (token.Position, token.Position) {
+	start := fset.Position(pos)
+	if !end.IsValid() {
+		return start, start
+	}
+	return start, fset.Position(end)
+}
@@ -0,0 +1,233 @@
+package errlintlib_test
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kakkoyun/demo-error-lint/config"
+	"github.com/kakkoyun/demo-error-lint/errlintlib"
+)
+
+// fixtures are the fixture packages of the analyzer, in its GOPATH tree
+// testdata/src, that Run is checked against. They are analyzed with the
+// default checks.
+var fixtures = []string{
+	"comparison",
+	"errorf",
+	"oserror",
+	"switches",
+	"nilerror",
+	"wrongerr",
+}
+
+// gopath returns the absolute path of the GOPATH tree of the analyzer's
+// fixtures, and sets up the environment to load packages from it.
+func gopath(t *testing.T) string {
+	t.Helper()
+	testdata, err := filepath.Abs(filepath.Join("..", "analyzer", "testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	useGOPATH(t, testdata)
+	return testdata
+}
+
+// useGOPATH sets up the environment to load packages from the GOPATH tree
+// dir.
+func useGOPATH(t *testing.T, dir string) {
+	t.Helper()
+	t.Setenv("GOPATH", dir)
+	t.Setenv("GO111MODULE", "off")
+	t.Setenv("GOFLAGS", "")
+}
+
+// run runs errlintlib.Run on the package in dir with cfg, rooted at dir.
+func run(t *testing.T, cfg config.Config, dir string) []errlintlib.Finding {
+	t.Helper()
+	cfg.Dir = dir
+	findings, err := errlintlib.Run(context.Background(), &cfg, []string{"."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return findings
+}
+
+// wantRE matches the "// want" comments of a fixture. Facts, as in
+// name:"fact", are skipped; the patterns of the expected diagnostics are
+// in back quotes.
+var (
+	wantRE    = regexp.MustCompile("// want( .*)$")
+	patternRE = regexp.MustCompile("`([^`]*)`")
+)
+
+// wants returns the patterns of the diagnostics the "// want" comments of
+// the Go files in dir expect, by "file:line".
+func wants(t *testing.T, dir string) map[string][]*regexp.Regexp {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make(map[string][]*regexp.Regexp)
+	for _, name := range files {
+		f, err := os.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sc := bufio.NewScanner(f)
+		for line := 1; sc.Scan(); line++ {
+			m := wantRE.FindStringSubmatch(sc.Text())
+			if m == nil {
+				continue
+			}
+			key := fmt.Sprintf("%s:%d", name, line)
+			for _, p := range patternRE.FindAllStringSubmatch(m[1], -1) {
+				want[key] = append(want[key], regexp.MustCompile(p[1]))
+			}
+		}
+		f.Close()
+		if err := sc.Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return want
+}
+
+// TestRun checks the findings of Run against the "// want" comments of the
+// analyzer's fixtures, and that they carry the ID, severity and package
+// the errlint command reports.
+func TestRun(t *testing.T) {
+	testdata := gopath(t)
+	for _, pkg := range fixtures {
+		t.Run(pkg, func(t *testing.T) {
+			dir := filepath.Join(testdata, "src", pkg)
+			want := wants(t, dir)
+			findings := run(t, config.Config{}, dir)
+			if !slices.IsSortedFunc(findings, comparePos) {
+				t.Errorf("findings are not sorted by position")
+			}
+			for _, f := range findings {
+				if !strings.HasPrefix(f.RuleID, "ERRLINT") || !strings.HasSuffix(f.Message, " ["+f.RuleID+"]") {
+					t.Errorf("%s: finding %q has rule ID %q", f.Pos, f.Message, f.RuleID)
+				}
+				if f.Severity != config.DefaultSeverity {
+					t.Errorf("%s: severity is %q, want %q", f.Pos, f.Severity, config.DefaultSeverity)
+				}
+				if f.Package != pkg {
+					t.Errorf("%s: package is %q, want %q", f.Pos, f.Package, pkg)
+				}
+				key := fmt.Sprintf("%s:%d", f.Pos.Filename, f.Pos.Line)
+				i := slices.IndexFunc(want[key], func(re *regexp.Regexp) bool { return re.MatchString(f.Message) })
+				if i < 0 {
+					t.Errorf("%s: unexpected finding %q", f.Pos, f.Message)
+					continue
+				}
+				want[key] = slices.Delete(want[key], i, i+1)
+			}
+			for key, patterns := range want {
+				for _, re := range patterns {
+					t.Errorf("%s: no finding matching %q", key, re)
+				}
+			}
+		})
+	}
+}
+
+// TestRunConfig checks that Run applies the severities and exclusions of
+// the configuration.
+func TestRunConfig(t *testing.T) {
+	dir := filepath.Join(gopath(t), "src", "comparison")
+	all := run(t, config.Config{}, dir)
+	if len(all) == 0 {
+		t.Fatal("no findings in comparison")
+	}
+
+	tests := []struct {
+		name     string
+		cfg      config.Config
+		severity string
+	}{
+		{"severity", config.Config{Severity: map[string]string{"comparison": "error"}}, "error"},
+		{"off", config.Config{Severity: map[string]string{"comparison": "off"}}, ""},
+		{"exclude", config.Config{Exclude: []string{"comparison.go"}}, ""},
+		{"exclude other", config.Config{Exclude: []string{"other.go"}}, config.DefaultSeverity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := run(t, tt.cfg, dir)
+			if tt.severity == "" {
+				if len(findings) > 0 {
+					t.Fatalf("got %d findings, want none", len(findings))
+				}
+				return
+			}
+			if len(findings) != len(all) {
+				t.Fatalf("got %d findings, want %d", len(findings), len(all))
+			}
+			for _, f := range findings {
+				if f.Severity != tt.severity {
+					t.Errorf("%s: severity is %q, want %q", f.Pos, f.Severity, tt.severity)
+				}
+			}
+		})
+	}
+}
+
+// TestRunVariants checks that Run reports the findings of a package once
+// when it is analyzed with its test variant, and skips generated files
+// unless the configuration lints them.
+func TestRunVariants(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join(gopath(t), "src", "comparison", "comparison.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	useGOPATH(t, root)
+	write := func(pkg, name, data string) string {
+		dir := filepath.Join(root, "src", pkg)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	plain := write("comparison", "comparison.go", string(src))
+	want := len(run(t, config.Config{}, plain))
+	if want == 0 {
+		t.Fatal("no findings in comparison")
+	}
+
+	tested := write("tested/comparison", "comparison.go", string(src))
+	write("tested/comparison", "comparison_test.go", "package comparison\n\nimport \"testing\"\n\nfunc TestFetch(t *testing.T) { fetch() }\n")
+	if got := len(run(t, config.Config{}, tested)); got != want {
+		t.Errorf("with a test file: got %d findings, want %d", got, want)
+	}
+
+	generated := write("generated/comparison", "comparison.go", "// Code generated by hand. DO NOT EDIT.\n\n"+string(src))
+	if got := len(run(t, config.Config{}, generated)); got != 0 {
+		t.Errorf("generated: got %d findings, want none", got)
+	}
+	if got := len(run(t, config.Config{Generated: true}, generated)); got != want {
+		t.Errorf("generated with Generated set: got %d findings, want %d", got, want)
+	}
+}
+
+// comparePos orders findings by position, then message, as Run sorts them.
+func comparePos(a, b errlintlib.Finding) int {
+	return cmp.Or(
+		cmp.Compare(a.Pos.Filename, b.Pos.Filename),
+		cmp.Compare(a.Pos.Line, b.Pos.Line),
+		cmp.Compare(a.Pos.Column, b.Pos.Column),
+		cmp.Compare(a.Message, b.Message),
+	)
+}
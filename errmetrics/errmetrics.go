@@ -0,0 +1,75 @@
+// Package errmetrics counts errors with Prometheus by their classification,
+// so operators can watch which kinds of error spike rather than a single
+// error count.
+//
+// Metrics is a prometheus.Collector with a counter of errors labeled by
+// the operation that failed, the errcode code of the error and the name of
+// the sentinel it matches in an errkit.Registry:
+//
+//	metrics := errmetrics.New(sentinels)
+//	prometheus.MustRegister(metrics)
+//	...
+//	if err := checkout(ctx, order); err != nil {
+//		metrics.Observe("checkout", err)
+//	}
+//
+// The labels only take the values of the codes and registered names, so
+// the number of series stays bounded whatever the messages of the errors.
+package errmetrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+)
+
+// The labels of the counter.
+const (
+	OpLabel       = "op"
+	CodeLabel     = "code"
+	SentinelLabel = "sentinel"
+)
+
+// Metrics counts the errors it observes as errors_total. A Metrics is safe
+// for concurrent use.
+type Metrics struct {
+	reg    *errkit.Registry
+	errors *prometheus.CounterVec
+}
+
+// New returns a Metrics that labels errors with the names of the sentinels
+// registered in reg. reg may be nil, which leaves the sentinel label empty.
+func New(reg *errkit.Registry) *Metrics {
+	return &Metrics{
+		reg: reg,
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "errors_total",
+			Help: "Errors returned by operations, by operation, errcode code and registered sentinel.",
+		}, []string{OpLabel, CodeLabel, SentinelLabel}),
+	}
+}
+
+// Observe counts err as an error of the operation op, labeled with its
+// errcode code and the name of the registered sentinel it matches, or the
+// empty string if it matches none. It does nothing if err is nil.
+func (m *Metrics) Observe(op string, err error) {
+	if err == nil {
+		return
+	}
+	var name string
+	if m.reg != nil {
+		name, _ = m.reg.Name(err)
+	}
+	m.errors.WithLabelValues(op, errcode.CodeOf(err).String(), name).Inc()
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.errors.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.errors.Collect(ch)
+}
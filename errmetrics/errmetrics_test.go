@@ -0,0 +1,80 @@
+package errmetrics_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+	"github.com/kakkoyun/demo-error-lint/errmetrics"
+)
+
+// counts returns the values of errors_total in reg by their op, code and
+// sentinel labels.
+func counts(t *testing.T, reg *prometheus.Registry) map[string]float64 {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]float64)
+	for _, f := range families {
+		if f.GetName() != "errors_total" {
+			t.Errorf("unexpected metric %s", f.GetName())
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			key := fmt.Sprintf("%s/%s/%s", labels[errmetrics.OpLabel], labels[errmetrics.CodeLabel], labels[errmetrics.SentinelLabel])
+			got[key] = m.GetCounter().GetValue()
+		}
+	}
+	return got
+}
+
+// TestObserve checks the labels of the errors Observe counts.
+func TestObserve(t *testing.T) {
+	sentinels := errkit.NewRegistry()
+	errOutOfStock := sentinels.Register("inventory.out_of_stock", errors.New("out of stock"))
+
+	tests := []struct {
+		name string
+		reg  *errkit.Registry
+		want map[string]float64
+	}{
+		{"registry", sentinels, map[string]float64{
+			"checkout/NotFound/inventory.out_of_stock": 2,
+			"checkout/Unknown/":                        1,
+			"refund/Timeout/":                          1,
+		}},
+		{"no registry", nil, map[string]float64{
+			"checkout/NotFound/": 2,
+			"checkout/Unknown/":  1,
+			"refund/Timeout/":    1,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := errmetrics.New(tt.reg)
+			reg := prometheus.NewPedanticRegistry()
+			reg.MustRegister(m)
+
+			m.Observe("checkout", nil)
+			m.Observe("checkout", errcode.WithCode(fmt.Errorf("sku-1: %w", errOutOfStock), errcode.NotFound))
+			m.Observe("checkout", errcode.WithCode(fmt.Errorf("sku-2: %w", errOutOfStock), errcode.NotFound))
+			m.Observe("checkout", errors.New("payment declined for card 4242"))
+			m.Observe("refund", errcode.WithCode(errors.New("bank timed out"), errcode.Timeout))
+
+			got := counts(t, reg)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("errors_total = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
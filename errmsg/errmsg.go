@@ -0,0 +1,178 @@
+// Package errmsg separates the messages errors show to users from the
+// technical chain they wrap.
+//
+// Attach a user message where an error crosses into the code that talks to
+// users, as a key into a catalog of translations and its arguments:
+//
+//	return errmsg.WithUserMessage(err, "order.not_found", id)
+//
+// The error still formats as err, so logs keep the whole chain, while a
+// Renderer turns the message into the language of the user:
+//
+//	r := errmsg.NewRenderer("en")
+//	r.Register("en", errmsg.Catalog{"order.not_found": "Order %s does not exist."})
+//	r.Register("de", errmsg.Catalog{"order.not_found": "Bestellung %s existiert nicht."})
+//	msg := r.Render(err, errmsg.ParseAcceptLanguage(req.Header.Get("Accept-Language"))...)
+//
+// Errors without a user message render as the message with key Unknown, so
+// internal details never reach users by accident.
+package errmsg
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Unknown is the key of the message rendered for errors that carry no user
+// message.
+const Unknown = "error.unknown"
+
+// Message is a user message: a key into the catalogs of a Renderer and the
+// arguments of its translations.
+type Message struct {
+	Key  string
+	Args []any
+}
+
+// WithUserMessage returns an error that formats as err and carries the
+// user message with key and args. It returns nil if err is nil.
+func WithUserMessage(err error, key string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return &messageError{err: err, msg: Message{Key: key, Args: args}}
+}
+
+// messageError is the error returned by WithUserMessage.
+type messageError struct {
+	err error
+	msg Message
+}
+
+func (e *messageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *messageError) Unwrap() error {
+	return e.err
+}
+
+// MessageOf returns the user message of the first error in the chain of
+// err that carries one, which is the outermost, and reports whether there
+// is one.
+func MessageOf(err error) (Message, bool) {
+	var me *messageError
+	if errors.As(err, &me) {
+		return me.msg, true
+	}
+	return Message{}, false
+}
+
+// Locale translates the keys of user messages into one language.
+type Locale interface {
+	// Translate returns the translation of key as a format string for
+	// fmt.Sprintf, which formats the arguments of the message, and reports
+	// whether the locale has one.
+	Translate(key string) (string, bool)
+}
+
+// Catalog is a Locale that looks translations up in a map from keys to
+// format strings.
+type Catalog map[string]string
+
+func (c Catalog) Translate(key string) (string, bool) {
+	format, ok := c[key]
+	return format, ok
+}
+
+// Renderer renders the user messages of errors in the locales registered
+// with it. A Renderer must not be modified while it renders messages.
+type Renderer struct {
+	fallback string
+	locales  map[string]Locale
+}
+
+// NewRenderer returns a renderer without locales, which falls back to the
+// locale registered under the language tag fallback for messages the
+// languages of the user do not translate.
+func NewRenderer(fallback string) *Renderer {
+	return &Renderer{fallback: normalize(fallback), locales: make(map[string]Locale)}
+}
+
+// Register registers l as the locale of the language tag, such as "en" or
+// "pt-BR", replacing the locale registered before for it.
+func (r *Renderer) Register(tag string, l Locale) {
+	r.locales[normalize(tag)] = l
+}
+
+// Render returns the user message of err, or the message with key Unknown
+// if err carries none, translated into the first of the language tags,
+// in order of preference, that has a translation. A tag such as "de-CH"
+// also matches the locale of its base language, "de". Messages none of
+// the tags translate are taken from the fallback locale, and the key
+// itself is returned if that has no translation either.
+func (r *Renderer) Render(err error, tags ...string) string {
+	msg, ok := MessageOf(err)
+	if !ok {
+		msg = Message{Key: Unknown}
+	}
+	for _, tag := range append(slices.Clip(tags), r.fallback) {
+		tag = normalize(tag)
+		for {
+			if l, ok := r.locales[tag]; ok {
+				if format, ok := l.Translate(msg.Key); ok {
+					return fmt.Sprintf(format, msg.Args...)
+				}
+			}
+			i := strings.LastIndexByte(tag, '-')
+			if i < 0 {
+				break
+			}
+			tag = tag[:i]
+		}
+	}
+	return msg.Key
+}
+
+// ParseAcceptLanguage returns the language tags of the value of an
+// Accept-Language header, in order of preference. Tags with a quality of
+// zero, and the wildcard, are left out.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+	var list []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			v, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = v
+		}
+		if tag == "" || tag == "*" || quality <= 0 {
+			continue
+		}
+		list = append(list, weighted{tag, quality})
+	}
+	slices.SortStableFunc(list, func(a, b weighted) int { return cmp.Compare(b.quality, a.quality) })
+	tags := make([]string, len(list))
+	for i, w := range list {
+		tags[i] = w.tag
+	}
+	return tags
+}
+
+// normalize returns tag in lower case with hyphens, so "pt_BR" and "pt-br"
+// name the same locale.
+func normalize(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+}
@@ -0,0 +1,93 @@
+package errmsg_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/kakkoyun/demo-error-lint/errmsg"
+)
+
+var errNotFound = errors.New("order not found")
+
+// TestParseAcceptLanguage checks the tags of Accept-Language headers and
+// their order of preference.
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"", []string{}},
+		{"de", []string{"de"}},
+		{"de-CH, fr;q=0.9, en;q=0.8", []string{"de-CH", "fr", "en"}},
+		{"en;q=0.5, de;q=0.9, fr", []string{"fr", "de", "en"}},
+		{"en, de", []string{"en", "de"}},
+		{"*, de;q=0, fr;q=0.1", []string{"fr"}},
+		{" pt-BR ; q=0.7 ,, es ", []string{"es", "pt-BR"}},
+		{"en;q=high, de", []string{"de"}},
+	}
+	for _, tt := range tests {
+		if got := errmsg.ParseAcceptLanguage(tt.header); !slices.Equal(got, tt.want) {
+			t.Errorf("ParseAcceptLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+// TestRender checks which locale renders a message, down to the fallback
+// locale and the key itself.
+func TestRender(t *testing.T) {
+	r := errmsg.NewRenderer("en")
+	r.Register("en", errmsg.Catalog{
+		"order.not_found": "Order %s does not exist.",
+		"order.paid":      "Order %s is already paid.",
+		errmsg.Unknown:    "Something went wrong.",
+	})
+	r.Register("de", errmsg.Catalog{"order.not_found": "Bestellung %s existiert nicht."})
+	r.Register("pt_BR", errmsg.Catalog{"order.not_found": "O pedido %s não existe."})
+
+	notFound := fmt.Errorf("loading: %w", errmsg.WithUserMessage(errNotFound, "order.not_found", "42"))
+	tests := []struct {
+		name string
+		err  error
+		tags []string
+		want string
+	}{
+		{"first tag", notFound, []string{"de", "en"}, "Bestellung 42 existiert nicht."},
+		{"base language", notFound, []string{"de-CH"}, "Bestellung 42 existiert nicht."},
+		{"normalized", notFound, []string{"PT-br"}, "O pedido 42 não existe."},
+		{"later tag", notFound, []string{"fr", "de"}, "Bestellung 42 existiert nicht."},
+		{"fallback", notFound, []string{"fr"}, "Order 42 does not exist."},
+		{"no tags", notFound, nil, "Order 42 does not exist."},
+		{"untranslated in tag", errmsg.WithUserMessage(errNotFound, "order.paid", "7"), []string{"de"}, "Order 7 is already paid."},
+		{"untranslated", errmsg.WithUserMessage(errNotFound, "order.lost"), []string{"de"}, "order.lost"},
+		{"no user message", errNotFound, []string{"de"}, "Something went wrong."},
+		{"outermost", errmsg.WithUserMessage(notFound, "order.paid", "9"), []string{"en"}, "Order 9 is already paid."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Render(tt.err, tt.tags...); got != tt.want {
+				t.Errorf("Render(%q) = %q, want %q", tt.tags, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWithUserMessage checks that the error WithUserMessage returns still
+// formats and matches as the error it wraps.
+func TestWithUserMessage(t *testing.T) {
+	if err := errmsg.WithUserMessage(nil, "order.not_found"); err != nil {
+		t.Errorf("WithUserMessage(nil) = %v, want nil", err)
+	}
+	err := errmsg.WithUserMessage(errNotFound, "order.not_found", "42")
+	if err.Error() != errNotFound.Error() || !errors.Is(err, errNotFound) {
+		t.Errorf("err = %v, want an error that formats and matches as errNotFound", err)
+	}
+	msg, ok := errmsg.MessageOf(fmt.Errorf("x: %w", err))
+	if !ok || msg.Key != "order.not_found" || !slices.Equal(msg.Args, []any{"42"}) {
+		t.Errorf("MessageOf = %v, %t, want order.not_found with 42", msg, ok)
+	}
+	if _, ok := errmsg.MessageOf(errNotFound); ok {
+		t.Errorf("MessageOf(errNotFound) found a message")
+	}
+}
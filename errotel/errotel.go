@@ -0,0 +1,98 @@
+// Package errotel records errors on OpenTelemetry spans as the whole chain
+// of errors they wrap, instead of the flat message span.RecordError keeps.
+//
+// RecordError sets the status of the span from the errcode code of the
+// error, adds an exception event for each error in its chain and records
+// whether the operation may be retried:
+//
+//	ctx, span := tracer.Start(ctx, "checkout")
+//	defer span.End()
+//	if err := checkout(ctx, order); err != nil {
+//		errotel.RecordError(span, err)
+//		return err
+//	}
+package errotel
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+	"github.com/kakkoyun/demo-error-lint/errtree"
+)
+
+// The keys of the attributes RecordError sets on the span and its events.
+// The exception keys are those of the OpenTelemetry semantic conventions.
+const (
+	CodeKey       = attribute.Key("error.code")
+	RetryableKey  = attribute.Key("error.retryable")
+	ChainIndexKey = attribute.Key("error.chain.index")
+
+	ExceptionTypeKey       = attribute.Key("exception.type")
+	ExceptionMessageKey    = attribute.Key("exception.message")
+	ExceptionStacktraceKey = attribute.Key("exception.stacktrace")
+)
+
+// RecordError records err on span. It does nothing if err is nil.
+//
+// The status of the span is set to codes.Error with the message of err,
+// unless the errcode code of err says the caller is at fault:
+// InvalidInput, NotFound, AlreadyExists and PermissionDenied leave the
+// status unset, as the semantic conventions do for client errors on server
+// spans, so they do not count as failures of the operation.
+//
+// The span gets the attributes error.code, the code of err, and
+// error.retryable, which reports errkit.IsRetryable(err). An exception
+// event is added for each error in the chain, starting with err itself and
+// followed by the errors it wraps in depth-first order, with the type,
+// message and code the error itself carries and its index in the chain.
+// The event of err also holds the stack trace recorded by errkit, if any.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	code := errcode.CodeOf(err)
+	if !callerError(code) {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.SetAttributes(
+		CodeKey.String(code.String()),
+		RetryableKey.Bool(errkit.IsRetryable(err)),
+	)
+
+	index := 0
+	var walk func(n *errtree.Node)
+	walk = func(n *errtree.Node) {
+		attrs := []attribute.KeyValue{
+			ExceptionTypeKey.String(fmt.Sprintf("%T", n.Err)),
+			ExceptionMessageKey.String(n.Err.Error()),
+			ChainIndexKey.Int(index),
+		}
+		if n.Code != errcode.Unknown {
+			attrs = append(attrs, CodeKey.String(n.Code.String()))
+		}
+		if st := errkit.StackTrace(n.Err); index == 0 && st != nil {
+			attrs = append(attrs, ExceptionStacktraceKey.String(fmt.Sprintf("%+v", st)))
+		}
+		span.AddEvent("exception", trace.WithAttributes(attrs...))
+		index++
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+	walk(errtree.Chain(err))
+}
+
+// callerError reports whether code says the caller of the operation is at
+// fault rather than the operation itself.
+func callerError(code errcode.Code) bool {
+	switch code {
+	case errcode.InvalidInput, errcode.NotFound, errcode.AlreadyExists, errcode.PermissionDenied:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package errotel_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+	"github.com/kakkoyun/demo-error-lint/errotel"
+)
+
+var (
+	errInventoryDown = errcode.WithCode(errors.New("inventory unavailable"), errcode.Unavailable)
+	errNoSuchItem    = errcode.WithCode(errors.New("no such item"), errcode.NotFound)
+)
+
+// record records err on a new span with errotel.RecordError and returns
+// the span as the in-memory exporter received it.
+func record(t *testing.T, err error) tracetest.SpanStub {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+
+	_, span := provider.Tracer("test").Start(context.Background(), "checkout")
+	errotel.RecordError(span, err)
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("exported %d spans, want 1", len(spans))
+	}
+	return spans[0]
+}
+
+// attrs returns attrs as a map from keys to values as Emit formats them.
+func attrs(kvs []attribute.KeyValue) map[attribute.Key]string {
+	m := make(map[attribute.Key]string)
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value.Emit()
+	}
+	return m
+}
+
+// TestRecordError checks the status and attributes RecordError sets on a
+// span.
+func TestRecordError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		status    codes.Code
+		code      string
+		retryable string
+	}{
+		{"unavailable", fmt.Errorf("checking out: %w", errkit.Retryable(errInventoryDown)), codes.Error, "Unavailable", "true"},
+		{"uncoded", errors.New("boom"), codes.Error, "Unknown", "false"},
+		{"not found", fmt.Errorf("checking out: %w", errNoSuchItem), codes.Unset, "NotFound", "false"},
+		{"invalid input", errcode.WithCode(errors.New("bad sku"), errcode.InvalidInput), codes.Unset, "InvalidInput", "false"},
+		{"already exists", errcode.WithCode(errors.New("dup"), errcode.AlreadyExists), codes.Unset, "AlreadyExists", "false"},
+		{"permission denied", errcode.WithCode(errors.New("no"), errcode.PermissionDenied), codes.Unset, "PermissionDenied", "false"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			span := record(t, tt.err)
+			if span.Status.Code != tt.status {
+				t.Errorf("status = %v, want %v", span.Status.Code, tt.status)
+			}
+			if tt.status == codes.Error && span.Status.Description != tt.err.Error() {
+				t.Errorf("status description = %q, want the message of the error", span.Status.Description)
+			}
+			got := attrs(span.Attributes)
+			if got[errotel.CodeKey] != tt.code || got[errotel.RetryableKey] != tt.retryable {
+				t.Errorf("attributes = %v, want %s=%s and %s=%s", got, errotel.CodeKey, tt.code, errotel.RetryableKey, tt.retryable)
+			}
+		})
+	}
+}
+
+// TestRecordErrorEvents checks the exception events RecordError adds for
+// the errors of a chain.
+func TestRecordErrorEvents(t *testing.T) {
+	err := errkit.Wrap(fmt.Errorf("reserving book: %w", errkit.Retryable(errInventoryDown)), "checking out")
+	span := record(t, err)
+
+	want := []map[attribute.Key]string{
+		{errotel.ExceptionTypeKey: "*errkit.stackError", errotel.ExceptionMessageKey: "checking out: reserving book: inventory unavailable"},
+		{errotel.ExceptionTypeKey: "*fmt.wrapError", errotel.ExceptionMessageKey: "reserving book: inventory unavailable"},
+		{errotel.ExceptionTypeKey: "*errkit.retryError", errotel.ExceptionMessageKey: "inventory unavailable"},
+		{errotel.ExceptionTypeKey: "*errcode.codeError", errotel.ExceptionMessageKey: "inventory unavailable", errotel.CodeKey: "Unavailable"},
+		{errotel.ExceptionTypeKey: "*errors.errorString", errotel.ExceptionMessageKey: "inventory unavailable"},
+	}
+	if len(span.Events) != len(want) {
+		t.Fatalf("span has %d events, want %d", len(span.Events), len(want))
+	}
+	for i, event := range span.Events {
+		if event.Name != "exception" {
+			t.Errorf("event %d is named %q, want exception", i, event.Name)
+		}
+		got := attrs(event.Attributes)
+		stack, hasStack := got[errotel.ExceptionStacktraceKey]
+		delete(got, errotel.ExceptionStacktraceKey)
+		want[i][errotel.ChainIndexKey] = fmt.Sprint(i)
+		if fmt.Sprint(got) != fmt.Sprint(want[i]) {
+			t.Errorf("event %d = %v, want %v", i, got, want[i])
+		}
+		if hasStack != (i == 0) {
+			t.Errorf("event %d has a stack trace: %t, want %t", i, hasStack, i == 0)
+		}
+		if i == 0 && !strings.Contains(stack, "errotel_test.TestRecordErrorEvents") {
+			t.Errorf("stack trace = %q, want the frame of the test", stack)
+		}
+	}
+}
+
+// TestRecordErrorNil checks that RecordError leaves the span of a nil error
+// alone.
+func TestRecordErrorNil(t *testing.T) {
+	span := record(t, nil)
+	if span.Status.Code != codes.Unset || len(span.Attributes) > 0 || len(span.Events) > 0 {
+		t.Errorf("span of a nil error = %+v, want it untouched", span)
+	}
+}
@@ -0,0 +1,91 @@
+// Package errslog logs errors with log/slog as the whole chain of errors
+// they wrap, instead of the flat message a handler prints for an error
+// value.
+//
+// Attr returns an attribute whose value is a group with the message of the
+// error, its errcode code, its errfields fields and the type, message and
+// code of each error in its chain:
+//
+//	logger.Error("checkout failed", errslog.Attr(err))
+//
+// Chain is the slog.LogValuer behind Attr, which also adds the stack trace
+// recorded by errkit if Stack is set:
+//
+//	logger.Error("checkout failed", "error", errslog.Chain{Err: err, Stack: true})
+package errslog
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errfields"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+	"github.com/kakkoyun/demo-error-lint/errtree"
+)
+
+// Chain logs Err as a group of attributes:
+//
+//   - msg, the message of Err;
+//   - code, the errcode code of Err, unless it is errcode.Unknown;
+//   - fields, a group with the errfields fields of the chain, if any;
+//   - chain, a group with an entry for each error in the chain, starting
+//     with Err itself and followed by the errors it wraps in depth-first
+//     order, keyed 0, 1 and so on, with the type, msg and code the error
+//     itself carries;
+//   - stack, the frames of the errkit stack trace of Err, if Stack is set
+//     and the chain records one.
+type Chain struct {
+	Err error
+	// Stack adds the stack trace recorded by errkit.
+	Stack bool
+}
+
+// LogValue returns the group that Chain logs, or a nil value if Err is
+// nil.
+func (c Chain) LogValue() slog.Value {
+	if c.Err == nil {
+		return slog.AnyValue(nil)
+	}
+	attrs := []slog.Attr{slog.String("msg", c.Err.Error())}
+	if code := errcode.CodeOf(c.Err); code != errcode.Unknown {
+		attrs = append(attrs, slog.String("code", code.String()))
+	}
+	if fields := errfields.Fields(c.Err); len(fields) > 0 {
+		attrs = append(attrs, slog.Attr{Key: "fields", Value: slog.GroupValue(fields...)})
+	}
+
+	var chain []slog.Attr
+	var walk func(n *errtree.Node)
+	walk = func(n *errtree.Node) {
+		node := []slog.Attr{
+			slog.String("type", fmt.Sprintf("%T", n.Err)),
+			slog.String("msg", n.Err.Error()),
+		}
+		if n.Code != errcode.Unknown {
+			node = append(node, slog.String("code", n.Code.String()))
+		}
+		chain = append(chain, slog.Attr{Key: strconv.Itoa(len(chain)), Value: slog.GroupValue(node...)})
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+	walk(errtree.Chain(c.Err))
+	attrs = append(attrs, slog.Attr{Key: "chain", Value: slog.GroupValue(chain...)})
+
+	if st := errkit.StackTrace(c.Err); c.Stack && st != nil {
+		frames := make([]string, len(st))
+		for i, f := range st {
+			frames[i] = fmt.Sprintf("%s %s:%d", f.Function, f.File, f.Line)
+		}
+		attrs = append(attrs, slog.Any("stack", frames))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Attr returns an attribute with key "error" that logs err as Chain does,
+// without the stack trace.
+func Attr(err error) slog.Attr {
+	return slog.Any("error", Chain{Err: err})
+}
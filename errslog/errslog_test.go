@@ -0,0 +1,73 @@
+package errslog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errfields"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+	"github.com/kakkoyun/demo-error-lint/errslog"
+)
+
+// logJSON logs msg with args with a JSON handler and returns the record
+// without its time and level.
+func logJSON(t *testing.T, args ...any) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", args...)
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+	delete(record, slog.TimeKey)
+	delete(record, slog.LevelKey)
+	return record
+}
+
+// TestAttr checks the group Attr logs for an error with a code, fields
+// and a chain.
+func TestAttr(t *testing.T) {
+	err := fmt.Errorf("checkout: %w", errfields.With(errcode.WithCode(errors.New("out of stock"), errcode.NotFound), "sku", "sku-42"))
+	got, _ := json.Marshal(logJSON(t, errslog.Attr(err)))
+	want := `{"error":{"chain":{` +
+		`"0":{"msg":"checkout: out of stock","type":"*fmt.wrapError"},` +
+		`"1":{"msg":"out of stock","type":"*errfields.fieldsError"},` +
+		`"2":{"code":"NotFound","msg":"out of stock","type":"*errcode.codeError"},` +
+		`"3":{"msg":"out of stock","type":"*errors.errorString"}},` +
+		`"code":"NotFound","fields":{"sku":"sku-42"},"msg":"checkout: out of stock"},"msg":"failed"}`
+	if string(got) != want {
+		t.Errorf("logged\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestChain checks that Chain logs nil as null, leaves out the code of
+// uncoded errors, and adds the errkit stack trace only when asked to.
+func TestChain(t *testing.T) {
+	if got := logJSON(t, errslog.Attr(nil)); got["error"] != nil {
+		t.Errorf("Attr(nil) logged %v, want null", got["error"])
+	}
+
+	err := errkit.Wrap(errors.New("disk full"), "saving")
+	plain := logJSON(t, "error", errslog.Chain{Err: err})["error"].(map[string]any)
+	if _, ok := plain["code"]; ok {
+		t.Errorf("an uncoded error was logged with a code: %v", plain)
+	}
+	if _, ok := plain["stack"]; ok {
+		t.Errorf("Chain without Stack logged a stack: %v", plain)
+	}
+
+	stack, ok := logJSON(t, "error", errslog.Chain{Err: err, Stack: true})["error"].(map[string]any)["stack"].([]any)
+	if !ok || len(stack) == 0 || !strings.Contains(stack[0].(string), "errslog_test.TestChain ") {
+		t.Errorf("Chain with Stack logged the stack %v, want it to start in TestChain", stack)
+	}
+	nostack := logJSON(t, "error", errslog.Chain{Err: errors.New("x"), Stack: true})["error"].(map[string]any)
+	if _, ok := nostack["stack"]; ok {
+		t.Errorf("Chain logged a stack for an error without one: %v", nostack)
+	}
+}
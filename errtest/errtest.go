@@ -0,0 +1,113 @@
+// Package errtest provides test assertions for error chains, which report
+// the whole chain when they fail rather than only its message.
+//
+// AssertChain checks that errors.Is or errors.As finds each target in the
+// chain of an error, and AssertCode checks its errcode code:
+//
+//	err := store.Get(ctx, "42")
+//	errtest.AssertChain(t, err, ErrInvalidInput, &NotFoundError{})
+//	errtest.AssertCode(t, err, errcode.NotFound)
+//
+// A failed assertion prints ChainDiff, which lists the targets found and
+// missing next to the tree of errors the error wraps:
+//
+//	errors wanted in the chain:
+//		found    *store.NotFoundError
+//		missing  *errors.errorString "invalid input"
+//	chain of the error:
+//		*fmt.wrapError "getting item 42: item 42 not found"
+//		└── *store.NotFoundError "item 42 not found"
+package errtest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errtree"
+)
+
+// AssertChain reports an error through t, with the ChainDiff of err and
+// targets, unless each of the targets is in the chain of err, and returns
+// whether they all are. The targets are matched in any order:
+//
+//   - An error, such as a sentinel, matches if errors.Is finds it. A
+//     pointer to the zero value of its type, such as &NotFoundError{},
+//     also matches any error of that type, as errors.As would find it.
+//   - A pointer to an interface, or to a type that implements error, such
+//     as new(net.Error) or new(*NotFoundError), matches if errors.As finds
+//     an error it can be set to. The pointer itself is left unchanged.
+func AssertChain(t testing.TB, err error, targets ...any) bool {
+	t.Helper()
+	if diff := ChainDiff(err, targets...); diff != "" {
+		t.Errorf("error chain does not hold the wanted errors:\n%s", diff)
+		return false
+	}
+	return true
+}
+
+// AssertCode reports an error through t unless errcode.CodeOf(err) is code,
+// and returns whether it is.
+func AssertCode(t testing.TB, err error, code errcode.Code) bool {
+	t.Helper()
+	if got := errcode.CodeOf(err); got != code {
+		t.Errorf("errcode.CodeOf(err) = %s, want %s\nchain of the error:\n%s", got, code, indent(errtree.Chain(err).String()))
+		return false
+	}
+	return true
+}
+
+// ChainDiff returns a description of the targets, as accepted by
+// AssertChain, that are found and missing in the chain of err, followed by
+// the tree of errors err wraps. It returns the empty string if every
+// target is found.
+func ChainDiff(err error, targets ...any) string {
+	var b strings.Builder
+	missing := false
+	b.WriteString("errors wanted in the chain:\n")
+	for _, target := range targets {
+		found, desc := match(err, target)
+		status := "found  "
+		if !found {
+			status = "missing"
+			missing = true
+		}
+		fmt.Fprintf(&b, "\t%s  %s\n", status, desc)
+	}
+	if !missing {
+		return ""
+	}
+	b.WriteString("chain of the error:\n")
+	b.WriteString(indent(errtree.Chain(err).String()))
+	return b.String()
+}
+
+// match reports whether target is in the chain of err, and describes it.
+func match(err error, target any) (bool, string) {
+	v := reflect.ValueOf(target)
+	if e, ok := target.(error); ok {
+		if v.Kind() == reflect.Pointer && !v.IsNil() && v.Elem().IsZero() {
+			// errors.As sets a new variable of the type of target, which
+			// is left as it is.
+			as := reflect.New(v.Type())
+			return errors.Is(err, e) || errors.As(err, as.Interface()), fmt.Sprintf("%T", target)
+		}
+		return errors.Is(err, e), fmt.Sprintf("%T %q", target, e.Error())
+	}
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return false, fmt.Sprintf("%T, which is neither an error nor a pointer to an error type", target)
+	}
+	elem := v.Type().Elem()
+	if elem.Kind() != reflect.Interface && !elem.Implements(reflect.TypeFor[error]()) {
+		return false, fmt.Sprintf("%T, which is neither an error nor a pointer to an error type", target)
+	}
+	return errors.As(err, reflect.New(elem).Interface()), elem.String()
+}
+
+// indent indents each line of s with a tab.
+func indent(s string) string {
+	return "\t" + strings.ReplaceAll(s, "\n", "\n\t") + "\n"
+}
@@ -0,0 +1,90 @@
+package errtest_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errtest"
+)
+
+var errInvalid = errors.New("invalid input")
+
+// notFoundError is an error type of a test store.
+type notFoundError struct{ id string }
+
+func (e *notFoundError) Error() string { return "item " + e.id + " not found" }
+
+// recorder is a testing.TB that records the failures it is told about.
+type recorder struct {
+	testing.TB
+	msgs []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.msgs = append(r.msgs, fmt.Sprintf(format, args...))
+}
+
+// TestChainDiff checks which targets ChainDiff finds in the chain of an
+// error.
+func TestChainDiff(t *testing.T) {
+	err := fmt.Errorf("getting item 42: %w", errors.Join(&notFoundError{"42"}, errcode.WithCode(errInvalid, errcode.NotFound)))
+	tests := []struct {
+		name    string
+		targets []any
+		missing []string
+	}{
+		{"sentinel", []any{errInvalid}, nil},
+		{"zero pointer", []any{&notFoundError{}}, nil},
+		{"pointer to type", []any{new(*notFoundError)}, nil},
+		{"pointer to interface", []any{new(errcode.Coder)}, nil},
+		{"several", []any{errInvalid, &notFoundError{}, new(errcode.Coder)}, nil},
+		{"missing sentinel", []any{errInvalid, fs.ErrNotExist}, []string{`*errors.errorString "file does not exist"`}},
+		{"missing type", []any{new(*fs.PathError)}, []string{"*fs.PathError"}},
+		{"other pointer", []any{&notFoundError{"7"}}, []string{`*errtest_test.notFoundError "item 7 not found"`}},
+		{"not an error", []any{new(int)}, []string{"*int, which is neither an error nor a pointer to an error type"}},
+		{"nil", []any{nil}, []string{"<nil>, which is neither an error nor a pointer to an error type"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := errtest.ChainDiff(err, tt.targets...)
+			if len(tt.missing) == 0 {
+				if diff != "" {
+					t.Errorf("ChainDiff =\n%s\nwant no difference", diff)
+				}
+				return
+			}
+			for _, m := range tt.missing {
+				if !strings.Contains(diff, "missing  "+m+"\n") {
+					t.Errorf("ChainDiff =\n%s\nwant %s missing", diff, m)
+				}
+			}
+			if !strings.Contains(diff, "chain of the error:\n\t*fmt.wrapError") {
+				t.Errorf("ChainDiff =\n%s\nwant the chain of the error", diff)
+			}
+		})
+	}
+}
+
+// TestAssert checks that AssertChain and AssertCode report failures
+// through t only when the chain does not match.
+func TestAssert(t *testing.T) {
+	err := fmt.Errorf("loading: %w", errcode.WithCode(errInvalid, errcode.InvalidInput))
+
+	var r recorder
+	if !errtest.AssertChain(&r, err, errInvalid) || !errtest.AssertCode(&r, err, errcode.InvalidInput) || len(r.msgs) > 0 {
+		t.Errorf("assertions on a matching chain failed: %q", r.msgs)
+	}
+	if errtest.AssertChain(&r, err, fs.ErrNotExist) || len(r.msgs) != 1 || !strings.Contains(r.msgs[0], "missing") {
+		t.Errorf("AssertChain of a missing target reported %q", r.msgs)
+	}
+	r.msgs = nil
+	if errtest.AssertCode(&r, err, errcode.NotFound) || len(r.msgs) != 1 || !strings.Contains(r.msgs[0], "= InvalidInput, want NotFound") {
+		t.Errorf("AssertCode of another code reported %q", r.msgs)
+	}
+}
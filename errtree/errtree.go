@@ -0,0 +1,77 @@
+// Package errtree renders the tree of errors an error wraps.
+//
+// Errors wrapped with %w form a chain, and errors.Join or several %w verbs
+// in one fmt.Errorf call branch it into a tree. Chain returns that tree,
+// and its String method draws it with the type, message and code of each
+// error:
+//
+//	fmt.Println(errtree.Chain(err))
+//
+//	*fmt.wrapErrors "restoring backup: disk full and replica down"
+//	├── *errors.errorString "disk full"
+//	└── *errcode.codeError "replica down" [Unavailable]
+//	    └── *errors.errorString "replica down"
+package errtree
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+)
+
+// Node is an error in a tree of wrapped errors.
+type Node struct {
+	// Err is the error itself.
+	Err error
+	// Code is the code Err itself carries through an errcode.Coder
+	// Code method, or errcode.Unknown.
+	Code errcode.Code
+	// Children are the errors Err wraps, in the order Unwrap returns them.
+	Children []*Node
+}
+
+// Chain returns the tree of errors wrapped by err, or nil if err is nil.
+func Chain(err error) *Node {
+	if err == nil {
+		return nil
+	}
+	n := &Node{Err: err, Code: errcode.Unknown}
+	// Only the code of err itself is recorded here. Comparing the Coder
+	// errors.As finds with err would panic for errors of types that are
+	// not comparable.
+	//errlint:ignore ERRLINT002 only err itself is asked about, not its chain
+	if coder, ok := err.(errcode.Coder); ok {
+		n.Code = coder.Code()
+	}
+	for _, w := range errkit.Wrapped(err) {
+		n.Children = append(n.Children, Chain(w))
+	}
+	return n
+}
+
+// String draws the tree with one error per line.
+func (n *Node) String() string {
+	if n == nil {
+		return "<nil>"
+	}
+	var b strings.Builder
+	n.write(&b, "", "")
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (n *Node) write(b *strings.Builder, first, rest string) {
+	fmt.Fprintf(b, "%s%T %q", first, n.Err, n.Err.Error())
+	if n.Code != errcode.Unknown {
+		fmt.Fprintf(b, " [%s]", n.Code)
+	}
+	b.WriteString("\n")
+	for i, c := range n.Children {
+		if i == len(n.Children)-1 {
+			c.write(b, rest+"└── ", rest+"    ")
+		} else {
+			c.write(b, rest+"├── ", rest+"│   ")
+		}
+	}
+}
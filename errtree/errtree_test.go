@@ -0,0 +1,65 @@
+package errtree_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errtree"
+)
+
+// listError is a coded error of a type that is not comparable.
+type listError []string
+
+func (e listError) Error() string      { return fmt.Sprint([]string(e)) }
+func (e listError) Code() errcode.Code { return errcode.Internal }
+
+// TestChain checks the tree Chain returns and how String draws it.
+func TestChain(t *testing.T) {
+	diskFull := errors.New("disk full")
+	replica := errcode.WithCode(errors.New("replica down"), errcode.Unavailable)
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, "<nil>"},
+		{"leaf", diskFull, `*errors.errorString "disk full"`},
+		{
+			"tree",
+			fmt.Errorf("restoring backup: %w and %w", diskFull, replica),
+			`*fmt.wrapErrors "restoring backup: disk full and replica down"
+├── *errors.errorString "disk full"
+└── *errcode.codeError "replica down" [Unavailable]
+    └── *errors.errorString "replica down"`,
+		},
+		{
+			"nested",
+			fmt.Errorf("a: %w", errors.Join(fmt.Errorf("b: %w", diskFull), diskFull)),
+			`*fmt.wrapError "a: b: disk full\ndisk full"
+└── *errors.joinError "b: disk full\ndisk full"
+    ├── *fmt.wrapError "b: disk full"
+    │   └── *errors.errorString "disk full"
+    └── *errors.errorString "disk full"`,
+		},
+		{"incomparable coder", fmt.Errorf("x: %w", listError{"a"}), `*fmt.wrapError "x: [a]"
+└── errtree_test.listError "[a]" [Internal]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errtree.Chain(tt.err).String(); got != tt.want {
+				t.Errorf("Chain(%v) =\n%s\nwant\n%s", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestChainCodes checks that each node has the code of its own error, not
+// of the errors below it.
+func TestChainCodes(t *testing.T) {
+	n := errtree.Chain(fmt.Errorf("x: %w", errcode.WithCode(errors.New("y"), errcode.NotFound)))
+	if n.Code != errcode.Unknown || len(n.Children) != 1 || n.Children[0].Code != errcode.NotFound || n.Children[0].Children[0].Code != errcode.Unknown {
+		t.Errorf("codes of the chain are wrong:\n%s", n)
+	}
+}
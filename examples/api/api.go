@@ -0,0 +1,129 @@
+// Package api is the API layer of the shop example. Service serves the
+// storage layer over HTTP as problem+json and over gRPC as statuses,
+// records each failed call on its span, in its metrics and in its log, and
+// Client turns the HTTP responses back into errors.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+
+	"example.com/shop/storage"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errgrpc"
+	"github.com/kakkoyun/demo-error-lint/errhttp"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+	"github.com/kakkoyun/demo-error-lint/errmetrics"
+	"github.com/kakkoyun/demo-error-lint/errotel"
+	"github.com/kakkoyun/demo-error-lint/errslog"
+)
+
+// ErrInvalidQuantity is returned for reservations of less than one item.
+var ErrInvalidQuantity = errcode.WithCode(errors.New("invalid quantity"), errcode.InvalidInput) // want ErrInvalidQuantity:"^sentinel wrapped by api.Service.Reserve$"
+
+// Service serves the products of a store.
+type Service struct {
+	store  *storage.Store
+	tracer trace.Tracer
+	logger *slog.Logger
+	// Metrics counts the errors of the service by operation, code and
+	// sentinel of the storage layer.
+	Metrics *errmetrics.Metrics
+}
+
+// NewService returns a service for store that traces with tracer and logs
+// to logger.
+func NewService(store *storage.Store, tracer trace.Tracer, logger *slog.Logger) *Service {
+	return &Service{
+		store:   store,
+		tracer:  tracer,
+		logger:  logger,
+		Metrics: errmetrics.New(storage.Sentinels),
+	}
+}
+
+// Get returns the product with the given SKU.
+func (s *Service) Get(ctx context.Context, sku string) (storage.Product, error) { // want Get:"^wraps example.com/shop/storage.ErrNotFound \\(via storage.Store.Get\\); returns other errors$"
+	ctx, span := s.tracer.Start(ctx, "Get")
+	defer span.End()
+	p, err := s.store.Get(ctx, sku)
+	if err != nil {
+		s.record(ctx, span, "get", err)
+		return p, err
+	}
+	return p, nil
+}
+
+// Reserve takes n items of the product with the given SKU from its stock.
+func (s *Service) Reserve(ctx context.Context, sku string, n int) error { // want Reserve:"^wraps example.com/shop/api.ErrInvalidQuantity, example.com/shop/storage.ErrNotFound \\(via storage.Store.Reserve\\), example.com/shop/storage.ErrOutOfStock \\(via storage.Store.Reserve\\); returns other errors$"
+	ctx, span := s.tracer.Start(ctx, "Reserve")
+	defer span.End()
+	var err error
+	if n < 1 {
+		err = fmt.Errorf("reserving %d of %s: %w", n, sku, ErrInvalidQuantity)
+	} else {
+		err = s.store.Reserve(ctx, sku, n)
+	}
+	if err != nil {
+		s.record(ctx, span, "reserve", err)
+		return err
+	}
+	return nil
+}
+
+// record records err, the error of operation op, on span, in the metrics
+// and in the log.
+func (s *Service) record(ctx context.Context, span trace.Span, op string, err error) {
+	errotel.RecordError(span, err)
+	s.Metrics.Observe(op, err)
+	s.logger.LogAttrs(ctx, slog.LevelWarn, op+" failed", slog.String("op", op), errslog.Attr(err))
+}
+
+// Handler returns the HTTP handler of the service:
+//
+//	GET  /products/{sku}
+//	POST /products/{sku}/reserve?n=1
+func (s *Service) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /products/{sku}", errhttp.Handler(s.getProduct))
+	mux.Handle("POST /products/{sku}/reserve", errhttp.Handler(s.reserve))
+	return mux
+}
+
+func (s *Service) getProduct(w http.ResponseWriter, r *http.Request) error { // want getProduct:"^wraps example.com/shop/storage.ErrNotFound \\(via api.Service.Get, storage.Store.Get\\); returns other errors$"
+	p, err := s.Get(r.Context(), r.PathValue("sku"))
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(p)
+}
+
+func (s *Service) reserve(w http.ResponseWriter, r *http.Request) error {
+	n, err := strconv.Atoi(r.FormValue("n"))
+	if err != nil {
+		return errcode.WithCode(fmt.Errorf("parsing quantity: %w", err), errcode.InvalidInput)
+	}
+	if err := s.Reserve(r.Context(), r.PathValue("sku"), n); err != nil {
+		return errkit.Wrap(err, "handling reservation")
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// GRPCServerOptions returns the options that make a gRPC server send the
+// errors of its handlers as statuses with their code and the name of the
+// storage sentinel they match, which errgrpc.UnaryClientInterceptor with
+// storage.Sentinels turns back into the sentinel on the client.
+func GRPCServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{grpc.UnaryInterceptor(errgrpc.UnaryServerInterceptor(storage.Sentinels))}
+}
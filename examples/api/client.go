@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"example.com/shop/storage"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errhttp"
+)
+
+// Client calls the HTTP API of a Service.
+type Client struct {
+	// URL is the base URL of the service.
+	URL string
+	// HTTP is the client that sends the requests.
+	HTTP *http.Client
+}
+
+// Error is an error response of the service.
+type Error struct {
+	Problem errhttp.Problem
+}
+
+func (e *Error) Error() string {
+	if e.Problem.Detail != "" {
+		return e.Problem.Detail
+	}
+	return e.Problem.Title
+}
+
+// Code returns the code the service sent with the problem.
+func (e *Error) Code() errcode.Code {
+	for _, c := range errcode.Codes() {
+		if c.String() == e.Problem.Code {
+			return c
+		}
+	}
+	return errcode.Unknown
+}
+
+// Get returns the product with the given SKU.
+func (c *Client) Get(ctx context.Context, sku string) (storage.Product, error) { // want Get:"^wraps io.ErrUnexpectedEOF; returns other errors$"
+	var p storage.Product
+	resp, err := c.do(ctx, http.MethodGet, "/products/"+url.PathEscape(sku))
+	if err != nil {
+		return p, fmt.Errorf("getting product %s: %w", sku, err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+		return p, fmt.Errorf("decoding product %s: %w", sku, err)
+	}
+	return p, nil
+}
+
+// Reserve takes n items of the product with the given SKU from its stock.
+func (c *Client) Reserve(ctx context.Context, sku string, n int) error { // want Reserve:"^wraps io.ErrUnexpectedEOF; returns other errors$"
+	resp, err := c.do(ctx, http.MethodPost, "/products/"+url.PathEscape(sku)+"/reserve?n="+strconv.Itoa(n))
+	if err != nil {
+		return fmt.Errorf("reserving %d of %s: %w", n, sku, err)
+	}
+	return resp.Body.Close()
+}
+
+// do sends a request and returns an *Error for error responses.
+func (c *Client) do(ctx context.Context, method, path string) (*http.Response, error) { // want do:"^wraps io.ErrUnexpectedEOF; returns other errors$"
+	req, err := http.NewRequestWithContext(ctx, method, c.URL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 400 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	apiErr := &Error{Problem: errhttp.Problem{Status: resp.StatusCode, Title: http.StatusText(resp.StatusCode)}}
+	if err := json.NewDecoder(resp.Body).Decode(&apiErr.Problem); err != nil {
+		return nil, fmt.Errorf("decoding %s response: %w", resp.Status, err)
+	}
+	return nil, apiErr
+}
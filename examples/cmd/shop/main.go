@@ -0,0 +1,101 @@
+// Command shop runs the shop example: it starts the service on an
+// in-process HTTP server with a small catalog and calls it with the client.
+//
+//	shop [-v] [-json] get SKU
+//	shop [-v] [-json] reserve SKU N
+//
+// Errors exit with the code errexit derives from them, so invalid
+// arguments exit with 2. The -v flag prints the tree of errors a failure
+// wraps and -json prints it as errjson sends it.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"strconv"
+
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"example.com/shop/api"
+	"example.com/shop/storage"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errexit"
+	"github.com/kakkoyun/demo-error-lint/errjson"
+	"github.com/kakkoyun/demo-error-lint/errtree"
+)
+
+var errUsage = errcode.WithCode(errors.New("usage: shop [-v] [-json] get SKU | reserve SKU N"), errcode.InvalidInput) // want errUsage:"^sentinel wrapped by main.run$"
+
+var (
+	verbose = flag.Bool("v", false, "print the tree of errors a failure wraps")
+	asJSON  = flag.Bool("json", false, "print failures as JSON")
+)
+
+func main() {
+	flag.Parse()
+	errexit.Run(func() error {
+		err := run(context.Background(), flag.Args())
+		if err != nil {
+			report(err)
+		}
+		return err
+	})
+}
+
+// report prints the details of err the flags ask for.
+func report(err error) {
+	if *verbose {
+		fmt.Fprintln(os.Stderr, errtree.Chain(err))
+	}
+	if *asJSON {
+		data, jsonErr := errjson.Marshal(err, storage.Sentinels)
+		if jsonErr != nil {
+			fmt.Fprintln(os.Stderr, "marshaling error:", jsonErr)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "%s\n", data)
+	}
+}
+
+func run(ctx context.Context, args []string) error { // want run:"^returns example.com/shop/cmd/shop.errUsage; wraps example.com/shop/cmd/shop.errUsage, io.ErrUnexpectedEOF \\(via api.Client.Get\\); returns other errors$"
+	store := storage.NewStore()
+	if err := store.Import(
+		storage.Product{SKU: "TEA-001", Name: "green tea", Stock: 12},
+		storage.Product{SKU: "TEA-002", Name: "black tea", Stock: 0},
+		storage.Product{SKU: "MUG-010", Name: "mug", Stock: 3},
+	); err != nil {
+		return fmt.Errorf("seeding catalog: %w", err)
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	svc := api.NewService(store, noop.NewTracerProvider().Tracer("shop"), logger)
+	server := httptest.NewServer(svc.Handler())
+	defer server.Close()
+	client := &api.Client{URL: server.URL, HTTP: server.Client()}
+
+	switch {
+	case len(args) == 2 && args[0] == "get":
+		p, err := client.Get(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s %s: %d in stock\n", p.SKU, p.Name, p.Stock)
+		return nil
+	case len(args) == 3 && args[0] == "reserve":
+		n, err := strconv.Atoi(args[2])
+		if err != nil {
+			return fmt.Errorf("%w: %w", errUsage, err)
+		}
+		if err := client.Reserve(ctx, args[1], n); err != nil {
+			return err
+		}
+		fmt.Printf("reserved %d of %s\n", n, args[1])
+		return nil
+	}
+	return errUsage
+}
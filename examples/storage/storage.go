@@ -0,0 +1,105 @@
+// Package storage is the storage layer of the shop example: an in-memory
+// catalog of products and their stock.
+//
+// It returns its sentinels wrapped with what it was doing and registers
+// them by name in Sentinels, so the layers above can match them with
+// errors.Is, count them and send them across the wire. The analyzer
+// exports what each function returns as a fact, which the api module
+// imports to know that its own functions wrap these sentinels.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/kakkoyun/demo-error-lint/errcode"
+	"github.com/kakkoyun/demo-error-lint/errcollect"
+	"github.com/kakkoyun/demo-error-lint/errfields"
+	"github.com/kakkoyun/demo-error-lint/errkit"
+)
+
+// Sentinels names the sentinel errors of the package.
+var Sentinels = errkit.NewRegistry()
+
+// Sentinel errors
+var (
+	ErrNotFound   = Sentinels.Register("storage.not_found", errcode.WithCode(errors.New("product not found"), errcode.NotFound))  // want ErrNotFound:"^sentinel wrapped by storage.Store.Get, storage.Store.Reserve$"
+	ErrOutOfStock = Sentinels.Register("storage.out_of_stock", errcode.WithCode(errors.New("out of stock"), errcode.Unavailable)) // want ErrOutOfStock:"^sentinel wrapped by storage.Store.Reserve$"
+	ErrInvalidSKU = Sentinels.Register("storage.invalid_sku", errcode.WithCode(errors.New("invalid SKU"), errcode.InvalidInput))  // want ErrInvalidSKU:"^sentinel$"
+)
+
+// Product is a product of the catalog.
+type Product struct {
+	SKU   string `json:"sku"`
+	Name  string `json:"name"`
+	Stock int    `json:"stock"`
+}
+
+// Store is an in-memory catalog. It is safe for concurrent use.
+type Store struct {
+	mu       sync.Mutex
+	products map[string]Product
+}
+
+// NewStore returns an empty store.
+func NewStore() *Store {
+	return &Store{products: make(map[string]Product)}
+}
+
+// Import adds products to the store. It imports every valid product and
+// reports all the invalid ones at once.
+func (s *Store) Import(products ...Product) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var c errcollect.Collector
+	for _, p := range products {
+		if err := validSKU(p.SKU); err != nil {
+			c.Add(fmt.Errorf("importing %q: %w", p.Name, err))
+			continue
+		}
+		s.products[p.SKU] = p
+	}
+	return c.Err()
+}
+
+// Get returns the product with the given SKU.
+func (s *Store) Get(ctx context.Context, sku string) (Product, error) { // want Get:"^wraps example.com/shop/storage.ErrNotFound; returns other errors$"
+	if err := ctx.Err(); err != nil {
+		return Product{}, fmt.Errorf("getting product %s: %w", sku, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.products[sku]
+	if !ok {
+		return Product{}, fmt.Errorf("getting product %s: %w", sku, ErrNotFound)
+	}
+	return p, nil
+}
+
+// Reserve takes n items of the product with the given SKU from its stock.
+func (s *Store) Reserve(ctx context.Context, sku string, n int) error { // want Reserve:"^wraps example.com/shop/storage.ErrNotFound, example.com/shop/storage.ErrOutOfStock; returns other errors$"
+	p, err := s.Get(ctx, sku)
+	if err != nil {
+		return fmt.Errorf("reserving %d: %w", n, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p.Stock < n {
+		return fmt.Errorf("reserving %d of %s, %d available: %w", n, sku, p.Stock, ErrOutOfStock)
+	}
+	p.Stock -= n
+	s.products[sku] = p
+	return nil
+}
+
+// validSKU checks that sku has the form AAA-000.
+func validSKU(sku string) error {
+	prefix, digits, ok := strings.Cut(sku, "-")
+	if !ok || len(prefix) != 3 || len(digits) != 3 || strings.Trim(digits, "0123456789") != "" {
+		return errfields.With(ErrInvalidSKU, "sku", sku)
+	}
+	return nil
+}
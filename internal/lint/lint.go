@@ -0,0 +1,140 @@
+// Package lint loads packages, runs the errlint analyzer on them and
+// filters its diagnostics by configuration, the steps the errlint command
+// and errlintlib share, so that both report the same findings.
+package lint
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/kakkoyun/demo-error-lint/config"
+)
+
+// Diagnostic is a diagnostic of the analyzer, with where it was reported.
+type Diagnostic struct {
+	analysis.Diagnostic
+
+	// Fset is the file set the positions of the diagnostic refer to.
+	Fset *token.FileSet
+	// Generated is set if the diagnostic is in a generated file.
+	Generated bool
+	// Package is the path of the package the diagnostic was reported in,
+	// or of the package under test for test packages.
+	Package string
+}
+
+// Load loads the packages matching patterns with cfg, in the mode the
+// analyzer needs, and returns an error if any of them or their
+// dependencies failed to load or type-check.
+func Load(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+	c := *cfg
+	c.Mode = packages.LoadAllSyntax
+	pkgs, err := packages.Load(&c, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if err := PackageErrors(pkgs); err != nil {
+		return nil, err
+	}
+	return pkgs, nil
+}
+
+// PackageErrors returns the load and type errors of pkgs and their
+// dependencies, if any.
+func PackageErrors(pkgs []*packages.Package) error {
+	var errs []error
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, err := range pkg.Errors {
+			errs = append(errs, err)
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// Analyze runs a on the loaded packages pkgs and returns its diagnostics,
+// sorted by position, then message. Diagnostics reported for several
+// variants of a package, such as the package and its test variant, appear
+// once.
+func Analyze(a *analysis.Analyzer, pkgs []*packages.Package) ([]Diagnostic, error) {
+	graph, err := checker.Analyze([]*analysis.Analyzer{a}, pkgs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	generated := make(map[string]bool)
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, file := range pkg.Syntax {
+			if ast.IsGenerated(file) {
+				generated[pkg.Fset.File(file.FileStart).Name()] = true
+			}
+		}
+	})
+
+	// The checker analyzes the packages in parallel and returns them in
+	// no particular order. Merge them in the order of their IDs, which puts
+	// a package before its test variant, so that the same variant's copy of
+	// a diagnostic is kept on every run.
+	roots := slices.SortedFunc(slices.Values(graph.Roots), func(a, b *checker.Action) int {
+		return cmp.Compare(a.Package.ID, b.Package.ID)
+	})
+	seen := make(map[string]bool)
+	var (
+		diags []Diagnostic
+		errs  []error
+	)
+	for _, act := range roots {
+		if act.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", act.Package.PkgPath, act.Err))
+			continue
+		}
+		fset := act.Package.Fset
+		for _, d := range act.Diagnostics {
+			pos := fset.Position(d.Pos)
+			key := pos.String() + ": " + d.Message
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			diags = append(diags, Diagnostic{
+				Diagnostic: d,
+				Fset:       fset,
+				Generated:  generated[pos.Filename],
+				Package:    cmp.Or(act.Package.ForTest, act.Package.PkgPath),
+			})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	slices.SortFunc(diags, func(a, b Diagnostic) int {
+		pa, pb := a.Fset.Position(a.Pos), b.Fset.Position(b.Pos)
+		return cmp.Or(
+			cmp.Compare(pa.Filename, pb.Filename),
+			cmp.Compare(pa.Line, pb.Line),
+			cmp.Compare(pa.Column, pb.Column),
+			cmp.Compare(a.Message, b.Message),
+		)
+	})
+	return diags, nil
+}
+
+// Severity returns the severity cfg gives the findings of check in the
+// file filename, and false if cfg drops them: if the file is generated and
+// cfg does not lint generated files, if cfg excludes the file, or if the
+// check is off.
+func Severity(cfg *config.Config, check, filename string, generated bool) (string, bool) {
+	if generated && !cfg.Generated || cfg.Excluded(filename) {
+		return "", false
+	}
+	severity := cfg.SeverityOf(check)
+	return severity, severity != "off"
+}
@@ -0,0 +1,102 @@
+// Package plugin registers errlint as a golangci-lint module plugin.
+//
+// Build a custom golangci-lint binary that includes it with a
+// .custom-gcl.yml such as
+//
+//	version: v2.5.0
+//	plugins:
+//	  - module: github.com/kakkoyun/demo-error-lint
+//	    import: github.com/kakkoyun/demo-error-lint/plugin
+//	    version: latest
+//
+// and enable it in .golangci.yml:
+//
+//	linters:
+//	  enable:
+//	    - errlint
+//	  settings:
+//	    custom:
+//	      errlint:
+//	        type: module
+//	        settings:
+//	          checks: [comparison, errorf]
+//	          enable: [dynamic]
+//	          allow: [example.com/store.ErrMiss]
+//	          passthrough: [example.com/store]
+//	          loggers: [go.uber.org/zap.Logger.Error]
+//	          wrap-prefix: "^[a-z]+ing\\b"
+//	          custom:
+//	            - name: legacywrap
+//	              call: example.com/app/errs.LegacyWrap
+//	              message: "{{.Expr}} is deprecated; wrap with fmt.Errorf and %w"
+package plugin
+
+import (
+	"github.com/golangci/plugin-module-register/register"
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/kakkoyun/demo-error-lint/analyzer"
+)
+
+func init() {
+	register.Plugin("errlint", New)
+}
+
+// Settings are the plugin settings read from .golangci.yml.
+type Settings struct {
+	// Checks lists the checks to run. If empty, all checks that are not
+	// opt-in run.
+	Checks []string `json:"checks"`
+	// Enable lists checks to run in addition to Checks, such as the opt-in
+	// ones.
+	Enable []string `json:"enable"`
+	// Allow lists additional sentinel errors, as "pkg/path.Name", that may
+	// be compared with ==.
+	Allow []string `json:"allow"`
+	// Passthrough lists additional packages, as "pkg/path", and functions,
+	// as "pkg/path.Name", whose errors may be returned unwrapped.
+	Passthrough []string `json:"passthrough"`
+	// Loggers lists additional packages, as "pkg/path", and functions, as
+	// "pkg/path.Name", whose calls log an error.
+	Loggers []string `json:"loggers"`
+	// WrapPrefix is a regular expression the context of wrap messages must
+	// match.
+	WrapPrefix string `json:"wrap-prefix"`
+	// Custom lists checks defined by a call or comparison pattern and a
+	// message template.
+	Custom []analyzer.CustomCheck `json:"custom"`
+}
+
+// New returns the errlint plugin configured by the raw settings golangci-lint
+// passes in.
+func New(settings any) (register.LinterPlugin, error) {
+	s, err := register.DecodeSettings[Settings](settings)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin{settings: s}, nil
+}
+
+type plugin struct {
+	settings Settings
+}
+
+func (p *plugin) BuildAnalyzers() ([]*analysis.Analyzer, error) {
+	a, err := analyzer.New(analyzer.Config{
+		Checks:      p.settings.Checks,
+		Enable:      p.settings.Enable,
+		Allow:       p.settings.Allow,
+		Passthrough: p.settings.Passthrough,
+		Loggers:     p.settings.Loggers,
+		WrapPrefix:  p.settings.WrapPrefix,
+		Custom:      p.settings.Custom,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []*analysis.Analyzer{a}, nil
+}
+
+func (p *plugin) GetLoadMode() string {
+	return register.LoadModeTypesInfo
+}
@@ -0,0 +1,121 @@
+// Package result is an experimental Result[T] type, which carries either a
+// value or the error that prevented it, with combinators that chain steps
+// without an if err != nil check after each.
+//
+// The carried error is an ordinary error: Map and AndThen pass it along
+// unchanged, and Wrap wraps it with %w, so errors.Is and errors.As find the
+// sentinels and types of whichever step failed. Get turns the result back
+// into a (value, error) pair at the boundary of code that does not use the
+// package:
+//
+//	port, err := result.AndThen(
+//		result.Of(os.ReadFile(name)),
+//		func(data []byte) result.Result[int] {
+//			return result.Of(strconv.Atoi(strings.TrimSpace(string(data))))
+//		},
+//	).Get()
+//
+// The package is experimental, and its API may change. Idiomatic Go returns
+// (value, error) pairs and wraps each error with what the function was
+// doing; a chain of combinators makes that context easy to leave out, which
+// demos/result shows side by side.
+package result
+
+import "fmt"
+
+// Result holds either a value of type T or the error that prevented it. The
+// zero value holds the zero value of T and no error.
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Ok returns a result holding v.
+func Ok[T any](v T) Result[T] {
+	return Result[T]{value: v}
+}
+
+// Err returns a result holding err. It panics if err is nil, since a
+// failed result needs an error to report.
+func Err[T any](err error) Result[T] {
+	if err == nil {
+		panic("result: Err called with a nil error")
+	}
+	return Result[T]{err: err}
+}
+
+// Of returns a result holding err if it is not nil, and v otherwise. It
+// takes the results of a function returning (T, error) directly, as in
+// result.Of(strconv.Atoi(s)).
+func Of[T any](v T, err error) Result[T] {
+	if err != nil {
+		return Result[T]{err: err}
+	}
+	return Result[T]{value: v}
+}
+
+// Get returns the value and the error of r, as a function returning (T,
+// error) would: the zero value of T if r holds an error.
+func (r Result[T]) Get() (T, error) {
+	if r.err != nil {
+		var zero T
+		return zero, r.err
+	}
+	return r.value, nil
+}
+
+// Err returns the error of r, or nil if it holds a value.
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// IsOk reports whether r holds a value.
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+// Unwrap returns the value of r. It panics if r holds an error, with an
+// error that wraps it, so a recover further up can still match it with
+// errors.Is. Use Unwrap only where an error is a bug, such as in tests.
+func (r Result[T]) Unwrap() T {
+	if r.err != nil {
+		panic(fmt.Errorf("result: Unwrap called on a failed result: %w", r.err))
+	}
+	return r.value
+}
+
+// Or returns the value of r, or def if r holds an error.
+func (r Result[T]) Or(def T) T {
+	if r.err != nil {
+		return def
+	}
+	return r.value
+}
+
+// Map returns the result of f applied to the value of r, or the error of r
+// unchanged, without calling f, if it holds one.
+func Map[T, U any](r Result[T], f func(T) U) Result[U] {
+	if r.err != nil {
+		return Result[U]{err: r.err}
+	}
+	return Result[U]{value: f(r.value)}
+}
+
+// AndThen returns the result of f, which may fail itself, called with the
+// value of r, or the error of r unchanged, without calling f, if it holds
+// one.
+func AndThen[T, U any](r Result[T], f func(T) Result[U]) Result[U] {
+	if r.err != nil {
+		return Result[U]{err: r.err}
+	}
+	return f(r.value)
+}
+
+// Wrap returns r with its error, if it holds one, wrapped as
+// fmt.Errorf("msg: %w", err), to say what the failed step was doing.
+func (r Result[T]) Wrap(msg string) Result[T] {
+	if r.err != nil {
+		r.err = fmt.Errorf("%s: %w", msg, r.err)
+	}
+	return r
+}
@@ -0,0 +1,104 @@
+package result_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/kakkoyun/demo-error-lint/result"
+)
+
+var errParse = errors.New("parse error")
+
+// parse returns the result of strconv.Atoi.
+func parse(s string) result.Result[int] {
+	return result.Of(strconv.Atoi(s))
+}
+
+// TestResult checks the value and error of results, and the combinators
+// that pass an error along unchanged.
+func TestResult(t *testing.T) {
+	double := func(n int) int { return 2 * n }
+	tests := []struct {
+		name string
+		r    result.Result[int]
+		want int
+		err  string
+	}{
+		{"ok", result.Ok(1), 1, ""},
+		{"zero", result.Result[int]{}, 0, ""},
+		{"err", result.Err[int](errParse), 0, "parse error"},
+		{"of", parse("42"), 42, ""},
+		{"of error", parse("x"), 0, `strconv.Atoi: parsing "x": invalid syntax`},
+		{"map", result.Map(parse("21"), double), 42, ""},
+		{"map error", result.Map(result.Err[int](errParse), double), 0, "parse error"},
+		{"and then", result.AndThen(result.Ok("7"), parse), 7, ""},
+		{"and then error", result.AndThen(result.Ok("x"), parse).Wrap("reading port"), 0, `reading port: strconv.Atoi: parsing "x": invalid syntax`},
+		{"wrap ok", result.Ok(3).Wrap("reading port"), 3, ""},
+		{"wrap", result.Err[int](errParse).Wrap("reading port"), 0, "reading port: parse error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := tt.r.Get()
+			if v != tt.want {
+				t.Errorf("Get value = %d, want %d", v, tt.want)
+			}
+			if got := errString(err); got != tt.err || errString(tt.r.Err()) != tt.err {
+				t.Errorf("Get error = %q, want %q", got, tt.err)
+			}
+			if tt.r.IsOk() != (tt.err == "") {
+				t.Errorf("IsOk = %t, want %t", tt.r.IsOk(), tt.err == "")
+			}
+			want := tt.want
+			if tt.err != "" {
+				want = -1
+			}
+			if got := tt.r.Or(-1); got != want {
+				t.Errorf("Or(-1) = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+// errString returns the message of err, or "" if it is nil.
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// TestErrorsIs checks that errors.Is finds the error of the step that
+// failed through Map, AndThen and Wrap.
+func TestErrorsIs(t *testing.T) {
+	r := result.Map(result.AndThen(result.Err[string](errParse), parse).Wrap("reading port"), strconv.Itoa)
+	if !errors.Is(r.Err(), errParse) {
+		t.Errorf("errors.Is(%v, errParse) = false, want true", r.Err())
+	}
+	var numErr *strconv.NumError
+	if !errors.As(parse("x").Wrap("reading port").Err(), &numErr) || numErr.Func != "Atoi" {
+		t.Errorf("errors.As did not find the *strconv.NumError")
+	}
+}
+
+// TestPanics checks that Err panics with a nil error, and that Unwrap of a
+// failed result panics with an error wrapping its error.
+func TestPanics(t *testing.T) {
+	if v := recovered(func() { result.Err[int](nil) }); v == nil {
+		t.Errorf("Err(nil) did not panic")
+	}
+	v := recovered(func() { result.Err[int](errParse).Unwrap() })
+	if err, ok := v.(error); !ok || !errors.Is(err, errParse) {
+		t.Errorf("Unwrap panicked with %v, want an error wrapping errParse", v)
+	}
+	if got := result.Ok(5).Unwrap(); got != 5 {
+		t.Errorf("Unwrap = %d, want 5", got)
+	}
+}
+
+// recovered returns the value f panics with, or nil.
+func recovered(f func()) (v any) {
+	defer func() { v = recover() }()
+	f()
+	return nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kakkoyun/demo-error-lint/demos"
+	"github.com/kakkoyun/demo-error-lint/demos/demo"
+)
+
+// distractors are the offsets in demos.All of the demos whose fixes are
+// offered as wrong answers, far enough apart to be about other patterns.
+var distractors = []int{7, 13}
+
+// choice is a fix offered for the buggy code of a demo, taken from the
+// demo it belongs to.
+type choice struct {
+	from demo.Demo
+}
+
+// choices returns the fixes offered for d, and the index of the right one,
+// which varies between demos.
+func choices(d demo.Demo) ([]choice, int) {
+	i := slices.IndexFunc(demos.All, func(u demo.Demo) bool { return u.Name == d.Name })
+	var opts []choice
+	for _, k := range distractors {
+		u := demos.All[(i+k)%len(demos.All)]
+		if u.Name != d.Name && u.Correct != d.Correct {
+			opts = append(opts, choice{from: u})
+		}
+	}
+	right := i % (len(opts) + 1)
+	return slices.Insert(opts, right, choice{from: d}), right
+}
+
+// tutor steps through the demos, asking for the fix of each buggy snippet
+// on in.
+func tutor(selected []demo.Demo, in io.Reader, w io.Writer) {
+	answers := bufio.NewScanner(in)
+	firstTry := 0
+	for n, d := range selected {
+		if n > 0 {
+			fmt.Fprint(w, "\nPress Enter for the next demo, or q to quit. ")
+			if !answers.Scan() || strings.TrimSpace(answers.Text()) == "q" {
+				summary(w, firstTry, n)
+				return
+			}
+		}
+		fmt.Fprintf(w, "\n== %d/%d %s: %s\n", n+1, len(selected), d.Name, d.Title)
+		fmt.Fprintf(w, "\nBuggy code:\n%s", indent(d.Buggy))
+
+		opts, right := choices(d)
+		fmt.Fprintln(w, "\nWhich fix is right?")
+		for i, o := range opts {
+			fmt.Fprintf(w, "%2d) %s\n", i+1, strings.ReplaceAll(o.from.Correct, "\n", "\n    "))
+		}
+
+		tries := 0
+	ask:
+		for {
+			fmt.Fprintf(w, "Answer [1-%d, s to skip, q to quit]: ", len(opts))
+			if !answers.Scan() {
+				fmt.Fprintln(w)
+				summary(w, firstTry, n)
+				return
+			}
+			answer := strings.TrimSpace(answers.Text())
+			switch answer {
+			case "q":
+				summary(w, firstTry, n)
+				return
+			case "s":
+				fmt.Fprintf(w, "Skipped: the right fix is %d.\n", right+1)
+				break ask
+			}
+			i, err := strconv.Atoi(answer)
+			switch {
+			case err != nil || i < 1 || i > len(opts):
+				fmt.Fprintf(w, "Pick a number from 1 to %d.\n", len(opts))
+				continue
+			case i-1 != right:
+				tries++
+				fmt.Fprintf(w, "Not quite: that fixes %q. Try again.\n", opts[i-1].from.Title)
+				continue
+			}
+			if tries == 0 {
+				firstTry++
+			}
+			fmt.Fprintln(w, "Right.")
+			break ask
+		}
+
+		var out bytes.Buffer
+		d.Run(&out)
+		fmt.Fprintf(w, "\nCorrected code:\n%s", indent(d.Correct))
+		fmt.Fprintf(w, "\nWhy:\n%s", indent(d.Explain))
+		fmt.Fprintf(w, "\nOutput:\n%s", indent(out.String()))
+	}
+	summary(w, firstTry, len(selected))
+}
+
+// summary prints how many of the done demos were answered right on the
+// first try.
+func summary(w io.Writer, firstTry, done int) {
+	fmt.Fprintf(w, "\nRight on the first try: %d of %d.\n", firstTry, done)
+}